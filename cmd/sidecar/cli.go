@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marcus/sidecar/internal/adapter"
+	"github.com/marcus/sidecar/internal/app"
+)
+
+// subcommand is a headless command that prints data and exits without
+// starting the TUI.
+type subcommand struct {
+	summary string
+	run     func(args []string) error
+}
+
+// subcommands lists the headless subcommands, keyed by name.
+var subcommands = map[string]subcommand{
+	"sessions":  {summary: "list or show agent sessions", run: runSessions},
+	"worktrees": {summary: "list git worktrees with sidecar metadata", run: runWorktrees},
+	"usage":     {summary: "summarize token usage and estimated cost", run: runUsage},
+	"remote":    {summary: "call the remote control API of a running sidecar", run: runRemote},
+}
+
+// runSubcommand dispatches args to a headless subcommand. It returns false
+// when args[0] is not a known subcommand so the caller can start the TUI.
+func runSubcommand(args []string) (bool, int) {
+	if len(args) == 0 {
+		return false, 0
+	}
+	cmd, ok := subcommands[args[0]]
+	if !ok {
+		return false, 0
+	}
+	if err := cmd.run(args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "sidecar %s: %v\n", args[0], err)
+		return true, 1
+	}
+	return true, 0
+}
+
+// subcommandNames returns the subcommand names in sorted order.
+func subcommandNames() []string {
+	names := make([]string, 0, len(subcommands))
+	for name := range subcommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newSubcommandFlags creates a flag set for a subcommand with the shared
+// -project flag, defaulting to the global -project value.
+func newSubcommandFlags(name string) (*flag.FlagSet, *string) {
+	fs := flag.NewFlagSet("sidecar "+name, flag.ContinueOnError)
+	project := fs.String("project", *projectRoot, "project root directory")
+	return fs, project
+}
+
+// parseInterspersed parses flags that may appear before or after positional
+// arguments (e.g. "show <id> --format md") and returns the positionals.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// resolveProjectRoot converts dir to an absolute path and resolves linked
+// worktrees to their main worktree, matching the TUI's startup behavior.
+func resolveProjectRoot(dir string) (string, error) {
+	workDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("resolve project root: %w", err)
+	}
+	if mainPath := app.GetMainWorktreePath(workDir); mainPath != "" {
+		return mainPath, nil
+	}
+	return workDir, nil
+}
+
+// collectSessions gathers sessions from every detected adapter across all
+// worktrees related to projectRoot, newest first.
+func collectSessions(projectRoot string) ([]adapter.Session, map[string]adapter.Adapter, error) {
+	adapters, err := adapter.DetectAdapters(projectRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	paths := app.GetAllRelatedPaths(projectRoot)
+	if len(paths) == 0 {
+		paths = []string{projectRoot}
+	}
+
+	names := make(map[string]string, len(paths))
+	for _, path := range paths {
+		if path != projectRoot {
+			names[path] = app.WorktreeNameForPath(projectRoot, path)
+		}
+	}
+
+	var sessions []adapter.Session
+	seen := make(map[string]bool)
+	for id, a := range adapters {
+		for _, path := range paths {
+			list, err := a.Sessions(path)
+			if err != nil {
+				continue
+			}
+			for _, s := range list {
+				if s.AdapterID == "" {
+					s.AdapterID = id
+				}
+				if s.AdapterName == "" {
+					s.AdapterName = a.Name()
+				}
+				if path != projectRoot {
+					s.WorktreeName = names[path]
+					s.WorktreePath = path
+				}
+				key := s.AdapterID + "/" + s.ID
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				sessions = append(sessions, s)
+			}
+		}
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].UpdatedAt.After(sessions[j].UpdatedAt)
+	})
+	return sessions, adapters, nil
+}
+
+// parseSince parses a relative age such as "7d", "12h" or "2w", or an
+// absolute date in YYYY-MM-DD form, into the cutoff time relative to now.
+func parseSince(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return t, nil
+	}
+	if n := len(value); n > 1 {
+		unit := value[n-1]
+		if count, err := strconv.Atoi(value[:n-1]); err == nil && count >= 0 {
+			switch unit {
+			case 'd':
+				return now.AddDate(0, 0, -count), nil
+			case 'w':
+				return now.AddDate(0, 0, -7*count), nil
+			}
+		}
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since %q (use e.g. 7d, 12h, 2w or 2006-01-02)", value)
+	}
+	return now.Add(-d), nil
+}
+
+// writeJSON writes v as indented JSON followed by a newline.
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
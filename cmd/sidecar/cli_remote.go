@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/marcus/sidecar/internal/config"
+	"github.com/marcus/sidecar/internal/remote"
+)
+
+// runRemote implements `sidecar remote <method> [params]`: it calls a method
+// on a running sidecar and prints the result. events.subscribe prints one
+// event per line until sidecar exits.
+func runRemote(args []string) error {
+	fs, project := newSubcommandFlags("remote")
+	socket := fs.String("socket", "", "socket path (default: $SIDECAR_SOCKET or the sidecar running for -project)")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 || len(positional) > 2 {
+		return errors.New(`usage: sidecar remote <method> ['{"param": "value"}'] [--socket path]`)
+	}
+	method := positional[0]
+	var params json.RawMessage
+	if len(positional) == 2 {
+		params = json.RawMessage(positional[1])
+		if !json.Valid(params) {
+			return fmt.Errorf("params must be JSON: %s", positional[1])
+		}
+	}
+
+	path := *socket
+	if path == "" {
+		if path, err = findRemoteSocket(*project); err != nil {
+			return err
+		}
+	}
+	client, err := remote.Dial(path)
+	if err != nil {
+		return fmt.Errorf("connect to %s: %w", path, err)
+	}
+	defer func() { _ = client.Close() }()
+
+	var result json.RawMessage
+	if err := client.Call(method, params, &result); err != nil {
+		return err
+	}
+	if method != "events.subscribe" {
+		return writeJSON(os.Stdout, result)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	for {
+		e, err := client.NextEvent()
+		if err != nil {
+			return nil // sidecar exited
+		}
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+}
+
+// findRemoteSocket picks the socket to use: $SIDECAR_SOCKET, the configured
+// socket, the only running sidecar, or the running sidecar for project.
+func findRemoteSocket(project string) (string, error) {
+	if path := os.Getenv(remote.EnvSocket); path != "" {
+		return path, nil
+	}
+	if cfg, err := loadConfig(*configPath); err == nil && cfg.Remote.Socket != "" {
+		return cfg.Remote.Socket, nil
+	}
+
+	configDir := filepath.Dir(config.ConfigPath())
+	sockets := remote.FindSockets(configDir)
+	switch len(sockets) {
+	case 0:
+		return "", errors.New(`no running sidecar found; enable it with "remote": {"enabled": true} in config.json`)
+	case 1:
+		return sockets[0], nil
+	}
+
+	root, err := resolveProjectRoot(project)
+	if err != nil {
+		return "", err
+	}
+	for _, path := range sockets {
+		var info struct {
+			ProjectRoot string `json:"projectRoot"`
+		}
+		client, err := remote.Dial(path)
+		if err != nil {
+			continue
+		}
+		err = client.Call("info", nil, &info)
+		_ = client.Close()
+		if err == nil && info.ProjectRoot == root {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("%d sidecars are running but none for %s; pass --socket", len(sockets), root)
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/marcus/sidecar/internal/adapter"
+	"github.com/marcus/sidecar/internal/plugins/conversations"
+)
+
+// sessionJSON is the JSON shape for a session in `sidecar sessions list`.
+type sessionJSON struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	Adapter      string    `json:"adapter"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	DurationSecs int64     `json:"duration_secs"`
+	Active       bool      `json:"active"`
+	SubAgent     bool      `json:"sub_agent"`
+	Messages     int       `json:"messages"`
+	Tokens       int       `json:"tokens"`
+	EstCost      float64   `json:"est_cost"`
+	Unpriced     []string  `json:"unpriced_models,omitempty"`
+	Worktree     string    `json:"worktree,omitempty"`
+	WorktreePath string    `json:"worktree_path,omitempty"`
+	Path         string    `json:"path,omitempty"`
+}
+
+func toSessionJSON(s adapter.Session) sessionJSON {
+	return sessionJSON{
+		ID:           s.ID,
+		Name:         s.Name,
+		Adapter:      s.AdapterID,
+		CreatedAt:    s.CreatedAt,
+		UpdatedAt:    s.UpdatedAt,
+		DurationSecs: int64(s.Duration.Seconds()),
+		Active:       s.IsActive,
+		SubAgent:     s.IsSubAgent,
+		Messages:     s.MessageCount,
+		Tokens:       s.TotalTokens,
+		EstCost:      s.EstCost,
+		Unpriced:     s.UnpricedModels,
+		Worktree:     s.WorktreeName,
+		WorktreePath: s.WorktreePath,
+		Path:         s.Path,
+	}
+}
+
+// runSessions implements `sidecar sessions list|show`.
+func runSessions(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: sidecar sessions list|show <id> [flags]")
+	}
+	switch args[0] {
+	case "list", "ls":
+		return runSessionsList(args[1:])
+	case "show":
+		return runSessionsShow(args[1:])
+	default:
+		return fmt.Errorf("unknown action %q (want list or show)", args[0])
+	}
+}
+
+func runSessionsList(args []string) error {
+	fs, project := newSubcommandFlags("sessions list")
+	asJSON := fs.Bool("json", false, "output JSON")
+	adapterID := fs.String("adapter", "", "only include sessions from this adapter ID")
+	since := fs.String("since", "", "only include sessions updated since (e.g. 7d, 12h, 2006-01-02)")
+	limit := fs.Int("limit", 0, "maximum number of sessions (0 = all)")
+	subAgents := fs.Bool("subagents", false, "include sub-agent sessions")
+	if _, err := parseInterspersed(fs, args); err != nil {
+		return err
+	}
+
+	root, err := resolveProjectRoot(*project)
+	if err != nil {
+		return err
+	}
+	cutoff, err := parseSince(*since, time.Now())
+	if err != nil {
+		return err
+	}
+	sessions, _, err := collectSessions(root)
+	if err != nil {
+		return err
+	}
+
+	filtered := make([]adapter.Session, 0, len(sessions))
+	for _, s := range sessions {
+		if *adapterID != "" && s.AdapterID != *adapterID {
+			continue
+		}
+		if !cutoff.IsZero() && s.UpdatedAt.Before(cutoff) {
+			continue
+		}
+		if s.IsSubAgent && !*subAgents {
+			continue
+		}
+		filtered = append(filtered, s)
+		if *limit > 0 && len(filtered) >= *limit {
+			break
+		}
+	}
+
+	if *asJSON {
+		out := make([]sessionJSON, 0, len(filtered))
+		for _, s := range filtered {
+			out = append(out, toSessionJSON(s))
+		}
+		return writeJSON(os.Stdout, out)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tADAPTER\tUPDATED\tMSGS\tTOKENS\tCOST\tWORKTREE\tNAME")
+	for _, s := range filtered {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\t$%.2f\t%s\t%s\n",
+			s.ID, s.AdapterID, s.UpdatedAt.Format("2006-01-02 15:04"),
+			s.MessageCount, s.TotalTokens, s.EstCost, s.WorktreeName, truncateName(s.Name, 60))
+	}
+	return tw.Flush()
+}
+
+func runSessionsShow(args []string) error {
+	fs, project := newSubcommandFlags("sessions show")
+	formats := strings.Join(conversations.ExporterIDs(), ", ")
+	format := fs.String("format", "", "output format: "+formats+" (default markdown, or inferred from -o)")
+	output := fs.String("o", "", "write to file instead of stdout")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: sidecar sessions show <id> [--format %s] [-o file]", strings.Join(conversations.ExporterIDs(), "|"))
+	}
+
+	formatName := *format
+	if formatName == "" {
+		formatName = "markdown"
+		if ext := filepath.Ext(*output); ext != "" {
+			if _, ok := conversations.LookupExporter(ext); ok {
+				formatName = ext
+			}
+		}
+	}
+	exporter, ok := conversations.LookupExporter(formatName)
+	if !ok {
+		return fmt.Errorf("unknown format %q (want one of: %s)", formatName, formats)
+	}
+
+	root, err := resolveProjectRoot(*project)
+	if err != nil {
+		return err
+	}
+	sessions, adapters, err := collectSessions(root)
+	if err != nil {
+		return err
+	}
+	session, err := findSession(sessions, positional[0])
+	if err != nil {
+		return err
+	}
+	a, ok := adapters[session.AdapterID]
+	if !ok {
+		return fmt.Errorf("adapter %q not available", session.AdapterID)
+	}
+	messages, err := a.Messages(session.ID)
+	if err != nil {
+		return fmt.Errorf("load messages: %w", err)
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		out = f
+	}
+	return exporter.Export(out, session, messages)
+}
+
+// findSession resolves id to a single session by exact match or unique
+// prefix.
+func findSession(sessions []adapter.Session, id string) (*adapter.Session, error) {
+	var matches []*adapter.Session
+	for i := range sessions {
+		if sessions[i].ID == id {
+			return &sessions[i], nil
+		}
+		if strings.HasPrefix(sessions[i].ID, id) {
+			matches = append(matches, &sessions[i])
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("session %q not found", id)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("session prefix %q is ambiguous (%d matches)", id, len(matches))
+	}
+}
+
+// truncateName shortens name to at most n runes for table output.
+func truncateName(name string, n int) string {
+	name = strings.Join(strings.Fields(name), " ")
+	if runes := []rune(name); len(runes) > n {
+		return string(runes[:n-1]) + "…"
+	}
+	return name
+}
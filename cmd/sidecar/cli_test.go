@@ -0,0 +1,56 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marcus/sidecar/internal/adapter"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{"", time.Time{}, false},
+		{"7d", now.AddDate(0, 0, -7), false},
+		{"2w", now.AddDate(0, 0, -14), false},
+		{"12h", now.Add(-12 * time.Hour), false},
+		{"2026-03-01", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), false},
+		{"soon", time.Time{}, true},
+		{"-3d", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		got, err := parseSince(tt.in, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseSince(%q) err = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !got.Equal(tt.want) {
+			t.Errorf("parseSince(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFindSession(t *testing.T) {
+	sessions := []adapter.Session{
+		{ID: "abc123"},
+		{ID: "abd456"},
+		{ID: "xyz"},
+	}
+
+	if s, err := findSession(sessions, "xyz"); err != nil || s.ID != "xyz" {
+		t.Errorf("exact match: got %v, %v", s, err)
+	}
+	if s, err := findSession(sessions, "abc"); err != nil || s.ID != "abc123" {
+		t.Errorf("unique prefix: got %v, %v", s, err)
+	}
+	if _, err := findSession(sessions, "ab"); err == nil {
+		t.Error("expected ambiguous prefix error")
+	}
+	if _, err := findSession(sessions, "nope"); err == nil {
+		t.Error("expected not found error")
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// usageRow aggregates token usage for one adapter in `sidecar usage`.
+type usageRow struct {
+	Adapter      string  `json:"adapter"`
+	Sessions     int     `json:"sessions"`
+	Messages     int     `json:"messages"`
+	InputTokens  int     `json:"input_tokens,omitempty"`
+	OutputTokens int     `json:"output_tokens,omitempty"`
+	CacheRead    int     `json:"cache_read,omitempty"`
+	CacheWrite   int     `json:"cache_write,omitempty"`
+	TotalTokens  int     `json:"total_tokens"`
+	EstCost      float64 `json:"est_cost"`
+}
+
+// usageReport is the JSON shape of `sidecar usage --json`.
+type usageReport struct {
+	Since    time.Time  `json:"since,omitzero"`
+	Adapters []usageRow `json:"adapters"`
+	Total    usageRow   `json:"total"`
+}
+
+// runUsage implements `sidecar usage`.
+func runUsage(args []string) error {
+	fs, project := newSubcommandFlags("usage")
+	asJSON := fs.Bool("json", false, "output JSON")
+	since := fs.String("since", "", "only include sessions updated since (e.g. 7d, 12h, 2006-01-02)")
+	detailed := fs.Bool("detailed", false, "load per-session usage for input/output/cache breakdown (slower)")
+	if _, err := parseInterspersed(fs, args); err != nil {
+		return err
+	}
+
+	root, err := resolveProjectRoot(*project)
+	if err != nil {
+		return err
+	}
+	cutoff, err := parseSince(*since, time.Now())
+	if err != nil {
+		return err
+	}
+	sessions, adapters, err := collectSessions(root)
+	if err != nil {
+		return err
+	}
+
+	rows := make(map[string]*usageRow)
+	for _, s := range sessions {
+		if !cutoff.IsZero() && s.UpdatedAt.Before(cutoff) {
+			continue
+		}
+		row, ok := rows[s.AdapterID]
+		if !ok {
+			row = &usageRow{Adapter: s.AdapterID}
+			rows[s.AdapterID] = row
+		}
+		row.Sessions++
+		row.Messages += s.MessageCount
+		row.TotalTokens += s.TotalTokens
+		row.EstCost += s.EstCost
+
+		if !*detailed {
+			continue
+		}
+		a, ok := adapters[s.AdapterID]
+		if !ok {
+			continue
+		}
+		stats, err := a.Usage(s.ID)
+		if err != nil || stats == nil {
+			continue
+		}
+		row.InputTokens += stats.TotalInputTokens
+		row.OutputTokens += stats.TotalOutputTokens
+		row.CacheRead += stats.TotalCacheRead
+		row.CacheWrite += stats.TotalCacheWrite
+	}
+
+	report := usageReport{Since: cutoff, Total: usageRow{Adapter: "total"}}
+	for _, row := range rows {
+		report.Adapters = append(report.Adapters, *row)
+		report.Total.Sessions += row.Sessions
+		report.Total.Messages += row.Messages
+		report.Total.InputTokens += row.InputTokens
+		report.Total.OutputTokens += row.OutputTokens
+		report.Total.CacheRead += row.CacheRead
+		report.Total.CacheWrite += row.CacheWrite
+		report.Total.TotalTokens += row.TotalTokens
+		report.Total.EstCost += row.EstCost
+	}
+	sort.Slice(report.Adapters, func(i, j int) bool {
+		return report.Adapters[i].Adapter < report.Adapters[j].Adapter
+	})
+
+	if *asJSON {
+		return writeJSON(os.Stdout, report)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	if *detailed {
+		fmt.Fprintln(tw, "ADAPTER\tSESSIONS\tMSGS\tINPUT\tOUTPUT\tCACHE READ\tCACHE WRITE\tTOKENS\tCOST\t")
+	} else {
+		fmt.Fprintln(tw, "ADAPTER\tSESSIONS\tMSGS\tTOKENS\tCOST\t")
+	}
+	for _, row := range append(report.Adapters, report.Total) {
+		if *detailed {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t$%.2f\t\n",
+				row.Adapter, row.Sessions, row.Messages, row.InputTokens, row.OutputTokens,
+				row.CacheRead, row.CacheWrite, row.TotalTokens, row.EstCost)
+		} else {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t$%.2f\t\n",
+				row.Adapter, row.Sessions, row.Messages, row.TotalTokens, row.EstCost)
+		}
+	}
+	return tw.Flush()
+}
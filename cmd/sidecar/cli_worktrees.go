@@ -0,0 +1,105 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"text/tabwriter"
+
+	"github.com/marcus/sidecar/internal/plugins/workspace"
+)
+
+// worktreeJSON is the JSON shape for a worktree in `sidecar worktrees list`.
+type worktreeJSON struct {
+	Name         string `json:"name"`
+	Path         string `json:"path"`
+	Branch       string `json:"branch"`
+	BaseBranch   string `json:"base_branch,omitempty"`
+	TaskID       string `json:"task_id,omitempty"`
+	Agent        string `json:"agent,omitempty"`
+	AgentRunning bool   `json:"agent_running"`
+	PRURL        string `json:"pr_url,omitempty"`
+	Main         bool   `json:"main"`
+	Missing      bool   `json:"missing"`
+	Additions    int    `json:"additions"`
+	Deletions    int    `json:"deletions"`
+	Ahead        int    `json:"ahead"`
+	Behind       int    `json:"behind"`
+}
+
+// runWorktrees implements `sidecar worktrees list`.
+func runWorktrees(args []string) error {
+	if len(args) == 0 || (args[0] != "list" && args[0] != "ls") {
+		return errors.New("usage: sidecar worktrees list [--json]")
+	}
+
+	fs, project := newSubcommandFlags("worktrees list")
+	asJSON := fs.Bool("json", false, "output JSON")
+	if _, err := parseInterspersed(fs, args[1:]); err != nil {
+		return err
+	}
+
+	root, err := resolveProjectRoot(*project)
+	if err != nil {
+		return err
+	}
+	worktrees, err := workspace.ListWorktrees(root, false) // listing must not change the repo
+	if err != nil {
+		return err
+	}
+
+	out := make([]worktreeJSON, 0, len(worktrees))
+	for _, wt := range worktrees {
+		entry := worktreeJSON{
+			Name:    wt.Name,
+			Path:    wt.Path,
+			Branch:  wt.Branch,
+			Main:    wt.IsMain,
+			Missing: wt.IsMissing,
+		}
+		if !wt.IsMissing {
+			workspace.LoadWorktreeMetadata(wt)
+			entry.BaseBranch = wt.BaseBranch
+			entry.TaskID = wt.TaskID
+			entry.Agent = string(wt.ChosenAgentType)
+			entry.PRURL = wt.PRURL
+			if stats, err := workspace.ComputeStats(wt.Path); err == nil {
+				entry.Additions = stats.Additions
+				entry.Deletions = stats.Deletions
+				entry.Ahead = stats.Ahead
+				entry.Behind = stats.Behind
+			}
+			entry.AgentRunning = tmuxSessionExists(workspace.TmuxSessionName(wt))
+		}
+		out = append(out, entry)
+	}
+
+	if *asJSON {
+		return writeJSON(os.Stdout, out)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tBRANCH\tAGENT\tRUNNING\tTASK\t+/-\tPATH")
+	for _, wt := range out {
+		name := wt.Name
+		if wt.Main {
+			name += " (main)"
+		} else if wt.Missing {
+			name += " (missing)"
+		}
+		running := "-"
+		if wt.AgentRunning {
+			running = "yes"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t+%d/-%d\t%s\n",
+			name, wt.Branch, wt.Agent, running, wt.TaskID, wt.Additions, wt.Deletions, wt.Path)
+	}
+	return tw.Flush()
+}
+
+// tmuxSessionExists reports whether a tmux session with the given name is
+// running. Returns false when tmux is not installed.
+func tmuxSessionExists(name string) bool {
+	return exec.Command("tmux", "has-session", "-t", name).Run() == nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunWorktrees_ListDoesNotPrune(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "repo")
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", root}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	if err := os.MkdirAll(root, 0755); err != nil {
+		t.Fatal(err)
+	}
+	git("init", "-q", "-b", "main")
+	git("-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "--allow-empty", "-m", "init")
+	gone := filepath.Join(dir, "gone")
+	git("worktree", "add", "-q", "-b", "gone", gone)
+
+	// A worktree whose folder and branch are both gone would be auto-pruned
+	if err := os.RemoveAll(gone); err != nil {
+		t.Fatal(err)
+	}
+	git("update-ref", "-d", "refs/heads/gone")
+	admin := filepath.Join(root, ".git", "worktrees", "gone")
+	if _, err := os.Stat(admin); err != nil {
+		t.Fatalf("expected worktree admin dir: %v", err)
+	}
+
+	stdout := os.Stdout
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = devNull.Close() }()
+	os.Stdout = devNull
+	err = runWorktrees([]string{"list", "--json", "--project", root})
+	os.Stdout = stdout
+	if err != nil {
+		t.Fatalf("runWorktrees: %v", err)
+	}
+
+	if _, err := os.Stat(admin); err != nil {
+		t.Errorf("worktrees list changed .git/worktrees: %v", err)
+	}
+}
@@ -14,11 +14,13 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/marcus/sidecar/internal/adapter"
+	_ "github.com/marcus/sidecar/internal/adapter/aider"
 	_ "github.com/marcus/sidecar/internal/adapter/amp"
 	_ "github.com/marcus/sidecar/internal/adapter/claudecode"
 	_ "github.com/marcus/sidecar/internal/adapter/codex"
 	_ "github.com/marcus/sidecar/internal/adapter/cursor"
 	_ "github.com/marcus/sidecar/internal/adapter/geminicli"
+	"github.com/marcus/sidecar/internal/adapter/generic"
 	_ "github.com/marcus/sidecar/internal/adapter/kiro"
 	_ "github.com/marcus/sidecar/internal/adapter/opencode"
 	_ "github.com/marcus/sidecar/internal/adapter/warp"
@@ -34,6 +36,8 @@ import (
 	"github.com/marcus/sidecar/internal/plugins/notes"
 	"github.com/marcus/sidecar/internal/plugins/tdmonitor"
 	"github.com/marcus/sidecar/internal/plugins/workspace"
+	"github.com/marcus/sidecar/internal/pricing"
+	"github.com/marcus/sidecar/internal/remote"
 	"github.com/marcus/sidecar/internal/state"
 	"github.com/marcus/sidecar/internal/styles"
 	"github.com/marcus/sidecar/internal/theme"
@@ -58,7 +62,9 @@ func main() {
 
 	// Unset TMUX so sidecar's internal tmux sessions are independent of any
 	// outer tmux session. This allows prefix+d to detach from the workspace's
-	// inner session rather than the user's outer tmux.
+	// inner session rather than the user's outer tmux. Notifications still
+	// need to know about the outer tmux to pass escapes through it.
+	inTmux := os.Getenv("TMUX") != ""
 	os.Unsetenv("TMUX")
 
 	// Start pprof server if enabled (for memory profiling)
@@ -116,6 +122,22 @@ func main() {
 	features.Init(cfg)
 	applyFeatureOverrides()
 
+	// Initialize model pricing (defaults plus config overrides)
+	pricing.Init(cfg)
+
+	// Register config-driven adapters before any adapter detection
+	generic.Register(cfg)
+
+	// Headless subcommands (sessions, worktrees, usage, remote) print and exit
+	// without a terminal UI.
+	if handled, code := runSubcommand(flag.Args()); handled {
+		os.Exit(code)
+	} else if flag.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", flag.Arg(0))
+		flag.Usage()
+		os.Exit(2)
+	}
+
 	// Load persistent state (ignore errors - state is optional)
 	_ = state.Init()
 
@@ -157,6 +179,7 @@ func main() {
 		EventBus:    dispatcher,
 		Logger:      logger,
 		Keymap:      km,
+		InTmux:      inTmux,
 	}
 
 	// Detect adapters
@@ -185,7 +208,9 @@ func main() {
 	if err := registry.Register(conversations.New()); err != nil {
 		logger.Warn("failed to register conversations plugin", "err", err)
 	}
-	if err := registry.Register(workspace.New()); err != nil {
+	workspacePlugin := workspace.New()
+	workspacePlugin.SetTranscriptRenderer(conversations.NewTranscriptRenderer(logger))
+	if err := registry.Register(workspacePlugin); err != nil {
 		logger.Warn("failed to register workspace plugin", "err", err)
 	}
 	if features.IsEnabled("notes_plugin") {
@@ -194,10 +219,17 @@ func main() {
 		}
 	}
 
-	// Apply user keymap overrides
+	// Apply user keymap overrides (leader first, so "<leader>" expands)
+	km.SetLeader(cfg.Keymap.Leader)
+	km.SetChordTimeout(cfg.Keymap.ChordTimeout)
 	for key, cmdID := range cfg.Keymap.Overrides {
 		km.SetUserOverride(key, cmdID)
 	}
+	for ctx, bindings := range cfg.Keymap.Contexts {
+		for key, cmdID := range bindings {
+			km.SetContextOverride(ctx, key, cmdID)
+		}
+	}
 
 	// Create and run application
 	currentVersion := effectiveVersion(Version)
@@ -209,14 +241,51 @@ func main() {
 		fmt.Fprintln(os.Stderr, "sidecar requires an interactive terminal")
 		os.Exit(1)
 	}
+	// Serve the remote control API if enabled. Agents started from sidecar
+	// inherit the socket path; an inherited one belongs to another sidecar.
+	remoteServer := startRemoteServer(cfg, dispatcher, logger)
+	_ = os.Unsetenv(remote.EnvSocket)
+	if remoteServer != nil {
+		_ = os.Setenv(remote.EnvSocket, remoteServer.Path())
+	}
+
 	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseAllMotion())
+	if remoteServer != nil {
+		go func() {
+			if err := remoteServer.Serve(func(r *remote.Request) { p.Send(r) }); err != nil {
+				logger.Warn("remote control stopped", "err", err)
+			}
+		}()
+	}
 
-	if _, err := p.Run(); err != nil {
+	_, err = p.Run()
+	if remoteServer != nil {
+		_ = remoteServer.Close()
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error running application: %v\n", err)
 		os.Exit(1)
 	}
 }
 
+// startRemoteServer listens on the remote control socket when enabled in
+// config. Failures are logged and leave remote control off.
+func startRemoteServer(cfg *config.Config, bus *event.Dispatcher, logger *slog.Logger) *remote.Server {
+	if !cfg.Remote.Enabled {
+		return nil
+	}
+	path := cfg.Remote.Socket
+	if path == "" {
+		path = remote.DefaultSocketPath(filepath.Dir(config.ConfigPath()), os.Getpid())
+	}
+	srv, err := remote.Listen(path, bus, logger)
+	if err != nil {
+		logger.Warn("remote control disabled", "socket", path, "err", err)
+		return nil
+	}
+	return srv
+}
+
 func loadConfig(path string) (*config.Config, error) {
 	if path != "" {
 		return config.LoadFrom(path)
@@ -271,9 +340,13 @@ func effectiveVersion(v string) string {
 func init() {
 	// Customize usage output
 	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Usage: sidecar [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Usage: sidecar [options] [command]\n\n")
 		fmt.Fprintf(os.Stderr, "A TUI dashboard for AI coding agents.\n\n")
-		fmt.Fprintf(os.Stderr, "Options:\n")
+		fmt.Fprintf(os.Stderr, "Commands:\n")
+		for _, name := range subcommandNames() {
+			fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, subcommands[name].summary)
+		}
+		fmt.Fprintf(os.Stderr, "\nOptions:\n")
 		flag.PrintDefaults()
 	}
 }
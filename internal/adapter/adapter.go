@@ -100,6 +100,10 @@ type Session struct {
 	FileSize     int64   // Session file size in bytes, for performance-aware behavior
 	Path         string  // Absolute path to session file (for tiered watching, td-dca6fe)
 
+	// UnpricedModels lists models used in the session that have no entry in
+	// the pricing table; their tokens are not included in EstCost.
+	UnpricedModels []string
+
 	// Worktree fields - populated when session is from a different worktree
 	WorktreeName string // Branch name or directory name of the worktree (empty if main or non-worktree)
 	WorktreePath string // Absolute path to the worktree (empty if same as current workdir)
@@ -154,7 +158,9 @@ type Message struct {
 	ContentBlocks  []ContentBlock // Structured content for rich display
 }
 
-// TokenUsage tracks token counts for a message or session.
+// TokenUsage tracks token counts for a message or session. InputTokens
+// excludes cache reads and writes, as Anthropic reports it; adapters whose
+// source counts cached tokens as part of the input subtract them.
 type TokenUsage struct {
 	InputTokens  int
 	OutputTokens int
@@ -0,0 +1,345 @@
+package aider
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/marcus/sidecar/internal/adapter"
+	"github.com/marcus/sidecar/internal/adapter/cache"
+	"github.com/marcus/sidecar/internal/pricing"
+)
+
+const (
+	adapterID   = "aider"
+	adapterName = "Aider"
+
+	// ChatHistoryFile is the Markdown transcript Aider writes in the project root.
+	ChatHistoryFile = ".aider.chat.history.md"
+	// InputHistoryFile holds the user's prompts with timestamps.
+	InputHistoryFile = ".aider.input.history"
+
+	historyCacheMaxEntries = 64
+)
+
+// Adapter implements the adapter.Adapter interface for Aider chat history.
+type Adapter struct {
+	sessionIndex map[string]sessionRef // session ID -> location
+	mu           sync.RWMutex          // guards sessionIndex
+	historyCache *cache.Cache[[]*chatSession]
+}
+
+// sessionRef locates a session within a project's chat history.
+type sessionRef struct {
+	projectRoot string
+	index       int
+}
+
+// New creates a new Aider adapter.
+func New() *Adapter {
+	return &Adapter{
+		sessionIndex: make(map[string]sessionRef),
+		historyCache: cache.New[[]*chatSession](historyCacheMaxEntries),
+	}
+}
+
+// ID returns the adapter identifier.
+func (a *Adapter) ID() string { return adapterID }
+
+// Name returns the human-readable adapter name.
+func (a *Adapter) Name() string { return adapterName }
+
+// Icon returns the adapter icon for badge display.
+func (a *Adapter) Icon() string { return "◈" }
+
+// Detect checks if an Aider chat history exists in the project root.
+func (a *Adapter) Detect(projectRoot string) (bool, error) {
+	info, err := os.Stat(filepath.Join(projectRoot, ChatHistoryFile))
+	if err != nil {
+		return false, nil
+	}
+	return info.Size() > 0, nil
+}
+
+// Capabilities returns the supported features.
+func (a *Adapter) Capabilities() adapter.CapabilitySet {
+	return adapter.CapabilitySet{
+		adapter.CapSessions: true,
+		adapter.CapMessages: true,
+		adapter.CapUsage:    true,
+		adapter.CapWatch:    true,
+	}
+}
+
+// Sessions returns the sessions in the project's chat history, newest first.
+// Only the newest session carries Path, since older sections of the history
+// file never change and the file is shared by all of them.
+func (a *Adapter) Sessions(projectRoot string) ([]adapter.Session, error) {
+	path := filepath.Join(projectRoot, ChatHistoryFile)
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	chats, err := a.loadHistory(projectRoot, info)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := projectHash(projectRoot)
+	sessions := make([]adapter.Session, 0, len(chats))
+	seen := make(map[string]int)
+	refs := make(map[string]sessionRef, len(chats))
+	for i, chat := range chats {
+		if len(chat.Messages) == 0 {
+			continue
+		}
+		id := sessionID(chat.Start, prefix)
+		if n := seen[id]; n > 0 {
+			seen[id]++
+			id = fmt.Sprintf("%s-%d", id, n)
+		} else {
+			seen[id] = 1
+		}
+		refs[id] = sessionRef{projectRoot: projectRoot, index: i}
+
+		created := chat.Start
+		updated := chat.Messages[len(chat.Messages)-1].Timestamp
+		isLast := i == len(chats)-1
+		if isLast && info.ModTime().After(updated) {
+			updated = info.ModTime()
+		}
+		if created.IsZero() {
+			created = updated
+		}
+
+		name := ""
+		for _, m := range chat.Messages {
+			if m.Role == "user" && m.Content != "" {
+				name = truncateTitle(m.Content, 50)
+				break
+			}
+		}
+		if name == "" {
+			name = shortID(id)
+		}
+
+		s := adapter.Session{
+			ID:          id,
+			Name:        name,
+			AdapterID:   adapterID,
+			AdapterName: adapterName,
+			AdapterIcon: a.Icon(),
+			CreatedAt:   created,
+			UpdatedAt:   updated,
+			Duration:    updated.Sub(created),
+			FileSize:    chat.Length,
+		}
+		for _, m := range chat.Messages {
+			if m.Role == "user" || m.Role == "assistant" {
+				s.MessageCount++
+			}
+			s.TotalTokens += m.InputTokens + m.OutputTokens
+		}
+		s.EstCost, s.UnpricedModels = estimateCost(chat)
+		if isLast {
+			s.IsActive = time.Since(updated) < 5*time.Minute
+			s.Path = path // td-dca6fe: tiered watching needs session file path
+		}
+		sessions = append(sessions, s)
+	}
+
+	a.mu.Lock()
+	for id, ref := range a.sessionIndex {
+		if ref.projectRoot == projectRoot {
+			delete(a.sessionIndex, id)
+		}
+	}
+	for id, ref := range refs {
+		a.sessionIndex[id] = ref
+	}
+	a.mu.Unlock()
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].UpdatedAt.After(sessions[j].UpdatedAt)
+	})
+	return sessions, nil
+}
+
+// Messages returns all messages for a session.
+func (a *Adapter) Messages(sessionID string) ([]adapter.Message, error) {
+	a.mu.RLock()
+	ref, ok := a.sessionIndex[sessionID]
+	a.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("session %s not found", sessionID)
+	}
+
+	info, err := os.Stat(filepath.Join(ref.projectRoot, ChatHistoryFile))
+	if err != nil {
+		return nil, err
+	}
+	chats, err := a.loadHistory(ref.projectRoot, info)
+	if err != nil {
+		return nil, err
+	}
+	if ref.index >= len(chats) {
+		return nil, fmt.Errorf("session %s not found", sessionID)
+	}
+	return chats[ref.index].Messages, nil
+}
+
+// Usage returns aggregate usage stats for a session.
+func (a *Adapter) Usage(sessionID string) (*adapter.UsageStats, error) {
+	messages, err := a.Messages(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &adapter.UsageStats{}
+	for _, m := range messages {
+		stats.TotalInputTokens += m.InputTokens
+		stats.TotalOutputTokens += m.OutputTokens
+		stats.TotalCacheRead += m.CacheRead
+		stats.TotalCacheWrite += m.CacheWrite
+		stats.MessageCount++
+	}
+	return stats, nil
+}
+
+// Watch returns a channel that emits events when the project's Aider
+// history files change.
+func (a *Adapter) Watch(projectRoot string) (<-chan adapter.Event, io.Closer, error) {
+	return NewWatcher(projectRoot, a.latestSessionID)
+}
+
+// loadHistory parses the project's chat history and dates its messages
+// from the input history. Results are cached until the chat history changes.
+func (a *Adapter) loadHistory(projectRoot string, info os.FileInfo) ([]*chatSession, error) {
+	path := filepath.Join(projectRoot, ChatHistoryFile)
+	if chats, ok := a.historyCache.Get(path, info.Size(), info.ModTime()); ok {
+		return chats, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	chats, err := parseChatHistory(f)
+	if err != nil {
+		return nil, err
+	}
+
+	var inputs []inputEntry
+	if in, err := os.Open(filepath.Join(projectRoot, InputHistoryFile)); err == nil {
+		inputs, _ = parseInputHistory(in)
+		_ = in.Close()
+	}
+	for i, chat := range chats {
+		var end time.Time
+		if i+1 < len(chats) {
+			end = chats[i+1].Start
+		}
+		applyTimestamps(chat, inputs, end)
+	}
+
+	a.historyCache.Set(path, chats, info.Size(), info.ModTime(), 0)
+	return chats, nil
+}
+
+// latestSessionID returns the ID of the newest session in a project, which
+// is the only one that can still change.
+func (a *Adapter) latestSessionID(projectRoot string) string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	latest, best := "", -1
+	for id, ref := range a.sessionIndex {
+		if ref.projectRoot == projectRoot && ref.index > best {
+			latest, best = id, ref.index
+		}
+	}
+	return latest
+}
+
+// estimateCost prices each message with the pricing table, falling back to
+// Aider's own cost report for models the table doesn't know.
+func estimateCost(chat *chatSession) (cost float64, unpriced []string) {
+	seen := make(map[string]bool)
+	for i, m := range chat.Messages {
+		if m.InputTokens+m.OutputTokens+m.CacheRead+m.CacheWrite == 0 {
+			continue
+		}
+		model := m.Model
+		if model == "" {
+			model = chat.Model
+		}
+		c, ok := pricing.Cost(model, m.Timestamp, pricing.Usage{
+			Input:      m.InputTokens,
+			Output:     m.OutputTokens,
+			CacheRead:  m.CacheRead,
+			CacheWrite: m.CacheWrite,
+		})
+		if !ok {
+			if reported, has := chat.ReportedCost[i]; has {
+				c, ok = reported, true
+			}
+		}
+		if !ok {
+			if model == "" {
+				model = "unknown"
+			}
+			if !seen[model] {
+				seen[model] = true
+				unpriced = append(unpriced, model)
+			}
+			continue
+		}
+		cost += c
+	}
+	sort.Strings(unpriced)
+	return cost, unpriced
+}
+
+// sessionID derives a stable ID from the session start time and project.
+func sessionID(start time.Time, projectHash string) string {
+	return fmt.Sprintf("aider-%s-%s", start.Format("20060102-150405"), projectHash)
+}
+
+// projectHash returns a short hash of the project root, keeping session IDs
+// unique across projects.
+func projectHash(projectRoot string) string {
+	abs, err := filepath.Abs(projectRoot)
+	if err != nil {
+		abs = projectRoot
+	}
+	sum := sha1.Sum([]byte(filepath.Clean(abs)))
+	return hex.EncodeToString(sum[:])[:6]
+}
+
+// truncateTitle truncates text to maxLen, adding "..." if truncated.
+// It also replaces newlines with spaces for display.
+func truncateTitle(s string, maxLen int) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	s = strings.ReplaceAll(s, "\r", "")
+	s = strings.TrimSpace(s)
+
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen-3] + "..."
+}
+
+// shortID returns the timestamp portion of a session ID for display.
+func shortID(id string) string {
+	return strings.TrimPrefix(id, "aider-")
+}
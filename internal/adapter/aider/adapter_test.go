@@ -0,0 +1,220 @@
+package aider
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcus/sidecar/internal/adapter"
+	"github.com/marcus/sidecar/internal/adapter/testutil"
+)
+
+// setupFixtureProject copies the testdata history files into a temp project.
+func setupFixtureProject(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for src, dst := range map[string]string{
+		"chat.history.md": ChatHistoryFile,
+		"input.history":   InputHistoryFile,
+	} {
+		data, err := os.ReadFile(filepath.Join("testdata", src))
+		if err != nil {
+			t.Fatalf("read fixture: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, dst), data, 0644); err != nil {
+			t.Fatalf("write fixture: %v", err)
+		}
+	}
+	return dir
+}
+
+func TestDetect(t *testing.T) {
+	a := New()
+	found, err := a.Detect(setupFixtureProject(t))
+	if err != nil || !found {
+		t.Errorf("expected Aider history to be detected, got %v (err %v)", found, err)
+	}
+	found, _ = a.Detect(t.TempDir())
+	if found {
+		t.Error("should not detect a project without history")
+	}
+}
+
+func TestSessions_Fixture(t *testing.T) {
+	project := setupFixtureProject(t)
+	a := New()
+	sessions, err := a.Sessions(project)
+	if err != nil {
+		t.Fatalf("Sessions: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(sessions))
+	}
+
+	// Newest first; only the newest carries Path
+	latest, first := sessions[0], sessions[1]
+	if !strings.HasPrefix(latest.ID, "aider-20240502-093000-") {
+		t.Errorf("unexpected latest ID %q", latest.ID)
+	}
+	if latest.Path == "" || first.Path != "" {
+		t.Errorf("expected Path only on newest session, got %q / %q", latest.Path, first.Path)
+	}
+	if first.Name != "add retries to fetch with exponential backoff" {
+		t.Errorf("unexpected name %q", first.Name)
+	}
+	if first.MessageCount != 4 {
+		t.Errorf("expected 4 messages in first session, got %d", first.MessageCount)
+	}
+	if first.TotalTokens != 2512 {
+		t.Errorf("expected 2512 tokens (2k uncached in + 512 out), got %d", first.TotalTokens)
+	}
+	if first.EstCost <= 0 || len(first.UnpricedModels) != 0 {
+		t.Errorf("expected priced session, got %v / %v", first.EstCost, first.UnpricedModels)
+	}
+	wantUpdated := time.Date(2024, 5, 1, 10, 2, 5, 0, time.Local)
+	if !first.UpdatedAt.Equal(wantUpdated) {
+		t.Errorf("UpdatedAt = %v, want %v (from input history)", first.UpdatedAt, wantUpdated)
+	}
+
+	// Unknown model falls back to Aider's reported cost
+	if latest.EstCost != 0.0012 || len(latest.UnpricedModels) != 0 {
+		t.Errorf("expected reported cost fallback, got %v / %v", latest.EstCost, latest.UnpricedModels)
+	}
+}
+
+func TestMessages_Fixture(t *testing.T) {
+	project := setupFixtureProject(t)
+	a := New()
+	sessions, err := a.Sessions(project)
+	if err != nil || len(sessions) != 2 {
+		t.Fatalf("Sessions: %v (%d)", err, len(sessions))
+	}
+
+	msgs, err := a.Messages(sessions[1].ID)
+	if err != nil {
+		t.Fatalf("Messages: %v", err)
+	}
+	if len(msgs) != 4 {
+		t.Fatalf("expected 4 messages, got %d", len(msgs))
+	}
+
+	user := msgs[0]
+	if user.Role != "user" || user.Content != "add retries to fetch\nwith exponential backoff" {
+		t.Errorf("unexpected user message: %+v", user)
+	}
+	if want := time.Date(2024, 5, 1, 10, 0, 20, 123456000, time.Local); !user.Timestamp.Equal(want) {
+		t.Errorf("user timestamp = %v, want %v", user.Timestamp, want)
+	}
+
+	reply := msgs[1]
+	if reply.Role != "assistant" || reply.Model != "claude-3-5-sonnet-20241022" {
+		t.Errorf("unexpected reply: role %q model %q", reply.Role, reply.Model)
+	}
+	if !strings.Contains(reply.Content, "#### this is code, not a prompt") || !strings.Contains(reply.Content, "> neither is this") {
+		t.Errorf("fenced code should stay in the reply:\n%s", reply.Content)
+	}
+	if reply.InputTokens != 2000 || reply.CacheWrite != 2000 || reply.CacheRead != 6000 || reply.OutputTokens != 512 {
+		t.Errorf("unexpected usage: %+v", reply.TokenUsage)
+	}
+	if len(reply.ToolUses) != 1 || !strings.Contains(reply.ToolUses[0].Output, "Commit 1a2b3c4") {
+		t.Errorf("expected applied edit/commit tool output, got %+v", reply.ToolUses)
+	}
+
+	// /run output with no assistant text becomes a tool-only reply
+	if msgs[2].Content != "/run pytest" || len(msgs[3].ToolUses) != 1 || msgs[3].Content != "" {
+		t.Errorf("unexpected /run handling: %+v / %+v", msgs[2], msgs[3])
+	}
+}
+
+func TestSearchMessages(t *testing.T) {
+	project := setupFixtureProject(t)
+	a := New()
+	sessions, _ := a.Sessions(project)
+	if len(sessions) == 0 {
+		t.Fatal("no sessions")
+	}
+	matches, err := a.SearchMessages(sessions[1].ID, "backoff", adapter.DefaultSearchOptions())
+	if err != nil {
+		t.Fatalf("SearchMessages: %v", err)
+	}
+	if len(matches) != 1 || matches[0].Role != "user" {
+		t.Errorf("expected 1 user match, got %+v", matches)
+	}
+}
+
+func TestSessions_Generated(t *testing.T) {
+	project := t.TempDir()
+	if err := testutil.GenerateAiderHistory(project, 3, 5); err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	a := New()
+	sessions, err := a.Sessions(project)
+	if err != nil {
+		t.Fatalf("Sessions: %v", err)
+	}
+	if len(sessions) != 3 {
+		t.Fatalf("expected 3 sessions, got %d", len(sessions))
+	}
+	for _, s := range sessions {
+		if s.MessageCount != 10 || s.TotalTokens != 5*1450 {
+			t.Errorf("%s: got %d messages / %d tokens, want 10 / %d", s.ID, s.MessageCount, s.TotalTokens, 5*1450)
+		}
+	}
+	oldest := sessions[2]
+	if !oldest.CreatedAt.Equal(testutil.AiderSessionStart(0)) {
+		t.Errorf("CreatedAt = %v, want %v", oldest.CreatedAt, testutil.AiderSessionStart(0))
+	}
+	usage, err := a.Usage(oldest.ID)
+	if err != nil || usage.TotalOutputTokens != 5*250 {
+		t.Errorf("unexpected usage %+v (err %v)", usage, err)
+	}
+}
+
+func TestTokenCount(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"Tokens: 1.2k sent", 1200},
+		{"Tokens: 2,345 sent", 2345},
+		{"Tokens: 1.5M sent", 1500000},
+		{"Tokens: 87 sent", 87},
+		{"no tokens here", 0},
+	}
+	for _, tt := range tests {
+		if got := tokenCount(sentRe, tt.text); got != tt.want {
+			t.Errorf("tokenCount(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestWatch(t *testing.T) {
+	project := setupFixtureProject(t)
+	a := New()
+	if _, err := a.Sessions(project); err != nil {
+		t.Fatalf("Sessions: %v", err)
+	}
+	events, closer, err := a.Watch(project)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	defer func() { _ = closer.Close() }()
+
+	f, err := os.OpenFile(filepath.Join(project, ChatHistoryFile), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("\n#### one more thing\n")
+	_ = f.Close()
+
+	select {
+	case evt := <-events:
+		if !strings.HasPrefix(evt.SessionID, "aider-20240502-") {
+			t.Errorf("expected event for newest session, got %q", evt.SessionID)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for watch event")
+	}
+}
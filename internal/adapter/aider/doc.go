@@ -0,0 +1,5 @@
+// Package aider provides an adapter for Aider that reads the per-project
+// .aider.chat.history.md transcript, splitting it into one session per
+// "aider chat started" header, and uses .aider.input.history to timestamp
+// user prompts.
+package aider
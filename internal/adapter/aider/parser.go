@@ -0,0 +1,331 @@
+package aider
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marcus/sidecar/internal/adapter"
+)
+
+const (
+	sessionHeaderPrefix = "# aider chat started at "
+	userPrefix          = "#### "
+	headerTimeLayout    = "2006-01-02 15:04:05"
+	inputTimeLayout     = "2006-01-02 15:04:05.999999"
+	toolName            = "aider"
+)
+
+var (
+	// modelLineRe matches the model banner, e.g. "Main model: gpt-4o with diff edit format".
+	modelLineRe = regexp.MustCompile(`^(?:Main )?[Mm]odel: (\S+)`)
+	// tokensLineRe matches the per-message usage report.
+	tokensLineRe  = regexp.MustCompile(`^Tokens: `)
+	sentRe        = regexp.MustCompile(`([\d.,]+[kKmM]?) sent`)
+	cacheWriteRe  = regexp.MustCompile(`([\d.,]+[kKmM]?) cache write`)
+	cacheHitRe    = regexp.MustCompile(`([\d.,]+[kKmM]?) cache hit`)
+	receivedRe    = regexp.MustCompile(`([\d.,]+[kKmM]?) received`)
+	messageCostRe = regexp.MustCompile(`Cost: \$([\d.]+) message`)
+)
+
+// chatSession is one "aider chat started" section of the chat history.
+type chatSession struct {
+	Start    time.Time
+	Model    string // model from the startup banner
+	Messages []adapter.Message
+	// ReportedCost holds Aider's own cost report per message index, used
+	// when the pricing table doesn't know the model.
+	ReportedCost map[int]float64
+	// Offset and Length are the section's byte range in the history file.
+	Offset int64
+	Length int64
+}
+
+// inputEntry is one prompt from .aider.input.history.
+type inputEntry struct {
+	Time time.Time
+	Text string
+}
+
+// parseChatHistory splits an Aider chat history into sessions.
+//
+// The file is Markdown: each session starts with a "# aider chat started at"
+// header, user prompts are "#### " lines, Aider's own output (startup banner,
+// applied edits, commits, command output, token reports) is quoted with "> ",
+// and everything else is assistant text.
+func parseChatHistory(r io.Reader) ([]*chatSession, error) {
+	reader := bufio.NewReader(r)
+	var (
+		sessions []*chatSession
+		cur      *chatSession
+		p        sessionParser
+		offset   int64
+	)
+	finish := func(end int64) {
+		if cur == nil {
+			return
+		}
+		cur.Messages = p.finish()
+		cur.Length = end - cur.Offset
+		sessions = append(sessions, cur)
+	}
+
+	for {
+		raw, err := reader.ReadString('\n')
+		if raw == "" && err != nil {
+			if err != io.EOF {
+				return nil, err
+			}
+			break
+		}
+		lineStart := offset
+		offset += int64(len(raw))
+		line := strings.TrimRight(raw, "\r\n")
+
+		if strings.HasPrefix(line, sessionHeaderPrefix) && !p.inFence {
+			finish(lineStart)
+			start, _ := time.ParseInLocation(headerTimeLayout, strings.TrimSpace(strings.TrimPrefix(line, sessionHeaderPrefix)), time.Local)
+			cur = &chatSession{Start: start, ReportedCost: make(map[int]float64), Offset: lineStart}
+			p = sessionParser{session: cur}
+			continue
+		}
+		if cur == nil {
+			continue // preamble before the first header
+		}
+		p.line(line)
+
+		if err == io.EOF {
+			break
+		}
+	}
+	finish(offset)
+	return sessions, nil
+}
+
+// sessionParser accumulates messages for one session.
+type sessionParser struct {
+	session  *chatSession
+	messages []adapter.Message
+	cur      *adapter.Message
+	text     []string // content lines of cur
+	tool     []string // open block of quoted Aider output
+	inFence  bool
+	model    string
+}
+
+func (p *sessionParser) line(line string) {
+	isQuote := line == ">" || strings.HasPrefix(line, "> ")
+	isUser := strings.HasPrefix(line, userPrefix) || line == strings.TrimSpace(userPrefix)
+
+	if p.inFence || (!isQuote && !isUser) {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			p.inFence = !p.inFence
+		}
+		p.assistantLine(line)
+		return
+	}
+
+	if isUser {
+		text := strings.TrimRight(strings.TrimPrefix(strings.TrimPrefix(line, strings.TrimSpace(userPrefix)), " "), " ")
+		if p.cur != nil && p.cur.Role == "user" {
+			p.text = append(p.text, text)
+			return
+		}
+		p.flush()
+		p.cur = &adapter.Message{Role: "user"}
+		p.text = []string{text}
+		return
+	}
+
+	// Quoted Aider output
+	// Aider ends quoted lines with two spaces (a Markdown line break)
+	text := strings.TrimRight(strings.TrimPrefix(strings.TrimPrefix(line, ">"), " "), " ")
+	if m := modelLineRe.FindStringSubmatch(text); m != nil {
+		p.model = m[1]
+		if p.session.Model == "" {
+			p.session.Model = m[1]
+		}
+	}
+	if p.cur == nil {
+		return // startup banner
+	}
+	if tokensLineRe.MatchString(text) {
+		p.applyTokens(text)
+		return
+	}
+	if p.cur.Role == "user" {
+		// Output with no assistant reply, e.g. after /run or /add
+		p.startAssistant()
+	}
+	p.tool = append(p.tool, text)
+}
+
+// assistantLine adds a line of assistant text.
+func (p *sessionParser) assistantLine(line string) {
+	if p.cur == nil {
+		return
+	}
+	if p.cur.Role == "user" {
+		if strings.TrimSpace(line) == "" {
+			return
+		}
+		p.startAssistant()
+	}
+	p.closeTool()
+	p.text = append(p.text, line)
+}
+
+func (p *sessionParser) startAssistant() {
+	p.flush()
+	p.cur = &adapter.Message{Role: "assistant", Model: p.model}
+}
+
+// closeTool turns the open block of quoted output into a tool use.
+func (p *sessionParser) closeTool() {
+	if p.cur == nil || len(p.tool) == 0 {
+		return
+	}
+	output := strings.TrimSpace(strings.Join(p.tool, "\n"))
+	p.tool = nil
+	if output == "" {
+		return
+	}
+	input := output
+	if i := strings.IndexByte(input, '\n'); i >= 0 {
+		input = input[:i]
+	}
+	p.cur.ToolUses = append(p.cur.ToolUses, adapter.ToolUse{
+		ID:     fmt.Sprintf("tool-%d-%d", len(p.messages), len(p.cur.ToolUses)),
+		Name:   toolName,
+		Input:  input,
+		Output: output,
+	})
+}
+
+// applyTokens records a "Tokens: ..." report on the current assistant
+// message. Aider's "sent" count includes cache reads and writes.
+func (p *sessionParser) applyTokens(text string) {
+	if p.cur.Role == "user" {
+		p.startAssistant()
+	}
+	sent := tokenCount(sentRe, text)
+	write := tokenCount(cacheWriteRe, text)
+	hit := tokenCount(cacheHitRe, text)
+	p.cur.InputTokens = max(sent-write-hit, 0)
+	p.cur.CacheWrite = write
+	p.cur.CacheRead = hit
+	p.cur.OutputTokens = tokenCount(receivedRe, text)
+	if m := messageCostRe.FindStringSubmatch(text); m != nil {
+		if cost, err := strconv.ParseFloat(m[1], 64); err == nil {
+			p.session.ReportedCost[len(p.messages)] = cost
+		}
+	}
+}
+
+// flush finalizes the current message.
+func (p *sessionParser) flush() {
+	if p.cur == nil {
+		return
+	}
+	p.closeTool()
+	msg := *p.cur
+	msg.Content = strings.TrimSpace(strings.Join(p.text, "\n"))
+	p.cur, p.text = nil, nil
+	if msg.Content == "" && len(msg.ToolUses) == 0 && msg.InputTokens+msg.OutputTokens == 0 {
+		return
+	}
+	msg.ID = fmt.Sprintf("msg-%d", len(p.messages))
+	p.messages = append(p.messages, msg)
+}
+
+func (p *sessionParser) finish() []adapter.Message {
+	p.flush()
+	return p.messages
+}
+
+// tokenCount extracts a count such as "1.2k", "2,345" or "1.5M".
+func tokenCount(re *regexp.Regexp, text string) int {
+	m := re.FindStringSubmatch(text)
+	if m == nil {
+		return 0
+	}
+	s := strings.ReplaceAll(m[1], ",", "")
+	mult := 1.0
+	switch s[len(s)-1] {
+	case 'k', 'K':
+		mult, s = 1e3, s[:len(s)-1]
+	case 'm', 'M':
+		mult, s = 1e6, s[:len(s)-1]
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return int(n*mult + 0.5)
+}
+
+// parseInputHistory reads .aider.input.history: a "# <timestamp>" line
+// followed by the prompt's lines, each prefixed with "+".
+func parseInputHistory(r io.Reader) ([]inputEntry, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	var (
+		entries []inputEntry
+		cur     *inputEntry
+		lines   []string
+	)
+	flush := func() {
+		if cur != nil {
+			cur.Text = strings.Join(lines, "\n")
+			entries = append(entries, *cur)
+		}
+		cur, lines = nil, nil
+	}
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "# "):
+			flush()
+			ts, err := time.ParseInLocation(inputTimeLayout, strings.TrimPrefix(line, "# "), time.Local)
+			if err != nil {
+				continue
+			}
+			cur = &inputEntry{Time: ts}
+		case strings.HasPrefix(line, "+") && cur != nil:
+			lines = append(lines, strings.TrimPrefix(line, "+"))
+		}
+	}
+	flush()
+	return entries, scanner.Err()
+}
+
+// applyTimestamps dates messages using the input history. User prompts are
+// matched in order by text within the session's time window; other messages
+// inherit the previous timestamp, starting from the session start.
+func applyTimestamps(s *chatSession, inputs []inputEntry, end time.Time) {
+	next := 0
+	for next < len(inputs) && inputs[next].Time.Before(s.Start) {
+		next++
+	}
+	last := s.Start
+	for i := range s.Messages {
+		msg := &s.Messages[i]
+		if msg.Role == "user" {
+			for j := next; j < len(inputs); j++ {
+				if !end.IsZero() && !inputs[j].Time.Before(end) {
+					break
+				}
+				if strings.TrimSpace(inputs[j].Text) == msg.Content {
+					last = inputs[j].Time
+					next = j + 1
+					break
+				}
+			}
+		}
+		msg.Timestamp = last
+	}
+}
@@ -0,0 +1,105 @@
+package aider
+
+import (
+	"io"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/marcus/sidecar/internal/adapter"
+)
+
+// NewWatcher creates a watcher for a project's Aider history files. Events
+// are attributed to the newest session, since Aider only appends to it.
+func NewWatcher(projectRoot string, latestSessionID func(projectRoot string) string) (<-chan adapter.Event, io.Closer, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Watch the directory: Aider may recreate the files
+	if err := watcher.Add(projectRoot); err != nil {
+		_ = watcher.Close()
+		return nil, nil, err
+	}
+
+	events := make(chan adapter.Event, 32)
+
+	go func() {
+		var debounceTimer *time.Timer
+		var lastEvent fsnotify.Event
+		debounceDelay := 200 * time.Millisecond
+
+		var closed bool
+		var mu sync.Mutex
+
+		defer func() {
+			mu.Lock()
+			closed = true
+			if debounceTimer != nil {
+				debounceTimer.Stop()
+			}
+			mu.Unlock()
+			close(events)
+		}()
+
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				name := filepath.Base(event.Name)
+				if name != ChatHistoryFile && name != InputHistoryFile {
+					continue
+				}
+
+				mu.Lock()
+				lastEvent = event
+
+				if debounceTimer != nil {
+					debounceTimer.Stop()
+				}
+				debounceTimer = time.AfterFunc(debounceDelay, func() {
+					mu.Lock()
+					defer mu.Unlock()
+
+					if closed {
+						return
+					}
+
+					var eventType adapter.EventType
+					switch {
+					case lastEvent.Op&fsnotify.Create != 0:
+						eventType = adapter.EventSessionCreated
+					case lastEvent.Op&fsnotify.Write != 0:
+						eventType = adapter.EventMessageAdded
+					case lastEvent.Op&fsnotify.Remove != 0:
+						return
+					default:
+						eventType = adapter.EventSessionUpdated
+					}
+
+					select {
+					case events <- adapter.Event{
+						Type:      eventType,
+						SessionID: latestSessionID(projectRoot),
+					}:
+					default:
+						// Channel full, drop event
+					}
+				})
+				mu.Unlock()
+
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+
+	return events, watcher, nil
+}
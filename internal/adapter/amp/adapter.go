@@ -438,7 +438,7 @@ func (a *Adapter) parseThreadMeta(path string) (*threadMeta, error) {
 
 		// Aggregate usage
 		if msg.Usage != nil {
-			totalInput += msg.Usage.TotalInputTokens
+			totalInput += msg.Usage.InputTokens
 			totalOutput += msg.Usage.OutputTokens
 			if meta.Model == "" {
 				meta.Model = msg.Usage.Model
@@ -527,7 +527,7 @@ func (a *Adapter) parseMessages(path string) ([]adapter.Message, error) {
 		if msg.Usage != nil {
 			adapterMsg.Model = msg.Usage.Model
 			adapterMsg.TokenUsage = adapter.TokenUsage{
-				InputTokens:  msg.Usage.TotalInputTokens,
+				InputTokens:  msg.Usage.InputTokens, // TotalInputTokens includes cache reads and writes
 				OutputTokens: msg.Usage.OutputTokens,
 				CacheRead:    msg.Usage.CacheReadInputTokens,
 				CacheWrite:   msg.Usage.CacheCreationInputTokens,
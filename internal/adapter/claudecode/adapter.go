@@ -15,6 +15,7 @@ import (
 
 	"github.com/marcus/sidecar/internal/adapter"
 	"github.com/marcus/sidecar/internal/adapter/cache"
+	"github.com/marcus/sidecar/internal/pricing"
 )
 
 // xmlTagRegex matches XML/HTML-like tags for stripping from session titles
@@ -150,22 +151,23 @@ func (a *Adapter) Sessions(projectRoot string) ([]adapter.Session, error) {
 		newIndex[meta.SessionID] = path
 
 		sessions = append(sessions, adapter.Session{
-			ID:           meta.SessionID,
-			Name:         name,
-			Slug:         meta.Slug,
-			AdapterID:    adapterID,
-			AdapterName:  adapterName,
-			AdapterIcon:  a.Icon(),
-			CreatedAt:    meta.FirstMsg,
-			UpdatedAt:    meta.LastMsg,
-			Duration:     meta.LastMsg.Sub(meta.FirstMsg),
-			IsActive:     time.Since(meta.LastMsg) < 5*time.Minute,
-			TotalTokens:  meta.TotalTokens,
-			EstCost:      meta.EstCost,
-			IsSubAgent:   isSubAgent,
-			MessageCount: meta.MsgCount,
-			FileSize:     info.Size(),
-			Path:         path, // td-dca6fe: tiered watching needs session file path
+			ID:             meta.SessionID,
+			Name:           name,
+			Slug:           meta.Slug,
+			AdapterID:      adapterID,
+			AdapterName:    adapterName,
+			AdapterIcon:    a.Icon(),
+			CreatedAt:      meta.FirstMsg,
+			UpdatedAt:      meta.LastMsg,
+			Duration:       meta.LastMsg.Sub(meta.FirstMsg),
+			IsActive:       time.Since(meta.LastMsg) < 5*time.Minute,
+			TotalTokens:    meta.TotalTokens,
+			EstCost:        meta.EstCost,
+			UnpricedModels: meta.UnpricedModels,
+			IsSubAgent:     isSubAgent,
+			MessageCount:   meta.MsgCount,
+			FileSize:       info.Size(),
+			Path:           path, // td-dca6fe: tiered watching needs session file path
 		})
 	}
 
@@ -217,21 +219,22 @@ func (a *Adapter) SessionByID(sessionID string) (*adapter.Session, error) {
 	isSubAgent := strings.HasPrefix(filepath.Base(path), "agent-")
 
 	return &adapter.Session{
-		ID:           meta.SessionID,
-		Name:         name,
-		Slug:         meta.Slug,
-		AdapterID:    adapterID,
-		AdapterName:  adapterName,
-		AdapterIcon:  a.Icon(),
-		CreatedAt:    meta.FirstMsg,
-		UpdatedAt:    meta.LastMsg,
-		Duration:     meta.LastMsg.Sub(meta.FirstMsg),
-		IsActive:     time.Since(meta.LastMsg) < 5*time.Minute,
-		TotalTokens:  meta.TotalTokens,
-		EstCost:      meta.EstCost,
-		IsSubAgent:   isSubAgent,
-		MessageCount: meta.MsgCount,
-		FileSize:     info.Size(),
+		ID:             meta.SessionID,
+		Name:           name,
+		Slug:           meta.Slug,
+		AdapterID:      adapterID,
+		AdapterName:    adapterName,
+		AdapterIcon:    a.Icon(),
+		CreatedAt:      meta.FirstMsg,
+		UpdatedAt:      meta.LastMsg,
+		Duration:       meta.LastMsg.Sub(meta.FirstMsg),
+		IsActive:       time.Since(meta.LastMsg) < 5*time.Minute,
+		TotalTokens:    meta.TotalTokens,
+		EstCost:        meta.EstCost,
+		UnpricedModels: meta.UnpricedModels,
+		IsSubAgent:     isSubAgent,
+		MessageCount:   meta.MsgCount,
+		FileSize:       info.Size(),
 	}, nil
 }
 
@@ -847,6 +850,7 @@ func (a *Adapter) processMetadataLine(line []byte, meta *SessionMetadata, modelC
 			mt.in += usage.InputTokens
 			mt.out += usage.OutputTokens
 			mt.cache += usage.CacheReadInputTokens
+			mt.cacheWrite += usage.CacheCreationInputTokens
 			modelTokens[model] = mt
 		}
 	}
@@ -865,31 +869,25 @@ func (a *Adapter) finalizeMetadataCost(meta *SessionMetadata, modelCounts map[st
 		}
 	}
 
+	meta.UnpricedModels = nil
 	for model, mt := range modelTokens {
-		var inRate, outRate float64
-		switch {
-		case strings.Contains(model, "opus"):
-			inRate, outRate = 15.0, 75.0
-		case strings.Contains(model, "sonnet"):
-			inRate, outRate = 3.0, 15.0
-		case strings.Contains(model, "haiku"):
-			inRate, outRate = 0.25, 1.25
-		default:
-			inRate, outRate = 3.0, 15.0
-		}
-		regularIn := mt.in - mt.cache
-		if regularIn < 0 {
-			regularIn = 0
+		cost, ok := pricing.Cost(model, meta.LastMsg, pricing.Usage{
+			Input:      mt.in,
+			Output:     mt.out,
+			CacheRead:  mt.cache,
+			CacheWrite: mt.cacheWrite,
+		})
+		if !ok && mt.in+mt.out > 0 {
+			meta.UnpricedModels = append(meta.UnpricedModels, model)
 		}
-		meta.EstCost += float64(mt.cache)*inRate*0.1/1_000_000 +
-			float64(regularIn)*inRate/1_000_000 +
-			float64(mt.out)*outRate/1_000_000
+		meta.EstCost += cost
 	}
+	sort.Strings(meta.UnpricedModels)
 }
 
 // modelTokenEntry tracks per-model token accumulation for incremental cost calculation.
 type modelTokenEntry struct {
-	in, out, cache int
+	in, out, cache, cacheWrite int
 }
 
 type sessionMetaCacheEntry struct {
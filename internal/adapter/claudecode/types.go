@@ -74,8 +74,9 @@ type SessionMetadata struct {
 	FirstMsg         time.Time
 	LastMsg          time.Time
 	MsgCount         int
-	TotalTokens      int     // Sum of input + output tokens
-	EstCost          float64 // Estimated cost based on model usage
-	PrimaryModel     string  // Most used model in session
-	FirstUserMessage string  // Content of the first user message (for title)
+	TotalTokens      int      // Sum of input + output tokens
+	EstCost          float64  // Estimated cost based on model usage
+	PrimaryModel     string   // Most used model in session
+	UnpricedModels   []string // Models with no known price (excluded from EstCost)
+	FirstUserMessage string   // Content of the first user message (for title)
 }
@@ -14,6 +14,7 @@ import (
 
 	"github.com/marcus/sidecar/internal/adapter"
 	"github.com/marcus/sidecar/internal/adapter/cache"
+	"github.com/marcus/sidecar/internal/pricing"
 )
 
 const (
@@ -141,20 +142,23 @@ func (a *Adapter) Sessions(projectRoot string) ([]adapter.Session, error) {
 		if name == "" {
 			name = shortID(meta.SessionID)
 		}
+		estCost, unpriced := estimateCost(meta)
 		sessions = append(sessions, adapter.Session{
-			ID:           meta.SessionID,
-			Name:         name,
-			AdapterID:    adapterID,
-			AdapterName:  adapterName,
-			AdapterIcon:  a.Icon(),
-			CreatedAt:    meta.FirstMsg,
-			UpdatedAt:    meta.LastMsg,
-			Duration:     meta.LastMsg.Sub(meta.FirstMsg),
-			IsActive:     time.Since(meta.LastMsg) < 5*time.Minute,
-			TotalTokens:  meta.TotalTokens,
-			MessageCount: meta.MsgCount,
-			FileSize:     f.info.Size(),
-			Path:         f.path, // td-dca6fe: tiered watching needs session file path
+			ID:             meta.SessionID,
+			Name:           name,
+			AdapterID:      adapterID,
+			AdapterName:    adapterName,
+			AdapterIcon:    a.Icon(),
+			CreatedAt:      meta.FirstMsg,
+			UpdatedAt:      meta.LastMsg,
+			Duration:       meta.LastMsg.Sub(meta.FirstMsg),
+			IsActive:       time.Since(meta.LastMsg) < 5*time.Minute,
+			TotalTokens:    meta.TotalTokens,
+			EstCost:        estCost,
+			UnpricedModels: unpriced,
+			MessageCount:   meta.MsgCount,
+			FileSize:       f.info.Size(),
+			Path:           f.path, // td-dca6fe: tiered watching needs session file path
 		})
 
 		// Add to new index (will be swapped atomically after loop)
@@ -592,7 +596,7 @@ func (a *Adapter) Usage(sessionID string) (*adapter.UsageStats, error) {
 		usage := a.totalUsageCache[sessionID]
 		a.mu.RUnlock()
 		if usage != nil {
-			stats.TotalInputTokens = usage.InputTokens
+			stats.TotalInputTokens = uncachedInput(usage)
 			stats.TotalOutputTokens = usage.OutputTokens + usage.ReasoningOutputTokens
 			stats.TotalCacheRead = usage.CachedInputTokens
 		}
@@ -909,6 +913,7 @@ func (a *Adapter) parseSessionMetadataTailOnly(path string, headMeta *SessionMet
 		CWD:              headMeta.CWD,
 		FirstMsg:         headMeta.FirstMsg,
 		FirstUserMessage: headMeta.FirstUserMessage,
+		Model:            headMeta.Model, // tail turn_context records override
 	}
 
 	var sessionTimestamp time.Time
@@ -955,6 +960,12 @@ func (a *Adapter) processMetadataRecord(line []byte, meta *SessionMetadata, sess
 	}
 
 	switch record.Type {
+	case "turn_context":
+		var payload TurnContextPayload
+		if err := json.Unmarshal(record.Payload, &payload); err == nil && payload.Model != "" {
+			meta.Model = payload.Model
+		}
+
 	case "session_meta":
 		var payload SessionMetaPayload
 		if err := json.Unmarshal(record.Payload, &payload); err != nil {
@@ -1008,6 +1019,8 @@ func (a *Adapter) processMetadataRecord(line []byte, meta *SessionMetadata, sess
 		usage := event.Info.TotalTokenUsage
 		if usage == nil {
 			usage = event.Info.LastTokenUsage
+		} else {
+			meta.Usage = *usage
 		}
 		if usage != nil {
 			*totalTokens = usage.TotalTokens
@@ -1050,6 +1063,29 @@ func (a *Adapter) finalizeMetadata(meta *SessionMetadata, path string, sessionTi
 	meta.TotalTokens = totalTokens
 }
 
+// estimateCost prices a session's cumulative token usage with the pricing
+// table. Codex reports cached tokens as a subset of input tokens. Returns the
+// model in unpriced when it has usage but no known price.
+func estimateCost(meta *SessionMetadata) (cost float64, unpriced []string) {
+	u := meta.Usage
+	if u.InputTokens+u.OutputTokens == 0 {
+		return 0, nil
+	}
+	cost, ok := pricing.Cost(meta.Model, meta.LastMsg, pricing.Usage{
+		Input:     uncachedInput(&u),
+		Output:    u.OutputTokens,
+		CacheRead: u.CachedInputTokens,
+	})
+	if !ok {
+		model := meta.Model
+		if model == "" {
+			model = "unknown"
+		}
+		return 0, []string{model}
+	}
+	return cost, nil
+}
+
 func (a *Adapter) sessionFilePath(sessionID string) string {
 	a.mu.RLock()
 	if path, ok := a.sessionIndex[sessionID]; ok && path != "" {
@@ -1125,12 +1161,18 @@ func convertUsage(usage *TokenUsage) *adapter.TokenUsage {
 		return nil
 	}
 	return &adapter.TokenUsage{
-		InputTokens:  usage.InputTokens,
+		InputTokens:  uncachedInput(usage),
 		OutputTokens: usage.OutputTokens + usage.ReasoningOutputTokens,
 		CacheRead:    usage.CachedInputTokens,
 	}
 }
 
+// uncachedInput returns the input tokens not served from the cache. Codex
+// counts cached tokens as part of input_tokens.
+func uncachedInput(usage *TokenUsage) int {
+	return max(usage.InputTokens-usage.CachedInputTokens, 0)
+}
+
 // resolvedProjectPath holds a pre-resolved project path for efficient matching.
 type resolvedProjectPath struct {
 	abs string
@@ -62,7 +62,7 @@ func TestMessagesAndUsage(t *testing.T) {
 	if len(messages[1].ThinkingBlocks) != 2 {
 		t.Fatalf("thinking blocks = %d, want 2", len(messages[1].ThinkingBlocks))
 	}
-	if messages[1].InputTokens != 10 || messages[1].OutputTokens != 6 || messages[1].CacheRead != 2 {
+	if messages[1].InputTokens != 8 || messages[1].OutputTokens != 6 || messages[1].CacheRead != 2 {
 		t.Fatalf("token usage mismatch: %+v", messages[1].TokenUsage)
 	}
 	if messages[2].Role != "assistant" || messages[2].Content != "tool calls" {
@@ -79,7 +79,7 @@ func TestMessagesAndUsage(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Usage error: %v", err)
 	}
-	if usage.TotalInputTokens != 10 || usage.TotalOutputTokens != 6 || usage.TotalCacheRead != 2 {
+	if usage.TotalInputTokens != 8 || usage.TotalOutputTokens != 6 || usage.TotalCacheRead != 2 {
 		t.Fatalf("usage mismatch: %+v", usage)
 	}
 	if usage.MessageCount != 4 {
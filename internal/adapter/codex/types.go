@@ -102,5 +102,7 @@ type SessionMetadata struct {
 	LastMsg          time.Time
 	MsgCount         int
 	TotalTokens      int
-	FirstUserMessage string // Content of the first user message (for title)
+	FirstUserMessage string     // Content of the first user message (for title)
+	Model            string     // Most recent model from turn_context records
+	Usage            TokenUsage // Cumulative usage from the last token_count event
 }
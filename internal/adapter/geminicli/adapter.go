@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/marcus/sidecar/internal/adapter"
+	"github.com/marcus/sidecar/internal/pricing"
 )
 
 const (
@@ -137,22 +138,23 @@ func (a *Adapter) Sessions(projectRoot string) ([]adapter.Session, error) {
 		}
 
 		sessions = append(sessions, adapter.Session{
-			ID:           meta.SessionID,
-			Name:         name,
-			Slug:         shortID(meta.SessionID),
-			AdapterID:    adapterID,
-			AdapterName:  adapterName,
-			AdapterIcon:  a.Icon(),
-			CreatedAt:    meta.StartTime,
-			UpdatedAt:    meta.LastUpdated,
-			Duration:     meta.LastUpdated.Sub(meta.StartTime),
-			IsActive:     time.Since(meta.LastUpdated) < 5*time.Minute,
-			TotalTokens:  meta.TotalTokens,
-			EstCost:      meta.EstCost,
-			IsSubAgent:   false,
-			MessageCount: meta.MsgCount,
-			FileSize:     info.Size(),
-			Path:         path, // td-dca6fe: tiered watching needs session file path
+			ID:             meta.SessionID,
+			Name:           name,
+			Slug:           shortID(meta.SessionID),
+			AdapterID:      adapterID,
+			AdapterName:    adapterName,
+			AdapterIcon:    a.Icon(),
+			CreatedAt:      meta.StartTime,
+			UpdatedAt:      meta.LastUpdated,
+			Duration:       meta.LastUpdated.Sub(meta.StartTime),
+			IsActive:       time.Since(meta.LastUpdated) < 5*time.Minute,
+			TotalTokens:    meta.TotalTokens,
+			EstCost:        meta.EstCost,
+			UnpricedModels: meta.UnpricedModels,
+			IsSubAgent:     false,
+			MessageCount:   meta.MsgCount,
+			FileSize:       info.Size(),
+			Path:           path, // td-dca6fe: tiered watching needs session file path
 		})
 	}
 
@@ -202,7 +204,7 @@ func (a *Adapter) Messages(sessionID string) ([]adapter.Message, error) {
 		// Parse tokens
 		if msg.Tokens != nil {
 			m.TokenUsage = adapter.TokenUsage{
-				InputTokens:  msg.Tokens.Input + msg.Tokens.Cached,
+				InputTokens:  msg.Tokens.Input,
 				OutputTokens: msg.Tokens.Output,
 				CacheRead:    msg.Tokens.Cached,
 			}
@@ -439,7 +441,7 @@ func (a *Adapter) parseSessionMetadata(path string) (*SessionMetadata, error) {
 		LastUpdated: session.LastUpdated,
 	}
 
-	modelTokens := make(map[string]struct{ in, out int })
+	modelTokens := make(map[string]struct{ in, out, cached int })
 
 	for _, msg := range session.Messages {
 		// Skip info messages
@@ -459,7 +461,8 @@ func (a *Adapter) parseSessionMetadata(path string) (*SessionMetadata, error) {
 			if msg.Model != "" {
 				mt := modelTokens[msg.Model]
 				mt.in += msg.Tokens.Input
-				mt.out += msg.Tokens.Output
+				mt.out += msg.Tokens.Output + msg.Tokens.Thoughts
+				mt.cached += msg.Tokens.Cached
 				modelTokens[msg.Model] = mt
 			}
 		}
@@ -474,19 +477,17 @@ func (a *Adapter) parseSessionMetadata(path string) (*SessionMetadata, error) {
 			meta.PrimaryModel = model
 		}
 
-		// Gemini pricing (approximate)
-		var inRate, outRate float64
-		switch {
-		case strings.Contains(model, "pro"):
-			inRate, outRate = 1.25, 5.0 // Gemini Pro
-		case strings.Contains(model, "flash"):
-			inRate, outRate = 0.075, 0.30 // Gemini Flash
-		default:
-			inRate, outRate = 0.50, 1.50 // Default
+		cost, ok := pricing.Cost(model, meta.LastUpdated, pricing.Usage{
+			Input:     mt.in,
+			Output:    mt.out,
+			CacheRead: mt.cached,
+		})
+		if !ok && mt.in+mt.out > 0 {
+			meta.UnpricedModels = append(meta.UnpricedModels, model)
 		}
-		meta.EstCost += float64(mt.in)*inRate/1_000_000 +
-			float64(mt.out)*outRate/1_000_000
+		meta.EstCost += cost
 	}
+	sort.Strings(meta.UnpricedModels)
 
 	return meta, nil
 }
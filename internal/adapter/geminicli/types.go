@@ -64,5 +64,6 @@ type SessionMetadata struct {
 	TotalTokens      int
 	EstCost          float64
 	PrimaryModel     string
-	FirstUserMessage string // Content of the first user message (for title)
+	UnpricedModels   []string // Models with no known price (excluded from EstCost)
+	FirstUserMessage string   // Content of the first user message (for title)
 }
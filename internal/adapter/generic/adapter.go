@@ -0,0 +1,293 @@
+package generic
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/marcus/sidecar/internal/adapter"
+	"github.com/marcus/sidecar/internal/adapter/cache"
+	"github.com/marcus/sidecar/internal/config"
+	"github.com/marcus/sidecar/internal/pricing"
+)
+
+const (
+	defaultIcon         = "◆"
+	defaultTimeout      = 10 * time.Second
+	fileCacheMaxEntries = 256
+	msgCacheMaxEntries  = 128
+)
+
+// Adapter implements adapter.Adapter for one config-declared agent.
+type Adapter struct {
+	cfg config.CustomAdapterConfig
+
+	mu           sync.RWMutex
+	project      string                // project root from the last Sessions call
+	sessionIndex map[string]sessionRef // session ID -> location
+	pathIndex    map[string]string     // file path -> session ID (files mode)
+
+	fileCache *cache.Cache[*parsedFile]
+	msgCache  *cache.Cache[[]adapter.Message]
+}
+
+// sessionRef locates a session and the project it was listed for. Sessions
+// is called once per worktree, so each project keeps its own entries.
+type sessionRef struct {
+	projectRoot string
+	path        string    // session file (files mode)
+	updated     time.Time // UpdatedAt (command mode)
+}
+
+// New creates an adapter from a custom adapter declaration. The declaration
+// should be checked with Validate first.
+func New(cfg config.CustomAdapterConfig) *Adapter {
+	return &Adapter{
+		cfg:          cfg,
+		sessionIndex: make(map[string]sessionRef),
+		pathIndex:    make(map[string]string),
+		fileCache:    cache.New[*parsedFile](fileCacheMaxEntries),
+		msgCache:     cache.New[[]adapter.Message](msgCacheMaxEntries),
+	}
+}
+
+// ID returns the configured adapter identifier.
+func (a *Adapter) ID() string { return a.cfg.ID }
+
+// Name returns the configured display name, defaulting to the ID.
+func (a *Adapter) Name() string {
+	if a.cfg.Name != "" {
+		return a.cfg.Name
+	}
+	return a.cfg.ID
+}
+
+// Icon returns the configured badge icon.
+func (a *Adapter) Icon() string {
+	if a.cfg.Icon != "" {
+		return a.cfg.Icon
+	}
+	return defaultIcon
+}
+
+// Detect reports whether the agent has any sessions for the project.
+func (a *Adapter) Detect(projectRoot string) (bool, error) {
+	sessions, err := a.Sessions(projectRoot)
+	if err != nil {
+		return false, nil
+	}
+	return len(sessions) > 0, nil
+}
+
+// Capabilities returns the supported features. Command adapters can only
+// watch when a watch glob is configured.
+func (a *Adapter) Capabilities() adapter.CapabilitySet {
+	return adapter.CapabilitySet{
+		adapter.CapSessions: true,
+		adapter.CapMessages: true,
+		adapter.CapUsage:    true,
+		adapter.CapWatch:    a.cfg.Files != nil || a.cfg.Command.Watch != "",
+	}
+}
+
+// Sessions returns the agent's sessions for the project, newest first.
+func (a *Adapter) Sessions(projectRoot string) ([]adapter.Session, error) {
+	a.mu.Lock()
+	a.project = projectRoot
+	a.mu.Unlock()
+
+	var sessions []adapter.Session
+	var err error
+	if a.cfg.Files != nil {
+		sessions, err = a.fileSessions(projectRoot)
+	} else {
+		sessions, err = a.commandSessions(projectRoot)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].UpdatedAt.After(sessions[j].UpdatedAt)
+	})
+	return sessions, nil
+}
+
+// Messages returns all messages for a session.
+func (a *Adapter) Messages(sessionID string) ([]adapter.Message, error) {
+	if a.cfg.Files != nil {
+		return a.fileMessages(sessionID)
+	}
+	return a.commandMessages(sessionID)
+}
+
+// Usage returns token usage totals for a session.
+func (a *Adapter) Usage(sessionID string) (*adapter.UsageStats, error) {
+	messages, err := a.Messages(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &adapter.UsageStats{}
+	for _, m := range messages {
+		stats.TotalInputTokens += m.InputTokens
+		stats.TotalOutputTokens += m.OutputTokens
+		stats.TotalCacheRead += m.CacheRead
+		stats.TotalCacheWrite += m.CacheWrite
+		stats.MessageCount++
+	}
+	return stats, nil
+}
+
+// Watch returns a channel that emits events when session data changes.
+func (a *Adapter) Watch(projectRoot string) (<-chan adapter.Event, io.Closer, error) {
+	if a.cfg.Files != nil {
+		return a.watchFiles(projectRoot)
+	}
+	return a.watchCommand(projectRoot)
+}
+
+// WatchScope returns Project when the session glob depends on the project,
+// Global otherwise.
+func (a *Adapter) WatchScope() adapter.WatchScope {
+	if a.cfg.Files != nil && strings.Contains(a.cfg.Files.Glob, "{project") {
+		return adapter.WatchScopeProject
+	}
+	return adapter.WatchScopeGlobal
+}
+
+// setProjectSessions replaces the index entries of projectRoot's sessions,
+// leaving other projects' entries alone.
+func (a *Adapter) setProjectSessions(projectRoot string, refs map[string]sessionRef) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for id, ref := range a.sessionIndex {
+		if ref.projectRoot != projectRoot {
+			continue
+		}
+		delete(a.sessionIndex, id)
+		if ref.path != "" && a.pathIndex[ref.path] == id {
+			delete(a.pathIndex, ref.path)
+		}
+	}
+	for id, ref := range refs {
+		a.sessionIndex[id] = ref
+		if ref.path != "" {
+			a.pathIndex[ref.path] = id
+		}
+	}
+}
+
+// sessionRef returns the index entry of a session listed by Sessions.
+func (a *Adapter) sessionRef(sessionID string) (sessionRef, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	ref, ok := a.sessionIndex[sessionID]
+	return ref, ok
+}
+
+// currentProject returns the project root from the last Sessions call.
+func (a *Adapter) currentProject() string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.project
+}
+
+// newSession fills in the adapter-level fields of a session.
+func (a *Adapter) newSession(id, name string, created, updated time.Time) adapter.Session {
+	if name == "" {
+		name = shortID(id)
+	}
+	return adapter.Session{
+		ID:          id,
+		Name:        name,
+		AdapterID:   a.ID(),
+		AdapterName: a.Name(),
+		AdapterIcon: a.Icon(),
+		CreatedAt:   created,
+		UpdatedAt:   updated,
+		Duration:    updated.Sub(created),
+		IsActive:    time.Since(updated) < 5*time.Minute,
+	}
+}
+
+// applyMessageStats sets message count, tokens and estimated cost on a
+// session from its messages.
+func applyMessageStats(s *adapter.Session, messages []adapter.Message) {
+	for _, m := range messages {
+		if m.Role == "user" || m.Role == "assistant" {
+			s.MessageCount++
+		}
+		s.TotalTokens += m.InputTokens + m.OutputTokens
+	}
+	s.EstCost, s.UnpricedModels = estimateCost(messages)
+}
+
+// estimateCost prices each message's usage with the pricing table. Messages
+// without a model are priced with the last model seen. Returns the models
+// that have usage but no known price.
+func estimateCost(messages []adapter.Message) (cost float64, unpriced []string) {
+	var model string
+	seen := make(map[string]bool)
+	for _, m := range messages {
+		if m.Model != "" {
+			model = m.Model
+		}
+		if m.InputTokens+m.OutputTokens+m.CacheRead+m.CacheWrite == 0 {
+			continue
+		}
+		c, ok := pricing.Cost(model, m.Timestamp, pricing.Usage{
+			Input:      m.InputTokens,
+			Output:     m.OutputTokens,
+			CacheRead:  m.CacheRead,
+			CacheWrite: m.CacheWrite,
+		})
+		if !ok {
+			name := model
+			if name == "" {
+				name = "unknown"
+			}
+			if !seen[name] {
+				seen[name] = true
+				unpriced = append(unpriced, name)
+			}
+			continue
+		}
+		cost += c
+	}
+	sort.Strings(unpriced)
+	return cost, unpriced
+}
+
+// sessionTitle returns the first user message, truncated for display.
+func sessionTitle(messages []adapter.Message) string {
+	for _, m := range messages {
+		if m.Role == "user" && strings.TrimSpace(m.Content) != "" {
+			return truncateTitle(m.Content, 50)
+		}
+	}
+	return ""
+}
+
+// truncateTitle truncates text to maxLen, adding "..." if truncated.
+// It also replaces newlines with spaces for display.
+func truncateTitle(s string, maxLen int) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	s = strings.ReplaceAll(s, "\r", "")
+	s = strings.TrimSpace(s)
+
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen-3] + "..."
+}
+
+// shortID returns the first 8 characters of an ID for display.
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
@@ -0,0 +1,325 @@
+package generic
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcus/sidecar/internal/adapter"
+	"github.com/marcus/sidecar/internal/config"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func jsonlAdapter(glob string) *Adapter {
+	return New(config.CustomAdapterConfig{
+		ID:   "inhouse",
+		Name: "In-house",
+		Files: &config.CustomAdapterFiles{
+			Glob: glob,
+			Fields: config.CustomAdapterFields{
+				SessionID:    "session",
+				Project:      "cwd",
+				Role:         "message.role",
+				Content:      "message.content",
+				Timestamp:    "ts",
+				Model:        "message.model",
+				InputTokens:  "message.usage.input",
+				OutputTokens: "message.usage.output",
+			},
+		},
+	})
+}
+
+func TestFileSessions_JSONL(t *testing.T) {
+	dir := t.TempDir()
+	project := filepath.Join(dir, "project")
+	if err := os.MkdirAll(project, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, filepath.Join(dir, "sessions", "a.jsonl"), strings.Join([]string{
+		`{"type":"meta","session":"sess-a","cwd":"` + project + `/sub"}`,
+		`{"ts":"2026-03-01T10:00:00Z","message":{"role":"user","content":"Add retries"}}`,
+		`not json`,
+		`{"ts":"2026-03-01T10:01:00Z","message":{"role":"assistant","model":"claude-sonnet-4-5","content":[{"type":"text","text":"Done."},{"type":"tool_use","name":"edit"}],"usage":{"input":1000,"output":200}}}`,
+	}, "\n"))
+	writeFile(t, filepath.Join(dir, "sessions", "b.jsonl"),
+		`{"session":"sess-b","cwd":"/elsewhere","ts":1772359200,"message":{"role":"user","content":"hi"}}`)
+
+	a := jsonlAdapter(filepath.Join(dir, "sessions", "*.jsonl"))
+	sessions, err := a.Sessions(project)
+	if err != nil {
+		t.Fatalf("Sessions: %v", err)
+	}
+	if len(sessions) != 1 {
+		t.Fatalf("expected 1 session for project, got %d", len(sessions))
+	}
+	s := sessions[0]
+	if s.ID != "sess-a" || s.Name != "Add retries" || s.AdapterID != "inhouse" {
+		t.Errorf("unexpected session: %+v", s)
+	}
+	if s.MessageCount != 2 || s.TotalTokens != 1200 {
+		t.Errorf("got %d messages / %d tokens, want 2 / 1200", s.MessageCount, s.TotalTokens)
+	}
+	if s.EstCost <= 0 || len(s.UnpricedModels) != 0 {
+		t.Errorf("expected priced session, got cost %v unpriced %v", s.EstCost, s.UnpricedModels)
+	}
+	if s.Duration != time.Minute || s.Path == "" {
+		t.Errorf("unexpected duration %v / path %q", s.Duration, s.Path)
+	}
+
+	msgs, err := a.Messages("sess-a")
+	if err != nil {
+		t.Fatalf("Messages: %v", err)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(msgs))
+	}
+	if msgs[1].Content != "Done." || msgs[1].Model != "claude-sonnet-4-5" || msgs[1].InputTokens != 1000 {
+		t.Errorf("unexpected assistant message: %+v", msgs[1])
+	}
+
+	matches, err := a.SearchMessages("sess-a", "retries", adapter.DefaultSearchOptions())
+	if err != nil || len(matches) != 1 {
+		t.Errorf("expected 1 search match, got %d (err %v)", len(matches), err)
+	}
+
+	usage, err := a.Usage("sess-a")
+	if err != nil || usage.TotalOutputTokens != 200 {
+		t.Errorf("unexpected usage %+v (err %v)", usage, err)
+	}
+}
+
+func TestFileSessions_JSONDocument(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "proj", "chat.json"), `{
+		"title": "Refactor parser",
+		"history": [
+			{"from": "USER", "text": "refactor it", "at": 1772359200000},
+			{"from": "assistant", "text": "ok", "at": 1772359260000}
+		]
+	}`)
+
+	a := New(config.CustomAdapterConfig{
+		ID: "doc",
+		Files: &config.CustomAdapterFiles{
+			Glob:     filepath.Join(dir, "{projectName}", "*.json"),
+			Format:   "json",
+			Messages: "history",
+			Fields: config.CustomAdapterFields{
+				Title:     "title",
+				Role:      "from",
+				Content:   "text",
+				Timestamp: "at",
+			},
+		},
+	})
+	if a.WatchScope() != adapter.WatchScopeProject {
+		t.Error("expected project watch scope for {projectName} glob")
+	}
+
+	sessions, err := a.Sessions(filepath.Join(dir, "proj"))
+	if err != nil || len(sessions) != 1 {
+		t.Fatalf("expected 1 session, got %d (err %v)", len(sessions), err)
+	}
+	s := sessions[0]
+	if s.ID != "chat" || s.Name != "Refactor parser" || s.Duration != time.Minute {
+		t.Errorf("unexpected session: %+v", s)
+	}
+	msgs, _ := a.Messages("chat")
+	if len(msgs) != 2 || msgs[0].Role != "user" {
+		t.Errorf("unexpected messages: %+v", msgs)
+	}
+}
+
+func TestCommandAdapter(t *testing.T) {
+	dir := t.TempDir()
+	sessionsOut := filepath.Join(dir, "sessions.json")
+	messagesOut := filepath.Join(dir, "sess-1.jsonl")
+	writeFile(t, sessionsOut, `[{"id":"sess-1","name":"Fix CI","updatedAt":"2026-03-01T10:00:00Z","model":"mystery-model","inputTokens":10,"outputTokens":5,"messageCount":2}]`)
+	writeFile(t, messagesOut, strings.Join([]string{
+		`{"role":"user","content":"fix ci","timestamp":"2026-03-01T09:59:00Z"}`,
+		`{"role":"assistant","content":"fixed","thinking":"check logs","toolUses":[{"name":"shell","input":"make test","output":"ok"}]}`,
+	}, "\n"))
+
+	a := New(config.CustomAdapterConfig{
+		ID: "cli",
+		Command: &config.CustomAdapterCommand{
+			Sessions: []string{"cat", sessionsOut},
+			Messages: []string{"cat", filepath.Join(dir, "{session}.jsonl")},
+		},
+	})
+	if a.Capabilities()[adapter.CapWatch] {
+		t.Error("expected no watch capability without a watch glob")
+	}
+
+	sessions, err := a.Sessions(dir)
+	if err != nil || len(sessions) != 1 {
+		t.Fatalf("expected 1 session, got %d (err %v)", len(sessions), err)
+	}
+	s := sessions[0]
+	if s.Name != "Fix CI" || s.TotalTokens != 15 || s.MessageCount != 2 {
+		t.Errorf("unexpected session: %+v", s)
+	}
+	if len(s.UnpricedModels) != 1 || s.UnpricedModels[0] != "mystery-model" {
+		t.Errorf("expected unpriced model, got %v", s.UnpricedModels)
+	}
+
+	msgs, err := a.Messages("sess-1")
+	if err != nil || len(msgs) != 2 {
+		t.Fatalf("expected 2 messages, got %d (err %v)", len(msgs), err)
+	}
+	if len(msgs[1].ToolUses) != 1 || msgs[1].ToolUses[0].Output != "ok" || len(msgs[1].ThinkingBlocks) != 1 {
+		t.Errorf("unexpected assistant message: %+v", msgs[1])
+	}
+}
+
+func TestSessions_KeepsEachProjectsIndex(t *testing.T) {
+	dir := t.TempDir()
+	p1, p2 := filepath.Join(dir, "p1"), filepath.Join(dir, "p2")
+	for i, p := range []string{p1, p2} {
+		id := []string{"sess-1", "sess-2"}[i]
+		writeFile(t, filepath.Join(dir, "sessions", id+".jsonl"),
+			`{"session":"`+id+`","cwd":"`+p+`","ts":1772359200,"message":{"role":"user","content":"in `+filepath.Base(p)+`"}}`)
+		writeFile(t, filepath.Join(p, "sessions.json"), `[{"id":"`+id+`","updatedAt":"2026-03-01T10:00:00Z"}]`)
+		writeFile(t, filepath.Join(p, id+".jsonl"), `{"role":"user","content":"from `+filepath.Base(p)+`"}`)
+	}
+
+	// The conversations plugin lists every worktree before loading messages
+	files := jsonlAdapter(filepath.Join(dir, "sessions", "*.jsonl"))
+	cmd := New(config.CustomAdapterConfig{
+		ID: "cli",
+		Command: &config.CustomAdapterCommand{
+			Sessions: []string{"cat", "{project}/sessions.json"},
+			Messages: []string{"cat", "{project}/{session}.jsonl"},
+		},
+	})
+	for _, a := range []*Adapter{files, cmd} {
+		for _, p := range []string{p1, p2} {
+			if sessions, err := a.Sessions(p); err != nil || len(sessions) != 1 {
+				t.Fatalf("%s: Sessions(%s) = %d sessions (err %v)", a.ID(), p, len(sessions), err)
+			}
+		}
+	}
+
+	for _, tt := range []struct {
+		a        *Adapter
+		id, want string
+	}{
+		{files, "sess-1", "in p1"},
+		{files, "sess-2", "in p2"},
+		{cmd, "sess-1", "from p1"},
+		{cmd, "sess-2", "from p2"},
+	} {
+		msgs, err := tt.a.Messages(tt.id)
+		if err != nil || len(msgs) != 1 || msgs[0].Content != tt.want {
+			t.Errorf("%s: Messages(%s) = %+v (err %v), want %q", tt.a.ID(), tt.id, msgs, err, tt.want)
+		}
+	}
+
+	// Refreshing one project drops only its stale sessions
+	if err := os.Remove(filepath.Join(dir, "sessions", "sess-1.jsonl")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := files.Sessions(p1); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := files.sessionRef("sess-1"); ok {
+		t.Error("removed session is still indexed")
+	}
+	if id := files.pathIndex[filepath.Join(dir, "sessions", "sess-2.jsonl")]; id != "sess-2" {
+		t.Errorf("p2 path index lost: %q", id)
+	}
+	if _, ok := files.sessionRef("sess-2"); !ok {
+		t.Error("refreshing p1 dropped p2's session")
+	}
+}
+
+func TestCommandAdapter_Failure(t *testing.T) {
+	a := New(config.CustomAdapterConfig{
+		ID: "cli",
+		Command: &config.CustomAdapterCommand{
+			Sessions: []string{"sh", "-c", "echo boom >&2; exit 3"},
+			Messages: []string{"true"},
+		},
+	})
+	_, err := a.Sessions(t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("expected error with stderr, got %v", err)
+	}
+	if ok, _ := a.Detect(t.TempDir()); ok {
+		t.Error("expected Detect to be false when the command fails")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	files := &config.CustomAdapterFiles{Glob: "*.jsonl", Fields: config.CustomAdapterFields{Role: "r", Content: "c"}}
+	cmd := &config.CustomAdapterCommand{Sessions: []string{"a"}, Messages: []string{"b"}}
+	tests := []struct {
+		name string
+		cfg  config.CustomAdapterConfig
+		ok   bool
+	}{
+		{"files", config.CustomAdapterConfig{ID: "x", Files: files}, true},
+		{"command", config.CustomAdapterConfig{ID: "x", Command: cmd}, true},
+		{"missing id", config.CustomAdapterConfig{Files: files}, false},
+		{"both", config.CustomAdapterConfig{ID: "x", Files: files, Command: cmd}, false},
+		{"neither", config.CustomAdapterConfig{ID: "x"}, false},
+		{"bad format", config.CustomAdapterConfig{ID: "x", Files: &config.CustomAdapterFiles{Glob: "*", Format: "xml", Fields: files.Fields}}, false},
+		{"no role", config.CustomAdapterConfig{ID: "x", Files: &config.CustomAdapterFiles{Glob: "*"}}, false},
+	}
+	for _, tt := range tests {
+		if err := Validate(tt.cfg); (err == nil) != tt.ok {
+			t.Errorf("%s: Validate() = %v, want ok=%v", tt.name, err, tt.ok)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	doc := map[string]any{
+		"message": map[string]any{
+			"content": []any{
+				map[string]any{"type": "text", "text": "a"},
+				map[string]any{"type": "thinking", "text": "hidden"},
+				"b",
+			},
+			"usage": map[string]any{"in": "42"},
+		},
+		"ts": "2026-03-01 10:00:00",
+	}
+	if got := lookupString(doc, "$.message.content"); got != "a\nb" {
+		t.Errorf("content = %q", got)
+	}
+	if got := lookupString(doc, "message.content.2"); got != "b" {
+		t.Errorf("indexed content = %q", got)
+	}
+	if got := lookupInt(doc, "message.usage.in"); got != 42 {
+		t.Errorf("usage = %d", got)
+	}
+	if got := lookupTime(doc, "ts"); got.IsZero() {
+		t.Error("expected timestamp to parse")
+	}
+	if _, ok := lookup(doc, "message.missing"); ok {
+		t.Error("expected missing path to fail")
+	}
+}
+
+func TestGlobRoot(t *testing.T) {
+	if got := globRoot("/home/u/.agent/*/sessions/*.jsonl"); got != "/home/u/.agent" {
+		t.Errorf("globRoot = %q", got)
+	}
+	if got := globRoot("/data/*.json"); got != "/data" {
+		t.Errorf("globRoot = %q", got)
+	}
+}
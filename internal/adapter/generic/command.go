@@ -0,0 +1,209 @@
+package generic
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/marcus/sidecar/internal/adapter"
+)
+
+// commandSession is one session as printed by the sessions command.
+type commandSession struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+	Model        string    `json:"model"`
+	MessageCount int       `json:"messageCount"`
+	InputTokens  int       `json:"inputTokens"`
+	OutputTokens int       `json:"outputTokens"`
+	TotalTokens  int       `json:"totalTokens"`
+	Path         string    `json:"path"` // optional session file, enables tiered watching
+}
+
+// commandMessage is one message as printed by the messages command.
+type commandMessage struct {
+	ID           string           `json:"id"`
+	Role         string           `json:"role"`
+	Content      string           `json:"content"`
+	Timestamp    time.Time        `json:"timestamp"`
+	Model        string           `json:"model"`
+	InputTokens  int              `json:"inputTokens"`
+	OutputTokens int              `json:"outputTokens"`
+	CacheRead    int              `json:"cacheRead"`
+	CacheWrite   int              `json:"cacheWrite"`
+	Thinking     string           `json:"thinking"`
+	ToolUses     []commandToolUse `json:"toolUses"`
+}
+
+// commandToolUse is a tool call within a commandMessage.
+type commandToolUse struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Input  string `json:"input"`
+	Output string `json:"output"`
+}
+
+// commandSessions runs the sessions command for the project.
+func (a *Adapter) commandSessions(projectRoot string) ([]adapter.Session, error) {
+	out, err := a.run(a.cfg.Command.Sessions, projectRoot, "")
+	if err != nil {
+		return nil, err
+	}
+	list, err := decodeList[commandSession](out)
+	if err != nil {
+		return nil, fmt.Errorf("%s: sessions command: %w", a.ID(), err)
+	}
+
+	sessions := make([]adapter.Session, 0, len(list))
+	refs := make(map[string]sessionRef, len(list))
+	for _, cs := range list {
+		if cs.ID == "" {
+			continue
+		}
+		if cs.CreatedAt.IsZero() {
+			cs.CreatedAt = cs.UpdatedAt
+		}
+		s := a.newSession(cs.ID, truncateTitle(cs.Name, 50), cs.CreatedAt, cs.UpdatedAt)
+		s.MessageCount = cs.MessageCount
+		s.Path = cs.Path
+		s.TotalTokens = cs.TotalTokens
+		if s.TotalTokens == 0 {
+			s.TotalTokens = cs.InputTokens + cs.OutputTokens
+		}
+		s.EstCost, s.UnpricedModels = estimateCost([]adapter.Message{{
+			Model:      cs.Model,
+			Timestamp:  cs.UpdatedAt,
+			TokenUsage: adapter.TokenUsage{InputTokens: cs.InputTokens, OutputTokens: cs.OutputTokens},
+		}})
+		sessions = append(sessions, s)
+		refs[cs.ID] = sessionRef{projectRoot: projectRoot, updated: cs.UpdatedAt}
+	}
+
+	a.setProjectSessions(projectRoot, refs)
+
+	return sessions, nil
+}
+
+// commandMessages runs the messages command for a session, in the project
+// that listed it. Results are cached until the session's UpdatedAt changes.
+func (a *Adapter) commandMessages(sessionID string) ([]adapter.Message, error) {
+	ref, known := a.sessionRef(sessionID)
+	projectRoot := ref.projectRoot
+	if known {
+		if msgs, ok := a.msgCache.Get(sessionID, 0, ref.updated); ok {
+			return msgs, nil
+		}
+	} else {
+		projectRoot = a.currentProject()
+	}
+
+	out, err := a.run(a.cfg.Command.Messages, projectRoot, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	list, err := decodeList[commandMessage](out)
+	if err != nil {
+		return nil, fmt.Errorf("%s: messages command: %w", a.ID(), err)
+	}
+
+	messages := make([]adapter.Message, 0, len(list))
+	for i, cm := range list {
+		msg := adapter.Message{
+			ID:        cm.ID,
+			Role:      strings.ToLower(cm.Role),
+			Content:   cm.Content,
+			Timestamp: cm.Timestamp,
+			Model:     cm.Model,
+			TokenUsage: adapter.TokenUsage{
+				InputTokens:  cm.InputTokens,
+				OutputTokens: cm.OutputTokens,
+				CacheRead:    cm.CacheRead,
+				CacheWrite:   cm.CacheWrite,
+			},
+		}
+		if msg.ID == "" {
+			msg.ID = fmt.Sprintf("msg-%d", i)
+		}
+		if cm.Thinking != "" {
+			msg.ThinkingBlocks = []adapter.ThinkingBlock{{Content: cm.Thinking, TokenCount: len(cm.Thinking) / 4}}
+		}
+		for _, tu := range cm.ToolUses {
+			msg.ToolUses = append(msg.ToolUses, adapter.ToolUse(tu))
+		}
+		messages = append(messages, msg)
+	}
+
+	if known {
+		a.msgCache.Set(sessionID, messages, 0, ref.updated, 0)
+	}
+	return messages, nil
+}
+
+// run executes a command with {project} and {session} substituted in its
+// arguments, returning stdout.
+func (a *Adapter) run(argv []string, projectRoot, sessionID string) ([]byte, error) {
+	args := make([]string, len(argv))
+	for i, arg := range argv {
+		arg = strings.ReplaceAll(arg, "{project}", projectRoot)
+		args[i] = strings.ReplaceAll(arg, "{session}", sessionID)
+	}
+
+	timeout := a.cfg.Command.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = projectRoot
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %s: %w: %s", a.ID(), args[0], err, msg)
+		}
+		return nil, fmt.Errorf("%s: %s: %w", a.ID(), args[0], err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// decodeList decodes command output that is either a JSON array or JSON
+// Lines (one object per line).
+func decodeList[T any](data []byte) ([]T, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+	if data[0] == '[' {
+		var list []T
+		if err := json.Unmarshal(data, &list); err != nil {
+			return nil, err
+		}
+		return list, nil
+	}
+
+	var list []T
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var item T
+		if err := json.Unmarshal(line, &item); err != nil {
+			return nil, err
+		}
+		list = append(list, item)
+	}
+	return list, scanner.Err()
+}
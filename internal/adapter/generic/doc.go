@@ -0,0 +1,5 @@
+// Package generic provides config-driven adapters for agents that have no
+// built-in adapter. Each entry in the "adapters.custom" section of
+// config.json either maps the fields of JSON/JSONL session files or runs
+// external commands that print sessions and messages as JSON.
+package generic
@@ -0,0 +1,275 @@
+package generic
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/marcus/sidecar/internal/adapter"
+	"github.com/marcus/sidecar/internal/adapter/cache"
+	"github.com/marcus/sidecar/internal/config"
+)
+
+// Session file formats.
+const (
+	formatJSONL = "jsonl"
+	formatJSON  = "json"
+)
+
+// parsedFile holds a session file's messages and session-level fields.
+type parsedFile struct {
+	sessionID string
+	title     string
+	project   string
+	messages  []adapter.Message
+}
+
+// sessionGlob expands ~ and the project placeholders in the session glob.
+func (a *Adapter) sessionGlob(projectRoot string) string {
+	glob := a.cfg.Files.Glob
+	if strings.Contains(glob, "{project") {
+		root := resolveProject(projectRoot)
+		glob = strings.ReplaceAll(glob, "{projectName}", filepath.Base(root))
+		glob = strings.ReplaceAll(glob, "{project}", root)
+	}
+	return config.ExpandPath(glob)
+}
+
+// fileSessions parses every matching session file and keeps those that
+// belong to the project.
+func (a *Adapter) fileSessions(projectRoot string) ([]adapter.Session, error) {
+	paths, err := filepath.Glob(a.sessionGlob(projectRoot))
+	if err != nil {
+		return nil, err
+	}
+
+	// Only filter by the recorded project when the glob doesn't already
+	// select the project's files.
+	filterProject := a.cfg.Files.Fields.Project != "" && !strings.Contains(a.cfg.Files.Glob, "{project")
+	root := resolveProject(projectRoot)
+
+	sessions := make([]adapter.Session, 0, len(paths))
+	refs := make(map[string]sessionRef, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		pf, err := a.parseFileCached(path, info)
+		if err != nil || len(pf.messages) == 0 {
+			continue
+		}
+		if filterProject && !projectMatches(pf.project, root) {
+			continue
+		}
+
+		created, updated := messageSpan(pf.messages)
+		if created.IsZero() {
+			created, updated = info.ModTime(), info.ModTime()
+		}
+		name := pf.title
+		if name == "" {
+			name = sessionTitle(pf.messages)
+		}
+		s := a.newSession(pf.sessionID, name, created, updated)
+		s.FileSize = info.Size()
+		s.Path = path // tiered watching needs the session file path
+		applyMessageStats(&s, pf.messages)
+		sessions = append(sessions, s)
+
+		refs[pf.sessionID] = sessionRef{projectRoot: projectRoot, path: path}
+	}
+
+	a.setProjectSessions(projectRoot, refs)
+
+	return sessions, nil
+}
+
+// fileMessages returns the messages of a session file.
+func (a *Adapter) fileMessages(sessionID string) ([]adapter.Message, error) {
+	ref, ok := a.sessionRef(sessionID)
+	if !ok {
+		// Index is built by Sessions; rebuild it for direct lookups.
+		if _, err := a.fileSessions(a.currentProject()); err != nil {
+			return nil, err
+		}
+		if ref, ok = a.sessionRef(sessionID); !ok {
+			return nil, fmt.Errorf("session %s not found", sessionID)
+		}
+	}
+	path := ref.path
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	pf, err := a.parseFileCached(path, info)
+	if err != nil {
+		return nil, err
+	}
+	return pf.messages, nil
+}
+
+// parseFileCached parses a session file, reusing the previous result while
+// the file is unchanged.
+func (a *Adapter) parseFileCached(path string, info os.FileInfo) (*parsedFile, error) {
+	if pf, ok := a.fileCache.Get(path, info.Size(), info.ModTime()); ok {
+		return pf, nil
+	}
+	pf, err := a.parseFile(path)
+	if err != nil {
+		return nil, err
+	}
+	a.fileCache.Set(path, pf, info.Size(), info.ModTime(), 0)
+	return pf, nil
+}
+
+// parseFile reads a session file and maps its records to messages.
+func (a *Adapter) parseFile(path string) (*parsedFile, error) {
+	root, records, err := a.readRecords(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := a.cfg.Files.Fields
+	pf := &parsedFile{}
+	// Session-level fields come from the document root, then the first
+	// record that has them.
+	for _, v := range append([]any{root}, records...) {
+		if v == nil {
+			continue
+		}
+		if pf.sessionID == "" {
+			pf.sessionID = lookupString(v, fields.SessionID)
+		}
+		if pf.title == "" {
+			pf.title = lookupString(v, fields.Title)
+		}
+		if pf.project == "" {
+			pf.project = lookupString(v, fields.Project)
+		}
+	}
+	if pf.sessionID == "" {
+		base := filepath.Base(path)
+		pf.sessionID = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	if pf.title != "" {
+		pf.title = truncateTitle(pf.title, 50)
+	}
+
+	for i, rec := range records {
+		if msg, ok := a.recordMessage(rec, i); ok {
+			pf.messages = append(pf.messages, msg)
+		}
+	}
+	return pf, nil
+}
+
+// readRecords returns the decoded records of a session file. For "json"
+// files root is the whole document; for "jsonl" files it is nil.
+func (a *Adapter) readRecords(path string) (root any, records []any, err error) {
+	if a.cfg.Files.Format == formatJSON {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := json.Unmarshal(data, &root); err != nil {
+			return nil, nil, err
+		}
+		list, ok := lookup(root, a.cfg.Files.Messages)
+		if !ok {
+			return root, nil, nil
+		}
+		records, _ = list.([]any)
+		return root, records, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner, buf := cache.NewScanner(f)
+	defer cache.PutScannerBuffer(buf)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+		var rec any
+		if err := json.Unmarshal(line, &rec); err != nil {
+			continue // skip malformed lines, as other JSONL adapters do
+		}
+		records = append(records, rec)
+	}
+	return nil, records, scanner.Err()
+}
+
+// recordMessage maps one record to a message using the field mapping.
+// Records without a role (metadata lines) are skipped.
+func (a *Adapter) recordMessage(rec any, idx int) (adapter.Message, bool) {
+	fields := a.cfg.Files.Fields
+	role := strings.ToLower(lookupString(rec, fields.Role))
+	if role == "" {
+		return adapter.Message{}, false
+	}
+	id := lookupString(rec, fields.ID)
+	if id == "" {
+		id = fmt.Sprintf("msg-%d", idx)
+	}
+	return adapter.Message{
+		ID:        id,
+		Role:      role,
+		Content:   lookupString(rec, fields.Content),
+		Timestamp: lookupTime(rec, fields.Timestamp),
+		Model:     lookupString(rec, fields.Model),
+		TokenUsage: adapter.TokenUsage{
+			InputTokens:  lookupInt(rec, fields.InputTokens),
+			OutputTokens: lookupInt(rec, fields.OutputTokens),
+			CacheRead:    lookupInt(rec, fields.CacheRead),
+			CacheWrite:   lookupInt(rec, fields.CacheWrite),
+		},
+	}, true
+}
+
+// messageSpan returns the first and last non-zero message timestamps.
+func messageSpan(messages []adapter.Message) (first, last time.Time) {
+	for _, m := range messages {
+		if m.Timestamp.IsZero() {
+			continue
+		}
+		if first.IsZero() || m.Timestamp.Before(first) {
+			first = m.Timestamp
+		}
+		if m.Timestamp.After(last) {
+			last = m.Timestamp
+		}
+	}
+	return first, last
+}
+
+// resolveProject returns the absolute, symlink-resolved project root.
+func resolveProject(projectRoot string) string {
+	abs, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return filepath.Clean(projectRoot)
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	return filepath.Clean(abs)
+}
+
+// projectMatches reports whether a session's recorded working directory is
+// the project root or inside it.
+func projectMatches(sessionProject, root string) bool {
+	if sessionProject == "" {
+		return false
+	}
+	p := resolveProject(config.ExpandPath(sessionProject))
+	return p == root || strings.HasPrefix(p, root+string(filepath.Separator))
+}
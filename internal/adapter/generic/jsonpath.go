@@ -0,0 +1,154 @@
+package generic
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// lookup resolves a dotted path such as "message.usage.input_tokens" or
+// "content.0.text" in a decoded JSON value. A leading "$." is ignored.
+func lookup(v any, path string) (any, bool) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if path == "" {
+		return v, v != nil
+	}
+	for _, key := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]any:
+			next, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			v = next
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			v = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, v != nil
+}
+
+// lookupString resolves path and flattens the value to text.
+func lookupString(v any, path string) string {
+	if path == "" {
+		return ""
+	}
+	val, ok := lookup(v, path)
+	if !ok {
+		return ""
+	}
+	return textOf(val)
+}
+
+// lookupInt resolves path as an integer.
+func lookupInt(v any, path string) int {
+	if path == "" {
+		return 0
+	}
+	val, ok := lookup(v, path)
+	if !ok {
+		return 0
+	}
+	return toInt(val)
+}
+
+// lookupTime resolves path as a timestamp.
+func lookupTime(v any, path string) time.Time {
+	if path == "" {
+		return time.Time{}
+	}
+	val, ok := lookup(v, path)
+	if !ok {
+		return time.Time{}
+	}
+	return toTime(val)
+}
+
+// textOf flattens a JSON value to text. Strings are returned as-is; arrays
+// of strings or content blocks ({"type": "text", "text": ...}) are joined
+// with newlines, skipping non-text blocks.
+func textOf(v any) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	case map[string]any:
+		if t, ok := val["type"].(string); ok && t != "text" {
+			return ""
+		}
+		if text, ok := val["text"].(string); ok {
+			return text
+		}
+		return ""
+	case []any:
+		var parts []string
+		for _, item := range val {
+			if s := textOf(item); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, "\n")
+	}
+	return ""
+}
+
+// toInt converts a JSON number or numeric string to an int.
+func toInt(v any) int {
+	switch val := v.(type) {
+	case float64:
+		return int(val)
+	case string:
+		n, _ := strconv.Atoi(strings.TrimSpace(val))
+		return n
+	}
+	return 0
+}
+
+// timeLayouts are the string timestamp formats tried in order.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+}
+
+// toTime converts a timestamp string or a Unix time in seconds or
+// milliseconds to a time.Time.
+func toTime(v any) time.Time {
+	switch val := v.(type) {
+	case float64:
+		return unixTime(val)
+	case string:
+		val = strings.TrimSpace(val)
+		for _, layout := range timeLayouts {
+			if t, err := time.Parse(layout, val); err == nil {
+				return t
+			}
+		}
+		if f, err := strconv.ParseFloat(val, 64); err == nil {
+			return unixTime(f)
+		}
+	}
+	return time.Time{}
+}
+
+// unixTime interprets n as seconds, or milliseconds when it is too large to
+// be a plausible seconds value.
+func unixTime(n float64) time.Time {
+	if n <= 0 {
+		return time.Time{}
+	}
+	if n > 1e11 {
+		return time.UnixMilli(int64(n))
+	}
+	sec := int64(n)
+	return time.Unix(sec, int64((n-float64(sec))*1e9))
+}
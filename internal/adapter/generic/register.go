@@ -0,0 +1,61 @@
+package generic
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/marcus/sidecar/internal/adapter"
+	"github.com/marcus/sidecar/internal/config"
+)
+
+// Register registers an adapter factory for each valid custom adapter in
+// cfg. Invalid entries are logged and skipped. Must be called before
+// adapter.DetectAdapters.
+func Register(cfg *config.Config) {
+	if cfg == nil {
+		return
+	}
+	seen := make(map[string]bool)
+	for _, c := range cfg.Adapters.Custom {
+		if err := Validate(c); err != nil {
+			slog.Warn("custom adapter: invalid config", "id", c.ID, "err", err)
+			continue
+		}
+		if seen[c.ID] {
+			slog.Warn("custom adapter: duplicate id", "id", c.ID)
+			continue
+		}
+		seen[c.ID] = true
+		adapter.RegisterFactory(func() adapter.Adapter {
+			return New(c)
+		})
+	}
+}
+
+// Validate checks that a custom adapter declaration is usable.
+func Validate(c config.CustomAdapterConfig) error {
+	if c.ID == "" {
+		return errors.New("missing id")
+	}
+	if (c.Files == nil) == (c.Command == nil) {
+		return errors.New("exactly one of files or command must be set")
+	}
+	if f := c.Files; f != nil {
+		if f.Glob == "" {
+			return errors.New("files.glob is required")
+		}
+		if f.Format != "" && f.Format != formatJSONL && f.Format != formatJSON {
+			return fmt.Errorf("unknown files.format %q (want %q or %q)", f.Format, formatJSONL, formatJSON)
+		}
+		if f.Fields.Role == "" || f.Fields.Content == "" {
+			return errors.New("files.fields.role and files.fields.content are required")
+		}
+	}
+	if cmd := c.Command; cmd != nil {
+		if len(cmd.Sessions) == 0 || len(cmd.Messages) == 0 {
+			return errors.New("command.sessions and command.messages are required")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package generic
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/marcus/sidecar/internal/adapter"
+	"github.com/marcus/sidecar/internal/adapter/tieredwatcher"
+	"github.com/marcus/sidecar/internal/config"
+)
+
+// maxHotWatchFiles caps the HOT tier for command adapters' watch files.
+const maxHotWatchFiles = 8
+
+// watchFiles watches session files through a tiered watcher: recently
+// active sessions via fsnotify, the rest by polling.
+func (a *Adapter) watchFiles(projectRoot string) (<-chan adapter.Event, io.Closer, error) {
+	glob := a.sessionGlob(projectRoot)
+	sessions, err := a.Sessions(projectRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tw, ch, err := tieredwatcher.New(tieredwatcher.Config{
+		RootDir:   existingDir(globRoot(glob)),
+		Filter:    globFilter(glob),
+		ExtractID: a.sessionIDForPath,
+		ScanDir:   globScanner(glob, a.sessionIDForPath),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	infos := make([]tieredwatcher.SessionInfo, 0, len(sessions))
+	active := 0
+	for _, s := range sessions {
+		var lastHot time.Time
+		if s.IsActive {
+			lastHot = s.UpdatedAt
+			active++
+		}
+		infos = append(infos, tieredwatcher.SessionInfo{
+			ID:       s.ID,
+			Path:     s.Path,
+			ModTime:  s.UpdatedAt,
+			LastHot:  lastHot,
+			FileSize: s.FileSize,
+		})
+	}
+	tw.RegisterSessions(infos)
+	tw.SetHotTarget(active)
+	return ch, tw.NewCloser(), nil
+}
+
+// watchCommand watches the files matched by the command's watch glob.
+// Events carry no session ID, so consumers refresh the whole session list.
+func (a *Adapter) watchCommand(projectRoot string) (<-chan adapter.Event, io.Closer, error) {
+	if a.cfg.Command.Watch == "" {
+		return nil, nil, errors.New(a.ID() + ": no watch glob configured")
+	}
+	glob := config.ExpandPath(strings.ReplaceAll(a.cfg.Command.Watch, "{project}", resolveProject(projectRoot)))
+	pathID := func(path string) string { return path }
+
+	tw, ch, err := tieredwatcher.New(tieredwatcher.Config{
+		RootDir:   existingDir(globRoot(glob)),
+		Filter:    globFilter(glob),
+		ExtractID: pathID,
+		ScanDir:   globScanner(glob, pathID),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	paths, _ := filepath.Glob(glob)
+	infos := make([]tieredwatcher.SessionInfo, 0, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		infos = append(infos, tieredwatcher.SessionInfo{
+			ID:       path,
+			Path:     path,
+			ModTime:  info.ModTime(),
+			LastHot:  info.ModTime(),
+			FileSize: info.Size(),
+		})
+	}
+	tw.RegisterSessions(infos)
+	tw.SetHotTarget(min(len(infos), maxHotWatchFiles))
+
+	events := make(chan adapter.Event, 32)
+	go func() {
+		defer close(events)
+		for evt := range ch {
+			evt.SessionID = ""
+			select {
+			case events <- evt:
+			default:
+			}
+		}
+	}()
+	return events, tw.NewCloser(), nil
+}
+
+// sessionIDForPath maps a session file to its session ID, falling back to
+// the file name for files not yet seen by Sessions.
+func (a *Adapter) sessionIDForPath(path string) string {
+	a.mu.RLock()
+	id, ok := a.pathIndex[path]
+	a.mu.RUnlock()
+	if ok {
+		return id
+	}
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// globFilter returns a filter matching paths against glob.
+func globFilter(glob string) func(path string) bool {
+	return func(path string) bool {
+		ok, _ := filepath.Match(glob, path)
+		return ok
+	}
+}
+
+// globScanner returns a tieredwatcher ScanDir function listing the files in
+// dir that match glob.
+func globScanner(glob string, extractID func(string) string) func(dir string) ([]tieredwatcher.SessionInfo, error) {
+	match := globFilter(glob)
+	return func(dir string) ([]tieredwatcher.SessionInfo, error) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]tieredwatcher.SessionInfo, 0, len(entries))
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+			if entry.IsDir() || !match(path) {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			result = append(result, tieredwatcher.SessionInfo{
+				ID:       extractID(path),
+				Path:     path,
+				ModTime:  info.ModTime(),
+				FileSize: info.Size(),
+			})
+		}
+		return result, nil
+	}
+}
+
+// globRoot returns the deepest directory of glob that has no wildcards.
+func globRoot(glob string) string {
+	dir := filepath.Dir(glob)
+	for strings.ContainsAny(dir, "*?[") {
+		dir = filepath.Dir(dir)
+	}
+	return dir
+}
+
+// existingDir returns dir if it exists, or "" so the watcher starts without
+// a root directory.
+func existingDir(dir string) string {
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		return dir
+	}
+	return ""
+}
@@ -309,7 +309,9 @@ func TestCalculateCost(t *testing.T) {
 		{"claude-sonnet-4", 1000, 500, 0, 0.01, 0.02},
 		{"claude-haiku", 1000, 500, 0, 0.0005, 0.001},
 		{"gpt-4o", 1000, 500, 0, 0.005, 0.01},
-		{"deepseek", 1000, 500, 0, 0.0001, 0.0005},
+		{"deepseek", 1000, 500, 0, 0.0005, 0.001},
+		{"claude-sonnet-4", 1000, 0, 1000, 0.0002, 0.0004}, // all cached: 1000 * $0.30/M
+		{"unknown-model", 1000, 500, 0, 0, 0},
 	}
 
 	for _, tt := range tests {
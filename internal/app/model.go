@@ -12,6 +12,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/marcus/sidecar/internal/community"
 	"github.com/marcus/sidecar/internal/config"
+	"github.com/marcus/sidecar/internal/customcmd"
 	"github.com/marcus/sidecar/internal/keymap"
 	"github.com/marcus/sidecar/internal/modal"
 	"github.com/marcus/sidecar/internal/mouse"
@@ -38,7 +39,9 @@ const (
 	ModalWorktreeSwitcher                  // Worktree switcher
 	ModalThemeSwitcher                     // Theme switcher
 	ModalIssueInput                        // Issue ID text input
-	ModalIssuePreview                      // Issue preview display (lowest priority)
+	ModalIssuePreview                      // Issue preview display
+	ModalCommandConfirm                    // Project custom command confirmation
+	ModalCommandOutput                     // Custom command output (lowest priority)
 )
 
 // activeModal returns the highest-priority open modal.
@@ -65,6 +68,10 @@ func (m *Model) activeModal() ModalKind {
 		return ModalIssueInput
 	case m.showIssuePreview:
 		return ModalIssuePreview
+	case m.commandConfirm != nil:
+		return ModalCommandConfirm
+	case m.showCommandOutput:
+		return ModalCommandOutput
 	default:
 		return ModalNone
 	}
@@ -97,8 +104,9 @@ type Model struct {
 	activePlugin int
 
 	// Keymap
-	keymap        *keymap.Registry
-	activeContext string
+	keymap          *keymap.Registry
+	activeContext   string
+	keymapConflicts []keymap.Conflict // user overrides that shadow bindings, found at startup
 
 	// UI state
 	width, height    int
@@ -195,6 +203,25 @@ type Model struct {
 	issuePreviewModalWidth   int
 	issuePreviewMouseHandler *mouse.Handler
 
+	// User-defined commands (palette and key bindings)
+	customCommands      []customcmd.Command
+	customCommandErrors []string // invalid commands, shown in diagnostics
+
+	// Project custom command confirmation modal
+	commandConfirm             *customcmd.Command
+	commandConfirmModal        *modal.Modal
+	commandConfirmModalWidth   int
+	commandConfirmMouseHandler *mouse.Handler
+
+	// Custom command output modal
+	showCommandOutput         bool
+	commandOutputName         string
+	commandOutputText         string
+	commandOutputErr          error
+	commandOutputModal        *modal.Modal
+	commandOutputModalWidth   int
+	commandOutputMouseHandler *mouse.Handler
+
 	// Header/footer
 	ui *UIState
 
@@ -271,20 +298,23 @@ func New(reg *plugin.Registry, km *keymap.Registry, cfg *config.Config, currentV
 		}
 	}
 
-	return Model{
-		cfg:                   cfg,
-		registry:              reg,
-		keymap:                km,
-		activePlugin:          activeIdx,
-		activeContext:         "global",
-		showClock:             cfg.UI.ShowClock,
-		palette:               palette.New(),
-		ui:                    ui,
-		ready:                 false,
-		intro:                 NewIntroModel(repoName),
+	m := Model{
+		cfg:               cfg,
+		registry:          reg,
+		keymap:            km,
+		activePlugin:      activeIdx,
+		activeContext:     "global",
+		showClock:         cfg.UI.ShowClock,
+		palette:           palette.New(),
+		ui:                ui,
+		ready:             false,
+		intro:             NewIntroModel(repoName),
 		currentVersion:    currentVersion,
 		updatePhaseStatus: make(map[UpdatePhase]string),
 	}
+	// Also collects keymap conflicts, including custom command keys
+	m.loadCustomCommands()
+	return m
 }
 
 // Init initializes the model and returns initial commands.
@@ -303,6 +333,17 @@ func (m Model) Init() tea.Cmd {
 		}
 	}
 
+	var problems []string
+	if n := len(m.keymapConflicts); n > 0 {
+		problems = append(problems, fmt.Sprintf("%d keymap conflict(s)", n))
+	}
+	if n := len(m.customCommandErrors); n > 0 {
+		problems = append(problems, fmt.Sprintf("%d invalid custom command(s)", n))
+	}
+	if len(problems) > 0 {
+		cmds = append(cmds, ShowToast(strings.Join(problems, ", ")+", press ! for details", 5*time.Second))
+	}
+
 	return tea.Batch(cmds...)
 }
 
@@ -348,6 +389,7 @@ func (m *Model) SetActivePlugin(idx int) tea.Cmd {
 		if next := m.ActivePlugin(); next != nil {
 			next.SetFocused(true)
 			m.activeContext = next.FocusContext()
+			m.publishFocus(next.ID())
 			return PluginFocused()
 		}
 	}
@@ -713,6 +755,10 @@ func (m *Model) switchProject(projectPath string) tea.Cmd {
 	// This stops all plugins, updates the context, and starts them again
 	startCmds := m.registry.Reinit(targetPath, newProjectRoot)
 
+	// Custom commands can be defined per project
+	m.loadCustomCommands()
+	m.clearDiagnosticsModal()
+
 	// Send WindowSizeMsg to all plugins so they recalculate layout/bounds.
 	// Without this, plugins like td-monitor lose mouse interactivity because
 	// their panel bounds are only calculated on WindowSizeMsg receipt.
@@ -0,0 +1,97 @@
+package app
+
+import (
+	"os"
+	"slices"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/event"
+	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/remote"
+)
+
+// appRemoteMethods are the remote control methods the app answers itself.
+var appRemoteMethods = []string{"info", "plugins.list", "plugin.switch"}
+
+// remotePluginInfo describes a plugin for plugins.list.
+type remotePluginInfo struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Active bool   `json:"active"`
+}
+
+// handleRemoteRequest answers a remote control request or hands it to the
+// plugin that serves its method.
+func (m *Model) handleRemoteRequest(req *remote.Request) tea.Cmd {
+	switch req.Method {
+	case "info":
+		active := ""
+		if p := m.ActivePlugin(); p != nil {
+			active = p.ID()
+		}
+		req.Reply(map[string]any{
+			"pid":         os.Getpid(),
+			"version":     m.currentVersion,
+			"workDir":     m.ui.WorkDir,
+			"projectRoot": m.ui.ProjectRoot,
+			"plugin":      active,
+			"methods":     m.remoteMethods(),
+		}, nil)
+		return nil
+
+	case "plugins.list":
+		var list []remotePluginInfo
+		active := m.ActivePlugin()
+		for _, p := range m.registry.Plugins() {
+			list = append(list, remotePluginInfo{ID: p.ID(), Name: p.Name(), Active: p == active})
+		}
+		req.Reply(list, nil)
+		return nil
+
+	case "plugin.switch":
+		var params struct {
+			ID string `json:"id"`
+		}
+		if err := req.Decode(&params); err != nil {
+			req.Reply(nil, err)
+			return nil
+		}
+		if m.registry.Get(params.ID) == nil {
+			req.Reply(nil, remote.InvalidParams("unknown plugin %q", params.ID))
+			return nil
+		}
+		cmd := m.FocusPluginByID(params.ID)
+		req.Reply(nil, nil)
+		return cmd
+	}
+
+	for _, p := range m.registry.Plugins() {
+		if h, ok := p.(plugin.RemoteHandler); ok && slices.Contains(h.RemoteMethods(), req.Method) {
+			return h.HandleRemote(req)
+		}
+	}
+	req.Reply(nil, remote.MethodNotFound(req.Method))
+	return nil
+}
+
+// remoteMethods lists every remote method served by the app and its plugins.
+func (m *Model) remoteMethods() []string {
+	methods := append([]string{"ping", "events.subscribe"}, appRemoteMethods...)
+	for _, p := range m.registry.Plugins() {
+		if h, ok := p.(plugin.RemoteHandler); ok {
+			methods = append(methods, h.RemoteMethods()...)
+		}
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// publishFocus announces the focused plugin on the event bus.
+func (m *Model) publishFocus(pluginID string) {
+	ctx := m.registry.Context()
+	if ctx == nil || ctx.EventBus == nil {
+		return
+	}
+	ctx.EventBus.Publish(event.TopicUI, event.NewEvent(event.TypeFocusChanged, event.TopicUI, pluginID))
+}
@@ -0,0 +1,88 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/event"
+	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/remote"
+)
+
+// remoteStubPlugin serves the "stub.echo" remote method.
+type remoteStubPlugin struct {
+	id      string
+	focused bool
+}
+
+func (p *remoteStubPlugin) ID() string                              { return p.id }
+func (p *remoteStubPlugin) Name() string                            { return p.id }
+func (p *remoteStubPlugin) Icon() string                            { return "" }
+func (p *remoteStubPlugin) Init(*plugin.Context) error              { return nil }
+func (p *remoteStubPlugin) Start() tea.Cmd                          { return nil }
+func (p *remoteStubPlugin) Stop()                                   {}
+func (p *remoteStubPlugin) Update(tea.Msg) (plugin.Plugin, tea.Cmd) { return p, nil }
+func (p *remoteStubPlugin) View(int, int) string                    { return "" }
+func (p *remoteStubPlugin) IsFocused() bool                         { return p.focused }
+func (p *remoteStubPlugin) SetFocused(f bool)                       { p.focused = f }
+func (p *remoteStubPlugin) Commands() []plugin.Command              { return nil }
+func (p *remoteStubPlugin) FocusContext() string                    { return p.id }
+func (p *remoteStubPlugin) RemoteMethods() []string                 { return []string{"stub.echo"} }
+func (p *remoteStubPlugin) HandleRemote(req *remote.Request) tea.Cmd {
+	req.Reply(p.id, nil)
+	return nil
+}
+
+func TestHandleRemoteRequest(t *testing.T) {
+	bus := event.New()
+	defer bus.Close()
+	focus := bus.Subscribe(event.TopicUI)
+
+	registry := plugin.NewRegistry(&plugin.Context{EventBus: bus})
+	for _, id := range []string{"one", "two"} {
+		if err := registry.Register(&remoteStubPlugin{id: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m := Model{registry: registry, ui: &UIState{WorkDir: "/repo", ProjectRoot: "/repo"}}
+
+	call := func(method, params string) (any, error) {
+		req := remote.NewRequest(method, json.RawMessage(params))
+		m.handleRemoteRequest(req)
+		return req.Wait(time.Second)
+	}
+
+	if _, err := call("plugin.switch", `{"id": "two"}`); err != nil || m.ActivePlugin().ID() != "two" {
+		t.Fatalf("plugin.switch: %v, active %s", err, m.ActivePlugin().ID())
+	}
+	select {
+	case e := <-focus:
+		if e.Type != event.TypeFocusChanged || e.Data != "two" {
+			t.Errorf("focus event = %+v", e)
+		}
+	case <-time.After(time.Second):
+		t.Error("switching plugins should publish a focus event")
+	}
+
+	var rpcErr *remote.Error
+	if _, err := call("plugin.switch", `{"id": "nope"}`); !errors.As(err, &rpcErr) || rpcErr.Code != remote.CodeInvalidParams {
+		t.Errorf("unknown plugin should fail with invalid params, got %v", err)
+	}
+	if _, err := call("nope", ""); !errors.As(err, &rpcErr) || rpcErr.Code != remote.CodeMethodNotFound {
+		t.Errorf("unknown method should fail, got %v", err)
+	}
+	if got, err := call("stub.echo", ""); err != nil || got != "one" {
+		t.Errorf("plugin method should reach its plugin, got %v, %v", got, err)
+	}
+
+	info, err := call("info", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields := info.(map[string]any); fields["workDir"] != "/repo" || fields["plugin"] != "two" {
+		t.Errorf("info = %v", fields)
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/marcus/sidecar/internal/mouse"
 	"github.com/marcus/sidecar/internal/palette"
 	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/remote"
 	"github.com/marcus/sidecar/internal/state"
 	"github.com/marcus/sidecar/internal/styles"
 	"github.com/marcus/sidecar/internal/theme"
@@ -46,8 +47,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if res, ok := (&m).resolveUserKey(msg); ok {
+			return (&m).applyResolution(res)
+		}
 		return (&m).handleKeyMsg(msg)
 
+	case resolvedKeyMsg:
+		return (&m).handleKeyMsg(tea.KeyMsg(msg))
+
+	case chordTimeoutMsg:
+		if res, ok := m.keymap.ExpireChord(msg.id, m.activeContext); ok {
+			return (&m).applyResolution(res)
+		}
+		return m, nil
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -107,6 +120,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.handleIssueInputMouse(msg)
 		case ModalIssuePreview:
 			return m.handleIssuePreviewMouse(msg)
+		case ModalCommandConfirm:
+			return m.handleCommandConfirmMouse(msg)
+		case ModalCommandOutput:
+			return m.handleCommandOutputMouse(msg)
 		}
 
 		// Handle header tab clicks (Y < 2 means header area)
@@ -293,6 +310,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.clearChangelogModal() // Force rebuild with new content
 		return m, nil
 
+	case *remote.Request:
+		return m, m.handleRemoteRequest(msg)
+
 	case FocusPluginByIDMsg:
 		// Switch to requested plugin
 		return m, m.FocusPluginByID(msg.PluginID)
@@ -363,6 +383,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case RunCustomCommandMsg:
+		return m, m.runCustomCommand(msg.ID)
+
+	case customCommandDoneMsg:
+		return m, m.handleCustomCommandDone(msg)
+
 	case version.UpdateAvailableMsg:
 		m.updateAvailable = &msg
 		m.updateInstallMethod = msg.InstallMethod
@@ -499,6 +525,14 @@ func (m *Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.resetIssueInput()
 			m.updateContext()
 			return m, nil
+		case ModalCommandConfirm:
+			m.resetCommandConfirm()
+			m.updateContext()
+			return m, nil
+		case ModalCommandOutput:
+			m.resetCommandOutput()
+			m.updateContext()
+			return m, nil
 		case ModalThemeSwitcher:
 			// Esc: clear filter if set, otherwise close (restore original)
 			if m.themeSwitcherInput.Value() != "" {
@@ -540,7 +574,7 @@ func (m *Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// This ensures characters like `, ~, ?, !, @, q, 1-5 reach tmux instead of triggering app shortcuts
 	// Ctrl+C is forwarded to tmux (to interrupt running processes) instead of showing quit dialog
 	// User can exit interactive mode with Ctrl+\ first, then quit normally
-	if m.activeContext == "workspace-interactive" || m.activeContext == "file-browser-inline-edit" || m.activeContext == "notes-inline-edit" {
+	if isPassthroughContext(m.activeContext) {
 		// Forward ALL keys to plugin (exit keys and ctrl+c handled by plugin)
 		if p := m.ActivePlugin(); p != nil {
 			newPlugin, cmd := p.Update(msg)
@@ -1150,6 +1184,16 @@ func (m *Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	}
 
+	// Handle project command confirmation keys
+	if m.commandConfirm != nil {
+		return m.handleCommandConfirmKey(msg)
+	}
+
+	// Handle custom command output modal keys
+	if m.showCommandOutput {
+		return m.handleCommandOutputKey(msg)
+	}
+
 	// If any modal is open, don't process plugin/toggle keys
 	if m.hasModal() {
 		return m, nil
@@ -1268,9 +1312,12 @@ func (m *Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, Refresh()
 	}
 
-	// Try keymap for context-specific bindings
-	if cmd := m.keymap.Handle(msg, m.activeContext); cmd != nil {
-		return m, cmd
+	// Try keymap for context-specific bindings (e.g. custom command keys),
+	// unless the user is typing
+	if !m.consumesTextInput() {
+		if cmd := m.keymap.Handle(msg, m.activeContext); cmd != nil {
+			return m, cmd
+		}
 	}
 
 	// Forward to active plugin
@@ -1331,6 +1378,17 @@ func isRootContext(ctx string) bool {
 	}
 }
 
+// isPassthroughContext returns true if the context forwards every key to the
+// plugin, such as interactive terminals and inline editors.
+func isPassthroughContext(ctx string) bool {
+	switch ctx {
+	case "workspace-interactive", "file-browser-inline-edit", "notes-inline-edit":
+		return true
+	default:
+		return false
+	}
+}
+
 // isTextInputContext returns true if the context is a text input mode
 // where alphanumeric keys should be forwarded to the plugin for typing.
 func isTextInputContext(ctx string) bool {
@@ -57,9 +57,20 @@ func (m Model) View() string {
 	b.WriteString("\n")
 	b.WriteString("\n") // spacing between header and content
 
-	// Main content
+	// Main content, with the pending chord's continuations below it
+	whichKey := m.renderWhichKey()
+	if whichKey != "" {
+		contentHeight -= lipgloss.Height(whichKey)
+		if contentHeight < 0 {
+			contentHeight = 0
+		}
+	}
 	content := m.renderContent(m.width, contentHeight)
 	b.WriteString(content)
+	if whichKey != "" {
+		b.WriteString("\n")
+		b.WriteString(whichKey)
+	}
 
 	// Footer
 	b.WriteString("\n")
@@ -93,6 +104,10 @@ func (m Model) View() string {
 		return m.renderIssueInputOverlay(bg)
 	case ModalIssuePreview:
 		return m.renderIssuePreviewOverlay(bg)
+	case ModalCommandConfirm:
+		return m.renderCommandConfirmOverlay(bg)
+	case ModalCommandOutput:
+		return m.renderCommandOutputOverlay(bg)
 	}
 
 	return bg
@@ -697,6 +712,8 @@ func (m Model) footerHints() []footerHint {
 	if p := m.ActivePlugin(); p != nil {
 		hints = m.pluginFooterHints(p, m.activeContext)
 	}
+	// Custom commands with keys, next to the plugin's own
+	hints = append(hints, m.customFooterHints(m.activeContext)...)
 	// Then essential global hints
 	hints = append(hints, m.globalFooterHints()...)
 	return hints
@@ -775,6 +792,18 @@ func (m Model) pluginFooterHints(p plugin.Plugin, context string) []footerHint {
 	return hints
 }
 
+// customFooterHints returns hints for custom commands bound to a key in the
+// context or globally.
+func (m Model) customFooterHints(context string) []footerHint {
+	var hints []footerHint
+	for _, c := range m.customCommands {
+		if c.Bound && (c.Context == context || c.Context == "global") {
+			hints = append(hints, footerHint{keys: c.Key, label: c.Name})
+		}
+	}
+	return hints
+}
+
 func bindingKeysByCommand(bindings []keymap.Binding) map[string][]string {
 	keysByCmd := make(map[string][]string, len(bindings))
 	for _, b := range bindings {
@@ -0,0 +1,112 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/marcus/sidecar/internal/keymap"
+	"github.com/marcus/sidecar/internal/styles"
+)
+
+// chordTimeoutMsg ends a pending user chord that got no further key.
+type chordTimeoutMsg struct {
+	id int
+}
+
+// resolvedKeyMsg is a key produced by a user override. It is handled like a
+// key press but is not resolved again, so swapped keys don't loop.
+type resolvedKeyMsg tea.KeyMsg
+
+// resolveUserKey applies user keymap overrides and chords to a key press.
+// Overrides don't apply while a modal is open or the user is typing.
+func (m *Model) resolveUserKey(msg tea.KeyMsg) (keymap.Resolution, bool) {
+	if m.keymap == nil || m.hasModal() || isPassthroughContext(m.activeContext) || m.consumesTextInput() {
+		return keymap.Resolution{}, false
+	}
+	return m.keymap.Resolve(msg, m.activeContext)
+}
+
+// applyResolution acts on a resolved override: waits for the rest of a
+// chord, runs a command, or handles the command's default keys.
+func (m *Model) applyResolution(res keymap.Resolution) (tea.Model, tea.Cmd) {
+	switch {
+	case res.Pending:
+		id := res.ChordID
+		return m, tea.Tick(m.keymap.ChordTimeout(), func(time.Time) tea.Msg {
+			return chordTimeoutMsg{id: id}
+		})
+	case res.Cmd != nil:
+		return m, res.Cmd
+	case len(res.Keys) == 0:
+		return m, nil
+	case len(res.Keys) == 1:
+		return m.handleKeyMsg(res.Keys[0])
+	}
+
+	// Multi-key defaults (e.g. "g g") are replayed in order
+	cmds := make([]tea.Cmd, len(res.Keys))
+	for i, k := range res.Keys {
+		key := resolvedKeyMsg(k)
+		cmds[i] = func() tea.Msg { return key }
+	}
+	return m, tea.Sequence(cmds...)
+}
+
+// renderWhichKey renders the keys that can continue a pending chord, or ""
+// when no chord is pending.
+func (m Model) renderWhichKey() string {
+	if m.keymap == nil {
+		return ""
+	}
+	prefix, conts := m.keymap.PendingChord(m.activeContext)
+	if prefix == "" {
+		return ""
+	}
+
+	hints := make([]string, 0, len(conts)+1)
+	for _, c := range conts {
+		label := "+more"
+		if c.Command != "" {
+			label = m.commandLabel(c.Command)
+		}
+		hints = append(hints, fmt.Sprintf("%s %s", styles.KeyHint.Render(c.Key), label))
+	}
+	hints = append(hints, fmt.Sprintf("%s %s", styles.KeyHint.Render("esc"), styles.Muted.Render("cancel")))
+
+	// Wrap hints into lines that fit the screen
+	maxW := m.width - 4
+	var lines []string
+	line := styles.Title.Render(prefix) + " "
+	for _, h := range hints {
+		if lipgloss.Width(line)+lipgloss.Width(h)+2 > maxW && strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += "  "
+		}
+		line += h
+	}
+	lines = append(lines, line)
+
+	return styles.ModalBox.Padding(0, 1).Width(m.width - 2).Render(strings.Join(lines, "\n"))
+}
+
+// commandLabel returns a display name for a command ID, preferring the
+// active plugin's command names.
+func (m Model) commandLabel(id string) string {
+	if p := m.ActivePlugin(); p != nil {
+		for _, cmd := range p.Commands() {
+			if cmd.ID == id {
+				return cmd.Name
+			}
+		}
+	}
+	if cmd, ok := m.keymap.GetCommand(id); ok && cmd.Name != "" {
+		return cmd.Name
+	}
+	return id
+}
@@ -9,6 +9,120 @@ type Config struct {
 	Keymap   KeymapConfig   `json:"keymap"`
 	UI       UIConfig       `json:"ui"`
 	Features FeaturesConfig `json:"features"`
+	Pricing  PricingConfig  `json:"pricing"`
+	Adapters AdaptersConfig `json:"adapters"`
+	// Notifications alert when a workspace agent needs input or finishes.
+	Notifications NotificationsConfig `json:"notifications"`
+	// Remote serves the remote control API on a Unix socket.
+	Remote RemoteConfig `json:"remote"`
+}
+
+// RemoteConfig configures the remote control socket.
+type RemoteConfig struct {
+	Enabled bool `json:"enabled"`
+	// Socket is the socket path. Default: sockets/sidecar-<pid>.sock in the
+	// config directory.
+	Socket string `json:"socket,omitempty"`
+}
+
+// NotificationsConfig configures alerts for workspace agent status changes.
+type NotificationsConfig struct {
+	Enabled bool `json:"enabled"`
+	// Bell rings the terminal bell. Default: true.
+	Bell bool `json:"bell"`
+	// OSC sends a desktop notification escape sequence: "9" (iTerm2,
+	// WezTerm, Windows Terminal), "777" (urxvt, foot, Ghostty) or "" for none.
+	OSC string `json:"osc,omitempty"`
+	// Command runs a notifier such as notify-send. Arguments may contain
+	// {title}, {body}, {status}, {workspace} and {agent}.
+	Command []string `json:"command,omitempty"`
+	// WebhookURL receives a JSON POST per notification. Only loopback
+	// addresses are allowed.
+	WebhookURL string `json:"webhookUrl,omitempty"`
+	// On lists the statuses that notify: "waiting", "done", "error".
+	// Default: all three.
+	On []string `json:"on,omitempty"`
+	// Debounce is how long a status must hold before it notifies, so brief
+	// flickers don't. Default: 3s.
+	Debounce time.Duration `json:"debounce,omitempty"`
+	// MuteAgents silences agent types, e.g. ["aider"].
+	MuteAgents []string `json:"muteAgents,omitempty"`
+}
+
+// AdaptersConfig declares config-driven adapters for agents that have no
+// built-in adapter.
+type AdaptersConfig struct {
+	Custom []CustomAdapterConfig `json:"custom,omitempty"`
+}
+
+// CustomAdapterConfig declares one config-driven adapter. Exactly one of
+// Files or Command must be set.
+type CustomAdapterConfig struct {
+	ID      string                `json:"id"`
+	Name    string                `json:"name,omitempty"` // defaults to ID
+	Icon    string                `json:"icon,omitempty"` // single character badge
+	Files   *CustomAdapterFiles   `json:"files,omitempty"`
+	Command *CustomAdapterCommand `json:"command,omitempty"`
+}
+
+// CustomAdapterFiles reads sessions from files matched by a glob, one
+// session per file.
+type CustomAdapterFiles struct {
+	// Glob matches session files (supports ~). The placeholders {project}
+	// and {projectName} expand to the project root and its base name.
+	Glob string `json:"glob"`
+	// Format is "jsonl" (one record per line, default) or "json" (one
+	// document whose Messages path holds the records).
+	Format   string `json:"format,omitempty"`
+	Messages string `json:"messages,omitempty"`
+	// Fields maps message and session attributes to dotted JSON paths.
+	Fields CustomAdapterFields `json:"fields"`
+}
+
+// CustomAdapterFields maps attributes to dotted JSON paths such as
+// "message.usage.input_tokens" or "content.0.text". Paths are looked up on
+// each record; Title, Project and SessionID also fall back to the document
+// root in "json" format.
+type CustomAdapterFields struct {
+	SessionID    string `json:"sessionId,omitempty"` // default: file name
+	Title        string `json:"title,omitempty"`
+	Project      string `json:"project,omitempty"` // session working dir, for project filtering
+	ID           string `json:"id,omitempty"`
+	Role         string `json:"role"`
+	Content      string `json:"content"`
+	Timestamp    string `json:"timestamp,omitempty"`
+	Model        string `json:"model,omitempty"`
+	InputTokens  string `json:"inputTokens,omitempty"`
+	OutputTokens string `json:"outputTokens,omitempty"`
+	CacheRead    string `json:"cacheRead,omitempty"`
+	CacheWrite   string `json:"cacheWrite,omitempty"`
+}
+
+// CustomAdapterCommand runs external commands that print sessions and
+// messages as JSON. Arguments may contain {project} and {session}.
+type CustomAdapterCommand struct {
+	Sessions []string `json:"sessions"`
+	Messages []string `json:"messages"`
+	// Watch is an optional glob of files whose changes trigger a refresh.
+	Watch   string        `json:"watch,omitempty"`
+	Timeout time.Duration `json:"timeout,omitempty"`
+}
+
+// PricingConfig overrides or extends the built-in model pricing table.
+type PricingConfig struct {
+	// Models maps a model name pattern (case-insensitive substring, longest
+	// match wins) to its price history. Entries replace the built-in history
+	// for the same pattern.
+	Models map[string][]ModelPrice `json:"models,omitempty"`
+}
+
+// ModelPrice is a model's rates in USD per million tokens, effective from Since.
+type ModelPrice struct {
+	Since      string  `json:"since,omitempty"` // YYYY-MM-DD; empty = always
+	Input      float64 `json:"input"`
+	Output     float64 `json:"output"`
+	CacheRead  float64 `json:"cacheRead,omitempty"`
+	CacheWrite float64 `json:"cacheWrite,omitempty"`
 }
 
 // FeaturesConfig holds feature flag settings.
@@ -63,6 +177,9 @@ type WorkspacePluginConfig struct {
 	// DirPrefix prefixes workspace directory names with the repo name (e.g., 'myrepo-feature-auth')
 	// This helps associate conversations with the repo after workspace deletion. Default: true.
 	DirPrefix bool `json:"dirPrefix"`
+	// Backend runs agent sessions: "tmux", "pty" (in-process terminal, no
+	// tmux needed) or "auto" (tmux when installed, otherwise pty). Default: "auto".
+	Backend string `json:"backend,omitempty"`
 	// TmuxCaptureMaxBytes caps tmux pane capture size for the preview pane. Default: 2MB.
 	TmuxCaptureMaxBytes int `json:"tmuxCaptureMaxBytes"`
 	// InteractiveExitKey is the keybinding to exit interactive mode. Default: "ctrl+\".
@@ -75,6 +192,64 @@ type WorkspacePluginConfig struct {
 	InteractiveCopyKey string `json:"interactiveCopyKey,omitempty"`
 	// InteractivePasteKey is the keybinding to paste clipboard in interactive mode. Default: "alt+v".
 	InteractivePasteKey string `json:"interactivePasteKey,omitempty"`
+	// StatusRules overrides agent status detection per agent type ("claude",
+	// "codex", "aider", "gemini", "cursor", "opencode", or "default" for
+	// agents without built-in rules).
+	StatusRules map[string]StatusRulesConfig `json:"statusRules,omitempty"`
+	// SessionLogs keeps agent and shell output in .sidecar/logs.
+	SessionLogs SessionLogsConfig `json:"sessionLogs"`
+	// MergeVerifyCommand runs in each worktree after it is rebased in the
+	// merge queue (e.g. "go test ./..."); a non-zero exit stops the queue.
+	MergeVerifyCommand string `json:"mergeVerifyCommand,omitempty"`
+	// Budgets controls how agent run budgets are enforced.
+	Budgets BudgetsConfig `json:"budgets"`
+}
+
+// BudgetsConfig configures enforcement of per-worktree agent budgets. The
+// limits themselves are set per worktree when it is created.
+type BudgetsConfig struct {
+	// WarnPercent warns once this share of any limit is used. Default: 80.
+	WarnPercent int `json:"warnPercent"`
+	// Interrupt sends the agent an interrupt key when a limit is reached.
+	// Default: false (warn only).
+	Interrupt bool `json:"interrupt"`
+}
+
+// SessionLogsConfig configures the output logs written for workspace agent
+// and shell sessions, so their history outlives the session.
+type SessionLogsConfig struct {
+	// Enabled turns session logging on. Default: true.
+	Enabled bool `json:"enabled"`
+	// MaxSizeMB rotates a session's log once it grows past this size. Default: 5.
+	MaxSizeMB int `json:"maxSizeMB"`
+	// MaxFiles is how many rotated logs are kept per session. Default: 3.
+	MaxFiles int `json:"maxFiles"`
+	// RetentionDays deletes logs not written to for this many days. Default: 14.
+	RetentionDays int `json:"retentionDays"`
+}
+
+// StatusRulesConfig describes how an agent's status is read from its
+// terminal output. Patterns are Go regular expressions matched against single
+// lines (ANSI codes stripped). A list that is set replaces the built-in list,
+// so an empty list disables that status; null or absent keeps the built-in.
+type StatusRulesConfig struct {
+	// TailLines is how many trailing non-blank lines are checked.
+	TailLines int `json:"tailLines,omitempty"`
+	// PromptLines is how many trailing lines are checked for prompt markers.
+	PromptLines int `json:"promptLines,omitempty"`
+	// Waiting matches questions that block on the user (e.g. "[y/n]").
+	Waiting []string `json:"waiting"`
+	// Prompt matches the agent's idle input prompt.
+	Prompt []string `json:"prompt"`
+	// Thinking matches spinners and progress lines shown while working.
+	Thinking []string `json:"thinking"`
+	// Done matches completion messages.
+	Done []string `json:"done"`
+	// Error matches failure messages.
+	Error []string `json:"error"`
+	// ExitCode matches an exit report; its first group is the exit code.
+	// Zero means done, anything else an error.
+	ExitCode string `json:"exitCode,omitempty"`
 }
 
 // NotesPluginConfig configures the notes plugin.
@@ -87,7 +262,12 @@ type NotesPluginConfig struct {
 
 // KeymapConfig holds key binding overrides.
 type KeymapConfig struct {
-	Overrides map[string]string `json:"overrides"`
+	Overrides map[string]string            `json:"overrides"`          // key -> command ID, in every context
+	Contexts  map[string]map[string]string `json:"contexts,omitempty"` // context -> key -> command ID
+	// Leader is the key "<leader>" stands for in override keys (default "space").
+	Leader string `json:"leader,omitempty"`
+	// ChordTimeout is how long a multi-key override waits for its next key.
+	ChordTimeout time.Duration `json:"chordTimeout,omitempty"`
 }
 
 // UIConfig configures UI appearance.
@@ -127,11 +307,23 @@ func Default() *Config {
 			},
 			Workspace: WorkspacePluginConfig{
 				DirPrefix:           true,
+				Backend:             "auto",
 				TmuxCaptureMaxBytes: 2 * 1024 * 1024,
+				SessionLogs: SessionLogsConfig{
+					Enabled:       true,
+					MaxSizeMB:     5,
+					MaxFiles:      3,
+					RetentionDays: 14,
+				},
+				Budgets: BudgetsConfig{
+					WarnPercent: 80,
+				},
 			},
 		},
 		Keymap: KeymapConfig{
-			Overrides: make(map[string]string),
+			Overrides:    make(map[string]string),
+			Contexts:     make(map[string]map[string]string),
+			ChordTimeout: time.Second,
 		},
 		UI: UIConfig{
 			ShowClock:  true,
@@ -143,6 +335,10 @@ func Default() *Config {
 		Features: FeaturesConfig{
 			Flags: make(map[string]bool),
 		},
+		Notifications: NotificationsConfig{
+			Bell:     true,
+			Debounce: 3 * time.Second,
+		},
 	}
 }
 
@@ -157,5 +353,26 @@ func (c *Config) Validate() error {
 	if c.Plugins.Workspace.TmuxCaptureMaxBytes <= 0 {
 		c.Plugins.Workspace.TmuxCaptureMaxBytes = 2 * 1024 * 1024
 	}
+	logs := &c.Plugins.Workspace.SessionLogs
+	if logs.MaxSizeMB <= 0 {
+		logs.MaxSizeMB = 5
+	}
+	if logs.MaxFiles < 0 {
+		logs.MaxFiles = 3
+	}
+	if logs.RetentionDays <= 0 {
+		logs.RetentionDays = 14
+	}
+	if w := c.Plugins.Workspace.Budgets.WarnPercent; w <= 0 || w > 100 {
+		c.Plugins.Workspace.Budgets.WarnPercent = 80
+	}
+	switch c.Plugins.Workspace.Backend {
+	case "auto", "tmux", "pty":
+	default:
+		c.Plugins.Workspace.Backend = "auto"
+	}
+	if c.Notifications.Debounce < 0 {
+		c.Notifications.Debounce = 3 * time.Second
+	}
 	return nil
 }
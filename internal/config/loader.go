@@ -30,11 +30,57 @@ func ResetTestConfigPath() {
 
 // rawConfig is the JSON-unmarshaling intermediary.
 type rawConfig struct {
-	Projects rawProjectsConfig `json:"projects"`
-	Plugins  rawPluginsConfig  `json:"plugins"`
-	Keymap   KeymapConfig      `json:"keymap"`
-	UI       rawUIConfig       `json:"ui"`
-	Features FeaturesConfig    `json:"features"`
+	Projects      rawProjectsConfig      `json:"projects"`
+	Plugins       rawPluginsConfig       `json:"plugins"`
+	Keymap        rawKeymapConfig        `json:"keymap"`
+	UI            rawUIConfig            `json:"ui"`
+	Features      FeaturesConfig         `json:"features"`
+	Pricing       PricingConfig          `json:"pricing"`
+	Adapters      rawAdaptersConfig      `json:"adapters"`
+	Notifications rawNotificationsConfig `json:"notifications"`
+	Remote        rawRemoteConfig        `json:"remote"`
+}
+
+type rawRemoteConfig struct {
+	Enabled *bool  `json:"enabled"`
+	Socket  string `json:"socket"`
+}
+
+type rawNotificationsConfig struct {
+	Enabled    *bool    `json:"enabled"`
+	Bell       *bool    `json:"bell"`
+	OSC        string   `json:"osc"`
+	Command    []string `json:"command"`
+	WebhookURL string   `json:"webhookUrl"`
+	On         []string `json:"on"`
+	Debounce   string   `json:"debounce"`
+	MuteAgents []string `json:"muteAgents"`
+}
+
+type rawAdaptersConfig struct {
+	Custom []rawCustomAdapterConfig `json:"custom"`
+}
+
+type rawCustomAdapterConfig struct {
+	ID      string                   `json:"id"`
+	Name    string                   `json:"name"`
+	Icon    string                   `json:"icon"`
+	Files   *CustomAdapterFiles      `json:"files"`
+	Command *rawCustomAdapterCommand `json:"command"`
+}
+
+type rawCustomAdapterCommand struct {
+	Sessions []string `json:"sessions"`
+	Messages []string `json:"messages"`
+	Watch    string   `json:"watch"`
+	Timeout  string   `json:"timeout"`
+}
+
+type rawKeymapConfig struct {
+	Overrides    map[string]string            `json:"overrides"`
+	Contexts     map[string]map[string]string `json:"contexts"`
+	Leader       string                       `json:"leader"`
+	ChordTimeout string                       `json:"chordTimeout"`
 }
 
 type rawUIConfig struct {
@@ -63,12 +109,29 @@ type rawPluginsConfig struct {
 }
 
 type rawWorkspaceConfig struct {
-	DirPrefix            *bool  `json:"dirPrefix"`
-	TmuxCaptureMaxBytes  *int   `json:"tmuxCaptureMaxBytes"`
-	InteractiveExitKey   string `json:"interactiveExitKey"`
-	InteractiveAttachKey string `json:"interactiveAttachKey"`
-	InteractiveCopyKey   string `json:"interactiveCopyKey"`
-	InteractivePasteKey  string `json:"interactivePasteKey"`
+	DirPrefix            *bool                        `json:"dirPrefix"`
+	Backend              string                       `json:"backend"`
+	TmuxCaptureMaxBytes  *int                         `json:"tmuxCaptureMaxBytes"`
+	InteractiveExitKey   string                       `json:"interactiveExitKey"`
+	InteractiveAttachKey string                       `json:"interactiveAttachKey"`
+	InteractiveCopyKey   string                       `json:"interactiveCopyKey"`
+	InteractivePasteKey  string                       `json:"interactivePasteKey"`
+	StatusRules          map[string]StatusRulesConfig `json:"statusRules"`
+	SessionLogs          rawSessionLogsConfig         `json:"sessionLogs"`
+	MergeVerifyCommand   string                       `json:"mergeVerifyCommand"`
+	Budgets              rawBudgetsConfig             `json:"budgets"`
+}
+
+type rawBudgetsConfig struct {
+	WarnPercent *int  `json:"warnPercent"`
+	Interrupt   *bool `json:"interrupt"`
+}
+
+type rawSessionLogsConfig struct {
+	Enabled       *bool `json:"enabled"`
+	MaxSizeMB     *int  `json:"maxSizeMB"`
+	MaxFiles      *int  `json:"maxFiles"`
+	RetentionDays *int  `json:"retentionDays"`
 }
 
 type rawGitStatusConfig struct {
@@ -191,6 +254,9 @@ func mergeConfig(cfg *Config, raw *rawConfig) {
 	if raw.Plugins.Workspace.DirPrefix != nil {
 		cfg.Plugins.Workspace.DirPrefix = *raw.Plugins.Workspace.DirPrefix
 	}
+	if raw.Plugins.Workspace.Backend != "" {
+		cfg.Plugins.Workspace.Backend = raw.Plugins.Workspace.Backend
+	}
 	if raw.Plugins.Workspace.TmuxCaptureMaxBytes != nil {
 		cfg.Plugins.Workspace.TmuxCaptureMaxBytes = *raw.Plugins.Workspace.TmuxCaptureMaxBytes
 	}
@@ -206,6 +272,32 @@ func mergeConfig(cfg *Config, raw *rawConfig) {
 	if raw.Plugins.Workspace.InteractivePasteKey != "" {
 		cfg.Plugins.Workspace.InteractivePasteKey = raw.Plugins.Workspace.InteractivePasteKey
 	}
+	if raw.Plugins.Workspace.StatusRules != nil {
+		cfg.Plugins.Workspace.StatusRules = raw.Plugins.Workspace.StatusRules
+	}
+	if raw.Plugins.Workspace.MergeVerifyCommand != "" {
+		cfg.Plugins.Workspace.MergeVerifyCommand = raw.Plugins.Workspace.MergeVerifyCommand
+	}
+	rawLogs, logs := raw.Plugins.Workspace.SessionLogs, &cfg.Plugins.Workspace.SessionLogs
+	if rawLogs.Enabled != nil {
+		logs.Enabled = *rawLogs.Enabled
+	}
+	if rawLogs.MaxSizeMB != nil {
+		logs.MaxSizeMB = *rawLogs.MaxSizeMB
+	}
+	if rawLogs.MaxFiles != nil {
+		logs.MaxFiles = *rawLogs.MaxFiles
+	}
+	if rawLogs.RetentionDays != nil {
+		logs.RetentionDays = *rawLogs.RetentionDays
+	}
+	rawBudgets, budgets := raw.Plugins.Workspace.Budgets, &cfg.Plugins.Workspace.Budgets
+	if rawBudgets.WarnPercent != nil {
+		budgets.WarnPercent = *rawBudgets.WarnPercent
+	}
+	if rawBudgets.Interrupt != nil {
+		budgets.Interrupt = *rawBudgets.Interrupt
+	}
 
 	// Keymap
 	if raw.Keymap.Overrides != nil {
@@ -213,6 +305,22 @@ func mergeConfig(cfg *Config, raw *rawConfig) {
 			cfg.Keymap.Overrides[k] = v
 		}
 	}
+	for ctx, bindings := range raw.Keymap.Contexts {
+		if cfg.Keymap.Contexts[ctx] == nil {
+			cfg.Keymap.Contexts[ctx] = make(map[string]string)
+		}
+		for k, v := range bindings {
+			cfg.Keymap.Contexts[ctx][k] = v
+		}
+	}
+	if raw.Keymap.Leader != "" {
+		cfg.Keymap.Leader = raw.Keymap.Leader
+	}
+	if raw.Keymap.ChordTimeout != "" {
+		if d, err := time.ParseDuration(raw.Keymap.ChordTimeout); err == nil && d > 0 {
+			cfg.Keymap.ChordTimeout = d
+		}
+	}
 
 	// UI
 	if raw.UI.ShowClock != nil {
@@ -249,6 +357,65 @@ func mergeConfig(cfg *Config, raw *rawConfig) {
 			cfg.Features.Flags[k] = v
 		}
 	}
+
+	// Pricing
+	if len(raw.Pricing.Models) > 0 {
+		cfg.Pricing.Models = raw.Pricing.Models
+	}
+
+	// Custom adapters
+	for _, rc := range raw.Adapters.Custom {
+		c := CustomAdapterConfig{ID: rc.ID, Name: rc.Name, Icon: rc.Icon, Files: rc.Files}
+		if rc.Command != nil {
+			c.Command = &CustomAdapterCommand{
+				Sessions: rc.Command.Sessions,
+				Messages: rc.Command.Messages,
+				Watch:    rc.Command.Watch,
+			}
+			if rc.Command.Timeout != "" {
+				if d, err := time.ParseDuration(rc.Command.Timeout); err == nil {
+					c.Command.Timeout = d
+				}
+			}
+		}
+		cfg.Adapters.Custom = append(cfg.Adapters.Custom, c)
+	}
+
+	// Notifications
+	if raw.Notifications.Enabled != nil {
+		cfg.Notifications.Enabled = *raw.Notifications.Enabled
+	}
+	if raw.Notifications.Bell != nil {
+		cfg.Notifications.Bell = *raw.Notifications.Bell
+	}
+	if raw.Notifications.OSC != "" {
+		cfg.Notifications.OSC = raw.Notifications.OSC
+	}
+	if len(raw.Notifications.Command) > 0 {
+		cfg.Notifications.Command = raw.Notifications.Command
+	}
+	if raw.Notifications.WebhookURL != "" {
+		cfg.Notifications.WebhookURL = raw.Notifications.WebhookURL
+	}
+	if len(raw.Notifications.On) > 0 {
+		cfg.Notifications.On = raw.Notifications.On
+	}
+	if raw.Notifications.Debounce != "" {
+		if d, err := time.ParseDuration(raw.Notifications.Debounce); err == nil {
+			cfg.Notifications.Debounce = d
+		}
+	}
+	if len(raw.Notifications.MuteAgents) > 0 {
+		cfg.Notifications.MuteAgents = raw.Notifications.MuteAgents
+	}
+
+	// Remote control
+	if raw.Remote.Enabled != nil {
+		cfg.Remote.Enabled = *raw.Remote.Enabled
+	}
+	if raw.Remote.Socket != "" {
+		cfg.Remote.Socket = ExpandPath(raw.Remote.Socket)
+	}
 }
 
 // ExpandPath expands ~ to home directory.
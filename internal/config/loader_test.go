@@ -184,3 +184,216 @@ func TestLoadFrom_EmptyProjectsList(t *testing.T) {
 		t.Errorf("got %d projects, want 0", len(cfg.Projects.List))
 	}
 }
+
+func TestLoadFrom_CustomAdapters(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+
+	content := []byte(`{
+		"adapters": {
+			"custom": [
+				{
+					"id": "inhouse",
+					"name": "In-house Agent",
+					"files": {
+						"glob": "~/.inhouse/sessions/*.jsonl",
+						"fields": {"role": "role", "content": "text", "inputTokens": "usage.in"}
+					}
+				},
+				{
+					"id": "cli-agent",
+					"command": {
+						"sessions": ["agent", "sessions", "--json"],
+						"messages": ["agent", "show", "{session}"],
+						"timeout": "5s"
+					}
+				}
+			]
+		}
+	}`)
+
+	if err := os.WriteFile(configPath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+
+	if len(cfg.Adapters.Custom) != 2 {
+		t.Fatalf("got %d custom adapters, want 2", len(cfg.Adapters.Custom))
+	}
+	files := cfg.Adapters.Custom[0]
+	if files.Files == nil || files.Files.Fields.InputTokens != "usage.in" {
+		t.Errorf("files mapping not loaded: %+v", files.Files)
+	}
+	cmd := cfg.Adapters.Custom[1].Command
+	if cmd == nil || cmd.Timeout != 5*time.Second || len(cmd.Messages) != 3 {
+		t.Errorf("command not loaded: %+v", cmd)
+	}
+}
+
+func TestLoadFrom_StatusRules(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+
+	content := []byte(`{
+		"plugins": {
+			"workspace": {
+				"statusRules": {
+					"claude": {"tailLines": 20, "error": []},
+					"default": {"waiting": ["^Ready>"], "exitCode": "\\[exit (\\d+)\\]"}
+				}
+			}
+		}
+	}`)
+
+	if err := os.WriteFile(configPath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+
+	rules := cfg.Plugins.Workspace.StatusRules
+	claude := rules["claude"]
+	if claude.TailLines != 20 || claude.Error == nil || len(claude.Error) != 0 || claude.Waiting != nil {
+		t.Errorf("claude rules not loaded: %+v", claude)
+	}
+	if def := rules["default"]; len(def.Waiting) != 1 || def.ExitCode != `\[exit (\d+)\]` {
+		t.Errorf("default rules not loaded: %+v", def)
+	}
+}
+
+func TestLoadFrom_Notifications(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+
+	content := []byte(`{
+		"notifications": {
+			"enabled": true,
+			"bell": false,
+			"osc": "777",
+			"command": ["notify-send", "{title}", "{body}"],
+			"on": ["waiting"],
+			"debounce": "500ms",
+			"muteAgents": ["aider"]
+		}
+	}`)
+
+	if err := os.WriteFile(configPath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+
+	n := cfg.Notifications
+	if !n.Enabled || n.Bell || n.OSC != "777" || len(n.Command) != 3 {
+		t.Errorf("notifications not loaded: %+v", n)
+	}
+	if n.Debounce != 500*time.Millisecond || len(n.On) != 1 || n.MuteAgents[0] != "aider" {
+		t.Errorf("notification filters not loaded: %+v", n)
+	}
+
+	if d := Default().Notifications; d.Enabled || !d.Bell || d.Debounce != 3*time.Second {
+		t.Errorf("unexpected defaults: %+v", d)
+	}
+}
+
+func TestLoadFrom_Remote(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+
+	content := []byte(`{"remote": {"enabled": true, "socket": "/tmp/sidecar-test.sock"}}`)
+	if err := os.WriteFile(configPath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+	if !cfg.Remote.Enabled || cfg.Remote.Socket != "/tmp/sidecar-test.sock" {
+		t.Errorf("remote not loaded: %+v", cfg.Remote)
+	}
+	if Default().Remote.Enabled {
+		t.Error("remote should be disabled by default")
+	}
+}
+
+func TestLoadFrom_SessionLogs(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+
+	content := []byte(`{"plugins": {"workspace": {"sessionLogs": {"enabled": false, "maxFiles": 0, "retentionDays": 30}}}}`)
+	if err := os.WriteFile(configPath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+
+	logs := cfg.Plugins.Workspace.SessionLogs
+	if logs.Enabled || logs.MaxFiles != 0 || logs.RetentionDays != 30 || logs.MaxSizeMB != 5 {
+		t.Errorf("session logs not loaded: %+v", logs)
+	}
+}
+
+func TestLoadFrom_Budgets(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+
+	content := []byte(`{"plugins": {"workspace": {"budgets": {"warnPercent": 150, "interrupt": true}}}}`)
+	if err := os.WriteFile(configPath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+
+	budgets := cfg.Plugins.Workspace.Budgets
+	if !budgets.Interrupt || budgets.WarnPercent != 80 {
+		t.Errorf("budgets = %+v, want interrupt with warnPercent reset to 80", budgets)
+	}
+}
+
+func TestLoadFrom_KeymapContexts(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+
+	content := []byte(`{"keymap": {
+		"overrides": {"ctrl+t": "cursor-top"},
+		"contexts": {"git-status": {"ctrl+k": "commit", "<leader> s": "stage-file"}},
+		"leader": ",",
+		"chordTimeout": "750ms"
+	}}`)
+	if err := os.WriteFile(configPath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+
+	km := cfg.Keymap
+	if km.Overrides["ctrl+t"] != "cursor-top" || km.Contexts["git-status"]["ctrl+k"] != "commit" || km.Contexts["git-status"]["<leader> s"] != "stage-file" {
+		t.Errorf("keymap = %+v", km)
+	}
+	if km.Leader != "," || km.ChordTimeout != 750*time.Millisecond {
+		t.Errorf("leader %q, chord timeout %v", km.Leader, km.ChordTimeout)
+	}
+	if d := Default().Keymap.ChordTimeout; d != time.Second {
+		t.Errorf("default chord timeout = %v", d)
+	}
+}
@@ -12,11 +12,18 @@ import (
 type saveConfig struct {
 	Projects saveProjectsConfig `json:"projects"`
 	Plugins  savePluginsConfig  `json:"plugins"`
-	Keymap   KeymapConfig       `json:"keymap"`
+	Keymap   saveKeymapConfig   `json:"keymap"`
 	UI       UIConfig           `json:"ui"`
 	Features FeaturesConfig     `json:"features,omitempty"`
 }
 
+type saveKeymapConfig struct {
+	Overrides    map[string]string            `json:"overrides"`
+	Contexts     map[string]map[string]string `json:"contexts,omitempty"`
+	Leader       string                       `json:"leader,omitempty"`
+	ChordTimeout string                       `json:"chordTimeout,omitempty"`
+}
+
 type saveProjectsConfig struct {
 	Mode string          `json:"mode,omitempty"`
 	Root string          `json:"root,omitempty"`
@@ -47,12 +54,17 @@ type saveConversationsConfig struct {
 }
 
 type saveWorkspaceConfig struct {
-	DirPrefix            *bool  `json:"dirPrefix,omitempty"`
-	TmuxCaptureMaxBytes  *int   `json:"tmuxCaptureMaxBytes,omitempty"`
-	InteractiveExitKey   string `json:"interactiveExitKey,omitempty"`
-	InteractiveAttachKey string `json:"interactiveAttachKey,omitempty"`
-	InteractiveCopyKey   string `json:"interactiveCopyKey,omitempty"`
-	InteractivePasteKey  string `json:"interactivePasteKey,omitempty"`
+	DirPrefix            *bool                        `json:"dirPrefix,omitempty"`
+	Backend              string                       `json:"backend,omitempty"`
+	TmuxCaptureMaxBytes  *int                         `json:"tmuxCaptureMaxBytes,omitempty"`
+	InteractiveExitKey   string                       `json:"interactiveExitKey,omitempty"`
+	InteractiveAttachKey string                       `json:"interactiveAttachKey,omitempty"`
+	InteractiveCopyKey   string                       `json:"interactiveCopyKey,omitempty"`
+	InteractivePasteKey  string                       `json:"interactivePasteKey,omitempty"`
+	StatusRules          map[string]StatusRulesConfig `json:"statusRules,omitempty"`
+	SessionLogs          SessionLogsConfig            `json:"sessionLogs"`
+	MergeVerifyCommand   string                       `json:"mergeVerifyCommand,omitempty"`
+	Budgets              BudgetsConfig                `json:"budgets"`
 }
 
 // toSaveConfig converts Config to the JSON-serializable format.
@@ -79,14 +91,24 @@ func toSaveConfig(cfg *Config) saveConfig {
 			},
 			Workspace: saveWorkspaceConfig{
 				DirPrefix:            &cfg.Plugins.Workspace.DirPrefix,
+				Backend:              cfg.Plugins.Workspace.Backend,
 				TmuxCaptureMaxBytes:  &cfg.Plugins.Workspace.TmuxCaptureMaxBytes,
 				InteractiveExitKey:   cfg.Plugins.Workspace.InteractiveExitKey,
 				InteractiveAttachKey: cfg.Plugins.Workspace.InteractiveAttachKey,
 				InteractiveCopyKey:   cfg.Plugins.Workspace.InteractiveCopyKey,
 				InteractivePasteKey:  cfg.Plugins.Workspace.InteractivePasteKey,
+				StatusRules:          cfg.Plugins.Workspace.StatusRules,
+				SessionLogs:          cfg.Plugins.Workspace.SessionLogs,
+				MergeVerifyCommand:   cfg.Plugins.Workspace.MergeVerifyCommand,
+				Budgets:              cfg.Plugins.Workspace.Budgets,
 			},
 		},
-		Keymap:   cfg.Keymap,
+		Keymap: saveKeymapConfig{
+			Overrides:    cfg.Keymap.Overrides,
+			Contexts:     cfg.Keymap.Contexts,
+			Leader:       cfg.Keymap.Leader,
+			ChordTimeout: cfg.Keymap.ChordTimeout.String(),
+		},
 		UI:       cfg.UI,
 		Features: cfg.Features,
 	}
@@ -98,3 +98,29 @@ func TestSave_WorksWithNoExistingFile(t *testing.T) {
 		t.Error("missing 'projects' key")
 	}
 }
+
+func TestSave_RoundTripsStatusRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	SetTestConfigPath(path)
+	defer ResetTestConfigPath()
+
+	cfg := Default()
+	cfg.Plugins.Workspace.StatusRules = map[string]StatusRulesConfig{
+		"claude": {TailLines: 20, Error: []string{}},
+	}
+	if err := Save(cfg); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+	claude, ok := loaded.Plugins.Workspace.StatusRules["claude"]
+	if !ok || claude.TailLines != 20 {
+		t.Fatalf("status rules not saved: %+v", loaded.Plugins.Workspace.StatusRules)
+	}
+	if claude.Error == nil || claude.Waiting != nil {
+		t.Errorf("empty and unset lists not preserved: error=%v waiting=%v", claude.Error, claude.Waiting)
+	}
+}
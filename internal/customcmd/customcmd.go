@@ -0,0 +1,238 @@
+package customcmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// IDPrefix prefixes the keymap command IDs of custom commands.
+const IDPrefix = "custom:"
+
+// Output says what happens with a command's output.
+type Output string
+
+const (
+	OutputToast     Output = "toast"     // Show the last output line in a toast (default)
+	OutputModal     Output = "modal"     // Show the full output in a scrollable modal
+	OutputShell     Output = "shell"     // Run in a new workspace shell
+	OutputClipboard Output = "clipboard" // Copy the output to the clipboard
+)
+
+// Variables are the placeholders a command template may use.
+var Variables = []string{"file", "selection", "branch", "worktree", "session_id", "task", "project", "workdir"}
+
+// Command is a user-defined palette command.
+type Command struct {
+	Name        string `json:"name"`
+	Command     string `json:"command"`               // shell template, e.g. "make test-file {file}"
+	Description string `json:"description,omitempty"` // palette description; defaults to the template
+	Context     string `json:"context,omitempty"`     // keymap context, e.g. "file-browser-tree"; default "global"
+	Key         string `json:"key,omitempty"`         // optional key binding in Context
+	Output      Output `json:"output,omitempty"`      // default "toast"
+	Source      string `json:"-"`                     // "global" or "project" (set at load time)
+	Err         error  `json:"-"`                     // validation error found at load time
+	Bound       bool   `json:"-"`                     // Key is registered (set when loaded into the keymap)
+}
+
+// configWithCommands is the config structure for loading commands.
+type configWithCommands struct {
+	Commands []Command `json:"commands"`
+}
+
+// ID returns the keymap command ID for the command.
+func (c Command) ID() string {
+	return IDPrefix + c.Name
+}
+
+// Fingerprint identifies the command's definition. Confirming a project
+// command trusts this fingerprint, so editing the command asks again.
+func (c Command) Fingerprint() string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{c.Name, c.Command, c.Context, c.Key, string(c.Output)}, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Load loads and merges commands from global and project config directories.
+// Project commands override global commands with the same name. Commands that
+// fail validation have Err set. Empty directories are skipped. Returns sorted
+// list by name.
+func Load(globalConfigDir, projectDir string) []Command {
+	merged := make(map[string]Command)
+	if globalConfigDir != "" {
+		for _, c := range loadFromFile(filepath.Join(globalConfigDir, "config.json"), "global") {
+			merged[c.Name] = c
+		}
+	}
+	if projectDir != "" {
+		for _, c := range loadFromFile(filepath.Join(projectDir, ".sidecar", "config.json"), "project") {
+			merged[c.Name] = c
+		}
+	}
+
+	result := make([]Command, 0, len(merged))
+	for _, c := range merged {
+		if c.Context == "" {
+			c.Context = "global"
+		}
+		if c.Output == "" {
+			c.Output = OutputToast
+		}
+		if c.Description == "" {
+			c.Description = c.Command
+		}
+		c.Err = c.validate()
+		result = append(result, c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// loadFromFile loads commands from a JSON config file. Missing or unreadable
+// files yield no commands.
+func loadFromFile(path, source string) []Command {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var cfg configWithCommands
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil
+	}
+	for i := range cfg.Commands {
+		cfg.Commands[i].Source = source
+	}
+	return cfg.Commands
+}
+
+// validate checks the command's fields and template placeholders.
+func (c Command) validate() error {
+	switch {
+	case strings.TrimSpace(c.Name) == "":
+		return fmt.Errorf("missing name")
+	case strings.TrimSpace(c.Command) == "":
+		return fmt.Errorf("missing command")
+	}
+	switch c.Output {
+	case OutputToast, OutputModal, OutputShell, OutputClipboard:
+	default:
+		return fmt.Errorf("unknown output %q (want toast, modal, shell or clipboard)", c.Output)
+	}
+	for _, name := range c.Placeholders() {
+		if !slices.Contains(Variables, name) {
+			return fmt.Errorf("unknown variable {%s}", name)
+		}
+	}
+	return checkUnquoted(c.Command)
+}
+
+// placeholderPattern matches {name}. Matches preceded by "$" are shell
+// parameter expansions such as ${HOME} and are left alone.
+var placeholderPattern = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// placeholderIndexes returns the submatch indexes of the template's
+// placeholders.
+func placeholderIndexes(tmpl string) [][]int {
+	var out [][]int
+	for _, loc := range placeholderPattern.FindAllStringSubmatchIndex(tmpl, -1) {
+		if loc[0] > 0 && tmpl[loc[0]-1] == '$' {
+			continue
+		}
+		out = append(out, loc)
+	}
+	return out
+}
+
+// checkUnquoted rejects placeholders inside single or double quotes or after
+// a backslash. Their values are single-quoted when expanded, which only
+// protects them outside other quoting: within "..." a value's $(...) would
+// still run.
+func checkUnquoted(tmpl string) error {
+	locs := placeholderIndexes(tmpl)
+	var quote byte // the open quote character, or 0
+	for i := 0; i < len(tmpl) && len(locs) > 0; i++ {
+		if i >= locs[0][0] {
+			name := tmpl[locs[0][2]:locs[0][3]]
+			switch {
+			case i > locs[0][0]:
+				return fmt.Errorf("{%s} is escaped; placeholders are quoted for you", name)
+			case quote != 0:
+				return fmt.Errorf("{%s} is inside %c quotes; placeholders are quoted for you", name, quote)
+			}
+			locs = locs[1:]
+		}
+		switch c := tmpl[i]; {
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			}
+		case c == '\\':
+			i++ // escaped character
+		case quote == '"':
+			if c == '"' {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		}
+	}
+	return nil
+}
+
+// Placeholders returns the variable names used by the template, in order of
+// first use.
+func (c Command) Placeholders() []string {
+	var names []string
+	for _, loc := range placeholderIndexes(c.Command) {
+		if name := c.Command[loc[2]:loc[3]]; !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Expand fills the template's placeholders with shell-quoted variable values.
+// It fails when a placeholder has no value, e.g. {file} with nothing selected,
+// or sits inside quotes.
+func (c Command) Expand(vars map[string]string) (string, error) {
+	if err := checkUnquoted(c.Command); err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	last := 0
+	for _, loc := range placeholderIndexes(c.Command) {
+		name := c.Command[loc[2]:loc[3]]
+		value := vars[name]
+		if value == "" {
+			return "", fmt.Errorf("{%s} is not available here", name)
+		}
+		b.WriteString(c.Command[last:loc[0]])
+		b.WriteString(Quote(value))
+		last = loc[1]
+	}
+	b.WriteString(c.Command[last:])
+	return b.String(), nil
+}
+
+// Quote quotes a value for sh.
+func Quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Run runs an expanded command with sh in dir and returns its combined
+// output with trailing whitespace trimmed.
+func Run(dir, script string) (string, error) {
+	cmd := exec.Command("sh", "-c", script)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	return strings.TrimRight(string(out), " \t\r\n"), err
+}
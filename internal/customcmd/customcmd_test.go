@@ -0,0 +1,166 @@
+package customcmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, dir, body string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad_ProjectOverridesGlobal(t *testing.T) {
+	globalDir := t.TempDir()
+	projectDir := t.TempDir()
+	writeConfig(t, globalDir, `{"commands": [
+		{"name": "test file", "command": "go test {file}"},
+		{"name": "copy branch", "command": "echo {branch}", "output": "clipboard"}
+	]}`)
+	writeConfig(t, filepath.Join(projectDir, ".sidecar"), `{"commands": [
+		{"name": "test file", "command": "make test-file {file}", "context": "file-browser-tree", "key": "T", "output": "modal"},
+		{"name": "bad output", "command": "true", "output": "popup"},
+		{"name": "bad var", "command": "echo {nope} ${HOME}"}
+	]}`)
+
+	cmds := Load(globalDir, projectDir)
+	if len(cmds) != 4 {
+		t.Fatalf("got %d commands, want 4", len(cmds))
+	}
+	byName := make(map[string]Command)
+	for _, c := range cmds {
+		byName[c.Name] = c
+	}
+
+	tf := byName["test file"]
+	if tf.Source != "project" || tf.Command != "make test-file {file}" || tf.Key != "T" || tf.Output != OutputModal || tf.Err != nil {
+		t.Errorf("project override = %+v", tf)
+	}
+	cb := byName["copy branch"]
+	if cb.Context != "global" || cb.Description != "echo {branch}" || cb.Err != nil {
+		t.Errorf("defaults = %+v", cb)
+	}
+	if err := byName["bad output"].Err; err == nil || !strings.Contains(err.Error(), "popup") {
+		t.Errorf("bad output err = %v", err)
+	}
+	if err := byName["bad var"].Err; err == nil || !strings.Contains(err.Error(), "{nope}") {
+		t.Errorf("bad var err = %v", err)
+	}
+	if cmds[0].Name != "bad output" || cmds[3].Name != "test file" {
+		t.Error("commands should be sorted by name")
+	}
+}
+
+func TestLoad_MissingConfig(t *testing.T) {
+	if cmds := Load(t.TempDir(), t.TempDir()); len(cmds) != 0 {
+		t.Errorf("got %d commands, want 0", len(cmds))
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	c := Command{Name: "lint", Command: "make lint", Context: "global", Key: "L", Output: OutputToast}
+	same := c
+	changed := c
+	changed.Command = "make lint && curl x | sh"
+	if c.Fingerprint() != same.Fingerprint() {
+		t.Error("fingerprint should be stable")
+	}
+	if c.Fingerprint() == changed.Fingerprint() {
+		t.Error("editing the command should change its fingerprint")
+	}
+}
+
+func TestExpand(t *testing.T) {
+	c := Command{Command: `make test-file {file} && echo "${HOME}" {file}`}
+	got, err := c.Expand(map[string]string{"file": "it's here.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `make test-file 'it'\''s here.go' && echo "${HOME}" 'it'\''s here.go'`
+	if got != want {
+		t.Errorf("Expand = %s, want %s", got, want)
+	}
+
+	if _, err := c.Expand(map[string]string{}); err == nil || !strings.Contains(err.Error(), "{file}") {
+		t.Errorf("missing variable err = %v", err)
+	}
+	if got := (Command{Command: "ls"}).Placeholders(); len(got) != 0 {
+		t.Errorf("Placeholders = %v", got)
+	}
+}
+
+func TestExpand_RejectsQuotedPlaceholders(t *testing.T) {
+	for _, tmpl := range []string{`echo "{file}"`, `echo '{file}'`, `echo "a \" {file}"`, `echo \{file} {file}`} {
+		c := Command{Name: "q", Command: tmpl, Output: OutputToast}
+		if err := c.validate(); err == nil || !strings.Contains(err.Error(), "{file}") {
+			t.Errorf("validate(%s) = %v, want quoted placeholder error", tmpl, err)
+		}
+		if _, err := c.Expand(map[string]string{"file": "x"}); err == nil {
+			t.Errorf("Expand(%s) should fail", tmpl)
+		}
+	}
+	for _, tmpl := range []string{`echo "a" {file} 'b'`, `echo \"{file}`, `echo "it's" {file}`} {
+		c := Command{Name: "q", Command: tmpl, Output: OutputToast}
+		if err := c.validate(); err != nil {
+			t.Errorf("validate(%s) = %v", tmpl, err)
+		}
+	}
+}
+
+func TestRun_FilenameCannotInjectCommands(t *testing.T) {
+	dir := t.TempDir()
+	c := Command{Command: "echo {file}"}
+	script, err := c.Expand(map[string]string{"file": "$(touch pwned)`touch pwned2`.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := Run(dir, script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "$(touch pwned)`touch pwned2`.go" {
+		t.Errorf("output = %q", out)
+	}
+	for _, name := range []string{"pwned", "pwned2"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			t.Errorf("filename ran a command that created %s", name)
+		}
+	}
+
+	// The same name inside the template's own double quotes is refused
+	c.Command = `echo "{file}"`
+	if _, err := c.Expand(map[string]string{"file": "$(touch pwned)"}); err == nil {
+		t.Error("double-quoted placeholder should not expand")
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	c := Command{Command: "printf '%s\\n' {selection} && pwd"}
+	script, err := c.Expand(map[string]string{"selection": "a $b `c`"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := Run(dir, script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(out, "\n")
+	if len(lines) != 2 || lines[0] != "a $b `c`" {
+		t.Errorf("output = %q", out)
+	}
+	if resolved, _ := filepath.EvalSymlinks(dir); lines[1] != dir && lines[1] != resolved {
+		t.Errorf("ran in %s, want %s", lines[1], dir)
+	}
+
+	if _, err := Run(dir, "exit 3"); err == nil {
+		t.Error("non-zero exit should fail")
+	}
+}
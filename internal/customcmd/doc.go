@@ -0,0 +1,5 @@
+// Package customcmd loads user-defined commands from the global and project
+// config files. A command is a shell template such as "make test-file {file}"
+// whose placeholders are filled from the current selection when it runs from
+// the command palette or its key binding.
+package customcmd
@@ -47,6 +47,21 @@ func (d *Dispatcher) Subscribe(topic string) <-chan Event {
 	return ch
 }
 
+// Unsubscribe removes a channel returned by Subscribe and closes it.
+func (d *Dispatcher) Unsubscribe(topic string, ch <-chan Event) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	subs := d.subscribers[topic]
+	for i, sub := range subs {
+		if sub == ch {
+			close(sub)
+			d.subscribers[topic] = append(subs[:i:i], subs[i+1:]...)
+			return
+		}
+	}
+}
+
 // Publish sends an event to all subscribers of a topic.
 // Non-blocking: drops events if subscriber buffer is full.
 func (d *Dispatcher) Publish(topic string, e Event) {
@@ -147,3 +147,27 @@ func TestDispatcher_Concurrent(t *testing.T) {
 		}
 	}
 }
+
+func TestDispatcher_Unsubscribe(t *testing.T) {
+	d := New()
+	defer d.Close()
+
+	ch := d.Subscribe("test")
+	other := d.Subscribe("test")
+	d.Unsubscribe("test", ch)
+
+	if _, ok := <-ch; ok {
+		t.Error("unsubscribed channel should be closed")
+	}
+	d.Publish("test", NewEvent(TypeError, "test", nil))
+	select {
+	case <-other:
+	case <-time.After(100 * time.Millisecond):
+		t.Error("remaining subscriber should still receive events")
+	}
+
+	// Unsubscribing twice or after Close is a no-op
+	d.Unsubscribe("test", ch)
+	d.Close()
+	d.Unsubscribe("test", other)
+}
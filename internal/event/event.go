@@ -4,10 +4,10 @@ import "time"
 
 // Event represents a typed event in the system.
 type Event struct {
-	Type      Type
-	Topic     string
-	Timestamp time.Time
-	Data      any
+	Type      Type      `json:"type"`
+	Topic     string    `json:"topic"`
+	Timestamp time.Time `json:"timestamp"`
+	Data      any       `json:"data,omitempty"`
 }
 
 // Type identifies the kind of event.
@@ -27,10 +27,28 @@ const (
 	TypeFocusChanged  Type = "focus_changed"
 	TypeRefreshNeeded Type = "refresh_needed"
 
+	// Agent events
+	TypeAgentStatus Type = "agent_status"
+
 	// Error events
 	TypeError Type = "error"
 )
 
+// Topics published by the app and plugins.
+const (
+	TopicUI     = "ui"     // focus changes; Data is the focused plugin ID
+	TopicAgents = "agents" // agent status changes; Data is AgentStatus
+)
+
+// AgentStatus is the payload of TypeAgentStatus events.
+type AgentStatus struct {
+	Workspace string `json:"workspace"` // worktree or shell name
+	Shell     bool   `json:"shell,omitempty"`
+	Agent     string `json:"agent,omitempty"` // agent type, e.g. "claude"
+	Status    string `json:"status"`          // e.g. "active", "waiting", "done"
+	Detail    string `json:"detail,omitempty"`
+}
+
 // NewEvent creates a new event with the current timestamp.
 func NewEvent(t Type, topic string, data any) Event {
 	return Event{
@@ -67,11 +67,15 @@ func DefaultBindings() []Binding {
 		{Key: "z", Command: "stash", Context: "git-status"},
 		{Key: "Z", Command: "stash-pop", Context: "git-status"},
 		{Key: "ctrl+z", Command: "stash-apply", Context: "git-status"},
+		{Key: "H", Command: "show-reflog", Context: "git-status"},
+		{Key: "alt+z", Command: "undo-action", Context: "git-status"},
 		{Key: "O", Command: "open-in-file-browser", Context: "git-status"},
 		{Key: "o", Command: "open-in-github", Context: "git-status"},
 		{Key: "y", Command: "yank-file", Context: "git-status"},
 		{Key: "Y", Command: "yank-path", Context: "git-status"},
 		{Key: "D", Command: "discard-changes", Context: "git-status"},
+		{Key: "R", Command: "rebase-actions", Context: "git-status"},
+		{Key: "M", Command: "resolve-conflicts", Context: "git-status"},
 		{Key: "\\", Command: "toggle-sidebar", Context: "git-status"},
 
 		// Git status commits context (sidebar)
@@ -92,6 +96,14 @@ func DefaultBindings() []Binding {
 		{Key: "v", Command: "toggle-graph", Context: "git-status-commits"},
 		{Key: "P", Command: "push", Context: "git-status-commits"},
 		{Key: "L", Command: "pull", Context: "git-status-commits"},
+		{Key: "I", Command: "interactive-rebase", Context: "git-status-commits"},
+		{Key: "C", Command: "cherry-pick", Context: "git-status-commits"},
+		{Key: "m", Command: "mark-range", Context: "git-status-commits"},
+		{Key: "t", Command: "revert-commit", Context: "git-status-commits"},
+		{Key: "X", Command: "reset-to-commit", Context: "git-status-commits"},
+		{Key: "H", Command: "show-reflog", Context: "git-status-commits"},
+		{Key: "alt+z", Command: "undo-action", Context: "git-status-commits"},
+		{Key: "R", Command: "rebase-actions", Context: "git-status-commits"},
 		{Key: "\\", Command: "toggle-sidebar", Context: "git-status-commits"},
 
 		// Git history search modal context
@@ -114,8 +126,13 @@ func DefaultBindings() []Binding {
 		{Key: "ctrl+d", Command: "page-down", Context: "git-status-diff"},
 		{Key: "ctrl+u", Command: "page-up", Context: "git-status-diff"},
 		{Key: "enter", Command: "full-diff", Context: "git-status-diff"},
-		{Key: "s", Command: "stage-file", Context: "git-status-diff"},
-		{Key: "u", Command: "unstage-file", Context: "git-status-diff"},
+		{Key: "s", Command: "stage-hunk", Context: "git-status-diff"},
+		{Key: "u", Command: "unstage-hunk", Context: "git-status-diff"},
+		{Key: "D", Command: "discard-hunk", Context: "git-status-diff"},
+		{Key: "V", Command: "select-lines", Context: "git-status-diff"},
+		{Key: "n", Command: "next-hunk", Context: "git-status-diff"},
+		{Key: "N", Command: "prev-hunk", Context: "git-status-diff"},
+		{Key: "ctrl+z", Command: "undo-discard", Context: "git-status-diff"},
 		{Key: "v", Command: "toggle-diff-view", Context: "git-status-diff"},
 		{Key: "\\", Command: "toggle-sidebar", Context: "git-status-diff"},
 		{Key: "w", Command: "toggle-wrap", Context: "git-status-diff"},
@@ -140,8 +157,13 @@ func DefaultBindings() []Binding {
 		{Key: "up", Command: "scroll-up", Context: "git-diff"},
 		{Key: "ctrl+d", Command: "page-down", Context: "git-diff"},
 		{Key: "ctrl+u", Command: "page-up", Context: "git-diff"},
-		{Key: "s", Command: "stage-file", Context: "git-diff"},
-		{Key: "u", Command: "unstage-file", Context: "git-diff"},
+		{Key: "s", Command: "stage-hunk", Context: "git-diff"},
+		{Key: "u", Command: "unstage-hunk", Context: "git-diff"},
+		{Key: "D", Command: "discard-hunk", Context: "git-diff"},
+		{Key: "V", Command: "select-lines", Context: "git-diff"},
+		{Key: "n", Command: "next-hunk", Context: "git-diff"},
+		{Key: "N", Command: "prev-hunk", Context: "git-diff"},
+		{Key: "ctrl+z", Command: "undo-discard", Context: "git-diff"},
 		{Key: "[", Command: "prev-file", Context: "git-diff"},
 		{Key: "]", Command: "next-file", Context: "git-diff"},
 		{Key: "y", Command: "yank-diff", Context: "git-diff"},
@@ -175,13 +197,88 @@ func DefaultBindings() []Binding {
 
 		// Git error modal context
 		{Key: "L", Command: "pull-from-error", Context: "git-error"},
+		{Key: "r", Command: "resolve-conflicts", Context: "git-error"},
+		{Key: "a", Command: "abort-operation", Context: "git-error"},
 		{Key: "y", Command: "yank-error", Context: "git-error"},
 		{Key: "esc", Command: "dismiss", Context: "git-error"},
 
 		// Git pull conflict context
+		{Key: "r", Command: "resolve-conflicts", Context: "git-pull-conflict"},
 		{Key: "a", Command: "abort-pull", Context: "git-pull-conflict"},
 		{Key: "esc", Command: "dismiss", Context: "git-pull-conflict"},
 
+		// Git interactive rebase editor context
+		{Key: "enter", Command: "start-rebase", Context: "git-rebase"},
+		{Key: "p", Command: "rebase-pick", Context: "git-rebase"},
+		{Key: "r", Command: "rebase-reword", Context: "git-rebase"},
+		{Key: "e", Command: "rebase-edit", Context: "git-rebase"},
+		{Key: "s", Command: "rebase-squash", Context: "git-rebase"},
+		{Key: "f", Command: "rebase-fixup", Context: "git-rebase"},
+		{Key: "d", Command: "rebase-drop", Context: "git-rebase"},
+		{Key: "J", Command: "move-down", Context: "git-rebase"},
+		{Key: "K", Command: "move-up", Context: "git-rebase"},
+		{Key: "esc", Command: "cancel", Context: "git-rebase"},
+
+		// Git rebase reword context
+		{Key: "ctrl+s", Command: "save-message", Context: "git-rebase-reword"},
+		{Key: "esc", Command: "cancel", Context: "git-rebase-reword"},
+
+		// Git stopped rebase context
+		{Key: "c", Command: "continue-rebase", Context: "git-rebase-status"},
+		{Key: "r", Command: "resolve-conflicts", Context: "git-rebase-status"},
+		{Key: "a", Command: "abort-rebase", Context: "git-rebase-status"},
+		{Key: "esc", Command: "dismiss", Context: "git-rebase-status"},
+
+		// Git cherry-pick target picker context
+		{Key: "enter", Command: "confirm", Context: "git-cherry-pick"},
+		{Key: "esc", Command: "cancel", Context: "git-cherry-pick"},
+
+		// Git revert confirmation context
+		{Key: "y", Command: "confirm", Context: "git-revert"},
+		{Key: "esc", Command: "cancel", Context: "git-revert"},
+
+		// Git reset context
+		{Key: "enter", Command: "confirm", Context: "git-reset"},
+		{Key: "s", Command: "reset-soft", Context: "git-reset"},
+		{Key: "m", Command: "reset-mixed", Context: "git-reset"},
+		{Key: "h", Command: "reset-hard", Context: "git-reset"},
+		{Key: "esc", Command: "cancel", Context: "git-reset"},
+
+		// Git reflog browser context
+		{Key: "enter", Command: "restore-entry", Context: "git-reflog"},
+		{Key: "r", Command: "restore-entry", Context: "git-reflog"},
+		{Key: "u", Command: "undo-action", Context: "git-reflog"},
+		{Key: "tab", Command: "next-ref", Context: "git-reflog"},
+		{Key: "y", Command: "yank-id", Context: "git-reflog"},
+		{Key: "ctrl+d", Command: "scroll", Context: "git-reflog"},
+		{Key: "esc", Command: "close", Context: "git-reflog"},
+
+		// Git undo confirmation context
+		{Key: "y", Command: "confirm", Context: "git-undo"},
+		{Key: "esc", Command: "cancel", Context: "git-undo"},
+
+		// Git move branch confirmation context
+		{Key: "y", Command: "confirm", Context: "git-move-branch"},
+		{Key: "esc", Command: "cancel", Context: "git-move-branch"},
+
+		// Git conflict resolution context
+		{Key: "o", Command: "take-ours", Context: "git-conflicts"},
+		{Key: "t", Command: "take-theirs", Context: "git-conflicts"},
+		{Key: "b", Command: "take-both", Context: "git-conflicts"},
+		{Key: "e", Command: "edit-result", Context: "git-conflicts"},
+		{Key: "u", Command: "reset-conflict", Context: "git-conflicts"},
+		{Key: "n", Command: "next-conflict", Context: "git-conflicts"},
+		{Key: "]", Command: "next-conflict-file", Context: "git-conflicts"},
+		{Key: "s", Command: "save-resolution", Context: "git-conflicts"},
+		{Key: "O", Command: "take-file-ours", Context: "git-conflicts"},
+		{Key: "T", Command: "take-file-theirs", Context: "git-conflicts"},
+		{Key: "c", Command: "continue-merge", Context: "git-conflicts"},
+		{Key: "esc", Command: "close", Context: "git-conflicts"},
+
+		// Git conflict result editor context
+		{Key: "ctrl+s", Command: "apply-edit", Context: "git-conflicts-edit"},
+		{Key: "esc", Command: "cancel", Context: "git-conflicts-edit"},
+
 		// Git stash pop context
 		{Key: "y", Command: "confirm-pop", Context: "git-stash-pop"},
 		{Key: "esc", Command: "dismiss", Context: "git-stash-pop"},
@@ -238,6 +335,16 @@ func DefaultBindings() []Binding {
 		{Key: "y", Command: "yank-details", Context: "conversations-main"},
 		{Key: "Y", Command: "yank-resume", Context: "conversations-main"},
 		{Key: "R", Command: "resume-in-workspace", Context: "conversations-main"},
+		{Key: "E", Command: "export-session", Context: "conversations-main"},
+
+		// Conversations usage dashboard context
+		{Key: "esc", Command: "back", Context: "analytics"},
+		{Key: "t", Command: "cycle-range", Context: "analytics"},
+		{Key: "d", Command: "cycle-interval", Context: "analytics"},
+		{Key: "p", Command: "toggle-projects", Context: "analytics"},
+		{Key: "r", Command: "refresh", Context: "analytics"},
+		{Key: "x", Command: "export-csv", Context: "analytics"},
+		{Key: "X", Command: "export-json", Context: "analytics"},
 
 		// File browser tree context
 		{Key: "tab", Command: "switch-pane", Context: "file-browser-tree"},
@@ -355,6 +462,7 @@ func DefaultBindings() []Binding {
 		{Key: "N", Command: "reject", Context: "workspace-list"},
 		{Key: "K", Command: "kill-shell", Context: "workspace-list"},
 		{Key: "O", Command: "open-in-git", Context: "workspace-list"},
+		{Key: "M", Command: "toggle-mute", Context: "workspace-list"},
 		{Key: "l", Command: "focus-right", Context: "workspace-list"},
 		{Key: "right", Command: "focus-right", Context: "workspace-list"},
 		{Key: "tab", Command: "switch-pane", Context: "workspace-list"},
@@ -363,6 +471,7 @@ func DefaultBindings() []Binding {
 		{Key: "[", Command: "prev-tab", Context: "workspace-list"},
 		{Key: "]", Command: "next-tab", Context: "workspace-list"},
 		{Key: "F", Command: "fetch-pr", Context: "workspace-list"},
+		{Key: "Q", Command: "merge-queue", Context: "workspace-list"},
 		{Key: "alt+!", Command: "switch-1", Context: "workspace-list"},
 		{Key: "alt+@", Command: "switch-2", Context: "workspace-list"},
 		{Key: "alt+#", Command: "switch-3", Context: "workspace-list"},
@@ -377,6 +486,18 @@ func DefaultBindings() []Binding {
 		{Key: "esc", Command: "cancel", Context: "workspace-fetch-pr"},
 		{Key: "enter", Command: "fetch", Context: "workspace-fetch-pr"},
 
+		// Workspace merge queue context
+		{Key: "esc", Command: "cancel", Context: "workspace-merge-queue"},
+		{Key: "enter", Command: "start", Context: "workspace-merge-queue"},
+		{Key: "space", Command: "toggle", Context: "workspace-merge-queue"},
+		{Key: "r", Command: "retry", Context: "workspace-merge-queue"},
+		{Key: "esc", Command: "cancel", Context: "workspace-merge-queue-input"},
+		{Key: "enter", Command: "confirm", Context: "workspace-merge-queue-input"},
+
+		// Workspace log search context
+		{Key: "esc", Command: "cancel", Context: "workspace-log-search"},
+		{Key: "enter", Command: "search", Context: "workspace-log-search"},
+
 		// Workspace preview context
 		{Key: "h", Command: "focus-left", Context: "workspace-preview"},
 		{Key: "left", Command: "focus-left", Context: "workspace-preview"},
@@ -393,6 +514,7 @@ func DefaultBindings() []Binding {
 		{Key: "\\", Command: "toggle-sidebar", Context: "workspace-preview"},
 		{Key: "[", Command: "prev-tab", Context: "workspace-preview"},
 		{Key: "]", Command: "next-tab", Context: "workspace-preview"},
+		{Key: "/", Command: "search-log", Context: "workspace-preview"},
 		{Key: "j", Command: "scroll-down", Context: "workspace-preview"},
 		{Key: "k", Command: "scroll-up", Context: "workspace-preview"},
 		{Key: "ctrl+d", Command: "page-down", Context: "workspace-preview"},
@@ -410,6 +532,7 @@ func DefaultBindings() []Binding {
 		// Workspace merge error context
 		{Key: "esc", Command: "dismiss-merge-error", Context: "workspace-merge-error"},
 		{Key: "y", Command: "yank-merge-error", Context: "workspace-merge-error"},
+		{Key: "c", Command: "resolve-merge-conflicts", Context: "workspace-merge-error"},
 
 		// Workspace interactive context bindings are registered dynamically
 		// by the workspace plugin Init() to reflect configured keys.
@@ -0,0 +1,299 @@
+package keymap
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// DefaultChordTimeout is how long a user chord waits for its next key.
+	DefaultChordTimeout = time.Second
+
+	// DefaultLeader is the key "<leader>" stands for when no leader is set.
+	DefaultLeader = "space"
+
+	// LeaderToken is replaced by the leader key in user override keys.
+	LeaderToken = "<leader>"
+)
+
+// Resolution is the result of resolving a key press against user overrides.
+type Resolution struct {
+	// Keys replace the pressed key. They are the default keys of the
+	// command the override names, so the plugin sees the key it handles.
+	// Empty (and Cmd nil) when the key is consumed, e.g. an unbound key.
+	Keys []tea.KeyMsg
+
+	// Cmd is the handler of the resolved command, when it has one.
+	Cmd tea.Cmd
+
+	// Pending reports that the key started or extended a chord. ChordID
+	// identifies the chord for ExpireChord.
+	Pending bool
+	ChordID int
+}
+
+// Continuation is a key that continues the pending chord.
+type Continuation struct {
+	Key     string // next key
+	Command string // command ID, or "" when more keys follow
+}
+
+// Resolve applies user overrides and chords to a key press. It returns false
+// when no override applies and the key should be handled as usual.
+func (r *Registry) Resolve(key tea.KeyMsg, activeContext string) (Resolution, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keyStr := keyToString(key)
+
+	if len(r.chord) > 0 && (activeContext != r.chordContext || time.Since(r.chordTime) >= r.chordTimeout) {
+		r.chord = nil
+	}
+	if len(r.chord) > 0 {
+		if key.Type == tea.KeyEsc {
+			r.chord = nil
+			return Resolution{}, true
+		}
+		seq := strings.Join(r.chord, " ") + " " + keyStr
+		r.chord = nil
+		if r.hasOverridePrefix(seq, activeContext) {
+			return r.startChord(seq, activeContext), true
+		}
+		if cmdID, ok := r.overrideFor(seq, activeContext); ok {
+			res, _ := r.resolveCommand(cmdID, activeContext)
+			return res, true
+		}
+		// Not a continuation: drop the chord and handle the key on its own
+	}
+
+	if r.hasOverridePrefix(keyStr, activeContext) {
+		return r.startChord(keyStr, activeContext), true
+	}
+	cmdID, ok := r.overrideFor(keyStr, activeContext)
+	if !ok {
+		return Resolution{}, false
+	}
+	return r.resolveCommand(cmdID, activeContext)
+}
+
+// ExpireChord ends the chord with the given ID after its timeout. If the keys
+// typed so far are themselves bound, that binding is resolved, as in vim.
+func (r *Registry) ExpireChord(id int, activeContext string) (Resolution, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id != r.chordID || len(r.chord) == 0 {
+		return Resolution{}, false
+	}
+	seq := strings.Join(r.chord, " ")
+	r.chord = nil
+	if activeContext != r.chordContext {
+		return Resolution{}, false
+	}
+	cmdID, ok := r.overrideFor(seq, activeContext)
+	if !ok {
+		return Resolution{}, false
+	}
+	return r.resolveCommand(cmdID, activeContext)
+}
+
+// PendingChord returns the keys of the pending chord and the keys that can
+// follow it, or "" when no chord is pending.
+func (r *Registry) PendingChord(activeContext string) (string, []Continuation) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.chord) == 0 || activeContext != r.chordContext || time.Since(r.chordTime) >= r.chordTimeout {
+		return "", nil
+	}
+	prefix := strings.Join(r.chord, " ")
+
+	next := make(map[string]string)
+	for key := range r.overridesIn(activeContext) {
+		rest, ok := strings.CutPrefix(key, prefix+" ")
+		if !ok {
+			continue
+		}
+		cmdID, _ := r.overrideFor(key, activeContext)
+		if !r.applies(cmdID, activeContext) {
+			continue
+		}
+		first, more, _ := strings.Cut(rest, " ")
+		if more == "" {
+			next[first] = cmdID
+		} else if _, ok := next[first]; !ok {
+			next[first] = ""
+		}
+	}
+
+	conts := make([]Continuation, 0, len(next))
+	for key, cmdID := range next {
+		conts = append(conts, Continuation{Key: key, Command: cmdID})
+	}
+	sort.Slice(conts, func(i, j int) bool { return conts[i].Key < conts[j].Key })
+	return prefix, conts
+}
+
+// ChordTimeout returns how long a user chord waits for its next key.
+func (r *Registry) ChordTimeout() time.Duration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.chordTimeout
+}
+
+// startChord records a pending chord. Caller must hold the lock.
+func (r *Registry) startChord(seq, activeContext string) Resolution {
+	r.chord = strings.Fields(seq)
+	r.chordContext = activeContext
+	r.chordTime = time.Now()
+	r.chordID++
+	return Resolution{Pending: true, ChordID: r.chordID}
+}
+
+// overrideFor returns the command a user override binds to key in a context.
+// Context overrides take precedence over global ones.
+func (r *Registry) overrideFor(key, activeContext string) (string, bool) {
+	if cmdID, ok := r.contextOverrides[activeContext][key]; ok {
+		return cmdID, true
+	}
+	cmdID, ok := r.userOverrides[key]
+	return cmdID, ok
+}
+
+// overridesIn returns every override key visible in a context.
+func (r *Registry) overridesIn(activeContext string) map[string]bool {
+	keys := make(map[string]bool)
+	for key := range r.userOverrides {
+		keys[key] = true
+	}
+	for key := range r.contextOverrides[activeContext] {
+		keys[key] = true
+	}
+	return keys
+}
+
+// hasOverridePrefix reports whether a longer override that applies in the
+// context starts with the given keys.
+func (r *Registry) hasOverridePrefix(prefix, activeContext string) bool {
+	for key := range r.overridesIn(activeContext) {
+		if !strings.HasPrefix(key, prefix+" ") {
+			continue
+		}
+		if cmdID, _ := r.overrideFor(key, activeContext); r.applies(cmdID, activeContext) {
+			return true
+		}
+	}
+	return false
+}
+
+// applies reports whether an override to cmdID can do anything in a context:
+// unbind the key, run a handler, or send the command's default key.
+func (r *Registry) applies(cmdID, activeContext string) bool {
+	if cmdID == "" {
+		return true
+	}
+	if cmd, ok := r.commands[cmdID]; ok && cmd.Handler != nil {
+		return true
+	}
+	return r.defaultKey(cmdID, activeContext) != ""
+}
+
+// resolveCommand turns a command ID into its handler or its default keys in
+// the context. It returns false when the command is not bound there.
+func (r *Registry) resolveCommand(cmdID, activeContext string) (Resolution, bool) {
+	if cmdID == "" {
+		return Resolution{}, true
+	}
+	if cmd, ok := r.commands[cmdID]; ok && cmd.Handler != nil {
+		return Resolution{Cmd: cmd.Handler()}, true
+	}
+	keys, ok := parseKeys(r.defaultKey(cmdID, activeContext))
+	if !ok {
+		return Resolution{}, false
+	}
+	return Resolution{Keys: keys}, true
+}
+
+// defaultKey returns the first registered key for a command in the context,
+// falling back to global bindings.
+func (r *Registry) defaultKey(cmdID, activeContext string) string {
+	for _, ctx := range []string{activeContext, "global"} {
+		for _, b := range r.bindings[ctx] {
+			if b.Command == cmdID {
+				return b.Key
+			}
+		}
+	}
+	return ""
+}
+
+// normalizeKey expands the leader token and collapses whitespace in a key
+// sequence. Caller must hold the lock.
+func (r *Registry) normalizeKey(key string) string {
+	leader := r.leader
+	if leader == "" {
+		leader = DefaultLeader
+	}
+	fields := strings.Fields(key)
+	for i, f := range fields {
+		if strings.EqualFold(f, LeaderToken) {
+			fields[i] = leader
+		}
+	}
+	return strings.Join(fields, " ")
+}
+
+// namedKeys maps key names, as produced by keyToString, to key types.
+var namedKeys = func() map[string]tea.KeyType {
+	names := make(map[string]tea.KeyType)
+	// Key types are small integers: control codes are 0-127, special keys
+	// are negative.
+	for t := tea.KeyType(-128); t <= 127; t++ {
+		if t == tea.KeyRunes {
+			continue
+		}
+		name := keyToString(tea.KeyMsg{Type: t})
+		if _, dup := names[name]; name != "" && !dup {
+			names[name] = t
+		}
+	}
+	return names
+}()
+
+// parseKeys parses a space-separated key sequence into key messages.
+func parseKeys(seq string) ([]tea.KeyMsg, bool) {
+	fields := strings.Fields(seq)
+	if len(fields) == 0 {
+		return nil, false
+	}
+	keys := make([]tea.KeyMsg, 0, len(fields))
+	for _, f := range fields {
+		key, ok := parseKey(f)
+		if !ok {
+			return nil, false
+		}
+		keys = append(keys, key)
+	}
+	return keys, true
+}
+
+// parseKey parses a single key name such as "x", "ctrl+k", "space" or "alt+j".
+func parseKey(name string) (tea.KeyMsg, bool) {
+	alt := false
+	if rest, ok := strings.CutPrefix(name, "alt+"); ok && rest != "" {
+		alt, name = true, rest
+	}
+	if name == "space" {
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}, Alt: alt}, true
+	}
+	if t, ok := namedKeys[name]; ok {
+		return tea.KeyMsg{Type: t, Alt: alt}, true
+	}
+	if runes := []rune(name); len(runes) == 1 {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: runes, Alt: alt}, true
+	}
+	return tea.KeyMsg{}, false
+}
@@ -0,0 +1,174 @@
+package keymap
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func runeKey(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func gitStatusRegistry() *Registry {
+	r := NewRegistry()
+	r.RegisterBinding(Binding{Key: "g g", Command: "cursor-top", Context: "global"})
+	r.RegisterBinding(Binding{Key: "s", Command: "stage-file", Context: "git-status"})
+	r.RegisterBinding(Binding{Key: "c", Command: "commit", Context: "git-status"})
+	r.RegisterBinding(Binding{Key: "d", Command: "show-diff", Context: "git-status"})
+	return r
+}
+
+func TestResolve_ContextOverrideSendsDefaultKey(t *testing.T) {
+	r := gitStatusRegistry()
+	r.SetContextOverride("git-status", "ctrl+k", "commit")
+
+	res, ok := r.Resolve(tea.KeyMsg{Type: tea.KeyCtrlK}, "git-status")
+	if !ok || len(res.Keys) != 1 || res.Keys[0].String() != "c" {
+		t.Fatalf("ctrl+k in git-status = %+v, %v", res, ok)
+	}
+
+	// Other contexts are unaffected
+	if _, ok := r.Resolve(tea.KeyMsg{Type: tea.KeyCtrlK}, "file-browser-tree"); ok {
+		t.Error("context override should not apply elsewhere")
+	}
+	// Keys without overrides pass through
+	if _, ok := r.Resolve(runeKey("s"), "git-status"); ok {
+		t.Error("plain key should pass through")
+	}
+}
+
+func TestResolve_GlobalOverrideOnlyWhereCommandIsBound(t *testing.T) {
+	r := gitStatusRegistry()
+	r.SetUserOverride("ctrl+t", "cursor-top")
+	r.SetUserOverride("x", "show-diff")
+
+	res, ok := r.Resolve(tea.KeyMsg{Type: tea.KeyCtrlT}, "file-browser-tree")
+	if !ok || len(res.Keys) != 2 || res.Keys[0].String() != "g" || res.Keys[1].String() != "g" {
+		t.Errorf("sequence default keys = %+v, %v", res, ok)
+	}
+	if _, ok := r.Resolve(runeKey("x"), "file-browser-tree"); ok {
+		t.Error("override to a command not bound here should pass through")
+	}
+	if res, ok := r.Resolve(runeKey("x"), "git-status"); !ok || res.Keys[0].String() != "d" {
+		t.Errorf("x in git-status = %+v, %v", res, ok)
+	}
+}
+
+func TestResolve_LeaderChord(t *testing.T) {
+	r := gitStatusRegistry()
+	r.SetLeader(",")
+	r.SetContextOverride("git-status", "<leader> g s", "stage-file")
+	r.SetContextOverride("git-status", "<leader> g c", "commit")
+
+	res, ok := r.Resolve(runeKey(","), "git-status")
+	if !ok || !res.Pending {
+		t.Fatalf("leader should start a chord, got %+v", res)
+	}
+	if res, _ := r.Resolve(runeKey("g"), "git-status"); !res.Pending {
+		t.Fatal("g should extend the chord")
+	}
+
+	prefix, conts := r.PendingChord("git-status")
+	if prefix != ", g" || len(conts) != 2 || conts[0].Key != "c" || conts[0].Command != "commit" {
+		t.Errorf("pending chord = %q %+v", prefix, conts)
+	}
+
+	res, ok = r.Resolve(runeKey("s"), "git-status")
+	if !ok || len(res.Keys) != 1 || res.Keys[0].String() != "s" {
+		t.Errorf("completed chord = %+v, %v", res, ok)
+	}
+	if prefix, _ := r.PendingChord("git-status"); prefix != "" {
+		t.Error("chord should be cleared after completing")
+	}
+}
+
+func TestResolve_ChordCancelAndUnknownContinuation(t *testing.T) {
+	r := gitStatusRegistry()
+	r.SetUserOverride("space c", "commit")
+
+	r.Resolve(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}, "git-status")
+	if res, ok := r.Resolve(tea.KeyMsg{Type: tea.KeyEsc}, "git-status"); !ok || len(res.Keys) != 0 || res.Pending {
+		t.Errorf("esc should cancel the chord, got %+v", res)
+	}
+
+	// An unknown continuation drops the chord and handles the key alone
+	r.Resolve(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}, "git-status")
+	if _, ok := r.Resolve(runeKey("s"), "git-status"); ok {
+		t.Error("s should pass through after an unknown continuation")
+	}
+}
+
+func TestExpireChord_FiresExactMatch(t *testing.T) {
+	r := gitStatusRegistry()
+	r.SetContextOverride("git-status", "z", "show-diff")
+	r.SetContextOverride("git-status", "z z", "commit")
+
+	res, _ := r.Resolve(runeKey("z"), "git-status")
+	if !res.Pending {
+		t.Fatal("z should wait for a possible second z")
+	}
+	if _, ok := r.ExpireChord(res.ChordID+1, "git-status"); ok {
+		t.Error("a stale timeout should be ignored")
+	}
+	got, ok := r.ExpireChord(res.ChordID, "git-status")
+	if !ok || len(got.Keys) != 1 || got.Keys[0].String() != "d" {
+		t.Errorf("expired chord = %+v, %v", got, ok)
+	}
+}
+
+func TestResolve_UnbindConsumesKey(t *testing.T) {
+	r := gitStatusRegistry()
+	r.SetContextOverride("git-status", "s", "")
+
+	res, ok := r.Resolve(runeKey("s"), "git-status")
+	if !ok || len(res.Keys) != 0 || res.Cmd != nil {
+		t.Errorf("unbound key = %+v, %v", res, ok)
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	for _, name := range []string{"x", "G", "ctrl+k", "enter", "esc", "tab", "shift+tab", "up", "pgdown", "space"} {
+		key, ok := parseKey(name)
+		if !ok || keyToString(key) != name {
+			t.Errorf("parseKey(%q) = %+v (%q), %v", name, key, keyToString(key), ok)
+		}
+	}
+	if key, ok := parseKey("alt+j"); !ok || !key.Alt || key.String() != "alt+j" {
+		t.Errorf("parseKey(alt+j) = %+v", key)
+	}
+	if _, ok := parseKey("hyper+q"); ok {
+		t.Error("unknown key names should not parse")
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	r := gitStatusRegistry()
+	r.SetContextOverride("git-status", "s", "commit")           // shadows stage-file
+	r.SetContextOverride("git-status", "c", "commit")           // same command: fine
+	r.SetContextOverride("git-status", "d", "")                 // deliberate unbind: fine
+	r.SetContextOverride("git-status", "ctrl+x", "no-such")     // never fires
+	r.SetContextOverride("file-browser-tree", "g x", "refresh") // not bound there
+	r.SetUserOverride("g h", "cursor-top")                      // next to g g: hides nothing
+
+	var got []string
+	for _, c := range r.Conflicts() {
+		got = append(got, c.String())
+	}
+	want := []string{
+		"file-browser-tree: g x → refresh is not bound in this context",
+		"git-status: ctrl+x → no-such is not bound in this context",
+		"git-status: s → commit shadows stage-file",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("conflicts:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// A chord whose first key is bound hides that binding
+	r = gitStatusRegistry()
+	r.SetContextOverride("git-status", "d x", "commit")
+	if cs := r.Conflicts(); len(cs) != 1 || cs[0].ShadowedKey != "d" || cs[0].Shadowed != "show-diff" {
+		t.Errorf("chord prefix conflicts = %+v", cs)
+	}
+}
@@ -0,0 +1,129 @@
+package keymap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Conflict describes a user override that hides a registered binding or that
+// can never fire.
+type Conflict struct {
+	Context     string
+	Key         string // override key
+	Command     string // command the override binds
+	ShadowedKey string // key of the hidden binding; differs from Key for chord prefixes
+	Shadowed    string // command of the hidden binding; "" when Command is not bound
+}
+
+// String formats the conflict for display.
+func (c Conflict) String() string {
+	switch {
+	case c.Shadowed == "":
+		return fmt.Sprintf("%s: %s → %s is not bound in this context", c.Context, c.Key, c.Command)
+	case c.ShadowedKey != c.Key:
+		return fmt.Sprintf("%s: %s → %s shadows %s → %s", c.Context, c.Key, c.Command, c.ShadowedKey, c.Shadowed)
+	default:
+		return fmt.Sprintf("%s: %s → %s shadows %s", c.Context, c.Key, c.Command, c.Shadowed)
+	}
+}
+
+// Conflicts reports user overrides that shadow registered bindings, either
+// directly or because a chord starts with a bound key, and context overrides
+// naming commands that are not bound in their context. Global overrides are
+// checked in every context where their command is bound. Unbinding a key is
+// deliberate and not reported.
+func (r *Registry) Conflicts() []Conflict {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	contexts := map[string]bool{"global": true}
+	for ctx := range r.bindings {
+		contexts[ctx] = true
+	}
+	for ctx := range r.contextOverrides {
+		contexts[ctx] = true
+	}
+
+	var conflicts []Conflict
+	for ctx := range contexts {
+		for key, cmdID := range r.contextOverrides[ctx] {
+			conflicts = append(conflicts, r.overrideConflicts(ctx, key, cmdID, true)...)
+		}
+		for key, cmdID := range r.userOverrides {
+			if _, ok := r.contextOverrides[ctx][key]; ok {
+				continue
+			}
+			if !r.applies(cmdID, ctx) && (ctx != "global" || r.appliesAnywhere(cmdID)) {
+				continue
+			}
+			conflicts = append(conflicts, r.overrideConflicts(ctx, key, cmdID, ctx == "global")...)
+		}
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool {
+		if conflicts[i].Context != conflicts[j].Context {
+			return conflicts[i].Context < conflicts[j].Context
+		}
+		return conflicts[i].Key < conflicts[j].Key
+	})
+	return conflicts
+}
+
+// overrideConflicts checks one override in one context. withGlobal also
+// checks global bindings, which the context falls back to.
+func (r *Registry) overrideConflicts(ctx, key, cmdID string, withGlobal bool) []Conflict {
+	if cmdID == "" {
+		return nil
+	}
+	if !r.applies(cmdID, ctx) {
+		return []Conflict{{Context: ctx, Key: key, Command: cmdID}}
+	}
+
+	var conflicts []Conflict
+	fields := strings.Fields(key)
+	for n := 1; n <= len(fields); n++ {
+		prefix := strings.Join(fields[:n], " ")
+		if n < len(fields) {
+			if _, ok := r.overrideFor(prefix, ctx); ok {
+				continue
+			}
+		}
+		if b, ok := r.bindingFor(prefix, ctx, withGlobal); ok && b.Command != cmdID {
+			conflicts = append(conflicts, Conflict{
+				Context:     ctx,
+				Key:         key,
+				Command:     cmdID,
+				ShadowedKey: prefix,
+				Shadowed:    b.Command,
+			})
+		}
+	}
+	return conflicts
+}
+
+// appliesAnywhere reports whether an override to cmdID applies in any context.
+func (r *Registry) appliesAnywhere(cmdID string) bool {
+	for ctx := range r.bindings {
+		if r.applies(cmdID, ctx) {
+			return true
+		}
+	}
+	return false
+}
+
+// bindingFor returns the registered binding for a key in a context.
+func (r *Registry) bindingFor(key, ctx string, withGlobal bool) (Binding, bool) {
+	contexts := []string{ctx}
+	if withGlobal && ctx != "global" {
+		contexts = append(contexts, "global")
+	}
+	for _, c := range contexts {
+		for _, b := range r.bindings[c] {
+			if b.Key == key {
+				return b, true
+			}
+		}
+	}
+	return Binding{}, false
+}
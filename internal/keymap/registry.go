@@ -1,6 +1,7 @@
 package keymap
 
 import (
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -12,10 +13,11 @@ const sequenceTimeout = 500 * time.Millisecond
 
 // Command represents a registered command handler.
 type Command struct {
-	ID      string
-	Name    string
-	Handler func() tea.Cmd
-	Context string
+	ID          string
+	Name        string
+	Description string
+	Handler     func() tea.Cmd
+	Context     string
 }
 
 // Binding maps a key or key sequence to a command.
@@ -27,20 +29,29 @@ type Binding struct {
 
 // Registry manages key bindings and command dispatch.
 type Registry struct {
-	commands      map[string]Command  // ID -> Command
-	bindings      map[string][]Binding // context -> bindings
-	userOverrides map[string]string   // key -> command ID
-	pendingKey    string
-	pendingTime   time.Time
-	mu            sync.RWMutex
+	commands         map[string]Command           // ID -> Command
+	bindings         map[string][]Binding         // context -> bindings
+	userOverrides    map[string]string            // key -> command ID
+	contextOverrides map[string]map[string]string // context -> key -> command ID
+	leader           string
+	chordTimeout     time.Duration
+	chord            []string // keys of the user chord typed so far
+	chordContext     string
+	chordTime        time.Time
+	chordID          int
+	pendingKey       string
+	pendingTime      time.Time
+	mu               sync.RWMutex
 }
 
 // NewRegistry creates a new keymap registry.
 func NewRegistry() *Registry {
 	return &Registry{
-		commands:      make(map[string]Command),
-		bindings:      make(map[string][]Binding),
-		userOverrides: make(map[string]string),
+		commands:         make(map[string]Command),
+		bindings:         make(map[string][]Binding),
+		userOverrides:    make(map[string]string),
+		contextOverrides: make(map[string]map[string]string),
+		chordTimeout:     DefaultChordTimeout,
 	}
 }
 
@@ -51,6 +62,31 @@ func (r *Registry) RegisterCommand(cmd Command) {
 	r.commands[cmd.ID] = cmd
 }
 
+// UnregisterCommands removes the commands whose IDs start with prefix, and
+// their bindings.
+func (r *Registry) UnregisterCommands(prefix string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id := range r.commands {
+		if strings.HasPrefix(id, prefix) {
+			delete(r.commands, id)
+		}
+	}
+	for ctx, bindings := range r.bindings {
+		kept := bindings[:0:0]
+		for _, b := range bindings {
+			if !strings.HasPrefix(b.Command, prefix) {
+				kept = append(kept, b)
+			}
+		}
+		if len(kept) == 0 {
+			delete(r.bindings, ctx)
+		} else {
+			r.bindings[ctx] = kept
+		}
+	}
+}
+
 // RegisterBinding adds a key binding.
 func (r *Registry) RegisterBinding(b Binding) {
 	r.mu.Lock()
@@ -64,11 +100,45 @@ func (r *Registry) RegisterPluginBinding(key, command, context string) {
 	r.RegisterBinding(Binding{Key: key, Command: command, Context: context})
 }
 
-// SetUserOverride sets a user-configured key override.
+// SetUserOverride sets a user-configured key override that applies in every
+// context. An empty command ID unbinds the key.
 func (r *Registry) SetUserOverride(key, commandID string) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.userOverrides[key] = commandID
+	r.userOverrides[r.normalizeKey(key)] = commandID
+}
+
+// SetContextOverride sets a user-configured key override for one context.
+// The "global" context is the same as SetUserOverride.
+func (r *Registry) SetContextOverride(context, key, commandID string) {
+	if context == "" || context == "global" {
+		r.SetUserOverride(key, commandID)
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.contextOverrides[context] == nil {
+		r.contextOverrides[context] = make(map[string]string)
+	}
+	r.contextOverrides[context][r.normalizeKey(key)] = commandID
+}
+
+// SetLeader sets the key that "<leader>" stands for in overrides. Set it
+// before adding overrides that use it.
+func (r *Registry) SetLeader(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.leader = key
+}
+
+// SetChordTimeout sets how long a user chord waits for its next key.
+func (r *Registry) SetChordTimeout(d time.Duration) {
+	if d <= 0 {
+		d = DefaultChordTimeout
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.chordTimeout = d
 }
 
 // Handle dispatches a key event to the appropriate command handler.
@@ -181,6 +251,26 @@ func (r *Registry) GetCommand(id string) (Command, bool) {
 	return cmd, ok
 }
 
+// Commands returns all registered commands, sorted by ID.
+func (r *Registry) Commands() []Command {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	cmds := make([]Command, 0, len(r.commands))
+	for _, cmd := range r.commands {
+		cmds = append(cmds, cmd)
+	}
+	sort.Slice(cmds, func(i, j int) bool { return cmds[i].ID < cmds[j].ID })
+	return cmds
+}
+
+// BindingFor returns the binding for a key in a context, falling back to
+// global bindings.
+func (r *Registry) BindingFor(key, context string) (Binding, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.bindingFor(key, context, true)
+}
+
 // BindingsForContext returns all bindings for a given context.
 func (r *Registry) BindingsForContext(context string) []Binding {
 	r.mu.RLock()
@@ -220,3 +220,26 @@ func TestRegistry_GetCommand(t *testing.T) {
 		t.Error("GetCommand should return false for missing command")
 	}
 }
+
+func TestRegistry_UnregisterCommands(t *testing.T) {
+	r := NewRegistry()
+	r.RegisterCommand(Command{ID: "custom:test", Handler: func() tea.Cmd { return nil }})
+	r.RegisterCommand(Command{ID: "quit", Handler: func() tea.Cmd { return nil }})
+	r.RegisterBinding(Binding{Key: "T", Command: "custom:test", Context: "git-status"})
+	r.RegisterBinding(Binding{Key: "q", Command: "quit", Context: "global"})
+
+	r.UnregisterCommands("custom:")
+
+	if _, ok := r.GetCommand("custom:test"); ok {
+		t.Error("custom command should be removed")
+	}
+	if cmds := r.Commands(); len(cmds) != 1 || cmds[0].ID != "quit" {
+		t.Errorf("Commands() = %+v", cmds)
+	}
+	if _, ok := r.BindingFor("T", "git-status"); ok {
+		t.Error("custom binding should be removed")
+	}
+	if b, ok := r.BindingFor("q", "git-status"); !ok || b.Command != "quit" {
+		t.Errorf("BindingFor should fall back to global, got %+v, %v", b, ok)
+	}
+}
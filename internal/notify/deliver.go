@@ -0,0 +1,166 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const commandTimeout = 10 * time.Second
+
+// send delivers a notification on every configured channel.
+func (n *Notifier) send(ev Event) {
+	if seq := n.terminalSequence(ev); seq != "" {
+		n.writeTerminal(seq)
+	}
+	if len(n.cfg.Command) > 0 {
+		if err := runCommand(n.cfg.Command, ev); err != nil {
+			n.logger.Warn("notifications: command failed", "error", err)
+		}
+	}
+	if n.cfg.WebhookURL != "" {
+		if err := n.postWebhook(ev); err != nil {
+			n.logger.Warn("notifications: webhook failed", "error", err)
+		}
+	}
+}
+
+// terminalSequence builds the bell and OSC escapes for an event. Inside tmux
+// the OSC is wrapped in a passthrough sequence so it reaches the outer
+// terminal (requires tmux's allow-passthrough option).
+func (n *Notifier) terminalSequence(ev Event) string {
+	var b strings.Builder
+	if n.cfg.Bell {
+		b.WriteString("\a")
+	}
+	var osc string
+	switch n.cfg.OSC {
+	case "9":
+		osc = "\x1b]9;" + sanitizeOSC(ev.Title()+": "+ev.Body()) + "\x07"
+	case "777":
+		osc = "\x1b]777;notify;" + sanitizeOSC(ev.Title()) + ";" + sanitizeOSC(ev.Body()) + "\x07"
+	}
+	if osc != "" && n.inTmux {
+		osc = "\x1bPtmux;" + strings.ReplaceAll(osc, "\x1b", "\x1b\x1b") + "\x1b\\"
+	}
+	b.WriteString(osc)
+	return b.String()
+}
+
+// writeTerminal writes to the controlling terminal rather than stdout, which
+// the TUI renderer owns.
+func (n *Notifier) writeTerminal(seq string) {
+	w := n.tty
+	if w == nil {
+		f, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+		if err != nil {
+			w = os.Stderr
+		} else {
+			defer func() { _ = f.Close() }()
+			w = f
+		}
+	}
+	_, _ = io.WriteString(w, seq)
+}
+
+// sanitizeOSC strips control characters and the ";" separator that would
+// end or split an OSC payload.
+func sanitizeOSC(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return ' '
+		}
+		if r == ';' {
+			return ','
+		}
+		return r
+	}, s)
+}
+
+// runCommand runs the notifier command with event placeholders substituted.
+func runCommand(argv []string, ev Event) error {
+	r := strings.NewReplacer(
+		"{title}", ev.Title(),
+		"{body}", ev.Body(),
+		"{status}", string(ev.Kind),
+		"{workspace}", ev.Workspace,
+		"{agent}", ev.Agent,
+	)
+	args := make([]string, len(argv))
+	for i, arg := range argv {
+		args[i] = r.Replace(arg)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, args[0], args[1:]...).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s: %w: %s", args[0], err, msg)
+		}
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+	return nil
+}
+
+// postWebhook sends the event as JSON.
+func (n *Notifier) postWebhook(ev Event) error {
+	payload := struct {
+		Event
+		Title string `json:"title"`
+		Body  string `json:"body"`
+	}{ev, ev.Title(), ev.Body()}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	resp, err := n.client.Post(n.cfg.WebhookURL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// checkWebhookRedirect validates every redirect hop like the configured URL,
+// so a local endpoint can't forward the payload off the machine.
+func checkWebhookRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	return ValidateWebhookURL(req.URL.String())
+}
+
+// ValidateWebhookURL checks that a webhook URL is http(s) on a loopback
+// host, so agent prompts never leave the machine.
+func ValidateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("webhook URL must be http or https: %s", raw)
+	}
+	host := u.Hostname()
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("webhook URL must point to localhost: %s", raw)
+}
@@ -0,0 +1,6 @@
+// Package notify alerts the user when a workspace agent changes status while
+// they may not be looking: it rings the terminal bell, emits OSC 9/777
+// desktop notification escapes, runs a notifier command such as notify-send,
+// or posts to a local webhook. Transitions are debounced per source so a
+// status that flickers doesn't notify.
+package notify
@@ -0,0 +1,237 @@
+package notify
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/marcus/sidecar/internal/config"
+)
+
+// Kind is a notifiable agent status.
+type Kind string
+
+const (
+	KindWaiting Kind = "waiting" // agent needs input
+	KindDone    Kind = "done"    // agent finished
+	KindError   Kind = "error"   // agent failed
+)
+
+const defaultDebounce = 3 * time.Second
+
+// Event describes a status transition of one worktree or shell.
+type Event struct {
+	Key       string    `json:"key"`       // stable source ID, e.g. "worktree:auth"
+	Workspace string    `json:"workspace"` // worktree or shell display name
+	Agent     string    `json:"agent"`     // agent type, e.g. "claude"
+	Kind      Kind      `json:"status"`    // empty for non-notifiable statuses
+	Detail    string    `json:"detail,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+// Title returns the notification title.
+func (e Event) Title() string {
+	return "sidecar: " + e.Workspace
+}
+
+// Body returns the notification text.
+func (e Event) Body() string {
+	agent := e.Agent
+	if agent == "" {
+		agent = "agent"
+	}
+	var body string
+	switch e.Kind {
+	case KindWaiting:
+		body = agent + " is waiting for input"
+	case KindDone:
+		body = agent + " finished"
+	case KindError:
+		body = agent + " hit an error"
+	default:
+		body = agent + " changed status"
+	}
+	if e.Detail != "" {
+		body += ": " + e.Detail
+	}
+	return body
+}
+
+// Notifier delivers debounced notifications for status transitions.
+type Notifier struct {
+	cfg         config.NotificationsConfig
+	debounce    time.Duration
+	on          map[Kind]bool
+	mutedAgents map[string]bool
+	logger      *slog.Logger
+	inTmux      bool
+
+	mu      sync.Mutex
+	last    map[string]Kind        // last observed kind per source
+	pending map[string]*time.Timer // scheduled notification per source
+	muted   map[string]bool        // sources muted at runtime
+	stopped bool
+
+	// deliver sends a notification; replaced in tests.
+	deliver func(Event)
+	tty     io.Writer
+	client  *http.Client
+}
+
+// New creates a notifier. inTmux says sidecar runs inside tmux, which needs
+// OSC escapes wrapped to pass them through; main unsets TMUX, so the caller
+// supplies it. An invalid webhook URL is dropped with a warning; other
+// channels keep working.
+func New(cfg config.NotificationsConfig, inTmux bool, logger *slog.Logger) *Notifier {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	n := &Notifier{
+		cfg:         cfg,
+		debounce:    cfg.Debounce,
+		on:          make(map[Kind]bool),
+		mutedAgents: make(map[string]bool),
+		logger:      logger,
+		inTmux:      inTmux,
+		last:        make(map[string]Kind),
+		pending:     make(map[string]*time.Timer),
+		muted:       make(map[string]bool),
+		client:      &http.Client{Timeout: 5 * time.Second, CheckRedirect: checkWebhookRedirect},
+	}
+	if n.debounce <= 0 {
+		n.debounce = defaultDebounce
+	}
+	if len(cfg.On) == 0 {
+		n.on[KindWaiting], n.on[KindDone], n.on[KindError] = true, true, true
+	}
+	for _, k := range cfg.On {
+		n.on[Kind(strings.ToLower(k))] = true
+	}
+	for _, a := range cfg.MuteAgents {
+		n.mutedAgents[strings.ToLower(a)] = true
+	}
+	if cfg.WebhookURL != "" {
+		if err := ValidateWebhookURL(cfg.WebhookURL); err != nil {
+			logger.Warn("notifications: webhook disabled", "error", err)
+			n.cfg.WebhookURL = ""
+		}
+	}
+	n.deliver = n.send
+	return n
+}
+
+// Enabled reports whether notifications are turned on.
+func (n *Notifier) Enabled() bool {
+	return n != nil && n.cfg.Enabled
+}
+
+// Observe records the current status of a source. A change into a
+// notifiable status is delivered after the debounce delay unless the source
+// changes again first. The first observation of a source only records its
+// status, so reconnecting to agents at startup stays quiet.
+func (n *Notifier) Observe(ev Event) {
+	if !n.Enabled() {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	prev, seen := n.last[ev.Key]
+	n.last[ev.Key] = ev.Kind
+	if !seen || prev == ev.Kind || n.stopped {
+		return
+	}
+	if t, ok := n.pending[ev.Key]; ok {
+		t.Stop()
+		delete(n.pending, ev.Key)
+	}
+	if ev.Kind == "" || !n.on[ev.Kind] || n.muted[ev.Key] || n.mutedAgents[strings.ToLower(ev.Agent)] {
+		return
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(n.debounce, func() {
+		n.mu.Lock()
+		if n.pending[ev.Key] != timer {
+			n.mu.Unlock()
+			return
+		}
+		delete(n.pending, ev.Key)
+		n.mu.Unlock()
+		n.deliver(ev)
+	})
+	n.pending[ev.Key] = timer
+}
+
+// Forget drops a source's state, e.g. when its worktree is deleted.
+func (n *Notifier) Forget(key string) {
+	if n == nil {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if t, ok := n.pending[key]; ok {
+		t.Stop()
+		delete(n.pending, key)
+	}
+	delete(n.last, key)
+	delete(n.muted, key)
+}
+
+// ToggleMute mutes or unmutes a source and returns the new state. Muting
+// cancels a notification that is waiting out its debounce.
+func (n *Notifier) ToggleMute(key string) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.muted[key] {
+		delete(n.muted, key)
+		return false
+	}
+	n.muted[key] = true
+	if t, ok := n.pending[key]; ok {
+		t.Stop()
+		delete(n.pending, key)
+	}
+	return true
+}
+
+// Muted reports whether a source is muted at runtime.
+func (n *Notifier) Muted(key string) bool {
+	if n == nil {
+		return false
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.muted[key]
+}
+
+// Stop cancels pending notifications. Later observations are ignored.
+func (n *Notifier) Stop() {
+	if n == nil {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.stopped = true
+	for key, t := range n.pending {
+		t.Stop()
+		delete(n.pending, key)
+	}
+}
+
+// WorktreeKey returns the source key for a worktree.
+func WorktreeKey(name string) string {
+	return fmt.Sprintf("worktree:%s", name)
+}
+
+// ShellKey returns the source key for a shell session.
+func ShellKey(tmuxName string) string {
+	return fmt.Sprintf("shell:%s", tmuxName)
+}
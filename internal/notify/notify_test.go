@@ -0,0 +1,240 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/marcus/sidecar/internal/config"
+)
+
+// recorder collects delivered events.
+type recorder struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (r *recorder) deliver(ev Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, ev)
+}
+
+func (r *recorder) kinds() []Kind {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var kinds []Kind
+	for _, ev := range r.events {
+		kinds = append(kinds, ev.Kind)
+	}
+	return kinds
+}
+
+func newTestNotifier(cfg config.NotificationsConfig) (*Notifier, *recorder) {
+	cfg.Enabled = true
+	if cfg.Debounce == 0 {
+		cfg.Debounce = 20 * time.Millisecond
+	}
+	n := New(cfg, false, nil)
+	r := &recorder{}
+	n.deliver = r.deliver
+	return n, r
+}
+
+func observe(n *Notifier, key string, kind Kind) {
+	n.Observe(Event{Key: key, Workspace: key, Agent: "claude", Kind: kind})
+}
+
+func settle() { time.Sleep(60 * time.Millisecond) }
+
+func TestObserve_Transitions(t *testing.T) {
+	n, r := newTestNotifier(config.NotificationsConfig{})
+
+	observe(n, "a", KindWaiting) // first observation is silent
+	settle()
+	if got := r.kinds(); len(got) != 0 {
+		t.Fatalf("expected no notification for initial status, got %v", got)
+	}
+
+	observe(n, "a", "")
+	observe(n, "a", KindDone)
+	observe(n, "a", KindDone) // unchanged
+	settle()
+	if got := r.kinds(); len(got) != 1 || got[0] != KindDone {
+		t.Fatalf("expected one done notification, got %v", got)
+	}
+}
+
+func TestObserve_DebounceDropsFlicker(t *testing.T) {
+	n, r := newTestNotifier(config.NotificationsConfig{})
+	observe(n, "a", "")
+	observe(n, "a", KindWaiting)
+	observe(n, "a", "") // back to active before the debounce elapses
+	settle()
+	if got := r.kinds(); len(got) != 0 {
+		t.Fatalf("expected flicker to be dropped, got %v", got)
+	}
+}
+
+func TestObserve_Filters(t *testing.T) {
+	n, r := newTestNotifier(config.NotificationsConfig{
+		On:         []string{"waiting"},
+		MuteAgents: []string{"Aider"},
+	})
+
+	observe(n, "a", "")
+	observe(n, "a", KindError) // not in On
+	n.Observe(Event{Key: "b", Agent: "aider"})
+	n.Observe(Event{Key: "b", Agent: "aider", Kind: KindWaiting}) // muted agent
+	observe(n, "c", "")
+	if !n.ToggleMute("c") {
+		t.Fatal("expected source to be muted")
+	}
+	observe(n, "c", KindWaiting)
+	settle()
+	if got := r.kinds(); len(got) != 0 {
+		t.Fatalf("expected no notifications, got %v", got)
+	}
+
+	if n.ToggleMute("c") {
+		t.Fatal("expected source to be unmuted")
+	}
+	observe(n, "c", "")
+	observe(n, "c", KindWaiting)
+	settle()
+	if got := r.kinds(); len(got) != 1 {
+		t.Fatalf("expected notification after unmute, got %v", got)
+	}
+}
+
+func TestMuteCancelsPending(t *testing.T) {
+	n, r := newTestNotifier(config.NotificationsConfig{Debounce: 40 * time.Millisecond})
+	observe(n, "a", "")
+	observe(n, "a", KindWaiting)
+	n.ToggleMute("a")
+	time.Sleep(80 * time.Millisecond)
+	if got := r.kinds(); len(got) != 0 {
+		t.Fatalf("expected pending notification to be cancelled, got %v", got)
+	}
+}
+
+func TestDisabledNotifier(t *testing.T) {
+	var nilNotifier *Notifier
+	if nilNotifier.Enabled() || nilNotifier.Muted("a") {
+		t.Error("nil notifier should be disabled")
+	}
+	nilNotifier.Observe(Event{Key: "a"})
+	nilNotifier.Forget("a")
+	nilNotifier.Stop()
+
+	n := New(config.NotificationsConfig{}, false, nil)
+	if n.Enabled() {
+		t.Error("expected notifier to be disabled")
+	}
+}
+
+func TestTerminalSequence(t *testing.T) {
+	ev := Event{Workspace: "auth", Agent: "claude", Kind: KindWaiting, Detail: "Allow edit; main.go?"}
+
+	n := New(config.NotificationsConfig{Enabled: true, Bell: true, OSC: "777"}, false, nil)
+	want := "\a\x1b]777;notify;sidecar: auth;claude is waiting for input: Allow edit, main.go?\x07"
+	if got := n.terminalSequence(ev); got != want {
+		t.Errorf("terminalSequence() = %q, want %q", got, want)
+	}
+
+	// TMUX is unset at startup, so only the caller's flag counts
+	t.Setenv("TMUX", "")
+	n = New(config.NotificationsConfig{Enabled: true, OSC: "9"}, true, nil)
+	got := n.terminalSequence(ev)
+	if !strings.HasPrefix(got, "\x1bPtmux;\x1b\x1b]9;") || !strings.HasSuffix(got, "\x07\x1b\\") {
+		t.Errorf("expected tmux passthrough, got %q", got)
+	}
+}
+
+func TestSend_CommandAndWebhook(t *testing.T) {
+	var mu sync.Mutex
+	var received map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		_ = json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer srv.Close()
+
+	out := filepath.Join(t.TempDir(), "out")
+	n := New(config.NotificationsConfig{
+		Enabled:    true,
+		Command:    []string{"sh", "-c", `printf '%s|%s' "$1" "$2" > ` + out, "sh", "{status}", "{workspace}"},
+		WebhookURL: srv.URL,
+	}, false, nil)
+	n.send(Event{Key: "worktree:auth", Workspace: "auth", Agent: "codex", Kind: KindDone})
+
+	data, err := os.ReadFile(out)
+	if err != nil || string(data) != "done|auth" {
+		t.Errorf("command output = %q (err %v)", data, err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if received["status"] != "done" || received["body"] != "codex finished" || received["workspace"] != "auth" {
+		t.Errorf("unexpected webhook payload: %v", received)
+	}
+}
+
+func TestPostWebhook_RedirectsStayLocal(t *testing.T) {
+	var hits int
+	var mu sync.Mutex
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits++
+		mu.Unlock()
+	}))
+	defer target.Close()
+	redirectTo := target.URL
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		to := redirectTo
+		mu.Unlock()
+		http.Redirect(w, r, to, http.StatusTemporaryRedirect)
+	}))
+	defer srv.Close()
+
+	n := New(config.NotificationsConfig{Enabled: true, WebhookURL: srv.URL}, false, nil)
+	ev := Event{Key: "worktree:auth", Workspace: "auth", Agent: "codex", Kind: KindDone}
+	if err := n.postWebhook(ev); err != nil {
+		t.Fatalf("local redirect: %v", err)
+	}
+	mu.Lock()
+	if hits != 1 {
+		t.Errorf("local redirect target got %d requests, want 1", hits)
+	}
+	redirectTo = "http://hooks.example.com/collect"
+	mu.Unlock()
+	if err := n.postWebhook(ev); err == nil || !strings.Contains(err.Error(), "localhost") {
+		t.Errorf("off-host redirect err = %v, want refusal", err)
+	}
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	tests := []struct {
+		url string
+		ok  bool
+	}{
+		{"http://localhost:9000/hook", true},
+		{"http://127.0.0.1:8080", true},
+		{"https://[::1]/notify", true},
+		{"https://hooks.example.com/x", false},
+		{"http://10.0.0.5/hook", false},
+		{"ftp://localhost/x", false},
+	}
+	for _, tt := range tests {
+		if err := ValidateWebhookURL(tt.url); (err == nil) != tt.ok {
+			t.Errorf("ValidateWebhookURL(%q) = %v, want ok=%v", tt.url, err, tt.ok)
+		}
+	}
+}
@@ -57,6 +57,15 @@ func BuildEntries(km *keymap.Registry, plugins []plugin.Plugin, activeContext, p
 			cmdMeta[key] = cmd
 		}
 	}
+	// Commands registered with the keymap (e.g. user-defined commands) carry
+	// their own metadata
+	registered := km.Commands()
+	for _, cmd := range registered {
+		key := cmd.ID + ":" + commandContext(cmd)
+		if _, ok := cmdMeta[key]; !ok {
+			cmdMeta[key] = plugin.Command{ID: cmd.ID, Name: cmd.Name, Description: cmd.Description, Context: cmd.Context}
+		}
+	}
 
 	// Collect all unique contexts
 	contexts := km.AllContexts()
@@ -79,9 +88,28 @@ func BuildEntries(km *keymap.Registry, plugins []plugin.Plugin, activeContext, p
 		}
 	}
 
+	// Runnable commands without a key binding are listed with an empty key
+	for _, cmd := range registered {
+		ctx := commandContext(cmd)
+		if cmd.Handler == nil || seen[cmd.ID+":"+ctx] {
+			continue
+		}
+		seen[cmd.ID+":"+ctx] = true
+		b := keymap.Binding{Command: cmd.ID, Context: ctx}
+		entries = append(entries, bindingToEntry(b, cmdMeta, activeContext, pluginContext))
+	}
+
 	return entries
 }
 
+// commandContext returns a keymap command's context, defaulting to global.
+func commandContext(cmd keymap.Command) string {
+	if cmd.Context == "" {
+		return "global"
+	}
+	return cmd.Context
+}
+
 // bindingToEntry converts a keymap binding to a palette entry.
 func bindingToEntry(b keymap.Binding, cmdMeta map[string]plugin.Command, activeContext, pluginContext string) PaletteEntry {
 	entry := PaletteEntry{
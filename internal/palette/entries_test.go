@@ -3,6 +3,8 @@ package palette
 import (
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/keymap"
 	"github.com/marcus/sidecar/internal/plugin"
 )
 
@@ -161,3 +163,26 @@ func TestPaletteEntry_Fields(t *testing.T) {
 		t.Errorf("MatchRanges should have 1 element")
 	}
 }
+
+func TestBuildEntries_RegisteredCommands(t *testing.T) {
+	km := keymap.NewRegistry()
+	km.RegisterBinding(keymap.Binding{Key: "s", Command: "stage-file", Context: "git-status"})
+	handler := func() tea.Cmd { return nil }
+	km.RegisterCommand(keymap.Command{ID: "custom:lint", Name: "lint", Description: "make lint", Context: "git-status", Handler: handler})
+	km.RegisterCommand(keymap.Command{ID: "custom:test", Name: "test", Description: "make test", Context: "git-status", Handler: handler})
+	km.RegisterBinding(keymap.Binding{Key: "T", Command: "custom:test", Context: "git-status"})
+
+	byID := make(map[string]PaletteEntry)
+	for _, e := range BuildEntries(km, nil, "git-status", "git-status") {
+		byID[e.CommandID] = e
+	}
+	if len(byID) != 3 {
+		t.Fatalf("got %d entries, want 3: %+v", len(byID), byID)
+	}
+	if e := byID["custom:lint"]; e.Key != "" || e.Name != "lint" || e.Description != "make lint" || e.Context != "git-status" {
+		t.Errorf("unbound command entry = %+v", e)
+	}
+	if e := byID["custom:test"]; e.Key != "T" || e.Name != "test" {
+		t.Errorf("bound command entry = %+v", e)
+	}
+}
@@ -250,7 +250,11 @@ func (m Model) renderLayerHeader(layer Layer, count int) string {
 // renderEntry renders a single palette entry.
 func (m Model) renderEntry(entry PaletteEntry, selected bool, maxWidth int) string {
 	// Key column - render as pill/chip using KeyHint style
-	keyStr := styles.KeyHint.Render(entry.Key)
+	// (unbound commands get an empty column)
+	keyStr := ""
+	if entry.Key != "" {
+		keyStr = styles.KeyHint.Render(entry.Key)
+	}
 	keyWidth := lipgloss.Width(keyStr)
 
 	// Pad key to fixed column width for alignment
@@ -1,6 +1,9 @@
 package plugin
 
-import tea "github.com/charmbracelet/bubbletea"
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/remote"
+)
 
 // Plugin defines the interface for all sidecar plugins.
 type Plugin interface {
@@ -54,6 +57,21 @@ type DiagnosticProvider interface {
 	Diagnostics() []Diagnostic
 }
 
+// CommandVariableProvider is implemented by plugins that expose their current
+// selection to user-defined commands, e.g. {"file": "internal/app/model.go"}.
+// Keys are customcmd variable names; empty values are omitted.
+type CommandVariableProvider interface {
+	CommandVariables() map[string]string
+}
+
+// RemoteHandler is implemented by plugins that serve remote control
+// methods, e.g. "agent.start". HandleRemote must reply to the request, either
+// directly or from the returned command.
+type RemoteHandler interface {
+	RemoteMethods() []string
+	HandleRemote(req *remote.Request) tea.Cmd
+}
+
 // Diagnostic represents a health/status check result.
 type Diagnostic struct {
 	ID     string
@@ -1,143 +1,220 @@
 package conversations
 
 import (
+	"bytes"
 	"fmt"
-	"sort"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/marcus/sidecar/internal/adapter/claudecode"
+	"github.com/marcus/sidecar/internal/analytics"
+	"github.com/marcus/sidecar/internal/config"
+	appmsg "github.com/marcus/sidecar/internal/msg"
+	"github.com/marcus/sidecar/internal/plugin"
 	"github.com/marcus/sidecar/internal/styles"
 )
 
-// renderAnalytics renders the global analytics view with scrolling support.
-func (p *Plugin) renderAnalytics() string {
-	// Build all content lines first
-	var lines []string
+// Number of rows shown per breakdown and in the top sessions list.
+const (
+	analyticsBreakdownRows = 8
+	analyticsTopSessions   = 10
+)
 
-	// Load stats
-	stats, err := claudecode.LoadStatsCache()
-	if err != nil {
-		lines = append(lines, styles.Title.Render(" Usage Analytics"))
-		lines = append(lines, styles.Muted.Render(strings.Repeat("━", p.width-2)))
-		lines = append(lines, styles.StatusDeleted.Render(" Unable to load stats: "+err.Error()))
-		p.analyticsLines = lines
-		return strings.Join(lines, "\n")
-	}
-
-	// Header
-	lines = append(lines, styles.Title.Render(" Usage Analytics"))
-	lines = append(lines, styles.Muted.Render(strings.Repeat("━", p.width-2)))
-
-	// Summary line
-	firstDate := stats.FirstSessionDate.Format("Jan 2")
-	summary := fmt.Sprintf(" Since %s  │  %d sessions  │  %s messages",
-		firstDate,
-		stats.TotalSessions,
-		formatLargeNumber(stats.TotalMessages))
-	lines = append(lines, styles.Body.Render(summary))
-	lines = append(lines, "")
+// analyticsRange is a selectable report time range.
+type analyticsRange struct {
+	label  string
+	bounds func(now time.Time) (from, to time.Time)
+}
+
+// lastDays returns bounds covering today and the n-1 days before it.
+func lastDays(n int) func(time.Time) (time.Time, time.Time) {
+	return func(now time.Time) (time.Time, time.Time) {
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		return today.AddDate(0, 0, -(n - 1)), today.AddDate(0, 0, 1)
+	}
+}
+
+// analyticsRanges are the time ranges cycled with t. New selects the last
+// 30 days.
+var analyticsRanges = []analyticsRange{
+	{"Last 7 days", lastDays(7)},
+	{"Last 30 days", lastDays(30)},
+	{"Last 90 days", lastDays(90)},
+	{"This month", func(now time.Time) (time.Time, time.Time) {
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		return start, start.AddDate(0, 1, 0)
+	}},
+	{"Last month", func(now time.Time) (time.Time, time.Time) {
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		return start.AddDate(0, -1, 0), start
+	}},
+	{"All time", func(time.Time) (time.Time, time.Time) { return time.Time{}, time.Time{} }},
+}
 
-	// Weekly activity chart
-	lines = append(lines, styles.Title.Render(" This Week's Activity"))
-	lines = append(lines, styles.Muted.Render(strings.Repeat("─", p.width-2)))
+// analyticsIntervals are the series granularities cycled with i.
+var analyticsIntervals = []analytics.Granularity{analytics.Daily, analytics.Weekly, analytics.Monthly}
 
-	recentActivity := stats.GetRecentActivity(7)
-	maxMsgs := 0
-	for _, day := range recentActivity {
-		if day.MessageCount > maxMsgs {
-			maxMsgs = day.MessageCount
+// AnalyticsLoadedMsg delivers usage records collected across projects.
+type AnalyticsLoadedMsg struct {
+	Epoch       uint64
+	Records     []analytics.Record
+	CurrentRoot string // project path of the current project
+	Err         error  // cache read or write error; records are still valid
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m AnalyticsLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// analyticsProjects returns the configured projects plus the current one.
+func (p *Plugin) analyticsProjects() []analytics.Project {
+	var projects []analytics.Project
+	seen := make(map[string]bool)
+	if p.ctx.Config != nil {
+		for _, pc := range p.ctx.Config.Projects.List {
+			path := config.ExpandPath(pc.Path)
+			if path == "" || seen[path] {
+				continue
+			}
+			seen[path] = true
+			projects = append(projects, analytics.Project{Name: pc.Name, Path: path})
 		}
 	}
-
-	for _, day := range recentActivity {
-		date, _ := time.Parse("2006-01-02", day.Date)
-		dayName := date.Format("Mon")
-		bar := renderColoredBar(day.MessageCount, maxMsgs, 16)
-		dayLabel := styles.Body.Render(fmt.Sprintf(" %s │ ", dayName))
-		statsLabel := styles.Subtitle.Render(fmt.Sprintf(" │ %5d msgs │ %2d sessions", day.MessageCount, day.SessionCount))
-		lines = append(lines, dayLabel+bar+statsLabel)
+	root := p.ctx.ProjectRoot
+	if root == "" {
+		root = p.ctx.WorkDir
 	}
-	lines = append(lines, "")
+	if root != "" && !seen[root] {
+		projects = append(projects, analytics.Project{Name: filepath.Base(root), Path: root})
+	}
+	return projects
+}
 
-	// Model usage
-	lines = append(lines, styles.Title.Render(" Model Usage"))
-	lines = append(lines, styles.Muted.Render(strings.Repeat("─", p.width-2)))
-
-	// Sort models by total tokens descending for stable ordering
-	type modelEntry struct {
-		name        string
-		usage       claudecode.ModelUsage
-		totalTokens int64
-	}
-	var models []modelEntry
-	var maxTokens int64
-	for model, usage := range stats.ModelUsage {
-		total := int64(usage.InputTokens) + int64(usage.OutputTokens)
-		if total > maxTokens {
-			maxTokens = total
-		}
-		models = append(models, modelEntry{model, usage, total})
+// openAnalytics shows the analytics dashboard and refreshes its data.
+func (p *Plugin) openAnalytics() tea.Cmd {
+	p.view = ViewAnalytics
+	p.analyticsScrollOff = 0
+	return p.loadAnalytics()
+}
+
+// loadAnalytics collects usage for all projects in the background. Only
+// sessions changed since they were cached are re-read.
+func (p *Plugin) loadAnalytics() tea.Cmd {
+	if p.analyticsLoading {
+		return nil
 	}
-	sort.Slice(models, func(i, j int) bool {
-		if models[i].totalTokens != models[j].totalTokens {
-			return models[i].totalTokens > models[j].totalTokens // descending by tokens
+	p.analyticsLoading = true
+	if p.analyticsCache == nil {
+		p.analyticsCache = analytics.NewCache("")
+		if p.ctx.ConfigDir != "" {
+			cache, err := analytics.LoadCache(filepath.Join(filepath.Dir(p.ctx.ConfigDir), analytics.CacheFileName))
+			if err != nil && p.ctx.Logger != nil {
+				p.ctx.Logger.Warn("conversations: usage cache unreadable, rebuilding", "err", err)
+			}
+			p.analyticsCache = cache
 		}
-		return models[i].name < models[j].name // ascending by name for ties
-	})
+	}
 
-	for _, m := range models {
-		shortName := modelShortName(m.name)
-		if shortName == "" {
-			continue
+	cache := p.analyticsCache
+	projects := p.analyticsProjects()
+	current := p.ctx.ProjectRoot
+	if current == "" {
+		current = p.ctx.WorkDir
+	}
+	epoch := p.ctx.Epoch
+	return func() tea.Msg {
+		records := analytics.Collect(projects, cache)
+		return AnalyticsLoadedMsg{
+			Epoch:       epoch,
+			Records:     records,
+			CurrentRoot: analytics.ProjectRoot(current),
+			Err:         cache.Save(),
 		}
+	}
+}
 
-		bar := renderColoredBar64(m.totalTokens, maxTokens, 12)
-		cost := claudecode.CalculateModelCost(m.name, m.usage)
+// handleAnalyticsLoaded stores collected records and rebuilds the report.
+func (p *Plugin) handleAnalyticsLoaded(msg AnalyticsLoadedMsg) tea.Cmd {
+	p.analyticsLoading = false
+	if plugin.IsStale(p.ctx, msg) {
+		return nil
+	}
+	p.analyticsRecords = msg.Records
+	if p.analyticsRecords == nil {
+		p.analyticsRecords = []analytics.Record{} // loaded, no usage
+	}
+	p.analyticsRoot = msg.CurrentRoot
+	p.rebuildAnalyticsReport()
+	if msg.Err != nil {
+		return analyticsErrorToast("Usage cache not saved: " + msg.Err.Error())
+	}
+	return nil
+}
 
-		modelLabel := styles.Body.Render(fmt.Sprintf(" %-6s │ ", shortName))
-		tokensLabel := styles.Subtitle.Render(fmt.Sprintf(" │ %s in  %s out │ ",
-			formatLargeNumber64(int64(m.usage.InputTokens)),
-			formatLargeNumber64(int64(m.usage.OutputTokens))))
-		costLabel := lipgloss.NewStyle().Foreground(styles.Accent).Render(fmt.Sprintf("~$%.0f", cost))
-		lines = append(lines, modelLabel+bar+tokensLabel+costLabel)
+// rebuildAnalyticsReport aggregates the loaded records for the selected
+// range, interval and project scope.
+func (p *Plugin) rebuildAnalyticsReport() {
+	if p.analyticsRecords == nil {
+		p.analyticsReport = nil
+		return
 	}
-	lines = append(lines, "")
+	from, to := analyticsRanges[p.analyticsRangeIdx].bounds(time.Now())
+	f := analytics.Filter{From: from, To: to}
+	if !p.analyticsAllProjects && p.analyticsRoot != "" {
+		f.Projects = []string{p.analyticsRoot}
+	}
+	p.analyticsReport = analytics.BuildReport(p.analyticsRecords, f, analyticsIntervals[p.analyticsIntervalIdx], analyticsTopSessions)
+}
 
-	// Stats footer
-	cacheEff := stats.CacheEfficiency()
-	cacheLabel := styles.Subtitle.Render(" Cache Efficiency: ")
-	cacheValue := lipgloss.NewStyle().Foreground(styles.Success).Render(fmt.Sprintf("%.0f%%", cacheEff))
-	lines = append(lines, cacheLabel+cacheValue)
-
-	// Peak hours
-	peakHours := stats.GetPeakHours(3)
-	if len(peakHours) > 0 {
-		peakLabel := styles.Subtitle.Render(" Peak Hours:")
-		peakValues := ""
-		for i, ph := range peakHours {
-			if i > 0 {
-				peakValues += ","
-			}
-			peakValues += fmt.Sprintf(" %s:00", ph.Hour)
-		}
-		lines = append(lines, peakLabel+styles.Body.Render(peakValues))
+// analyticsScopeLabel describes the project scope.
+func (p *Plugin) analyticsScopeLabel() string {
+	if p.analyticsAllProjects {
+		return "all projects"
 	}
+	return "this project"
+}
 
-	// Longest session
-	if stats.LongestSession.Duration > 0 {
-		dur := time.Duration(stats.LongestSession.Duration) * time.Millisecond
-		sessionLabel := styles.Subtitle.Render(" Longest Session: ")
-		sessionValue := styles.Body.Render(formatSessionDuration(dur))
-		lines = append(lines, sessionLabel+sessionValue)
+// exportAnalytics writes the current report to the working directory as CSV
+// or JSON.
+func (p *Plugin) exportAnalytics(format string) tea.Cmd {
+	rep := p.analyticsReport
+	if rep == nil {
+		return appmsg.ShowToast("Usage is still loading", 2*time.Second)
+	}
+	var buf bytes.Buffer
+	var err error
+	if format == "json" {
+		err = analytics.WriteJSON(&buf, rep)
+	} else {
+		err = analytics.WriteCSV(&buf, rep)
+	}
+	if err != nil {
+		return analyticsErrorToast("Export failed: " + err.Error())
 	}
 
-	// Total cost
-	totalCost := stats.TotalCost()
-	costLabel := styles.Subtitle.Render(" Total Estimated Cost: ")
-	costValue := lipgloss.NewStyle().Foreground(styles.Accent).Bold(true).Render(fmt.Sprintf("~$%.0f", totalCost))
-	lines = append(lines, costLabel+costValue)
+	span := "all"
+	if !rep.From.IsZero() {
+		span = rep.From.Format("20060102") + "-" + rep.To.AddDate(0, 0, -1).Format("20060102")
+	}
+	filename := fmt.Sprintf("usage-%s-%s.%s", span, rep.Granularity, format)
+	if err := os.WriteFile(filepath.Join(p.ctx.WorkDir, filename), buf.Bytes(), 0644); err != nil {
+		return analyticsErrorToast("Export failed: " + err.Error())
+	}
+	return appmsg.ShowToast("Exported "+filename, 3*time.Second)
+}
+
+// analyticsErrorToast shows an error toast.
+func analyticsErrorToast(text string) tea.Cmd {
+	return func() tea.Msg {
+		return appmsg.ToastMsg{Message: text, Duration: 3 * time.Second, IsError: true}
+	}
+}
+
+// renderAnalytics renders the usage dashboard with scrolling support.
+func (p *Plugin) renderAnalytics() string {
+	lines := p.analyticsContentLines()
 
 	// Store lines for scroll calculation
 	p.analyticsLines = lines
@@ -164,30 +241,154 @@ func (p *Plugin) renderAnalytics() string {
 	return strings.Join(visibleLines, "\n")
 }
 
-// renderColoredBar renders a colored ASCII bar chart segment.
-func renderColoredBar(value, max, width int) string {
-	if max == 0 {
-		return styles.Muted.Render(strings.Repeat("░", width))
+// analyticsContentLines builds all dashboard lines.
+func (p *Plugin) analyticsContentLines() []string {
+	rule := strings.Repeat("─", max(p.width-2, 1))
+	accent := lipgloss.NewStyle().Foreground(styles.Accent)
+
+	interval := analyticsIntervals[p.analyticsIntervalIdx]
+	var lines []string
+	title := styles.Title.Render(" Usage Analytics")
+	scope := styles.Muted.Render(fmt.Sprintf("%s · %s · %s ",
+		analyticsRanges[p.analyticsRangeIdx].label, interval, p.analyticsScopeLabel()))
+	gap := p.width - lipgloss.Width(title) - lipgloss.Width(scope)
+	if gap < 2 {
+		gap = 2
 	}
-	filled := (value * width) / max
-	if filled > width {
-		filled = width
+	lines = append(lines, title+strings.Repeat(" ", gap)+scope)
+	lines = append(lines, styles.Muted.Render(strings.Repeat("━", max(p.width-2, 1))))
+
+	rep := p.analyticsReport
+	if rep == nil {
+		lines = append(lines, styles.Muted.Render(" Collecting usage across projects..."))
+		return append(lines, "", p.analyticsHints())
 	}
-	filledBar := lipgloss.NewStyle().Foreground(styles.Primary).Render(strings.Repeat("█", filled))
-	emptyBar := styles.Muted.Render(strings.Repeat("░", width-filled))
-	return filledBar + emptyBar
+	if p.analyticsLoading {
+		lines = append(lines, styles.Muted.Render(" Refreshing..."))
+	}
+
+	// Summary
+	t := rep.Totals
+	summary := fmt.Sprintf(" %s  │  %s tokens  │  %d sessions  │  cache hit %.0f%%",
+		accent.Bold(true).Render(fmt.Sprintf("~$%.2f", t.Cost)),
+		formatLargeNumber(t.Tokens()), t.Sessions, t.CacheHitRatio*100)
+	lines = append(lines, styles.Body.Render(summary))
+	lines = append(lines, "")
+
+	if t.Sessions == 0 {
+		lines = append(lines, styles.Muted.Render(" No usage in this range"))
+		return append(lines, "", p.analyticsHints())
+	}
+
+	// Series
+	seriesTitle := map[analytics.Granularity]string{
+		analytics.Daily: "Daily", analytics.Weekly: "Weekly", analytics.Monthly: "Monthly",
+	}[interval]
+	lines = append(lines, styles.Title.Render(" "+seriesTitle+" Cost"))
+	lines = append(lines, styles.Muted.Render(rule))
+	var maxCost float64
+	for _, pt := range rep.Series {
+		maxCost = max(maxCost, pt.Cost)
+	}
+	for _, pt := range rep.Series {
+		label := pt.Start.Format("Jan 02")
+		if interval == analytics.Monthly {
+			label = pt.Start.Format("Jan 06")
+		}
+		lines = append(lines, styles.Body.Render(fmt.Sprintf(" %s │ ", label))+
+			renderCostBar(pt.Cost, maxCost, 20)+
+			styles.Subtitle.Render(fmt.Sprintf(" │ %8s  %7s tok  %3d sess", fmt.Sprintf("$%.2f", pt.Cost), formatLargeNumber(pt.Tokens()), pt.Sessions)))
+	}
+	lines = append(lines, "")
+
+	// Breakdowns
+	lines = append(lines, p.renderBreakdown("By Model", rep.ByModel)...)
+	lines = append(lines, p.renderBreakdown("By Adapter", rep.ByAdapter)...)
+	if p.analyticsAllProjects {
+		lines = append(lines, p.renderBreakdown("By Project", rep.ByProject)...)
+	}
+	lines = append(lines, p.renderBreakdown("By Worktree", rep.ByWorktree)...)
+
+	// Top sessions
+	lines = append(lines, styles.Title.Render(" Top Sessions"))
+	lines = append(lines, styles.Muted.Render(rule))
+	nameW := max(p.width-44, 12)
+	for _, s := range rep.TopSessions {
+		name := s.Name
+		if name == "" {
+			name = s.SessionID
+		}
+		where := s.Project
+		if s.Worktree != "" {
+			where += "/" + s.Worktree
+		}
+		label := truncateRunes(name+" · "+where, nameW)
+		lines = append(lines, styles.Body.Render(fmt.Sprintf(" %-*s ", nameW, label))+
+			accent.Render(fmt.Sprintf("%9s", fmt.Sprintf("$%.2f", s.Cost)))+
+			styles.Subtitle.Render(fmt.Sprintf("  %7s tok  %s", formatLargeNumber(s.Tokens()), s.UpdatedAt.Format("Jan 02"))))
+	}
+
+	if len(rep.Unpriced) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, styles.Muted.Render(" No price for: "+strings.Join(rep.Unpriced, ", ")+" (tokens counted, cost excluded)"))
+	}
+	return append(lines, "", p.analyticsHints())
+}
+
+// renderBreakdown renders a titled breakdown table.
+func (p *Plugin) renderBreakdown(title string, rows []analytics.Row) []string {
+	if len(rows) == 0 {
+		return nil
+	}
+	lines := []string{
+		styles.Title.Render(" " + title),
+		styles.Muted.Render(strings.Repeat("─", max(p.width-2, 1))),
+	}
+	maxCost := rows[0].Cost // rows are sorted by cost
+	keyW := 24
+	for i, r := range rows {
+		if i == analyticsBreakdownRows {
+			lines = append(lines, styles.Muted.Render(fmt.Sprintf(" … %d more", len(rows)-i)))
+			break
+		}
+		lines = append(lines, styles.Body.Render(fmt.Sprintf(" %-*s │ ", keyW, truncateRunes(r.Key, keyW)))+
+			renderCostBar(r.Cost, maxCost, 12)+
+			styles.Subtitle.Render(fmt.Sprintf(" │ %8s  %7s tok  %3d sess  cache %3.0f%%",
+				fmt.Sprintf("$%.2f", r.Cost), formatLargeNumber(r.Tokens()), r.Sessions, r.CacheHitRatio*100)))
+	}
+	return append(lines, "")
 }
 
-// renderColoredBar64 renders a colored ASCII bar chart segment for int64 values.
-func renderColoredBar64(value, max int64, width int) string {
-	if max == 0 {
+// analyticsHints renders the dashboard key hints.
+func (p *Plugin) analyticsHints() string {
+	return styles.Muted.Render(" t range  d interval  p projects  r refresh  x export CSV  X export JSON  esc back")
+}
+
+// truncateRunes shortens s to at most n runes, ending with an ellipsis.
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	if n <= 1 {
+		return string(r[:n])
+	}
+	return string(r[:n-1]) + "…"
+}
+
+// renderCostBar renders a colored bar for a cost relative to max.
+func renderCostBar(value, max float64, width int) string {
+	if max <= 0 {
 		return styles.Muted.Render(strings.Repeat("░", width))
 	}
-	filled := int((value * int64(width)) / max)
+	filled := int(value / max * float64(width))
 	if filled > width {
 		filled = width
 	}
-	filledBar := lipgloss.NewStyle().Foreground(styles.Secondary).Render(strings.Repeat("█", filled))
+	if filled == 0 && value > 0 {
+		filled = 1
+	}
+	filledBar := lipgloss.NewStyle().Foreground(styles.Primary).Render(strings.Repeat("█", filled))
 	emptyBar := styles.Muted.Render(strings.Repeat("░", width-filled))
 	return filledBar + emptyBar
 }
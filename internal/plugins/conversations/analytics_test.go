@@ -0,0 +1,59 @@
+package conversations
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/analytics"
+	"github.com/marcus/sidecar/internal/plugin"
+)
+
+func TestAnalyticsDashboard_ScopeIntervalAndExport(t *testing.T) {
+	p := New()
+	p.ctx = &plugin.Context{WorkDir: t.TempDir()}
+	p.width, p.height = 100, 40
+	p.view = ViewAnalytics
+
+	today := time.Now().Format("2006-01-02")
+	p.handleAnalyticsLoaded(AnalyticsLoadedMsg{
+		CurrentRoot: "/src/api",
+		Records: []analytics.Record{
+			{AdapterID: "codex", SessionID: "a", Project: "api", ProjectPath: "/src/api",
+				Buckets: []analytics.Bucket{{Day: today, Input: 1000, Cost: 2}}},
+			{AdapterID: "codex", SessionID: "b", Project: "web", ProjectPath: "/src/web",
+				Buckets: []analytics.Bucket{{Day: today, Input: 500, Cost: 1}}},
+		},
+	})
+	if p.analyticsReport == nil || p.analyticsReport.Totals.Sessions != 1 {
+		t.Fatalf("default scope should be the current project, report %+v", p.analyticsReport)
+	}
+	if !strings.Contains(p.renderAnalytics(), "~$2.00") {
+		t.Error("dashboard should show the project's cost")
+	}
+
+	p.updateAnalytics(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	if p.analyticsReport.Totals.Sessions != 2 || p.analyticsReport.Totals.Cost != 3 {
+		t.Errorf("all projects totals = %+v", p.analyticsReport.Totals)
+	}
+	p.updateAnalytics(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	if p.analyticsReport.Granularity != analytics.Weekly {
+		t.Errorf("granularity = %s, want week", p.analyticsReport.Granularity)
+	}
+
+	_, cmd := p.updateAnalytics(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if cmd == nil {
+		t.Fatal("export should return a toast")
+	}
+	matches, _ := filepath.Glob(filepath.Join(p.ctx.WorkDir, "usage-*-week.csv"))
+	if len(matches) != 1 {
+		t.Fatalf("exported files = %v", matches)
+	}
+	data, _ := os.ReadFile(matches[0])
+	if !strings.Contains(string(data), ",api,main,codex,unknown,1,") {
+		t.Errorf("csv = %s", data)
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/marcus/sidecar/internal/adapter"
+	"github.com/marcus/sidecar/internal/searchindex"
 )
 
 const (
@@ -53,175 +54,251 @@ func RunContentSearch(query string, sessions []adapter.Session,
 		if query == "" {
 			return ContentSearchResultsMsg{Epoch: epoch, Results: nil}
 		}
+		results, totalMatches := scanSessions(query, sessions, adapters, opts)
+		return newContentSearchResultsMsg(epoch, query, results, totalMatches)
+	}
+}
 
-		// Performance: sort sessions by UpdatedAt descending before searching (td-80cbe1)
-		// This prioritizes recent sessions and improves perceived performance
-		sortedSessions := make([]adapter.Session, len(sessions))
-		copy(sortedSessions, sessions)
-		sort.Slice(sortedSessions, func(i, j int) bool {
-			return sortedSessions[i].UpdatedAt.After(sortedSessions[j].UpdatedAt)
-		})
+// RunIndexedContentSearch answers a search from the persistent index for
+// sessions that are already indexed, and scans the remaining (new or changed
+// since the last sync) sessions through their adapters. Indexed results come
+// first in rank order, followed by scanned results. Falls back to a full scan
+// if the index cannot be queried.
+func RunIndexedContentSearch(query string, sessions []adapter.Session,
+	adapters map[string]adapter.Adapter, idx *searchindex.Index, opts adapter.SearchOptions, epoch uint64) tea.Cmd {
+	return func() tea.Msg {
+		if query == "" {
+			return ContentSearchResultsMsg{Epoch: epoch, Results: nil}
+		}
 
-		var results []SessionSearchResult
-		var mu sync.Mutex
-		var wg sync.WaitGroup
-		concurrency := searchConcurrency()
-		sem := make(chan struct{}, concurrency)
+		stale, err := idx.Stale(sessions)
+		if err != nil {
+			results, totalMatches := scanSessions(query, sessions, adapters, opts)
+			return newContentSearchResultsMsg(epoch, query, results, totalMatches)
+		}
+		staleKeys := make(map[string]bool, len(stale))
+		for _, s := range stale {
+			staleKeys[searchindex.SessionKey(s.AdapterID, s.ID)] = true
+		}
+		byKey := make(map[string]adapter.Session, len(sessions))
+		indexed := make([]adapter.Session, 0, len(sessions)-len(stale))
+		for _, s := range sessions {
+			key := searchindex.SessionKey(s.AdapterID, s.ID)
+			if !staleKeys[key] {
+				byKey[key] = s
+				indexed = append(indexed, s)
+			}
+		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), searchTimeout)
-		defer cancel()
+		hits, capped, err := idx.Search(query, opts, indexed)
+		if err != nil {
+			results, totalMatches := scanSessions(query, sessions, adapters, opts)
+			return newContentSearchResultsMsg(epoch, query, results, totalMatches)
+		}
 
+		var results []SessionSearchResult
 		totalMatches := 0
-		done := make(chan struct{})
-
-	sessionLoop:
-		for _, session := range sortedSessions {
-			// Performance: skip sessions with no messages (td-80cbe1)
-			if session.MessageCount == 0 {
+		for _, h := range hits {
+			s, ok := byKey[searchindex.SessionKey(h.AdapterID, h.SessionID)]
+			if !ok {
 				continue
 			}
+			results = append(results, SessionSearchResult{Session: s, Messages: h.Messages})
+			totalMatches += countMatches(h.Messages)
+		}
 
-			// Check if we've hit the match limit
-			mu.Lock()
-			if totalMatches >= maxTotalMatches {
-				mu.Unlock()
-				break sessionLoop
-			}
+		if len(stale) > 0 && totalMatches < maxTotalMatches {
+			scanned, scannedMatches := scanSessions(query, stale, adapters, opts)
+			results = append(results, scanned...)
+			totalMatches += scannedMatches
+		}
+
+		msg := newContentSearchResultsMsg(epoch, query, results, totalMatches)
+		// The index dropped lower-ranked matches, so more exist than shown.
+		msg.Truncated = msg.Truncated || capped
+		return msg
+	}
+}
+
+// scanSessions searches sessions in parallel through their adapters'
+// MessageSearcher implementations. Results are ordered by session UpdatedAt
+// descending. Returns the results and total match count.
+func scanSessions(query string, sessions []adapter.Session,
+	adapters map[string]adapter.Adapter, opts adapter.SearchOptions) ([]SessionSearchResult, int) {
+	// Performance: sort sessions by UpdatedAt descending before searching (td-80cbe1)
+	// This prioritizes recent sessions and improves perceived performance
+	sortedSessions := make([]adapter.Session, len(sessions))
+	copy(sortedSessions, sessions)
+	sort.Slice(sortedSessions, func(i, j int) bool {
+		return sortedSessions[i].UpdatedAt.After(sortedSessions[j].UpdatedAt)
+	})
+
+	var results []SessionSearchResult
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	concurrency := searchConcurrency()
+	sem := make(chan struct{}, concurrency)
+
+	ctx, cancel := context.WithTimeout(context.Background(), searchTimeout)
+	defer cancel()
+
+	totalMatches := 0
+	done := make(chan struct{})
+
+sessionLoop:
+	for _, session := range sortedSessions {
+		// Performance: skip sessions with no messages (td-80cbe1)
+		if session.MessageCount == 0 {
+			continue
+		}
+
+		// Check if we've hit the match limit
+		mu.Lock()
+		if totalMatches >= maxTotalMatches {
 			mu.Unlock()
+			break sessionLoop
+		}
+		mu.Unlock()
 
-			// Check context cancellation
+		// Check context cancellation
+		select {
+		case <-ctx.Done():
+			break sessionLoop
+		default:
+		}
+
+		wg.Add(1)
+		go func(s adapter.Session) {
+			defer wg.Done()
+
+			// Acquire semaphore or bail on context cancel
 			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
 			case <-ctx.Done():
-				break sessionLoop
-			default:
+				return
 			}
 
-			wg.Add(1)
-			go func(s adapter.Session) {
-				defer wg.Done()
+			// Get adapter for this session
+			adp, ok := adapters[s.AdapterID]
+			if !ok || adp == nil {
+				return
+			}
 
-				// Acquire semaphore or bail on context cancel
-				select {
-				case sem <- struct{}{}:
-					defer func() { <-sem }()
-				case <-ctx.Done():
-					return
-				}
+			// Check if adapter supports search
+			searcher, ok := adp.(adapter.MessageSearcher)
+			if !ok {
+				return
+			}
 
-				// Get adapter for this session
-				adp, ok := adapters[s.AdapterID]
-				if !ok || adp == nil {
-					return
-				}
+			// Execute search
+			matches, err := searcher.SearchMessages(s.ID, query, opts)
+			if err != nil || len(matches) == 0 {
+				return
+			}
 
-				// Check if adapter supports search
-				searcher, ok := adp.(adapter.MessageSearcher)
-				if !ok {
-					return
-				}
+			matchCount := countMatches(matches)
 
-				// Execute search
-				matches, err := searcher.SearchMessages(s.ID, query, opts)
-				if err != nil || len(matches) == 0 {
-					return
-				}
+			mu.Lock()
+			results = append(results, SessionSearchResult{
+				Session:   s,
+				Messages:  matches,
+				Collapsed: false,
+			})
+			totalMatches += matchCount
+			mu.Unlock()
+		}(session)
+	}
 
-				matchCount := countMatches(matches)
-
-				mu.Lock()
-				results = append(results, SessionSearchResult{
-					Session:   s,
-					Messages:  matches,
-					Collapsed: false,
-				})
-				totalMatches += matchCount
-				mu.Unlock()
-			}(session)
-		}
+	// Wait for all goroutines in a separate goroutine
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
 
-		// Wait for all goroutines in a separate goroutine
-		go func() {
-			wg.Wait()
-			close(done)
-		}()
+	// Wait for completion or context timeout
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
 
-		// Wait for completion or context timeout
-		select {
-		case <-done:
-		case <-ctx.Done():
-		}
+	// Sort results by session UpdatedAt descending (most recent first)
+	mu.Lock()
+	defer mu.Unlock()
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Session.UpdatedAt.After(results[j].Session.UpdatedAt)
+	})
+	return results, totalMatches
+}
 
-		// Sort results by session UpdatedAt descending (most recent first)
-		sort.Slice(results, func(i, j int) bool {
-			return results[i].Session.UpdatedAt.After(results[j].Session.UpdatedAt)
-		})
-
-		// Count total matches and cap visible results (td-8e1a2b)
-		totalFound := totalMatches
-		truncated := totalMatches > maxVisibleMatches
-
-		// Truncate results to maxVisibleMatches
-		if truncated {
-			visibleCount := 0
-			truncatedResults := make([]SessionSearchResult, 0, len(results))
-			for _, sr := range results {
-				if visibleCount >= maxVisibleMatches {
-					break
+// newContentSearchResultsMsg caps results at maxVisibleMatches and builds the
+// results message.
+func newContentSearchResultsMsg(epoch uint64, query string, results []SessionSearchResult, totalMatches int) ContentSearchResultsMsg {
+	// Count total matches and cap visible results (td-8e1a2b)
+	totalFound := totalMatches
+	truncated := totalMatches > maxVisibleMatches
+
+	// Truncate results to maxVisibleMatches
+	if truncated {
+		visibleCount := 0
+		truncatedResults := make([]SessionSearchResult, 0, len(results))
+		for _, sr := range results {
+			if visibleCount >= maxVisibleMatches {
+				break
+			}
+			// Count matches in this session
+			sessionMatches := 0
+			for _, mm := range sr.Messages {
+				sessionMatches += len(mm.Matches)
+			}
+			if visibleCount+sessionMatches <= maxVisibleMatches {
+				// Include whole session
+				truncatedResults = append(truncatedResults, sr)
+				visibleCount += sessionMatches
+			} else {
+				// Need to truncate within this session
+				remaining := maxVisibleMatches - visibleCount
+				truncatedSession := SessionSearchResult{
+					Session:   sr.Session,
+					Collapsed: sr.Collapsed,
 				}
-				// Count matches in this session
-				sessionMatches := 0
 				for _, mm := range sr.Messages {
-					sessionMatches += len(mm.Matches)
-				}
-				if visibleCount+sessionMatches <= maxVisibleMatches {
-					// Include whole session
-					truncatedResults = append(truncatedResults, sr)
-					visibleCount += sessionMatches
-				} else {
-					// Need to truncate within this session
-					remaining := maxVisibleMatches - visibleCount
-					truncatedSession := SessionSearchResult{
-						Session:   sr.Session,
-						Collapsed: sr.Collapsed,
+					if remaining <= 0 {
+						break
 					}
-					for _, mm := range sr.Messages {
-						if remaining <= 0 {
-							break
+					if len(mm.Matches) <= remaining {
+						truncatedSession.Messages = append(truncatedSession.Messages, mm)
+						remaining -= len(mm.Matches)
+					} else {
+						// Truncate matches within message
+						truncatedMsg := adapter.MessageMatch{
+							MessageID:  mm.MessageID,
+							MessageIdx: mm.MessageIdx,
+							Role:       mm.Role,
+							Timestamp:  mm.Timestamp,
+							Model:      mm.Model,
+							Matches:    mm.Matches[:remaining],
 						}
-						if len(mm.Matches) <= remaining {
-							truncatedSession.Messages = append(truncatedSession.Messages, mm)
-							remaining -= len(mm.Matches)
-						} else {
-							// Truncate matches within message
-							truncatedMsg := adapter.MessageMatch{
-								MessageID:  mm.MessageID,
-								MessageIdx: mm.MessageIdx,
-								Role:       mm.Role,
-								Timestamp:  mm.Timestamp,
-								Model:      mm.Model,
-								Matches:    mm.Matches[:remaining],
-							}
-							truncatedSession.Messages = append(truncatedSession.Messages, truncatedMsg)
-							remaining = 0
-						}
-					}
-					if len(truncatedSession.Messages) > 0 {
-						truncatedResults = append(truncatedResults, truncatedSession)
+						truncatedSession.Messages = append(truncatedSession.Messages, truncatedMsg)
+						remaining = 0
 					}
-					break
 				}
+				if len(truncatedSession.Messages) > 0 {
+					truncatedResults = append(truncatedResults, truncatedSession)
+				}
+				break
 			}
-			results = truncatedResults
 		}
+		results = truncatedResults
+	}
 
-		// Include query in results for staleness validation (td-5b9928)
-		return ContentSearchResultsMsg{
-			Epoch:        epoch,
-			Results:      results,
-			Query:        query,
-			TotalMatches: totalFound,
-			Truncated:    truncated,
-		}
+	// Include query in results for staleness validation (td-5b9928)
+	return ContentSearchResultsMsg{
+		Epoch:        epoch,
+		Results:      results,
+		Query:        query,
+		TotalMatches: totalFound,
+		Truncated:    truncated,
 	}
 }
 
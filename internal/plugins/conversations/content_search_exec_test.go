@@ -2,10 +2,12 @@ package conversations
 
 import (
 	"io"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/marcus/sidecar/internal/adapter"
+	"github.com/marcus/sidecar/internal/searchindex"
 )
 
 // nopCloser is a no-op io.Closer for mock adapters.
@@ -322,3 +324,74 @@ func TestScheduleContentSearch(t *testing.T) {
 		t.Errorf("elapsed = %v, expected >= 150ms (debounce delay is 200ms)", elapsed)
 	}
 }
+
+func TestRunIndexedContentSearch_IndexedAndStale(t *testing.T) {
+	idx, err := searchindex.Open(filepath.Join(t.TempDir(), searchindex.FileName))
+	if err != nil {
+		t.Fatalf("open index: %v", err)
+	}
+	defer func() { _ = idx.Close() }()
+
+	now := time.Now()
+	indexed := adapter.Session{ID: "indexed", AdapterID: "mock", UpdatedAt: now.Add(-time.Hour), MessageCount: 1}
+	stale := adapter.Session{ID: "stale", AdapterID: "mock", UpdatedAt: now, MessageCount: 1}
+
+	if err := idx.IndexSession(indexed, []adapter.Message{
+		{ID: "m1", Role: "user", Content: "refactor the parser"},
+	}); err != nil {
+		t.Fatalf("IndexSession: %v", err)
+	}
+
+	// Only the stale session is scanned through the adapter
+	mock := &mockSearchAdapter{
+		id: "mock",
+		results: map[string][]adapter.MessageMatch{
+			"indexed": {{MessageID: "wrong", Matches: []adapter.ContentMatch{{LineNo: 1}}}},
+			"stale":   {{MessageID: "s1", Matches: []adapter.ContentMatch{{LineNo: 1}}}},
+		},
+	}
+	adapters := map[string]adapter.Adapter{"mock": mock}
+
+	cmd := RunIndexedContentSearch("parser", []adapter.Session{stale, indexed}, adapters,
+		idx, adapter.DefaultSearchOptions(), 0)
+	msg := cmd().(ContentSearchResultsMsg)
+
+	if len(msg.Results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(msg.Results))
+	}
+	if got := msg.Results[0]; got.Session.ID != "indexed" || got.Messages[0].MessageID != "m1" {
+		t.Errorf("expected indexed hit first from index, got %+v", got)
+	}
+	if got := msg.Results[1]; got.Session.ID != "stale" || got.Messages[0].MessageID != "s1" {
+		t.Errorf("expected stale session scanned via adapter, got %+v", got)
+	}
+	if msg.TotalMatches != 2 {
+		t.Errorf("expected 2 total matches, got %d", msg.TotalMatches)
+	}
+}
+
+func TestRunIndexedContentSearch_MidWordQuery(t *testing.T) {
+	idx, err := searchindex.Open(filepath.Join(t.TempDir(), searchindex.FileName))
+	if err != nil {
+		t.Fatalf("open index: %v", err)
+	}
+	defer func() { _ = idx.Close() }()
+
+	s := adapter.Session{ID: "s1", AdapterID: "mock", UpdatedAt: time.Now(), MessageCount: 1}
+	if err := idx.IndexSession(s, []adapter.Message{
+		{ID: "m1", Role: "user", Content: "more research needed"},
+	}); err != nil {
+		t.Fatalf("IndexSession: %v", err)
+	}
+	adapters := map[string]adapter.Adapter{"mock": &mockSearchAdapter{id: "mock"}}
+
+	// The substring scan finds "earch" inside "research"; the index must too
+	if !searchindex.Supports("earch", adapter.DefaultSearchOptions()) {
+		t.Fatal("a literal query should use the index")
+	}
+	msg := RunIndexedContentSearch("earch", []adapter.Session{s}, adapters,
+		idx, adapter.DefaultSearchOptions(), 0)().(ContentSearchResultsMsg)
+	if len(msg.Results) != 1 || msg.Results[0].Messages[0].MessageID != "m1" {
+		t.Errorf("expected mid-word hit from the index, got %+v", msg.Results)
+	}
+}
@@ -2,8 +2,6 @@ package conversations
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -100,25 +98,7 @@ func CopyToClipboard(content string) error {
 
 // ExportSessionToFile writes a session to a markdown file.
 func ExportSessionToFile(session *adapter.Session, messages []adapter.Message, workDir string) (string, error) {
-	md := ExportSessionAsMarkdown(session, messages)
-
-	// Generate filename from session name or ID
-	name := "session"
-	if session != nil && session.Name != "" {
-		name = sanitizeFilename(session.Name)
-	} else if session != nil {
-		name = session.ID[:8]
-	}
-
-	timestamp := time.Now().Format("20060102-150405")
-	filename := fmt.Sprintf("%s-%s.md", name, timestamp)
-	path := filepath.Join(workDir, filename)
-
-	if err := os.WriteFile(path, []byte(md), 0644); err != nil {
-		return "", err
-	}
-
-	return filename, nil
+	return ExportSessionToFileAs(markdownExporter{}, session, messages, workDir)
 }
 
 // formatExportDuration formats duration for export.
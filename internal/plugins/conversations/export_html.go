@@ -0,0 +1,168 @@
+package conversations
+
+import (
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/marcus/sidecar/internal/adapter"
+)
+
+// htmlExporter writes a self-contained HTML page. Tool calls and thinking
+// are collapsible <details> elements, so no JavaScript is needed.
+type htmlExporter struct{}
+
+func (htmlExporter) ID() string        { return "html" }
+func (htmlExporter) Name() string      { return "HTML" }
+func (htmlExporter) Extension() string { return "html" }
+
+// htmlBlock is a render-ready content block.
+type htmlBlock struct {
+	Kind    string // "text", "thinking", "tool"
+	Text    string
+	Tool    string
+	Input   string
+	Output  string
+	IsError bool
+}
+
+// htmlMessage is a render-ready message.
+type htmlMessage struct {
+	Role      string
+	Timestamp time.Time
+	Model     string
+	Usage     adapter.TokenUsage
+	Blocks    []htmlBlock
+}
+
+type htmlPage struct {
+	Title    string
+	Session  *adapter.Session
+	Messages []htmlMessage
+}
+
+func (htmlExporter) Export(w io.Writer, session *adapter.Session, messages []adapter.Message) error {
+	page := htmlPage{Title: exportSessionTitle(session), Session: session}
+	results := toolResultIndex(messages)
+	for _, msg := range messages {
+		hm := htmlMessage{
+			Role:      msg.Role,
+			Timestamp: msg.Timestamp,
+			Model:     msg.Model,
+			Usage:     msg.TokenUsage,
+			Blocks:    htmlBlocks(&msg, results),
+		}
+		if len(hm.Blocks) == 0 {
+			continue // e.g. user messages carrying only linked tool results
+		}
+		page.Messages = append(page.Messages, hm)
+	}
+	return htmlTemplate.Execute(w, page)
+}
+
+// htmlBlocks converts a message into render-ready blocks, attaching tool
+// results to the tool call they answer.
+func htmlBlocks(msg *adapter.Message, results map[string]adapter.ContentBlock) []htmlBlock {
+	var blocks []htmlBlock
+	if len(msg.ContentBlocks) > 0 {
+		for _, cb := range msg.ContentBlocks {
+			switch cb.Type {
+			case "text":
+				if cb.Text != "" {
+					blocks = append(blocks, htmlBlock{Kind: "text", Text: cb.Text})
+				}
+			case "thinking":
+				if cb.Text != "" {
+					blocks = append(blocks, htmlBlock{Kind: "thinking", Text: cb.Text})
+				}
+			case "tool_use":
+				result := results[cb.ToolUseID]
+				blocks = append(blocks, htmlBlock{
+					Kind: "tool", Tool: cb.ToolName, Input: cb.ToolInput,
+					Output: result.ToolOutput, IsError: result.IsError,
+				})
+			case "tool_result":
+				if _, linked := results[cb.ToolUseID]; !linked {
+					blocks = append(blocks, htmlBlock{Kind: "tool", Tool: "result", Output: cb.ToolOutput, IsError: cb.IsError})
+				}
+			}
+		}
+		return blocks
+	}
+
+	for _, tb := range msg.ThinkingBlocks {
+		blocks = append(blocks, htmlBlock{Kind: "thinking", Text: tb.Content})
+	}
+	if msg.Content != "" {
+		blocks = append(blocks, htmlBlock{Kind: "text", Text: msg.Content})
+	}
+	for _, tu := range msg.ToolUses {
+		blocks = append(blocks, htmlBlock{Kind: "tool", Tool: tu.Name, Input: tu.Input, Output: tu.Output})
+	}
+	return blocks
+}
+
+var htmlTemplate = template.Must(template.New("session").Funcs(template.FuncMap{
+	"ts":       func(t time.Time) string { return t.Format("2006-01-02 15:04:05") },
+	"duration": formatExportDuration,
+	"oneLine": func(s string) string {
+		s = oneLine(s)
+		if r := []rune(s); len(r) > 80 {
+			return string(r[:79]) + "…"
+		}
+		return s
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; max-width: 960px; margin: 2rem auto; padding: 0 1rem; color: #1f2328; background: #fff; line-height: 1.5; }
+header { border-bottom: 1px solid #d0d7de; margin-bottom: 1.5rem; }
+header dl { display: grid; grid-template-columns: max-content 1fr; gap: .25rem 1rem; font-size: .9rem; }
+header dt { color: #59636e; }
+.msg { border: 1px solid #d0d7de; border-radius: 6px; margin: 1rem 0; }
+.msg > .meta { padding: .4rem .75rem; font-size: .85rem; color: #59636e; background: #f6f8fa; border-bottom: 1px solid #d0d7de; border-radius: 6px 6px 0 0; }
+.msg.user > .meta { background: #ddf4ff; }
+.msg > .meta .role { font-weight: 600; color: #1f2328; text-transform: capitalize; }
+.body { padding: .5rem .75rem; }
+pre { white-space: pre-wrap; word-wrap: break-word; font-family: ui-monospace, SFMono-Regular, Menlo, monospace; font-size: .85rem; margin: .25rem 0; }
+.text pre { font-family: inherit; font-size: 1rem; }
+details { border: 1px solid #d0d7de; border-radius: 4px; margin: .4rem 0; background: #f6f8fa; }
+details > summary { cursor: pointer; padding: .25rem .5rem; font-family: ui-monospace, SFMono-Regular, Menlo, monospace; font-size: .85rem; }
+details > div { padding: .25rem .5rem; border-top: 1px solid #d0d7de; background: #fff; }
+details.thinking > summary { font-style: italic; color: #59636e; }
+details.error > summary { color: #cf222e; }
+.label { font-size: .75rem; color: #59636e; text-transform: uppercase; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Title}}</h1>
+{{with .Session}}<dl>
+{{if .AdapterName}}<dt>Agent</dt><dd>{{.AdapterName}}</dd>{{end}}
+<dt>Session</dt><dd><code>{{.ID}}</code></dd>
+<dt>Date</dt><dd>{{ts .CreatedAt}}</dd>
+{{if .Duration}}<dt>Duration</dt><dd>{{duration .Duration}}</dd>{{end}}
+{{if .TotalTokens}}<dt>Tokens</dt><dd>{{.TotalTokens}}</dd>{{end}}
+{{if .EstCost}}<dt>Estimated cost</dt><dd>${{printf "%.2f" .EstCost}}</dd>{{end}}
+</dl>{{end}}
+</header>
+<main>
+{{range .Messages}}<section class="msg {{.Role}}">
+<div class="meta"><span class="role">{{.Role}}</span> · {{ts .Timestamp}}{{if .Model}} · {{.Model}}{{end}}{{if or .Usage.InputTokens .Usage.OutputTokens}} · in {{.Usage.InputTokens}} / out {{.Usage.OutputTokens}}{{if .Usage.CacheRead}} / cache {{.Usage.CacheRead}}{{end}}{{end}}</div>
+<div class="body">
+{{range .Blocks}}{{if eq .Kind "text"}}<div class="text"><pre>{{.Text}}</pre></div>
+{{else if eq .Kind "thinking"}}<details class="thinking"><summary>Thinking</summary><div><pre>{{.Text}}</pre></div></details>
+{{else}}<details class="tool{{if .IsError}} error{{end}}"><summary>{{.Tool}}{{if .Input}} {{oneLine .Input}}{{end}}</summary><div>
+{{if .Input}}<div class="label">Input</div><pre>{{.Input}}</pre>{{end}}
+{{if .Output}}<div class="label">{{if .IsError}}Error{{else}}Result{{end}}</div><pre>{{.Output}}</pre>{{end}}
+</div></details>
+{{end}}{{end}}</div>
+</section>
+{{end}}</main>
+</body>
+</html>
+`))
@@ -0,0 +1,163 @@
+package conversations
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/marcus/sidecar/internal/adapter"
+)
+
+// JSONExportFormat identifies sidecar's session JSON documents.
+const JSONExportFormat = "sidecar-session"
+
+// JSONExportVersion is bumped on incompatible changes to the JSON schema.
+const JSONExportVersion = 1
+
+// ExportDocument is the lossless JSON export of a session. Field names are
+// stable so other tools can consume archived sessions.
+type ExportDocument struct {
+	Format     string          `json:"format"`
+	Version    int             `json:"version"`
+	ExportedAt time.Time       `json:"exported_at"`
+	Session    ExportedSession `json:"session"`
+	Messages   []ExportedMsg   `json:"messages"`
+}
+
+// ExportedSession is session metadata in an ExportDocument.
+type ExportedSession struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name,omitempty"`
+	Slug           string    `json:"slug,omitempty"`
+	AdapterID      string    `json:"adapter_id"`
+	AdapterName    string    `json:"adapter_name,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	DurationMs     int64     `json:"duration_ms"`
+	IsSubAgent     bool      `json:"is_sub_agent,omitempty"`
+	MessageCount   int       `json:"message_count"`
+	TotalTokens    int       `json:"total_tokens"`
+	EstCost        float64   `json:"est_cost"`
+	UnpricedModels []string  `json:"unpriced_models,omitempty"`
+	WorktreeName   string    `json:"worktree_name,omitempty"`
+	WorktreePath   string    `json:"worktree_path,omitempty"`
+}
+
+// ExportedMsg is a message in an ExportDocument.
+type ExportedMsg struct {
+	ID            string          `json:"id"`
+	Role          string          `json:"role"`
+	Timestamp     time.Time       `json:"timestamp"`
+	Model         string          `json:"model,omitempty"`
+	Content       string          `json:"content"`
+	Usage         *ExportedUsage  `json:"usage,omitempty"`
+	ContentBlocks []ExportedBlock `json:"content_blocks,omitempty"`
+	ToolUses      []ExportedTool  `json:"tool_uses,omitempty"`
+	Thinking      []ExportedThink `json:"thinking,omitempty"`
+}
+
+// ExportedUsage is per-message token usage.
+type ExportedUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+	CacheRead    int `json:"cache_read,omitempty"`
+	CacheWrite   int `json:"cache_write,omitempty"`
+}
+
+// ExportedBlock is a structured content block. ToolUseID links tool_use
+// blocks to their tool_result blocks.
+type ExportedBlock struct {
+	Type       string `json:"type"`
+	Text       string `json:"text,omitempty"`
+	ToolUseID  string `json:"tool_use_id,omitempty"`
+	ToolName   string `json:"tool_name,omitempty"`
+	ToolInput  string `json:"tool_input,omitempty"`
+	ToolOutput string `json:"tool_output,omitempty"`
+	IsError    bool   `json:"is_error,omitempty"`
+	TokenCount int    `json:"token_count,omitempty"`
+}
+
+// ExportedTool is a tool call recorded directly on a message.
+type ExportedTool struct {
+	ID     string `json:"id,omitempty"`
+	Name   string `json:"name"`
+	Input  string `json:"input,omitempty"`
+	Output string `json:"output,omitempty"`
+}
+
+// ExportedThink is a thinking block recorded directly on a message.
+type ExportedThink struct {
+	Content    string `json:"content"`
+	TokenCount int    `json:"token_count,omitempty"`
+}
+
+// NewExportDocument builds the JSON export document for a session.
+func NewExportDocument(session *adapter.Session, messages []adapter.Message) ExportDocument {
+	doc := ExportDocument{
+		Format:     JSONExportFormat,
+		Version:    JSONExportVersion,
+		ExportedAt: time.Now().UTC(),
+		Messages:   make([]ExportedMsg, 0, len(messages)),
+	}
+	if session != nil {
+		doc.Session = ExportedSession{
+			ID:             session.ID,
+			Name:           session.Name,
+			Slug:           session.Slug,
+			AdapterID:      session.AdapterID,
+			AdapterName:    session.AdapterName,
+			CreatedAt:      session.CreatedAt,
+			UpdatedAt:      session.UpdatedAt,
+			DurationMs:     session.Duration.Milliseconds(),
+			IsSubAgent:     session.IsSubAgent,
+			MessageCount:   session.MessageCount,
+			TotalTokens:    session.TotalTokens,
+			EstCost:        session.EstCost,
+			UnpricedModels: session.UnpricedModels,
+			WorktreeName:   session.WorktreeName,
+			WorktreePath:   session.WorktreePath,
+		}
+	}
+
+	for _, msg := range messages {
+		em := ExportedMsg{
+			ID:        msg.ID,
+			Role:      msg.Role,
+			Timestamp: msg.Timestamp,
+			Model:     msg.Model,
+			Content:   msg.Content,
+		}
+		if msg.TokenUsage != (adapter.TokenUsage{}) {
+			em.Usage = &ExportedUsage{
+				InputTokens:  msg.InputTokens,
+				OutputTokens: msg.OutputTokens,
+				CacheRead:    msg.CacheRead,
+				CacheWrite:   msg.CacheWrite,
+			}
+		}
+		for _, cb := range msg.ContentBlocks {
+			em.ContentBlocks = append(em.ContentBlocks, ExportedBlock(cb))
+		}
+		for _, tu := range msg.ToolUses {
+			em.ToolUses = append(em.ToolUses, ExportedTool(tu))
+		}
+		for _, tb := range msg.ThinkingBlocks {
+			em.Thinking = append(em.Thinking, ExportedThink(tb))
+		}
+		doc.Messages = append(doc.Messages, em)
+	}
+	return doc
+}
+
+// jsonExporter writes the lossless ExportDocument format.
+type jsonExporter struct{}
+
+func (jsonExporter) ID() string        { return "json" }
+func (jsonExporter) Name() string      { return "JSON" }
+func (jsonExporter) Extension() string { return "json" }
+
+func (jsonExporter) Export(w io.Writer, session *adapter.Session, messages []adapter.Message) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(NewExportDocument(session, messages))
+}
@@ -0,0 +1,205 @@
+package conversations
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/app"
+	"github.com/marcus/sidecar/internal/modal"
+	"github.com/marcus/sidecar/internal/ui"
+)
+
+// Export modal field IDs
+const (
+	exportFormatListID     = "export-format-list"
+	exportSubmitID         = "export-submit"
+	exportCopyID           = "export-copy"
+	exportCancelID         = "export-cancel"
+	exportFormatItemPrefix = "export-format-"
+)
+
+// ensureExportModal builds or caches the export modal.
+func (p *Plugin) ensureExportModal() {
+	if p.exportSession == nil {
+		return
+	}
+
+	modalW := 44
+	maxW := p.width - 4
+	if maxW < 20 {
+		maxW = 20
+	}
+	if modalW > maxW {
+		modalW = maxW
+	}
+
+	if p.exportModal != nil && p.exportModalWidth == modalW {
+		return
+	}
+	p.exportModalWidth = modalW
+
+	formats := Exporters()
+	items := make([]modal.ListItem, len(formats))
+	for i, e := range formats {
+		items[i] = modal.ListItem{
+			ID:    fmt.Sprintf("%s%d", exportFormatItemPrefix, i),
+			Label: fmt.Sprintf("%s (.%s)", e.Name(), e.Extension()),
+		}
+	}
+
+	p.exportModal = modal.New("Export Session",
+		modal.WithWidth(modalW),
+		modal.WithPrimaryAction(exportSubmitID),
+		modal.WithHints(false),
+	).
+		AddSection(modal.Text("Session: " + exportSessionTitle(p.exportSession))).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Text("Format:")).
+		AddSection(modal.List(exportFormatListID, items, &p.exportFormatIdx, modal.WithMaxVisible(len(items)))).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Buttons(
+			modal.Btn(" Export ", exportSubmitID),
+			modal.Btn(" Copy ", exportCopyID),
+			modal.Btn(" Cancel ", exportCancelID),
+		))
+}
+
+// openExportModal opens the export format picker for the selected session.
+func (p *Plugin) openExportModal() tea.Cmd {
+	session := p.findSelectedSession()
+	if session == nil {
+		return func() tea.Msg {
+			return app.ToastMsg{Message: "No session selected", IsError: true}
+		}
+	}
+	if p.exportFormatIdx < 0 || p.exportFormatIdx >= len(Exporters()) {
+		p.exportFormatIdx = 0
+	}
+	p.exportSession = session
+	p.exportModal = nil
+	p.showExportModal = true
+	return nil
+}
+
+// resetExportModal closes the export modal. The chosen format is kept so
+// the next export defaults to it.
+func (p *Plugin) resetExportModal() {
+	p.showExportModal = false
+	p.exportModal = nil
+	p.exportSession = nil
+}
+
+// handleExportAction applies a modal action. Selecting a format with enter
+// exports immediately.
+func (p *Plugin) handleExportAction(action string) tea.Cmd {
+	switch action {
+	case exportSubmitID:
+		return p.executeExport(false)
+	case exportCopyID:
+		return p.executeExport(true)
+	case exportCancelID, "cancel":
+		p.resetExportModal()
+		return nil
+	}
+
+	if strings.HasPrefix(action, exportFormatItemPrefix) {
+		var idx int
+		_, _ = fmt.Sscanf(action, exportFormatItemPrefix+"%d", &idx)
+		if idx >= 0 && idx < len(Exporters()) {
+			p.exportFormatIdx = idx
+			return p.executeExport(false)
+		}
+	}
+	return nil
+}
+
+// handleExportModalKeys handles keyboard input for the export modal.
+func (p *Plugin) handleExportModalKeys(msg tea.KeyMsg) tea.Cmd {
+	p.ensureExportModal()
+	if p.exportModal == nil {
+		return nil
+	}
+	action, cmd := p.exportModal.HandleKey(msg)
+	if action != "" {
+		return p.handleExportAction(action)
+	}
+	return cmd
+}
+
+// handleExportModalMouse handles mouse input for the export modal.
+func (p *Plugin) handleExportModalMouse(msg tea.MouseMsg) tea.Cmd {
+	p.ensureExportModal()
+	if p.exportModal == nil {
+		return nil
+	}
+	action := p.exportModal.HandleMouse(msg, p.mouseHandler)
+	if strings.HasPrefix(action, exportFormatItemPrefix) {
+		// A click selects the format; export on the button
+		var idx int
+		_, _ = fmt.Sscanf(action, exportFormatItemPrefix+"%d", &idx)
+		if idx >= 0 && idx < len(Exporters()) {
+			p.exportFormatIdx = idx
+		}
+		return nil
+	}
+	return p.handleExportAction(action)
+}
+
+// renderExportModal renders the export modal over the background.
+func (p *Plugin) renderExportModal(width, height int) string {
+	p.ensureExportModal()
+	if p.exportModal == nil {
+		return ""
+	}
+	background := p.renderTwoPane()
+	rendered := p.exportModal.Render(width, height, p.mouseHandler)
+	return ui.OverlayModal(background, rendered, width, height)
+}
+
+// executeExport exports the session in the chosen format, to a file in the
+// work dir or to the clipboard. Messages are reloaded from the adapter so
+// the export is complete even when the view holds only a page of them.
+func (p *Plugin) executeExport(toClipboard bool) tea.Cmd {
+	session := p.exportSession
+	formats := Exporters()
+	if session == nil || p.exportFormatIdx < 0 || p.exportFormatIdx >= len(formats) {
+		p.resetExportModal()
+		return nil
+	}
+	exp := formats[p.exportFormatIdx]
+	a := p.adapters[session.AdapterID]
+	fallback := p.messages
+	if p.loadedSession != session.ID {
+		fallback = nil
+	}
+	workDir := p.ctx.WorkDir
+	p.resetExportModal()
+
+	return func() tea.Msg {
+		messages := fallback
+		if a != nil {
+			if msgs, err := a.Messages(session.ID); err == nil {
+				messages = msgs
+			}
+		}
+
+		if toClipboard {
+			content, err := ExportSession(exp, session, messages)
+			if err == nil {
+				err = CopyToClipboard(content)
+			}
+			if err != nil {
+				return app.ToastMsg{Message: "Copy failed: " + err.Error(), Duration: 2 * time.Second, IsError: true}
+			}
+			return app.ToastMsg{Message: "Session copied as " + exp.Name(), Duration: 2 * time.Second}
+		}
+
+		filename, err := ExportSessionToFileAs(exp, session, messages, workDir)
+		if err != nil {
+			return app.ToastMsg{Message: "Export failed: " + err.Error(), Duration: 2 * time.Second, IsError: true}
+		}
+		return app.ToastMsg{Message: "Exported to " + filename, Duration: 2 * time.Second}
+	}
+}
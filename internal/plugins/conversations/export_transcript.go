@@ -0,0 +1,127 @@
+package conversations
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/marcus/sidecar/internal/adapter"
+)
+
+// transcriptExporter writes a normalized plain-text transcript: the same
+// layout for every adapter, with tool calls shown next to their results.
+type transcriptExporter struct{}
+
+func (transcriptExporter) ID() string        { return "transcript" }
+func (transcriptExporter) Name() string      { return "Transcript" }
+func (transcriptExporter) Extension() string { return "txt" }
+
+func (transcriptExporter) Export(w io.Writer, session *adapter.Session, messages []adapter.Message) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "Session: %s\n", exportSessionTitle(session))
+	if session != nil {
+		if session.AdapterName != "" {
+			fmt.Fprintf(bw, "Agent: %s\n", session.AdapterName)
+		}
+		fmt.Fprintf(bw, "ID: %s\n", session.ID)
+		fmt.Fprintf(bw, "Date: %s\n", session.CreatedAt.Format("2006-01-02 15:04"))
+		if session.Duration > 0 {
+			fmt.Fprintf(bw, "Duration: %s\n", formatExportDuration(session.Duration))
+		}
+		if session.TotalTokens > 0 {
+			fmt.Fprintf(bw, "Tokens: %d\n", session.TotalTokens)
+		}
+	}
+	bw.WriteString(strings.Repeat("=", 72) + "\n")
+
+	results := toolResultIndex(messages)
+	for _, msg := range messages {
+		body := transcriptBody(&msg, results)
+		if body == "" {
+			continue // e.g. user messages carrying only linked tool results
+		}
+
+		header := fmt.Sprintf("\n[%s] %s", msg.Timestamp.Format("2006-01-02 15:04:05"), strings.ToUpper(msg.Role))
+		if msg.Model != "" {
+			header += " (" + msg.Model + ")"
+		}
+		if msg.InputTokens > 0 || msg.OutputTokens > 0 {
+			header += fmt.Sprintf(" [in=%d out=%d]", msg.InputTokens, msg.OutputTokens)
+		}
+		bw.WriteString(header + "\n")
+		bw.WriteString(body)
+	}
+
+	return bw.Flush()
+}
+
+// transcriptBody renders a message's content, thinking and tool calls.
+func transcriptBody(msg *adapter.Message, results map[string]adapter.ContentBlock) string {
+	var sb strings.Builder
+	writeText := func(text string) {
+		if text = strings.TrimRight(text, "\n"); text != "" {
+			sb.WriteString(text + "\n")
+		}
+	}
+	writeTool := func(name, input, output string, isError bool) {
+		sb.WriteString("> tool: " + name)
+		if input != "" {
+			sb.WriteString(" " + oneLine(input))
+		}
+		sb.WriteString("\n")
+		if output != "" {
+			label := "< result:"
+			if isError {
+				label = "< error:"
+			}
+			sb.WriteString("  " + label + "\n" + indentLines(strings.TrimRight(output, "\n"), "    ") + "\n")
+		}
+	}
+
+	if len(msg.ContentBlocks) > 0 {
+		for _, cb := range msg.ContentBlocks {
+			switch cb.Type {
+			case "text":
+				writeText(cb.Text)
+			case "thinking":
+				if cb.Text != "" {
+					sb.WriteString("  (thinking)\n" + indentLines(strings.TrimRight(cb.Text, "\n"), "    ") + "\n")
+				}
+			case "tool_use":
+				result := results[cb.ToolUseID]
+				writeTool(cb.ToolName, cb.ToolInput, result.ToolOutput, result.IsError)
+			case "tool_result":
+				// Linked results are printed with their tool call
+				if _, linked := results[cb.ToolUseID]; !linked {
+					writeTool("(result)", "", cb.ToolOutput, cb.IsError)
+				}
+			}
+		}
+		return sb.String()
+	}
+
+	for _, tb := range msg.ThinkingBlocks {
+		sb.WriteString("  (thinking)\n" + indentLines(strings.TrimRight(tb.Content, "\n"), "    ") + "\n")
+	}
+	writeText(msg.Content)
+	for _, tu := range msg.ToolUses {
+		writeTool(tu.Name, tu.Input, tu.Output, false)
+	}
+	return sb.String()
+}
+
+// oneLine collapses whitespace so a value fits on a single line.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// indentLines prefixes every line of s with prefix.
+func indentLines(s, prefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = prefix + l
+	}
+	return strings.Join(lines, "\n")
+}
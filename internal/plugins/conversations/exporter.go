@@ -0,0 +1,159 @@
+package conversations
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/marcus/sidecar/internal/adapter"
+)
+
+// Exporter renders a session and its messages in one output format.
+type Exporter interface {
+	// ID is the format identifier used by the CLI and UI (e.g. "json").
+	ID() string
+	// Name is the human-readable format name.
+	Name() string
+	// Extension is the file extension without the dot.
+	Extension() string
+	// Export writes the session to w.
+	Export(w io.Writer, session *adapter.Session, messages []adapter.Message) error
+}
+
+// exporters holds registered exporters in registration order.
+var exporters []Exporter
+
+// RegisterExporter registers an exporter, replacing any existing exporter
+// with the same ID.
+func RegisterExporter(e Exporter) {
+	for i, existing := range exporters {
+		if existing.ID() == e.ID() {
+			exporters[i] = e
+			return
+		}
+	}
+	exporters = append(exporters, e)
+}
+
+// Exporters returns the registered exporters in registration order.
+func Exporters() []Exporter {
+	out := make([]Exporter, len(exporters))
+	copy(out, exporters)
+	return out
+}
+
+// ExporterIDs returns the IDs of the registered exporters.
+func ExporterIDs() []string {
+	ids := make([]string, len(exporters))
+	for i, e := range exporters {
+		ids[i] = e.ID()
+	}
+	return ids
+}
+
+// LookupExporter finds an exporter by ID or file extension
+// (e.g. "markdown" or "md").
+func LookupExporter(format string) (Exporter, bool) {
+	format = strings.ToLower(strings.TrimPrefix(format, "."))
+	for _, e := range exporters {
+		if e.ID() == format || e.Extension() == format {
+			return e, true
+		}
+	}
+	return nil, false
+}
+
+// ExportSession renders a session with the given exporter.
+func ExportSession(e Exporter, session *adapter.Session, messages []adapter.Message) (string, error) {
+	var buf bytes.Buffer
+	if err := e.Export(&buf, session, messages); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// ExportSessionToFileAs writes a session to a file in workDir using the given
+// exporter. Returns the filename.
+func ExportSessionToFileAs(e Exporter, session *adapter.Session, messages []adapter.Message, workDir string) (string, error) {
+	content, err := ExportSession(e, session, messages)
+	if err != nil {
+		return "", err
+	}
+
+	// Generate filename from session name or ID
+	name := "session"
+	if session != nil && session.Name != "" {
+		name = sanitizeFilename(session.Name)
+	} else if session != nil {
+		name = session.ID[:min(8, len(session.ID))]
+	}
+
+	timestamp := time.Now().Format("20060102-150405")
+	filename := fmt.Sprintf("%s-%s.%s", name, timestamp, e.Extension())
+	path := filepath.Join(workDir, filename)
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return "", err
+	}
+
+	return filename, nil
+}
+
+// markdownExporter wraps ExportSessionAsMarkdown.
+type markdownExporter struct{}
+
+func (markdownExporter) ID() string        { return "markdown" }
+func (markdownExporter) Name() string      { return "Markdown" }
+func (markdownExporter) Extension() string { return "md" }
+
+func (markdownExporter) Export(w io.Writer, session *adapter.Session, messages []adapter.Message) error {
+	_, err := io.WriteString(w, ExportSessionAsMarkdown(session, messages))
+	return err
+}
+
+func init() {
+	RegisterExporter(markdownExporter{})
+	RegisterExporter(jsonExporter{})
+	RegisterExporter(htmlExporter{})
+	RegisterExporter(transcriptExporter{})
+}
+
+// toolResultIndex maps tool_use IDs to their results across a session, so
+// exporters can render a tool call together with its output even when the
+// result arrives in a later message. Results without a matching tool_use in
+// the session are left out and rendered on their own.
+func toolResultIndex(messages []adapter.Message) map[string]adapter.ContentBlock {
+	uses := make(map[string]bool)
+	for _, msg := range messages {
+		for _, cb := range msg.ContentBlocks {
+			if cb.Type == "tool_use" && cb.ToolUseID != "" {
+				uses[cb.ToolUseID] = true
+			}
+		}
+	}
+	results := make(map[string]adapter.ContentBlock)
+	for _, msg := range messages {
+		for _, cb := range msg.ContentBlocks {
+			if cb.Type == "tool_result" && uses[cb.ToolUseID] {
+				results[cb.ToolUseID] = cb
+			}
+		}
+	}
+	return results
+}
+
+// exportSessionTitle returns the display title for an exported session.
+func exportSessionTitle(session *adapter.Session) string {
+	switch {
+	case session == nil:
+		return "Unknown Session"
+	case session.Name != "":
+		return session.Name
+	default:
+		return session.ID
+	}
+}
@@ -0,0 +1,180 @@
+package conversations
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcus/sidecar/internal/adapter"
+)
+
+func exportFixture() (*adapter.Session, []adapter.Message) {
+	ts := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	session := &adapter.Session{
+		ID:          "abc123def456",
+		Name:        "Fix <login> bug",
+		AdapterID:   "claude-code",
+		AdapterName: "Claude Code",
+		CreatedAt:   ts,
+		UpdatedAt:   ts.Add(5 * time.Minute),
+		Duration:    5 * time.Minute,
+		TotalTokens: 1500,
+	}
+	messages := []adapter.Message{
+		{ID: "m1", Role: "user", Content: "Fix the login bug", Timestamp: ts},
+		{
+			ID: "m2", Role: "assistant", Timestamp: ts.Add(time.Minute),
+			Model:      "claude-sonnet-4-5-20250929",
+			TokenUsage: adapter.TokenUsage{InputTokens: 1000, OutputTokens: 500, CacheRead: 200},
+			Content:    "Let me look.",
+			ContentBlocks: []adapter.ContentBlock{
+				{Type: "thinking", Text: "Check auth.go first", TokenCount: 5},
+				{Type: "text", Text: "Let me look."},
+				{Type: "tool_use", ToolUseID: "tu1", ToolName: "Read", ToolInput: `{"file_path":"auth.go"}`},
+			},
+		},
+		{
+			ID: "m3", Role: "user", Timestamp: ts.Add(2 * time.Minute),
+			ContentBlocks: []adapter.ContentBlock{
+				{Type: "tool_result", ToolUseID: "tu1", ToolOutput: "package auth <script>", IsError: false},
+			},
+		},
+	}
+	return session, messages
+}
+
+func TestLookupExporter(t *testing.T) {
+	for _, format := range []string{"markdown", "md", "json", "html", "transcript", "txt", ".html", "JSON"} {
+		if _, ok := LookupExporter(format); !ok {
+			t.Errorf("LookupExporter(%q) not found", format)
+		}
+	}
+	if _, ok := LookupExporter("pdf"); ok {
+		t.Error("expected unknown format to be rejected")
+	}
+}
+
+func TestJSONExporter_Lossless(t *testing.T) {
+	session, messages := exportFixture()
+	e, _ := LookupExporter("json")
+	out, err := ExportSession(e, session, messages)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	var doc ExportDocument
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if doc.Format != JSONExportFormat || doc.Version != JSONExportVersion {
+		t.Errorf("unexpected format header %q v%d", doc.Format, doc.Version)
+	}
+	if doc.Session.ID != session.ID || doc.Session.AdapterID != "claude-code" {
+		t.Errorf("session metadata lost: %+v", doc.Session)
+	}
+	if len(doc.Messages) != 3 {
+		t.Fatalf("expected 3 messages, got %d", len(doc.Messages))
+	}
+	m2 := doc.Messages[1]
+	if m2.Usage == nil || m2.Usage.InputTokens != 1000 || m2.Usage.CacheRead != 200 {
+		t.Errorf("usage lost: %+v", m2.Usage)
+	}
+	if len(m2.ContentBlocks) != 3 || m2.ContentBlocks[2].ToolUseID != "tu1" || m2.ContentBlocks[0].TokenCount != 5 {
+		t.Errorf("content blocks lost: %+v", m2.ContentBlocks)
+	}
+	if doc.Messages[2].ContentBlocks[0].ToolUseID != "tu1" {
+		t.Error("tool_result link lost")
+	}
+	if doc.Messages[0].Usage != nil {
+		t.Error("expected no usage for message without tokens")
+	}
+}
+
+func TestHTMLExporter(t *testing.T) {
+	session, messages := exportFixture()
+	e, _ := LookupExporter("html")
+	out, err := ExportSession(e, session, messages)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"Fix &lt;login&gt; bug",         // title escaped
+		`<details class="tool">`,        // collapsible tool call
+		"package auth &lt;script&gt;",   // linked result rendered with its call, escaped
+		`<details class="thinking">`,    // thinking collapsible
+		"in 1000 / out 500 / cache 200", // per-message usage
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected HTML to contain %q", want)
+		}
+	}
+	if strings.Contains(out, "<script>") {
+		t.Error("tool output not escaped")
+	}
+	// The tool-result-only user message is folded into the tool call
+	if n := strings.Count(out, `<section class="msg`); n != 2 {
+		t.Errorf("expected 2 rendered messages, got %d", n)
+	}
+}
+
+func TestTranscriptExporter(t *testing.T) {
+	session, messages := exportFixture()
+	e, _ := LookupExporter("transcript")
+	out, err := ExportSession(e, session, messages)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	for _, want := range []string{
+		"Session: Fix <login> bug",
+		"Agent: Claude Code",
+		"[2026-03-01 10:00:00] USER",
+		"ASSISTANT (claude-sonnet-4-5-20250929) [in=1000 out=500]",
+		`> tool: Read {"file_path":"auth.go"}`,
+		"  < result:\n    package auth <script>",
+		"  (thinking)\n    Check auth.go first",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected transcript to contain %q\n%s", want, out)
+		}
+	}
+	if strings.Count(out, "] USER") != 1 {
+		t.Error("expected tool-result-only user message to be folded into the tool call")
+	}
+}
+
+func TestTranscriptExporter_ToolUsesWithoutBlocks(t *testing.T) {
+	messages := []adapter.Message{{
+		ID: "m1", Role: "assistant", Content: "done",
+		ToolUses: []adapter.ToolUse{{Name: "shell", Input: "ls", Output: "a.go"}},
+	}}
+	e, _ := LookupExporter("transcript")
+	out, err := ExportSession(e, nil, messages)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if !strings.Contains(out, "> tool: shell ls") || !strings.Contains(out, "a.go") {
+		t.Errorf("expected tool use in transcript, got:\n%s", out)
+	}
+}
+
+func TestExportSessionToFileAs(t *testing.T) {
+	session, messages := exportFixture()
+	dir := t.TempDir()
+	e, _ := LookupExporter("html")
+	filename, err := ExportSessionToFileAs(e, session, messages, dir)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if !strings.HasSuffix(filename, ".html") {
+		t.Errorf("expected .html extension, got %q", filename)
+	}
+	if _, err := os.Stat(filepath.Join(dir, filename)); err != nil {
+		t.Errorf("expected exported file: %v", err)
+	}
+}
@@ -13,6 +13,9 @@ func (p *Plugin) handleMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
 		cmd := p.handleResumeModalMouse(msg)
 		return p, cmd
 	}
+	if p.showExportModal {
+		return p, p.handleExportModalMouse(msg)
+	}
 
 	action := p.mouseHandler.HandleMouse(msg)
 
@@ -14,10 +14,12 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/marcus/sidecar/internal/adapter"
 	"github.com/marcus/sidecar/internal/adapter/tieredwatcher"
+	"github.com/marcus/sidecar/internal/analytics"
 	"github.com/marcus/sidecar/internal/app"
 	"github.com/marcus/sidecar/internal/modal"
 	"github.com/marcus/sidecar/internal/mouse"
 	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/searchindex"
 	"github.com/marcus/sidecar/internal/state"
 	"github.com/marcus/sidecar/internal/ui"
 )
@@ -134,8 +136,16 @@ type Plugin struct {
 	detailScroll int
 
 	// Analytics view state
-	analyticsScrollOff int
-	analyticsLines     []string // pre-rendered lines for scrolling
+	analyticsScrollOff   int
+	analyticsLines       []string // pre-rendered lines for scrolling
+	analyticsCache       *analytics.Cache
+	analyticsRecords     []analytics.Record // nil until first collected
+	analyticsRoot        string             // current project's path in records
+	analyticsReport      *analytics.Report  // records aggregated for the selected options
+	analyticsLoading     bool
+	analyticsRangeIdx    int  // index into analyticsRanges
+	analyticsIntervalIdx int  // index into analyticsIntervals
+	analyticsAllProjects bool // false = current project only
 
 	// Layout state
 	activePane         FocusPane // Which pane is focused
@@ -158,6 +168,12 @@ type Plugin struct {
 	// Tiered watcher manager for FD reduction (td-dca6fe)
 	tieredManager *tieredwatcher.Manager
 
+	// Persistent full-text index for content search
+	searchIndex      *searchindex.Index
+	indexSyncing     bool                 // a background sync pass is running
+	indexSyncPending bool                 // sessions changed during the running pass
+	indexFailed      map[string]time.Time // session key -> UpdatedAt that failed to index
+
 	// Event coalescing for watch events
 	coalescer         *EventCoalescer
 	coalesceChan      chan CoalescedRefreshMsg
@@ -237,6 +253,13 @@ type Plugin struct {
 	resumeFocus           int
 	resumeSession         *adapter.Session
 
+	// Export modal state
+	showExportModal  bool
+	exportModal      *modal.Modal
+	exportModalWidth int
+	exportFormatIdx  int // index into Exporters(); kept between exports
+	exportSession    *adapter.Session
+
 	// Content search state (td-6ac70a: cross-conversation search)
 	contentSearchMode  bool                // True when content search modal is open
 	contentSearchState *ContentSearchState // Content search state
@@ -283,6 +306,7 @@ func New() *Plugin {
 		renderCache:         make(map[renderCacheKey]string),
 		hitRegionsDirty:     true, // Start dirty to ensure first render builds regions
 		sidebarVisible:      true, // Sidebar visible by default
+		analyticsRangeIdx:   1,    // last 30 days
 		sidebarRestore:      PaneSidebar,
 		warnedSessions:      make(map[string]bool),
 		skeleton:            ui.NewSkeleton(8, nil), // 8 placeholder rows
@@ -302,8 +326,14 @@ func (p *Plugin) Icon() string { return pluginIcon }
 
 // renderContent renders markdown content to styled lines, falling back to plain text.
 func (p *Plugin) renderContent(content string, width int) []string {
-	if p.contentRenderer != nil {
-		return p.contentRenderer.RenderContent(content, width)
+	return renderMarkdown(p.contentRenderer, content, width)
+}
+
+// renderMarkdown renders markdown content with r, or wraps it as plain text
+// when r is nil.
+func renderMarkdown(r *GlamourRenderer, content string, width int) []string {
+	if r != nil {
+		return r.RenderContent(content, width)
 	}
 	return wrapText(content, width)
 }
@@ -355,6 +385,9 @@ func (p *Plugin) resetState() {
 	// Analytics view state
 	p.analyticsScrollOff = 0
 	p.analyticsLines = nil
+	p.analyticsRecords = nil
+	p.analyticsReport = nil
+	p.analyticsLoading = false
 
 	// Layout state - reset to defaults but preserve sidebarWidth (persisted)
 	p.activePane = PaneSidebar
@@ -437,6 +470,8 @@ func (p *Plugin) Init(ctx *plugin.Context) error {
 		p.sidebarWidth = savedWidth
 	}
 
+	p.openSearchIndex()
+
 	p.adapters = make(map[string]adapter.Adapter)
 	for id, a := range ctx.Adapters {
 		found, err := a.Detect(ctx.ProjectRoot)
@@ -486,6 +521,7 @@ func (p *Plugin) Stop() {
 	})
 	p.closeWatchers()
 	p.watchChan = nil
+	p.closeSearchIndex()
 }
 
 func (p *Plugin) closeWatchers() {
@@ -545,6 +581,11 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 			return p, cmd
 		}
 
+		// Handle export modal first if open
+		if p.showExportModal {
+			return p, p.handleExportModalKeys(msg)
+		}
+
 		switch p.view {
 		case ViewAnalytics:
 			return p.updateAnalytics(msg)
@@ -713,12 +754,25 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		if settleCmd != nil {
 			cmds = append(cmds, settleCmd)
 		}
+		if syncCmd := p.syncSearchIndex(); syncCmd != nil {
+			cmds = append(cmds, syncCmd)
+		}
 		p.updateTieredHotTargets()
 		if len(cmds) > 0 {
 			return p, tea.Batch(cmds...)
 		}
 		return p, nil
 
+	case AnalyticsLoadedMsg:
+		return p, p.handleAnalyticsLoaded(msg)
+
+	case SearchIndexSyncedMsg:
+		if plugin.IsStale(p.ctx, msg) {
+			p.indexSyncing = false
+			return p, nil // Ignore stale message from previous project
+		}
+		return p, p.handleSearchIndexSynced(msg)
+
 	case LoadSettledMsg:
 		// Only settle if token matches (no new sessions arrived) (td-6cc19f)
 		if msg.Token == p.loadSettleToken && !p.initialLoadDone {
@@ -972,10 +1026,8 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 			if p.ctx != nil {
 				epoch = p.ctx.Epoch
 			}
-			return p, RunContentSearch(
+			return p, p.runContentSearch(
 				msg.Query,
-				p.sessions,
-				p.adapters,
 				adapter.SearchOptions{
 					UseRegex:      p.contentSearchState.UseRegex,
 					CaseSensitive: p.contentSearchState.CaseSensitive,
@@ -1035,6 +1087,12 @@ func (p *Plugin) View(width, height int) string {
 		return lipgloss.NewStyle().Width(width).Height(height).MaxHeight(height).Render(content)
 	}
 
+	// Handle export modal overlay
+	if p.showExportModal {
+		content := p.renderExportModal(width, height)
+		return lipgloss.NewStyle().Width(width).Height(height).MaxHeight(height).Render(content)
+	}
+
 	var content string
 	if len(p.adapters) == 0 {
 		content = renderNoAdapter()
@@ -1100,12 +1158,19 @@ func (p *Plugin) Commands() []plugin.Command {
 			{ID: "back", Name: "Back", Description: "Return to sidebar", Category: plugin.CategoryNavigation, Context: "conversations-main", Priority: 4},
 			{ID: "open", Name: "Open", Description: "Open in CLI", Category: plugin.CategoryActions, Context: "conversations-main", Priority: 5},
 			{ID: "yank", Name: "Yank", Description: "Yank turn content", Category: plugin.CategoryActions, Context: "conversations-main", Priority: 6},
+			{ID: "export-session", Name: "Export", Description: "Export session (E)", Category: plugin.CategoryActions, Context: "conversations-main", Priority: 6},
 			{ID: "toggle-sidebar", Name: "Sidebar", Description: "Toggle sidebar visibility", Category: plugin.CategoryView, Context: "conversations-main", Priority: 7},
 		}
 	}
 	if p.view == ViewAnalytics {
 		return []plugin.Command{
 			{ID: "back", Name: "Back", Description: "Return to conversations", Category: plugin.CategoryNavigation, Context: "analytics", Priority: 1},
+			{ID: "cycle-range", Name: "Range", Description: "Cycle time range", Category: plugin.CategoryView, Context: "analytics", Priority: 2},
+			{ID: "cycle-interval", Name: "Interval", Description: "Cycle daily/weekly/monthly", Category: plugin.CategoryView, Context: "analytics", Priority: 2},
+			{ID: "toggle-projects", Name: "Projects", Description: "Toggle this project / all projects", Category: plugin.CategoryView, Context: "analytics", Priority: 3},
+			{ID: "refresh", Name: "Refresh", Description: "Re-collect usage", Category: plugin.CategoryActions, Context: "analytics", Priority: 4},
+			{ID: "export-csv", Name: "CSV", Description: "Export usage as CSV", Category: plugin.CategoryActions, Context: "analytics", Priority: 4},
+			{ID: "export-json", Name: "JSON", Description: "Export usage as JSON", Category: plugin.CategoryActions, Context: "analytics", Priority: 5},
 		}
 	}
 	return []plugin.Command{
@@ -1116,6 +1181,7 @@ func (p *Plugin) Commands() []plugin.Command {
 		{ID: "resume-in-workspace", Name: "Resume", Description: "Resume in workspace", Category: plugin.CategoryActions, Context: "conversations-sidebar", Priority: 3},
 		{ID: "yank-details", Name: "Copy Details", Description: "Copy session details", Category: plugin.CategoryActions, Context: "conversations-sidebar", Priority: 3},
 		{ID: "yank-resume", Name: "Copy Resume", Description: "Copy resume command", Category: plugin.CategoryActions, Context: "conversations-sidebar", Priority: 4},
+		{ID: "export-session", Name: "Export", Description: "Export session", Category: plugin.CategoryActions, Context: "conversations-sidebar", Priority: 4},
 		{ID: "toggle-sidebar", Name: "Sidebar", Description: "Toggle sidebar visibility", Category: plugin.CategoryView, Context: "conversations-sidebar", Priority: 5},
 	}
 }
@@ -1130,6 +1196,9 @@ func (p *Plugin) FocusContext() string {
 	if p.showResumeModal {
 		return "conversations-resume-modal"
 	}
+	if p.showExportModal {
+		return "conversations-export-modal"
+	}
 	if p.searchMode {
 		return "conversations-search"
 	}
@@ -1158,6 +1227,15 @@ func (p *Plugin) ConsumesTextInput() bool {
 	return p.searchMode || p.filterMode || p.contentSearchMode
 }
 
+// CommandVariables exposes the selected session to custom commands.
+func (p *Plugin) CommandVariables() map[string]string {
+	vars := make(map[string]string)
+	if s := p.getSelectedSession(); s != nil {
+		vars["session_id"] = s.ID
+	}
+	return vars
+}
+
 // Diagnostics returns plugin health info.
 func (p *Plugin) Diagnostics() []plugin.Diagnostic {
 	status := "ok"
@@ -1208,21 +1286,6 @@ func (p *Plugin) copySessionToClipboard() tea.Cmd {
 	}
 }
 
-// exportSessionToFile exports the current session to a markdown file.
-func (p *Plugin) exportSessionToFile() tea.Cmd {
-	session := p.findSelectedSession()
-	messages := p.messages
-	workDir := p.ctx.WorkDir
-
-	return func() tea.Msg {
-		filename, err := ExportSessionToFile(session, messages, workDir)
-		if err != nil {
-			return app.ToastMsg{Message: "Export failed: " + err.Error(), Duration: 2 * time.Second, IsError: true}
-		}
-		return app.ToastMsg{Message: "Exported to " + filename, Duration: 2 * time.Second}
-	}
-}
-
 // Message types
 type SessionsLoadedMsg struct {
 	Epoch    uint64 // Epoch when request was issued (for stale detection)
@@ -159,9 +159,8 @@ func (p *Plugin) updateSessions(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 		return p, p.loadSessions()
 
 	case "U":
-		// Toggle global analytics view
-		p.view = ViewAnalytics
-		return p, nil
+		// Open the usage dashboard
+		return p, p.openAnalytics()
 
 	case "y":
 		// Yank session details to clipboard
@@ -174,6 +173,10 @@ func (p *Plugin) updateSessions(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 	case "R":
 		// Open resume modal for workspace
 		return p, p.openResumeModal()
+
+	case "e":
+		// Pick a format and export the session
+		return p, p.openExportModal()
 	}
 
 	return p, nil
@@ -358,6 +361,30 @@ func (p *Plugin) updateAnalytics(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 		if p.analyticsScrollOff < 0 {
 			p.analyticsScrollOff = 0
 		}
+
+	case "t":
+		p.analyticsRangeIdx = (p.analyticsRangeIdx + 1) % len(analyticsRanges)
+		p.analyticsScrollOff = 0
+		p.rebuildAnalyticsReport()
+
+	case "d":
+		p.analyticsIntervalIdx = (p.analyticsIntervalIdx + 1) % len(analyticsIntervals)
+		p.analyticsScrollOff = 0
+		p.rebuildAnalyticsReport()
+
+	case "p":
+		p.analyticsAllProjects = !p.analyticsAllProjects
+		p.analyticsScrollOff = 0
+		p.rebuildAnalyticsReport()
+
+	case "r":
+		return p, p.loadAnalytics()
+
+	case "x":
+		return p, p.exportAnalytics("csv")
+
+	case "X":
+		return p, p.exportAnalytics("json")
 	}
 	return p, nil
 }
@@ -655,9 +682,9 @@ func (p *Plugin) updateMessages(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 		}
 
 	case "E":
-		// Export session to file
+		// Pick a format and export the session
 		if p.selectedSession != "" {
-			return p, p.exportSessionToFile()
+			return p, p.openExportModal()
 		}
 
 	case " ":
@@ -151,7 +151,7 @@ func (p *Plugin) loadMoreSessions() {
 func (p *Plugin) visibleMessageIndices() []int {
 	var indices []int
 	for i, msg := range p.messages {
-		if !p.isToolResultOnlyMessage(msg) {
+		if !isToolResultOnlyMessage(msg) {
 			indices = append(indices, i)
 		}
 	}
@@ -159,7 +159,7 @@ func (p *Plugin) visibleMessageIndices() []int {
 }
 
 // isToolResultOnlyMessage checks if a message contains only tool_result blocks.
-func (p *Plugin) isToolResultOnlyMessage(msg adapter.Message) bool {
+func isToolResultOnlyMessage(msg adapter.Message) bool {
 	if len(msg.ContentBlocks) == 0 {
 		return false
 	}
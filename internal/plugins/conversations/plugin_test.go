@@ -2390,7 +2390,7 @@ func TestRenderToolUseBlockCollapsed(t *testing.T) {
 		ToolOutput: "total 0\ndrwxr-xr-x  2 user  group  64 Jan  1 00:00 .",
 	}
 
-	lines := p.renderToolUseBlock(block, 60)
+	lines := renderToolUseBlock(block, p.expandedToolResults[block.ToolUseID], 60)
 
 	if len(lines) < 1 {
 		t.Fatal("expected at least 1 line")
@@ -2424,7 +2424,7 @@ func TestRenderToolUseBlockExpanded(t *testing.T) {
 		ToolOutput: "file1.txt\nfile2.txt\nfile3.txt",
 	}
 
-	lines := p.renderToolUseBlock(block, 60)
+	lines := renderToolUseBlock(block, p.expandedToolResults[block.ToolUseID], 60)
 
 	// Should have more lines when expanded
 	if len(lines) < 3 {
@@ -2452,7 +2452,7 @@ func TestRenderToolUseBlockError(t *testing.T) {
 		IsError:    true,
 	}
 
-	lines := p.renderToolUseBlock(block, 60)
+	lines := renderToolUseBlock(block, p.expandedToolResults[block.ToolUseID], 60)
 
 	content := strings.Join(lines, "\n")
 	// Errors should show the ✗ error indicator
@@ -2494,7 +2494,7 @@ func TestRenderToolUseBlockDifferentToolTypes(t *testing.T) {
 				ToolInput: tt.toolInput,
 			}
 
-			lines := p.renderToolUseBlock(block, 80)
+			lines := renderToolUseBlock(block, p.expandedToolResults[block.ToolUseID], 80)
 
 			if len(lines) == 0 {
 				t.Fatal("expected at least 1 line")
@@ -2529,7 +2529,7 @@ func TestRenderToolUseBlockLongOutput(t *testing.T) {
 		ToolOutput: sb.String(),
 	}
 
-	result := p.renderToolUseBlock(block, 60)
+	result := renderToolUseBlock(block, p.expandedToolResults[block.ToolUseID], 60)
 
 	// Should be truncated to 20 lines + header + "more lines" indicator
 	// Max is 20 output lines + header + possible indicator
@@ -2558,7 +2558,7 @@ func TestRenderToolUseBlockJSONOutput(t *testing.T) {
 		ToolOutput: `{"key":"value","nested":{"a":1}}`,
 	}
 
-	result := p.renderToolUseBlock(block, 60)
+	result := renderToolUseBlock(block, p.expandedToolResults[block.ToolUseID], 60)
 	content := strings.Join(result, "\n")
 
 	// JSON should be prettified (indented)
@@ -2569,8 +2569,6 @@ func TestRenderToolUseBlockJSONOutput(t *testing.T) {
 
 // TestIsToolResultOnlyMessage tests the helper function.
 func TestIsToolResultOnlyMessage(t *testing.T) {
-	p := New()
-
 	tests := []struct {
 		name     string
 		msg      adapter.Message
@@ -2622,7 +2620,7 @@ func TestIsToolResultOnlyMessage(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := p.isToolResultOnlyMessage(tt.msg)
+			result := isToolResultOnlyMessage(tt.msg)
 			if result != tt.expected {
 				t.Errorf("isToolResultOnlyMessage() = %v, expected %v", result, tt.expected)
 			}
@@ -2842,7 +2840,7 @@ func TestRenderToolUseBlockNoOutput(t *testing.T) {
 		ToolOutput: "", // No output
 	}
 
-	lines := p.renderToolUseBlock(block, 60)
+	lines := renderToolUseBlock(block, p.expandedToolResults[block.ToolUseID], 60)
 
 	if len(lines) != 1 {
 		t.Errorf("expected 1 line (header only) for no output, got %d", len(lines))
@@ -0,0 +1,141 @@
+package conversations
+
+import (
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/adapter"
+	"github.com/marcus/sidecar/internal/searchindex"
+)
+
+// searchIndexBatchSize limits how many sessions one background sync pass
+// indexes, so the initial build over a large history yields between batches.
+const searchIndexBatchSize = 25
+
+// SearchIndexSyncedMsg reports that a background index sync pass finished.
+type SearchIndexSyncedMsg struct {
+	Epoch  uint64               // Epoch when request was issued (for stale detection)
+	More   bool                 // Stale sessions remain after this batch
+	Failed map[string]time.Time // Session key -> UpdatedAt of sessions that failed to load
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m SearchIndexSyncedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// openSearchIndex opens the persistent search index next to the config file.
+// The index is optional: without a config dir (tests) or on error, content
+// search scans sessions directly.
+func (p *Plugin) openSearchIndex() {
+	if p.searchIndex != nil || p.ctx == nil || p.ctx.ConfigDir == "" {
+		return
+	}
+	idx, err := searchindex.Open(filepath.Join(filepath.Dir(p.ctx.ConfigDir), searchindex.FileName))
+	if err != nil {
+		if p.ctx.Logger != nil {
+			p.ctx.Logger.Warn("conversations: search index unavailable", "err", err)
+		}
+		return
+	}
+	p.searchIndex = idx
+}
+
+// closeSearchIndex closes the search index.
+func (p *Plugin) closeSearchIndex() {
+	if p.searchIndex != nil {
+		_ = p.searchIndex.Close()
+		p.searchIndex = nil
+	}
+	p.indexSyncing = false
+	p.indexSyncPending = false
+	p.indexFailed = nil
+}
+
+// syncSearchIndex indexes sessions that changed since they were last
+// indexed, most recently updated first. Watch events reach here through the
+// coalesced session refresh, so only the sessions that changed are re-read.
+// Only one pass runs at a time; requests during a pass are folded into a
+// follow-up pass.
+func (p *Plugin) syncSearchIndex() tea.Cmd {
+	if p.searchIndex == nil {
+		return nil
+	}
+	if p.indexSyncing {
+		p.indexSyncPending = true
+		return nil
+	}
+	p.indexSyncing = true
+
+	idx := p.searchIndex
+	adapters := p.adapters
+	sessions := make([]adapter.Session, 0, len(p.sessions))
+	for _, s := range p.sessions {
+		if s.MessageCount == 0 {
+			continue
+		}
+		// Don't retry sessions that failed to load until they change again
+		if failedAt, ok := p.indexFailed[searchindex.SessionKey(s.AdapterID, s.ID)]; ok && failedAt.Equal(s.UpdatedAt) {
+			continue
+		}
+		sessions = append(sessions, s)
+	}
+	var epoch uint64
+	if p.ctx != nil {
+		epoch = p.ctx.Epoch
+	}
+
+	return func() tea.Msg {
+		stale, err := idx.Stale(sessions)
+		if err != nil {
+			return SearchIndexSyncedMsg{Epoch: epoch}
+		}
+		more := len(stale) > searchIndexBatchSize
+		if more {
+			stale = stale[:searchIndexBatchSize]
+		}
+		failed := make(map[string]time.Time)
+		for _, s := range stale {
+			key := searchindex.SessionKey(s.AdapterID, s.ID)
+			a, ok := adapters[s.AdapterID]
+			if !ok || a == nil {
+				failed[key] = s.UpdatedAt
+				continue
+			}
+			msgs, err := a.Messages(s.ID)
+			if err == nil {
+				err = idx.IndexSession(s, msgs)
+			}
+			if err != nil {
+				failed[key] = s.UpdatedAt
+			}
+		}
+		return SearchIndexSyncedMsg{Epoch: epoch, More: more, Failed: failed}
+	}
+}
+
+// handleSearchIndexSynced schedules the next sync pass if more work remains.
+func (p *Plugin) handleSearchIndexSynced(msg SearchIndexSyncedMsg) tea.Cmd {
+	p.indexSyncing = false
+	if len(msg.Failed) > 0 {
+		if p.indexFailed == nil {
+			p.indexFailed = make(map[string]time.Time)
+		}
+		for key, updatedAt := range msg.Failed {
+			p.indexFailed[key] = updatedAt
+		}
+	}
+	if msg.More || p.indexSyncPending {
+		p.indexSyncPending = false
+		return p.syncSearchIndex()
+	}
+	return nil
+}
+
+// runContentSearch starts a content search, using the persistent index when
+// it can answer the query.
+func (p *Plugin) runContentSearch(query string, opts adapter.SearchOptions, epoch uint64) tea.Cmd {
+	if p.searchIndex != nil && searchindex.Supports(query, opts) {
+		return RunIndexedContentSearch(query, p.sessions, p.adapters, p.searchIndex, opts, epoch)
+	}
+	return RunContentSearch(query, p.sessions, p.adapters, opts, epoch)
+}
@@ -2,10 +2,10 @@ package conversations
 
 import (
 	"sort"
-	"strings"
 	"time"
 
 	"github.com/marcus/sidecar/internal/adapter"
+	"github.com/marcus/sidecar/internal/pricing"
 )
 
 // SessionSummary holds aggregated statistics for a session.
@@ -20,6 +20,7 @@ type SessionSummary struct {
 	PrimaryModel   string         // Most used model
 	MessageCount   int            // Total messages
 	ToolCounts     map[string]int // Tool name -> count
+	UnpricedModels []string       // Models with tokens but no known price (excluded from TotalCost)
 }
 
 // ComputeSessionSummary aggregates statistics from messages.
@@ -66,13 +67,8 @@ func ComputeSessionSummary(messages []adapter.Message, duration time.Duration) S
 		}
 	}
 
-	// Calculate cost
-	summary.TotalCost = estimateTotalCost(
-		summary.PrimaryModel,
-		summary.TotalTokensIn,
-		summary.TotalTokensOut,
-		summary.TotalCacheRead,
-	)
+	// Calculate cost per message so mixed-model sessions are priced correctly
+	addMessageCosts(&summary, messages)
 
 	return summary
 }
@@ -128,40 +124,54 @@ func UpdateSessionSummary(summary *SessionSummary, newMessages []adapter.Message
 
 	summary.FileCount = len(summary.FilesTouched)
 
-	// Recalculate cost
-	summary.TotalCost = estimateTotalCost(
-		summary.PrimaryModel,
-		summary.TotalTokensIn,
-		summary.TotalTokensOut,
-		summary.TotalCacheRead,
-	)
+	// Add cost of the new messages
+	addMessageCosts(summary, newMessages)
 }
 
-// estimateTotalCost calculates cost based on model and tokens.
-func estimateTotalCost(model string, inputTokens, outputTokens, cacheRead int) float64 {
-	var inRate, outRate float64
-	switch {
-	case strings.HasPrefix(model, "gpt-") || strings.HasPrefix(model, "o") || strings.Contains(model, "codex"):
-		return 0
-	case strings.Contains(model, "opus"):
-		inRate, outRate = 15.0, 75.0
-	case strings.Contains(model, "sonnet"):
-		inRate, outRate = 3.0, 15.0
-	case strings.Contains(model, "haiku"):
-		inRate, outRate = 0.25, 1.25
-	default:
-		inRate, outRate = 3.0, 15.0
+// addMessageCosts prices each message with the pricing table and adds the
+// result to summary.TotalCost. Messages without a model fall back to the
+// summary's primary model. Models with tokens but no known price are
+// recorded in summary.UnpricedModels.
+func addMessageCosts(summary *SessionSummary, messages []adapter.Message) {
+	for _, msg := range messages {
+		if msg.InputTokens+msg.OutputTokens+msg.CacheRead+msg.CacheWrite == 0 {
+			continue
+		}
+		model := msg.Model
+		if model == "" {
+			model = summary.PrimaryModel
+		}
+		cost, ok := messageCost(model, msg)
+		if !ok {
+			summary.addUnpricedModel(model)
+			continue
+		}
+		summary.TotalCost += cost
 	}
+}
 
-	regularIn := inputTokens - cacheRead
-	if regularIn < 0 {
-		regularIn = 0
-	}
-	cacheInCost := float64(cacheRead) * inRate * 0.1 / 1_000_000
-	regularInCost := float64(regularIn) * inRate / 1_000_000
-	outCost := float64(outputTokens) * outRate / 1_000_000
+// messageCost prices a single message's token usage.
+func messageCost(model string, msg adapter.Message) (float64, bool) {
+	return pricing.Cost(model, msg.Timestamp, pricing.Usage{
+		Input:      msg.InputTokens,
+		Output:     msg.OutputTokens,
+		CacheRead:  msg.CacheRead,
+		CacheWrite: msg.CacheWrite,
+	})
+}
 
-	return cacheInCost + regularInCost + outCost
+// addUnpricedModel records model as unpriced, keeping the list sorted and unique.
+func (s *SessionSummary) addUnpricedModel(model string) {
+	if model == "" {
+		model = "unknown"
+	}
+	idx := sort.SearchStrings(s.UnpricedModels, model)
+	if idx < len(s.UnpricedModels) && s.UnpricedModels[idx] == model {
+		return
+	}
+	s.UnpricedModels = append(s.UnpricedModels, "")
+	copy(s.UnpricedModels[idx+1:], s.UnpricedModels[idx:])
+	s.UnpricedModels[idx] = model
 }
 
 // SessionGroup represents a group of sessions by time period.
@@ -173,9 +183,10 @@ type SessionGroup struct {
 
 // GroupSummary holds aggregate stats for a session group.
 type GroupSummary struct {
-	SessionCount int
-	TotalTokens  int
-	TotalCost    float64
+	SessionCount     int
+	TotalTokens      int
+	TotalCost        float64
+	UnpricedSessions int // Sessions whose cost excludes models with no known price
 }
 
 // GroupSessionsByTime organizes sessions into time-based groups.
@@ -211,6 +222,9 @@ func groupSessionsByTimeAt(sessions []adapter.Session, now time.Time) []SessionG
 		group.Summary.SessionCount++
 		group.Summary.TotalTokens += s.TotalTokens
 		group.Summary.TotalCost += s.EstCost
+		if len(s.UnpricedModels) > 0 {
+			group.Summary.UnpricedSessions++
+		}
 	}
 
 	// Build result in order, skip empty groups
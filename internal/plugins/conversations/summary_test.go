@@ -1,10 +1,12 @@
 package conversations
 
 import (
+	"math"
 	"testing"
 	"time"
 
 	"github.com/marcus/sidecar/internal/adapter"
+	"github.com/marcus/sidecar/internal/pricing"
 )
 
 func TestComputeSessionSummary_Empty(t *testing.T) {
@@ -114,52 +116,128 @@ func TestComputeSessionSummary_CacheRead(t *testing.T) {
 	}
 }
 
-func TestEstimateTotalCost_Opus(t *testing.T) {
-	// Opus: $15/M in, $75/M out
-	cost := estimateTotalCost("claude-opus-4-5-20251101", 1_000_000, 1_000_000, 0)
-	// Expected: 15 + 75 = 90
-	if cost < 89 || cost > 91 {
-		t.Errorf("expected cost ~90, got %f", cost)
+func tokenMsg(model string, in, out, cacheRead int) adapter.Message {
+	return adapter.Message{
+		Model:     model,
+		Timestamp: time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC),
+		TokenUsage: adapter.TokenUsage{
+			InputTokens:  in,
+			OutputTokens: out,
+			CacheRead:    cacheRead,
+		},
+	}
+}
+
+func TestMessageCost_Opus(t *testing.T) {
+	// Opus 4.5: $5/M in, $25/M out
+	cost, ok := messageCost("claude-opus-4-5-20251101", tokenMsg("", 1_000_000, 1_000_000, 0))
+	// Expected: 5 + 25 = 30
+	if !ok || cost < 29 || cost > 31 {
+		t.Errorf("expected cost ~30, got %f (ok=%v)", cost, ok)
 	}
 }
 
-func TestEstimateTotalCost_Sonnet(t *testing.T) {
+func TestMessageCost_Sonnet(t *testing.T) {
 	// Sonnet: $3/M in, $15/M out
-	cost := estimateTotalCost("claude-sonnet-4-5-20250929", 1_000_000, 1_000_000, 0)
+	cost, ok := messageCost("claude-sonnet-4-5-20250929", tokenMsg("", 1_000_000, 1_000_000, 0))
 	// Expected: 3 + 15 = 18
-	if cost < 17 || cost > 19 {
-		t.Errorf("expected cost ~18, got %f", cost)
+	if !ok || cost < 17 || cost > 19 {
+		t.Errorf("expected cost ~18, got %f (ok=%v)", cost, ok)
+	}
+}
+
+func TestMessageCost_Haiku(t *testing.T) {
+	// Haiku 3.5: $0.80/M in, $4/M out
+	cost, ok := messageCost("claude-3-5-haiku-latest", tokenMsg("", 1_000_000, 1_000_000, 0))
+	// Expected: 0.80 + 4 = 4.8
+	if !ok || cost < 4.7 || cost > 4.9 {
+		t.Errorf("expected cost ~4.8, got %f (ok=%v)", cost, ok)
 	}
 }
 
-func TestEstimateTotalCost_Haiku(t *testing.T) {
-	// Haiku: $0.25/M in, $1.25/M out
-	cost := estimateTotalCost("claude-3-5-haiku-latest", 1_000_000, 1_000_000, 0)
-	// Expected: 0.25 + 1.25 = 1.5
-	if cost < 1.4 || cost > 1.6 {
-		t.Errorf("expected cost ~1.5, got %f", cost)
+func TestMessageCost_WithCache(t *testing.T) {
+	// Opus 4.5 with 80% cache hit
+	// 200k regular input, 800k from cache ($0.50/M)
+	cost, ok := messageCost("claude-opus-4-5-20251101", tokenMsg("", 200_000, 0, 800_000))
+	// Cache: 800k * 0.50 / 1M = 0.4
+	// Regular: 200k * 5 / 1M = 1
+	// Total: 1.4
+	if !ok || cost < 1.35 || cost > 1.45 {
+		t.Errorf("expected cost ~1.4, got %f (ok=%v)", cost, ok)
 	}
 }
 
-func TestEstimateTotalCost_WithCache(t *testing.T) {
-	// Opus with 80% cache hit
-	// 1M input, 800k from cache (10% rate), 200k regular
-	cost := estimateTotalCost("claude-opus-4-5-20251101", 1_000_000, 0, 800_000)
-	// Cache: 800k * 15 * 0.1 / 1M = 1.2
-	// Regular: 200k * 15 / 1M = 3
-	// Total: 4.2
-	if cost < 4 || cost > 4.5 {
-		t.Errorf("expected cost ~4.2, got %f", cost)
+func TestComputeSessionSummary_ClaudeCodeUsage(t *testing.T) {
+	// Claude Code reports input_tokens excluding cache reads and writes; the
+	// summary must price them like the adapter's session estimate does.
+	ts := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	usage := adapter.TokenUsage{InputTokens: 3, OutputTokens: 400, CacheRead: 50_000, CacheWrite: 2_000}
+	messages := []adapter.Message{
+		{Model: "claude-sonnet-4-5-20250929", Timestamp: ts, TokenUsage: usage},
+		{Model: "claude-sonnet-4-5-20250929", Timestamp: ts, TokenUsage: usage},
+	}
+	summary := ComputeSessionSummary(messages, time.Minute)
+
+	want, ok := pricing.Cost("claude-sonnet-4-5-20250929", ts, pricing.Usage{
+		Input: 6, Output: 800, CacheRead: 100_000, CacheWrite: 4_000,
+	})
+	if !ok {
+		t.Fatal("sonnet should be priced")
+	}
+	if math.Abs(summary.TotalCost-want) > 1e-9 {
+		t.Errorf("TotalCost = %f, want %f", summary.TotalCost, want)
+	}
+	if summary.TotalTokensIn != 6 {
+		t.Errorf("TotalTokensIn = %d, want 6", summary.TotalTokensIn)
 	}
 }
 
-func TestEstimateTotalCost_ZeroTokens(t *testing.T) {
-	cost := estimateTotalCost("claude-opus-4-5-20251101", 0, 0, 0)
+func TestMessageCost_NonClaude(t *testing.T) {
+	// GPT-5: $1.25/M in, $10/M out
+	cost, ok := messageCost("gpt-5-codex", tokenMsg("", 1_000_000, 1_000_000, 0))
+	if !ok || cost < 11.2 || cost > 11.3 {
+		t.Errorf("expected cost ~11.25, got %f (ok=%v)", cost, ok)
+	}
+}
+
+func TestMessageCost_ZeroTokens(t *testing.T) {
+	cost, _ := messageCost("claude-opus-4-5-20251101", tokenMsg("", 0, 0, 0))
 	if cost != 0 {
 		t.Errorf("expected cost 0, got %f", cost)
 	}
 }
 
+func TestComputeSessionSummary_MixedModels(t *testing.T) {
+	messages := []adapter.Message{
+		tokenMsg("claude-opus-4-5-20251101", 1_000_000, 0, 0),
+		tokenMsg("claude-opus-4-5-20251101", 1_000_000, 0, 0),
+		tokenMsg("claude-3-5-haiku-latest", 1_000_000, 0, 0),
+	}
+	summary := ComputeSessionSummary(messages, 0)
+	// Each message is priced with its own model: 5 + 5 + 0.80
+	if summary.TotalCost < 10.7 || summary.TotalCost > 10.9 {
+		t.Errorf("expected cost ~10.8, got %f", summary.TotalCost)
+	}
+	if len(summary.UnpricedModels) != 0 {
+		t.Errorf("expected no unpriced models, got %v", summary.UnpricedModels)
+	}
+}
+
+func TestComputeSessionSummary_UnpricedModel(t *testing.T) {
+	messages := []adapter.Message{
+		tokenMsg("claude-sonnet-4-5-20250929", 1_000_000, 0, 0),
+		tokenMsg("mystery-model", 1_000_000, 0, 0),
+		tokenMsg("mystery-model", 1_000_000, 0, 0),
+	}
+	summary := ComputeSessionSummary(messages, 0)
+	if summary.TotalCost < 2.9 || summary.TotalCost > 3.1 {
+		t.Errorf("expected only sonnet cost ~3, got %f", summary.TotalCost)
+	}
+	if len(summary.UnpricedModels) != 1 || summary.UnpricedModels[0] != "mystery-model" {
+		t.Errorf("expected UnpricedModels=[mystery-model], got %v", summary.UnpricedModels)
+	}
+}
+
 func TestGroupSessionsByTime_Empty(t *testing.T) {
 	groups := groupSessionsByTimeAt(nil, testNow())
 	if len(groups) != 0 {
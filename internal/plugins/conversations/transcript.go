@@ -0,0 +1,113 @@
+package conversations
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/marcus/sidecar/internal/adapter"
+	"github.com/marcus/sidecar/internal/styles"
+)
+
+// TranscriptRenderer renders session messages as turns for display outside
+// the conversations plugin, such as the workspace preview pane. It uses the
+// plugin's text, thinking and tool renderers with every block collapsed.
+type TranscriptRenderer struct {
+	markdown *GlamourRenderer // nil falls back to plain wrapped text
+}
+
+// NewTranscriptRenderer creates a renderer with its own markdown renderer.
+// If the markdown renderer cannot be created, the failure is logged to logger
+// and content is rendered as plain text.
+func NewTranscriptRenderer(logger *slog.Logger) *TranscriptRenderer {
+	renderer, err := NewGlamourRenderer()
+	if err != nil && logger != nil {
+		logger.Warn("conversations: markdown renderer unavailable for transcripts", "err", err)
+	}
+	return &TranscriptRenderer{markdown: renderer}
+}
+
+// Render groups messages into turns and renders each turn with a header, its
+// content blocks and tool calls, and the files its tools touched. agentName
+// labels assistant turns.
+func (r *TranscriptRenderer) Render(messages []adapter.Message, agentName string, width int) []string {
+	if width < 10 {
+		width = 10
+	}
+	var lines []string
+	for i, turn := range GroupMessagesIntoTurns(messages) {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, renderTurnHeader(turn, agentName))
+
+		for _, msg := range turn.Messages {
+			if isToolResultOnlyMessage(msg) {
+				continue
+			}
+			for _, line := range r.renderMessage(msg, width-4) {
+				lines = append(lines, "    "+line)
+			}
+		}
+
+		if files := ComputeSessionSummary(turn.Messages, 0).FilesTouched; len(files) > 0 {
+			lines = append(lines, styles.Muted.Render("   └─ files: "+strings.Join(files, ", ")))
+		}
+	}
+	return lines
+}
+
+// renderMessage renders a message's content blocks, or its plain content when
+// it has none, collapsed as in the conversations plugin.
+func (r *TranscriptRenderer) renderMessage(msg adapter.Message, width int) []string {
+	if len(msg.ContentBlocks) == 0 {
+		if msg.Content == "" {
+			return nil
+		}
+		return renderTextContent(r.markdown, msg.Content, false, width)
+	}
+	var lines []string
+	for _, block := range msg.ContentBlocks {
+		switch block.Type {
+		case "text":
+			if block.Text != "" {
+				lines = append(lines, renderTextContent(r.markdown, block.Text, false, width)...)
+			}
+		case "thinking":
+			lines = append(lines, renderThinkingLines(block, false, width)...)
+		case "tool_use":
+			lines = append(lines, renderToolUseBlock(block, false, width)...)
+		}
+	}
+	return lines
+}
+
+// turnStats returns the " (N msgs, in:X out:Y)" suffix for a turn header.
+func turnStats(turn Turn) string {
+	var stats []string
+	if len(turn.Messages) > 1 {
+		stats = append(stats, fmt.Sprintf("%d msgs", len(turn.Messages)))
+	}
+	if turn.TotalTokensIn > 0 || turn.TotalTokensOut > 0 {
+		stats = append(stats, fmt.Sprintf("in:%s out:%s", formatK(turn.TotalTokensIn), formatK(turn.TotalTokensOut)))
+	}
+	if len(stats) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(stats, ", ") + ")"
+}
+
+// renderTurnHeader renders the styled "[15:04] role (stats)" line of an
+// unselected turn.
+func renderTurnHeader(turn Turn, agentName string) string {
+	roleName := agentName
+	roleStyle := styles.StatusStaged
+	if turn.Role == "user" {
+		roleName = "you"
+		roleStyle = styles.StatusInProgress
+	}
+	return fmt.Sprintf("[%s] %s%s",
+		styles.Muted.Render(turn.FirstTimestamp()),
+		roleStyle.Render(roleName),
+		styles.Muted.Render(turnStats(turn)))
+}
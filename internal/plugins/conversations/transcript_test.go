@@ -0,0 +1,42 @@
+package conversations
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/marcus/sidecar/internal/adapter"
+)
+
+func TestTranscriptRenderer_Render(t *testing.T) {
+	now := time.Now()
+	messages := []adapter.Message{
+		{ID: "m1", Role: "user", Content: "fix the login bug", Timestamp: now},
+		{
+			ID: "m2", Role: "assistant", Timestamp: now,
+			TokenUsage: adapter.TokenUsage{InputTokens: 1200, OutputTokens: 300},
+			ContentBlocks: []adapter.ContentBlock{
+				{Type: "text", Text: "Looking at the handler."},
+				{Type: "tool_use", ToolUseID: "t1", ToolName: "Edit", ToolInput: `{"file_path":"auth/login.go"}`, ToolOutput: "ok"},
+			},
+			ToolUses: []adapter.ToolUse{{ID: "t1", Name: "Edit", Input: `{"file_path":"auth/login.go"}`, Output: "ok"}},
+		},
+		{
+			ID: "m3", Role: "user", Timestamp: now,
+			ContentBlocks: []adapter.ContentBlock{{Type: "tool_result", ToolUseID: "t1", ToolOutput: "ok"}},
+		},
+	}
+
+	lines := NewTranscriptRenderer(nil).Render(messages, "claude", 80)
+	out := ansi.Strip(strings.Join(lines, "\n"))
+	for _, want := range []string{"you", "fix the login bug", "claude (in:1.2k out:300)", "◈ Edit: auth/login.go", "files: auth/login.go"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("transcript missing %q:\n%s", want, out)
+		}
+	}
+	// The tool-result-only message forms its own turn but renders no body.
+	if strings.Count(out, "you") != 2 {
+		t.Errorf("expected two user turn headers:\n%s", out)
+	}
+}
@@ -156,6 +156,9 @@ func renderAdapterIcon(session adapter.Session) string {
 	case "amp":
 		// Sourcegraph orange
 		return lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5543")).Render(icon)
+	case "aider":
+		// Aider green
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("#14B014")).Render(icon)
 	default:
 		return styles.Muted.Render(icon)
 	}
@@ -173,6 +176,8 @@ func adapterAbbrev(session adapter.Session) string {
 		return "GC"
 	case "amp":
 		return "AM"
+	case "aider":
+		return "AI"
 	default:
 		name := session.AdapterName
 		if name == "" {
@@ -209,6 +214,8 @@ func adapterShortName(session *adapter.Session) string {
 		return "warp"
 	case "amp":
 		return "amp"
+	case "aider":
+		return "aider"
 	default:
 		if session.AdapterName != "" {
 			return strings.ToLower(session.AdapterName)
@@ -315,6 +322,9 @@ func resumeCommand(session *adapter.Session) string {
 		return fmt.Sprintf("cursor-agent --resume %s", session.ID)
 	case "amp":
 		return fmt.Sprintf("amp --resume %s", session.ID)
+	case "aider":
+		// Aider resumes per project rather than per session
+		return "aider --restore-chat-history"
 	default:
 		return ""
 	}
@@ -706,7 +716,7 @@ func (p *Plugin) renderContentBlocks(msg adapter.Message, maxWidth int) []string
 			lines = append(lines, thinkingLines...)
 
 		case "tool_use":
-			toolLines := p.renderToolUseBlock(block, maxWidth)
+			toolLines := renderToolUseBlock(block, p.expandedToolResults[block.ToolUseID], maxWidth)
 			lines = append(lines, toolLines...)
 
 		case "tool_result":
@@ -726,10 +736,6 @@ func (p *Plugin) renderMessageContent(content string, msgID string, maxWidth int
 		return nil
 	}
 
-	// Check if content is "short" (can display inline)
-	lineCount := strings.Count(content, "\n") + 1
-	isShort := len(content) <= ShortMessageCharLimit && lineCount <= ShortMessageLineLimit
-
 	expanded := p.expandedMessages[msgID]
 
 	// Check cache (td-8910b218)
@@ -737,10 +743,24 @@ func (p *Plugin) renderMessageContent(content string, msgID string, maxWidth int
 		return strings.Split(cached, "\n")
 	}
 
+	result := renderTextContent(p.contentRenderer, content, expanded, maxWidth)
+
+	// Store in cache (td-8910b218)
+	p.setCachedRender(msgID, maxWidth, expanded, strings.Join(result, "\n"))
+	return result
+}
+
+// renderTextContent renders text as markdown when it is short or expanded,
+// and as a one-line preview otherwise.
+func renderTextContent(r *GlamourRenderer, content string, expanded bool, maxWidth int) []string {
+	// Check if content is "short" (can display inline)
+	lineCount := strings.Count(content, "\n") + 1
+	isShort := len(content) <= ShortMessageCharLimit && lineCount <= ShortMessageLineLimit
+
 	var result []string
 	if isShort || expanded {
 		// Show full content
-		result = p.renderContent(content, maxWidth)
+		result = renderMarkdown(r, content, maxWidth)
 	} else {
 		// Collapsed: show preview with toggle hint (rune-safe for Unicode)
 		preview := content
@@ -755,9 +775,6 @@ func (p *Plugin) renderMessageContent(content string, msgID string, maxWidth int
 		}
 		result = wrapText(preview, maxWidth)
 	}
-
-	// Store in cache (td-8910b218)
-	p.setCachedRender(msgID, maxWidth, expanded, strings.Join(result, "\n"))
 	return result
 }
 
@@ -773,6 +790,16 @@ func (p *Plugin) renderThinkingBlock(block adapter.ContentBlock, msgID string, m
 		return strings.Split(cached, "\n")
 	}
 
+	lines := renderThinkingLines(block, expanded, maxWidth)
+
+	// Store in cache (td-8910b218)
+	p.setCachedRender(thinkingCacheID, maxWidth, expanded, strings.Join(lines, "\n"))
+	return lines
+}
+
+// renderThinkingLines renders a thinking block's header, followed by its
+// content when expanded or a one-line preview when collapsed.
+func renderThinkingLines(block adapter.ContentBlock, expanded bool, maxWidth int) []string {
 	var lines []string
 
 	// Light purple style for thinking blocks
@@ -814,14 +841,11 @@ func (p *Plugin) renderThinkingBlock(block adapter.ContentBlock, msgID string, m
 			lines = append(lines, thinkingStyle.Render(header))
 		}
 	}
-
-	// Store in cache (td-8910b218)
-	p.setCachedRender(thinkingCacheID, maxWidth, expanded, strings.Join(lines, "\n"))
 	return lines
 }
 
 // renderToolUseBlock renders a tool use block with its result (expand/collapse).
-func (p *Plugin) renderToolUseBlock(block adapter.ContentBlock, maxWidth int) []string {
+func renderToolUseBlock(block adapter.ContentBlock, expanded bool, maxWidth int) []string {
 	var lines []string
 
 	// Tool-specific icons for visual distinction
@@ -863,8 +887,6 @@ func (p *Plugin) renderToolUseBlock(block adapter.ContentBlock, maxWidth int) []
 		toolHeader = ui.TruncateString(toolHeader, maxWidth-2)
 	}
 
-	expanded := p.expandedToolResults[block.ToolUseID]
-
 	// Style based on error state
 	if block.IsError {
 		// Red styling for errors with x indicator
@@ -717,7 +717,14 @@ func (p *Plugin) renderMainPane(paneWidth, height int) string {
 
 		// Cost estimate
 		if session != nil && session.EstCost > 0 {
-			statsParts = append(statsParts, formatCost(session.EstCost))
+			cost := formatCost(session.EstCost)
+			if len(session.UnpricedModels) > 0 || len(s.UnpricedModels) > 0 {
+				// Some tokens were not priced; the estimate is a lower bound
+				cost += "+?"
+			}
+			statsParts = append(statsParts, cost)
+		} else if session != nil && (len(session.UnpricedModels) > 0 || len(s.UnpricedModels) > 0) {
+			statsParts = append(statsParts, "no price")
 		}
 
 		// Last updated
@@ -1013,47 +1020,24 @@ func (p *Plugin) renderCompactTurn(turn Turn, turnIndex int, maxWidth int) []str
 	// Header line: [timestamp] role (N msgs) tokens
 	ts := turn.FirstTimestamp()
 
-	// Build stats string
-	msgCount := len(turn.Messages)
-	var stats []string
-	if msgCount > 1 {
-		stats = append(stats, fmt.Sprintf("%d msgs", msgCount))
-	}
-	if turn.TotalTokensIn > 0 || turn.TotalTokensOut > 0 {
-		stats = append(stats, fmt.Sprintf("in:%s out:%s", formatK(turn.TotalTokensIn), formatK(turn.TotalTokensOut)))
-	}
-	statsStr := ""
-	if len(stats) > 0 {
-		statsStr = " (" + strings.Join(stats, ", ") + ")"
-	}
+	statsStr := turnStats(turn)
 
 	// Get friendly role name
 	session := p.findSelectedSession()
-	var roleName string
-	if turn.Role == "user" {
-		roleName = "you"
-	} else {
-		roleName = adapterShortName(session)
-	}
+	agentName := adapterShortName(session)
 
 	// Build header line
 	if selected {
 		// For selected: plain text with background highlight
+		roleName := agentName
+		if turn.Role == "user" {
+			roleName = "you"
+		}
 		headerContent := fmt.Sprintf("[%s] %s%s", ts, roleName, statsStr)
 		lines = append(lines, p.styleTurnLine(headerContent, true, maxWidth))
 	} else {
 		// For unselected: colored role badge with muted styling
-		var roleStyle lipgloss.Style
-		if turn.Role == "user" {
-			roleStyle = styles.StatusInProgress
-		} else {
-			roleStyle = styles.StatusStaged
-		}
-		styledHeader := fmt.Sprintf("[%s] %s%s",
-			styles.Muted.Render(ts),
-			roleStyle.Render(roleName),
-			styles.Muted.Render(statsStr))
-		lines = append(lines, styledHeader)
+		lines = append(lines, renderTurnHeader(turn, agentName))
 	}
 
 	// Thinking indicator (aggregate) - indented under header
@@ -1110,7 +1094,7 @@ func (p *Plugin) renderConversationFlow(contentWidth, height int) []string {
 
 	for msgIdx, msg := range p.messages {
 		// Skip user messages that are just tool results (they'll be shown inline with tool_use)
-		if p.isToolResultOnlyMessage(msg) {
+		if isToolResultOnlyMessage(msg) {
 			continue
 		}
 
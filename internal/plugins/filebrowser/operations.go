@@ -1090,9 +1090,10 @@ func (p *Plugin) expandParents(node *FileNode) {
 	}
 }
 
-// navigateToFile navigates the file browser to a specific file path.
+// navigateToFile navigates the file browser to a specific file path and,
+// when line is positive, scrolls the preview to that line.
 // Used when other plugins request navigation (e.g., git plugin opening file in browser).
-func (p *Plugin) navigateToFile(path string) (plugin.Plugin, tea.Cmd) {
+func (p *Plugin) navigateToFile(path string, line int) (plugin.Plugin, tea.Cmd) {
 	// Find the file node in tree
 	var targetNode *FileNode
 	p.walkTree(p.tree.Root, func(node *FileNode) {
@@ -1102,8 +1103,12 @@ func (p *Plugin) navigateToFile(path string) (plugin.Plugin, tea.Cmd) {
 	})
 
 	if targetNode == nil {
-		// File not found in tree, maybe it's new or ignored
-		return p, nil
+		// File not in tree yet (new or ignored): preview it if it exists
+		if info, err := os.Stat(filepath.Join(p.ctx.WorkDir, path)); err != nil || info.IsDir() {
+			return p, nil
+		}
+		p.activePane = PanePreview
+		return p, p.openTabAtLine(path, line, TabOpenNew)
 	}
 
 	// Expand parents to make the file visible
@@ -1118,46 +1123,53 @@ func (p *Plugin) navigateToFile(path string) (plugin.Plugin, tea.Cmd) {
 
 	// Load preview
 	p.activePane = PanePreview
-	return p, p.openTab(path, TabOpenNew)
+	return p, p.openTabAtLine(path, line, TabOpenNew)
 }
 
 // copySelectedTextToClipboard copies the selected text to the system clipboard
 // with character-level precision using the shared ui.SelectionState.
 func (p *Plugin) copySelectedTextToClipboard() tea.Cmd {
 	return func() tea.Msg {
-		if !p.selection.HasSelection() {
-			return nil
-		}
-		startLine := p.selection.Start.Line
-		endLine := p.selection.End.Line
-		if startLine > endLine {
-			startLine, endLine = endLine, startLine
-		}
-		if startLine < 0 {
-			startLine = 0
-		}
-		if endLine >= len(p.previewLines) {
-			endLine = len(p.previewLines) - 1
-		}
-		if endLine < startLine {
-			return nil
-		}
-
-		lines := p.previewLines[startLine : endLine+1]
-		result := p.selection.SelectedText(lines, startLine, 8)
-		if len(result) == 0 {
+		text, lineCount := p.selectedText()
+		if text == "" {
 			return nil
 		}
-
-		text := strings.Join(result, "\n")
 		if err := clipboard.WriteAll(text); err != nil {
 			return msg.ToastMsg{Message: "Copy failed: " + err.Error(), Duration: 2 * time.Second, IsError: true}
 		}
-		lineCount := endLine - startLine + 1
 		return msg.ToastMsg{Message: fmt.Sprintf("Copied %d line(s)", lineCount), Duration: 2 * time.Second}
 	}
 }
 
+// selectedText returns the text selected in the preview and the number of
+// lines it spans, or "" when nothing is selected.
+func (p *Plugin) selectedText() (string, int) {
+	if !p.selection.HasSelection() {
+		return "", 0
+	}
+	startLine := p.selection.Start.Line
+	endLine := p.selection.End.Line
+	if startLine > endLine {
+		startLine, endLine = endLine, startLine
+	}
+	if startLine < 0 {
+		startLine = 0
+	}
+	if endLine >= len(p.previewLines) {
+		endLine = len(p.previewLines) - 1
+	}
+	if endLine < startLine {
+		return "", 0
+	}
+
+	lines := p.previewLines[startLine : endLine+1]
+	result := p.selection.SelectedText(lines, startLine, 8)
+	if len(result) == 0 {
+		return "", 0
+	}
+	return strings.Join(result, "\n"), endLine - startLine + 1
+}
+
 // copyFileContentsToClipboard copies the entire file contents to the system clipboard.
 func (p *Plugin) copyFileContentsToClipboard() tea.Cmd {
 	return func() tea.Msg {
@@ -3,6 +3,7 @@ package filebrowser
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
@@ -14,6 +15,7 @@ import (
 	"github.com/marcus/sidecar/internal/modal"
 	"github.com/marcus/sidecar/internal/mouse"
 	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/remote"
 	"github.com/marcus/sidecar/internal/state"
 	"github.com/marcus/sidecar/internal/tty"
 	"github.com/marcus/sidecar/internal/ui"
@@ -60,6 +62,7 @@ type (
 	// NavigateToFileMsg requests navigation to a specific file (from other plugins).
 	NavigateToFileMsg struct {
 		Path string // Relative path from workdir
+		Line int    // 1-based line to scroll to; 0 keeps the saved position
 	}
 	// RevealErrorMsg is sent when reveal in file manager fails.
 	RevealErrorMsg struct {
@@ -545,7 +548,7 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		if p.pendingOpenFile != "" {
 			path := p.pendingOpenFile
 			p.pendingOpenFile = "" // Clear immediately to avoid re-processing
-			_, navCmd := p.navigateToFile(path)
+			_, navCmd := p.navigateToFile(path, 0)
 			// Restore state after first tree build
 			if !p.stateRestored {
 				p.stateRestored = true
@@ -659,7 +662,7 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		return p, tea.Batch(cmds...)
 
 	case NavigateToFileMsg:
-		return p.navigateToFile(msg.Path)
+		return p.navigateToFile(msg.Path, msg.Line)
 
 	case RevealErrorMsg:
 		p.ctx.Logger.Error("file browser: reveal failed", "error", msg.Err)
@@ -908,3 +911,66 @@ func (p *Plugin) ConsumesTextInput() bool {
 		p.lineJumpMode ||
 		p.inlineEditMode
 }
+
+// RemoteMethods lists the remote control methods the file browser serves.
+func (p *Plugin) RemoteMethods() []string {
+	return []string{"file.open"}
+}
+
+// HandleRemote serves file.open: focus the file browser and preview a file,
+// given relative to the working directory or as an absolute path inside it,
+// optionally scrolled to a 1-based line.
+func (p *Plugin) HandleRemote(req *remote.Request) tea.Cmd {
+	var params struct {
+		Path string `json:"path"`
+		Line int    `json:"line"`
+	}
+	if err := req.Decode(&params); err != nil {
+		req.Reply(nil, err)
+		return nil
+	}
+	if params.Path == "" {
+		req.Reply(nil, remote.InvalidParams("path is required"))
+		return nil
+	}
+	path := params.Path
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(p.ctx.WorkDir, path)
+	}
+	rel, err := filepath.Rel(p.ctx.WorkDir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		req.Reply(nil, remote.InvalidParams("%s is outside %s", params.Path, p.ctx.WorkDir))
+		return nil
+	}
+	if info, err := os.Stat(path); err != nil {
+		req.Reply(nil, err)
+		return nil
+	} else if info.IsDir() {
+		req.Reply(nil, remote.InvalidParams("%s is a directory", params.Path))
+		return nil
+	}
+
+	req.Reply(map[string]any{"path": rel}, nil)
+	line := params.Line
+	return tea.Batch(
+		app.FocusPlugin(pluginID),
+		func() tea.Msg { return NavigateToFileMsg{Path: rel, Line: line} },
+	)
+}
+
+// CommandVariables exposes the file under the cursor (the previewed file in
+// the preview pane) and the preview's text selection to custom commands.
+func (p *Plugin) CommandVariables() map[string]string {
+	vars := make(map[string]string)
+	if p.activePane == PanePreview && p.previewFile != "" {
+		vars["file"] = p.previewFile
+	} else if p.tree != nil {
+		if node := p.tree.GetNode(p.treeCursor); node != nil {
+			vars["file"] = node.Path
+		}
+	}
+	if text, _ := p.selectedText(); text != "" {
+		vars["selection"] = text
+	}
+	return vars
+}
@@ -0,0 +1,42 @@
+package filebrowser
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/marcus/sidecar/internal/remote"
+)
+
+func TestHandleRemote_FileOpen(t *testing.T) {
+	tmpDir := t.TempDir()
+	p := createTabTestPlugin(t, tmpDir)
+
+	call := func(params string) (any, error) {
+		req := remote.NewRequest("file.open", json.RawMessage(params))
+		p.HandleRemote(req)
+		return req.Wait(time.Second)
+	}
+
+	abs, _ := json.Marshal(map[string]any{"path": filepath.Join(tmpDir, "src", "helper.go"), "line": 3})
+	result, err := call(string(abs))
+	if err != nil {
+		t.Fatalf("file.open failed: %v", err)
+	}
+	if got := result.(map[string]any)["path"]; got != filepath.Join("src", "helper.go") {
+		t.Errorf("path = %v, want relative path", got)
+	}
+
+	for _, params := range []string{`{}`, `{"path": "../outside.go"}`, `{"path": "missing.go"}`, `{"path": "src"}`} {
+		if _, err := call(params); err == nil {
+			t.Errorf("file.open %s should fail", params)
+		}
+	}
+
+	// Navigation scrolls the preview to the requested line
+	p.navigateToFile("src/helper.go", 3)
+	if p.previewFile != "src/helper.go" || p.previewScroll != 2 {
+		t.Errorf("preview = %s at %d, want src/helper.go at 2", p.previewFile, p.previewScroll)
+	}
+}
@@ -94,20 +94,37 @@ func CreateBranch(workDir, branchName string) error {
 	return nil
 }
 
-// DeleteBranch deletes a branch.
+// DeleteBranch deletes a branch, recording its tip in the operation journal
+// so the deletion can be undone.
 func DeleteBranch(workDir, branchName string) error {
-	cmd := exec.Command("git", "branch", "-d", branchName)
+	return deleteBranch(workDir, branchName, "-d")
+}
+
+// ForceDeleteBranch force-deletes a branch, recording its tip in the
+// operation journal.
+func ForceDeleteBranch(workDir, branchName string) error {
+	return deleteBranch(workDir, branchName, "-D")
+}
+
+func deleteBranch(workDir, branchName, flag string) error {
+	tip, _ := revParse(workDir, "refs/heads/"+branchName)
+	cmd := exec.Command("git", "branch", flag, branchName)
 	cmd.Dir = workDir
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return &BranchError{Output: string(output), Err: err}
 	}
+	if tip != "" {
+		// Best effort: the branch is gone either way
+		_ = RecordJournal(workDir, JournalEntry{Op: JournalDeleteBranch, Branch: branchName, Hash: tip})
+	}
 	return nil
 }
 
-// ForceDeleteBranch force-deletes a branch.
-func ForceDeleteBranch(workDir, branchName string) error {
-	cmd := exec.Command("git", "branch", "-D", branchName)
+// MoveBranch points a branch that is not checked out at hash. git refuses
+// to move a branch checked out in any worktree.
+func MoveBranch(workDir, branchName, hash string) error {
+	cmd := exec.Command("git", "branch", "--force", branchName, hash)
 	cmd.Dir = workDir
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -0,0 +1,876 @@
+package gitstatus
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/marcus/sidecar/internal/app"
+	"github.com/marcus/sidecar/internal/modal"
+	appmsg "github.com/marcus/sidecar/internal/msg"
+	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/styles"
+	"github.com/marcus/sidecar/internal/ui"
+)
+
+const (
+	cherryPickTargetPrefix = "cherry-pick-target-"
+	cherryPickActionID     = "cherry-pick-action"
+	cherryPickCancelID     = "cherry-pick-cancel"
+
+	revertConfirmID = "revert-confirm"
+	revertCancelID  = "revert-cancel"
+
+	resetModePrefix = "reset-mode-"
+	resetActionID   = "reset-action"
+	resetCancelID   = "reset-cancel"
+
+	errorResolveID = "resolve"
+	errorAbortID   = "abort"
+
+	commitActionMaxListed = 6 // Commits or files listed before "... and N more"
+)
+
+// resetModes are the reset modes offered, in list order.
+var resetModes = []ResetMode{ResetSoft, ResetMixed, ResetHard}
+
+// CherryPickTarget is a worktree that commits can be cherry-picked onto.
+type CherryPickTarget struct {
+	Path    string
+	Branch  string // "" when HEAD is detached
+	Current bool   // The worktree this plugin shows
+}
+
+// label describes the target in the picker.
+func (t CherryPickTarget) label() string {
+	branch := t.Branch
+	if branch == "" {
+		branch = "detached HEAD"
+	}
+	if t.Current {
+		return "Current branch (" + branch + ")"
+	}
+	return branch + "  " + filepath.Base(t.Path)
+}
+
+// CherryPickTargetsMsg is sent when the worktrees to pick onto are listed.
+type CherryPickTargetsMsg struct {
+	Epoch   uint64
+	Targets []CherryPickTarget
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m CherryPickTargetsMsg) GetEpoch() uint64 { return m.Epoch }
+
+// ResetPreviewMsg is sent when the changes a reset would discard are loaded.
+type ResetPreviewMsg struct {
+	Epoch   uint64
+	Preview *ResetPreview
+	Err     error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m ResetPreviewMsg) GetEpoch() uint64 { return m.Epoch }
+
+// CommitActionDoneMsg is sent when a cherry-pick, revert, reset, abort or
+// branch move finishes or stops.
+type CommitActionDoneMsg struct {
+	Op      string // "cherry-pick", "revert", "reset", "abort" or "move-branch"
+	Commits int
+	Branch  string           // Branch moved, for move-branch
+	Hash    string           // Short hash the branch moved to, for move-branch
+	Target  CherryPickTarget // Worktree picked onto, for cherry-pick
+	Mode    ResetMode
+	Aborted bool       // A conflicting pick in another worktree was rolled back
+	Stopped ConflictOp // Operation left stopped on conflicts, for the error modal
+	Err     error
+}
+
+// toggleCommitMark marks the selected commit as one end of a range, or clears
+// the mark when it is already there.
+func (p *Plugin) toggleCommitMark() tea.Cmd {
+	commit := p.getCurrentCommit()
+	if commit == nil {
+		return nil
+	}
+	if p.commitMark == commit.Hash {
+		p.commitMark = ""
+		return appmsg.ShowToast("Range mark cleared", 2*time.Second)
+	}
+	p.commitMark = commit.Hash
+	return appmsg.ShowToast("Marked "+commit.ShortHash+": select the other end and press C", 2*time.Second)
+}
+
+// commitMarkIndex returns the index of the marked commit in the listed
+// commits, or -1.
+func (p *Plugin) commitMarkIndex() int {
+	if p.commitMark == "" {
+		return -1
+	}
+	for i, c := range p.activeCommits() {
+		if c.Hash == p.commitMark {
+			return i
+		}
+	}
+	return -1
+}
+
+// commitInRange reports whether the listed commit idx lies between the mark
+// and the selected commit, or is the mark while no commit is selected.
+func (p *Plugin) commitInRange(idx int) bool {
+	mark := p.commitMarkIndex()
+	if mark < 0 {
+		return false
+	}
+	if !p.cursorOnCommit() {
+		return idx == mark
+	}
+	sel := p.selectedCommitIndex()
+	return idx >= min(mark, sel) && idx <= max(mark, sel)
+}
+
+// selectedCommits returns the marked range when a mark is set, otherwise the
+// selected commit, oldest first.
+func (p *Plugin) selectedCommits() []*Commit {
+	commits := p.activeCommits()
+	sel := p.selectedCommitIndex()
+	if !p.cursorOnCommit() || sel < 0 || sel >= len(commits) {
+		return nil
+	}
+	lo, hi := sel, sel
+	if mark := p.commitMarkIndex(); mark >= 0 {
+		lo, hi = min(mark, sel), max(mark, sel)
+	}
+	picked := make([]*Commit, 0, hi-lo+1)
+	for i := hi; i >= lo; i-- {
+		picked = append(picked, commits[i])
+	}
+	return picked
+}
+
+// commitActionBlocked returns why commits can't be rewritten right now, or
+// "" when they can.
+func (p *Plugin) commitActionBlocked() string {
+	switch {
+	case p.commitActionRunning:
+		return "A git operation is already running"
+	case p.rebaseState != nil || p.rebaseRunning:
+		return "A rebase is in progress (R for actions)"
+	case p.hasConflicts():
+		return "Resolve the conflicts first (M)"
+	}
+	return ""
+}
+
+// openCherryPick lists the worktrees the selected commits can be picked onto.
+func (p *Plugin) openCherryPick() tea.Cmd {
+	commits := p.selectedCommits()
+	if len(commits) == 0 {
+		return nil
+	}
+	if p.commitActionRunning {
+		return appmsg.ShowToast("A git operation is already running", 2*time.Second)
+	}
+	if len(commits) > 1 {
+		for _, c := range commits {
+			if c.IsMerge {
+				return appmsg.ShowToast("Can't cherry-pick a range with merge commits", 2*time.Second)
+			}
+		}
+	}
+
+	p.cherryPickCommits = commits
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		return CherryPickTargetsMsg{Epoch: epoch, Targets: loadCherryPickTargets(workDir)}
+	}
+}
+
+// loadCherryPickTargets lists the repository's worktrees, the one at workDir
+// first.
+func loadCherryPickTargets(workDir string) []CherryPickTarget {
+	root := workDir
+	if resolved, err := filepath.EvalSymlinks(workDir); err == nil {
+		root = resolved
+	}
+	var targets []CherryPickTarget
+	hasCurrent := false
+	for _, wt := range app.GetWorktrees(workDir) {
+		path := wt.Path
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			path = resolved
+		}
+		current := path == root
+		hasCurrent = hasCurrent || current
+		targets = append(targets, CherryPickTarget{Path: wt.Path, Branch: wt.Branch, Current: current})
+	}
+	if !hasCurrent {
+		targets = append(targets, CherryPickTarget{Path: workDir, Current: true})
+	}
+	sort.SliceStable(targets, func(i, j int) bool { return targets[i].Current && !targets[j].Current })
+	return targets
+}
+
+// handleCherryPickTargets opens the target picker.
+func (p *Plugin) handleCherryPickTargets(msg CherryPickTargetsMsg) {
+	if len(p.cherryPickCommits) == 0 || p.viewMode != ViewModeStatus {
+		return
+	}
+	p.cherryPickTargets = msg.Targets
+	p.cherryPickTargetIdx = 0
+	p.viewMode = ViewModeCherryPick
+	p.clearCherryPickModal()
+}
+
+func (p *Plugin) closeCherryPick() {
+	p.viewMode = ViewModeStatus
+	p.cherryPickCommits = nil
+	p.cherryPickTargets = nil
+	p.clearCherryPickModal()
+}
+
+func (p *Plugin) clearCherryPickModal() {
+	p.cherryPickModal = nil
+	p.cherryPickWidth = 0
+}
+
+// updateCherryPick handles key events in the cherry-pick target picker.
+func (p *Plugin) updateCherryPick(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	p.ensureCherryPickModal()
+	if p.cherryPickModal == nil {
+		return p, nil
+	}
+	if msg.String() == "q" {
+		p.closeCherryPick()
+		return p, nil
+	}
+	action, cmd := p.cherryPickModal.HandleKey(msg)
+	return p, p.cherryPickAction(action, cmd)
+}
+
+// handleCherryPickMouse processes mouse events in the target picker.
+func (p *Plugin) handleCherryPickMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	p.ensureCherryPickModal()
+	if p.cherryPickModal == nil {
+		return p, nil
+	}
+	action := p.cherryPickModal.HandleMouse(msg, p.mouseHandler)
+	return p, p.cherryPickAction(action, nil)
+}
+
+func (p *Plugin) cherryPickAction(action string, cmd tea.Cmd) tea.Cmd {
+	switch {
+	case action == "cancel" || action == cherryPickCancelID:
+		p.closeCherryPick()
+		return nil
+	case action == cherryPickActionID:
+		return p.startCherryPick(p.cherryPickTargetIdx)
+	case strings.HasPrefix(action, cherryPickTargetPrefix):
+		idx, err := strconv.Atoi(strings.TrimPrefix(action, cherryPickTargetPrefix))
+		if err != nil {
+			return nil
+		}
+		return p.startCherryPick(idx)
+	}
+	return cmd
+}
+
+// startCherryPick picks the commits onto target idx. A pick that stops on
+// conflicts in another worktree is aborted there, so an agent working in it
+// never finds it half-merged.
+func (p *Plugin) startCherryPick(idx int) tea.Cmd {
+	if idx < 0 || idx >= len(p.cherryPickTargets) {
+		return nil
+	}
+	target := p.cherryPickTargets[idx]
+	if target.Current {
+		if reason := p.commitActionBlocked(); reason != "" {
+			return appmsg.ShowToast(reason, 2*time.Second)
+		}
+	}
+	commits := p.cherryPickCommits
+	hashes := make([]string, len(commits))
+	for i, c := range commits {
+		hashes[i] = c.Hash
+	}
+	merge := len(commits) == 1 && commits[0].IsMerge
+
+	p.closeCherryPick()
+	p.commitMark = ""
+	p.commitActionRunning = true
+	return func() tea.Msg {
+		done := CommitActionDoneMsg{Op: "cherry-pick", Commits: len(hashes), Target: target}
+		done.Err = CherryPick(target.Path, hashes, merge)
+		if done.Err != nil && GetConflictOp(target.Path) == ConflictOpCherryPick {
+			if target.Current {
+				done.Stopped = ConflictOpCherryPick
+			} else {
+				done.Aborted = AbortConflictOp(target.Path, ConflictOpCherryPick) == nil
+			}
+		}
+		return done
+	}
+}
+
+// ensureCherryPickModal builds/rebuilds the cherry-pick target picker.
+func (p *Plugin) ensureCherryPickModal() {
+	modalW := ui.ModalWidthLarge
+	if modalW > p.width-4 {
+		modalW = p.width - 4
+	}
+	if modalW < pullMenuMinWidth {
+		modalW = pullMenuMinWidth
+	}
+	if p.cherryPickModal != nil && p.cherryPickWidth == modalW {
+		return
+	}
+	p.cherryPickWidth = modalW
+
+	items := make([]modal.ListItem, len(p.cherryPickTargets))
+	for i, t := range p.cherryPickTargets {
+		items[i] = modal.ListItem{ID: cherryPickTargetPrefix + strconv.Itoa(i), Label: t.label()}
+	}
+	title := "Cherry-pick"
+	if n := len(p.cherryPickCommits); n > 1 {
+		title = fmt.Sprintf("Cherry-pick %d Commits", n)
+	}
+	p.cherryPickModal = modal.New(title,
+		modal.WithWidth(modalW),
+		modal.WithHints(false),
+		modal.WithPrimaryAction(cherryPickActionID),
+	).
+		AddSection(p.commitListSection(p.cherryPickCommits)).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Text(styles.Muted.Render("Onto:"))).
+		AddSection(modal.List("cherry-pick-targets", items, &p.cherryPickTargetIdx, modal.WithMaxVisible(6))).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Buttons(
+			modal.Btn(" Pick ", cherryPickActionID, modal.BtnPrimary()),
+			modal.Btn(" Cancel ", cherryPickCancelID),
+		))
+}
+
+// commitListSection lists commits by short hash and subject.
+func (p *Plugin) commitListSection(commits []*Commit) modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		var lines []string
+		for i, c := range commits {
+			if i >= commitActionMaxListed {
+				lines = append(lines, styles.Muted.Render(fmt.Sprintf("  ... and %d more", len(commits)-i)))
+				break
+			}
+			subject := truncateStr(c.Subject, max(contentWidth-10, 10))
+			lines = append(lines, styles.Code.Render(c.ShortHash)+" "+styles.Body.Render(subject))
+		}
+		return modal.RenderedSection{Content: strings.Join(lines, "\n")}
+	}, nil)
+}
+
+// renderCherryPick renders the cherry-pick target picker.
+func (p *Plugin) renderCherryPick() string {
+	background := p.renderThreePaneView()
+
+	p.ensureCherryPickModal()
+	if p.cherryPickModal == nil {
+		return background
+	}
+	modalContent := p.cherryPickModal.Render(p.width, p.height, p.mouseHandler)
+	return ui.OverlayModal(background, modalContent, p.width, p.height)
+}
+
+// openRevert asks to confirm reverting the selected commit.
+func (p *Plugin) openRevert() tea.Cmd {
+	commit := p.getCurrentCommit()
+	if commit == nil {
+		return nil
+	}
+	if reason := p.commitActionBlocked(); reason != "" {
+		return appmsg.ShowToast(reason, 2*time.Second)
+	}
+	p.revertCommit = commit
+	p.revertMessage = RevertMessage(commit)
+	p.viewMode = ViewModeRevert
+	p.clearRevertModal()
+	return nil
+}
+
+func (p *Plugin) closeRevert() {
+	p.viewMode = ViewModeStatus
+	p.revertCommit = nil
+	p.revertMessage = ""
+	p.clearRevertModal()
+}
+
+func (p *Plugin) clearRevertModal() {
+	p.revertModal = nil
+	p.revertWidth = 0
+}
+
+// updateRevert handles key events in the revert confirmation.
+func (p *Plugin) updateRevert(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	p.ensureRevertModal()
+	if p.revertModal == nil {
+		return p, nil
+	}
+	switch msg.String() {
+	case "y":
+		return p, p.startRevert()
+	case "n", "q":
+		p.closeRevert()
+		return p, nil
+	}
+	action, cmd := p.revertModal.HandleKey(msg)
+	return p, p.revertAction(action, cmd)
+}
+
+// handleRevertMouse processes mouse events in the revert confirmation.
+func (p *Plugin) handleRevertMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	p.ensureRevertModal()
+	if p.revertModal == nil {
+		return p, nil
+	}
+	action := p.revertModal.HandleMouse(msg, p.mouseHandler)
+	return p, p.revertAction(action, nil)
+}
+
+func (p *Plugin) revertAction(action string, cmd tea.Cmd) tea.Cmd {
+	switch action {
+	case revertConfirmID:
+		return p.startRevert()
+	case "cancel", revertCancelID:
+		p.closeRevert()
+		return nil
+	}
+	return cmd
+}
+
+// startRevert commits the revert of the confirmed commit.
+func (p *Plugin) startRevert() tea.Cmd {
+	commit := p.revertCommit
+	if commit == nil {
+		return nil
+	}
+	message := p.revertMessage
+	workDir := p.repoRoot
+	p.closeRevert()
+	p.commitActionRunning = true
+	return func() tea.Msg {
+		done := CommitActionDoneMsg{Op: "revert", Commits: 1}
+		done.Err = RevertCommit(workDir, commit.Hash, message, commit.IsMerge)
+		if done.Err != nil && GetConflictOp(workDir) == ConflictOpRevert {
+			done.Stopped = ConflictOpRevert
+		}
+		return done
+	}
+}
+
+// ensureRevertModal builds/rebuilds the revert confirmation.
+func (p *Plugin) ensureRevertModal() {
+	if p.revertCommit == nil {
+		return
+	}
+	modalW := ui.ModalWidthLarge
+	if modalW > p.width-4 {
+		modalW = p.width - 4
+	}
+	if modalW < pullMenuMinWidth {
+		modalW = pullMenuMinWidth
+	}
+	if p.revertModal != nil && p.revertWidth == modalW {
+		return
+	}
+	p.revertWidth = modalW
+
+	c := p.revertCommit
+	m := modal.New("Revert Commit",
+		modal.WithWidth(modalW),
+		modal.WithHints(false),
+		modal.WithPrimaryAction(revertConfirmID),
+	).
+		AddSection(p.commitListSection([]*Commit{c})).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Text(styles.Muted.Render("Commits the inverse of its changes with the message:"))).
+		AddSection(modal.Text(styles.Body.Render(p.revertMessage)))
+	if c.IsMerge {
+		m.AddSection(modal.Spacer())
+		m.AddSection(modal.Text(lipgloss.NewStyle().Foreground(styles.Warning).Render(
+			"Reverting a merge undoes the changes it brought in from its other branch.")))
+	}
+	m.AddSection(modal.Spacer())
+	m.AddSection(modal.Buttons(
+		modal.Btn(" Revert ", revertConfirmID, modal.BtnPrimary()),
+		modal.Btn(" Cancel ", revertCancelID),
+	))
+	m.AddSection(modal.Text(styles.Muted.Render("y: revert   Esc: cancel")))
+	p.revertModal = m
+}
+
+// renderRevert renders the revert confirmation.
+func (p *Plugin) renderRevert() string {
+	background := p.renderThreePaneView()
+
+	p.ensureRevertModal()
+	if p.revertModal == nil {
+		return background
+	}
+	modalContent := p.revertModal.Render(p.width, p.height, p.mouseHandler)
+	return ui.OverlayModal(background, modalContent, p.width, p.height)
+}
+
+// openReset loads what resetting to the selected commit would discard.
+func (p *Plugin) openReset() tea.Cmd {
+	commit := p.getCurrentCommit()
+	if commit == nil {
+		return nil
+	}
+	return p.openResetTo(commit, ViewModeStatus)
+}
+
+// openResetTo loads what resetting HEAD to commit would discard. The reset
+// modal returns to returnMode when it closes.
+func (p *Plugin) openResetTo(commit *Commit, returnMode ViewMode) tea.Cmd {
+	if reason := p.commitActionBlocked(); reason != "" {
+		return appmsg.ShowToast(reason, 2*time.Second)
+	}
+	p.resetCommit = commit
+	p.resetReturnMode = returnMode
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		preview, err := LoadResetPreview(workDir, commit.Hash)
+		return ResetPreviewMsg{Epoch: epoch, Preview: preview, Err: err}
+	}
+}
+
+// handleResetPreview opens the reset modal on a loaded preview.
+func (p *Plugin) handleResetPreview(msg ResetPreviewMsg) {
+	if p.resetCommit == nil || p.viewMode != p.resetReturnMode {
+		return
+	}
+	if msg.Err != nil {
+		p.resetCommit = nil
+		p.showErrorModal("Reset", msg.Err)
+		return
+	}
+	p.resetPreview = msg.Preview
+	p.resetModeIdx = int(ResetMixed)
+	p.viewMode = ViewModeReset
+	p.clearResetModal()
+}
+
+func (p *Plugin) closeReset() {
+	p.viewMode = p.resetReturnMode
+	p.resetCommit = nil
+	p.resetPreview = nil
+	p.clearResetModal()
+}
+
+func (p *Plugin) clearResetModal() {
+	p.resetModal = nil
+	p.resetWidth = 0
+}
+
+// updateReset handles key events in the reset modal. s, m and h select a
+// mode; Enter resets with the selected one.
+func (p *Plugin) updateReset(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	p.ensureResetModal()
+	if p.resetModal == nil {
+		return p, nil
+	}
+	switch msg.String() {
+	case "s":
+		p.resetModeIdx = int(ResetSoft)
+		return p, nil
+	case "m":
+		p.resetModeIdx = int(ResetMixed)
+		return p, nil
+	case "h":
+		p.resetModeIdx = int(ResetHard)
+		return p, nil
+	case "q":
+		p.closeReset()
+		return p, nil
+	}
+	action, cmd := p.resetModal.HandleKey(msg)
+	if strings.HasPrefix(action, resetModePrefix) {
+		action = resetActionID
+	}
+	return p, p.resetAction(action, cmd)
+}
+
+// handleResetMouse processes mouse events in the reset modal. Clicking a
+// mode only selects it; the Reset button runs it.
+func (p *Plugin) handleResetMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	p.ensureResetModal()
+	if p.resetModal == nil {
+		return p, nil
+	}
+	action := p.resetModal.HandleMouse(msg, p.mouseHandler)
+	if strings.HasPrefix(action, resetModePrefix) {
+		for i, mode := range resetModes {
+			if action == resetModePrefix+mode.String() {
+				p.resetModeIdx = i
+			}
+		}
+		return p, nil
+	}
+	return p, p.resetAction(action, nil)
+}
+
+func (p *Plugin) resetAction(action string, cmd tea.Cmd) tea.Cmd {
+	switch action {
+	case resetActionID:
+		return p.startReset()
+	case "cancel", resetCancelID:
+		p.closeReset()
+		return nil
+	}
+	return cmd
+}
+
+// selectedResetMode returns the mode highlighted in the reset modal.
+func (p *Plugin) selectedResetMode() ResetMode {
+	if p.resetModeIdx < 0 || p.resetModeIdx >= len(resetModes) {
+		return ResetMixed
+	}
+	return resetModes[p.resetModeIdx]
+}
+
+// startReset resets HEAD to the commit with the selected mode.
+func (p *Plugin) startReset() tea.Cmd {
+	if p.resetCommit == nil {
+		return nil
+	}
+	hash := p.resetCommit.Hash
+	mode := p.selectedResetMode()
+	commits := 0
+	if p.resetPreview != nil {
+		commits = len(p.resetPreview.Commits)
+	}
+	workDir := p.repoRoot
+	p.closeReset()
+	p.commitActionRunning = true
+	return func() tea.Msg {
+		return CommitActionDoneMsg{Op: "reset", Commits: commits, Mode: mode, Err: ResetTo(workDir, hash, mode)}
+	}
+}
+
+// ensureResetModal builds/rebuilds the reset modal.
+func (p *Plugin) ensureResetModal() {
+	if p.resetCommit == nil || p.resetPreview == nil {
+		return
+	}
+	modalW := ui.ModalWidthLarge
+	if modalW > p.width-4 {
+		modalW = p.width - 4
+	}
+	if modalW < pullMenuMinWidth {
+		modalW = pullMenuMinWidth
+	}
+	if p.resetModal != nil && p.resetWidth == modalW {
+		return
+	}
+	p.resetWidth = modalW
+
+	items := []modal.ListItem{
+		{ID: resetModePrefix + ResetSoft.String(), Label: "Soft: keep all changes staged"},
+		{ID: resetModePrefix + ResetMixed.String(), Label: "Mixed: keep all changes, unstaged"},
+		{ID: resetModePrefix + ResetHard.String(), Label: "Hard: discard all changes"},
+	}
+	p.resetModal = modal.New("Reset to "+p.resetCommit.ShortHash,
+		modal.WithWidth(modalW),
+		modal.WithVariant(modal.VariantDanger),
+		modal.WithHints(false),
+		modal.WithPrimaryAction(resetActionID),
+	).
+		AddSection(modal.List("reset-modes", items, &p.resetModeIdx, modal.WithMaxVisible(len(items)))).
+		AddSection(modal.Spacer()).
+		AddSection(p.resetLossSection()).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Buttons(
+			modal.Btn(" Reset ", resetActionID, modal.BtnDanger()),
+			modal.Btn(" Cancel ", resetCancelID),
+		)).
+		AddSection(modal.Text(styles.Muted.Render("s/m/h: select mode   Enter: reset   Esc: cancel")))
+}
+
+// resetLossSection describes what the selected mode moves or discards.
+func (p *Plugin) resetLossSection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		preview := p.resetPreview
+		if preview == nil {
+			return modal.RenderedSection{}
+		}
+		return modal.RenderedSection{Content: p.resetLossText(preview, p.selectedResetMode(), contentWidth)}
+	}, nil)
+}
+
+// resetLossText lists the commits leaving the branch and the uncommitted
+// changes the mode discards or unstages.
+func (p *Plugin) resetLossText(preview *ResetPreview, mode ResetMode, width int) string {
+	warn := lipgloss.NewStyle().Foreground(styles.Warning)
+	var sb strings.Builder
+	listItems := func(items []string, style lipgloss.Style) {
+		for i, item := range items {
+			if i >= commitActionMaxListed {
+				sb.WriteString(styles.Muted.Render(fmt.Sprintf("  ... and %d more", len(items)-i)))
+				sb.WriteString("\n")
+				return
+			}
+			sb.WriteString(style.Render(truncateStr("  "+item, width)))
+			sb.WriteString("\n")
+		}
+	}
+
+	branch := preview.Branch
+	if branch == "" {
+		branch = "HEAD"
+	}
+	if n := len(preview.Commits); n > 0 {
+		pushed := make(map[string]bool, len(p.recentCommits))
+		for _, c := range p.recentCommits {
+			pushed[c.Hash] = c.Pushed
+		}
+		pushedCount := 0
+		items := make([]string, n)
+		for i, c := range preview.Commits {
+			mark := "  "
+			if pushed[c.Hash] {
+				mark = "↑ "
+				pushedCount++
+			}
+			items[i] = mark + c.ShortHash + " " + c.Subject
+		}
+		sb.WriteString(warn.Render(fmt.Sprintf("%d commit(s) leave %s:", n, branch)))
+		sb.WriteString("\n")
+		listItems(items, styles.Body)
+		switch mode {
+		case ResetSoft:
+			sb.WriteString(styles.Muted.Render("Their changes stay staged."))
+		case ResetMixed:
+			sb.WriteString(styles.Muted.Render("Their changes stay in the working tree, unstaged."))
+		case ResetHard:
+			sb.WriteString(styles.StatusDeleted.Render("Their changes are discarded."))
+		}
+		sb.WriteString("\n")
+		if pushedCount > 0 {
+			sb.WriteString(warn.Render(fmt.Sprintf("%d of them are pushed (↑); pushing again needs --force.", pushedCount)))
+			sb.WriteString("\n")
+		}
+	}
+
+	switch {
+	case mode == ResetHard && len(preview.Changed) > 0:
+		sb.WriteString(styles.StatusDeleted.Render(fmt.Sprintf("Uncommitted changes in %d file(s) are discarded:", len(preview.Changed))))
+		sb.WriteString("\n")
+		listItems(preview.Changed, styles.StatusDeleted)
+		sb.WriteString(styles.Muted.Render("Untracked files are kept."))
+	case mode == ResetMixed && len(preview.Staged) > 0:
+		sb.WriteString(styles.Muted.Render(fmt.Sprintf("%d staged file(s) are unstaged; no changes are lost.", len(preview.Staged))))
+	case len(preview.Commits) == 0:
+		sb.WriteString(styles.Muted.Render("Nothing is lost."))
+	default:
+		sb.WriteString(styles.Muted.Render("Uncommitted changes are kept."))
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// renderReset renders the reset modal.
+func (p *Plugin) renderReset() string {
+	var background string
+	if p.resetReturnMode == ViewModeReflog {
+		background = p.renderReflogView()
+	} else {
+		background = p.renderThreePaneView()
+	}
+
+	p.ensureResetModal()
+	if p.resetModal == nil {
+		return background
+	}
+	modalContent := p.resetModal.Render(p.width, p.height, p.mouseHandler)
+	return ui.OverlayModal(background, modalContent, p.width, p.height)
+}
+
+// handleCommitActionDone reports a finished cherry-pick, revert, reset or
+// abort. Failures go to the error modal, which offers to resolve or abort an
+// operation left stopped on conflicts.
+func (p *Plugin) handleCommitActionDone(msg CommitActionDoneMsg) tea.Cmd {
+	p.commitActionRunning = false
+	cmds := []tea.Cmd{p.refresh(), p.loadRecentCommits()}
+	if p.viewMode == ViewModeReflog {
+		cmds = append(cmds, p.loadReflog())
+	}
+
+	if msg.Err != nil {
+		title := commitActionTitle(msg.Op) + " Failed"
+		err := msg.Err
+		switch {
+		case msg.Stopped != ConflictOpNone:
+			title = commitActionTitle(msg.Op) + " Stopped on Conflicts"
+		case msg.Aborted:
+			err = fmt.Errorf("%s\n\nThe cherry-pick was aborted; %s is unchanged.",
+				strings.TrimSpace(err.Error()), msg.Target.label())
+		}
+		p.showErrorModal(title, err)
+		p.errorOfferResolve = msg.Stopped != ConflictOpNone
+		return tea.Batch(cmds...)
+	}
+
+	var text string
+	switch msg.Op {
+	case "cherry-pick":
+		text = fmt.Sprintf("Cherry-picked %d commit(s)", msg.Commits)
+		if !msg.Target.Current {
+			text += " onto " + msg.Target.label()
+		}
+	case "revert":
+		text = "Reverted commit"
+	case "reset":
+		text = fmt.Sprintf("Reset (%s), %d commit(s) removed", msg.Mode, msg.Commits)
+	case "abort":
+		text = "Aborted"
+	case "move-branch":
+		text = fmt.Sprintf("Moved %s to %s", msg.Branch, msg.Hash)
+	}
+	return tea.Batch(append(cmds, appmsg.ShowToast(text, 2*time.Second))...)
+}
+
+// commitActionTitle returns the display name of a commit action.
+func commitActionTitle(op string) string {
+	switch op {
+	case "cherry-pick":
+		return "Cherry-pick"
+	case "revert":
+		return "Revert"
+	case "reset":
+		return "Reset"
+	case "move-branch":
+		return "Move Branch"
+	default:
+		return "Abort"
+	}
+}
+
+// resolveFromErrorModal closes the error modal and opens the conflict view.
+func (p *Plugin) resolveFromErrorModal() (plugin.Plugin, tea.Cmd) {
+	p.dismissErrorModal()
+	return p, p.openConflicts("")
+}
+
+// abortFromErrorModal closes the error modal and aborts the operation that
+// stopped on conflicts.
+func (p *Plugin) abortFromErrorModal() (plugin.Plugin, tea.Cmd) {
+	p.dismissErrorModal()
+	p.commitActionRunning = true
+	workDir := p.repoRoot
+	return p, func() tea.Msg {
+		return CommitActionDoneMsg{Op: "abort", Err: AbortConflictOp(workDir, GetConflictOp(workDir))}
+	}
+}
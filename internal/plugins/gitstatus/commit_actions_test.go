@@ -0,0 +1,191 @@
+package gitstatus
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/mouse"
+	"github.com/marcus/sidecar/internal/plugin"
+)
+
+func TestCherryPick_RangeOntoWorktree(t *testing.T) {
+	dir := initRebaseRepo(t, []string{"a", "b", "c"}, nil)
+	wt := filepath.Join(t.TempDir(), "wt")
+	if out, err := exec.Command("git", "-C", dir, "worktree", "add", "-q", "-b", "other", wt, "HEAD~2").CombinedOutput(); err != nil {
+		t.Fatalf("worktree add: %v\n%s", err, out)
+	}
+
+	targets := loadCherryPickTargets(dir)
+	if len(targets) != 2 || !targets[0].Current || targets[1].Branch != "other" {
+		t.Fatalf("targets = %+v, want the current worktree, then other", targets)
+	}
+
+	hashes := []string{commitHash(t, dir, "HEAD~1"), commitHash(t, dir, "HEAD")}
+	if err := CherryPick(wt, hashes, false); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(logSubjects(t, wt), " "); got != "c b a" {
+		t.Errorf("worktree log = %q, want %q", got, "c b a")
+	}
+}
+
+func TestCherryPick_ConflictStops(t *testing.T) {
+	dir := initRebaseRepo(t, []string{"a", "b", "c"}, map[string][2]string{
+		"b": {"shared.txt", "one\n"},
+		"c": {"shared.txt", "two\n"},
+	})
+	c := commitHash(t, dir, "HEAD")
+	if out, err := exec.Command("git", "-C", dir, "checkout", "-q", "-b", "other", "HEAD~2").CombinedOutput(); err != nil {
+		t.Fatalf("checkout: %v\n%s", err, out)
+	}
+
+	// c changes shared.txt, which b created
+	err := CherryPick(dir, []string{c}, false)
+	if !IsConflictError(err) {
+		t.Fatalf("err = %v, want a conflict error", err)
+	}
+	if op := GetConflictOp(dir); op != ConflictOpCherryPick {
+		t.Fatalf("op = %v, want cherry-pick", op)
+	}
+	if err := AbortConflictOp(dir, ConflictOpCherryPick); err != nil {
+		t.Fatal(err)
+	}
+	if op := GetConflictOp(dir); op != ConflictOpNone {
+		t.Errorf("op after abort = %v, want none", op)
+	}
+}
+
+func TestRevertCommit_UsesMessage(t *testing.T) {
+	dir := initRebaseRepo(t, []string{"a", "b"}, nil)
+	c := &Commit{Hash: commitHash(t, dir, "HEAD"), Subject: "b"}
+
+	message := RevertMessage(c)
+	if want := "Revert \"b\"\n\nThis reverts commit " + c.Hash + "."; message != want {
+		t.Errorf("message = %q, want %q", message, want)
+	}
+	if err := RevertCommit(dir, c.Hash, message, false); err != nil {
+		t.Fatal(err)
+	}
+	body, _ := exec.Command("git", "-C", dir, "log", "-1", "--format=%B").Output()
+	if strings.TrimSpace(string(body)) != message {
+		t.Errorf("commit message = %q, want %q", body, message)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "b.txt")); err == nil {
+		t.Error("reverted commit's file still exists")
+	}
+}
+
+func TestResetPreviewAndReset(t *testing.T) {
+	dir := initRebaseRepo(t, []string{"a", "b", "c"}, nil)
+	target := commitHash(t, dir, "HEAD~2")
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	preview, err := LoadResetPreview(dir, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(preview.Commits) != 2 || preview.Commits[0].Subject != "c" || preview.Commits[1].Subject != "b" {
+		t.Errorf("commits = %+v, want c, b", preview.Commits)
+	}
+	if len(preview.Changed) != 1 || preview.Changed[0] != "a.txt" || len(preview.Staged) != 0 {
+		t.Errorf("changed %v, staged %v, want a.txt unstaged", preview.Changed, preview.Staged)
+	}
+
+	p := &Plugin{}
+	if text := p.resetLossText(preview, ResetHard, 60); !strings.Contains(text, "1 file(s) are discarded") {
+		t.Errorf("hard reset text does not list the discarded file:\n%s", text)
+	}
+	if text := p.resetLossText(preview, ResetSoft, 60); strings.Contains(text, "discarded") {
+		t.Errorf("soft reset text claims changes are discarded:\n%s", text)
+	}
+
+	if err := ResetTo(dir, target, ResetHard); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(logSubjects(t, dir), " "); got != "a" {
+		t.Errorf("log = %q, want %q", got, "a")
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "a.txt")); string(data) != "a\n" {
+		t.Errorf("a.txt = %q, want its committed content", data)
+	}
+}
+
+func TestCommitActionKeys(t *testing.T) {
+	commits := []*Commit{
+		{Hash: "h3", ShortHash: "h3", Subject: "three"},
+		{Hash: "h2", ShortHash: "h2", Subject: "two"},
+		{Hash: "h1", ShortHash: "h1", Subject: "one", IsMerge: true},
+	}
+	p := &Plugin{
+		ctx:           &plugin.Context{},
+		hasRepo:       true,
+		tree:          &FileTree{},
+		viewMode:      ViewModeStatus,
+		width:         100,
+		height:        40,
+		recentCommits: commits,
+		mouseHandler:  mouse.NewHandler(),
+	}
+	press := func(key string) tea.Cmd {
+		_, cmd := p.updateStatus(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		return cmd
+	}
+
+	// Mark "three", move to "two": the range is picked oldest first
+	press("m")
+	press("j")
+	got := p.selectedCommits()
+	if len(got) != 2 || got[0].Hash != "h2" || got[1].Hash != "h3" {
+		t.Fatalf("range = %v, want h2, h3", got)
+	}
+	if !p.commitInRange(0) || p.commitInRange(2) {
+		t.Error("range highlight is wrong")
+	}
+
+	// A range with a merge commit is refused
+	press("j")
+	press("C")
+	if p.cherryPickCommits != nil {
+		t.Error("a range with a merge commit should not open the picker")
+	}
+
+	p.commitMark = ""
+	if cmd := press("C"); cmd == nil || len(p.cherryPickCommits) != 1 {
+		t.Fatal("C should load the targets for the selected commit")
+	}
+	p.handleCherryPickTargets(CherryPickTargetsMsg{Targets: []CherryPickTarget{{Path: "/repo", Branch: "main", Current: true}}})
+	if p.viewMode != ViewModeCherryPick || p.FocusContext() != "git-cherry-pick" {
+		t.Fatalf("picker not open: mode %v", p.viewMode)
+	}
+	p.updateCherryPick(tea.KeyMsg{Type: tea.KeyEsc})
+	if p.viewMode != ViewModeStatus || p.cherryPickCommits != nil {
+		t.Error("esc should close the picker")
+	}
+
+	// Revert asks for confirmation with the generated message
+	press("t")
+	if p.viewMode != ViewModeRevert || !strings.HasPrefix(p.revertMessage, "Revert \"one\"") {
+		t.Fatalf("revert not confirming: mode %v, message %q", p.viewMode, p.revertMessage)
+	}
+	p.updateRevert(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if p.viewMode != ViewModeStatus {
+		t.Error("n should cancel the revert")
+	}
+
+	// A cherry-pick stopped on conflicts offers to resolve them
+	p.handleCommitActionDone(CommitActionDoneMsg{Op: "cherry-pick", Stopped: ConflictOpCherryPick, Err: &RemoteError{Output: "CONFLICT", Err: errors.New("exit 1")}})
+	if p.viewMode != ViewModeError || !p.errorOfferResolve || p.commitActionRunning {
+		t.Fatal("conflicts should open the error modal with resolve")
+	}
+	p.dismissErrorModal()
+	if p.errorOfferResolve {
+		t.Error("dismissing should clear the resolve offer")
+	}
+}
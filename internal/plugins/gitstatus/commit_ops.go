@@ -0,0 +1,155 @@
+package gitstatus
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// CherryPick applies commits, oldest first, onto the branch checked out in
+// workDir. A single merge commit is picked relative to its first parent.
+// Errors carry git's output as a RemoteError so IsConflictError recognises
+// a pick that stopped on conflicts.
+func CherryPick(workDir string, hashes []string, merge bool) error {
+	args := []string{"cherry-pick"}
+	if merge {
+		args = append(args, "-m", "1")
+	}
+	cmd := exec.Command("git", append(args, hashes...)...)
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(), "GIT_EDITOR=true")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return &RemoteError{Output: string(output), Err: err}
+	}
+	return nil
+}
+
+// RevertMessage returns the message of the commit that reverts c, in the
+// form git itself uses.
+func RevertMessage(c *Commit) string {
+	msg := fmt.Sprintf("Revert \"%s\"\n\nThis reverts commit %s", c.Subject, c.Hash)
+	if c.IsMerge && len(c.ParentHashes) > 0 {
+		return msg + fmt.Sprintf(", reversing\nchanges made to %s.", c.ParentHashes[0])
+	}
+	return msg + "."
+}
+
+// RevertCommit commits the inverse of the commit hash with message. A merge
+// commit is reverted relative to its first parent. The message is written
+// through GIT_EDITOR, so no editor opens.
+func RevertCommit(workDir, hash, message string, merge bool) error {
+	f, err := os.CreateTemp("", "sidecar-revert-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(message + "\n"); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	args := []string{"revert", "--edit"}
+	if merge {
+		args = append(args, "-m", "1")
+	}
+	cmd := exec.Command("git", append(args, hash)...)
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(), "GIT_EDITOR=cp "+shellQuote(f.Name()))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return &RemoteError{Output: string(output), Err: err}
+	}
+	return nil
+}
+
+// ResetMode selects what git reset does with the index and working tree.
+type ResetMode int
+
+const (
+	ResetSoft  ResetMode = iota // Keep the index and working tree
+	ResetMixed                  // Reset the index, keep the working tree
+	ResetHard                   // Reset the index and working tree
+)
+
+// String returns the git reset flag name of the mode.
+func (m ResetMode) String() string {
+	switch m {
+	case ResetSoft:
+		return "soft"
+	case ResetHard:
+		return "hard"
+	default:
+		return "mixed"
+	}
+}
+
+// ResetCommit is a commit that leaves the branch on reset.
+type ResetCommit struct {
+	Hash      string
+	ShortHash string
+	Subject   string
+}
+
+// ResetPreview lists what a reset to Target changes.
+type ResetPreview struct {
+	Target  string        // Commit the branch is reset to
+	Branch  string        // Checked out branch, "" when detached
+	Commits []ResetCommit // Commits leaving the branch, newest first
+	Staged  []string      // Files with staged changes
+	Changed []string      // Files with staged or unstaged changes
+}
+
+// LoadResetPreview collects the commits and uncommitted changes a reset of
+// HEAD to target would move or discard.
+func LoadResetPreview(workDir, target string) (*ResetPreview, error) {
+	preview := &ResetPreview{Target: target}
+
+	out, err := exec.Command("git", "-C", workDir, "log", "--format=%H%x00%h%x00%s", target+"..HEAD").Output()
+	if err != nil {
+		return nil, fmt.Errorf("list commits after %s: %w", shortHash(target), err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		parts := strings.SplitN(line, "\x00", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		preview.Commits = append(preview.Commits, ResetCommit{Hash: parts[0], ShortHash: parts[1], Subject: parts[2]})
+	}
+
+	if out, err := exec.Command("git", "-C", workDir, "symbolic-ref", "--quiet", "--short", "HEAD").Output(); err == nil {
+		preview.Branch = strings.TrimSpace(string(out))
+	}
+	preview.Staged = gitFileList(workDir, "diff", "--cached", "--name-only")
+	preview.Changed = gitFileList(workDir, "diff", "--name-only", "HEAD")
+	return preview, nil
+}
+
+// gitFileList runs a git command that prints one path per line.
+func gitFileList(workDir string, args ...string) []string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workDir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	return files
+}
+
+// ResetTo moves HEAD to the commit hash.
+func ResetTo(workDir, hash string, mode ResetMode) error {
+	cmd := exec.Command("git", "reset", "--"+mode.String(), hash)
+	cmd.Dir = workDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return &RemoteError{Output: string(output), Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,530 @@
+package gitstatus
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ConflictOp is the git operation that stopped with conflicts.
+type ConflictOp int
+
+const (
+	ConflictOpNone       ConflictOp = iota // No operation in progress
+	ConflictOpMerge                        // git merge, or git pull with merge
+	ConflictOpRebase                       // git rebase, or git pull --rebase
+	ConflictOpCherryPick                   // git cherry-pick
+	ConflictOpRevert                       // git revert
+)
+
+// String returns the git command of the operation.
+func (op ConflictOp) String() string {
+	switch op {
+	case ConflictOpMerge:
+		return "merge"
+	case ConflictOpRebase:
+		return "rebase"
+	case ConflictOpCherryPick:
+		return "cherry-pick"
+	case ConflictOpRevert:
+		return "revert"
+	default:
+		return ""
+	}
+}
+
+// GetConflictOp returns the operation in progress in workDir.
+func GetConflictOp(workDir string) ConflictOp {
+	if GetRebaseState(workDir) != nil {
+		return ConflictOpRebase
+	}
+	for _, head := range []struct {
+		name string
+		op   ConflictOp
+	}{
+		{"MERGE_HEAD", ConflictOpMerge},
+		{"CHERRY_PICK_HEAD", ConflictOpCherryPick},
+		{"REVERT_HEAD", ConflictOpRevert},
+	} {
+		path, err := gitPath(workDir, head.name)
+		if err != nil {
+			return ConflictOpNone
+		}
+		if _, err := os.Stat(path); err == nil {
+			return head.op
+		}
+	}
+	return ConflictOpNone
+}
+
+// ContinueConflictOp finishes the operation once its conflicts are resolved
+// and staged: a merge is committed with its prepared message, the others run
+// --continue. Errors carry git's output as a RemoteError, so a rebase that
+// stops at the next conflict is recognised by IsConflictError.
+func ContinueConflictOp(workDir string, op ConflictOp) error {
+	var cmd *exec.Cmd
+	switch op {
+	case ConflictOpMerge:
+		cmd = exec.Command("git", "commit", "--no-edit")
+	case ConflictOpRebase:
+		return ContinueRebase(workDir)
+	case ConflictOpCherryPick, ConflictOpRevert:
+		cmd = exec.Command("git", op.String(), "--continue")
+	default:
+		return errors.New("no merge, rebase, cherry-pick or revert in progress")
+	}
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(), "GIT_EDITOR=true")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return &RemoteError{Output: string(output), Err: err}
+	}
+	return nil
+}
+
+// AbortConflictOp abandons the operation, restoring the branch to where it
+// was before the operation started.
+func AbortConflictOp(workDir string, op ConflictOp) error {
+	switch op {
+	case ConflictOpNone:
+		return errors.New("no merge, rebase, cherry-pick or revert in progress")
+	case ConflictOpRebase:
+		defer CleanRebaseFiles(workDir)
+	}
+	cmd := exec.Command("git", op.String(), "--abort")
+	cmd.Dir = workDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return &RemoteError{Output: string(output), Err: err}
+	}
+	return nil
+}
+
+// ConflictResolution is how a conflict region is resolved.
+type ConflictResolution int
+
+const (
+	ConflictUnresolved ConflictResolution = iota
+	ConflictOurs                          // Keep our side
+	ConflictTheirs                        // Keep their side
+	ConflictBoth                          // Keep our side followed by theirs
+	ConflictEdited                        // Keep lines edited by hand
+)
+
+// String returns a short label for the resolution.
+func (r ConflictResolution) String() string {
+	switch r {
+	case ConflictOurs:
+		return "ours"
+	case ConflictTheirs:
+		return "theirs"
+	case ConflictBoth:
+		return "both"
+	case ConflictEdited:
+		return "edited"
+	default:
+		return "unresolved"
+	}
+}
+
+// ConflictRegion is one conflict of a file: the lines between a pair of
+// conflict markers.
+type ConflictRegion struct {
+	OursLabel   string // Label after <<<<<<<, e.g. "HEAD"
+	BaseLabel   string // Label after |||||||, when the base is known
+	TheirsLabel string // Label after >>>>>>>, e.g. the merged branch
+	Ours        []string
+	Base        []string
+	Theirs      []string
+	HasBase     bool // Base is known: the file has diff3 markers or it was recovered from the index
+	Resolution  ConflictResolution
+	Edited      []string // Result lines for ConflictEdited
+
+	// Original marker lines, so unresolved regions are written back unchanged
+	oursMarker   string
+	baseMarker   string // Empty when the file has no base section
+	sepMarker    string
+	theirsMarker string
+}
+
+// Result returns the lines that replace the region in the resolved file.
+func (r *ConflictRegion) Result() []string {
+	switch r.Resolution {
+	case ConflictOurs:
+		return r.Ours
+	case ConflictTheirs:
+		return r.Theirs
+	case ConflictBoth:
+		return append(append([]string(nil), r.Ours...), r.Theirs...)
+	case ConflictEdited:
+		return r.Edited
+	default:
+		return nil
+	}
+}
+
+// conflictChunk is a run of lines outside conflicts, or one conflict region.
+type conflictChunk struct {
+	Lines  []string
+	Region *ConflictRegion
+}
+
+// ConflictFile is a conflicted file split into its conflict regions.
+type ConflictFile struct {
+	Path          string
+	Regions       []*ConflictRegion
+	Binary        bool // Content is binary; only a whole side can be taken
+	Missing       bool // File is not in the working tree
+	OursDeleted   bool // Our side deleted the file
+	TheirsDeleted bool // Their side deleted the file
+
+	chunks  []conflictChunk
+	newline bool // Content ends with a newline
+}
+
+// ErrConflictMarkers is returned for a conflict region without its closing
+// marker.
+var ErrConflictMarkers = errors.New("unterminated conflict markers")
+
+// isConflictMarker reports whether line is a conflict marker made of ch.
+func isConflictMarker(line string, ch byte) bool {
+	if len(line) < 7 || strings.Count(line[:7], string(ch)) != 7 {
+		return false
+	}
+	rest := strings.TrimRight(line[7:], "\r")
+	if ch == '=' {
+		return rest == ""
+	}
+	return rest == "" || rest[0] == ' '
+}
+
+// markerLabel returns the label after a conflict marker.
+func markerLabel(line string) string {
+	return strings.TrimSpace(line[7:])
+}
+
+// ParseConflicts splits a file's content into its conflict regions.
+func ParseConflicts(path, content string) (*ConflictFile, error) {
+	f := &ConflictFile{Path: path}
+	if strings.Contains(content, "\x00") {
+		f.Binary = true
+		return f, nil
+	}
+	f.newline = strings.HasSuffix(content, "\n")
+	content = strings.TrimSuffix(content, "\n")
+	var lines []string
+	if content != "" {
+		lines = strings.Split(content, "\n")
+	}
+
+	const (
+		outside = iota
+		inOurs
+		inBase
+		inTheirs
+	)
+	state := outside
+	var text []string
+	var region *ConflictRegion
+	for _, line := range lines {
+		switch state {
+		case outside:
+			if isConflictMarker(line, '<') {
+				if len(text) > 0 {
+					f.chunks = append(f.chunks, conflictChunk{Lines: text})
+					text = nil
+				}
+				region = &ConflictRegion{OursLabel: markerLabel(line), oursMarker: line}
+				state = inOurs
+				continue
+			}
+			text = append(text, line)
+		case inOurs, inBase:
+			switch {
+			case state == inOurs && isConflictMarker(line, '|'):
+				region.BaseLabel = markerLabel(line)
+				region.baseMarker = line
+				region.HasBase = true
+				state = inBase
+			case isConflictMarker(line, '='):
+				region.sepMarker = line
+				state = inTheirs
+			case state == inOurs:
+				region.Ours = append(region.Ours, line)
+			default:
+				region.Base = append(region.Base, line)
+			}
+		case inTheirs:
+			if isConflictMarker(line, '>') {
+				region.TheirsLabel = markerLabel(line)
+				region.theirsMarker = line
+				f.Regions = append(f.Regions, region)
+				f.chunks = append(f.chunks, conflictChunk{Region: region})
+				region = nil
+				state = outside
+				continue
+			}
+			region.Theirs = append(region.Theirs, line)
+		}
+	}
+	if state != outside {
+		return nil, fmt.Errorf("%w in %s", ErrConflictMarkers, path)
+	}
+	if len(text) > 0 {
+		f.chunks = append(f.chunks, conflictChunk{Lines: text})
+	}
+	return f, nil
+}
+
+// Unresolved returns the number of regions not yet resolved.
+func (f *ConflictFile) Unresolved() int {
+	n := 0
+	for _, r := range f.Regions {
+		if r.Resolution == ConflictUnresolved {
+			n++
+		}
+	}
+	return n
+}
+
+// Context returns up to n lines before and after the region at idx.
+func (f *ConflictFile) Context(idx, n int) (before, after []string) {
+	for i, c := range f.chunks {
+		if c.Region != f.Regions[idx] {
+			continue
+		}
+		if i > 0 && f.chunks[i-1].Region == nil {
+			lines := f.chunks[i-1].Lines
+			before = lines[max(0, len(lines)-n):]
+		}
+		if i+1 < len(f.chunks) && f.chunks[i+1].Region == nil {
+			lines := f.chunks[i+1].Lines
+			after = lines[:min(n, len(lines))]
+		}
+		break
+	}
+	return before, after
+}
+
+// Render returns the file content with resolved regions replaced by their
+// result. Unresolved regions keep their conflict markers.
+func (f *ConflictFile) Render() string {
+	var lines []string
+	for _, c := range f.chunks {
+		r := c.Region
+		switch {
+		case r == nil:
+			lines = append(lines, c.Lines...)
+		case r.Resolution != ConflictUnresolved:
+			lines = append(lines, r.Result()...)
+		default:
+			lines = append(lines, r.oursMarker)
+			lines = append(lines, r.Ours...)
+			if r.baseMarker != "" {
+				lines = append(lines, r.baseMarker)
+				lines = append(lines, r.Base...)
+			}
+			lines = append(lines, r.sepMarker)
+			lines = append(lines, r.Theirs...)
+			lines = append(lines, r.theirsMarker)
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	content := strings.Join(lines, "\n")
+	if f.newline {
+		content += "\n"
+	}
+	return content
+}
+
+// LoadConflictFile reads a conflicted file from the working tree. When the
+// conflict markers lack the base section, the base of each region is
+// recovered by re-merging the index stages with diff3 markers.
+func LoadConflictFile(workDir, path string) (*ConflictFile, error) {
+	data, err := os.ReadFile(filepath.Join(workDir, path))
+	missing := errors.Is(err, os.ErrNotExist)
+	if err != nil && !missing {
+		return nil, err
+	}
+	f, err := ParseConflicts(path, string(data))
+	if err != nil {
+		return nil, err
+	}
+	f.Missing = missing
+
+	stages, err := readConflictStages(workDir, path)
+	if err != nil {
+		return nil, err
+	}
+	_, hasOurs := stages[2]
+	_, hasTheirs := stages[3]
+	f.OursDeleted = len(stages) > 0 && !hasOurs
+	f.TheirsDeleted = len(stages) > 0 && !hasTheirs
+	fillConflictBases(f, stages)
+	return f, nil
+}
+
+// readConflictStages returns the content of a conflicted file's index stages:
+// 1 for the base, 2 for ours and 3 for theirs. A side that deleted the file
+// has no stage.
+func readConflictStages(workDir, path string) (map[int]string, error) {
+	cmd := exec.Command("git", "ls-files", "-u", "-z", "--", path)
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	stages := make(map[int]string)
+	for _, entry := range strings.Split(string(output), "\x00") {
+		// Format: mode SP hash SP stage TAB path
+		meta, _, ok := strings.Cut(entry, "\t")
+		fields := strings.Fields(meta)
+		if !ok || len(fields) != 3 || len(fields[2]) != 1 {
+			continue
+		}
+		stage := int(fields[2][0] - '0')
+		cmd := exec.Command("git", "cat-file", "blob", fields[1])
+		cmd.Dir = workDir
+		blob, err := cmd.Output()
+		if err != nil {
+			return nil, err
+		}
+		stages[stage] = string(blob)
+	}
+	return stages, nil
+}
+
+// fillConflictBases sets the base of regions without one from a diff3 merge
+// of the stages. Regions are matched by position and must have the same
+// sides, so hand edits to the file never pick up a wrong base.
+func fillConflictBases(f *ConflictFile, stages map[int]string) {
+	missing := false
+	for _, r := range f.Regions {
+		if !r.HasBase {
+			missing = true
+		}
+	}
+	base, ok1 := stages[1]
+	ours, ok2 := stages[2]
+	theirs, ok3 := stages[3]
+	if !missing || !ok1 || !ok2 || !ok3 {
+		return
+	}
+
+	merged, err := mergeFileDiff3(base, ours, theirs)
+	if err != nil {
+		return
+	}
+	diff3, err := ParseConflicts(f.Path, merged)
+	if err != nil || len(diff3.Regions) != len(f.Regions) {
+		return
+	}
+	for i, r := range f.Regions {
+		d := diff3.Regions[i]
+		if r.HasBase || !equalLines(r.Ours, d.Ours) || !equalLines(r.Theirs, d.Theirs) {
+			continue
+		}
+		r.Base = d.Base
+		r.HasBase = true
+	}
+}
+
+// mergeFileDiff3 merges three versions of a file with git merge-file,
+// returning the result with diff3 conflict markers.
+func mergeFileDiff3(base, ours, theirs string) (string, error) {
+	dir, err := os.MkdirTemp("", "sidecar-conflict-")
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	names := []string{"ours", "base", "theirs"}
+	for i, content := range []string{ours, base, theirs} {
+		if err := os.WriteFile(filepath.Join(dir, names[i]), []byte(content), 0644); err != nil {
+			return "", err
+		}
+	}
+	cmd := exec.Command("git", "merge-file", "-p", "--diff3", "ours", "base", "theirs")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	// The exit code is the number of conflicts; only negative codes are errors
+	var exitErr *exec.ExitError
+	if err != nil && (!errors.As(err, &exitErr) || exitErr.ExitCode() > 127) {
+		return "", err
+	}
+	return string(output), nil
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// SaveConflictFile writes the file with its resolved regions and stages it
+// once no conflicts are left. Returns whether the file was staged.
+func SaveConflictFile(workDir string, f *ConflictFile) (bool, error) {
+	if f.Binary || len(f.Regions) == 0 {
+		return true, MarkConflictResolved(workDir, f.Path)
+	}
+	if err := os.WriteFile(filepath.Join(workDir, f.Path), []byte(f.Render()), 0644); err != nil {
+		return false, err
+	}
+	if f.Unresolved() > 0 {
+		return false, nil
+	}
+	return true, MarkConflictResolved(workDir, f.Path)
+}
+
+// MarkConflictResolved stages a conflicted file as it is in the working tree,
+// or its removal when the file was deleted.
+func MarkConflictResolved(workDir, path string) error {
+	args := []string{"add", "--", path}
+	if _, err := os.Lstat(filepath.Join(workDir, path)); errors.Is(err, os.ErrNotExist) {
+		args = []string{"rm", "-q", "--cached", "--", path}
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// ResolveConflictSide resolves a whole file by taking our or their version,
+// or by removing it when that side deleted it, and stages the result.
+func ResolveConflictSide(workDir, path string, ours bool) error {
+	side, stage := "--theirs", 3
+	if ours {
+		side, stage = "--ours", 2
+	}
+	stages, err := readConflictStages(workDir, path)
+	if err != nil {
+		return err
+	}
+
+	var args []string
+	if _, ok := stages[stage]; ok {
+		args = []string{"checkout", side, "--", path}
+	} else {
+		args = []string{"rm", "-q", "--", path}
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	if args[0] == "rm" {
+		return nil
+	}
+	return MarkConflictResolved(workDir, path)
+}
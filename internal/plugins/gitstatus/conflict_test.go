@@ -0,0 +1,271 @@
+package gitstatus
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/mouse"
+	"github.com/marcus/sidecar/internal/plugin"
+)
+
+// initConflictRepo commits base as file.txt, changes it to ours on the
+// current branch and to theirs on branch "other", and merges other, leaving
+// the merge stopped with conflicts.
+func initConflictRepo(t *testing.T, base, ours, theirs string) string {
+	t.Helper()
+	dir := initPatchRepo(t, base, base)
+	git := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("config", "merge.conflictStyle", "merge")
+	git("checkout", "-q", "-b", "other")
+	writeTestFile(t, dir, theirs)
+	git("commit", "-q", "-am", "theirs")
+	git("checkout", "-q", "-")
+	writeTestFile(t, dir, ours)
+	git("commit", "-q", "-am", "ours")
+
+	cmd := exec.Command("git", "merge", "other")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err == nil {
+		t.Fatalf("merge succeeded, want conflicts:\n%s", out)
+	}
+	return dir
+}
+
+const conflictContent = `a
+<<<<<<< HEAD
+b1
+||||||| base
+b
+=======
+b2
+>>>>>>> other
+c
+<<<<<<< HEAD
+d1
+=======
+d2
+d3
+>>>>>>> other
+`
+
+func TestParseConflicts(t *testing.T) {
+	f, err := ParseConflicts("f", conflictContent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Regions) != 2 {
+		t.Fatalf("regions = %d, want 2", len(f.Regions))
+	}
+	r := f.Regions[0]
+	if r.OursLabel != "HEAD" || r.TheirsLabel != "other" || !r.HasBase || r.Base[0] != "b" {
+		t.Errorf("first region = %+v", r)
+	}
+	if f.Regions[1].HasBase || len(f.Regions[1].Theirs) != 2 {
+		t.Errorf("second region = %+v", f.Regions[1])
+	}
+
+	// Unresolved regions are written back unchanged
+	if got := f.Render(); got != conflictContent {
+		t.Errorf("render =\n%s\nwant\n%s", got, conflictContent)
+	}
+
+	f.Regions[0].Resolution = ConflictTheirs
+	if got, want := f.Render(), "a\nb2\nc\n<<<<<<< HEAD\nd1\n=======\nd2\nd3\n>>>>>>> other\n"; got != want {
+		t.Errorf("render with one resolved =\n%s\nwant\n%s", got, want)
+	}
+	if f.Unresolved() != 1 {
+		t.Errorf("unresolved = %d, want 1", f.Unresolved())
+	}
+
+	f.Regions[1].Resolution = ConflictBoth
+	if got, want := f.Render(), "a\nb2\nc\nd1\nd2\nd3\n"; got != want {
+		t.Errorf("render resolved = %q, want %q", got, want)
+	}
+	f.Regions[1].Resolution, f.Regions[1].Edited = ConflictEdited, nil
+	if got, want := f.Render(), "a\nb2\nc\n"; got != want {
+		t.Errorf("render with region removed = %q, want %q", got, want)
+	}
+
+	if before, after := f.Context(1, 2); len(before) != 1 || before[0] != "c" || after != nil {
+		t.Errorf("context = %q, %q", before, after)
+	}
+}
+
+func TestParseConflicts_Unterminated(t *testing.T) {
+	_, err := ParseConflicts("f", "<<<<<<< HEAD\na\n=======\nb\n")
+	if !errors.Is(err, ErrConflictMarkers) {
+		t.Errorf("err = %v, want ErrConflictMarkers", err)
+	}
+	// A line of equals signs outside a conflict is content
+	f, err := ParseConflicts("f", "=======\n<<<<<<<< not a marker\n")
+	if err != nil || len(f.Regions) != 0 {
+		t.Errorf("plain content: regions %v, err %v", f, err)
+	}
+}
+
+func TestLoadConflictFile_RecoversBase(t *testing.T) {
+	dir := initConflictRepo(t, "a\nb\nc\n", "a\nours\nc\n", "a\ntheirs\nc\n")
+
+	f, err := LoadConflictFile(dir, "file.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Regions) != 1 {
+		t.Fatalf("regions = %d, want 1:\n%s", len(f.Regions), readTestFile(t, dir))
+	}
+	r := f.Regions[0]
+	if !r.HasBase || len(r.Base) != 1 || r.Base[0] != "b" {
+		t.Errorf("base = %q (known %v), want recovered from the index", r.Base, r.HasBase)
+	}
+	if r.Ours[0] != "ours" || r.Theirs[0] != "theirs" {
+		t.Errorf("sides = %q, %q", r.Ours, r.Theirs)
+	}
+	// The file itself keeps its two-way markers
+	if strings.Contains(f.Render(), "|||||||") {
+		t.Error("render added a base section")
+	}
+}
+
+func TestSaveConflictFile_AndContinueMerge(t *testing.T) {
+	dir := initConflictRepo(t, "a\nb\nc\n", "a\nours\nc\n", "a\ntheirs\nc\n")
+	if op := GetConflictOp(dir); op != ConflictOpMerge {
+		t.Fatalf("op = %v, want merge", op)
+	}
+
+	f, err := LoadConflictFile(dir, "file.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Regions[0].Resolution = ConflictBoth
+	staged, err := SaveConflictFile(dir, f)
+	if err != nil || !staged {
+		t.Fatalf("save: staged %v, err %v", staged, err)
+	}
+	if got, want := readTestFile(t, dir), "a\nours\ntheirs\nc\n"; got != want {
+		t.Errorf("file = %q, want %q", got, want)
+	}
+	if files := GetConflictedFiles(dir); len(files) != 0 {
+		t.Fatalf("still conflicted: %v", files)
+	}
+
+	if err := ContinueConflictOp(dir, ConflictOpMerge); err != nil {
+		t.Fatal(err)
+	}
+	if op := GetConflictOp(dir); op != ConflictOpNone {
+		t.Errorf("op after continue = %v, want none", op)
+	}
+	parents, _ := exec.Command("git", "-C", dir, "log", "-1", "--format=%P").Output()
+	if len(strings.Fields(string(parents))) != 2 {
+		t.Errorf("HEAD is not a merge commit: parents %q", parents)
+	}
+}
+
+func TestResolveConflictSide_DeletedFile(t *testing.T) {
+	dir := initPatchRepo(t, "a\n", "a\n")
+	git := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		_, _ = cmd.CombinedOutput()
+	}
+	git("checkout", "-q", "-b", "other")
+	git("rm", "-q", "file.txt")
+	git("commit", "-q", "-m", "delete")
+	git("checkout", "-q", "-")
+	writeTestFile(t, dir, "a\nchanged\n")
+	git("commit", "-q", "-am", "change")
+	git("merge", "other")
+
+	f, err := LoadConflictFile(dir, "file.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.TheirsDeleted || f.OursDeleted || len(f.Regions) != 0 {
+		t.Fatalf("file = %+v, want deleted on their side", f)
+	}
+
+	if err := ResolveConflictSide(dir, "file.txt", false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "file.txt")); err == nil {
+		t.Error("taking their side should delete the file")
+	}
+	if files := GetConflictedFiles(dir); len(files) != 0 {
+		t.Errorf("still conflicted: %v", files)
+	}
+}
+
+func TestConflictViewKeys(t *testing.T) {
+	f, err := ParseConflicts("f", conflictContent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Plugin{ctx: &plugin.Context{}, hasRepo: true, viewMode: ViewModeStatus, width: 120, height: 40, mouseHandler: mouse.NewHandler()}
+	p.openConflicts("")
+	p.handleConflictsLoaded(ConflictsLoadedMsg{Op: ConflictOpMerge, Files: []string{"f", "g"}, File: f})
+	if p.viewMode != ViewModeConflicts || p.FocusContext() != "git-conflicts" {
+		t.Fatalf("view not open: mode %v, context %q", p.viewMode, p.FocusContext())
+	}
+
+	press := func(keys ...string) tea.Cmd {
+		var last tea.Cmd
+		for _, k := range keys {
+			msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+			switch k {
+			case "esc":
+				msg = tea.KeyMsg{Type: tea.KeyEsc}
+			case "ctrl+s":
+				msg = tea.KeyMsg{Type: tea.KeyCtrlS}
+			}
+			_, last = p.updateConflicts(msg)
+		}
+		return last
+	}
+
+	// Picking a side moves on to the next unresolved conflict
+	press("t")
+	if f.Regions[0].Resolution != ConflictTheirs || p.conflictRegion != 1 {
+		t.Fatalf("after t: resolution %v, region %d", f.Regions[0].Resolution, p.conflictRegion)
+	}
+
+	// Edit the second conflict's result
+	press("e")
+	if !p.conflictEditing || !p.ConsumesTextInput() || p.FocusContext() != "git-conflicts-edit" {
+		t.Fatal("e should open the result editor")
+	}
+	p.conflictEditor.SetValue("d")
+	press("ctrl+s")
+	if r := f.Regions[1]; r.Resolution != ConflictEdited || strings.Join(r.Edited, "|") != "d" {
+		t.Errorf("second region = %+v, want edited to d", r)
+	}
+	if got := f.Render(); got != "a\nb2\nc\nd\n" {
+		t.Errorf("render = %q", got)
+	}
+	if view := p.renderConflictView(); !strings.Contains(view, "Conflict 2/2") {
+		t.Error("view does not show the selected conflict")
+	}
+
+	// Unsaved resolutions: the first esc warns, the second closes
+	press("esc")
+	if p.viewMode != ViewModeConflicts {
+		t.Fatal("esc with unsaved resolutions should warn first")
+	}
+	if cmd := press("c"); cmd == nil || p.conflictBusy {
+		t.Error("continue with unsaved resolutions should ask to save first")
+	}
+	p.conflictDiscardKey = ""
+	press("esc", "esc")
+	if p.viewMode != ViewModeStatus || p.conflictFiles != nil {
+		t.Error("second esc should close the view")
+	}
+}
@@ -0,0 +1,757 @@
+package gitstatus
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/marcus/sidecar/internal/app"
+	appmsg "github.com/marcus/sidecar/internal/msg"
+	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/styles"
+)
+
+const regionConflicts = "conflicts" // Conflict resolution view
+
+// OpenConflictsMsg opens the conflict resolution view on the repository's
+// conflicted files. Other plugins send it when a merge they ran stops with
+// conflicts.
+type OpenConflictsMsg struct {
+	Path string // File to show first; empty for the first conflicted file
+}
+
+// ConflictsLoadedMsg is sent when the conflicted files are listed.
+type ConflictsLoadedMsg struct {
+	Epoch uint64
+	Op    ConflictOp
+	Files []string
+	File  *ConflictFile // The file to show first
+	Err   error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m ConflictsLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// ConflictFileLoadedMsg is sent when a conflicted file is loaded.
+type ConflictFileLoadedMsg struct {
+	Epoch uint64
+	File  *ConflictFile
+	Err   error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m ConflictFileLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// ConflictSavedMsg is sent when resolutions are written to a file.
+type ConflictSavedMsg struct {
+	Path   string
+	Staged bool // File was marked resolved
+	Err    error
+}
+
+// ConflictContinueDoneMsg is sent when continuing the merge or rebase
+// finishes or stops.
+type ConflictContinueDoneMsg struct {
+	Op    ConflictOp
+	Next  ConflictOp // Operation still in progress afterwards
+	Files []string   // Files left conflicted, e.g. at the next rebase step
+	Err   error
+}
+
+// conflictOpTitle returns the operation name for titles and toasts.
+func conflictOpTitle(op ConflictOp) string {
+	switch op {
+	case ConflictOpMerge:
+		return "Merge"
+	case ConflictOpRebase:
+		return "Rebase"
+	case ConflictOpCherryPick:
+		return "Cherry-pick"
+	case ConflictOpRevert:
+		return "Revert"
+	default:
+		return "Resolve"
+	}
+}
+
+// hasConflicts reports whether the file list has unmerged files.
+func (p *Plugin) hasConflicts() bool {
+	if p.tree == nil {
+		return false
+	}
+	for _, e := range p.tree.Modified {
+		if e.Status == StatusUnmerged {
+			return true
+		}
+	}
+	return false
+}
+
+// openConflicts opens the conflict resolution view, starting at path when
+// it is conflicted.
+func (p *Plugin) openConflicts(path string) tea.Cmd {
+	if p.viewMode != ViewModeConflicts {
+		p.conflictReturnMode = ViewModeStatus
+		if p.viewMode == ViewModeDiff {
+			p.conflictReturnMode = ViewModeDiff
+		}
+	}
+	p.viewMode = ViewModeConflicts
+	p.conflictFile = nil
+	p.conflictError = ""
+	p.conflictEditing = false
+	p.conflictDiscardKey = ""
+	return p.loadConflicts(path)
+}
+
+// loadConflicts lists the conflicted files and loads the first one to show.
+func (p *Plugin) loadConflicts(path string) tea.Cmd {
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		files := GetConflictedFiles(workDir)
+		msg := ConflictsLoadedMsg{Epoch: epoch, Op: GetConflictOp(workDir), Files: files}
+		if len(files) == 0 {
+			return msg
+		}
+		first := files[0]
+		for _, f := range files {
+			if f == path {
+				first = f
+			}
+		}
+		msg.File, msg.Err = LoadConflictFile(workDir, first)
+		return msg
+	}
+}
+
+// handleConflictsLoaded shows the loaded conflicts, or closes the view when
+// there are none.
+func (p *Plugin) handleConflictsLoaded(msg ConflictsLoadedMsg) tea.Cmd {
+	if p.viewMode != ViewModeConflicts {
+		return nil
+	}
+	if msg.Err != nil {
+		p.closeConflicts()
+		p.showErrorModal("Conflicts", msg.Err)
+		return nil
+	}
+	if len(msg.Files) == 0 {
+		p.closeConflicts()
+		return appmsg.ShowToast("No conflicts to resolve", 2*time.Second)
+	}
+	p.conflictOp = msg.Op
+	p.conflictFiles = msg.Files
+	p.conflictResolved = make(map[string]bool)
+	p.setConflictFile(msg.File)
+	return nil
+}
+
+// setConflictFile shows a loaded file at its first unresolved region.
+func (p *Plugin) setConflictFile(f *ConflictFile) {
+	p.conflictFile = f
+	p.conflictRegion = 0
+	p.conflictScroll = 0
+	p.conflictDirty = false
+	p.conflictDiscardKey = ""
+	for i, path := range p.conflictFiles {
+		if path == f.Path {
+			p.conflictFileIdx = i
+		}
+	}
+}
+
+// loadConflictFile loads the conflicted file at idx.
+func (p *Plugin) loadConflictFile(idx int) tea.Cmd {
+	path := p.conflictFiles[idx]
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		f, err := LoadConflictFile(workDir, path)
+		return ConflictFileLoadedMsg{Epoch: epoch, File: f, Err: err}
+	}
+}
+
+func (p *Plugin) handleConflictFileLoaded(msg ConflictFileLoadedMsg) {
+	if p.viewMode != ViewModeConflicts {
+		return
+	}
+	if msg.Err != nil {
+		p.conflictError = msg.Err.Error()
+		return
+	}
+	p.conflictError = ""
+	p.setConflictFile(msg.File)
+}
+
+func (p *Plugin) closeConflicts() {
+	p.viewMode = p.conflictReturnMode
+	p.conflictFile = nil
+	p.conflictFiles = nil
+	p.conflictResolved = nil
+	p.conflictEditing = false
+	p.conflictDirty = false
+	p.conflictDiscardKey = ""
+}
+
+// confirmConflictDiscard reports whether an action that drops unsaved resolutions
+// may go ahead: the first press warns, a second press of the same key
+// confirms.
+func (p *Plugin) confirmConflictDiscard(key string) (bool, tea.Cmd) {
+	if !p.conflictDirty || p.conflictDiscardKey == key {
+		return true, nil
+	}
+	p.conflictDiscardKey = key
+	return false, appmsg.ShowToast("Unsaved resolutions: s to save, "+key+" again to discard", 3*time.Second)
+}
+
+// updateConflicts handles key events in the conflict resolution view.
+func (p *Plugin) updateConflicts(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	if p.conflictEditing {
+		return p.updateConflictEdit(msg)
+	}
+	key := msg.String()
+	if key == "esc" || key == "q" {
+		if ok, cmd := p.confirmConflictDiscard(key); !ok {
+			return p, cmd
+		}
+		p.closeConflicts()
+		return p, p.refresh()
+	}
+	if p.conflictBusy || p.conflictFiles == nil {
+		return p, nil
+	}
+
+	switch key {
+	case "tab", "]":
+		return p, p.switchConflictFile(key, 1)
+	case "shift+tab", "[":
+		return p, p.switchConflictFile(key, -1)
+	case "c":
+		return p, p.continueConflicts()
+	case "s":
+		return p, p.saveConflictFile()
+	case "O":
+		return p, p.takeConflictSide(true)
+	case "T":
+		return p, p.takeConflictSide(false)
+	}
+	p.conflictDiscardKey = ""
+
+	f := p.conflictFile
+	if f == nil || len(f.Regions) == 0 {
+		return p, nil
+	}
+	switch key {
+	case "j", "down", "n":
+		p.selectConflictRegion(p.conflictRegion + 1)
+	case "k", "up", "N":
+		p.selectConflictRegion(p.conflictRegion - 1)
+	case "g":
+		p.selectConflictRegion(0)
+	case "G":
+		p.selectConflictRegion(len(f.Regions) - 1)
+	case "o":
+		p.resolveConflictRegion(ConflictOurs)
+	case "t":
+		p.resolveConflictRegion(ConflictTheirs)
+	case "b":
+		p.resolveConflictRegion(ConflictBoth)
+	case "u":
+		p.resolveConflictRegion(ConflictUnresolved)
+	case "e":
+		p.startConflictEdit()
+	case "ctrl+d":
+		p.conflictScroll += 5
+	case "ctrl+u":
+		p.conflictScroll = max(0, p.conflictScroll-5)
+	}
+	return p, nil
+}
+
+func (p *Plugin) selectConflictRegion(idx int) {
+	n := len(p.conflictFile.Regions)
+	p.conflictRegion = max(0, min(idx, n-1))
+	p.conflictScroll = 0
+}
+
+// resolveConflictRegion resolves the selected region and moves on to the
+// next unresolved one.
+func (p *Plugin) resolveConflictRegion(res ConflictResolution) {
+	r := p.conflictFile.Regions[p.conflictRegion]
+	r.Resolution = res
+	if res != ConflictEdited {
+		r.Edited = nil
+	}
+	p.conflictDirty = true
+	if res == ConflictUnresolved {
+		return
+	}
+	regions := p.conflictFile.Regions
+	for i := 1; i < len(regions); i++ {
+		next := (p.conflictRegion + i) % len(regions)
+		if regions[next].Resolution == ConflictUnresolved {
+			p.selectConflictRegion(next)
+			return
+		}
+	}
+}
+
+// switchConflictFile moves to the next or previous conflicted file.
+func (p *Plugin) switchConflictFile(key string, delta int) tea.Cmd {
+	n := len(p.conflictFiles)
+	if n < 2 {
+		return nil
+	}
+	if ok, cmd := p.confirmConflictDiscard(key); !ok {
+		return cmd
+	}
+	p.conflictEditing = false
+	return p.loadConflictFile((p.conflictFileIdx + delta + n) % n)
+}
+
+// nextUnresolvedFile returns the index of the next file not yet resolved, or
+// -1 when every file is.
+func (p *Plugin) nextUnresolvedFile() int {
+	n := len(p.conflictFiles)
+	for i := 1; i <= n; i++ {
+		idx := (p.conflictFileIdx + i) % n
+		if !p.conflictResolved[p.conflictFiles[idx]] {
+			return idx
+		}
+	}
+	return -1
+}
+
+// saveConflictFile writes the resolutions of the current file, staging it
+// once no conflicts are left.
+func (p *Plugin) saveConflictFile() tea.Cmd {
+	f := p.conflictFile
+	if f == nil {
+		return nil
+	}
+	if p.conflictResolved[f.Path] && !p.conflictDirty {
+		return appmsg.ShowToast("Already resolved", 2*time.Second)
+	}
+	p.conflictBusy = true
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		staged, err := SaveConflictFile(workDir, f)
+		return ConflictSavedMsg{Path: f.Path, Staged: staged, Err: err}
+	}
+}
+
+// takeConflictSide resolves the current file by taking our or their version.
+func (p *Plugin) takeConflictSide(ours bool) tea.Cmd {
+	f := p.conflictFile
+	if f == nil {
+		return nil
+	}
+	if p.conflictResolved[f.Path] {
+		return appmsg.ShowToast("Already resolved", 2*time.Second)
+	}
+	p.conflictBusy = true
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		err := ResolveConflictSide(workDir, f.Path, ours)
+		return ConflictSavedMsg{Path: f.Path, Staged: err == nil, Err: err}
+	}
+}
+
+// handleConflictSaved reports a save and moves to the next conflicted file
+// once the current one is resolved.
+func (p *Plugin) handleConflictSaved(msg ConflictSavedMsg) tea.Cmd {
+	p.conflictBusy = false
+	if p.viewMode != ViewModeConflicts || p.conflictFiles == nil {
+		return p.refresh()
+	}
+	if msg.Err != nil {
+		return tea.Batch(p.refresh(), func() tea.Msg {
+			return app.ToastMsg{Message: "Save failed: " + msg.Err.Error(), Duration: 3 * time.Second, IsError: true}
+		})
+	}
+	p.conflictDirty = false
+	p.conflictDiscardKey = ""
+	cmds := []tea.Cmd{p.refresh()}
+	if !msg.Staged {
+		left := 0
+		if p.conflictFile != nil {
+			left = p.conflictFile.Unresolved()
+		}
+		text := fmt.Sprintf("Saved %s, %d conflict(s) left", msg.Path, left)
+		return tea.Batch(append(cmds, appmsg.ShowToast(text, 2*time.Second))...)
+	}
+
+	p.conflictResolved[msg.Path] = true
+	if next := p.nextUnresolvedFile(); next >= 0 {
+		cmds = append(cmds, p.loadConflictFile(next), appmsg.ShowToast("Resolved "+msg.Path, 2*time.Second))
+		return tea.Batch(cmds...)
+	}
+	text := "All conflicts resolved"
+	if p.conflictOp != ConflictOpNone {
+		text += fmt.Sprintf(": c to continue the %s", p.conflictOp)
+	}
+	cmds = append(cmds, p.loadConflictFile(p.conflictFileIdx), appmsg.ShowToast(text, 3*time.Second))
+	return tea.Batch(cmds...)
+}
+
+// continueConflicts continues the merge or rebase once every file is
+// resolved.
+func (p *Plugin) continueConflicts() tea.Cmd {
+	if p.conflictDirty {
+		return appmsg.ShowToast("Save the resolutions first (s)", 2*time.Second)
+	}
+	left := 0
+	for _, path := range p.conflictFiles {
+		if !p.conflictResolved[path] {
+			left++
+		}
+	}
+	if left > 0 {
+		return appmsg.ShowToast(fmt.Sprintf("%d file(s) still conflicted", left), 2*time.Second)
+	}
+	if p.conflictOp == ConflictOpNone {
+		p.closeConflicts()
+		return tea.Batch(p.refresh(), appmsg.ShowToast("Conflicts resolved", 2*time.Second))
+	}
+
+	p.conflictBusy = true
+	op := p.conflictOp
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		err := ContinueConflictOp(workDir, op)
+		return ConflictContinueDoneMsg{Op: op, Next: GetConflictOp(workDir), Files: GetConflictedFiles(workDir), Err: err}
+	}
+}
+
+// handleConflictContinueDone closes the view once the operation is over, or
+// shows the next conflicts when it stopped again.
+func (p *Plugin) handleConflictContinueDone(msg ConflictContinueDoneMsg) tea.Cmd {
+	p.conflictBusy = false
+	cmds := []tea.Cmd{p.refresh(), p.loadRecentCommits()}
+	if len(msg.Files) > 0 && p.viewMode == ViewModeConflicts {
+		text := fmt.Sprintf("%s stopped with conflicts in %d file(s)", conflictOpTitle(msg.Op), len(msg.Files))
+		cmds = append(cmds, p.loadConflicts(""), appmsg.ShowToast(text, 3*time.Second))
+		return tea.Batch(cmds...)
+	}
+	if p.viewMode == ViewModeConflicts {
+		p.closeConflicts()
+	}
+	if msg.Err != nil {
+		p.showErrorModal(conflictOpTitle(msg.Op)+" Failed", msg.Err)
+		return tea.Batch(cmds...)
+	}
+
+	text := conflictOpTitle(msg.Op) + " complete"
+	switch {
+	case msg.Op == ConflictOpMerge:
+		text = "Merge committed"
+	case msg.Next == msg.Op:
+		text = conflictOpTitle(msg.Op) + " continued"
+	}
+	return tea.Batch(append(cmds, appmsg.ShowToast(text, 2*time.Second))...)
+}
+
+// startConflictEdit opens the editor on the selected region's result, or on
+// both sides when it is unresolved.
+func (p *Plugin) startConflictEdit() {
+	r := p.conflictFile.Regions[p.conflictRegion]
+	lines := r.Result()
+	if r.Resolution == ConflictUnresolved {
+		lines = append(append([]string(nil), r.Ours...), r.Theirs...)
+	}
+	p.conflictEditor = textarea.New()
+	p.conflictEditor.SetValue(strings.Join(lines, "\n"))
+	p.conflictEditor.FocusedStyle.Placeholder = lipgloss.NewStyle().Foreground(styles.TextSecondary)
+	p.conflictEditor.Placeholder = "Empty to remove the region..."
+	p.conflictEditor.CharLimit = 0
+	p.conflictEditor.Focus()
+	p.conflictEditing = true
+}
+
+// updateConflictEdit handles key events while editing a region's result.
+func (p *Plugin) updateConflictEdit(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		p.conflictEditing = false
+		return p, nil
+	case "ctrl+s":
+		p.conflictEditing = false
+		r := p.conflictFile.Regions[p.conflictRegion]
+		r.Edited = nil
+		if value := p.conflictEditor.Value(); value != "" {
+			r.Edited = strings.Split(value, "\n")
+		}
+		p.resolveConflictRegion(ConflictEdited)
+		return p, nil
+	}
+	var cmd tea.Cmd
+	p.conflictEditor, cmd = p.conflictEditor.Update(msg)
+	return p, cmd
+}
+
+// handleConflictMouse scrolls the sides of the selected region.
+func (p *Plugin) handleConflictMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		p.conflictScroll = max(0, p.conflictScroll-1)
+	case tea.MouseButtonWheelDown:
+		p.conflictScroll++
+	}
+	return p, nil
+}
+
+// renderConflictView renders the conflict resolution view: the sides of the
+// selected region next to each other, and its result below.
+func (p *Plugin) renderConflictView() string {
+	paneHeight := p.height - 2
+	contentWidth := p.width - 4
+	if contentWidth < 30 {
+		contentWidth = 30
+	}
+
+	p.mouseHandler.Clear()
+	p.mouseHandler.HitMap.AddRect(regionConflicts, 0, 0, p.width, p.height, nil)
+
+	var sb strings.Builder
+	sb.WriteString(p.renderConflictHeader(contentWidth))
+	sb.WriteString("\n")
+	sb.WriteString(styles.Muted.Render(strings.Repeat("━", contentWidth)))
+	sb.WriteString("\n")
+
+	f := p.conflictFile
+	switch {
+	case p.conflictFiles == nil || f == nil:
+		sb.WriteString(styles.Muted.Render("Loading conflicts..."))
+	default:
+		sb.WriteString(p.renderConflictFileList(contentWidth))
+		sb.WriteString("\n\n")
+		if len(f.Regions) == 0 {
+			sb.WriteString(p.renderConflictWholeFile())
+		} else {
+			sb.WriteString(p.renderConflictRegion(contentWidth, paneHeight))
+		}
+	}
+	if p.conflictError != "" {
+		sb.WriteString("\n")
+		sb.WriteString(styles.StatusDeleted.Render(ansi.Truncate("✗ "+p.conflictError, contentWidth, "…")))
+	}
+
+	return p.wrapDiffContent(sb.String(), paneHeight)
+}
+
+func (p *Plugin) renderConflictHeader(width int) string {
+	title := styles.Body.Bold(true).Render(conflictOpTitle(p.conflictOp) + " conflicts")
+	if p.conflictOp == ConflictOpNone {
+		title = styles.Body.Bold(true).Render("Conflicts")
+	}
+	right := ""
+	if n := len(p.conflictFiles); n > 0 {
+		resolved := 0
+		for _, path := range p.conflictFiles {
+			if p.conflictResolved[path] {
+				resolved++
+			}
+		}
+		right = styles.Muted.Render(fmt.Sprintf("%d/%d file(s) resolved", resolved, n))
+	}
+	gap := width - ansi.StringWidth(title) - ansi.StringWidth(right)
+	if gap < 1 {
+		return title
+	}
+	return title + strings.Repeat(" ", gap) + right
+}
+
+// renderConflictFileList renders the conflicted files on one line.
+func (p *Plugin) renderConflictFileList(width int) string {
+	parts := make([]string, 0, len(p.conflictFiles))
+	for i, path := range p.conflictFiles {
+		text := "U " + path
+		style := styles.StatusModified
+		if p.conflictResolved[path] {
+			text = "✓ " + path
+			style = styles.StatusStaged
+		}
+		if i == p.conflictFileIdx {
+			style = styles.ListItemSelected
+		}
+		parts = append(parts, style.Render(" "+text+" "))
+	}
+	return ansi.Truncate(strings.Join(parts, " "), width, "…")
+}
+
+// renderConflictWholeFile renders a conflicted file without conflict
+// markers, which can only be resolved as a whole.
+func (p *Plugin) renderConflictWholeFile() string {
+	f := p.conflictFile
+	var text string
+	switch {
+	case p.conflictResolved[f.Path]:
+		text = "Resolved and staged."
+	case f.Binary:
+		text = "Binary file: take our or their version."
+	case f.OursDeleted:
+		text = "Deleted on our side and changed on theirs."
+	case f.TheirsDeleted:
+		text = "Changed on our side and deleted on theirs."
+	default:
+		text = "No conflict markers left in this file."
+	}
+	hints := "O take ours  T take theirs  s mark resolved  [/] file  c continue  esc close"
+	return styles.Body.Render(text) + "\n\n" + styles.Muted.Render(hints)
+}
+
+// renderConflictRegion renders the selected region's sides and its result.
+func (p *Plugin) renderConflictRegion(width, height int) string {
+	f := p.conflictFile
+	r := f.Regions[p.conflictRegion]
+
+	// Header, rule, file list, blank, region line, side headers, blank,
+	// result header, blank and hints
+	avail := height - 10
+	if p.conflictError != "" {
+		avail--
+	}
+	sideRows := max(1, avail/2)
+	resultRows := max(1, avail-sideRows)
+
+	var sb strings.Builder
+	status := styles.StatusModified.Render(r.Resolution.String())
+	if r.Resolution != ConflictUnresolved {
+		status = styles.StatusStaged.Render(r.Resolution.String())
+	}
+	sb.WriteString(fmt.Sprintf("%s %s %s",
+		styles.Body.Render(fmt.Sprintf("Conflict %d/%d", p.conflictRegion+1, len(f.Regions))),
+		styles.Muted.Render("·"),
+		status))
+	if left := f.Unresolved(); left > 0 {
+		sb.WriteString(styles.Muted.Render(fmt.Sprintf(" · %d left in file", left)))
+	}
+	sb.WriteString("\n")
+
+	// Sides: ours, base and theirs next to each other
+	colWidth := max(8, (width-6)/3)
+	picked := func(res ...ConflictResolution) bool {
+		for _, want := range res {
+			if r.Resolution == want {
+				return true
+			}
+		}
+		return false
+	}
+	base := r.Base
+	if !r.HasBase {
+		base = []string{"(base unavailable)"}
+	}
+	rows := max(len(r.Ours), len(base), len(r.Theirs))
+	p.conflictScroll = max(0, min(p.conflictScroll, rows-sideRows))
+	cols := []string{
+		renderConflictSide("Ours", r.OursLabel, r.Ours, picked(ConflictOurs, ConflictBoth), r.Resolution == ConflictUnresolved, colWidth, sideRows, p.conflictScroll),
+		renderConflictSide("Base", r.BaseLabel, base, false, false, colWidth, sideRows, p.conflictScroll),
+		renderConflictSide("Theirs", r.TheirsLabel, r.Theirs, picked(ConflictTheirs, ConflictBoth), r.Resolution == ConflictUnresolved, colWidth, sideRows, p.conflictScroll),
+	}
+	sep := strings.TrimSuffix(strings.Repeat(styles.Muted.Render(" │ ")+"\n", sideRows+1), "\n")
+	sb.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, cols[0], sep, cols[1], sep, cols[2]))
+	sb.WriteString("\n\n")
+
+	// Result with surrounding context
+	sb.WriteString(styles.Body.Bold(true).Render("Result"))
+	if p.conflictEditing {
+		sb.WriteString(styles.Muted.Render("  ctrl+s apply  esc cancel"))
+	}
+	sb.WriteString("\n")
+	sb.WriteString(p.renderConflictResult(r, width, resultRows))
+	sb.WriteString("\n\n")
+
+	hints := "o ours  t theirs  b both  e edit  u reset  n/N conflict  [/] file  s save  O/T whole file  c continue  esc close"
+	sb.WriteString(styles.Muted.Render(ansi.Truncate(hints, width, "…")))
+	return sb.String()
+}
+
+// renderConflictSide renders one side of a region as a column of rows+1
+// lines, its header first.
+func renderConflictSide(name, label string, lines []string, picked, unresolved bool, width, rows, scroll int) string {
+	header := name
+	if label != "" {
+		header += " · " + label
+	}
+	headerStyle := styles.Body.Bold(true)
+	lineStyle := styles.Body
+	switch {
+	case picked:
+		header = "✓ " + header
+		headerStyle = styles.StatusStaged.Bold(true)
+	case name == "Base" || !unresolved:
+		lineStyle = styles.Muted
+	}
+
+	out := []string{headerStyle.Render(padConflictLine(header, width))}
+	if len(lines) == 0 {
+		lines = []string{"(empty)"}
+		lineStyle = styles.Muted
+	}
+	for i := scroll; i < scroll+rows; i++ {
+		text := ""
+		if i < len(lines) {
+			text = lines[i]
+		}
+		if i == scroll+rows-1 && len(lines) > i+1 {
+			text = fmt.Sprintf("… %d more", len(lines)-i)
+		}
+		out = append(out, lineStyle.Render(padConflictLine(text, width)))
+	}
+	return strings.Join(out, "\n")
+}
+
+// padConflictLine fits a line of file content into a column.
+func padConflictLine(s string, width int) string {
+	s = strings.ReplaceAll(strings.TrimRight(s, "\r"), "\t", "    ")
+	s = ansi.Truncate(s, width, "…")
+	if pad := width - ansi.StringWidth(s); pad > 0 {
+		s += strings.Repeat(" ", pad)
+	}
+	return s
+}
+
+// renderConflictResult renders the region's result between its context
+// lines, or the editor while editing.
+func (p *Plugin) renderConflictResult(r *ConflictRegion, width, rows int) string {
+	before, after := p.conflictFile.Context(p.conflictRegion, 2)
+	if rows < len(before)+len(after)+1 {
+		before, after = nil, nil
+	}
+	var lines []string
+	for _, line := range before {
+		lines = append(lines, styles.Muted.Render("  "+padConflictLine(line, width-2)))
+	}
+
+	bodyRows := rows - len(before) - len(after)
+	switch {
+	case p.conflictEditing:
+		p.conflictEditor.SetWidth(width)
+		p.conflictEditor.SetHeight(max(1, bodyRows))
+		lines = append(lines, p.conflictEditor.View())
+	case r.Resolution == ConflictUnresolved:
+		lines = append(lines, styles.StatusModified.Render("▌ unresolved: o ours, t theirs, b both, e edit"))
+	case len(r.Result()) == 0:
+		lines = append(lines, styles.Muted.Render("▌ (region removed)"))
+	default:
+		result := r.Result()
+		for i, line := range result {
+			if i == bodyRows-1 && len(result) > bodyRows {
+				lines = append(lines, styles.Muted.Render(fmt.Sprintf("▌ … %d more", len(result)-i)))
+				break
+			}
+			lines = append(lines, styles.StatusStaged.Render("▌ ")+styles.Body.Render(padConflictLine(line, width-2)))
+		}
+	}
+
+	for _, line := range after {
+		lines = append(lines, styles.Muted.Render("  "+padConflictLine(line, width-2)))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -28,6 +28,7 @@ type DiffLine struct {
 	NewLineNo int // 0 means not applicable
 	Content   string
 	WordDiff  []WordSegment
+	NoNewline bool // Followed by "\ No newline at end of file"
 }
 
 // Hunk represents a diff hunk.
@@ -46,6 +47,10 @@ type ParsedDiff struct {
 	NewFile string
 	Binary  bool
 	Hunks   []Hunk
+
+	// Selection is the line cursor used for hunk and line staging; renderers
+	// mark the selected lines when it is set.
+	Selection *DiffSelection
 }
 
 // FileDiffInfo holds a parsed diff with rendering position info.
@@ -169,49 +174,23 @@ func ParseUnifiedDiff(diff string) (*ParsedDiff, error) {
 	var currentHunk *Hunk
 	oldLineNo := 0
 	newLineNo := 0
+	oldLeft, newLeft := 0, 0 // Lines the current hunk header still promises
 
 	for _, line := range lines {
-		switch {
-		case strings.HasPrefix(line, "Binary files"):
-			parsed.Binary = true
-			return parsed, nil
-
-		case strings.HasPrefix(line, "--- "):
-			parsed.OldFile = strings.TrimPrefix(line, "--- ")
-			parsed.OldFile = strings.TrimPrefix(parsed.OldFile, "a/")
-
-		case strings.HasPrefix(line, "+++ "):
-			parsed.NewFile = strings.TrimPrefix(line, "+++ ")
-			parsed.NewFile = strings.TrimPrefix(parsed.NewFile, "b/")
-
-		case strings.HasPrefix(line, "@@"):
-			match := hunkHeaderRegex.FindStringSubmatch(line)
-			if match != nil {
-				oldStart, _ := strconv.Atoi(match[1])
-				oldCount := 1
-				if match[2] != "" {
-					oldCount, _ = strconv.Atoi(match[2])
-				}
-				newStart, _ := strconv.Atoi(match[3])
-				newCount := 1
-				if match[4] != "" {
-					newCount, _ = strconv.Atoi(match[4])
-				}
+		// Inside a hunk every line is content, even one that looks like a
+		// file header ("--- x" is a removed "-- x"). Lines past the counts in
+		// the hunk header, such as the diff's trailing newline, are ignored.
+		inHunk := currentHunk != nil && (oldLeft > 0 || newLeft > 0 || strings.HasPrefix(line, "\\"))
 
-				currentHunk = &Hunk{
-					OldStart: oldStart,
-					OldCount: oldCount,
-					NewStart: newStart,
-					NewCount: newCount,
-					Header:   match[5],
+		switch {
+		case inHunk && !strings.HasPrefix(line, "@@"):
+			if strings.HasPrefix(line, "\\") {
+				// "\ No newline at end of file" applies to the line before it
+				if n := len(currentHunk.Lines); n > 0 {
+					currentHunk.Lines[n-1].NoNewline = true
 				}
-				parsed.Hunks = append(parsed.Hunks, *currentHunk)
-				currentHunk = &parsed.Hunks[len(parsed.Hunks)-1]
-				oldLineNo = oldStart
-				newLineNo = newStart
+				continue
 			}
-
-		case currentHunk != nil:
 			if len(line) == 0 {
 				// Empty context line
 				diffLine := DiffLine{
@@ -223,6 +202,8 @@ func ParseUnifiedDiff(diff string) (*ParsedDiff, error) {
 				currentHunk.Lines = append(currentHunk.Lines, diffLine)
 				oldLineNo++
 				newLineNo++
+				oldLeft--
+				newLeft--
 				continue
 			}
 
@@ -242,6 +223,7 @@ func ParseUnifiedDiff(diff string) (*ParsedDiff, error) {
 				}
 				currentHunk.Lines = append(currentHunk.Lines, diffLine)
 				newLineNo++
+				newLeft--
 
 			case '-':
 				diffLine := DiffLine{
@@ -252,6 +234,7 @@ func ParseUnifiedDiff(diff string) (*ParsedDiff, error) {
 				}
 				currentHunk.Lines = append(currentHunk.Lines, diffLine)
 				oldLineNo++
+				oldLeft--
 
 			case ' ':
 				diffLine := DiffLine{
@@ -263,9 +246,8 @@ func ParseUnifiedDiff(diff string) (*ParsedDiff, error) {
 				currentHunk.Lines = append(currentHunk.Lines, diffLine)
 				oldLineNo++
 				newLineNo++
-
-			case '\\':
-				// "\ No newline at end of file" - skip
+				oldLeft--
+				newLeft--
 
 			default:
 				// Treat as context if unrecognized
@@ -278,6 +260,48 @@ func ParseUnifiedDiff(diff string) (*ParsedDiff, error) {
 				currentHunk.Lines = append(currentHunk.Lines, diffLine)
 				oldLineNo++
 				newLineNo++
+				oldLeft--
+				newLeft--
+			}
+
+		case strings.HasPrefix(line, "Binary files"):
+			parsed.Binary = true
+			return parsed, nil
+
+		case strings.HasPrefix(line, "--- "):
+			parsed.OldFile = strings.TrimPrefix(line, "--- ")
+			parsed.OldFile = strings.TrimPrefix(parsed.OldFile, "a/")
+
+		case strings.HasPrefix(line, "+++ "):
+			parsed.NewFile = strings.TrimPrefix(line, "+++ ")
+			parsed.NewFile = strings.TrimPrefix(parsed.NewFile, "b/")
+
+		case strings.HasPrefix(line, "@@"):
+			match := hunkHeaderRegex.FindStringSubmatch(line)
+			if match != nil {
+				oldStart, _ := strconv.Atoi(match[1])
+				oldCount := 1
+				if match[2] != "" {
+					oldCount, _ = strconv.Atoi(match[2])
+				}
+				newStart, _ := strconv.Atoi(match[3])
+				newCount := 1
+				if match[4] != "" {
+					newCount, _ = strconv.Atoi(match[4])
+				}
+
+				currentHunk = &Hunk{
+					OldStart: oldStart,
+					OldCount: oldCount,
+					NewStart: newStart,
+					NewCount: newCount,
+					Header:   match[5],
+				}
+				parsed.Hunks = append(parsed.Hunks, *currentHunk)
+				currentHunk = &parsed.Hunks[len(parsed.Hunks)-1]
+				oldLineNo = oldStart
+				newLineNo = newStart
+				oldLeft, newLeft = oldCount, newCount
 			}
 		}
 	}
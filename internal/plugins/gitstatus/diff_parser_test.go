@@ -227,3 +227,38 @@ func TestParsedDiff_MaxLineNumber(t *testing.T) {
 		t.Errorf("MaxLineNumber() = %d, want 102", max)
 	}
 }
+
+func TestParseUnifiedDiff_HunkCountsAndNoNewline(t *testing.T) {
+	diff := `--- a/file.sql
++++ b/file.sql
+@@ -1,2 +1,2 @@
+ select 1;
+--- old comment
+\ No newline at end of file
++++ new comment
+\ No newline at end of file
+`
+
+	parsed, err := ParseUnifiedDiff(diff)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if parsed.OldFile != "file.sql" || parsed.NewFile != "file.sql" {
+		t.Errorf("files = %q, %q; removed and added lines were read as headers", parsed.OldFile, parsed.NewFile)
+	}
+
+	// The trailing newline of the diff is not an extra context line
+	lines := parsed.Hunks[0].Lines
+	if len(lines) != 3 {
+		t.Fatalf("len(Lines) = %d, want 3", len(lines))
+	}
+	if lines[1].Type != LineRemove || lines[1].Content != "-- old comment" || !lines[1].NoNewline {
+		t.Errorf("removed line = %+v", lines[1])
+	}
+	if lines[2].Type != LineAdd || lines[2].Content != "++ new comment" || !lines[2].NoNewline {
+		t.Errorf("added line = %+v", lines[2])
+	}
+	if lines[0].NoNewline {
+		t.Error("context line should not be marked NoNewline")
+	}
+}
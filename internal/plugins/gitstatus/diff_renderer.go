@@ -40,8 +40,25 @@ var (
 			Foreground(styles.TextPrimary).
 			Background(styles.BgTertiary).
 			Bold(true)
+
+	selectionGutterStyle = lipgloss.NewStyle().
+				Foreground(styles.Primary).
+				Bold(true)
 )
 
+// selectionGutter returns the separator between line numbers and content:
+// an arrow on the selection cursor, a bar on other selected lines.
+func selectionGutter(cursor, selected bool) string {
+	switch {
+	case cursor:
+		return selectionGutterStyle.Render("▶")
+	case selected:
+		return selectionGutterStyle.Render("┃")
+	default:
+		return "│"
+	}
+}
+
 // RenderLineDiff renders a parsed diff in unified line-by-line format with line numbers.
 // horizontalOffset scrolls the content horizontally (0 = no scroll).
 // highlighter is optional - if nil, no syntax highlighting is applied.
@@ -73,7 +90,7 @@ func RenderLineDiff(diff *ParsedDiff, width, startLine, maxLines, horizontalOffs
 	contentWidth := width - (lineNoWidth*2 + 4) // Two line numbers + separators
 	isFirstHunk := true
 
-	for _, hunk := range diff.Hunks {
+	for hi, hunk := range diff.Hunks {
 		// Skip until we reach the start line
 		if lineNum < startLine {
 			lineNum++
@@ -110,7 +127,7 @@ func RenderLineDiff(diff *ParsedDiff, width, startLine, maxLines, horizontalOffs
 			break
 		}
 
-		for _, line := range hunk.Lines {
+		for li, line := range hunk.Lines {
 			lineNum++
 			if lineNum <= startLine {
 				continue
@@ -130,9 +147,10 @@ func RenderLineDiff(diff *ParsedDiff, width, startLine, maxLines, horizontalOffs
 				newNo = fmt.Sprintf("%d", line.NewLineNo)
 			}
 
-			lineNos := fmt.Sprintf("%s %s │ ",
+			lineNos := fmt.Sprintf("%s %s %s ",
 				lineNoStyle.Render(oldNo),
-				lineNoStyle.Render(newNo))
+				lineNoStyle.Render(newNo),
+				selectionGutter(diff.Selection.mark(diff, hi, li)))
 
 			// Render content with appropriate style
 			var content string
@@ -199,7 +217,7 @@ func RenderSideBySide(diff *ParsedDiff, width, startLine, maxLines, horizontalOf
 		Align(lipgloss.Right)
 
 	isFirstHunk := true
-	for _, hunk := range diff.Hunks {
+	for hi, hunk := range diff.Hunks {
 		if rendered >= maxLines {
 			break
 		}
@@ -232,6 +250,14 @@ func RenderSideBySide(diff *ParsedDiff, width, startLine, maxLines, horizontalOf
 				continue
 			}
 
+			// Mark the selection on the center separator
+			leftCursor, leftSelected := diff.Selection.mark(diff, hi, pair.leftIdx)
+			rightCursor, rightSelected := diff.Selection.mark(diff, hi, pair.rightIdx)
+			sep := sideBySideBorder.Render(" │ ")
+			if leftSelected || rightSelected {
+				sep = " " + selectionGutter(leftCursor || rightCursor, true) + " "
+			}
+
 			// Left side (old)
 			leftLineNo := " "
 			leftRendered := ""
@@ -282,7 +308,6 @@ func RenderSideBySide(diff *ParsedDiff, width, startLine, maxLines, horizontalOf
 					maxH = len(rightLines)
 				}
 				lineNoPad := strings.Repeat(" ", lineNoWidth)
-				sep := sideBySideBorder.Render(" │ ")
 				for vi := 0; vi < maxH; vi++ {
 					if rendered >= maxLines {
 						break
@@ -322,7 +347,7 @@ func RenderSideBySide(diff *ParsedDiff, width, startLine, maxLines, horizontalOf
 					rightRendered)
 
 				sb.WriteString(leftPanel)
-				sb.WriteString(sideBySideBorder.Render(" │ "))
+				sb.WriteString(sep)
 				sb.WriteString(rightPanel)
 				sb.WriteString("\n")
 				rendered++
@@ -336,8 +361,10 @@ func RenderSideBySide(diff *ParsedDiff, width, startLine, maxLines, horizontalOf
 
 // linePair represents a pair of lines for side-by-side view.
 type linePair struct {
-	left  *DiffLine
-	right *DiffLine
+	left     *DiffLine
+	right    *DiffLine
+	leftIdx  int // Index of left in the hunk's lines, -1 if nil
+	rightIdx int // Index of right in the hunk's lines, -1 if nil
 }
 
 // groupLinesForSideBySide groups diff lines into pairs for side-by-side display.
@@ -351,7 +378,7 @@ func groupLinesForSideBySide(lines []DiffLine) []linePair {
 		switch line.Type {
 		case LineContext:
 			// Context lines appear on both sides
-			pairs = append(pairs, linePair{left: line, right: line})
+			pairs = append(pairs, linePair{left: line, right: line, leftIdx: i, rightIdx: i})
 			i++
 
 		case LineRemove:
@@ -377,19 +404,19 @@ func groupLinesForSideBySide(lines []DiffLine) []linePair {
 			}
 
 			for j := 0; j < maxPairs; j++ {
-				var left, right *DiffLine
+				pair := linePair{leftIdx: -1, rightIdx: -1}
 				if j < removeCount {
-					left = &lines[removeStart+j]
+					pair.left, pair.leftIdx = &lines[removeStart+j], removeStart+j
 				}
 				if j < addCount {
-					right = &lines[addStart+j]
+					pair.right, pair.rightIdx = &lines[addStart+j], addStart+j
 				}
-				pairs = append(pairs, linePair{left: left, right: right})
+				pairs = append(pairs, pair)
 			}
 
 		case LineAdd:
 			// Orphan add (shouldn't happen if grouping is correct)
-			pairs = append(pairs, linePair{left: nil, right: line})
+			pairs = append(pairs, linePair{left: nil, right: line, leftIdx: -1, rightIdx: i})
 			i++
 		}
 	}
@@ -442,3 +442,40 @@ func TestRenderLineDiff_WrapVeryLongLine(t *testing.T) {
 		t.Errorf("expected at least 10 wrapped lines for 1500 char content, got %d", len(lines))
 	}
 }
+
+func TestRenderDiff_SelectionGutter(t *testing.T) {
+	diff := &ParsedDiff{
+		Hunks: []Hunk{
+			{
+				OldStart: 1,
+				OldCount: 2,
+				NewStart: 1,
+				NewCount: 3,
+				Lines: []DiffLine{
+					{Type: LineContext, OldLineNo: 1, NewLineNo: 1, Content: "context"},
+					{Type: LineRemove, OldLineNo: 2, Content: "old"},
+					{Type: LineAdd, NewLineNo: 2, Content: "new"},
+					{Type: LineAdd, NewLineNo: 3, Content: "extra"},
+				},
+			},
+		},
+		Selection: &DiffSelection{Line: 2, Anchor: 1},
+	}
+
+	for name, result := range map[string]string{
+		"unified":      RenderLineDiff(diff, 80, 0, 20, 0, nil, false),
+		"side-by-side": RenderSideBySide(diff, 80, 0, 20, 0, nil, false),
+	} {
+		if strings.Count(result, "▶") != 1 {
+			t.Errorf("%s: expected one cursor mark:\n%s", name, result)
+		}
+	}
+	if got := strings.Count(RenderLineDiff(diff, 80, 0, 20, 0, nil, false), "┃"); got != 1 {
+		t.Errorf("unified: %d selected marks, want 1 besides the cursor", got)
+	}
+
+	diff.Selection = nil
+	if result := RenderLineDiff(diff, 80, 0, 20, 0, nil, false); strings.ContainsAny(result, "▶┃") {
+		t.Error("no marks expected without a selection")
+	}
+}
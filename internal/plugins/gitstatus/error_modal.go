@@ -45,11 +45,18 @@ func (p *Plugin) ensureErrorModal() {
 	}
 	p.errorModalWidth = modalW
 	p.errorModalHeight = p.height
-	// Build button list — offer Pull when push was rejected due to remote ahead
+	// Build button list — offer Pull when push was rejected due to remote ahead,
+	// Resolve and Abort when an operation stopped on conflicts
 	var btns []modal.ButtonDef
 	if p.errorOfferPull {
 		btns = append(btns, modal.Btn(" Pull ", "pull"))
 	}
+	if p.errorOfferResolve {
+		btns = append(btns,
+			modal.Btn(" Resolve ", errorResolveID, modal.BtnPrimary()),
+			modal.Btn(" Abort ", errorAbortID, modal.BtnDanger()),
+		)
+	}
 	btns = append(btns, modal.Btn(" Dismiss ", "dismiss"))
 
 	p.errorModal = modal.New(p.errorTitle,
@@ -86,6 +93,16 @@ func (p *Plugin) updateErrorModal(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 		return p.errorModalToPullMenu()
 	}
 
+	// Resolve or abort shortcuts for operations stopped on conflicts
+	if p.errorOfferResolve {
+		switch msg.String() {
+		case "r":
+			return p.resolveFromErrorModal()
+		case "a":
+			return p.abortFromErrorModal()
+		}
+	}
+
 	// Intercept yank before delegating to modal key handler
 	if msg.String() == "y" {
 		return p, p.yankErrorToClipboard()
@@ -95,6 +112,10 @@ func (p *Plugin) updateErrorModal(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 	switch action {
 	case "pull":
 		return p.errorModalToPullMenu()
+	case errorResolveID:
+		return p.resolveFromErrorModal()
+	case errorAbortID:
+		return p.abortFromErrorModal()
 	case "dismiss", "cancel":
 		return p.dismissErrorModal()
 	}
@@ -111,6 +132,10 @@ func (p *Plugin) handleErrorModalMouse(m tea.MouseMsg) (plugin.Plugin, tea.Cmd)
 	switch action {
 	case "pull":
 		return p.errorModalToPullMenu()
+	case errorResolveID:
+		return p.resolveFromErrorModal()
+	case errorAbortID:
+		return p.abortFromErrorModal()
 	case "dismiss", "cancel":
 		return p.dismissErrorModal()
 	}
@@ -126,6 +151,7 @@ func (p *Plugin) dismissErrorModal() (plugin.Plugin, tea.Cmd) {
 	p.errorModalWidth = 0
 	p.errorModalHeight = 0
 	p.errorOfferPull = false
+	p.errorOfferResolve = false
 	p.pushError = ""
 	p.fetchError = ""
 	p.pullError = ""
@@ -144,6 +170,7 @@ func (p *Plugin) errorModalToPullMenu() (plugin.Plugin, tea.Cmd) {
 	p.fetchError = ""
 	p.pullError = ""
 	p.errorOfferPull = false
+	p.errorOfferResolve = false
 	// Open pull menu
 	p.pullMenuReturnMode = ViewModeStatus
 	p.viewMode = ViewModePullMenu
@@ -24,10 +24,15 @@ func (p *Plugin) doCommit(message string) tea.Cmd {
 func (p *Plugin) doAmend(message string) tea.Cmd {
 	workDir := p.repoRoot
 	return func() tea.Msg {
+		oldHead, _ := revParse(workDir, "HEAD")
 		hash, err := ExecuteAmend(workDir, message)
 		if err != nil {
 			return CommitErrorMsg{Err: err}
 		}
+		if newHead, err := revParse(workDir, "HEAD"); err == nil && oldHead != "" {
+			// Best effort: the amend itself succeeded
+			_ = RecordJournal(workDir, JournalEntry{Op: JournalAmend, OldHead: oldHead, NewHead: newHead})
+		}
 		subject := strings.Split(message, "\n")[0]
 		return CommitSuccessMsg{Hash: hash, Subject: subject}
 	}
@@ -191,7 +196,11 @@ func (p *Plugin) canPull() bool {
 func (p *Plugin) doDiscard(entry *FileEntry) tea.Cmd {
 	workDir := p.repoRoot
 	return func() tea.Msg {
-		var err error
+		// Back up the file first so the discard can be undone
+		backup, err := BackupFile(workDir, entry.Path, entry.Staged)
+		if err != nil {
+			return ErrorMsg{Err: err}
+		}
 		if entry.Status == StatusUntracked {
 			// Remove untracked file
 			err = DiscardUntracked(workDir, entry.Path)
@@ -205,6 +214,7 @@ func (p *Plugin) doDiscard(entry *FileEntry) tea.Cmd {
 		if err != nil {
 			return ErrorMsg{Err: err}
 		}
-		return RefreshDoneMsg{}
+		_ = RecordJournal(workDir, backup)
+		return RefreshDoneMsg{Rebase: GetRebaseState(workDir)}
 	}
 }
@@ -0,0 +1,324 @@
+package gitstatus
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/app"
+	appmsg "github.com/marcus/sidecar/internal/msg"
+)
+
+// patchTarget is the file entry a diff view shows.
+type patchTarget struct {
+	Path   string
+	Staged bool
+	Status FileStatus
+}
+
+// patchable reports whether hunk and line operations apply to the target's
+// diff. Untracked files have no diff against the index.
+func (t *patchTarget) patchable() bool {
+	return t != nil && t.Status != StatusUntracked
+}
+
+// patchTargetFor returns the target for a file entry, or nil for folders.
+func patchTargetFor(entry *FileEntry) *patchTarget {
+	if entry == nil || entry.IsFolder {
+		return nil
+	}
+	return &patchTarget{Path: entry.Path, Staged: entry.Staged, Status: entry.Status}
+}
+
+// discardedPatch is the last hunk or line discard, kept for undo.
+type discardedPatch struct {
+	Path  string
+	Patch string
+}
+
+// PatchAppliedMsg is sent when a hunk or line operation finishes.
+type PatchAppliedMsg struct {
+	Op    PatchOp
+	Path  string
+	Patch string // Applied patch; for discards, applying it forward undoes them
+	Lines int    // Selected change lines, 0 for a whole hunk
+	Undo  bool   // True when a discard was undone
+	Err   error
+}
+
+// initSelection sets the line cursor of a newly loaded diff, carried over from
+// the diff it replaces when there was one.
+func initSelection(diff, prev *ParsedDiff, target *patchTarget) {
+	if diff == nil || !target.patchable() {
+		return
+	}
+	if prev != nil && prev.Selection != nil {
+		diff.Selection = prev.Selection.clamp(diff)
+	} else {
+		diff.Selection = NewDiffSelection(diff)
+	}
+}
+
+// openFileDiff opens the full-screen diff for a file or folder entry.
+func (p *Plugin) openFileDiff(entry *FileEntry) tea.Cmd {
+	p.diffReturnMode = p.viewMode
+	p.viewMode = ViewModeDiff
+	p.diffFile = entry.Path
+	p.diffTarget = patchTargetFor(entry)
+	p.diffCommit = ""
+	p.diffCommitSubject = ""
+	p.diffCommitShortHash = ""
+	p.diffScroll = 0
+	p.diffLoaded = false
+	p.parsedDiff = nil
+	if entry.IsFolder {
+		return p.loadFullFolderDiff(entry)
+	}
+	return p.loadDiff(entry.Path, entry.Staged, entry.Status)
+}
+
+// selectionView is a diff view with a line cursor: the inline diff pane or the
+// full-screen diff.
+type selectionView struct {
+	diff   *ParsedDiff
+	target *patchTarget
+	mode   DiffViewMode
+	scroll *int
+}
+
+// inlineSelectionView returns the inline diff pane's selection view, or false
+// if its diff has no line cursor.
+func (p *Plugin) inlineSelectionView() (selectionView, bool) {
+	v := selectionView{diff: p.diffPaneParsedDiff, target: p.diffPaneTarget, mode: p.diffPaneViewMode, scroll: &p.diffPaneScroll}
+	return v, v.diff != nil && v.diff.Selection != nil && v.target.patchable()
+}
+
+// fullSelectionView returns the full-screen diff's selection view, or false if
+// its diff has no line cursor.
+func (p *Plugin) fullSelectionView() (selectionView, bool) {
+	v := selectionView{diff: p.parsedDiff, target: p.diffTarget, mode: p.diffViewMode, scroll: &p.diffScroll}
+	return v, p.diffCommit == "" && v.diff != nil && v.diff.Selection != nil && v.target.patchable()
+}
+
+// handleSelectionKey handles line cursor and hunk operation keys in a diff
+// view. Returns false for keys it does not handle.
+func (p *Plugin) handleSelectionKey(v selectionView, key string) (bool, tea.Cmd) {
+	sel := v.diff.Selection
+	switch key {
+	case "j", "down":
+		sel.move(v.diff, 1)
+	case "k", "up":
+		sel.move(v.diff, -1)
+	case "n":
+		sel.moveHunk(v.diff, 1)
+	case "N":
+		sel.moveHunk(v.diff, -1)
+	case "g":
+		*sel = *NewDiffSelection(v.diff)
+		*v.scroll = 0
+		return true, nil
+	case "G":
+		last := len(v.diff.Hunks) - 1
+		lines := v.diff.Hunks[last].Lines
+		sel.Hunk, sel.Line, sel.Anchor = last, nearestChange(lines, len(lines)-1), -1
+	case "V":
+		if sel.RangeActive() {
+			sel.Anchor = -1
+		} else {
+			sel.Anchor = sel.Line
+		}
+		return true, nil
+	case "esc":
+		if !sel.RangeActive() {
+			return false, nil
+		}
+		sel.Anchor = -1
+		return true, nil
+	case "s":
+		return true, p.applySelection(v, PatchStage)
+	case "u":
+		return true, p.applySelection(v, PatchUnstage)
+	case "D":
+		return true, p.applySelection(v, PatchDiscard)
+	case "ctrl+z":
+		return true, p.undoDiscard()
+	default:
+		return false, nil
+	}
+	p.scrollToSelection(v)
+	return true, nil
+}
+
+// scrollToSelection scrolls a diff view so its cursor line is visible.
+func (p *Plugin) scrollToSelection(v selectionView) {
+	row := v.diff.Selection.row(v.diff, v.mode)
+	visible := p.height - 6 // Panel borders and diff header
+	if visible < 1 {
+		visible = 1
+	}
+	if row <= *v.scroll {
+		// One line above, so the hunk header shows for the first line
+		*v.scroll = row - 1
+		if *v.scroll < 0 {
+			*v.scroll = 0
+		}
+		return
+	}
+	// Hunks after the first are preceded by a blank line
+	headers := hunkHeaderRows(v.diff, v.mode)
+	for *v.scroll < row {
+		rows := row - *v.scroll + 1
+		for _, h := range headers {
+			if h > *v.scroll && h <= row {
+				rows++
+			}
+		}
+		if rows <= visible {
+			break
+		}
+		*v.scroll++
+	}
+}
+
+// hunkHeaderRows returns the rows of the hunk headers after the first, counted
+// like DiffSelection.row.
+func hunkHeaderRows(diff *ParsedDiff, mode DiffViewMode) []int {
+	var rows []int
+	row := 0
+	for h, hunk := range diff.Hunks {
+		if h > 0 {
+			rows = append(rows, row)
+		}
+		row++
+		if mode == DiffViewSideBySide {
+			row += len(groupLinesForSideBySide(hunk.Lines))
+		} else {
+			row += len(hunk.Lines)
+		}
+	}
+	return rows
+}
+
+// applySelection stages, unstages or discards the selected lines, or the
+// cursor's hunk when no range is selected.
+func (p *Plugin) applySelection(v selectionView, op PatchOp) tea.Cmd {
+	target := *v.target
+	switch {
+	case op == PatchStage && target.Staged:
+		return appmsg.ShowToast("Already staged", 2*time.Second)
+	case op == PatchUnstage && !target.Staged:
+		return appmsg.ShowToast("Not staged", 2*time.Second)
+	case op == PatchDiscard && target.Staged:
+		return appmsg.ShowToast("Unstage changes before discarding them", 2*time.Second)
+	}
+
+	sel := *v.diff.Selection
+	hunk := v.diff.Hunks[sel.Hunk]
+	start, end := sel.Range(&hunk)
+	lines := 0
+	if sel.RangeActive() {
+		for _, line := range hunk.Lines[start : end+1] {
+			if line.Type != LineContext {
+				lines++
+			}
+		}
+	}
+	v.diff.Selection.Anchor = -1
+
+	// Keep the sidebar cursor on this entry once the file list refreshes
+	p.restoreCursor = &target
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		var backup JournalEntry
+		if op == PatchDiscard {
+			var err error
+			if backup, err = BackupFile(workDir, target.Path, false); err != nil {
+				return PatchAppliedMsg{Op: op, Path: target.Path, Err: err}
+			}
+		}
+		patch, err := ApplySelection(workDir, target.Path, target.Staged, sel.Hunk, hunk, start, end, op)
+		if err == nil && op == PatchDiscard {
+			_ = RecordJournal(workDir, backup)
+		}
+		return PatchAppliedMsg{Op: op, Path: target.Path, Patch: patch, Lines: lines, Err: err}
+	}
+}
+
+// undoDiscard restores the lines of the last hunk or line discard.
+func (p *Plugin) undoDiscard() tea.Cmd {
+	last := p.lastDiscard
+	if last == nil {
+		return appmsg.ShowToast("Nothing to undo", 2*time.Second)
+	}
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		err := ApplyPatch(workDir, last.Patch, false, false)
+		if err == nil {
+			_ = forgetJournalDiscard(workDir, last.Path)
+		}
+		return PatchAppliedMsg{Op: PatchDiscard, Path: last.Path, Patch: last.Patch, Undo: true, Err: err}
+	}
+}
+
+// handlePatchApplied reports a finished hunk or line operation and reloads
+// the file list and diffs.
+func (p *Plugin) handlePatchApplied(msg PatchAppliedMsg) tea.Cmd {
+	if msg.Err != nil {
+		p.restoreCursor = nil
+		action := msg.Op.Verb()
+		if msg.Undo {
+			action = "Undo"
+		}
+		toast := func() tea.Msg {
+			return app.ToastMsg{Message: action + " failed: " + msg.Err.Error(), Duration: 3 * time.Second, IsError: true}
+		}
+		if errors.Is(msg.Err, ErrDiffChanged) {
+			return tea.Batch(toast, p.refresh())
+		}
+		return toast
+	}
+
+	var text string
+	switch {
+	case msg.Undo:
+		p.lastDiscard = nil
+		text = "Restored discarded changes in " + msg.Path
+	case msg.Lines > 0:
+		text = fmt.Sprintf("%s %d line(s)", msg.Op, msg.Lines)
+	default:
+		text = msg.Op.String() + " hunk"
+	}
+	if msg.Op == PatchDiscard && !msg.Undo {
+		p.lastDiscard = &discardedPatch{Path: msg.Path, Patch: msg.Patch}
+		text += " (ctrl+z to undo)"
+	}
+
+	cmds := []tea.Cmd{appmsg.ShowToast(text, 2*time.Second), p.refresh()}
+	if p.viewMode == ViewModeDiff && p.diffTarget != nil && p.diffCommit == "" {
+		cmds = append(cmds, p.loadDiff(p.diffTarget.Path, p.diffTarget.Staged, p.diffTarget.Status))
+	}
+	return tea.Batch(cmds...)
+}
+
+// moveCursorToTarget moves the sidebar cursor to the entry for target, or to
+// the other side of the same file once all of its changes moved there.
+func (p *Plugin) moveCursorToTarget(target *patchTarget) {
+	entries := p.tree.AllEntries()
+	fallback := -1
+	for i, entry := range entries {
+		if entry.Path != target.Path || entry.IsFolder {
+			continue
+		}
+		if entry.Staged == target.Staged {
+			p.cursor = i
+			p.ensureCursorVisible()
+			return
+		}
+		fallback = i
+	}
+	if fallback >= 0 {
+		p.cursor = fallback
+		p.ensureCursorVisible()
+	}
+}
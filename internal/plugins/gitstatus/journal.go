@@ -0,0 +1,386 @@
+package gitstatus
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// JournalOp is a sidecar git action the operation journal can reverse.
+type JournalOp string
+
+const (
+	JournalAmend        JournalOp = "amend"
+	JournalDiscard      JournalOp = "discard"
+	JournalDeleteBranch JournalOp = "delete-branch"
+)
+
+const (
+	journalFile       = "sidecar-journal" // In the repository's common git dir
+	journalMaxEntries = 100
+)
+
+// ErrJournalEmpty is returned when there is no sidecar action to undo.
+var ErrJournalEmpty = errors.New("no sidecar action to undo")
+
+// JournalEntry records a sidecar git action and what is needed to reverse
+// it. Discards are backed up as blobs in the object database; git prunes
+// unreachable blobs after gc.pruneExpire (two weeks by default).
+type JournalEntry struct {
+	Op       JournalOp `json:"op"`
+	Time     time.Time `json:"time"`
+	Worktree string    `json:"worktree,omitempty"` // Top level of the worktree; empty for branch deletions
+
+	// Amend
+	OldHead string `json:"old_head,omitempty"` // HEAD before the amend
+	NewHead string `json:"new_head,omitempty"` // The amended commit
+
+	// Discard
+	Path      string `json:"path,omitempty"`
+	Blob      string `json:"blob,omitempty"` // Working tree content; empty when the file did not exist
+	FileMode  uint32 `json:"file_mode,omitempty"`
+	Symlink   bool   `json:"symlink,omitempty"`    // Blob holds the link target
+	IndexBlob string `json:"index_blob,omitempty"` // Staged content the discard unstaged
+	IndexMode string `json:"index_mode,omitempty"`
+
+	// Branch deletion
+	Branch string `json:"branch,omitempty"`
+	Hash   string `json:"hash,omitempty"` // Tip of the deleted branch
+}
+
+// Description returns a short summary of the action, e.g. "amend of 1a2b3c4".
+func (e JournalEntry) Description() string {
+	switch e.Op {
+	case JournalAmend:
+		return "amend of " + shortHash(e.OldHead)
+	case JournalDiscard:
+		return "discard of " + e.Path
+	case JournalDeleteBranch:
+		return "deletion of branch " + e.Branch
+	}
+	return string(e.Op)
+}
+
+// journalPath returns the journal file, shared by all worktrees of the
+// repository so a branch deleted from one can be restored from another.
+func journalPath(workDir string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--git-common-dir")
+	cmd.Dir = workDir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("find git dir: %w", err)
+	}
+	dir := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(workDir, dir)
+	}
+	return filepath.Join(dir, journalFile), nil
+}
+
+// worktreeRoot returns the top level of the worktree containing workDir.
+func worktreeRoot(workDir string) string {
+	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
+	cmd.Dir = workDir
+	out, err := cmd.Output()
+	if err != nil {
+		return workDir
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// revParse resolves rev to a full commit or object hash.
+func revParse(workDir, rev string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--verify", "-q", rev)
+	cmd.Dir = workDir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("resolve %s: %w", rev, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// loadJournal reads the journal, oldest entry first. A missing journal is
+// empty; unreadable lines are skipped.
+func loadJournal(path string) ([]JournalEntry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []JournalEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e JournalEntry
+		if json.Unmarshal(scanner.Bytes(), &e) == nil {
+			entries = append(entries, e)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// writeJournal replaces the journal with entries, keeping the newest
+// journalMaxEntries.
+func writeJournal(path string, entries []JournalEntry) error {
+	if len(entries) > journalMaxEntries {
+		entries = entries[len(entries)-journalMaxEntries:]
+	}
+	var sb strings.Builder
+	for _, e := range entries {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		sb.Write(line)
+		sb.WriteString("\n")
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(sb.String()), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// RecordJournal appends e to the repository's operation journal. Amends and
+// discards are tied to the worktree of workDir.
+func RecordJournal(workDir string, e JournalEntry) error {
+	path, err := journalPath(workDir)
+	if err != nil {
+		return err
+	}
+	entries, err := loadJournal(path)
+	if err != nil {
+		return err
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	if e.Op != JournalDeleteBranch && e.Worktree == "" {
+		e.Worktree = worktreeRoot(workDir)
+	}
+	return writeJournal(path, append(entries, e))
+}
+
+// lastJournalIndex returns the index of the newest entry that can be undone
+// from the worktree root, or -1.
+func lastJournalIndex(entries []JournalEntry, root string) int {
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Worktree == "" || entries[i].Worktree == root {
+			return i
+		}
+	}
+	return -1
+}
+
+// LastJournalEntry returns the newest sidecar action that can be undone from
+// workDir, or nil when there is none.
+func LastJournalEntry(workDir string) (*JournalEntry, error) {
+	path, err := journalPath(workDir)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := loadJournal(path)
+	if err != nil {
+		return nil, err
+	}
+	i := lastJournalIndex(entries, worktreeRoot(workDir))
+	if i < 0 {
+		return nil, nil
+	}
+	return &entries[i], nil
+}
+
+// UndoLastJournalEntry reverses the newest sidecar action that can be undone
+// from workDir and drops it from the journal. It returns ErrJournalEmpty
+// when there is nothing to undo.
+func UndoLastJournalEntry(workDir string) (*JournalEntry, error) {
+	path, err := journalPath(workDir)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := loadJournal(path)
+	if err != nil {
+		return nil, err
+	}
+	i := lastJournalIndex(entries, worktreeRoot(workDir))
+	if i < 0 {
+		return nil, ErrJournalEmpty
+	}
+	e := entries[i]
+
+	switch e.Op {
+	case JournalAmend:
+		err = undoAmend(workDir, e)
+	case JournalDiscard:
+		err = restoreDiscardBackup(workDir, e)
+	case JournalDeleteBranch:
+		err = restoreBranch(workDir, e)
+	default:
+		err = fmt.Errorf("unknown journal operation %q", e.Op)
+	}
+	if err != nil {
+		return &e, err
+	}
+	return &e, writeJournal(path, append(entries[:i:i], entries[i+1:]...))
+}
+
+// forgetJournalDiscard drops the newest entry of the worktree if it is the
+// discard of path, once that discard was undone another way.
+func forgetJournalDiscard(workDir, path string) error {
+	file, err := journalPath(workDir)
+	if err != nil {
+		return err
+	}
+	entries, err := loadJournal(file)
+	if err != nil {
+		return err
+	}
+	i := lastJournalIndex(entries, worktreeRoot(workDir))
+	if i < 0 || entries[i].Op != JournalDiscard || entries[i].Path != path {
+		return nil
+	}
+	return writeJournal(file, append(entries[:i:i], entries[i+1:]...))
+}
+
+// undoAmend moves HEAD back to the commit before the amend, keeping the
+// amended changes staged. It refuses once HEAD has moved past the amend.
+func undoAmend(workDir string, e JournalEntry) error {
+	head, err := revParse(workDir, "HEAD")
+	if err != nil {
+		return err
+	}
+	if head != e.NewHead {
+		return fmt.Errorf("HEAD moved since the amend (now %s); restore %s from the reflog instead",
+			shortHash(head), shortHash(e.OldHead))
+	}
+	return ResetTo(workDir, e.OldHead, ResetSoft)
+}
+
+// BackupFile saves the working tree content of path as a blob before it is
+// discarded, and its staged content too when staged is set, returning the
+// journal entry that restores them.
+func BackupFile(workDir, path string, staged bool) (JournalEntry, error) {
+	e := JournalEntry{Op: JournalDiscard, Path: path}
+
+	info, err := os.Lstat(filepath.Join(workDir, path))
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		// Deleted in the working tree: undoing the discard deletes it again
+	case err != nil:
+		return e, err
+	case info.Mode()&fs.ModeSymlink != 0:
+		target, err := os.Readlink(filepath.Join(workDir, path))
+		if err != nil {
+			return e, err
+		}
+		cmd := exec.Command("git", "hash-object", "-w", "--stdin")
+		cmd.Dir = workDir
+		cmd.Stdin = strings.NewReader(target)
+		out, err := cmd.Output()
+		if err != nil {
+			return e, fmt.Errorf("back up %s: %w", path, err)
+		}
+		e.Blob = strings.TrimSpace(string(out))
+		e.Symlink = true
+	case !info.Mode().IsRegular():
+		return e, fmt.Errorf("cannot back up %s: not a regular file", path)
+	default:
+		cmd := exec.Command("git", "hash-object", "-w", "--", path)
+		cmd.Dir = workDir
+		out, err := cmd.Output()
+		if err != nil {
+			return e, fmt.Errorf("back up %s: %w", path, err)
+		}
+		e.Blob = strings.TrimSpace(string(out))
+		e.FileMode = uint32(info.Mode().Perm())
+	}
+
+	if staged {
+		cmd := exec.Command("git", "ls-files", "--stage", "--", path)
+		cmd.Dir = workDir
+		out, err := cmd.Output()
+		if err != nil {
+			return e, fmt.Errorf("back up staged %s: %w", path, err)
+		}
+		// <mode> <blob> <stage>\t<path>
+		if fields := strings.Fields(string(out)); len(fields) >= 2 {
+			e.IndexMode, e.IndexBlob = fields[0], fields[1]
+		}
+	}
+	return e, nil
+}
+
+// restoreDiscardBackup writes a discarded file back from its backup blobs.
+func restoreDiscardBackup(workDir string, e JournalEntry) error {
+	full := filepath.Join(workDir, e.Path)
+	if e.Blob == "" {
+		if err := os.Remove(full); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	} else {
+		cmd := exec.Command("git", "cat-file", "blob", e.Blob)
+		cmd.Dir = workDir
+		content, err := cmd.Output()
+		if err != nil {
+			return fmt.Errorf("read backup of %s: %w", e.Path, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			return err
+		}
+		if e.Symlink {
+			if err := os.Remove(full); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+			if err := os.Symlink(string(content), full); err != nil {
+				return err
+			}
+			return restoreIndexBackup(workDir, e)
+		}
+		mode := fs.FileMode(e.FileMode)
+		if mode == 0 {
+			mode = 0644
+		}
+		if err := os.WriteFile(full, content, mode); err != nil {
+			return err
+		}
+		if err := os.Chmod(full, mode); err != nil {
+			return err
+		}
+	}
+
+	return restoreIndexBackup(workDir, e)
+}
+
+// restoreIndexBackup puts staged content a discard dropped back in the index.
+func restoreIndexBackup(workDir string, e JournalEntry) error {
+	if e.IndexBlob == "" {
+		return nil
+	}
+	cmd := exec.Command("git", "update-index", "--add", "--cacheinfo", e.IndexMode+","+e.IndexBlob+","+e.Path)
+	cmd.Dir = workDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return &RemoteError{Output: string(output), Err: err}
+	}
+	return nil
+}
+
+// restoreBranch recreates a deleted branch at its old tip.
+func restoreBranch(workDir string, e JournalEntry) error {
+	cmd := exec.Command("git", "branch", e.Branch, e.Hash)
+	cmd.Dir = workDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return &BranchError{Output: string(output), Err: err}
+	}
+	return nil
+}
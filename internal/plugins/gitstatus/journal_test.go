@@ -0,0 +1,139 @@
+package gitstatus
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestJournal_UndoAmend(t *testing.T) {
+	dir := initRebaseRepo(t, []string{"a", "b"}, nil)
+	before := commitHash(t, dir, "HEAD")
+	p := &Plugin{repoRoot: dir}
+
+	if msg := p.doAmend("b amended")(); !isCommitSuccess(msg) {
+		t.Fatalf("amend: %#v", msg)
+	}
+	entry, err := LastJournalEntry(dir)
+	if err != nil || entry == nil || entry.Op != JournalAmend || entry.OldHead != before {
+		t.Fatalf("journal entry = %+v, err %v, want the amend of %s", entry, err, before)
+	}
+
+	if _, err := UndoLastJournalEntry(dir); err != nil {
+		t.Fatal(err)
+	}
+	if got := commitHash(t, dir, "HEAD"); got != before {
+		t.Errorf("HEAD = %s, want %s", got, before)
+	}
+	if _, err := UndoLastJournalEntry(dir); !errors.Is(err, ErrJournalEmpty) {
+		t.Errorf("second undo err = %v, want ErrJournalEmpty", err)
+	}
+}
+
+func TestJournal_UndoAmendAfterHeadMoved(t *testing.T) {
+	dir := initRebaseRepo(t, []string{"a", "b"}, nil)
+	p := &Plugin{repoRoot: dir}
+	p.doAmend("b amended")()
+	if err := ResetTo(dir, "HEAD~1", ResetHard); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := UndoLastJournalEntry(dir)
+	if err == nil || !strings.Contains(err.Error(), "HEAD moved") {
+		t.Fatalf("err = %v, want a refusal", err)
+	}
+	// The entry stays so it can be retried
+	if entry, _ := LastJournalEntry(dir); entry == nil {
+		t.Error("failed undo dropped the journal entry")
+	}
+}
+
+func TestJournal_UndoDiscard(t *testing.T) {
+	dir := initPatchRepo(t, "base\n", "staged\n")
+	if out, err := exec.Command("git", "-C", dir, "add", "file.txt").CombinedOutput(); err != nil {
+		t.Fatalf("add: %v\n%s", err, out)
+	}
+	writeTestFile(t, dir, "staged\nunstaged\n")
+	if err := os.WriteFile(filepath.Join(dir, "new.txt"), []byte("new\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	p := &Plugin{repoRoot: dir}
+
+	p.doDiscard(&FileEntry{Path: "file.txt", Status: StatusModified, Staged: true})()
+	p.doDiscard(&FileEntry{Path: "new.txt", Status: StatusUntracked})()
+	if _, err := os.Stat(filepath.Join(dir, "new.txt")); err == nil {
+		t.Fatal("untracked file was not discarded")
+	}
+	if got := readTestFile(t, dir); got != "base\n" {
+		t.Fatalf("file.txt = %q, want it discarded", got)
+	}
+
+	// Newest first: the untracked file, then the staged one
+	if _, err := UndoLastJournalEntry(dir); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(filepath.Join(dir, "new.txt"))
+	if err != nil || info.Mode().Perm() != 0755 {
+		t.Errorf("new.txt not restored with its mode: %v, %v", info, err)
+	}
+
+	if _, err := UndoLastJournalEntry(dir); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTestFile(t, dir); got != "staged\nunstaged\n" {
+		t.Errorf("file.txt = %q, want its discarded content", got)
+	}
+	staged, _ := exec.Command("git", "-C", dir, "show", ":file.txt").Output()
+	if string(staged) != "staged\n" {
+		t.Errorf("index = %q, want the staged content back", staged)
+	}
+}
+
+func TestJournal_UndoBranchDeletion(t *testing.T) {
+	dir := initRebaseRepo(t, []string{"a", "b"}, nil)
+	if out, err := exec.Command("git", "-C", dir, "branch", "feature", "HEAD~1").CombinedOutput(); err != nil {
+		t.Fatalf("branch: %v\n%s", err, out)
+	}
+	tip := commitHash(t, dir, "feature")
+
+	if err := DeleteBranch(dir, "feature"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := revParse(dir, "refs/heads/feature"); err == nil {
+		t.Fatal("branch not deleted")
+	}
+
+	// Branch deletions can be undone from any worktree
+	wt := filepath.Join(t.TempDir(), "wt")
+	if out, err := exec.Command("git", "-C", dir, "worktree", "add", "-q", "--detach", wt).CombinedOutput(); err != nil {
+		t.Fatalf("worktree add: %v\n%s", err, out)
+	}
+	entry, err := UndoLastJournalEntry(wt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Description() != "deletion of branch feature" {
+		t.Errorf("description = %q", entry.Description())
+	}
+	if got := commitHash(t, dir, "feature"); got != tip {
+		t.Errorf("feature = %s, want %s", got, tip)
+	}
+}
+
+func TestJournal_ScopedToWorktree(t *testing.T) {
+	dir := initRebaseRepo(t, []string{"a"}, nil)
+	if err := RecordJournal(dir, JournalEntry{Op: JournalAmend, Worktree: "/elsewhere", OldHead: "x", NewHead: "y"}); err != nil {
+		t.Fatal(err)
+	}
+	if entry, err := LastJournalEntry(dir); err != nil || entry != nil {
+		t.Errorf("entry = %+v, err %v; another worktree's amend should not be offered", entry, err)
+	}
+}
+
+func isCommitSuccess(msg any) bool {
+	_, ok := msg.(CommitSuccessMsg)
+	return ok
+}
@@ -164,19 +164,7 @@ func (p *Plugin) handleMouseDoubleClick(action mouse.MouseAction) (*Plugin, tea.
 		if !p.cursorOnCommit() {
 			entries := p.tree.AllEntries()
 			if p.cursor < len(entries) {
-				entry := entries[p.cursor]
-				p.diffReturnMode = p.viewMode
-				p.viewMode = ViewModeDiff
-				p.diffFile = entry.Path
-				p.diffCommit = ""
-				p.diffCommitSubject = ""
-				p.diffCommitShortHash = ""
-				p.diffScroll = 0
-				p.diffLoaded = false
-				if entry.IsFolder {
-					return p, p.loadFullFolderDiff(entry)
-				}
-				return p, p.loadDiff(entry.Path, entry.Staged, entry.Status)
+				return p, p.openFileDiff(entries[p.cursor])
 			}
 		}
 		return p, nil
@@ -477,6 +465,9 @@ func (p *Plugin) handlePullConflictMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
 
 	action := p.pullConflictModal.HandleMouse(msg, p.mouseHandler)
 	switch action {
+	case pullConflictResolveID:
+		plug, cmd := p.resolvePullConflict()
+		return plug.(*Plugin), cmd
 	case pullConflictAbortID:
 		plug, cmd := p.abortPullConflict()
 		return plug.(*Plugin), cmd
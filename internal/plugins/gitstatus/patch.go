@@ -0,0 +1,364 @@
+package gitstatus
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// PatchOp is a hunk or line operation on a file's changes.
+type PatchOp int
+
+const (
+	PatchStage   PatchOp = iota // Apply working tree changes to the index
+	PatchUnstage                // Remove staged changes from the index
+	PatchDiscard                // Remove working tree changes
+)
+
+// String returns the past-tense verb for the operation, used in toasts.
+func (op PatchOp) String() string {
+	switch op {
+	case PatchUnstage:
+		return "Unstaged"
+	case PatchDiscard:
+		return "Discarded"
+	default:
+		return "Staged"
+	}
+}
+
+// Verb returns the imperative verb for the operation, used in error toasts.
+func (op PatchOp) Verb() string {
+	switch op {
+	case PatchUnstage:
+		return "Unstage"
+	case PatchDiscard:
+		return "Discard"
+	default:
+		return "Stage"
+	}
+}
+
+var (
+	// ErrNoChangesSelected is returned when a selection holds only context lines.
+	ErrNoChangesSelected = errors.New("no changes selected")
+	// ErrDiffChanged is returned when the file changed since its diff was shown.
+	ErrDiffChanged = errors.New("diff changed since it was shown, try again")
+	// ErrWholeFileOnly is returned for new and deleted files, which can only
+	// be staged, unstaged or discarded as a whole.
+	ErrWholeFileOnly = errors.New("new and deleted files can only be changed as a whole")
+)
+
+// DiffSelection is the line cursor of a diff view. It selects either a whole
+// hunk or a range of lines within one hunk.
+type DiffSelection struct {
+	Hunk   int // Index into ParsedDiff.Hunks
+	Line   int // Cursor line within the hunk
+	Anchor int // Line where a range started, or -1 to select the whole hunk
+}
+
+// NewDiffSelection returns a selection on the first change of the diff, or nil
+// if the diff has no hunks.
+func NewDiffSelection(diff *ParsedDiff) *DiffSelection {
+	if diff == nil || diff.Binary || len(diff.Hunks) == 0 {
+		return nil
+	}
+	sel := &DiffSelection{Anchor: -1}
+	sel.Line = nearestChange(diff.Hunks[0].Lines, 0)
+	return sel
+}
+
+// RangeActive reports whether a line range is selected rather than the hunk.
+func (s *DiffSelection) RangeActive() bool {
+	return s.Anchor >= 0
+}
+
+// Range returns the first and last selected line of the hunk.
+func (s *DiffSelection) Range(hunk *Hunk) (start, end int) {
+	if !s.RangeActive() {
+		return 0, len(hunk.Lines) - 1
+	}
+	start, end = s.Anchor, s.Line
+	if start > end {
+		start, end = end, start
+	}
+	return start, end
+}
+
+// clamp fits the selection to diff, keeping the cursor on a change line. It is
+// used after the diff reloads; a range does not survive a reload.
+func (s *DiffSelection) clamp(diff *ParsedDiff) *DiffSelection {
+	if diff == nil || diff.Binary || len(diff.Hunks) == 0 {
+		return nil
+	}
+	sel := &DiffSelection{Hunk: s.Hunk, Line: s.Line, Anchor: -1}
+	if sel.Hunk >= len(diff.Hunks) {
+		sel.Hunk = len(diff.Hunks) - 1
+		sel.Line = len(diff.Hunks[sel.Hunk].Lines) - 1
+	}
+	lines := diff.Hunks[sel.Hunk].Lines
+	if sel.Line >= len(lines) {
+		sel.Line = len(lines) - 1
+	}
+	sel.Line = nearestChange(lines, sel.Line)
+	return sel
+}
+
+// move moves the cursor to the next (delta 1) or previous (delta -1) change
+// line. With a range active the cursor stays in its hunk. Returns false if
+// there is no change line in that direction.
+func (s *DiffSelection) move(diff *ParsedDiff, delta int) bool {
+	h, i := s.Hunk, s.Line+delta
+	for h >= 0 && h < len(diff.Hunks) {
+		lines := diff.Hunks[h].Lines
+		for ; i >= 0 && i < len(lines); i += delta {
+			if lines[i].Type != LineContext {
+				s.Hunk, s.Line = h, i
+				return true
+			}
+		}
+		if s.RangeActive() {
+			return false
+		}
+		h += delta
+		if h >= 0 && h < len(diff.Hunks) && delta < 0 {
+			i = len(diff.Hunks[h].Lines) - 1
+		} else {
+			i = 0
+		}
+	}
+	return false
+}
+
+// moveHunk moves the cursor to the first change of the next (delta 1) or
+// previous (delta -1) hunk and clears any range.
+func (s *DiffSelection) moveHunk(diff *ParsedDiff, delta int) bool {
+	h := s.Hunk + delta
+	if h < 0 || h >= len(diff.Hunks) {
+		return false
+	}
+	s.Hunk, s.Line, s.Anchor = h, nearestChange(diff.Hunks[h].Lines, 0), -1
+	return true
+}
+
+// mark reports whether line i of hunk h is under the cursor or selected.
+func (s *DiffSelection) mark(diff *ParsedDiff, h, i int) (cursor, selected bool) {
+	if s == nil || h != s.Hunk || h >= len(diff.Hunks) {
+		return false, false
+	}
+	start, end := s.Range(&diff.Hunks[h])
+	return i == s.Line, i >= start && i <= end
+}
+
+// row returns the cursor's line as counted by the startLine argument of
+// RenderLineDiff and RenderSideBySide: one per hunk header, then one per line
+// (unified) or line pair (side-by-side).
+func (s *DiffSelection) row(diff *ParsedDiff, mode DiffViewMode) int {
+	row := 0
+	for h := range diff.Hunks {
+		lines := diff.Hunks[h].Lines
+		row++ // Hunk header
+		if mode != DiffViewSideBySide {
+			if h == s.Hunk {
+				return row + s.Line
+			}
+			row += len(lines)
+			continue
+		}
+		pairs := groupLinesForSideBySide(lines)
+		if h == s.Hunk {
+			for pi, pair := range pairs {
+				if pair.leftIdx == s.Line || pair.rightIdx == s.Line {
+					return row + pi
+				}
+			}
+			return row
+		}
+		row += len(pairs)
+	}
+	return row
+}
+
+// nearestChange returns the first change line at or after i, else the last
+// one before it, else i.
+func nearestChange(lines []DiffLine, i int) int {
+	for j := i; j < len(lines); j++ {
+		if lines[j].Type != LineContext {
+			return j
+		}
+	}
+	for j := i - 1; j >= 0; j-- {
+		if lines[j].Type != LineContext {
+			return j
+		}
+	}
+	return i
+}
+
+// BuildPatch returns a patch of path holding only lines start..end of hunk.
+// Unselected changes are dropped from the patch so it applies to one side of
+// the hunk: with reverse false it applies to the old side (git apply for
+// staging), with reverse true to the new side (git apply -R for unstaging and
+// discarding).
+func BuildPatch(path string, hunk Hunk, start, end int, reverse bool) (string, error) {
+	var body strings.Builder
+	oldCount, newCount, changes := 0, 0, 0
+	writeLine := func(prefix byte, line DiffLine) {
+		body.WriteByte(prefix)
+		body.WriteString(line.Content)
+		body.WriteByte('\n')
+		if line.NoNewline {
+			body.WriteString("\\ No newline at end of file\n")
+		}
+	}
+
+	for i, line := range hunk.Lines {
+		selected := i >= start && i <= end
+		switch {
+		case line.Type == LineContext:
+			writeLine(' ', line)
+			oldCount++
+			newCount++
+		case selected && line.Type == LineAdd:
+			writeLine('+', line)
+			newCount++
+			changes++
+		case selected && line.Type == LineRemove:
+			writeLine('-', line)
+			oldCount++
+			changes++
+		case line.Type == LineAdd && reverse, line.Type == LineRemove && !reverse:
+			// The line exists on the side the patch applies to: keep it
+			writeLine(' ', line)
+			oldCount++
+			newCount++
+		}
+		// Otherwise the line is missing from that side and is left out
+	}
+	if changes == 0 {
+		return "", ErrNoChangesSelected
+	}
+
+	// The side the patch applies to keeps its range; the other side starts at
+	// the same line. A zero-length range starts at the line before it.
+	oldStart, newStart := hunk.OldStart, hunk.NewStart
+	if reverse {
+		oldStart = otherStart(hunk.NewStart, newCount, oldCount)
+	} else {
+		newStart = otherStart(hunk.OldStart, oldCount, newCount)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "diff --git a/%s b/%s\n", path, path)
+	fmt.Fprintf(&sb, "--- a/%s\n", path)
+	fmt.Fprintf(&sb, "+++ b/%s\n", path)
+	fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+	sb.WriteString(body.String())
+	return sb.String(), nil
+}
+
+// otherStart returns the start of a hunk side with otherCount lines, given
+// the start and count of the side it is relative to.
+func otherStart(start, count, otherCount int) int {
+	if count == 0 {
+		start++
+	}
+	if otherCount == 0 {
+		start--
+	}
+	return start
+}
+
+// ApplyPatch applies a patch with git apply, to the index when cached is set
+// and in reverse when reverse is set.
+func ApplyPatch(workDir, patch string, cached, reverse bool) error {
+	args := []string{"apply"}
+	if cached {
+		args = append(args, "--cached")
+	}
+	if reverse {
+		args = append(args, "-R")
+	}
+	args = append(args, "-")
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workDir
+	cmd.Stdin = strings.NewReader(patch)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// ApplySelection stages, unstages or discards lines start..end of hunk index
+// hunkIdx of a file's diff. shown is the hunk as displayed; the diff is read
+// again and the operation fails with ErrDiffChanged if the hunk no longer
+// matches. staged says whether shown came from the staged diff. Returns the
+// applied patch, which undoes a discard when applied forward.
+func ApplySelection(workDir, path string, staged bool, hunkIdx int, shown Hunk, start, end int, op PatchOp) (string, error) {
+	raw, err := getPatchableDiff(workDir, path, staged)
+	if err != nil {
+		return "", err
+	}
+	if strings.Contains(raw, "\nnew file mode ") || strings.Contains(raw, "\ndeleted file mode ") {
+		return "", ErrWholeFileOnly
+	}
+	parsed, _ := ParseUnifiedDiff(raw)
+	if parsed == nil || hunkIdx >= len(parsed.Hunks) || !sameHunk(parsed.Hunks[hunkIdx], shown) {
+		return "", ErrDiffChanged
+	}
+
+	patch, err := BuildPatch(path, parsed.Hunks[hunkIdx], start, end, op != PatchStage)
+	if err != nil {
+		return "", err
+	}
+	if err := ApplyPatch(workDir, patch, op != PatchDiscard, op != PatchStage); err != nil {
+		return "", err
+	}
+	return patch, nil
+}
+
+// getPatchableDiff returns a file's diff for building patches. Unlike GetDiff
+// it keeps trailing whitespace and ignores color and external diff settings.
+func getPatchableDiff(workDir, path string, staged bool) (string, error) {
+	args := []string{"diff", "--no-color", "--no-ext-diff"}
+	if staged {
+		args = append(args, "--cached")
+	}
+	args = append(args, "--", path)
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
+// sameHunk reports whether two hunks have the same range and lines.
+func sameHunk(a, b Hunk) bool {
+	if a.OldStart != b.OldStart || a.OldCount != b.OldCount ||
+		a.NewStart != b.NewStart || a.NewCount != b.NewCount {
+		return false
+	}
+	// The shown diff is trimmed, so it may miss trailing whitespace and blank
+	// context lines at its end
+	if len(a.Lines) < len(b.Lines) {
+		return false
+	}
+	for _, line := range a.Lines[len(b.Lines):] {
+		if line.Type != LineContext || strings.TrimSpace(line.Content) != "" {
+			return false
+		}
+	}
+	for i := range b.Lines {
+		if a.Lines[i].Type != b.Lines[i].Type ||
+			strings.TrimRight(a.Lines[i].Content, " \t") != strings.TrimRight(b.Lines[i].Content, " \t") {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,247 @@
+package gitstatus
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// initPatchRepo creates a repo with file.txt committed as base and modified
+// to work in the working tree.
+func initPatchRepo(t *testing.T, base, work string) string {
+	t.Helper()
+	dir := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	git("config", "user.email", "test@example.com")
+	git("config", "user.name", "Test")
+	writeTestFile(t, dir, base)
+	git("add", "file.txt")
+	git("commit", "-q", "-m", "base")
+	writeTestFile(t, dir, work)
+	return dir
+}
+
+func writeTestFile(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, "file.txt"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readTestFile(t *testing.T, dir string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, "file.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func indexContent(t *testing.T, dir string) string {
+	t.Helper()
+	out, err := exec.Command("git", "-C", dir, "show", ":file.txt").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+// shownHunk returns a hunk of the file's diff as the diff views show it.
+func shownHunk(t *testing.T, dir string, staged bool, idx int) Hunk {
+	t.Helper()
+	raw, err := GetDiff(dir, "file.txt", staged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, _ := ParseUnifiedDiff(raw)
+	if idx >= len(parsed.Hunks) {
+		t.Fatalf("diff has %d hunks, want hunk %d:\n%s", len(parsed.Hunks), idx, raw)
+	}
+	return parsed.Hunks[idx]
+}
+
+const (
+	patchBase = "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\n"
+	patchWork = "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nK\nl\nm\n"
+)
+
+func TestApplySelection_StageHunk(t *testing.T) {
+	dir := initPatchRepo(t, patchBase, patchWork)
+
+	hunk := shownHunk(t, dir, false, 1)
+	if _, err := ApplySelection(dir, "file.txt", false, 1, hunk, 0, len(hunk.Lines)-1, PatchStage); err != nil {
+		t.Fatal(err)
+	}
+	want := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nK\nl\nm\n"
+	if got := indexContent(t, dir); got != want {
+		t.Errorf("index = %q, want %q", got, want)
+	}
+	if got := readTestFile(t, dir); got != patchWork {
+		t.Errorf("staging changed the working tree: %q", got)
+	}
+}
+
+func TestApplySelection_StageLines(t *testing.T) {
+	dir := initPatchRepo(t, patchBase, patchWork)
+
+	// Second hunk: " h i j", "-k", "+K", " l", "+m"; stage only "+m"
+	hunk := shownHunk(t, dir, false, 1)
+	last := len(hunk.Lines) - 1
+	if hunk.Lines[last].Type != LineAdd || hunk.Lines[last].Content != "m" {
+		t.Fatalf("unexpected hunk lines: %+v", hunk.Lines)
+	}
+	if _, err := ApplySelection(dir, "file.txt", false, 1, hunk, last, last, PatchStage); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := indexContent(t, dir), patchBase+"m\n"; got != want {
+		t.Errorf("index = %q, want %q", got, want)
+	}
+
+	// Unstage it again from the staged diff
+	staged := shownHunk(t, dir, true, 0)
+	if _, err := ApplySelection(dir, "file.txt", true, 0, staged, 0, len(staged.Lines)-1, PatchUnstage); err != nil {
+		t.Fatal(err)
+	}
+	if got := indexContent(t, dir); got != patchBase {
+		t.Errorf("index after unstage = %q, want %q", got, patchBase)
+	}
+}
+
+func TestApplySelection_DiscardLinesAndUndo(t *testing.T) {
+	dir := initPatchRepo(t, patchBase, patchWork)
+
+	// Discard "-k"/"+K" but keep "+m"
+	hunk := shownHunk(t, dir, false, 1)
+	start, end := -1, -1
+	for i, line := range hunk.Lines {
+		if line.Content == "k" || line.Content == "K" {
+			if start < 0 {
+				start = i
+			}
+			end = i
+		}
+	}
+	patch, err := ApplySelection(dir, "file.txt", false, 1, hunk, start, end, PatchDiscard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := readTestFile(t, dir), "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\n"; got != want {
+		t.Errorf("working tree = %q, want %q", got, want)
+	}
+	if got := indexContent(t, dir); got != patchBase {
+		t.Errorf("discard changed the index: %q", got)
+	}
+
+	if err := ApplyPatch(dir, patch, false, false); err != nil {
+		t.Fatalf("undo: %v", err)
+	}
+	if got := readTestFile(t, dir); got != patchWork {
+		t.Errorf("working tree after undo = %q, want %q", got, patchWork)
+	}
+}
+
+func TestApplySelection_NoNewlineAtEnd(t *testing.T) {
+	dir := initPatchRepo(t, "a\nb", "a\nb\nc")
+
+	hunk := shownHunk(t, dir, false, 0)
+	if _, err := ApplySelection(dir, "file.txt", false, 0, hunk, 0, len(hunk.Lines)-1, PatchStage); err != nil {
+		t.Fatal(err)
+	}
+	if got := indexContent(t, dir); got != "a\nb\nc" {
+		t.Errorf("index = %q, want %q", got, "a\nb\nc")
+	}
+}
+
+func TestApplySelection_Errors(t *testing.T) {
+	dir := initPatchRepo(t, patchBase, patchWork)
+	hunk := shownHunk(t, dir, false, 0)
+
+	// A range of context lines only
+	if _, err := ApplySelection(dir, "file.txt", false, 0, hunk, 0, 0, PatchStage); !errors.Is(err, ErrNoChangesSelected) {
+		t.Errorf("context-only selection: err = %v, want ErrNoChangesSelected", err)
+	}
+
+	// The file changed after the diff was shown
+	writeTestFile(t, dir, "x\n"+patchWork)
+	if _, err := ApplySelection(dir, "file.txt", false, 0, hunk, 0, len(hunk.Lines)-1, PatchStage); !errors.Is(err, ErrDiffChanged) {
+		t.Errorf("stale diff: err = %v, want ErrDiffChanged", err)
+	}
+}
+
+func TestBuildPatch_Counts(t *testing.T) {
+	hunk := Hunk{OldStart: 3, OldCount: 3, NewStart: 3, NewCount: 3, Lines: []DiffLine{
+		{Type: LineContext, Content: "c"},
+		{Type: LineRemove, Content: "d"},
+		{Type: LineAdd, Content: "D"},
+		{Type: LineAdd, Content: "E"},
+		{Type: LineRemove, Content: "e"},
+	}}
+
+	// Stage only "+D": "-d" becomes context, "+E" is dropped, "-e" becomes context
+	patch, err := BuildPatch("f", hunk, 2, 2, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(patch, "@@ -3,3 +3,4 @@\n c\n d\n+D\n e\n") {
+		t.Errorf("forward patch:\n%s", patch)
+	}
+
+	// Discard only "-d": "+D" and "+E" become context, "-e" is dropped
+	patch, err = BuildPatch("f", hunk, 1, 1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(patch, "@@ -3,4 +3,3 @@\n c\n-d\n D\n E\n") {
+		t.Errorf("reverse patch:\n%s", patch)
+	}
+}
+
+func TestDiffSelection_Move(t *testing.T) {
+	diff, _ := ParseUnifiedDiff(`--- a/f
++++ b/f
+@@ -1,3 +1,3 @@
+ a
+-b
++B
+@@ -10,2 +10,3 @@
+ j
++x
+ k
+`)
+	sel := NewDiffSelection(diff)
+	if sel.Hunk != 0 || sel.Line != 1 {
+		t.Fatalf("new selection = %+v, want first change", sel)
+	}
+	sel.move(diff, 1)
+	sel.move(diff, 1)
+	if sel.Hunk != 1 || sel.Line != 1 {
+		t.Errorf("after two moves = %+v, want second hunk's change", sel)
+	}
+	if sel.move(diff, 1) {
+		t.Error("move past the last change should fail")
+	}
+	if row := sel.row(diff, DiffViewUnified); row != 6 {
+		t.Errorf("row = %d, want 6", row)
+	}
+
+	// A range stays inside its hunk
+	sel.moveHunk(diff, -1)
+	sel.Anchor = sel.Line
+	sel.move(diff, 1)
+	if sel.move(diff, 1) || sel.Hunk != 0 {
+		t.Errorf("range moved out of its hunk: %+v", sel)
+	}
+	if start, end := sel.Range(&diff.Hunks[0]); start != 1 || end != 2 {
+		t.Errorf("range = %d..%d, want 1..2", start, end)
+	}
+}
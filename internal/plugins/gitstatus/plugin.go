@@ -41,6 +41,15 @@ const (
 	ViewModeConfirmStashPop                 // Confirm stash pop modal
 	ViewModePullConflict                    // Pull conflict resolution modal
 	ViewModeError                           // Generic error modal for git operation failures
+	ViewModeRebase                          // Interactive rebase editor modal
+	ViewModeRebaseStatus                    // Stopped rebase continue/abort modal
+	ViewModeConflicts                       // Three-way conflict resolution view
+	ViewModeCherryPick                      // Cherry-pick target picker modal
+	ViewModeRevert                          // Confirm revert modal
+	ViewModeReset                           // Reset mode and confirmation modal
+	ViewModeReflog                          // Reflog browser
+	ViewModeUndo                            // Confirm undo of the last sidecar action modal
+	ViewModeMoveBranch                      // Confirm moving a branch to a reflog entry modal
 )
 
 // FocusPane represents which pane is active in the three-pane view.
@@ -85,6 +94,7 @@ type Plugin struct {
 	diffPaneHorizScroll int          // Horizontal scroll for inline diff
 	diffPaneParsedDiff  *ParsedDiff  // Parsed diff for inline view
 	diffPaneViewMode    DiffViewMode // Unified or side-by-side for inline diff
+	diffPaneTarget      *patchTarget // File entry shown in the diff pane, nil for folders
 
 	// Commit preview state (for three-pane view when on commit)
 	previewCommit       *Commit // Commit being previewed in right pane
@@ -106,6 +116,11 @@ type Plugin struct {
 	diffLoaded          bool         // True once diff load completes (distinguishes loading vs empty)
 	diffWrapEnabled     bool         // Wrap long lines instead of truncating
 	diffBackWidth       int          // Width of back button for hit region (set during render)
+	diffTarget          *patchTarget // File entry of a working tree diff, nil for commits and folders
+
+	// Hunk and line staging state
+	restoreCursor *patchTarget    // Entry to put the cursor on after the next refresh
+	lastDiscard   *discardedPatch // Last hunk or line discard, for undo
 
 	// Push status state
 	pushStatus              *PushStatus
@@ -153,12 +168,13 @@ type Plugin struct {
 	mouseHandler *mouse.Handler
 
 	// Error modal state
-	errorModal       *modal.Modal
-	errorModalWidth  int
-	errorModalHeight int
-	errorTitle       string // e.g. "Push Failed", "Fetch Failed"
-	errorDetail      string // full git command output
-	errorOfferPull   bool   // true when push was rejected due to remote ahead
+	errorModal        *modal.Modal
+	errorModalWidth   int
+	errorModalHeight  int
+	errorTitle        string // e.g. "Push Failed", "Fetch Failed"
+	errorDetail       string // full git command output
+	errorOfferPull    bool   // true when push was rejected due to remote ahead
+	errorOfferResolve bool   // true when a cherry-pick or revert stopped on conflicts
 
 	// Discard confirm state
 	discardFile       *FileEntry   // File being confirmed for discard
@@ -180,6 +196,88 @@ type Plugin struct {
 	branchPickerModal *modal.Modal
 	branchPickerWidth int
 
+	// Interactive rebase state
+	rebasePlan             *RebasePlan   // Plan being edited
+	rebaseOriginal         []RebaseEntry // Plan entries as loaded, to detect changes
+	rebaseCursor           int
+	rebaseReturnMode       ViewMode // Mode to return to when the editor closes
+	rebaseError            string   // Validation error shown in the editor
+	rebaseModal            *modal.Modal
+	rebaseModalWidth       int
+	rebaseRewording        bool // True while editing a reworded message
+	rebaseMessage          textarea.Model
+	rebaseRewordModal      *modal.Modal
+	rebaseRewordWidth      int
+	rebaseRunning          bool         // True while git rebase runs
+	rebaseState            *RebaseState // Stopped rebase, nil when none is in progress
+	rebaseStatusReturnMode ViewMode
+	rebaseStatusError      string // Error from the last continue
+	rebaseStatusModal      *modal.Modal
+	rebaseStatusWidth      int
+
+	// Conflict resolution state
+	conflictOp         ConflictOp      // Operation that stopped with the conflicts
+	conflictFiles      []string        // Files conflicted when the view opened
+	conflictResolved   map[string]bool // Files resolved and staged in the view
+	conflictFileIdx    int
+	conflictFile       *ConflictFile // File shown, nil while loading
+	conflictRegion     int           // Selected region of conflictFile
+	conflictScroll     int           // Scroll offset of the region's sides
+	conflictDirty      bool          // Resolutions not yet written to the file
+	conflictDiscardKey string        // Key pressed once to drop unsaved resolutions
+	conflictEditing    bool          // True while editing a region's result
+	conflictEditor     textarea.Model
+	conflictBusy       bool // True while saving or continuing
+	conflictError      string
+	conflictReturnMode ViewMode
+
+	// Commit action state (cherry-pick, revert, reset)
+	commitMark          string    // Hash of the commit marked as one end of a range
+	commitActionRunning bool      // True while a cherry-pick, revert or reset runs
+	cherryPickCommits   []*Commit // Commits to pick, oldest first
+	cherryPickTargets   []CherryPickTarget
+	cherryPickTargetIdx int
+	cherryPickModal     *modal.Modal
+	cherryPickWidth     int
+	revertCommit        *Commit
+	revertMessage       string
+	revertModal         *modal.Modal
+	revertWidth         int
+	resetCommit         *Commit
+	resetPreview        *ResetPreview
+	resetModeIdx        int
+	resetModal          *modal.Modal
+	resetWidth          int
+	resetReturnMode     ViewMode
+
+	// Reflog browser state
+	reflogRefs       []string // HEAD, then local branches
+	reflogCurrent    string   // Checked out branch; "" when HEAD is detached
+	reflogRefIdx     int
+	reflogEntries    []ReflogEntry
+	reflogCursor     int
+	reflogScroll     int
+	reflogLoaded     bool
+	reflogDiff       *MultiFileDiff
+	reflogDiffHash   string // Entry the preview belongs to
+	reflogDiffScroll int
+	reflogError      string
+	reflogUndo       *JournalEntry // Newest sidecar action that can be undone
+	reflogReturnMode ViewMode
+
+	// Moving a branch that is not checked out to a reflog entry
+	moveBranchName  string
+	moveBranchEntry *ReflogEntry
+	moveBranchModal *modal.Modal
+	moveBranchWidth int
+
+	// Undo of the last sidecar action (operation journal)
+	undoEntry      *JournalEntry
+	undoRunning    bool
+	undoModal      *modal.Modal
+	undoWidth      int
+	undoReturnMode ViewMode
+
 	// Fetch/Pull state
 	fetchInProgress bool
 	pullInProgress  bool
@@ -352,6 +450,24 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 			return p.updatePullMenu(msg)
 		case ViewModePullConflict:
 			return p.updatePullConflict(msg)
+		case ViewModeRebase:
+			return p.updateRebase(msg)
+		case ViewModeRebaseStatus:
+			return p.updateRebaseStatus(msg)
+		case ViewModeConflicts:
+			return p.updateConflicts(msg)
+		case ViewModeCherryPick:
+			return p.updateCherryPick(msg)
+		case ViewModeRevert:
+			return p.updateRevert(msg)
+		case ViewModeReset:
+			return p.updateReset(msg)
+		case ViewModeReflog:
+			return p.updateReflog(msg)
+		case ViewModeUndo:
+			return p.updateUndo(msg)
+		case ViewModeMoveBranch:
+			return p.updateMoveBranch(msg)
 		case ViewModeConfirmDiscard:
 			return p.updateConfirmDiscard(msg)
 		case ViewModeConfirmStashPop:
@@ -382,6 +498,24 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 			return p.handlePullMenuMouse(msg)
 		case ViewModePullConflict:
 			return p.handlePullConflictMouse(msg)
+		case ViewModeRebase:
+			return p.handleRebaseMouse(msg)
+		case ViewModeRebaseStatus:
+			return p.handleRebaseStatusMouse(msg)
+		case ViewModeConflicts:
+			return p.handleConflictMouse(msg)
+		case ViewModeCherryPick:
+			return p.handleCherryPickMouse(msg)
+		case ViewModeRevert:
+			return p.handleRevertMouse(msg)
+		case ViewModeReset:
+			return p.handleResetMouse(msg)
+		case ViewModeReflog:
+			return p.handleReflogMouse(msg)
+		case ViewModeUndo:
+			return p.handleUndoMouse(msg)
+		case ViewModeMoveBranch:
+			return p.handleMoveBranchMouse(msg)
 		case ViewModeConfirmDiscard:
 			return p.handleDiscardMouse(msg)
 		case ViewModeConfirmStashPop:
@@ -437,6 +571,16 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		if p.cursor > maxCursor {
 			p.cursor = maxCursor
 		}
+		if !p.rebaseRunning {
+			p.rebaseState = msg.Rebase
+			if p.rebaseState == nil && p.viewMode == ViewModeRebaseStatus {
+				p.closeRebaseStatus()
+			}
+		}
+		if p.restoreCursor != nil {
+			p.moveCursorToTarget(p.restoreCursor)
+			p.restoreCursor = nil
+		}
 		// Auto-load preview for current cursor position after refresh
 		if p.viewMode == ViewModeStatus {
 			return p, p.autoLoadPreview(true)
@@ -452,9 +596,16 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		p.diffLoaded = true
 		// Always parse diff for built-in rendering (even if delta is available)
 		// This allows toggling between delta and built-in rendering at runtime
+		prev := p.parsedDiff
 		p.parsedDiff, _ = ParseUnifiedDiff(msg.Raw)
+		if p.diffCommit == "" {
+			initSelection(p.parsedDiff, prev, p.diffTarget)
+		}
 		return p, nil
 
+	case PatchAppliedMsg:
+		return p, p.handlePatchApplied(msg)
+
 	case CommitSuccessMsg:
 		// Commit succeeded, return to status view and refresh
 		p.viewMode = ViewModeStatus
@@ -476,6 +627,7 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		}
 		// Only update if this is still the selected file
 		if msg.File == p.selectedDiffFile {
+			initSelection(msg.Parsed, p.diffPaneParsedDiff, p.diffPaneTarget)
 			p.diffPaneParsedDiff = msg.Parsed
 			// Clamp scroll to new content length (diff may have shrunk after stage/unstage)
 			if p.diffPaneParsedDiff != nil {
@@ -747,6 +899,79 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		p.showErrorModal("Stash Failed", msg.Err)
 		return p, nil
 
+	case RebasePlanLoadedMsg:
+		if plugin.IsStale(p.ctx, msg) {
+			return p, nil
+		}
+		return p, p.handleRebasePlanLoaded(msg)
+
+	case RebaseDoneMsg:
+		return p, p.handleRebaseDone(msg)
+
+	case OpenConflictsMsg:
+		if p.inNoRepoMode() {
+			return p, nil
+		}
+		return p, p.openConflicts(msg.Path)
+
+	case ConflictsLoadedMsg:
+		if plugin.IsStale(p.ctx, msg) {
+			return p, nil
+		}
+		return p, p.handleConflictsLoaded(msg)
+
+	case ConflictFileLoadedMsg:
+		if plugin.IsStale(p.ctx, msg) {
+			return p, nil
+		}
+		p.handleConflictFileLoaded(msg)
+		return p, nil
+
+	case ConflictSavedMsg:
+		return p, p.handleConflictSaved(msg)
+
+	case ConflictContinueDoneMsg:
+		return p, p.handleConflictContinueDone(msg)
+
+	case CherryPickTargetsMsg:
+		if plugin.IsStale(p.ctx, msg) {
+			return p, nil
+		}
+		p.handleCherryPickTargets(msg)
+		return p, nil
+
+	case ResetPreviewMsg:
+		if plugin.IsStale(p.ctx, msg) {
+			return p, nil
+		}
+		p.handleResetPreview(msg)
+		return p, nil
+
+	case CommitActionDoneMsg:
+		return p, p.handleCommitActionDone(msg)
+
+	case ReflogLoadedMsg:
+		if plugin.IsStale(p.ctx, msg) {
+			return p, nil
+		}
+		return p, p.handleReflogLoaded(msg)
+
+	case ReflogDiffMsg:
+		if plugin.IsStale(p.ctx, msg) {
+			return p, nil
+		}
+		p.handleReflogDiff(msg)
+		return p, nil
+
+	case JournalLoadedMsg:
+		if plugin.IsStale(p.ctx, msg) {
+			return p, nil
+		}
+		return p, p.handleJournalLoaded(msg)
+
+	case JournalUndoneMsg:
+		return p, p.handleJournalUndone(msg)
+
 	case PullAbortedMsg:
 		p.pullConflictFiles = nil
 		p.pullConflictType = ""
@@ -881,6 +1106,24 @@ func (p *Plugin) View(width, height int) string {
 			content = p.renderPullMenu()
 		case ViewModePullConflict:
 			content = p.renderPullConflict()
+		case ViewModeRebase:
+			content = p.renderRebaseEditor()
+		case ViewModeRebaseStatus:
+			content = p.renderRebaseStatus()
+		case ViewModeConflicts:
+			content = p.renderConflictView()
+		case ViewModeCherryPick:
+			content = p.renderCherryPick()
+		case ViewModeRevert:
+			content = p.renderRevert()
+		case ViewModeReset:
+			content = p.renderReset()
+		case ViewModeReflog:
+			content = p.renderReflogView()
+		case ViewModeUndo:
+			content = p.renderUndo()
+		case ViewModeMoveBranch:
+			content = p.renderMoveBranch()
 		case ViewModeConfirmDiscard:
 			content = p.renderConfirmDiscard()
 		case ViewModeConfirmStashPop:
@@ -918,7 +1161,7 @@ func (p *Plugin) SetFocused(f bool) { p.focused = f }
 
 // Commands returns the available commands.
 func (p *Plugin) Commands() []plugin.Command {
-	return []plugin.Command{
+	cmds := []plugin.Command{
 		// git-no-repo context
 		{ID: "init-repo", Name: "Init", Description: "Initialize a git repository", Category: plugin.CategoryGit, Context: "git-no-repo", Priority: 1},
 		{ID: "refresh", Name: "Retry", Description: "Re-check for a git repository", Category: plugin.CategoryActions, Context: "git-no-repo", Priority: 2},
@@ -940,6 +1183,8 @@ func (p *Plugin) Commands() []plugin.Command {
 		{ID: "stash", Name: "Stash", Description: "Stash changes", Category: plugin.CategoryGit, Context: "git-status", Priority: 4},
 		{ID: "stash-pop", Name: "Pop", Description: "Pop latest stash", Category: plugin.CategoryGit, Context: "git-status", Priority: 4},
 		{ID: "stash-apply", Name: "Apply", Description: "Apply latest stash", Category: plugin.CategoryGit, Context: "git-status", Priority: 4},
+		{ID: "show-reflog", Name: "Reflog", Description: "Browse HEAD and branch reflogs", Category: plugin.CategoryNavigation, Context: "git-status", Priority: 4},
+		{ID: "undo-action", Name: "Undo", Description: "Undo the last sidecar amend, discard or branch deletion", Category: plugin.CategoryGit, Context: "git-status", Priority: 4},
 		{ID: "open-in-file-browser", Name: "Browse", Description: "Open file in file browser", Category: plugin.CategoryNavigation, Context: "git-status", Priority: 4},
 		{ID: "open-in-github", Name: "GitHub", Description: "Open commit in GitHub", Category: plugin.CategoryActions, Context: "git-status", Priority: 4},
 		{ID: "toggle-sidebar", Name: "Sidebar", Description: "Toggle sidebar visibility", Category: plugin.CategoryView, Context: "git-status", Priority: 5},
@@ -958,6 +1203,13 @@ func (p *Plugin) Commands() []plugin.Command {
 		{ID: "yank-id", Name: "YankID", Description: "Copy commit ID", Category: plugin.CategoryActions, Context: "git-status-commits", Priority: 3},
 		{ID: "open-in-github", Name: "GitHub", Description: "Open commit in GitHub", Category: plugin.CategoryActions, Context: "git-status-commits", Priority: 3},
 		{ID: "toggle-graph", Name: "Graph", Description: "Toggle commit graph display", Category: plugin.CategoryView, Context: "git-status-commits", Priority: 2},
+		{ID: "interactive-rebase", Name: "Rebase", Description: "Interactive rebase from this commit", Category: plugin.CategoryGit, Context: "git-status-commits", Priority: 3},
+		{ID: "cherry-pick", Name: "Pick", Description: "Cherry-pick the commit or marked range", Category: plugin.CategoryGit, Context: "git-status-commits", Priority: 2},
+		{ID: "mark-range", Name: "Mark", Description: "Mark one end of a cherry-pick range", Category: plugin.CategoryGit, Context: "git-status-commits", Priority: 4},
+		{ID: "revert-commit", Name: "Revert", Description: "Revert the commit", Category: plugin.CategoryGit, Context: "git-status-commits", Priority: 3},
+		{ID: "reset-to-commit", Name: "Reset", Description: "Reset the branch to this commit", Category: plugin.CategoryGit, Context: "git-status-commits", Priority: 3},
+		{ID: "show-reflog", Name: "Reflog", Description: "Browse HEAD and branch reflogs", Category: plugin.CategoryNavigation, Context: "git-status-commits", Priority: 4},
+		{ID: "undo-action", Name: "Undo", Description: "Undo the last sidecar amend, discard or branch deletion", Category: plugin.CategoryGit, Context: "git-status-commits", Priority: 4},
 		{ID: "toggle-sidebar", Name: "Sidebar", Description: "Toggle sidebar visibility", Category: plugin.CategoryView, Context: "git-status-commits", Priority: 5},
 		// git-history-search context (commit search modal)
 		{ID: "select", Name: "Select", Description: "Jump to selected match", Category: plugin.CategoryActions, Context: "git-history-search", Priority: 1},
@@ -977,11 +1229,21 @@ func (p *Plugin) Commands() []plugin.Command {
 		{ID: "open-in-file-browser", Name: "Browse", Description: "Open file in file browser", Category: plugin.CategoryNavigation, Context: "git-commit-preview", Priority: 3},
 		{ID: "toggle-sidebar", Name: "Sidebar", Description: "Toggle sidebar visibility", Category: plugin.CategoryView, Context: "git-commit-preview", Priority: 4},
 		// git-status-diff context (inline diff pane)
+		{ID: "stage-hunk", Name: "Stage", Description: "Stage hunk or selected lines", Category: plugin.CategoryGit, Context: "git-status-diff", Priority: 1},
+		{ID: "unstage-hunk", Name: "Unstage", Description: "Unstage hunk or selected lines", Category: plugin.CategoryGit, Context: "git-status-diff", Priority: 1},
+		{ID: "discard-hunk", Name: "Discard", Description: "Discard hunk or selected lines", Category: plugin.CategoryGit, Context: "git-status-diff", Priority: 2},
+		{ID: "select-lines", Name: "Lines", Description: "Select a range of lines in the hunk", Category: plugin.CategoryEdit, Context: "git-status-diff", Priority: 2},
+		{ID: "undo-discard", Name: "Undo", Description: "Restore the last discarded hunk or lines", Category: plugin.CategoryGit, Context: "git-status-diff", Priority: 4},
 		{ID: "toggle-diff-view", Name: "View", Description: "Toggle unified/split diff view", Category: plugin.CategoryView, Context: "git-status-diff", Priority: 2},
 		{ID: "toggle-wrap", Name: "Wrap", Description: "Toggle line wrapping", Category: plugin.CategoryView, Context: "git-status-diff", Priority: 3},
 		{ID: "toggle-sidebar", Name: "Sidebar", Description: "Toggle sidebar visibility", Category: plugin.CategoryView, Context: "git-status-diff", Priority: 3},
 		// git-diff context
 		{ID: "close-diff", Name: "Close", Description: "Close diff view", Category: plugin.CategoryView, Context: "git-diff", Priority: 1},
+		{ID: "stage-hunk", Name: "Stage", Description: "Stage hunk or selected lines", Category: plugin.CategoryGit, Context: "git-diff", Priority: 1},
+		{ID: "unstage-hunk", Name: "Unstage", Description: "Unstage hunk or selected lines", Category: plugin.CategoryGit, Context: "git-diff", Priority: 1},
+		{ID: "discard-hunk", Name: "Discard", Description: "Discard hunk or selected lines", Category: plugin.CategoryGit, Context: "git-diff", Priority: 2},
+		{ID: "select-lines", Name: "Lines", Description: "Select a range of lines in the hunk", Category: plugin.CategoryEdit, Context: "git-diff", Priority: 2},
+		{ID: "undo-discard", Name: "Undo", Description: "Restore the last discarded hunk or lines", Category: plugin.CategoryGit, Context: "git-diff", Priority: 4},
 		{ID: "scroll", Name: "Scroll", Description: "Scroll diff content", Category: plugin.CategoryNavigation, Context: "git-diff", Priority: 2},
 		{ID: "toggle-sidebar", Name: "Sidebar", Description: "Toggle sidebar visibility", Category: plugin.CategoryView, Context: "git-diff", Priority: 2},
 		{ID: "toggle-diff-view", Name: "View", Description: "Toggle unified/split diff view", Category: plugin.CategoryView, Context: "git-diff", Priority: 3},
@@ -1002,16 +1264,97 @@ func (p *Plugin) Commands() []plugin.Command {
 		{ID: "pull-autostash", Name: "Autostash", Description: "Pull rebase + autostash", Category: plugin.CategoryGit, Context: "git-pull-menu", Priority: 1},
 		{ID: "cancel", Name: "Cancel", Description: "Cancel", Category: plugin.CategoryNavigation, Context: "git-pull-menu", Priority: 2},
 		// git-pull-conflict context
+		{ID: "resolve-conflicts", Name: "Resolve", Description: "Resolve conflicts in the conflict view", Category: plugin.CategoryGit, Context: "git-pull-conflict", Priority: 1},
 		{ID: "abort-pull", Name: "Abort", Description: "Abort merge/rebase", Category: plugin.CategoryGit, Context: "git-pull-conflict", Priority: 1},
 		{ID: "dismiss", Name: "Dismiss", Description: "Dismiss and resolve manually", Category: plugin.CategoryNavigation, Context: "git-pull-conflict", Priority: 2},
 		// git-error context (error modal)
 		{ID: "pull-from-error", Name: "Pull", Description: "Pull from remote", Category: plugin.CategoryGit, Context: "git-error", Priority: 1},
 		{ID: "dismiss", Name: "Dismiss", Description: "Dismiss error", Category: plugin.CategoryNavigation, Context: "git-error", Priority: 1},
 		{ID: "yank-error", Name: "Yank", Description: "Copy error to clipboard", Category: plugin.CategoryActions, Context: "git-error", Priority: 2},
+		// git-rebase context (interactive rebase editor)
+		{ID: "start-rebase", Name: "Start", Description: "Run the rebase", Category: plugin.CategoryGit, Context: "git-rebase", Priority: 1},
+		{ID: "rebase-squash", Name: "Squash", Description: "Meld into the commit above, joining messages", Category: plugin.CategoryEdit, Context: "git-rebase", Priority: 1},
+		{ID: "rebase-fixup", Name: "Fixup", Description: "Meld into the commit above, dropping the message", Category: plugin.CategoryEdit, Context: "git-rebase", Priority: 1},
+		{ID: "rebase-reword", Name: "Reword", Description: "Edit the commit message", Category: plugin.CategoryEdit, Context: "git-rebase", Priority: 2},
+		{ID: "rebase-drop", Name: "Drop", Description: "Remove the commit", Category: plugin.CategoryEdit, Context: "git-rebase", Priority: 2},
+		{ID: "rebase-pick", Name: "Pick", Description: "Keep the commit", Category: plugin.CategoryEdit, Context: "git-rebase", Priority: 3},
+		{ID: "rebase-edit", Name: "Edit", Description: "Stop after the commit to amend it", Category: plugin.CategoryEdit, Context: "git-rebase", Priority: 3},
+		{ID: "move-down", Name: "Down", Description: "Move the commit down", Category: plugin.CategoryEdit, Context: "git-rebase", Priority: 3},
+		{ID: "move-up", Name: "Up", Description: "Move the commit up", Category: plugin.CategoryEdit, Context: "git-rebase", Priority: 3},
+		{ID: "cancel", Name: "Cancel", Description: "Close without rebasing", Category: plugin.CategoryActions, Context: "git-rebase", Priority: 2},
+		// git-rebase-reword context (reword message editor)
+		{ID: "save-message", Name: "Save", Description: "Save the new message", Category: plugin.CategoryEdit, Context: "git-rebase-reword", Priority: 1},
+		{ID: "cancel", Name: "Cancel", Description: "Keep the old message", Category: plugin.CategoryActions, Context: "git-rebase-reword", Priority: 1},
+		// git-rebase-status context (stopped rebase modal)
+		{ID: "continue-rebase", Name: "Continue", Description: "Continue the rebase", Category: plugin.CategoryGit, Context: "git-rebase-status", Priority: 1},
+		{ID: "abort-rebase", Name: "Abort", Description: "Abort the rebase", Category: plugin.CategoryGit, Context: "git-rebase-status", Priority: 1},
+		{ID: "resolve-conflicts", Name: "Resolve", Description: "Resolve conflicts in the conflict view", Category: plugin.CategoryGit, Context: "git-rebase-status", Priority: 2},
+		{ID: "dismiss", Name: "Dismiss", Description: "Close and keep the rebase stopped", Category: plugin.CategoryNavigation, Context: "git-rebase-status", Priority: 2},
+		// git-conflicts context (conflict resolution view)
+		{ID: "take-ours", Name: "Ours", Description: "Resolve the conflict with our side", Category: plugin.CategoryEdit, Context: "git-conflicts", Priority: 1},
+		{ID: "take-theirs", Name: "Theirs", Description: "Resolve the conflict with their side", Category: plugin.CategoryEdit, Context: "git-conflicts", Priority: 1},
+		{ID: "take-both", Name: "Both", Description: "Resolve the conflict with both sides", Category: plugin.CategoryEdit, Context: "git-conflicts", Priority: 1},
+		{ID: "edit-result", Name: "Edit", Description: "Edit the conflict's result", Category: plugin.CategoryEdit, Context: "git-conflicts", Priority: 2},
+		{ID: "save-resolution", Name: "Save", Description: "Write resolutions and stage the resolved file", Category: plugin.CategoryGit, Context: "git-conflicts", Priority: 1},
+		{ID: "continue-merge", Name: "Continue", Description: "Continue the merge or rebase", Category: plugin.CategoryGit, Context: "git-conflicts", Priority: 2},
+		{ID: "next-conflict", Name: "Next", Description: "Next conflict in the file", Category: plugin.CategoryNavigation, Context: "git-conflicts", Priority: 3},
+		{ID: "next-conflict-file", Name: "File", Description: "Next conflicted file", Category: plugin.CategoryNavigation, Context: "git-conflicts", Priority: 3},
+		{ID: "reset-conflict", Name: "Reset", Description: "Mark the conflict unresolved", Category: plugin.CategoryEdit, Context: "git-conflicts", Priority: 4},
+		{ID: "take-file-ours", Name: "All ours", Description: "Take our version of the whole file", Category: plugin.CategoryGit, Context: "git-conflicts", Priority: 4},
+		{ID: "take-file-theirs", Name: "All theirs", Description: "Take their version of the whole file", Category: plugin.CategoryGit, Context: "git-conflicts", Priority: 4},
+		{ID: "close", Name: "Close", Description: "Close the conflict view", Category: plugin.CategoryNavigation, Context: "git-conflicts", Priority: 2},
+		// git-conflicts-edit context (editing a conflict's result)
+		{ID: "apply-edit", Name: "Apply", Description: "Use the edited lines as the result", Category: plugin.CategoryEdit, Context: "git-conflicts-edit", Priority: 1},
+		{ID: "cancel", Name: "Cancel", Description: "Discard the edit", Category: plugin.CategoryActions, Context: "git-conflicts-edit", Priority: 1},
+		// git-cherry-pick context (cherry-pick target picker)
+		{ID: "confirm", Name: "Pick", Description: "Cherry-pick onto the selected branch", Category: plugin.CategoryGit, Context: "git-cherry-pick", Priority: 1},
+		{ID: "cancel", Name: "Cancel", Description: "Close without picking", Category: plugin.CategoryNavigation, Context: "git-cherry-pick", Priority: 2},
+		// git-revert context (revert confirmation)
+		{ID: "confirm", Name: "Revert", Description: "Commit the revert", Category: plugin.CategoryGit, Context: "git-revert", Priority: 1},
+		{ID: "cancel", Name: "Cancel", Description: "Close without reverting", Category: plugin.CategoryNavigation, Context: "git-revert", Priority: 2},
+		// git-reset context (reset mode and confirmation)
+		{ID: "confirm", Name: "Reset", Description: "Reset with the selected mode", Category: plugin.CategoryGit, Context: "git-reset", Priority: 1},
+		{ID: "reset-soft", Name: "Soft", Description: "Keep all changes staged", Category: plugin.CategoryGit, Context: "git-reset", Priority: 2},
+		{ID: "reset-mixed", Name: "Mixed", Description: "Keep all changes, unstaged", Category: plugin.CategoryGit, Context: "git-reset", Priority: 2},
+		{ID: "reset-hard", Name: "Hard", Description: "Discard all changes", Category: plugin.CategoryGit, Context: "git-reset", Priority: 2},
+		{ID: "cancel", Name: "Cancel", Description: "Close without resetting", Category: plugin.CategoryNavigation, Context: "git-reset", Priority: 2},
+		// git-reflog context (reflog browser)
+		{ID: "restore-entry", Name: "Restore", Description: "Reset HEAD or move the branch to the selected entry", Category: plugin.CategoryGit, Context: "git-reflog", Priority: 1},
+		{ID: "undo-action", Name: "Undo", Description: "Undo the last sidecar amend, discard or branch deletion", Category: plugin.CategoryGit, Context: "git-reflog", Priority: 1},
+		{ID: "next-ref", Name: "Ref", Description: "Show the next ref's reflog", Category: plugin.CategoryNavigation, Context: "git-reflog", Priority: 2},
+		{ID: "yank-id", Name: "YankID", Description: "Copy the entry's commit ID", Category: plugin.CategoryActions, Context: "git-reflog", Priority: 3},
+		{ID: "scroll", Name: "Scroll", Description: "Scroll the diff preview", Category: plugin.CategoryNavigation, Context: "git-reflog", Priority: 3},
+		{ID: "close", Name: "Close", Description: "Close the reflog", Category: plugin.CategoryNavigation, Context: "git-reflog", Priority: 2},
+		// git-undo context (undo confirmation)
+		{ID: "confirm", Name: "Undo", Description: "Undo the action", Category: plugin.CategoryGit, Context: "git-undo", Priority: 1},
+		{ID: "cancel", Name: "Cancel", Description: "Keep the action", Category: plugin.CategoryNavigation, Context: "git-undo", Priority: 2},
+		// git-move-branch context (move branch confirmation)
+		{ID: "confirm", Name: "Move", Description: "Move the branch to the entry", Category: plugin.CategoryGit, Context: "git-move-branch", Priority: 1},
+		{ID: "cancel", Name: "Cancel", Description: "Keep the branch where it is", Category: plugin.CategoryNavigation, Context: "git-move-branch", Priority: 2},
 		// git-stash-pop context (stash pop confirmation modal)
 		{ID: "confirm-pop", Name: "Pop", Description: "Confirm stash pop", Category: plugin.CategoryGit, Context: "git-stash-pop", Priority: 1},
 		{ID: "dismiss", Name: "Cancel", Description: "Cancel stash pop", Category: plugin.CategoryNavigation, Context: "git-stash-pop", Priority: 2},
 	}
+	if p.rebaseState != nil {
+		// Only offered while a rebase is stopped
+		cmds = append(cmds,
+			plugin.Command{ID: "rebase-actions", Name: "Rebase", Description: "Continue or abort the stopped rebase", Category: plugin.CategoryGit, Context: "git-status", Priority: 1},
+			plugin.Command{ID: "rebase-actions", Name: "Rebase", Description: "Continue or abort the stopped rebase", Category: plugin.CategoryGit, Context: "git-status-commits", Priority: 1},
+		)
+	}
+	if p.hasConflicts() {
+		cmds = append(cmds,
+			plugin.Command{ID: "resolve-conflicts", Name: "Resolve", Description: "Resolve merge conflicts", Category: plugin.CategoryGit, Context: "git-status", Priority: 1},
+		)
+	}
+	if p.errorOfferResolve {
+		// Only offered while the error modal reports a stop on conflicts
+		cmds = append(cmds,
+			plugin.Command{ID: "resolve-conflicts", Name: "Resolve", Description: "Resolve conflicts in the conflict view", Category: plugin.CategoryGit, Context: "git-error", Priority: 1},
+			plugin.Command{ID: "abort-operation", Name: "Abort", Description: "Abort and restore the branch", Category: plugin.CategoryGit, Context: "git-error", Priority: 1},
+		)
+	}
+	return cmds
 }
 
 // FocusContext returns the current focus context.
@@ -1037,8 +1380,32 @@ func (p *Plugin) FocusContext() string {
 		return "git-pull-menu"
 	case ViewModePullConflict:
 		return "git-pull-conflict"
+	case ViewModeRebase:
+		if p.rebaseRewording {
+			return "git-rebase-reword"
+		}
+		return "git-rebase"
+	case ViewModeRebaseStatus:
+		return "git-rebase-status"
+	case ViewModeConflicts:
+		if p.conflictEditing {
+			return "git-conflicts-edit"
+		}
+		return "git-conflicts"
 	case ViewModeError:
 		return "git-error"
+	case ViewModeCherryPick:
+		return "git-cherry-pick"
+	case ViewModeRevert:
+		return "git-revert"
+	case ViewModeReset:
+		return "git-reset"
+	case ViewModeReflog:
+		return "git-reflog"
+	case ViewModeUndo:
+		return "git-undo"
+	case ViewModeMoveBranch:
+		return "git-move-branch"
 	case ViewModeConfirmStashPop:
 		return "git-stash-pop"
 	default:
@@ -1060,7 +1427,23 @@ func (p *Plugin) FocusContext() string {
 // ConsumesTextInput reports whether the plugin is currently in a mode where
 // printable keys should be treated as text input.
 func (p *Plugin) ConsumesTextInput() bool {
-	return p.viewMode == ViewModeCommit || p.historySearchMode || p.pathFilterMode
+	return p.viewMode == ViewModeCommit || p.historySearchMode || p.pathFilterMode ||
+		(p.viewMode == ViewModeRebase && p.rebaseRewording) ||
+		(p.viewMode == ViewModeConflicts && p.conflictEditing)
+}
+
+// CommandVariables exposes the selected file (the diffed file in the diff
+// view) to custom commands.
+func (p *Plugin) CommandVariables() map[string]string {
+	vars := make(map[string]string)
+	if p.viewMode == ViewModeDiff && p.diffFile != "" {
+		vars["file"] = p.diffFile
+	} else if p.tree != nil && !p.inNoRepoMode() {
+		if entries := p.tree.AllEntries(); p.cursor < len(entries) {
+			vars["file"] = entries[p.cursor].Path
+		}
+	}
+	return vars
 }
 
 // Diagnostics returns plugin health info.
@@ -1089,7 +1472,7 @@ func (p *Plugin) refresh() tea.Cmd {
 		if err := p.tree.Refresh(); err != nil {
 			return ErrorMsg{Err: err}
 		}
-		return RefreshDoneMsg{}
+		return RefreshDoneMsg{Rebase: GetRebaseState(p.repoRoot)}
 	}
 }
 
@@ -1193,7 +1576,7 @@ func countLines(s string) int {
 }
 
 // Message types
-type RefreshDoneMsg struct{}
+type RefreshDoneMsg struct{ Rebase *RebaseState }
 type WatchEventMsg struct{}
 type WatchStartedMsg struct{ Watcher *Watcher }
 type ErrorMsg struct{ Err error }
@@ -54,20 +54,27 @@ func (p *Plugin) autoLoadDiff() tea.Cmd {
 	if len(entries) == 0 || p.cursor >= len(entries) {
 		p.selectedDiffFile = ""
 		p.diffPaneParsedDiff = nil
+		p.diffPaneTarget = nil
 		return nil
 	}
 
 	entry := entries[p.cursor]
-	isNewFile := entry.Path != p.selectedDiffFile
+	// The staged and unstaged entries of a file show different diffs
+	isNewFile := entry.Path != p.selectedDiffFile ||
+		(p.diffPaneTarget != nil && entry.Staged != p.diffPaneTarget.Staged)
 	if !isNewFile && !p.forceNextDiffReload {
 		return nil // Already loaded
 	}
 
 	p.selectedDiffFile = entry.Path
+	p.diffPaneTarget = patchTargetFor(entry)
 	p.forceNextDiffReload = false
 	if isNewFile {
-		// Only reset scroll when switching to a different file
+		// Only reset scroll and line cursor when switching to a different file
 		p.diffPaneScroll = 0
+		if p.diffPaneParsedDiff != nil {
+			p.diffPaneParsedDiff.Selection = nil
+		}
 	}
 	// Clear commit preview when switching to file
 	p.previewCommit = nil
@@ -103,6 +110,7 @@ func (p *Plugin) autoLoadCommitPreview() tea.Cmd {
 	// Clear file diff when switching to commit
 	p.selectedDiffFile = ""
 	p.diffPaneParsedDiff = nil
+	p.diffPaneTarget = nil
 	p.previewCommitCursor = 0
 	p.previewCommitScroll = 0
 
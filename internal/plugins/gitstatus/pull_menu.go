@@ -19,6 +19,7 @@ const (
 	pullMenuModalWidth = 50 // Default modal width
 	pullMenuMinWidth   = 20 // Minimum modal width
 
+	pullConflictResolveID = "pull-conflict-resolve"
 	pullConflictAbortID   = "pull-conflict-abort"
 	pullConflictDismissID = "pull-conflict-dismiss"
 )
@@ -85,7 +86,7 @@ func (p *Plugin) ensurePullConflictModal() {
 		modal.WithWidth(modalW),
 		modal.WithVariant(modal.VariantDanger),
 		modal.WithHints(false),
-		modal.WithPrimaryAction(pullConflictAbortID),
+		modal.WithPrimaryAction(pullConflictResolveID),
 	).
 		AddSection(p.pullConflictSummarySection()).
 		AddSection(modal.Spacer()).
@@ -94,6 +95,7 @@ func (p *Plugin) ensurePullConflictModal() {
 		AddSection(p.pullConflictResolutionSection()).
 		AddSection(modal.Spacer()).
 		AddSection(modal.Buttons(
+			modal.Btn(" Resolve ", pullConflictResolveID, modal.BtnPrimary()),
 			modal.Btn(" Abort ", pullConflictAbortID, modal.BtnDanger()),
 			modal.Btn(" Dismiss ", pullConflictDismissID),
 		))
@@ -147,7 +149,7 @@ func (p *Plugin) pullConflictFilesSection() modal.Section {
 
 func (p *Plugin) pullConflictResolutionSection() modal.Section {
 	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
-		content := styles.Muted.Render("Resolve conflicts in your editor, then commit.")
+		content := styles.Muted.Render("Resolve conflicts here (r) or in your editor, then commit.")
 		return modal.RenderedSection{Content: content}
 	}, nil)
 }
@@ -0,0 +1,350 @@
+package gitstatus
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// RebaseAction is what an interactive rebase does with a commit.
+type RebaseAction int
+
+const (
+	RebasePick   RebaseAction = iota // Keep the commit
+	RebaseReword                     // Keep the commit with a new message
+	RebaseEdit                       // Stop after the commit to amend it
+	RebaseSquash                     // Meld into the commit above, joining messages
+	RebaseFixup                      // Meld into the commit above, dropping the message
+	RebaseDrop                       // Remove the commit
+)
+
+// String returns the todo command for the action.
+func (a RebaseAction) String() string {
+	switch a {
+	case RebaseReword:
+		return "reword"
+	case RebaseEdit:
+		return "edit"
+	case RebaseSquash:
+		return "squash"
+	case RebaseFixup:
+		return "fixup"
+	case RebaseDrop:
+		return "drop"
+	default:
+		return "pick"
+	}
+}
+
+// melds reports whether the action melds the commit into the one above it.
+func (a RebaseAction) melds() bool {
+	return a == RebaseSquash || a == RebaseFixup
+}
+
+var (
+	// ErrRebaseMerges is returned when the rebase range contains merge commits.
+	ErrRebaseMerges = errors.New("range contains merge commits, which the rebase editor cannot keep")
+	// ErrRebaseNotAncestor is returned when the commit is not on the current branch.
+	ErrRebaseNotAncestor = errors.New("commit is not an ancestor of HEAD")
+	// ErrRebaseNothingLeft is returned when every commit is dropped.
+	ErrRebaseNothingLeft = errors.New("every commit is dropped")
+	// ErrRebaseMeldFirst is returned when the first kept commit is a squash or
+	// fixup, which has no commit to meld into.
+	ErrRebaseMeldFirst = errors.New("the first commit cannot be squashed or fixed up")
+	// ErrRebaseEmptyMessage is returned when a reworded commit has no message.
+	ErrRebaseEmptyMessage = errors.New("reworded commit has an empty message")
+)
+
+// RebaseEntry is one commit of an interactive rebase todo list.
+type RebaseEntry struct {
+	Hash      string
+	ShortHash string
+	Subject   string
+	Message   string // Full message; the new message for reworded commits
+	Pushed    bool   // Commit exists upstream; rewriting it needs a force push
+	Action    RebaseAction
+}
+
+// RebasePlan is an interactive rebase of the commits after Base.
+type RebasePlan struct {
+	Base    string        // Commit the entries are replayed onto; empty to rebase from the root
+	Entries []RebaseEntry // Oldest first, in todo order
+}
+
+// LoadRebasePlan returns a plan that picks every commit from hash to HEAD.
+func LoadRebasePlan(workDir, hash string) (*RebasePlan, error) {
+	cmd := exec.Command("git", "merge-base", "--is-ancestor", hash, "HEAD")
+	cmd.Dir = workDir
+	if err := cmd.Run(); err != nil {
+		return nil, ErrRebaseNotAncestor
+	}
+
+	plan := &RebasePlan{}
+	rangeArg := "HEAD"
+	cmd = exec.Command("git", "rev-parse", "--verify", "-q", hash+"^")
+	cmd.Dir = workDir
+	if output, err := cmd.Output(); err == nil {
+		plan.Base = strings.TrimSpace(string(output))
+		rangeArg = plan.Base + "..HEAD"
+	}
+
+	// Format: hash\x00shorthash\x00parents\x00subject\x00body, records split by \x1e
+	cmd = exec.Command("git", "log", "--reverse", "--format=%H%x00%h%x00%P%x00%s%x00%B%x1e", rangeArg)
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range strings.Split(string(output), "\x1e") {
+		parts := strings.SplitN(strings.TrimLeft(record, "\n"), "\x00", 5)
+		if len(parts) < 5 {
+			continue
+		}
+		if len(strings.Fields(parts[2])) > 1 {
+			return nil, ErrRebaseMerges
+		}
+		plan.Entries = append(plan.Entries, RebaseEntry{
+			Hash:      parts[0],
+			ShortHash: parts[1],
+			Subject:   parts[3],
+			Message:   strings.TrimRight(parts[4], "\n"),
+		})
+	}
+	return plan, nil
+}
+
+// Validate checks that git can run the plan.
+func (p *RebasePlan) Validate() error {
+	kept := 0
+	for _, e := range p.Entries {
+		if e.Action == RebaseDrop {
+			continue
+		}
+		if kept == 0 && e.Action.melds() {
+			return ErrRebaseMeldFirst
+		}
+		if e.Action == RebaseReword && strings.TrimSpace(e.Message) == "" {
+			return fmt.Errorf("%w: %s", ErrRebaseEmptyMessage, e.ShortHash)
+		}
+		kept++
+	}
+	if kept == 0 {
+		return ErrRebaseNothingLeft
+	}
+	return nil
+}
+
+// Changed reports whether running the plan would rewrite any commit.
+func (p *RebasePlan) Changed(original []RebaseEntry) bool {
+	if len(p.Entries) != len(original) {
+		return true
+	}
+	for i, e := range p.Entries {
+		if e.Action != RebasePick || e.Hash != original[i].Hash {
+			return true
+		}
+	}
+	return false
+}
+
+// Todo returns the rebase todo list for the plan. msgFile returns the path
+// of the file holding the new message of entry i. Rewords are written as a
+// pick followed by an amend from that file, so git needs no editor.
+func (p *RebasePlan) Todo(msgFile func(i int) string) string {
+	var sb strings.Builder
+	for i, e := range p.Entries {
+		action := e.Action
+		if action == RebaseReword {
+			action = RebasePick
+		}
+		fmt.Fprintf(&sb, "%s %s %s\n", action, e.Hash, e.Subject)
+		if e.Action == RebaseReword {
+			fmt.Fprintf(&sb, "exec git commit --amend --only --allow-empty --quiet --cleanup=strip -F %s\n", shellQuote(msgFile(i)))
+		}
+	}
+	return sb.String()
+}
+
+// StartRebase runs the plan with git rebase -i, writing the todo list through
+// GIT_SEQUENCE_EDITOR. The rebase may stop at an edit step or a conflict, with
+// an error in the latter case; GetRebaseState tells where it stopped.
+func StartRebase(workDir string, plan *RebasePlan) error {
+	if err := plan.Validate(); err != nil {
+		return err
+	}
+	dir, err := rebaseFilesDir(workDir)
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	msgFile := func(i int) string {
+		return filepath.Join(dir, "message-"+strconv.Itoa(i))
+	}
+	for i, e := range plan.Entries {
+		if e.Action != RebaseReword {
+			continue
+		}
+		if err := os.WriteFile(msgFile(i), []byte(e.Message+"\n"), 0644); err != nil {
+			return err
+		}
+	}
+	todo := filepath.Join(dir, "git-rebase-todo")
+	if err := os.WriteFile(todo, []byte(plan.Todo(msgFile)), 0644); err != nil {
+		return err
+	}
+
+	args := []string{"rebase", "-i", "--no-autosquash"}
+	if plan.Base == "" {
+		args = append(args, "--root")
+	} else {
+		args = append(args, plan.Base)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(), "GIT_SEQUENCE_EDITOR=cp "+shellQuote(todo), "GIT_EDITOR=true")
+	return runRebaseCommand(workDir, cmd)
+}
+
+// ContinueRebase runs git rebase --continue, keeping the prepared message of
+// squashed and amended commits.
+func ContinueRebase(workDir string) error {
+	cmd := exec.Command("git", "rebase", "--continue")
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(), "GIT_EDITOR=true")
+	return runRebaseCommand(workDir, cmd)
+}
+
+// runRebaseCommand runs a rebase command, cleaning up the files written by
+// StartRebase once the rebase is over. Errors carry git's output as a
+// RemoteError so IsConflictError recognises conflicts.
+func runRebaseCommand(workDir string, cmd *exec.Cmd) error {
+	output, err := cmd.CombinedOutput()
+	if GetRebaseState(workDir) == nil {
+		CleanRebaseFiles(workDir)
+	}
+	if err != nil {
+		return &RemoteError{Output: string(output), Err: err}
+	}
+	return nil
+}
+
+// CleanRebaseFiles removes the todo list and messages written by StartRebase.
+func CleanRebaseFiles(workDir string) {
+	if dir, err := rebaseFilesDir(workDir); err == nil {
+		_ = os.RemoveAll(dir)
+	}
+}
+
+// rebaseFilesDir returns the directory StartRebase writes its files to.
+func rebaseFilesDir(workDir string) (string, error) {
+	return gitPath(workDir, "sidecar-rebase")
+}
+
+// gitPath resolves a path inside the repository's git directory.
+func gitPath(workDir, name string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--git-path", name)
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	path := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(workDir, path)
+	}
+	return path, nil
+}
+
+// RebaseState describes a rebase that stopped before finishing.
+type RebaseState struct {
+	Branch    string   // Branch being rebased, empty when detached
+	Step      int      // Todo step the rebase stopped at, 1-based
+	Total     int      // Number of todo steps
+	Command   string   // Todo line of the stopped step, e.g. "edit 1a2b3c Fix typo"
+	Conflicts []string // Files with unresolved conflicts
+}
+
+// Editing reports whether the rebase stopped at an edit step.
+func (s *RebaseState) Editing() bool {
+	fields := strings.Fields(s.Command)
+	return len(s.Conflicts) == 0 && len(fields) > 0 && (fields[0] == "edit" || fields[0] == "e")
+}
+
+// Summary returns a one-line description of where the rebase stopped.
+func (s *RebaseState) Summary() string {
+	step := ""
+	if s.Total > 0 {
+		step = fmt.Sprintf(" at %d/%d", s.Step, s.Total)
+	}
+	switch {
+	case len(s.Conflicts) > 0:
+		return fmt.Sprintf("Rebase stopped%s with conflicts in %d file(s)", step, len(s.Conflicts))
+	case s.Editing():
+		return "Rebase stopped" + step + " to edit a commit"
+	default:
+		return "Rebase stopped" + step
+	}
+}
+
+// GetRebaseState returns the state of the rebase in progress, or nil if there
+// is none.
+func GetRebaseState(workDir string) *RebaseState {
+	for _, name := range []string{"rebase-merge", "rebase-apply"} {
+		dir, err := gitPath(workDir, name)
+		if err != nil {
+			return nil
+		}
+		if _, err := os.Stat(dir); err != nil {
+			continue
+		}
+
+		read := func(file string) string {
+			data, _ := os.ReadFile(filepath.Join(dir, file))
+			return strings.TrimSpace(string(data))
+		}
+		state := &RebaseState{
+			Branch:    strings.TrimPrefix(read("head-name"), "refs/heads/"),
+			Conflicts: GetConflictedFiles(workDir),
+		}
+		if state.Branch == "detached HEAD" {
+			state.Branch = ""
+		}
+		if name == "rebase-merge" {
+			state.Step, _ = strconv.Atoi(read("msgnum"))
+			state.Total, _ = strconv.Atoi(read("end"))
+			state.Command = lastTodoLine(read("done"))
+		} else {
+			state.Step, _ = strconv.Atoi(read("next"))
+			state.Total, _ = strconv.Atoi(read("last"))
+		}
+		return state
+	}
+	return nil
+}
+
+// lastTodoLine returns the last command of a todo list.
+func lastTodoLine(todo string) string {
+	lines := strings.Split(todo, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return line
+		}
+	}
+	return ""
+}
+
+// shellQuote quotes a string for use as a single shell word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
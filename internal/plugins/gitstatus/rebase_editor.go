@@ -0,0 +1,878 @@
+package gitstatus
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/marcus/sidecar/internal/modal"
+	appmsg "github.com/marcus/sidecar/internal/msg"
+	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/styles"
+	"github.com/marcus/sidecar/internal/ui"
+)
+
+const (
+	rebaseItemPrefix = "rebase-item-"
+	rebaseStartID    = "rebase-start"
+
+	rebaseRewordMessageID = "rebase-reword-message"
+	rebaseRewordSaveID    = "rebase-reword-save"
+
+	rebaseContinueID = "rebase-continue"
+	rebaseResolveID  = "rebase-resolve"
+	rebaseAbortID    = "rebase-abort"
+	rebaseDismissID  = "rebase-dismiss"
+)
+
+func rebaseItemID(idx int) string {
+	return fmt.Sprintf("%s%d", rebaseItemPrefix, idx)
+}
+
+func parseRebaseItem(id string) (int, bool) {
+	if !strings.HasPrefix(id, rebaseItemPrefix) {
+		return 0, false
+	}
+	idx, err := strconv.Atoi(strings.TrimPrefix(id, rebaseItemPrefix))
+	if err != nil {
+		return 0, false
+	}
+	return idx, true
+}
+
+// rebaseActionKeys maps editor keys to todo actions.
+var rebaseActionKeys = map[string]RebaseAction{
+	"p": RebasePick,
+	"r": RebaseReword,
+	"e": RebaseEdit,
+	"s": RebaseSquash,
+	"f": RebaseFixup,
+	"d": RebaseDrop,
+}
+
+// RebasePlanLoadedMsg is sent when the commits for the rebase editor are loaded.
+type RebasePlanLoadedMsg struct {
+	Epoch uint64
+	Plan  *RebasePlan
+	Err   error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m RebasePlanLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// RebaseDoneMsg is sent when starting, continuing or aborting a rebase
+// finishes or stops.
+type RebaseDoneMsg struct {
+	Op      string       // "start", "continue" or "abort"
+	Commits int          // Commits in the plan, for "start"
+	State   *RebaseState // Where the rebase stopped, nil once it is over
+	Err     error
+}
+
+// openRebaseEditor loads the commits from the selected commit to HEAD into
+// the rebase editor.
+func (p *Plugin) openRebaseEditor() tea.Cmd {
+	if p.rebaseState != nil || p.rebaseRunning {
+		return appmsg.ShowToast("A rebase is in progress (R for actions)", 2*time.Second)
+	}
+	if p.historyFilterActive {
+		return appmsg.ShowToast("Clear the history filter before rebasing", 2*time.Second)
+	}
+	commits := p.activeCommits()
+	idx := p.selectedCommitIndex()
+	if idx < 0 || idx >= len(commits) {
+		return nil
+	}
+
+	hash := commits[idx].Hash
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		plan, err := LoadRebasePlan(workDir, hash)
+		return RebasePlanLoadedMsg{Epoch: epoch, Plan: plan, Err: err}
+	}
+}
+
+// handleRebasePlanLoaded opens the editor on a loaded plan.
+func (p *Plugin) handleRebasePlanLoaded(msg RebasePlanLoadedMsg) tea.Cmd {
+	if msg.Err != nil {
+		p.showErrorModal("Rebase", msg.Err)
+		return nil
+	}
+	pushed := make(map[string]bool, len(p.recentCommits))
+	for _, c := range p.recentCommits {
+		pushed[c.Hash] = c.Pushed
+	}
+	for i := range msg.Plan.Entries {
+		msg.Plan.Entries[i].Pushed = pushed[msg.Plan.Entries[i].Hash]
+	}
+
+	p.rebasePlan = msg.Plan
+	p.rebaseOriginal = append([]RebaseEntry(nil), msg.Plan.Entries...)
+	p.rebaseCursor = 0
+	p.rebaseError = ""
+	p.rebaseRewording = false
+	p.rebaseReturnMode = p.viewMode
+	p.viewMode = ViewModeRebase
+	p.clearRebaseModal()
+	return nil
+}
+
+// updateRebase handles key events in the rebase editor.
+func (p *Plugin) updateRebase(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	if p.rebaseRewording {
+		return p.updateRebaseReword(msg)
+	}
+	p.ensureRebaseModal()
+	if p.rebaseModal == nil || p.rebasePlan == nil {
+		return p, nil
+	}
+
+	key := msg.String()
+	if action, ok := rebaseActionKeys[key]; ok {
+		p.setRebaseAction(action)
+		return p, nil
+	}
+
+	switch key {
+	case "esc", "q":
+		p.closeRebaseEditor()
+		return p, nil
+	case "j", "down":
+		p.moveRebaseCursor(1)
+		return p, nil
+	case "k", "up":
+		p.moveRebaseCursor(-1)
+		return p, nil
+	case "g":
+		p.rebaseCursor = 0
+		return p, nil
+	case "G":
+		p.rebaseCursor = len(p.rebasePlan.Entries) - 1
+		return p, nil
+	case "J", "shift+down":
+		p.moveRebaseEntry(1)
+		return p, nil
+	case "K", "shift+up":
+		p.moveRebaseEntry(-1)
+		return p, nil
+	case "enter", "ctrl+s":
+		return p, p.startRebase()
+	}
+
+	action, cmd := p.rebaseModal.HandleKey(msg)
+	switch action {
+	case "cancel":
+		p.closeRebaseEditor()
+		return p, nil
+	case rebaseStartID:
+		return p, p.startRebase()
+	}
+	return p, cmd
+}
+
+// setRebaseAction sets the action of the commit under the cursor. Reword
+// opens the message editor first.
+func (p *Plugin) setRebaseAction(action RebaseAction) {
+	entry := &p.rebasePlan.Entries[p.rebaseCursor]
+	p.rebaseError = ""
+	if action == RebaseReword {
+		p.initRebaseMessage(entry.Message)
+		p.rebaseRewording = true
+		return
+	}
+	entry.Action = action
+	entry.Message = p.originalRebaseMessage(entry.Hash)
+}
+
+// originalRebaseMessage returns the message a commit had when the editor opened.
+func (p *Plugin) originalRebaseMessage(hash string) string {
+	for _, e := range p.rebaseOriginal {
+		if e.Hash == hash {
+			return e.Message
+		}
+	}
+	return ""
+}
+
+func (p *Plugin) moveRebaseCursor(delta int) {
+	n := len(p.rebasePlan.Entries)
+	p.rebaseCursor += delta
+	if p.rebaseCursor >= n {
+		p.rebaseCursor = n - 1
+	}
+	if p.rebaseCursor < 0 {
+		p.rebaseCursor = 0
+	}
+}
+
+// moveRebaseEntry swaps the commit under the cursor with its neighbour.
+func (p *Plugin) moveRebaseEntry(delta int) {
+	entries := p.rebasePlan.Entries
+	to := p.rebaseCursor + delta
+	if to < 0 || to >= len(entries) {
+		return
+	}
+	entries[p.rebaseCursor], entries[to] = entries[to], entries[p.rebaseCursor]
+	p.rebaseCursor = to
+	p.rebaseError = ""
+}
+
+// startRebase closes the editor and runs the plan.
+func (p *Plugin) startRebase() tea.Cmd {
+	plan := p.rebasePlan
+	if !plan.Changed(p.rebaseOriginal) {
+		p.closeRebaseEditor()
+		return appmsg.ShowToast("Nothing to rebase", 2*time.Second)
+	}
+	if err := plan.Validate(); err != nil {
+		p.rebaseError = err.Error()
+		return nil
+	}
+
+	p.closeRebaseEditor()
+	p.rebaseRunning = true
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		err := StartRebase(workDir, plan)
+		return RebaseDoneMsg{Op: "start", Commits: len(plan.Entries), State: GetRebaseState(workDir), Err: err}
+	}
+}
+
+func (p *Plugin) closeRebaseEditor() {
+	p.viewMode = p.rebaseReturnMode
+	p.rebasePlan = nil
+	p.rebaseOriginal = nil
+	p.rebaseRewording = false
+	p.clearRebaseModal()
+}
+
+func (p *Plugin) clearRebaseModal() {
+	p.rebaseModal = nil
+	p.rebaseModalWidth = 0
+	p.rebaseRewordModal = nil
+	p.rebaseRewordWidth = 0
+}
+
+// rebaseModalWidthForContent returns the editor width, wide enough for the
+// longest subject.
+func (p *Plugin) rebaseModalWidthForContent() int {
+	modalW := 60
+	if p.rebasePlan != nil {
+		for _, e := range p.rebasePlan.Entries {
+			if w := len(e.Subject) + 24; w > modalW {
+				modalW = w
+			}
+		}
+	}
+	if modalW > p.width-10 {
+		modalW = p.width - 10
+	}
+	if modalW < 30 {
+		modalW = 30
+	}
+	return modalW
+}
+
+// ensureRebaseModal builds/rebuilds the rebase editor modal.
+func (p *Plugin) ensureRebaseModal() {
+	modalW := p.rebaseModalWidthForContent()
+	if p.rebaseModal != nil && p.rebaseModalWidth == modalW {
+		return
+	}
+	p.rebaseModalWidth = modalW
+
+	p.rebaseModal = modal.New("Interactive Rebase",
+		modal.WithWidth(modalW),
+		modal.WithHints(false),
+		modal.WithPrimaryAction(rebaseStartID),
+	).
+		AddSection(p.rebaseSummarySection()).
+		AddSection(modal.Spacer()).
+		AddSection(p.rebaseListSection()).
+		AddSection(modal.Spacer()).
+		AddSection(p.rebaseStatusLineSection()).
+		AddSection(p.rebaseHintsSection())
+}
+
+func (p *Plugin) rebaseSummarySection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		if p.rebasePlan == nil {
+			return modal.RenderedSection{}
+		}
+		onto := "the root commit"
+		if p.rebasePlan.Base != "" {
+			onto = shortHash(p.rebasePlan.Base)
+		}
+		content := styles.Muted.Render(fmt.Sprintf("%d commit(s) onto %s, oldest first", len(p.rebasePlan.Entries), onto))
+		return modal.RenderedSection{Content: content}
+	}, nil)
+}
+
+func (p *Plugin) rebaseListSection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		if p.rebasePlan == nil {
+			return modal.RenderedSection{}
+		}
+		entries := p.rebasePlan.Entries
+		maxVisible := p.rebaseMaxVisible()
+		start := 0
+		if p.rebaseCursor >= maxVisible {
+			start = p.rebaseCursor - maxVisible + 1
+		}
+		end := start + maxVisible
+		if end > len(entries) {
+			end = len(entries)
+		}
+
+		var sb strings.Builder
+		focusables := make([]modal.FocusableInfo, 0, end-start)
+		for i := start; i < end; i++ {
+			itemID := rebaseItemID(i)
+			line := p.renderRebaseEntry(entries[i], i == p.rebaseCursor || itemID == hoverID, contentWidth)
+			if i > start {
+				sb.WriteString("\n")
+			}
+			sb.WriteString(line)
+			focusables = append(focusables, modal.FocusableInfo{
+				ID:      itemID,
+				OffsetX: 0,
+				OffsetY: i - start,
+				Width:   ansi.StringWidth(line),
+				Height:  1,
+			})
+		}
+		content := sb.String()
+		if len(entries) > maxVisible {
+			content += "\n" + styles.Muted.Render(fmt.Sprintf("  %d/%d commits", p.rebaseCursor+1, len(entries)))
+		}
+		return modal.RenderedSection{Content: content, Focusables: focusables}
+	}, p.rebaseListUpdate)
+}
+
+func (p *Plugin) rebaseListUpdate(msg tea.Msg, focusID string) (string, tea.Cmd) {
+	if _, ok := parseRebaseItem(focusID); !ok {
+		return "", nil
+	}
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return "", nil
+	}
+	if keyMsg.String() == "enter" {
+		return rebaseStartID, nil
+	}
+	return "", nil
+}
+
+// rebaseActionStyle returns the style for a todo action in the editor.
+func rebaseActionStyle(action RebaseAction) lipgloss.Style {
+	switch action {
+	case RebaseReword, RebaseEdit:
+		return styles.StatusModified
+	case RebaseSquash, RebaseFixup:
+		return styles.StatusInProgress
+	case RebaseDrop:
+		return styles.StatusDeleted
+	default:
+		return styles.StatusStaged
+	}
+}
+
+// renderRebaseEntry renders one commit of the rebase editor.
+func (p *Plugin) renderRebaseEntry(e RebaseEntry, selected bool, width int) string {
+	action := fmt.Sprintf("%-6s", e.Action)
+	subject := e.Subject
+	if e.Action == RebaseReword {
+		subject = strings.SplitN(e.Message, "\n", 2)[0]
+	}
+	marker := " "
+	if e.Pushed {
+		marker = "↑"
+	}
+	avail := width - 20
+	if avail < 10 {
+		avail = 10
+	}
+	subject = truncateStr(subject, avail)
+
+	if selected {
+		line := fmt.Sprintf(" %s %s %s %s", action, marker, e.ShortHash, subject)
+		if pad := width - ansi.StringWidth(line); pad > 0 {
+			line += strings.Repeat(" ", pad)
+		}
+		return styles.ListItemSelected.Render(line)
+	}
+
+	subjectStyle := styles.Body
+	if e.Action == RebaseDrop {
+		subjectStyle = styles.Muted.Strikethrough(true)
+	}
+	return fmt.Sprintf(" %s %s %s %s",
+		rebaseActionStyle(e.Action).Render(action),
+		styles.Muted.Render(marker),
+		styles.Code.Render(e.ShortHash),
+		subjectStyle.Render(subject))
+}
+
+// rebaseStatusLineSection shows validation errors and the force push warning.
+func (p *Plugin) rebaseStatusLineSection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		if p.rebasePlan == nil {
+			return modal.RenderedSection{}
+		}
+		var lines []string
+		if p.rebaseError != "" {
+			lines = append(lines, styles.StatusDeleted.Render("✗ "+p.rebaseError))
+		}
+		pushed := 0
+		for _, e := range p.rebasePlan.Entries {
+			if e.Pushed {
+				pushed++
+			}
+		}
+		if pushed > 0 {
+			lines = append(lines, styles.StatusModified.Render(fmt.Sprintf("↑ %d commit(s) already pushed; a force push will be needed", pushed)))
+		}
+		if len(lines) == 0 {
+			return modal.RenderedSection{}
+		}
+		return modal.RenderedSection{Content: strings.Join(lines, "\n") + "\n"}
+	}, nil)
+}
+
+func (p *Plugin) rebaseHintsSection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		hints := []string{
+			"p pick  r reword  e edit  s squash  f fixup  d drop",
+			"J/K move  Enter start rebase  Esc cancel",
+			"Squash and fixup meld into the commit above",
+		}
+		return modal.RenderedSection{Content: styles.Muted.Render(strings.Join(hints, "\n"))}
+	}, nil)
+}
+
+func (p *Plugin) rebaseMaxVisible() int {
+	maxVisible := 20
+	if p.height-14 < maxVisible {
+		maxVisible = p.height - 14
+	}
+	if maxVisible < 5 {
+		maxVisible = 5
+	}
+	return maxVisible
+}
+
+// renderRebaseEditor renders the rebase editor, or the reword modal over it.
+func (p *Plugin) renderRebaseEditor() string {
+	background := p.renderThreePaneView()
+
+	if p.rebaseRewording {
+		p.ensureRebaseRewordModal()
+		modalContent := p.rebaseRewordModal.Render(p.width, p.height, p.mouseHandler)
+		return ui.OverlayModal(background, modalContent, p.width, p.height)
+	}
+
+	p.ensureRebaseModal()
+	if p.rebaseModal == nil {
+		return background
+	}
+	modalContent := p.rebaseModal.Render(p.width, p.height, p.mouseHandler)
+	return ui.OverlayModal(background, modalContent, p.width, p.height)
+}
+
+// handleRebaseMouse processes mouse events in the rebase editor.
+func (p *Plugin) handleRebaseMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	if p.rebaseRewording {
+		p.ensureRebaseRewordModal()
+		switch p.rebaseRewordModal.HandleMouse(msg, p.mouseHandler) {
+		case rebaseRewordSaveID:
+			p.saveRebaseReword()
+		case "cancel":
+			p.rebaseRewording = false
+		}
+		return p, nil
+	}
+
+	p.ensureRebaseModal()
+	if p.rebaseModal == nil || p.rebasePlan == nil {
+		return p, nil
+	}
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		p.moveRebaseCursor(-1)
+		return p, nil
+	case tea.MouseButtonWheelDown:
+		p.moveRebaseCursor(1)
+		return p, nil
+	}
+
+	action := p.rebaseModal.HandleMouse(msg, p.mouseHandler)
+	if action == "cancel" {
+		p.closeRebaseEditor()
+		return p, nil
+	}
+	if idx, ok := parseRebaseItem(action); ok {
+		p.rebaseCursor = idx
+	}
+	return p, nil
+}
+
+// initRebaseMessage prepares the reword textarea with a commit's message.
+func (p *Plugin) initRebaseMessage(message string) {
+	p.rebaseMessage = textarea.New()
+	p.rebaseMessage.SetValue(message)
+	p.rebaseMessage.Placeholder = "Commit message..."
+	p.rebaseMessage.FocusedStyle.Placeholder = lipgloss.NewStyle().Foreground(styles.TextSecondary)
+	p.rebaseMessage.Focus()
+	p.rebaseMessage.CharLimit = 0
+	p.rebaseMessage.SetWidth(p.commitModalWidth() - 8)
+	p.rebaseMessage.SetHeight(6)
+	p.rebaseRewordModal = nil
+	p.rebaseRewordWidth = 0
+}
+
+// ensureRebaseRewordModal builds/rebuilds the reword message modal.
+func (p *Plugin) ensureRebaseRewordModal() {
+	modalW := p.commitModalWidth()
+	if p.rebaseRewordModal != nil && p.rebaseRewordWidth == modalW {
+		return
+	}
+	p.rebaseRewordWidth = modalW
+
+	title := "Reword"
+	if p.rebasePlan != nil && p.rebaseCursor < len(p.rebasePlan.Entries) {
+		title += " " + p.rebasePlan.Entries[p.rebaseCursor].ShortHash
+	}
+	p.rebaseRewordModal = modal.New(title,
+		modal.WithWidth(modalW),
+		modal.WithHints(false),
+		modal.WithPrimaryAction(rebaseRewordSaveID),
+	).
+		AddSection(modal.Textarea(rebaseRewordMessageID, &p.rebaseMessage, 6)).
+		AddSection(modal.Text(styles.Muted.Render("Ctrl+S to save, Esc to cancel"))).
+		AddSection(modal.Buttons(
+			modal.Btn(" Save ", rebaseRewordSaveID),
+			modal.Btn(" Cancel ", "cancel"),
+		))
+}
+
+// updateRebaseReword handles key events while editing a reworded message.
+func (p *Plugin) updateRebaseReword(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	p.ensureRebaseRewordModal()
+	switch msg.String() {
+	case "ctrl+s":
+		p.saveRebaseReword()
+		return p, nil
+	case "esc":
+		p.rebaseRewording = false
+		return p, nil
+	}
+
+	focusID := p.rebaseRewordModal.FocusedID()
+	action, cmd := p.rebaseRewordModal.HandleKey(msg)
+	if action == rebaseRewordSaveID && focusID == rebaseRewordMessageID {
+		return p, cmd
+	}
+	switch action {
+	case rebaseRewordSaveID:
+		p.saveRebaseReword()
+	case "cancel":
+		p.rebaseRewording = false
+	}
+	return p, cmd
+}
+
+// saveRebaseReword marks the commit under the cursor as reworded with the
+// edited message.
+func (p *Plugin) saveRebaseReword() {
+	p.rebaseRewording = false
+	message := strings.TrimSpace(p.rebaseMessage.Value())
+	if message == "" {
+		p.rebaseError = "Commit message cannot be empty"
+		return
+	}
+	entry := &p.rebasePlan.Entries[p.rebaseCursor]
+	if message == strings.TrimSpace(p.originalRebaseMessage(entry.Hash)) {
+		entry.Action = RebasePick
+		return
+	}
+	entry.Action = RebaseReword
+	entry.Message = message
+}
+
+// handleRebaseDone reports a finished or stopped rebase.
+func (p *Plugin) handleRebaseDone(msg RebaseDoneMsg) tea.Cmd {
+	p.rebaseRunning = false
+	p.rebaseState = msg.State
+	cmds := []tea.Cmd{p.refresh(), p.loadRecentCommits()}
+
+	if msg.State != nil {
+		p.rebaseStatusError = ""
+		if msg.Err != nil && len(msg.State.Conflicts) == 0 {
+			p.rebaseStatusError = firstLine(msg.Err.Error())
+		}
+		p.openRebaseStatus()
+		return tea.Batch(cmds...)
+	}
+	if msg.Err != nil {
+		p.showErrorModal("Rebase Failed", msg.Err)
+		return tea.Batch(cmds...)
+	}
+
+	var text string
+	switch msg.Op {
+	case "start":
+		text = fmt.Sprintf("Rebased %d commit(s)", msg.Commits)
+	case "abort":
+		text = "Rebase aborted"
+	default:
+		text = "Rebase complete"
+	}
+	return tea.Batch(append(cmds, appmsg.ShowToast(text, 2*time.Second))...)
+}
+
+// openRebaseStatus shows the continue/abort modal for the stopped rebase.
+func (p *Plugin) openRebaseStatus() {
+	if p.viewMode != ViewModeRebaseStatus {
+		p.rebaseStatusReturnMode = ViewModeStatus
+		if p.viewMode == ViewModeDiff {
+			p.rebaseStatusReturnMode = ViewModeDiff
+		}
+	}
+	p.viewMode = ViewModeRebaseStatus
+	p.clearRebaseStatusModal()
+}
+
+func (p *Plugin) closeRebaseStatus() {
+	p.viewMode = p.rebaseStatusReturnMode
+	p.clearRebaseStatusModal()
+}
+
+func (p *Plugin) clearRebaseStatusModal() {
+	p.rebaseStatusModal = nil
+	p.rebaseStatusWidth = 0
+}
+
+// updateRebaseStatus handles key events in the stopped rebase modal.
+func (p *Plugin) updateRebaseStatus(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	p.ensureRebaseStatusModal()
+	if p.rebaseStatusModal == nil {
+		return p, nil
+	}
+
+	switch msg.String() {
+	case "c":
+		return p, p.continueRebase()
+	case "r":
+		if p.rebaseState != nil && len(p.rebaseState.Conflicts) > 0 {
+			return p, p.resolveRebaseConflicts()
+		}
+		return p, nil
+	case "a":
+		return p, p.abortRebase()
+	case "esc", "q":
+		p.closeRebaseStatus()
+		return p, nil
+	}
+
+	action, cmd := p.rebaseStatusModal.HandleKey(msg)
+	return p, p.rebaseStatusAction(action, cmd)
+}
+
+// handleRebaseStatusMouse processes mouse events in the stopped rebase modal.
+func (p *Plugin) handleRebaseStatusMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	p.ensureRebaseStatusModal()
+	if p.rebaseStatusModal == nil {
+		return p, nil
+	}
+	action := p.rebaseStatusModal.HandleMouse(msg, p.mouseHandler)
+	return p, p.rebaseStatusAction(action, nil)
+}
+
+func (p *Plugin) rebaseStatusAction(action string, cmd tea.Cmd) tea.Cmd {
+	switch action {
+	case rebaseContinueID:
+		return p.continueRebase()
+	case rebaseResolveID:
+		return p.resolveRebaseConflicts()
+	case rebaseAbortID:
+		return p.abortRebase()
+	case "cancel", rebaseDismissID:
+		p.closeRebaseStatus()
+		return nil
+	}
+	return cmd
+}
+
+// continueRebase runs git rebase --continue.
+func (p *Plugin) continueRebase() tea.Cmd {
+	p.closeRebaseStatus()
+	p.rebaseRunning = true
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		err := ContinueRebase(workDir)
+		return RebaseDoneMsg{Op: "continue", State: GetRebaseState(workDir), Err: err}
+	}
+}
+
+// resolveRebaseConflicts opens the conflict view on the stopped rebase.
+func (p *Plugin) resolveRebaseConflicts() tea.Cmd {
+	p.closeRebaseStatus()
+	return p.openConflicts("")
+}
+
+// abortRebase runs git rebase --abort, restoring the branch.
+func (p *Plugin) abortRebase() tea.Cmd {
+	p.closeRebaseStatus()
+	p.rebaseRunning = true
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		err := AbortRebase(workDir)
+		state := GetRebaseState(workDir)
+		if state == nil {
+			CleanRebaseFiles(workDir)
+		}
+		return RebaseDoneMsg{Op: "abort", State: state, Err: err}
+	}
+}
+
+// ensureRebaseStatusModal builds/rebuilds the stopped rebase modal.
+func (p *Plugin) ensureRebaseStatusModal() {
+	modalW := ui.ModalWidthMedium
+	if modalW > p.width-4 {
+		modalW = p.width - 4
+	}
+	if modalW < pullMenuMinWidth {
+		modalW = pullMenuMinWidth
+	}
+	if p.rebaseStatusModal != nil && p.rebaseStatusWidth == modalW {
+		return
+	}
+	p.rebaseStatusWidth = modalW
+
+	variant := modal.VariantDefault
+	buttons := []modal.ButtonDef{modal.Btn(" Continue ", rebaseContinueID, modal.BtnPrimary())}
+	if p.rebaseState != nil && len(p.rebaseState.Conflicts) > 0 {
+		variant = modal.VariantDanger
+		buttons = append(buttons, modal.Btn(" Resolve ", rebaseResolveID))
+	}
+	buttons = append(buttons,
+		modal.Btn(" Abort ", rebaseAbortID, modal.BtnDanger()),
+		modal.Btn(" Dismiss ", rebaseDismissID),
+	)
+	p.rebaseStatusModal = modal.New("Rebase",
+		modal.WithWidth(modalW),
+		modal.WithVariant(variant),
+		modal.WithHints(false),
+		modal.WithPrimaryAction(rebaseContinueID),
+	).
+		AddSection(p.rebaseStatusSummarySection()).
+		AddSection(modal.Spacer()).
+		AddSection(p.rebaseStatusDetailSection()).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Buttons(buttons...))
+}
+
+func (p *Plugin) rebaseStatusSummarySection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		state := p.rebaseState
+		if state == nil {
+			return modal.RenderedSection{Content: styles.Muted.Render("No rebase in progress.")}
+		}
+		content := styles.Body.Render(state.Summary())
+		if state.Branch != "" {
+			content += "\n" + styles.Muted.Render("Rebasing "+state.Branch)
+		}
+		if state.Command != "" {
+			content += "\n" + styles.Muted.Render(truncateStr(state.Command, contentWidth))
+		}
+		return modal.RenderedSection{Content: content}
+	}, nil)
+}
+
+func (p *Plugin) rebaseStatusDetailSection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		state := p.rebaseState
+		if state == nil {
+			return modal.RenderedSection{}
+		}
+		var sb strings.Builder
+		if p.rebaseStatusError != "" {
+			sb.WriteString(styles.StatusDeleted.Render(truncateStr("✗ "+p.rebaseStatusError, contentWidth)))
+			sb.WriteString("\n")
+		}
+		maxFiles := 8
+		for i, f := range state.Conflicts {
+			if i >= maxFiles {
+				sb.WriteString(styles.Muted.Render(fmt.Sprintf("  ... and %d more", len(state.Conflicts)-maxFiles)))
+				sb.WriteString("\n")
+				break
+			}
+			sb.WriteString(styles.StatusModified.Render("  U " + f))
+			sb.WriteString("\n")
+		}
+		switch {
+		case len(state.Conflicts) > 0:
+			sb.WriteString(styles.Muted.Render("Resolve the conflicts (r) and stage the files, then continue."))
+		case state.Editing():
+			sb.WriteString(styles.Muted.Render("Amend the commit or stage changes, then continue."))
+		default:
+			sb.WriteString(styles.Muted.Render("Continue to resume the rebase."))
+		}
+		return modal.RenderedSection{Content: sb.String()}
+	}, nil)
+}
+
+// renderRebaseStatus renders the stopped rebase modal.
+func (p *Plugin) renderRebaseStatus() string {
+	background := p.renderThreePaneView()
+
+	p.ensureRebaseStatusModal()
+	if p.rebaseStatusModal == nil {
+		return background
+	}
+	modalContent := p.rebaseStatusModal.Render(p.width, p.height, p.mouseHandler)
+	return ui.OverlayModal(background, modalContent, p.width, p.height)
+}
+
+// rebaseIndicator returns the sidebar status line for a running or stopped
+// rebase, or "" when there is none.
+func (p *Plugin) rebaseIndicator() string {
+	switch {
+	case p.rebaseRunning:
+		return styles.StatusInProgress.Render("Rebasing...")
+	case p.rebaseState != nil:
+		text := "Rebase stopped"
+		if p.rebaseState.Total > 0 {
+			text += fmt.Sprintf(" %d/%d", p.rebaseState.Step, p.rebaseState.Total)
+		}
+		if n := len(p.rebaseState.Conflicts); n > 0 {
+			text += fmt.Sprintf(", %d conflict(s)", n)
+		}
+		return styles.StatusModified.Render(truncateStr(text+" (R)", p.sidebarWidth-4))
+	}
+	return ""
+}
+
+// shortHash returns the abbreviated form of a full commit hash.
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+// firstLine returns the first non-empty line of s.
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
@@ -0,0 +1,294 @@
+package gitstatus
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/mouse"
+)
+
+// initRebaseRepo creates a repo with one commit per subject. Each commit adds
+// a file named after its subject unless files maps the subject to another
+// file and content.
+func initRebaseRepo(t *testing.T, subjects []string, files map[string][2]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	git("config", "user.email", "test@example.com")
+	git("config", "user.name", "Test")
+	for _, subject := range subjects {
+		name, content := subject+".txt", subject+"\n"
+		if f, ok := files[subject]; ok {
+			name, content = f[0], f[1]
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		git("add", name)
+		git("commit", "-q", "-m", subject)
+	}
+	return dir
+}
+
+// logSubjects returns the commit subjects of HEAD, newest first.
+func logSubjects(t *testing.T, dir string) []string {
+	t.Helper()
+	out, err := exec.Command("git", "-C", dir, "log", "--format=%s").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Fields(string(out))
+}
+
+func commitHash(t *testing.T, dir, rev string) string {
+	t.Helper()
+	out, err := exec.Command("git", "-C", dir, "rev-parse", rev).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestLoadRebasePlan(t *testing.T) {
+	dir := initRebaseRepo(t, []string{"a", "b", "c"}, nil)
+
+	plan, err := LoadRebasePlan(dir, commitHash(t, dir, "HEAD~1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Base != commitHash(t, dir, "HEAD~2") {
+		t.Errorf("base = %s, want HEAD~2", plan.Base)
+	}
+	if len(plan.Entries) != 2 || plan.Entries[0].Subject != "b" || plan.Entries[1].Subject != "c" {
+		t.Fatalf("entries = %+v, want b, c", plan.Entries)
+	}
+	if plan.Entries[0].Message != "b" {
+		t.Errorf("message = %q, want %q", plan.Entries[0].Message, "b")
+	}
+
+	// From the root commit there is no base
+	plan, err = LoadRebasePlan(dir, commitHash(t, dir, "HEAD~2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Base != "" || len(plan.Entries) != 3 {
+		t.Errorf("root plan: base %q, %d entries, want no base and 3 entries", plan.Base, len(plan.Entries))
+	}
+}
+
+func TestRebasePlan_Validate(t *testing.T) {
+	entries := func(actions ...RebaseAction) *RebasePlan {
+		plan := &RebasePlan{}
+		for _, a := range actions {
+			plan.Entries = append(plan.Entries, RebaseEntry{Message: "m", Action: a})
+		}
+		return plan
+	}
+
+	tests := []struct {
+		name string
+		plan *RebasePlan
+		want error
+	}{
+		{"picks", entries(RebasePick, RebaseSquash, RebaseFixup), nil},
+		{"squash first", entries(RebaseSquash, RebasePick), ErrRebaseMeldFirst},
+		{"fixup after drop", entries(RebaseDrop, RebaseFixup), ErrRebaseMeldFirst},
+		{"all dropped", entries(RebaseDrop, RebaseDrop), ErrRebaseNothingLeft},
+		{"empty reword", &RebasePlan{Entries: []RebaseEntry{{Action: RebaseReword, Message: " \n"}}}, ErrRebaseEmptyMessage},
+	}
+	for _, tt := range tests {
+		if err := tt.plan.Validate(); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestRebasePlan_Todo(t *testing.T) {
+	plan := &RebasePlan{Entries: []RebaseEntry{
+		{Hash: "aaa", Subject: "first", Action: RebaseReword},
+		{Hash: "bbb", Subject: "second", Action: RebaseFixup},
+	}}
+	todo := plan.Todo(func(i int) string { return "/tmp/it's/msg" })
+	want := "pick aaa first\n" +
+		"exec git commit --amend --only --allow-empty --quiet --cleanup=strip -F '/tmp/it'\"'\"'s/msg'\n" +
+		"fixup bbb second\n"
+	if todo != want {
+		t.Errorf("todo =\n%s\nwant\n%s", todo, want)
+	}
+}
+
+func TestStartRebase_SquashRewordDropAndReorder(t *testing.T) {
+	dir := initRebaseRepo(t, []string{"base", "a", "wip1", "wip2", "b", "junk"}, nil)
+
+	plan, err := LoadRebasePlan(dir, commitHash(t, dir, "HEAD~4"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a, wip1, wip2, b, junk -> b, a (squashed with the wips and reworded)
+	e := plan.Entries
+	e[0].Action, e[0].Message = RebaseReword, "feature\n\nsquashed"
+	e[1].Action = RebaseFixup
+	e[2].Action = RebaseFixup
+	e[4].Action = RebaseDrop
+	plan.Entries = []RebaseEntry{e[3], e[0], e[1], e[2], e[4]}
+
+	if err := StartRebase(dir, plan); err != nil {
+		t.Fatal(err)
+	}
+	if state := GetRebaseState(dir); state != nil {
+		t.Fatalf("rebase still in progress: %+v", state)
+	}
+	if got, want := strings.Join(logSubjects(t, dir), " "), "feature b base"; got != want {
+		t.Errorf("log = %q, want %q", got, want)
+	}
+	body, _ := exec.Command("git", "-C", dir, "log", "-1", "--format=%B").Output()
+	if strings.TrimSpace(string(body)) != "feature\n\nsquashed" {
+		t.Errorf("reworded message = %q", body)
+	}
+	for _, name := range []string{"a.txt", "wip1.txt", "wip2.txt", "b.txt"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("%s missing after rebase", name)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "junk.txt")); err == nil {
+		t.Error("dropped commit's file still exists")
+	}
+	if files, _ := rebaseFilesDir(dir); files != "" {
+		if _, err := os.Stat(files); err == nil {
+			t.Error("rebase files were not cleaned up")
+		}
+	}
+}
+
+func TestStartRebase_StopsAtEditAndContinues(t *testing.T) {
+	dir := initRebaseRepo(t, []string{"a", "b", "c"}, nil)
+
+	plan, err := LoadRebasePlan(dir, commitHash(t, dir, "HEAD~1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan.Entries[0].Action = RebaseEdit
+	if err := StartRebase(dir, plan); err != nil {
+		t.Fatal(err)
+	}
+	state := GetRebaseState(dir)
+	if state == nil || !state.Editing() || state.Step != 1 || state.Total != 2 {
+		t.Fatalf("state = %+v, want stopped to edit at 1/2", state)
+	}
+
+	if err := ContinueRebase(dir); err != nil {
+		t.Fatal(err)
+	}
+	if state := GetRebaseState(dir); state != nil {
+		t.Fatalf("rebase still in progress: %+v", state)
+	}
+	if got := strings.Join(logSubjects(t, dir), " "); got != "c b a" {
+		t.Errorf("log = %q, want %q", got, "c b a")
+	}
+}
+
+func TestStartRebase_ConflictStops(t *testing.T) {
+	dir := initRebaseRepo(t, []string{"a", "b", "c"}, map[string][2]string{
+		"b": {"shared.txt", "one\n"},
+		"c": {"shared.txt", "two\n"},
+	})
+
+	plan, err := LoadRebasePlan(dir, commitHash(t, dir, "HEAD~1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Drop b: c's change to shared.txt no longer applies
+	plan.Entries[0].Action = RebaseDrop
+	err = StartRebase(dir, plan)
+	if !IsConflictError(err) {
+		t.Fatalf("err = %v, want a conflict error", err)
+	}
+	state := GetRebaseState(dir)
+	if state == nil || len(state.Conflicts) != 1 || state.Conflicts[0] != "shared.txt" {
+		t.Fatalf("state = %+v, want a conflict in shared.txt", state)
+	}
+	if !strings.Contains(state.Summary(), "conflicts in 1 file") {
+		t.Errorf("summary = %q", state.Summary())
+	}
+
+	if err := AbortRebase(dir); err != nil {
+		t.Fatal(err)
+	}
+	if GetRebaseState(dir) != nil {
+		t.Error("rebase still in progress after abort")
+	}
+	if got := strings.Join(logSubjects(t, dir), " "); got != "c b a" {
+		t.Errorf("log after abort = %q, want %q", got, "c b a")
+	}
+}
+
+func TestRebaseEditorKeys(t *testing.T) {
+	entries := []RebaseEntry{
+		{Hash: "h1", ShortHash: "h1", Subject: "one", Message: "one"},
+		{Hash: "h2", ShortHash: "h2", Subject: "two", Message: "two"},
+		{Hash: "h3", ShortHash: "h3", Subject: "three", Message: "three"},
+	}
+	p := &Plugin{viewMode: ViewModeStatus, width: 100, height: 40, mouseHandler: mouse.NewHandler()}
+	p.handleRebasePlanLoaded(RebasePlanLoadedMsg{Plan: &RebasePlan{Base: "base", Entries: append([]RebaseEntry(nil), entries...)}})
+	if p.viewMode != ViewModeRebase || p.FocusContext() != "git-rebase" {
+		t.Fatalf("editor not open: mode %v, context %q", p.viewMode, p.FocusContext())
+	}
+
+	press := func(keys ...string) {
+		for _, k := range keys {
+			msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+			switch k {
+			case "enter":
+				msg = tea.KeyMsg{Type: tea.KeyEnter}
+			case "esc":
+				msg = tea.KeyMsg{Type: tea.KeyEsc}
+			case "ctrl+s":
+				msg = tea.KeyMsg{Type: tea.KeyCtrlS}
+			}
+			p.updateRebase(msg)
+		}
+	}
+
+	// Move "three" to the top and fix it up: it has nothing to meld into
+	press("G", "K", "K", "f", "enter")
+	if p.rebasePlan.Entries[0].Hash != "h3" || p.rebasePlan.Entries[0].Action != RebaseFixup {
+		t.Fatalf("entries = %+v, want h3 fixup first", p.rebasePlan.Entries)
+	}
+	if p.rebaseError == "" || p.viewMode != ViewModeRebase {
+		t.Fatal("invalid plan should keep the editor open with an error")
+	}
+
+	// Reword "one"
+	press("j", "r")
+	if !p.rebaseRewording || !p.ConsumesTextInput() || p.FocusContext() != "git-rebase-reword" {
+		t.Fatal("reword should open the message editor")
+	}
+	p.rebaseMessage.SetValue("one, reworded")
+	press("ctrl+s")
+	if e := p.rebasePlan.Entries[1]; e.Action != RebaseReword || e.Message != "one, reworded" {
+		t.Errorf("entry = %+v, want reworded", e)
+	}
+
+	// Back to pick restores the message
+	press("p")
+	if e := p.rebasePlan.Entries[1]; e.Action != RebasePick || e.Message != "one" {
+		t.Errorf("entry = %+v, want picked with its old message", e)
+	}
+
+	press("esc")
+	if p.viewMode != ViewModeStatus || p.rebasePlan != nil {
+		t.Error("esc should close the editor")
+	}
+}
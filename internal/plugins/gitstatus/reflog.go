@@ -0,0 +1,98 @@
+package gitstatus
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// reflogMaxEntries caps how many entries of a reflog are loaded.
+const reflogMaxEntries = 300
+
+// ReflogEntry is one entry of a reflog: a position HEAD or a branch was at,
+// and the operation that moved it there.
+type ReflogEntry struct {
+	Selector  string // e.g. HEAD@{2}
+	Hash      string
+	ShortHash string
+	Op        string // Operation that wrote the entry, e.g. "commit (amend)", "reset"
+	Message   string // Rest of the reflog message, e.g. "moving to HEAD~1"
+	Subject   string // Subject of the commit the entry points at
+	Date      time.Time
+}
+
+// ParseReflogSubject splits a reflog message such as
+// "checkout: moving from main to fix" into its operation and detail.
+func ParseReflogSubject(s string) (op, message string) {
+	op, message, found := strings.Cut(s, ": ")
+	if !found {
+		return strings.TrimSuffix(s, ":"), ""
+	}
+	return op, message
+}
+
+// ReflogRefs returns the refs with a reflog to browse: HEAD, then the local
+// branches, the checked out one first.
+func ReflogRefs(workDir string) []string {
+	refs := []string{"HEAD"}
+	current := currentBranch(workDir)
+	if current != "" {
+		refs = append(refs, current)
+	}
+	for _, branch := range gitFileList(workDir, "for-each-ref", "--format=%(refname:short)", "refs/heads") {
+		if branch != current {
+			refs = append(refs, branch)
+		}
+	}
+	return refs
+}
+
+// currentBranch returns the checked out branch, or "" when HEAD is detached.
+func currentBranch(workDir string) string {
+	out, err := exec.Command("git", "-C", workDir, "symbolic-ref", "--quiet", "--short", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// LoadReflog returns the reflog of ref, newest entry first.
+func LoadReflog(workDir, ref string) ([]ReflogEntry, error) {
+	cmd := exec.Command("git", "reflog", "show", "-n", strconv.Itoa(reflogMaxEntries),
+		"--format=%gd%x00%H%x00%h%x00%gs%x00%s%x00%ct", ref, "--")
+	cmd.Dir = workDir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("reflog %s: %s", ref, strings.TrimSpace(string(out)))
+	}
+
+	var entries []ReflogEntry
+	for _, line := range strings.Split(string(out), "\n") {
+		parts := strings.Split(line, "\x00")
+		if len(parts) != 6 {
+			continue
+		}
+		e := ReflogEntry{Selector: parts[0], Hash: parts[1], ShortHash: parts[2], Subject: parts[4]}
+		e.Op, e.Message = ParseReflogSubject(parts[3])
+		if ts, err := strconv.ParseInt(parts[5], 10, 64); err == nil {
+			e.Date = time.Unix(ts, 0)
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// ReflogDiff returns the diff from the working tree to the commit hash,
+// i.e. what a hard reset to it would change in the current state.
+func ReflogDiff(workDir, hash string) (string, error) {
+	// -R swaps the prefixes too, so they are given swapped to read a/ and b/
+	cmd := exec.Command("git", "diff", "--no-color", "--no-ext-diff", "-R", "--src-prefix=b/", "--dst-prefix=a/", hash, "--")
+	cmd.Dir = workDir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("diff against %s: %w", shortHash(hash), err)
+	}
+	return string(out), nil
+}
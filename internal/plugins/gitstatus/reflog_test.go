@@ -0,0 +1,230 @@
+package gitstatus
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/mouse"
+	"github.com/marcus/sidecar/internal/plugin"
+)
+
+func TestParseReflogSubject(t *testing.T) {
+	tests := []struct {
+		subject, op, message string
+	}{
+		{"commit (amend): fix typo", "commit (amend)", "fix typo"},
+		{"checkout: moving from main to fix", "checkout", "moving from main to fix"},
+		{"reset: moving to HEAD~1", "reset", "moving to HEAD~1"},
+		{"rebase (finish): returning to refs/heads/main", "rebase (finish)", "returning to refs/heads/main"},
+		{"commit: subject: with colon", "commit", "subject: with colon"},
+		{"pull:", "pull", ""},
+	}
+	for _, tt := range tests {
+		op, message := ParseReflogSubject(tt.subject)
+		if op != tt.op || message != tt.message {
+			t.Errorf("ParseReflogSubject(%q) = %q, %q, want %q, %q", tt.subject, op, message, tt.op, tt.message)
+		}
+	}
+}
+
+func TestLoadReflog(t *testing.T) {
+	dir := initRebaseRepo(t, []string{"a", "b", "c"}, nil)
+	if _, err := ExecuteAmend(dir, "c amended"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ResetTo(dir, "HEAD~1", ResetHard); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := LoadReflog(dir, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 5 {
+		t.Fatalf("entries = %d, want 5: %+v", len(entries), entries)
+	}
+	if e := entries[0]; e.Selector != "HEAD@{0}" || e.Op != "reset" || e.Subject != "b" {
+		t.Errorf("newest entry = %+v, want the reset to b", e)
+	}
+	if e := entries[1]; e.Op != "commit (amend)" || e.Message != "c amended" || e.Date.IsZero() {
+		t.Errorf("second entry = %+v, want the amend", e)
+	}
+
+	refs := ReflogRefs(dir)
+	if len(refs) != 2 || refs[0] != "HEAD" {
+		t.Errorf("refs = %v, want HEAD and the branch", refs)
+	}
+
+	// Restoring the amend brings c.txt back
+	diff, err := ReflogDiff(dir, entries[1].Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(diff, "+++ b/c.txt") || !strings.Contains(diff, "+c") {
+		t.Errorf("diff does not add c.txt:\n%s", diff)
+	}
+}
+
+func TestReflogViewKeys(t *testing.T) {
+	p := &Plugin{
+		ctx:          &plugin.Context{},
+		hasRepo:      true,
+		tree:         &FileTree{},
+		viewMode:     ViewModeStatus,
+		width:        120,
+		height:       30,
+		mouseHandler: mouse.NewHandler(),
+	}
+	press := func(key string) tea.Cmd {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		switch key {
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case "tab":
+			msg = tea.KeyMsg{Type: tea.KeyTab}
+		}
+		var cmd tea.Cmd
+		if p.viewMode == ViewModeStatus {
+			_, cmd = p.updateStatus(msg)
+		} else {
+			_, cmd = p.updateReflog(msg)
+		}
+		return cmd
+	}
+
+	if cmd := press("H"); cmd == nil || p.viewMode != ViewModeReflog || p.FocusContext() != "git-reflog" {
+		t.Fatalf("H should open the reflog: mode %v", p.viewMode)
+	}
+	entries := []ReflogEntry{
+		{Selector: "HEAD@{0}", Hash: "h2", ShortHash: "h2", Op: "reset", Message: "moving to HEAD~1", Subject: "two"},
+		{Selector: "HEAD@{1}", Hash: "h1", ShortHash: "h1", Op: "commit (amend)", Message: "one", Subject: "one"},
+	}
+	undo := &JournalEntry{Op: JournalDeleteBranch, Branch: "feature", Hash: "h1"}
+	if cmd := p.handleReflogLoaded(ReflogLoadedMsg{Refs: []string{"HEAD", "main"}, Ref: "HEAD", Entries: entries, Undo: undo}); cmd == nil {
+		t.Fatal("loading the reflog should load the first entry's preview")
+	}
+	p.handleReflogDiff(ReflogDiffMsg{Hash: "h2", Raw: ""})
+	view := p.renderReflogView()
+	for _, want := range []string{"HEAD@{1}", "commit (amend)", "u: undo deletion of branch feature", "matches this entry"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view does not contain %q", want)
+		}
+	}
+
+	// Moving loads the next entry's preview; a stale preview is ignored
+	if cmd := press("j"); cmd == nil || p.reflogCursor != 1 {
+		t.Fatal("j should select the next entry and load its preview")
+	}
+	p.handleReflogDiff(ReflogDiffMsg{Hash: "h2", Raw: "stale"})
+	if p.reflogDiff != nil {
+		t.Error("a stale preview was shown")
+	}
+
+	// Restore opens the reset modal over the reflog
+	if cmd := press("enter"); cmd == nil || p.resetCommit == nil || p.resetCommit.Hash != "h1" {
+		t.Fatal("enter should load the reset preview for the entry")
+	}
+	p.handleResetPreview(ResetPreviewMsg{Preview: &ResetPreview{Target: "h1"}})
+	if p.viewMode != ViewModeReset || !p.reflogOpen() {
+		t.Fatalf("reset modal not open over the reflog: mode %v", p.viewMode)
+	}
+	p.updateReset(tea.KeyMsg{Type: tea.KeyEsc})
+	if p.viewMode != ViewModeReflog {
+		t.Fatalf("closing the reset modal should return to the reflog, got %v", p.viewMode)
+	}
+
+	// Switching refs reloads
+	if cmd := press("tab"); cmd == nil || p.reflogRef() != "main" || p.reflogEntries != nil {
+		t.Errorf("tab should load main's reflog, showing %q", p.reflogRef())
+	}
+	press("esc")
+	if p.viewMode != ViewModeStatus {
+		t.Error("esc should close the reflog")
+	}
+}
+
+func TestUndoConfirm(t *testing.T) {
+	p := &Plugin{ctx: &plugin.Context{}, hasRepo: true, tree: &FileTree{}, viewMode: ViewModeStatus, width: 100, height: 30, mouseHandler: mouse.NewHandler()}
+	if cmd := p.openUndo(); cmd == nil {
+		t.Fatal("undo should read the journal")
+	}
+	p.handleJournalLoaded(JournalLoadedMsg{Entry: &JournalEntry{Op: JournalDiscard, Path: "a.txt", Blob: "b1"}})
+	if p.viewMode != ViewModeUndo || p.FocusContext() != "git-undo" {
+		t.Fatalf("undo not confirming: mode %v", p.viewMode)
+	}
+	if !strings.Contains(undoEffectText(p.undoEntry), "replacing its current content") {
+		t.Error("confirmation does not warn that the file is replaced")
+	}
+	if view := p.renderUndo(); !strings.Contains(view, "Undo discard of a.txt") {
+		t.Error("confirmation does not name the action")
+	}
+	p.updateUndo(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if p.viewMode != ViewModeStatus || p.undoEntry != nil {
+		t.Error("n should cancel the undo")
+	}
+}
+
+func TestRestoreOtherBranchMovesIt(t *testing.T) {
+	dir := initRebaseRepo(t, []string{"a", "b"}, nil)
+	if out, err := exec.Command("git", "-C", dir, "branch", "feature").CombinedOutput(); err != nil {
+		t.Fatalf("branch: %v\n%s", err, out)
+	}
+	if err := MoveBranch(dir, "feature", "HEAD~1"); err != nil {
+		t.Fatal(err)
+	}
+	head := commitHash(t, dir, "HEAD")
+
+	p := &Plugin{ctx: &plugin.Context{}, repoRoot: dir, hasRepo: true, tree: &FileTree{}, viewMode: ViewModeStatus, width: 120, height: 30, mouseHandler: mouse.NewHandler()}
+	p.openReflog()
+	p.reflogRefs = ReflogRefs(dir)
+	for i, ref := range p.reflogRefs {
+		if ref == "feature" {
+			p.reflogRefIdx = i
+		}
+	}
+	p.handleReflogLoaded(p.loadReflog()().(ReflogLoadedMsg))
+	if p.reflogRef() != "feature" || len(p.reflogEntries) != 2 {
+		t.Fatalf("feature reflog = %+v", p.reflogEntries)
+	}
+
+	// The entry before the move is where the branch was created
+	p.moveReflogCursor(1)
+	p.restoreReflogEntry()
+	if p.viewMode != ViewModeMoveBranch || p.FocusContext() != "git-move-branch" || p.resetCommit != nil {
+		t.Fatalf("restoring another branch should confirm a move, mode %v", p.viewMode)
+	}
+	if view := p.renderMoveBranch(); !strings.Contains(view, "Move Branch feature") {
+		t.Error("confirmation does not name the branch")
+	}
+	_, cmd := p.updateMoveBranch(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	done := cmd().(CommitActionDoneMsg)
+	if done.Err != nil {
+		t.Fatal(done.Err)
+	}
+	p.handleCommitActionDone(done)
+	if got := commitHash(t, dir, "feature"); got != head {
+		t.Errorf("feature = %s, want %s", got, head)
+	}
+	if got := commitHash(t, dir, "HEAD"); got != head {
+		t.Errorf("HEAD moved to %s", got)
+	}
+	if p.viewMode != ViewModeReflog {
+		t.Errorf("mode = %v, want the reflog", p.viewMode)
+	}
+
+	// The checked out branch is reset through the reset modal instead
+	p.reflogRefIdx = 1
+	p.resetReflogEntries()
+	p.handleReflogLoaded(p.loadReflog()().(ReflogLoadedMsg))
+	if p.reflogRef() != p.reflogCurrent {
+		t.Fatalf("ref %q is not the current branch %q", p.reflogRef(), p.reflogCurrent)
+	}
+	if p.restoreReflogEntry(); p.resetCommit == nil || p.viewMode == ViewModeMoveBranch {
+		t.Error("restoring the current branch should open the reset modal")
+	}
+	if err := MoveBranch(dir, p.reflogCurrent, "HEAD~1"); err == nil {
+		t.Error("moving the checked out branch should fail")
+	}
+}
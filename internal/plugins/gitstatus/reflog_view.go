@@ -0,0 +1,595 @@
+package gitstatus
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/marcus/sidecar/internal/modal"
+	"github.com/marcus/sidecar/internal/mouse"
+	appmsg "github.com/marcus/sidecar/internal/msg"
+	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/styles"
+	"github.com/marcus/sidecar/internal/ui"
+)
+
+// Hit region IDs for the reflog browser
+const (
+	regionReflog      = "reflog"       // Reflog browser
+	regionReflogEntry = "reflog-entry" // An entry row; Data is its index
+)
+
+// Move branch confirmation element IDs
+const (
+	moveBranchConfirmID = "move-branch-confirm"
+	moveBranchCancelID  = "move-branch-cancel"
+)
+
+// ReflogLoadedMsg is sent when a reflog is loaded.
+type ReflogLoadedMsg struct {
+	Epoch   uint64
+	Refs    []string
+	Current string // Checked out branch; "" when HEAD is detached
+	Ref     string
+	Entries []ReflogEntry
+	Undo    *JournalEntry // Newest sidecar action that can be undone
+	Err     error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m ReflogLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// ReflogDiffMsg is sent when the diff preview of an entry is loaded.
+type ReflogDiffMsg struct {
+	Epoch uint64
+	Hash  string
+	Raw   string
+	Err   error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m ReflogDiffMsg) GetEpoch() uint64 { return m.Epoch }
+
+// openReflog opens the reflog browser on HEAD's reflog.
+func (p *Plugin) openReflog() tea.Cmd {
+	p.reflogReturnMode = p.viewMode
+	p.viewMode = ViewModeReflog
+	p.reflogRefs = nil
+	p.reflogRefIdx = 0
+	p.resetReflogEntries()
+	return p.loadReflog()
+}
+
+func (p *Plugin) closeReflog() {
+	p.viewMode = p.reflogReturnMode
+	p.reflogRefs = nil
+	p.resetReflogEntries()
+}
+
+// resetReflogEntries clears the shown entries before another reflog loads.
+func (p *Plugin) resetReflogEntries() {
+	p.reflogEntries = nil
+	p.reflogLoaded = false
+	p.reflogCursor = 0
+	p.reflogScroll = 0
+	p.reflogDiff = nil
+	p.reflogDiffHash = ""
+	p.reflogDiffScroll = 0
+	p.reflogError = ""
+}
+
+// reflogOpen reports whether the reflog browser is shown, possibly under a
+// reset or undo confirmation.
+func (p *Plugin) reflogOpen() bool {
+	switch p.viewMode {
+	case ViewModeReflog:
+		return true
+	case ViewModeReset:
+		return p.resetReturnMode == ViewModeReflog
+	case ViewModeUndo:
+		return p.undoReturnMode == ViewModeReflog
+	case ViewModeMoveBranch:
+		return true
+	}
+	return false
+}
+
+// reflogRef returns the ref whose reflog is shown.
+func (p *Plugin) reflogRef() string {
+	if p.reflogRefIdx < len(p.reflogRefs) {
+		return p.reflogRefs[p.reflogRefIdx]
+	}
+	return "HEAD"
+}
+
+// loadReflog loads the shown ref's reflog, the refs to switch between and
+// the newest undoable sidecar action.
+func (p *Plugin) loadReflog() tea.Cmd {
+	ref := p.reflogRef()
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		entries, err := LoadReflog(workDir, ref)
+		undo, _ := LastJournalEntry(workDir)
+		return ReflogLoadedMsg{Epoch: epoch, Refs: ReflogRefs(workDir), Current: currentBranch(workDir),
+			Ref: ref, Entries: entries, Undo: undo, Err: err}
+	}
+}
+
+// handleReflogLoaded shows a loaded reflog, keeping the cursor on the same
+// entry when the reflog is reloaded.
+func (p *Plugin) handleReflogLoaded(msg ReflogLoadedMsg) tea.Cmd {
+	if !p.reflogOpen() || msg.Ref != p.reflogRef() {
+		return nil
+	}
+	p.reflogUndo = msg.Undo
+	p.reflogLoaded = true
+	if msg.Err != nil {
+		p.reflogEntries = nil
+		p.reflogError = msg.Err.Error()
+		return nil
+	}
+	p.reflogError = ""
+
+	p.reflogRefs = msg.Refs
+	p.reflogCurrent = msg.Current
+	p.reflogRefIdx = 0
+	for i, ref := range msg.Refs {
+		if ref == msg.Ref {
+			p.reflogRefIdx = i
+		}
+	}
+
+	selected := ""
+	if e := p.selectedReflogEntry(); e != nil {
+		selected = e.Selector
+	}
+	p.reflogEntries = msg.Entries
+	p.reflogCursor = 0
+	for i, e := range msg.Entries {
+		if e.Selector == selected {
+			p.reflogCursor = i
+		}
+	}
+	// The working tree may have changed, so the preview is reloaded
+	p.reflogDiffHash = ""
+	return p.loadReflogDiff()
+}
+
+// selectedReflogEntry returns the entry under the cursor.
+func (p *Plugin) selectedReflogEntry() *ReflogEntry {
+	if p.reflogCursor < 0 || p.reflogCursor >= len(p.reflogEntries) {
+		return nil
+	}
+	return &p.reflogEntries[p.reflogCursor]
+}
+
+// loadReflogDiff loads the diff preview of the selected entry unless it is
+// already shown.
+func (p *Plugin) loadReflogDiff() tea.Cmd {
+	e := p.selectedReflogEntry()
+	if e == nil || e.Hash == p.reflogDiffHash {
+		return nil
+	}
+	hash := e.Hash
+	p.reflogDiffHash = hash
+	p.reflogDiff = nil
+	p.reflogDiffScroll = 0
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		raw, err := ReflogDiff(workDir, hash)
+		return ReflogDiffMsg{Epoch: epoch, Hash: hash, Raw: raw, Err: err}
+	}
+}
+
+func (p *Plugin) handleReflogDiff(msg ReflogDiffMsg) {
+	if msg.Hash != p.reflogDiffHash {
+		return
+	}
+	if msg.Err != nil {
+		p.reflogError = msg.Err.Error()
+		return
+	}
+	if strings.TrimSpace(msg.Raw) == "" {
+		p.reflogDiff = &MultiFileDiff{}
+		return
+	}
+	p.reflogDiff = ParseMultiFileDiff(msg.Raw)
+}
+
+// updateReflog handles key events in the reflog browser.
+func (p *Plugin) updateReflog(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		p.closeReflog()
+		return p, nil
+	case "j", "down":
+		return p, p.moveReflogCursor(1)
+	case "k", "up":
+		return p, p.moveReflogCursor(-1)
+	case "g":
+		return p, p.moveReflogCursor(-len(p.reflogEntries))
+	case "G":
+		return p, p.moveReflogCursor(len(p.reflogEntries))
+	case "ctrl+d":
+		p.reflogDiffScroll += 10
+	case "ctrl+u":
+		p.reflogDiffScroll = max(0, p.reflogDiffScroll-10)
+	case "tab", "]":
+		return p, p.switchReflogRef(1)
+	case "shift+tab", "[":
+		return p, p.switchReflogRef(-1)
+	case "enter", "r":
+		return p, p.restoreReflogEntry()
+	case "u":
+		return p, p.openUndo()
+	case "y", "Y":
+		if e := p.selectedReflogEntry(); e != nil {
+			if err := clipboard.WriteAll(e.ShortHash); err != nil {
+				return p, appmsg.ShowToast("Copy failed: "+err.Error(), 2*time.Second)
+			}
+			return p, appmsg.ShowToast("Yanked: "+e.ShortHash, 2*time.Second)
+		}
+	}
+	return p, nil
+}
+
+// moveReflogCursor moves the cursor by delta entries and loads the preview
+// of the entry it lands on.
+func (p *Plugin) moveReflogCursor(delta int) tea.Cmd {
+	if len(p.reflogEntries) == 0 {
+		return nil
+	}
+	p.reflogCursor = max(0, min(p.reflogCursor+delta, len(p.reflogEntries)-1))
+	return p.loadReflogDiff()
+}
+
+// switchReflogRef shows the reflog of the next or previous ref.
+func (p *Plugin) switchReflogRef(delta int) tea.Cmd {
+	n := len(p.reflogRefs)
+	if n < 2 {
+		return nil
+	}
+	p.reflogRefIdx = (p.reflogRefIdx + delta + n) % n
+	p.resetReflogEntries()
+	return p.loadReflog()
+}
+
+// restoreReflogEntry restores the shown ref to the selected entry. HEAD and
+// the checked out branch are reset through the reset modal; another
+// branch is moved without touching the working tree, after confirming.
+func (p *Plugin) restoreReflogEntry() tea.Cmd {
+	e := p.selectedReflogEntry()
+	if e == nil {
+		return nil
+	}
+	if ref := p.reflogRef(); ref != "HEAD" && ref != p.reflogCurrent {
+		return p.openMoveBranch(ref, e)
+	}
+	return p.openResetTo(&Commit{Hash: e.Hash, ShortHash: e.ShortHash, Subject: e.Subject}, ViewModeReflog)
+}
+
+// openMoveBranch asks to confirm pointing branch at a reflog entry.
+func (p *Plugin) openMoveBranch(branch string, e *ReflogEntry) tea.Cmd {
+	if p.commitActionRunning {
+		return appmsg.ShowToast("Another git action is running", 2*time.Second)
+	}
+	entry := *e
+	p.moveBranchName = branch
+	p.moveBranchEntry = &entry
+	p.viewMode = ViewModeMoveBranch
+	p.clearMoveBranchModal()
+	return nil
+}
+
+func (p *Plugin) closeMoveBranch() {
+	p.viewMode = ViewModeReflog
+	p.moveBranchName = ""
+	p.moveBranchEntry = nil
+	p.clearMoveBranchModal()
+}
+
+func (p *Plugin) clearMoveBranchModal() {
+	p.moveBranchModal = nil
+	p.moveBranchWidth = 0
+}
+
+// updateMoveBranch handles key events in the move branch confirmation.
+func (p *Plugin) updateMoveBranch(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	p.ensureMoveBranchModal()
+	if p.moveBranchModal == nil {
+		return p, nil
+	}
+	switch msg.String() {
+	case "y":
+		return p, p.startMoveBranch()
+	case "n", "q":
+		p.closeMoveBranch()
+		return p, nil
+	}
+	action, cmd := p.moveBranchModal.HandleKey(msg)
+	return p, p.moveBranchAction(action, cmd)
+}
+
+// handleMoveBranchMouse processes mouse events in the move branch
+// confirmation.
+func (p *Plugin) handleMoveBranchMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	p.ensureMoveBranchModal()
+	if p.moveBranchModal == nil {
+		return p, nil
+	}
+	action := p.moveBranchModal.HandleMouse(msg, p.mouseHandler)
+	return p, p.moveBranchAction(action, nil)
+}
+
+func (p *Plugin) moveBranchAction(action string, cmd tea.Cmd) tea.Cmd {
+	switch action {
+	case moveBranchConfirmID:
+		return p.startMoveBranch()
+	case "cancel", moveBranchCancelID:
+		p.closeMoveBranch()
+		return nil
+	}
+	return cmd
+}
+
+// startMoveBranch points the confirmed branch at the entry.
+func (p *Plugin) startMoveBranch() tea.Cmd {
+	branch, e := p.moveBranchName, p.moveBranchEntry
+	if e == nil {
+		return nil
+	}
+	workDir := p.repoRoot
+	p.closeMoveBranch()
+	p.commitActionRunning = true
+	return func() tea.Msg {
+		return CommitActionDoneMsg{Op: "move-branch", Branch: branch, Hash: e.ShortHash, Err: MoveBranch(workDir, branch, e.Hash)}
+	}
+}
+
+// ensureMoveBranchModal builds/rebuilds the move branch confirmation.
+func (p *Plugin) ensureMoveBranchModal() {
+	if p.moveBranchEntry == nil {
+		return
+	}
+	modalW := ui.ModalWidthLarge
+	if modalW > p.width-4 {
+		modalW = p.width - 4
+	}
+	if modalW < pullMenuMinWidth {
+		modalW = pullMenuMinWidth
+	}
+	if p.moveBranchModal != nil && p.moveBranchWidth == modalW {
+		return
+	}
+	p.moveBranchWidth = modalW
+
+	e := p.moveBranchEntry
+	p.moveBranchModal = modal.New("Move Branch "+p.moveBranchName,
+		modal.WithWidth(modalW),
+		modal.WithVariant(modal.VariantWarning),
+		modal.WithHints(false),
+		modal.WithPrimaryAction(moveBranchConfirmID),
+	).
+		AddSection(modal.Text(styles.Body.Render(fmt.Sprintf("%s is pointed at %s (%s), %s: %s",
+			p.moveBranchName, e.ShortHash, e.Selector, e.Op, e.Subject)))).
+		AddSection(modal.Text(styles.Muted.Render("It is not checked out, so the working tree does not change. Its current tip stays in its reflog."))).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Buttons(
+			modal.Btn(" Move ", moveBranchConfirmID, modal.BtnPrimary()),
+			modal.Btn(" Cancel ", moveBranchCancelID),
+		)).
+		AddSection(modal.Text(styles.Muted.Render("y: move   Esc: cancel")))
+}
+
+// renderMoveBranch renders the move branch confirmation over the reflog.
+func (p *Plugin) renderMoveBranch() string {
+	background := p.renderReflogView()
+	p.ensureMoveBranchModal()
+	if p.moveBranchModal == nil {
+		return background
+	}
+	modalContent := p.moveBranchModal.Render(p.width, p.height, p.mouseHandler)
+	return ui.OverlayModal(background, modalContent, p.width, p.height)
+}
+
+// handleReflogMouse selects clicked entries and scrolls the list or preview.
+func (p *Plugin) handleReflogMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	action := p.mouseHandler.HandleMouse(msg)
+	onList := action.Region != nil && action.Region.ID == regionReflogEntry
+	switch action.Type {
+	case mouse.ActionClick:
+		if idx, ok := regionIndex(action.Region, regionReflogEntry); ok {
+			return p, p.moveReflogCursor(idx - p.reflogCursor)
+		}
+	case mouse.ActionDoubleClick:
+		if idx, ok := regionIndex(action.Region, regionReflogEntry); ok {
+			p.reflogCursor = idx
+			return p, p.restoreReflogEntry()
+		}
+	case mouse.ActionScrollUp:
+		if onList {
+			return p, p.moveReflogCursor(-1)
+		}
+		p.reflogDiffScroll = max(0, p.reflogDiffScroll-3)
+	case mouse.ActionScrollDown:
+		if onList {
+			return p, p.moveReflogCursor(1)
+		}
+		p.reflogDiffScroll += 3
+	}
+	return p, nil
+}
+
+// regionIndex returns the int data of a hit region with the given ID.
+func regionIndex(r *mouse.Region, id string) (int, bool) {
+	if r == nil || r.ID != id {
+		return 0, false
+	}
+	idx, ok := r.Data.(int)
+	return idx, ok
+}
+
+// reflogOpStyle colors an entry's operation by how it moved the ref.
+func reflogOpStyle(op string) lipgloss.Style {
+	switch {
+	case strings.HasPrefix(op, "commit"), strings.HasPrefix(op, "cherry-pick"):
+		return styles.StatusStaged
+	case strings.HasPrefix(op, "reset"), strings.HasPrefix(op, "rebase"):
+		return lipgloss.NewStyle().Foreground(styles.Warning)
+	case strings.HasPrefix(op, "checkout"), strings.HasPrefix(op, "branch"):
+		return styles.StatusModified
+	default:
+		return styles.Body
+	}
+}
+
+// renderReflogView renders the reflog browser: the entries on the left and
+// the selected entry's diff against the working tree on the right.
+func (p *Plugin) renderReflogView() string {
+	paneHeight := p.height - 2
+	contentWidth := p.width - 4
+	if contentWidth < 40 {
+		contentWidth = 40
+	}
+	bodyRows := max(1, paneHeight-3)
+	listWidth := max(30, contentWidth*2/5)
+	diffWidth := max(10, contentWidth-listWidth-3)
+
+	p.mouseHandler.Clear()
+	p.mouseHandler.HitMap.AddRect(regionReflog, 0, 0, p.width, p.height, nil)
+
+	var sb strings.Builder
+	sb.WriteString(p.renderReflogHeader(contentWidth))
+	sb.WriteString("\n")
+	sb.WriteString(styles.Muted.Render(strings.Repeat("━", contentWidth)))
+	sb.WriteString("\n")
+
+	list := p.renderReflogList(listWidth, bodyRows)
+	preview := p.renderReflogPreview(diffWidth, bodyRows)
+	divider := styles.Muted.Render(strings.TrimSuffix(strings.Repeat(" │ \n", bodyRows), "\n"))
+	sb.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, list, divider, preview))
+	sb.WriteString("\n")
+
+	if p.reflogError != "" {
+		sb.WriteString(styles.StatusDeleted.Render(ansi.Truncate("✗ "+p.reflogError, contentWidth, "…")))
+	} else {
+		hints := "enter restore  u undo sidecar action  tab ref  ctrl+d/u scroll  y yank  esc close"
+		sb.WriteString(styles.Muted.Render(ansi.Truncate(hints, contentWidth, "…")))
+	}
+
+	return p.wrapDiffContent(sb.String(), paneHeight)
+}
+
+// renderReflogHeader renders the title with the refs to switch between, and
+// the sidecar action u would undo.
+func (p *Plugin) renderReflogHeader(width int) string {
+	parts := []string{styles.Body.Bold(true).Render("Reflog")}
+	for i, ref := range p.reflogRefs {
+		style := styles.Muted
+		if i == p.reflogRefIdx {
+			style = styles.ListItemSelected
+		}
+		parts = append(parts, style.Render(" "+ref+" "))
+	}
+	if len(p.reflogRefs) == 0 {
+		parts = append(parts, styles.ListItemSelected.Render(" "+p.reflogRef()+" "))
+	}
+	title := ansi.Truncate(strings.Join(parts, " "), width*3/5, "…")
+
+	right := styles.Muted.Render("nothing to undo")
+	if p.reflogUndo != nil {
+		right = styles.Muted.Render("u: undo " + p.reflogUndo.Description())
+	}
+	right = ansi.Truncate(right, max(0, width-ansi.StringWidth(title)-1), "…")
+	gap := width - ansi.StringWidth(title) - ansi.StringWidth(right)
+	if gap < 1 {
+		return title
+	}
+	return title + strings.Repeat(" ", gap) + right
+}
+
+// renderReflogList renders the visible entries, one per row.
+func (p *Plugin) renderReflogList(width, rows int) string {
+	lines := make([]string, 0, rows)
+	switch {
+	case !p.reflogLoaded:
+		lines = append(lines, styles.Muted.Render("Loading reflog..."))
+	case len(p.reflogEntries) == 0:
+		lines = append(lines, styles.Muted.Render("No reflog entries"))
+	}
+
+	// Keep the cursor visible
+	if p.reflogCursor < p.reflogScroll {
+		p.reflogScroll = p.reflogCursor
+	}
+	if p.reflogCursor >= p.reflogScroll+rows {
+		p.reflogScroll = p.reflogCursor - rows + 1
+	}
+
+	for i := p.reflogScroll; i < len(p.reflogEntries) && len(lines) < rows; i++ {
+		p.mouseHandler.HitMap.AddRect(regionReflogEntry, 0, 3+len(lines), width+2, 1, i)
+		lines = append(lines, p.renderReflogEntry(p.reflogEntries[i], i == p.reflogCursor, width))
+	}
+	for len(lines) < rows {
+		lines = append(lines, "")
+	}
+	for i, line := range lines {
+		lines[i] = padToWidth(line, width)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderReflogEntry renders an entry as "HEAD@{n} hash op: message  age".
+func (p *Plugin) renderReflogEntry(e ReflogEntry, selected bool, width int) string {
+	detail := e.Message
+	if detail == "" {
+		detail = e.Subject
+	}
+	age := ""
+	if !e.Date.IsZero() {
+		age = RelativeTime(e.Date)
+	}
+	left := fmt.Sprintf("%-10s %s ", e.Selector, e.ShortHash)
+	avail := width - ansi.StringWidth(left) - ansi.StringWidth(age) - 1
+	text := truncateStr(e.Op+": "+detail, max(0, avail))
+	if selected {
+		line := left + text
+		return styles.ListItemSelected.Render(padRight(line, width-ansi.StringWidth(age)) + age)
+	}
+
+	op, rest, _ := strings.Cut(text, ": ")
+	rendered := styles.Muted.Render(left) + reflogOpStyle(e.Op).Render(op)
+	if rest != "" || strings.Contains(text, ": ") {
+		rendered += styles.Body.Render(": " + rest)
+	}
+	gap := width - ansi.StringWidth(rendered) - ansi.StringWidth(age)
+	return rendered + strings.Repeat(" ", max(1, gap)) + styles.Muted.Render(age)
+}
+
+// renderReflogPreview renders the selected entry's diff against the
+// working tree.
+func (p *Plugin) renderReflogPreview(width, rows int) string {
+	e := p.selectedReflogEntry()
+	if e == nil {
+		return ""
+	}
+	title := styles.Subtitle.Render(truncateStr("Changes from the working tree to "+e.ShortHash+":", width))
+	var body string
+	switch {
+	case p.reflogDiff == nil:
+		body = styles.Muted.Render("Loading diff...")
+	case len(p.reflogDiff.Files) == 0:
+		body = styles.Muted.Render("Nothing: the working tree matches this entry.")
+	default:
+		maxScroll := max(0, p.reflogDiff.TotalLines()-(rows-1))
+		p.reflogDiffScroll = min(p.reflogDiffScroll, maxScroll)
+		body = RenderMultiFileDiff(p.reflogDiff, DiffViewUnified, width, p.reflogDiffScroll, rows-1, 0, false)
+	}
+	return title + "\n" + body
+}
@@ -205,8 +205,12 @@ func (p *Plugin) renderSidebar(visibleHeight int) string {
 	sb.WriteString("\n")
 	currentY++
 
-	// Remote operation status (push/fetch/pull)
-	if p.pushInProgress {
+	// Remote operation status (push/fetch/pull), after any rebase in progress
+	if indicator := p.rebaseIndicator(); indicator != "" {
+		sb.WriteString(indicator)
+		sb.WriteString("\n")
+		currentY++
+	} else if p.pushInProgress {
 		sb.WriteString(styles.StatusInProgress.Render("Pushing..."))
 		sb.WriteString("\n")
 		currentY++
@@ -482,9 +486,13 @@ func (p *Plugin) renderRecentCommits(currentY *int, maxVisible int) string {
 			graphVisualWidth = graphWidth
 		}
 
-		// Push indicator: ↑ for unpushed, nothing for pushed
+		// Push indicator: ↑ for unpushed, nothing for pushed; ● for commits
+		// in the marked cherry-pick range
+		inRange := p.commitInRange(i)
 		var indicator string
-		if !commit.Pushed {
+		if inRange {
+			indicator = styles.StatusInProgress.Render("●") + " "
+		} else if !commit.Pushed {
 			indicator = styles.StatusModified.Render("↑") + " "
 		} else {
 			indicator = "  " // Two spaces to align with indicator
@@ -507,7 +515,9 @@ func (p *Plugin) renderRecentCommits(currentY *int, maxVisible int) string {
 
 		if selected {
 			plainIndicator := "  "
-			if !commit.Pushed {
+			if inRange {
+				plainIndicator = "● "
+			} else if !commit.Pushed {
 				plainIndicator = "↑ "
 			}
 			// For selected lines, include graph prefix without styling (will be styled by selection)
@@ -674,13 +684,20 @@ func (p *Plugin) renderDiffPane(visibleHeight int) string {
 		contentHeight = 1
 	}
 
-	// Render diff based on view mode
+	// Render diff based on view mode. The line cursor only shows while the
+	// pane is focused.
+	parsed := p.diffPaneParsedDiff
+	if parsed.Selection != nil && p.activePane != PaneDiff {
+		unselected := *parsed
+		unselected.Selection = nil
+		parsed = &unselected
+	}
 	highlighter := p.getHighlighter(p.selectedDiffFile)
 	var diffContent string
 	if p.diffPaneViewMode == DiffViewSideBySide {
-		diffContent = RenderSideBySide(p.diffPaneParsedDiff, diffWidth, p.diffPaneScroll, contentHeight, p.diffPaneHorizScroll, highlighter, p.diffWrapEnabled)
+		diffContent = RenderSideBySide(parsed, diffWidth, p.diffPaneScroll, contentHeight, p.diffPaneHorizScroll, highlighter, p.diffWrapEnabled)
 	} else {
-		diffContent = RenderLineDiff(p.diffPaneParsedDiff, diffWidth, p.diffPaneScroll, contentHeight, p.diffPaneHorizScroll, highlighter, p.diffWrapEnabled)
+		diffContent = RenderLineDiff(parsed, diffWidth, p.diffPaneScroll, contentHeight, p.diffPaneHorizScroll, highlighter, p.diffWrapEnabled)
 	}
 	// Force truncate each line to prevent wrapping (skip when wrap is enabled)
 	if !p.diffWrapEnabled {
@@ -0,0 +1,232 @@
+package gitstatus
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/modal"
+	appmsg "github.com/marcus/sidecar/internal/msg"
+	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/styles"
+	"github.com/marcus/sidecar/internal/ui"
+)
+
+// Undo confirmation element IDs
+const (
+	undoConfirmID = "undo-confirm"
+	undoCancelID  = "undo-cancel"
+)
+
+// JournalLoadedMsg is sent when the newest undoable sidecar action is read
+// from the operation journal.
+type JournalLoadedMsg struct {
+	Epoch uint64
+	Entry *JournalEntry // nil when there is nothing to undo
+	Err   error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m JournalLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// JournalUndoneMsg is sent when undoing a sidecar action finishes.
+type JournalUndoneMsg struct {
+	Entry *JournalEntry
+	Err   error
+}
+
+// openUndo reads the newest sidecar action to confirm undoing it.
+func (p *Plugin) openUndo() tea.Cmd {
+	if p.undoRunning {
+		return appmsg.ShowToast("An undo is already running", 2*time.Second)
+	}
+	if reason := p.commitActionBlocked(); reason != "" {
+		return appmsg.ShowToast(reason, 2*time.Second)
+	}
+	p.undoReturnMode = p.viewMode
+	epoch := p.ctx.Epoch
+	workDir := p.repoRoot
+	return func() tea.Msg {
+		entry, err := LastJournalEntry(workDir)
+		return JournalLoadedMsg{Epoch: epoch, Entry: entry, Err: err}
+	}
+}
+
+// handleJournalLoaded opens the undo confirmation on the loaded action.
+func (p *Plugin) handleJournalLoaded(msg JournalLoadedMsg) tea.Cmd {
+	if p.viewMode != p.undoReturnMode {
+		return nil
+	}
+	if msg.Err != nil {
+		p.showErrorModal("Undo", msg.Err)
+		return nil
+	}
+	if msg.Entry == nil {
+		return appmsg.ShowToast("Nothing to undo", 2*time.Second)
+	}
+	p.undoEntry = msg.Entry
+	p.viewMode = ViewModeUndo
+	p.clearUndoModal()
+	return nil
+}
+
+func (p *Plugin) closeUndo() {
+	p.viewMode = p.undoReturnMode
+	p.undoEntry = nil
+	p.clearUndoModal()
+}
+
+func (p *Plugin) clearUndoModal() {
+	p.undoModal = nil
+	p.undoWidth = 0
+}
+
+// updateUndo handles key events in the undo confirmation.
+func (p *Plugin) updateUndo(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	p.ensureUndoModal()
+	if p.undoModal == nil {
+		return p, nil
+	}
+	switch msg.String() {
+	case "y":
+		return p, p.startUndo()
+	case "n", "q":
+		p.closeUndo()
+		return p, nil
+	}
+	action, cmd := p.undoModal.HandleKey(msg)
+	return p, p.undoAction(action, cmd)
+}
+
+// handleUndoMouse processes mouse events in the undo confirmation.
+func (p *Plugin) handleUndoMouse(msg tea.MouseMsg) (*Plugin, tea.Cmd) {
+	p.ensureUndoModal()
+	if p.undoModal == nil {
+		return p, nil
+	}
+	action := p.undoModal.HandleMouse(msg, p.mouseHandler)
+	return p, p.undoAction(action, nil)
+}
+
+func (p *Plugin) undoAction(action string, cmd tea.Cmd) tea.Cmd {
+	switch action {
+	case undoConfirmID:
+		return p.startUndo()
+	case "cancel", undoCancelID:
+		p.closeUndo()
+		return nil
+	}
+	return cmd
+}
+
+// startUndo reverses the newest sidecar action.
+func (p *Plugin) startUndo() tea.Cmd {
+	if p.undoEntry == nil {
+		return nil
+	}
+	workDir := p.repoRoot
+	p.closeUndo()
+	p.undoRunning = true
+	return func() tea.Msg {
+		entry, err := UndoLastJournalEntry(workDir)
+		return JournalUndoneMsg{Entry: entry, Err: err}
+	}
+}
+
+// handleJournalUndone reports a finished undo and reloads what it changed.
+func (p *Plugin) handleJournalUndone(msg JournalUndoneMsg) tea.Cmd {
+	p.undoRunning = false
+	cmds := []tea.Cmd{p.refresh(), p.loadRecentCommits()}
+	if p.viewMode == ViewModeReflog {
+		cmds = append(cmds, p.loadReflog())
+	}
+	switch {
+	case errors.Is(msg.Err, ErrJournalEmpty):
+		return tea.Batch(append(cmds, appmsg.ShowToast("Nothing to undo", 2*time.Second))...)
+	case msg.Err != nil:
+		p.showErrorModal("Undo Failed", msg.Err)
+		return tea.Batch(cmds...)
+	}
+
+	text := "Undid " + msg.Entry.Description()
+	if msg.Entry.Op == JournalAmend {
+		text += "; its changes are staged"
+	}
+	return tea.Batch(append(cmds, appmsg.ShowToast(text, 3*time.Second))...)
+}
+
+// undoEffectText describes what undoing the action changes.
+func undoEffectText(e *JournalEntry) string {
+	switch e.Op {
+	case JournalAmend:
+		return fmt.Sprintf("HEAD returns to %s, the commit before the amend. The amended changes stay staged.", shortHash(e.OldHead))
+	case JournalDiscard:
+		text := e.Path + " is restored from its backup, replacing its current content."
+		if e.Blob == "" {
+			text = e.Path + " is deleted again."
+		}
+		if e.IndexBlob != "" {
+			text += " Its staged changes are restored too."
+		}
+		return text
+	case JournalDeleteBranch:
+		return fmt.Sprintf("Branch %s is recreated at %s.", e.Branch, shortHash(e.Hash))
+	}
+	return ""
+}
+
+// ensureUndoModal builds/rebuilds the undo confirmation.
+func (p *Plugin) ensureUndoModal() {
+	if p.undoEntry == nil {
+		return
+	}
+	modalW := ui.ModalWidthLarge
+	if modalW > p.width-4 {
+		modalW = p.width - 4
+	}
+	if modalW < pullMenuMinWidth {
+		modalW = pullMenuMinWidth
+	}
+	if p.undoModal != nil && p.undoWidth == modalW {
+		return
+	}
+	p.undoWidth = modalW
+
+	e := p.undoEntry
+	variant := modal.VariantDefault
+	if e.Op == JournalDiscard && e.Blob != "" {
+		variant = modal.VariantWarning
+	}
+	p.undoModal = modal.New("Undo "+e.Description(),
+		modal.WithWidth(modalW),
+		modal.WithVariant(variant),
+		modal.WithHints(false),
+		modal.WithPrimaryAction(undoConfirmID),
+	).
+		AddSection(modal.Text(styles.Body.Render(undoEffectText(e)))).
+		AddSection(modal.Text(styles.Muted.Render("Done " + RelativeTime(e.Time) + " by sidecar."))).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Buttons(
+			modal.Btn(" Undo ", undoConfirmID, modal.BtnPrimary()),
+			modal.Btn(" Cancel ", undoCancelID),
+		)).
+		AddSection(modal.Text(styles.Muted.Render("y: undo   Esc: cancel")))
+}
+
+// renderUndo renders the undo confirmation over the view it was opened from.
+func (p *Plugin) renderUndo() string {
+	var background string
+	if p.undoReturnMode == ViewModeReflog {
+		background = p.renderReflogView()
+	} else {
+		background = p.renderThreePaneView()
+	}
+
+	p.ensureUndoModal()
+	if p.undoModal == nil {
+		return background
+	}
+	modalContent := p.undoModal.Render(p.width, p.height, p.mouseHandler)
+	return ui.OverlayModal(background, modalContent, p.width, p.height)
+}
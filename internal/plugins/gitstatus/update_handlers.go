@@ -183,19 +183,7 @@ func (p *Plugin) updateStatus(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 	case "d":
 		// Open full-screen diff view for files
 		if !p.cursorOnCommit() && len(entries) > 0 && p.cursor < len(entries) {
-			entry := entries[p.cursor]
-			p.diffReturnMode = p.viewMode
-			p.viewMode = ViewModeDiff
-			p.diffFile = entry.Path
-			p.diffCommit = ""
-			p.diffCommitSubject = ""
-			p.diffCommitShortHash = ""
-			p.diffScroll = 0
-			p.diffLoaded = false
-			if entry.IsFolder {
-				return p, p.loadFullFolderDiff(entry)
-			}
-			return p, p.loadDiff(entry.Path, entry.Staged, entry.Status)
+			return p, p.openFileDiff(entries[p.cursor])
 		}
 		// For commits, focus the preview pane (same as l/right)
 		if p.cursorOnCommit() && p.previewCommit != nil {
@@ -306,6 +294,52 @@ func (p *Plugin) updateStatus(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 			return p, p.openCommitInGitHub()
 		}
 
+	case "I":
+		// Interactive rebase from the selected commit to HEAD
+		if p.cursorOnCommit() {
+			return p, p.openRebaseEditor()
+		}
+
+	case "m":
+		// Mark the selected commit as one end of a cherry-pick range
+		if p.cursorOnCommit() {
+			return p, p.toggleCommitMark()
+		}
+
+	case "C":
+		// Cherry-pick the selected commit or marked range
+		if p.cursorOnCommit() {
+			return p, p.openCherryPick()
+		}
+
+	case "t":
+		// Revert the selected commit
+		if p.cursorOnCommit() {
+			return p, p.openRevert()
+		}
+
+	case "X":
+		// Reset the branch to the selected commit
+		if p.cursorOnCommit() {
+			return p, p.openReset()
+		}
+
+	case "R":
+		// Continue or abort a stopped rebase
+		if p.rebaseState != nil && !p.rebaseRunning {
+			p.openRebaseStatus()
+		}
+
+	case "M":
+		// Resolve merge conflicts, starting at the selected file
+		if p.hasConflicts() {
+			path := ""
+			if !p.cursorOnCommit() && p.cursor < len(entries) && entries[p.cursor].Status == StatusUnmerged {
+				path = entries[p.cursor].Path
+			}
+			return p, p.openConflicts(path)
+		}
+
 	case "D":
 		// Discard changes (confirm modal) - only for modified/staged files, not commits
 		if !p.cursorOnCommit() && len(entries) > 0 && p.cursor < len(entries) {
@@ -334,6 +368,14 @@ func (p *Plugin) updateStatus(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 		// Apply latest stash (non-destructive, stash entry preserved)
 		return p, p.doStashApply()
 
+	case "alt+z":
+		// Undo the last sidecar amend, discard or branch deletion
+		return p, p.openUndo()
+
+	case "H":
+		// Browse HEAD and branch reflogs
+		return p, p.openReflog()
+
 	case "b":
 		// Open branch picker
 		p.branchReturnMode = p.viewMode
@@ -421,6 +463,11 @@ func (p *Plugin) updateStatus(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 			p.clearSearchState()
 			return p, nil
 		}
+		// Otherwise it clears the cherry-pick range mark
+		if p.commitMark != "" {
+			p.commitMark = ""
+			return p, nil
+		}
 
 	case "v":
 		// Toggle commit graph display (only when on commits)
@@ -445,6 +492,13 @@ func (p *Plugin) updateStatusDiffPane(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 		return p.updateCommitPreviewPane(msg)
 	}
 
+	// Line cursor keys for hunk and line staging
+	if v, ok := p.inlineSelectionView(); ok {
+		if handled, cmd := p.handleSelectionKey(v, msg.String()); handled {
+			return p, cmd
+		}
+	}
+
 	switch msg.String() {
 	case "esc":
 		// Restore sidebar if hidden, then return to it
@@ -544,16 +598,7 @@ func (p *Plugin) updateStatusDiffPane(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 		// Open full-screen diff view for current file
 		entries := p.tree.AllEntries()
 		if len(entries) > 0 && p.cursor < len(entries) {
-			entry := entries[p.cursor]
-			p.diffReturnMode = p.viewMode
-			p.viewMode = ViewModeDiff
-			p.diffFile = entry.Path
-			p.diffCommit = ""
-			p.diffCommitSubject = ""
-			p.diffCommitShortHash = ""
-			p.diffScroll = 0
-			p.diffLoaded = false
-			return p, p.loadDiff(entry.Path, entry.Staged, entry.Status)
+			return p, p.openFileDiff(entries[p.cursor])
 		}
 	}
 
@@ -658,6 +703,7 @@ func (p *Plugin) closeDiffView() {
 	p.diffCommitSubject = ""
 	p.diffCommitShortHash = ""
 	p.diffFile = ""
+	p.diffTarget = nil
 	p.diffBackWidth = 0
 	p.viewMode = p.diffReturnMode
 	if p.diffReturnMode == ViewModeStatus && p.previewCommit != nil {
@@ -667,6 +713,13 @@ func (p *Plugin) closeDiffView() {
 
 // updateDiff handles key events in the diff view.
 func (p *Plugin) updateDiff(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
+	// Line cursor keys for hunk and line staging
+	if v, ok := p.fullSelectionView(); ok {
+		if handled, cmd := p.handleSelectionKey(v, msg.String()); handled {
+			return p, cmd
+		}
+	}
+
 	switch msg.String() {
 	case "esc", "q":
 		p.closeDiffView()
@@ -952,6 +1005,9 @@ func (p *Plugin) updatePullConflict(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 	}
 
 	switch msg.String() {
+	case "r":
+		// Resolve in the conflict view
+		return p.resolvePullConflict()
 	case "a":
 		// Abort merge/rebase
 		return p.abortPullConflict()
@@ -962,6 +1018,8 @@ func (p *Plugin) updatePullConflict(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 
 	action, cmd := p.pullConflictModal.HandleKey(msg)
 	switch action {
+	case pullConflictResolveID:
+		return p.resolvePullConflict()
 	case pullConflictAbortID:
 		return p.abortPullConflict()
 	case "cancel", pullConflictDismissID:
@@ -970,6 +1028,13 @@ func (p *Plugin) updatePullConflict(msg tea.KeyMsg) (plugin.Plugin, tea.Cmd) {
 	return p, cmd
 }
 
+func (p *Plugin) resolvePullConflict() (plugin.Plugin, tea.Cmd) {
+	p.viewMode = ViewModeStatus
+	p.pullConflictFiles = nil
+	p.clearPullConflictModal()
+	return p, p.openConflicts("")
+}
+
 func (p *Plugin) abortPullConflict() (plugin.Plugin, tea.Cmd) {
 	p.viewMode = ViewModeStatus
 	p.clearPullConflictModal()
@@ -383,6 +383,20 @@ func (p *Plugin) ConsumesTextInput() bool {
 	}
 }
 
+// CommandVariables exposes the open or selected issue to custom commands.
+func (p *Plugin) CommandVariables() map[string]string {
+	vars := make(map[string]string)
+	if p.model == nil {
+		return vars
+	}
+	if modal := p.model.CurrentModal(); modal != nil && modal.IssueID != "" {
+		vars["task"] = modal.IssueID
+	} else if id := p.model.SelectedIssueID(p.model.ActivePanel); id != "" {
+		vars["task"] = id
+	}
+	return vars
+}
+
 // Diagnostics returns plugin health info.
 func (p *Plugin) Diagnostics() []plugin.Diagnostic {
 	status := "ok"
@@ -7,7 +7,6 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -15,6 +14,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/marcus/sidecar/internal/features"
+	"github.com/marcus/sidecar/internal/remote"
 )
 
 // paneCacheEntry holds cached capture output with timestamp
@@ -220,6 +220,7 @@ const (
 	pollIntervalVisibleUnfocused = 500 * time.Millisecond // Output visible but plugin not focused
 	pollIntervalUnfocused        = 20 * time.Second       // Plugin not focused, output not visible
 	pollIntervalThrottled        = 20 * time.Second       // Runaway session throttled (td-018f25)
+	pollIntervalPushed           = 2 * time.Second        // Minimum for sessions that push output
 
 	// Poll staggering to prevent simultaneous subprocess spawns
 	pollStaggerMax = 400 * time.Millisecond // Max stagger offset based on worktree name hash
@@ -331,88 +332,17 @@ func (a *Agent) CheckRunaway() bool {
 	return false
 }
 
-// StartAgent creates a tmux session and starts an agent for a worktree.
+// StartAgent creates a session and starts an agent for a worktree.
 // If a session already exists, it reconnects to it instead of failing.
 func (p *Plugin) StartAgent(wt *Worktree, agentType AgentType) tea.Cmd {
 	epoch := p.ctx.Epoch // Capture epoch for stale detection
+	backend := p.agentBackend()
 	return func() tea.Msg {
-		sessionName := tmuxSessionPrefix + sanitizeName(wt.Name)
-
-		// Check if session already exists
-		checkCmd := exec.Command("tmux", "has-session", "-t", sessionName)
-		if checkCmd.Run() == nil {
-			// Session exists - reconnect to it instead of failing
-			paneID := getPaneID(sessionName)
-			return AgentStartedMsg{
-				Epoch:         epoch,
-				WorkspaceName: wt.Name,
-				SessionName:   sessionName,
-				PaneID:        paneID,
-				AgentType:     agentType,
-				Reconnected:   true, // Flag that we reconnected to existing session
-			}
-		}
-
-		// Create new detached session with working directory
-		args := []string{
-			"new-session",
-			"-d",              // Detached
-			"-s", sessionName, // Session name
-			"-c", wt.Path, // Working directory
-		}
-
-		cmd := exec.Command("tmux", args...)
-		if err := cmd.Run(); err != nil {
-			return AgentStartedMsg{Epoch: epoch, Err: fmt.Errorf("create session: %w", err)}
-		}
-
-		// Set history limit for scrollback capture
-		_ = exec.Command("tmux", "set-option", "-t", sessionName, "history-limit",
-			strconv.Itoa(tmuxHistoryLimit)).Run()
-
-		// Detect shell type for correct syntax (fish vs posix)
-		shellType := DetectShell()
-
-		// Set TD_SESSION_ID environment variable for td session tracking
-		envCmd := GenerateExportCommand("TD_SESSION_ID", sessionName, shellType)
-		_ = exec.Command("tmux", "send-keys", "-t", sessionName, envCmd, "Enter").Run()
-
-		// Apply environment isolation to prevent conflicts (GOWORK, etc.)
-		envOverrides := BuildEnvOverrides(p.ctx.WorkDir)
-		if envCmd := GenerateSingleEnvCommand(envOverrides, shellType); envCmd != "" {
-			_ = exec.Command("tmux", "send-keys", "-t", sessionName, envCmd, "Enter").Run()
-		}
-
-		// If worktree has a linked task, start it in td
-		if wt.TaskID != "" {
-			tdStartCmd := fmt.Sprintf("td start %s", wt.TaskID)
-			_ = exec.Command("tmux", "send-keys", "-t", sessionName, tdStartCmd, "Enter").Run()
-		}
-
-		// Small delay to ensure env is set
-		time.Sleep(100 * time.Millisecond)
-
 		// Get the agent command with optional task context
 		agentCmd := p.getAgentCommandWithContext(agentType, wt)
-
-		// Send the agent command to start it
-		sendCmd := exec.Command("tmux", "send-keys", "-t", sessionName, agentCmd, "Enter")
-		if err := sendCmd.Run(); err != nil {
-			// Try to kill the session if we failed to start the agent
-			_ = exec.Command("tmux", "kill-session", "-t", sessionName).Run()
-			return AgentStartedMsg{Epoch: epoch, Err: fmt.Errorf("start agent: %w", err)}
-		}
-
-		// Capture pane ID for interactive mode support
-		paneID := getPaneID(sessionName)
-
-		return AgentStartedMsg{
-			Epoch:         epoch,
-			WorkspaceName: wt.Name,
-			SessionName:   sessionName,
-			PaneID:        paneID,
-			AgentType:     agentType,
-		}
+		msg := p.startAgentSession(backend, wt, agentType, agentCmd)
+		msg.Epoch = epoch
+		return msg
 	}
 }
 
@@ -439,8 +369,14 @@ func (p *Plugin) buildAgentCommand(agentType AgentType, wt *Worktree, skipPerms
 	// Determine context to pass to agent
 	var ctx string
 	if prompt != nil {
-		// Use prompt template with ticket expansion
-		ctx = ExpandPromptTemplate(prompt.Body, wt.TaskID)
+		var err error
+		ctx, err = RenderPrompt(prompt, p.promptVars(wt))
+		if err != nil {
+			if p.ctx != nil && p.ctx.Logger != nil {
+				p.ctx.Logger.Warn("prompt template failed", "prompt", prompt.Name, "error", err)
+			}
+			ctx = prompt.Body
+		}
 	} else if wt.TaskID != "" {
 		// No prompt selected but task selected: try to fetch full context
 		ctx = p.getTaskContext(wt.TaskID)
@@ -464,6 +400,39 @@ func (p *Plugin) buildAgentCommand(agentType AgentType, wt *Worktree, skipPerms
 	return launcherCmd
 }
 
+// promptVars returns the prompt template variables for a worktree.
+func (p *Plugin) promptVars(wt *Worktree) PromptVars {
+	var workDir string
+	if p.ctx != nil {
+		workDir = p.ctx.WorkDir
+	}
+	baseBranch := resolveBaseBranch(wt)
+	vars := PromptVars{
+		Ticket:       wt.TaskID,
+		Branch:       wt.Branch,
+		BaseBranch:   baseBranch,
+		Worktree:     wt.Name,
+		WorktreePath: wt.Path,
+		LoadDiff: func() ([]string, string) {
+			return getChangesFromBase(wt.Path, baseBranch)
+		},
+	}
+	if wt.TaskID != "" {
+		vars.LoadTask = func() *TaskDetails {
+			details, err := fetchTaskDetails(workDir, wt.TaskID)
+			if err != nil {
+				// Fall back to the title from the create modal
+				if wt.TaskTitle != "" {
+					return &TaskDetails{ID: wt.TaskID, Title: wt.TaskTitle}
+				}
+				return nil
+			}
+			return details
+		}
+	}
+	return vars
+}
+
 // writeAgentLauncher writes a launcher script that safely passes the prompt to the agent.
 // Returns the command to execute the launcher. This avoids shell escaping issues
 // with complex markdown content (backticks, newlines, quotes, etc).
@@ -532,107 +501,115 @@ func (p *Plugin) getAgentCommandWithContext(agentType AgentType, wt *Worktree) s
 	return p.buildAgentCommand(agentType, wt, false, nil)
 }
 
-// StartAgentWithOptions creates a tmux session and starts an agent with options.
+// StartAgentWithOptions creates a session and starts an agent with options.
 // If a session already exists, it reconnects to it instead of failing.
 func (p *Plugin) StartAgentWithOptions(wt *Worktree, agentType AgentType, skipPerms bool, prompt *Prompt) tea.Cmd {
 	epoch := p.ctx.Epoch // Capture epoch for stale detection
+	backend := p.agentBackend()
 	return func() tea.Msg {
-		sessionName := tmuxSessionPrefix + sanitizeName(wt.Name)
-
-		// Check if session already exists
-		checkCmd := exec.Command("tmux", "has-session", "-t", sessionName)
-		if checkCmd.Run() == nil {
-			// Session exists - reconnect to it instead of failing
-			paneID := getPaneID(sessionName)
-			return AgentStartedMsg{
-				Epoch:         epoch,
-				WorkspaceName: wt.Name,
-				SessionName:   sessionName,
-				PaneID:        paneID,
-				AgentType:     agentType,
-				Reconnected:   true,
-			}
-		}
+		// Build the agent command with skip permissions and prompt if enabled
+		agentCmd := p.buildAgentCommand(agentType, wt, skipPerms, prompt)
+		msg := p.startAgentSession(backend, wt, agentType, agentCmd)
+		msg.Epoch = epoch
+		return msg
+	}
+}
 
-		// Create new detached session with working directory
-		args := []string{
-			"new-session",
-			"-d",              // Detached
-			"-s", sessionName, // Session name
-			"-c", wt.Path, // Working directory
-		}
+// startAgentSession creates a session for wt on backend, prepares its
+// environment and runs agentCmd in it. If the worktree's session already
+// exists on any backend, it reconnects to it instead of failing.
+func (p *Plugin) startAgentSession(backend AgentBackend, wt *Worktree, agentType AgentType, agentCmd string) AgentStartedMsg {
+	sessionName := tmuxSessionPrefix + sanitizeName(wt.Name)
 
-		cmd := exec.Command("tmux", args...)
-		if err := cmd.Run(); err != nil {
-			return AgentStartedMsg{Epoch: epoch, Err: fmt.Errorf("create session: %w", err)}
+	// Check if session already exists
+	if existing := backendFor(sessionName); existing.HasSession(sessionName) {
+		// Session exists - reconnect to it instead of failing
+		return AgentStartedMsg{
+			WorkspaceName: wt.Name,
+			SessionName:   sessionName,
+			PaneID:        existing.PaneID(sessionName),
+			AgentType:     agentType,
+			Reconnected:   true, // Flag that we reconnected to existing session
 		}
+	}
 
-		// Set history limit for scrollback capture
-		_ = exec.Command("tmux", "set-option", "-t", sessionName, "history-limit",
-			strconv.Itoa(tmuxHistoryLimit)).Run()
-
-		// Detect shell type for correct syntax (fish vs posix)
-		shellType := DetectShell()
+	// Create new detached session with working directory
+	if err := backend.NewSession(sessionName, wt.Path); err != nil {
+		return AgentStartedMsg{Err: fmt.Errorf("create session: %w", err)}
+	}
+	run := func(command string) error {
+		return backend.SendKeys(sessionName, keySpec{value: command}, keySpec{value: "Enter"})
+	}
 
-		// Set TD_SESSION_ID environment variable for td session tracking
-		tdEnvCmd := GenerateExportCommand("TD_SESSION_ID", sessionName, shellType)
-		_ = exec.Command("tmux", "send-keys", "-t", sessionName, tdEnvCmd, "Enter").Run()
+	// Detect shell type for correct syntax (fish vs posix)
+	shellType := DetectShell()
 
-		// Apply environment isolation to prevent conflicts (GOWORK, etc.)
-		envOverrides := BuildEnvOverrides(p.ctx.WorkDir)
-		if envCmd := GenerateSingleEnvCommand(envOverrides, shellType); envCmd != "" {
-			_ = exec.Command("tmux", "send-keys", "-t", sessionName, envCmd, "Enter").Run()
-		}
+	// Set TD_SESSION_ID environment variable for td session tracking
+	_ = run(GenerateExportCommand("TD_SESSION_ID", sessionName, shellType))
 
-		// If worktree has a linked task, start it in td
-		if wt.TaskID != "" {
-			tdStartCmd := fmt.Sprintf("td start %s", wt.TaskID)
-			_ = exec.Command("tmux", "send-keys", "-t", sessionName, tdStartCmd, "Enter").Run()
-		}
+	// Let agent hooks reach sidecar's remote control socket
+	if socket := os.Getenv(remote.EnvSocket); socket != "" {
+		_ = run(GenerateExportCommand(remote.EnvSocket, socket, shellType))
+	}
 
-		// Small delay to ensure env is set
-		time.Sleep(100 * time.Millisecond)
+	// Apply environment isolation to prevent conflicts (GOWORK, etc.)
+	envOverrides := BuildEnvOverrides(p.ctx.WorkDir)
+	if envCmd := GenerateSingleEnvCommand(envOverrides, shellType); envCmd != "" {
+		_ = run(envCmd)
+	}
 
-		// Build the agent command with skip permissions and prompt if enabled
-		agentCmd := p.buildAgentCommand(agentType, wt, skipPerms, prompt)
+	// If worktree has a linked task, start it in td
+	if wt.TaskID != "" {
+		_ = run(fmt.Sprintf("td start %s", wt.TaskID))
+	}
 
-		// Send the agent command to start it
-		sendCmd := exec.Command("tmux", "send-keys", "-t", sessionName, agentCmd, "Enter")
-		if err := sendCmd.Run(); err != nil {
-			// Try to kill the session if we failed to start the agent
-			_ = exec.Command("tmux", "kill-session", "-t", sessionName).Run()
-			return AgentStartedMsg{Epoch: epoch, Err: fmt.Errorf("start agent: %w", err)}
-		}
+	// Small delay to ensure env is set
+	time.Sleep(100 * time.Millisecond)
 
-		// Capture pane ID for interactive mode support
-		paneID := getPaneID(sessionName)
+	// Send the agent command to start it
+	if err := run(agentCmd); err != nil {
+		// Try to kill the session if we failed to start the agent
+		_ = backend.KillSession(sessionName)
+		return AgentStartedMsg{Err: fmt.Errorf("start agent: %w", err)}
+	}
 
-		return AgentStartedMsg{
-			Epoch:         epoch,
-			WorkspaceName: wt.Name,
-			SessionName:   sessionName,
-			PaneID:        paneID,
-			AgentType:     agentType,
-		}
+	return AgentStartedMsg{
+		WorkspaceName: wt.Name,
+		SessionName:   sessionName,
+		PaneID:        backend.PaneID(sessionName), // For interactive mode support
+		AgentType:     agentType,
 	}
 }
 
-// AttachToWorktreeDir creates a tmux session in the worktree directory and attaches to it.
+// AttachToWorktreeDir creates a session in the worktree directory and
+// attaches to it. PTY sessions can't be attached; they become the worktree's
+// shell and open in interactive mode instead.
 func (p *Plugin) AttachToWorktreeDir(wt *Worktree) tea.Cmd {
+	backend := p.agentBackend()
 	sessionName := tmuxSessionPrefix + sanitizeName(wt.Name)
 
+	if _, ok := backend.(tmuxBackend); !ok {
+		epoch := p.ctx.Epoch
+		p.pendingResumeWorktree = wt.Name
+		return func() tea.Msg {
+			if !sessionExists(sessionName) {
+				if err := backend.NewSession(sessionName, wt.Path); err != nil {
+					return AgentStartedMsg{Epoch: epoch, Err: fmt.Errorf("create session: %w", err)}
+				}
+			}
+			return AgentStartedMsg{
+				Epoch:         epoch,
+				WorkspaceName: wt.Name,
+				SessionName:   sessionName,
+				AgentType:     AgentShell,
+			}
+		}
+	}
+
 	// Check if session already exists
-	checkCmd := exec.Command("tmux", "has-session", "-t", sessionName)
-	if checkCmd.Run() != nil {
+	if !backend.HasSession(sessionName) {
 		// Session doesn't exist, create it
-		args := []string{
-			"new-session",
-			"-d",              // Detached
-			"-s", sessionName, // Session name
-			"-c", wt.Path, // Working directory
-		}
-		cmd := exec.Command("tmux", args...)
-		if err := cmd.Run(); err != nil {
+		if err := backend.NewSession(sessionName, wt.Path); err != nil {
 			return func() tea.Msg {
 				return TmuxAttachFinishedMsg{WorkspaceName: wt.Name, Err: fmt.Errorf("create session: %w", err)}
 			}
@@ -677,6 +654,11 @@ func (p *Plugin) getTaskContext(taskID string) string {
 	return fmt.Sprintf("Task: %s", task.Title)
 }
 
+// TmuxSessionName returns the tmux session name sidecar uses for wt's agent.
+func TmuxSessionName(wt *Worktree) string {
+	return tmuxSessionPrefix + sanitizeName(wt.Name)
+}
+
 // sanitizeName cleans a name for use in tmux session names.
 // tmux session names can't contain periods or colons.
 func sanitizeName(name string) string {
@@ -731,7 +713,7 @@ func (p *Plugin) scheduleAgentPoll(worktreeName string, delay time.Duration) tea
 	// Capture current generation for this worktree
 	gen := p.pollGeneration[worktreeName]
 	stagger := staggerOffset(worktreeName)
-	return tea.Tick(delay+stagger, func(t time.Time) tea.Msg {
+	return tea.Tick(p.agentPollDelay(worktreeName, delay)+stagger, func(t time.Time) tea.Msg {
 		return pollAgentMsg{WorkspaceName: worktreeName, Generation: gen}
 	})
 }
@@ -741,11 +723,30 @@ func (p *Plugin) scheduleAgentPoll(worktreeName string, delay time.Duration) tea
 // needs minimal latency. Uses the same generation tracking as scheduleAgentPoll.
 func (p *Plugin) scheduleInteractivePoll(worktreeName string, delay time.Duration) tea.Cmd {
 	gen := p.pollGeneration[worktreeName]
-	return tea.Tick(delay, func(t time.Time) tea.Msg {
+	return tea.Tick(p.agentPollDelay(worktreeName, delay), func(t time.Time) tea.Msg {
 		return pollAgentMsg{WorkspaceName: worktreeName, Generation: gen}
 	})
 }
 
+// agentPollDelay stretches poll delays for sessions whose backend pushes
+// output; their polls only catch updates the push path skipped.
+func (p *Plugin) agentPollDelay(worktreeName string, delay time.Duration) time.Duration {
+	if delay <= 0 || delay >= pollIntervalPushed {
+		return delay
+	}
+	wt := p.findWorktree(worktreeName)
+	if wt == nil || wt.Agent == nil || backendFor(wt.Agent.TmuxSession).Updates() == nil {
+		return delay
+	}
+	return pollIntervalPushed
+}
+
+// backendUpdateMsg reports new output in a session on a pushing backend.
+type backendUpdateMsg struct {
+	Session string
+	updates <-chan string
+}
+
 // AgentPollUnchangedMsg signals content unchanged, schedule next poll.
 type AgentPollUnchangedMsg struct {
 	WorkspaceName  string
@@ -759,7 +760,7 @@ type AgentPollUnchangedMsg struct {
 	PaneWidth     int // Tmux pane width for display alignment
 }
 
-// handlePollAgent captures output from a tmux session asynchronously.
+// handlePollAgent captures output from an agent session asynchronously.
 // Uses a goroutine to avoid blocking the UI thread on tmux subprocess calls (td-c2961e).
 func (p *Plugin) handlePollAgent(worktreeName string) tea.Cmd {
 	wt := p.findWorktree(worktreeName)
@@ -776,6 +777,9 @@ func (p *Plugin) handlePollAgent(worktreeName string) tea.Cmd {
 	maxBytes := p.tmuxCaptureMaxBytes
 	outputBuf := wt.Agent.OutputBuf
 	currentStatus := wt.Status
+	rules := p.statusRulesFor(agentType)
+	sessionAdapter := p.agentAdapter(agentType)
+	sessionLog := p.sessionLog(sessionName)
 
 	// Use non-joined capture when interactive mode is active for this worktree
 	// to preserve tmux line wrapping for cursor positioning (td-c7dd1e).
@@ -789,13 +793,17 @@ func (p *Plugin) handlePollAgent(worktreeName string) tea.Cmd {
 		}
 	}
 
+	backend := backendFor(sessionName)
+	_, pushed := backend.(*ptyBackend)
+
 	// When feature is enabled, use direct capture without -J for the selected worktree.
 	// This ensures the preview shows content wrapped at the pane width (which is resized
 	// to match the preview). We also resize inline to avoid races with async resize cmds.
+	// PTY sessions are always captured this way; they can't be attached at full size.
 	directCapture := false
 	var resizeTarget string
 	var previewWidth, previewHeight int
-	if !interactiveCapture && features.IsEnabled(features.TmuxInteractiveInput.Name) {
+	if !interactiveCapture && (pushed || features.IsEnabled(features.TmuxInteractiveInput.Name)) {
 		if selected := p.selectedWorktree(); selected != nil && selected.Name == worktreeName {
 			directCapture = true
 			previewWidth, previewHeight = p.calculatePreviewDimensions()
@@ -816,22 +824,26 @@ func (p *Plugin) handlePollAgent(worktreeName string) tea.Cmd {
 	return func() tea.Msg {
 		// Ensure pane is at preview width before capturing (avoids race with async resize)
 		if directCapture && resizeTarget != "" {
-			if w, h, ok := queryPaneSize(resizeTarget); !ok || w != previewWidth || h != previewHeight {
-				p.resizeTmuxPane(resizeTarget, previewWidth, previewHeight)
+			if w, h, ok := backend.PaneSize(resizeTarget); !ok || w != previewWidth || h != previewHeight {
+				backend.Resize(resizeTarget, previewWidth, previewHeight)
 			}
 		}
 
 		var output string
 		var err error
-		if interactiveCapture || directCapture {
-			output, err = capturePaneDirectWithJoin(sessionName, false)
-		} else {
+		switch {
+		case interactiveCapture || directCapture:
+			output, err = backend.Capture(sessionName, false)
+		case pushed:
+			output, err = backend.Capture(sessionName, true)
+		default:
 			output, err = capturePane(sessionName)
 		}
 		if err != nil {
 			// Session may have been killed
 			if strings.Contains(err.Error(), "can't find") ||
-				strings.Contains(err.Error(), "no server") {
+				strings.Contains(err.Error(), "no server") ||
+				isSessionDeadError(err) {
 				return AgentStoppedMsg{WorkspaceName: worktreeName}
 			}
 			// Schedule retry on other errors (with delay to prevent busy-loop)
@@ -845,7 +857,7 @@ func (p *Plugin) handlePollAgent(worktreeName string) tea.Cmd {
 		var cursorRow, cursorCol, paneHeight, paneWidth int
 		var cursorVisible, hasCursor bool
 		if interactiveCapture && cursorTarget != "" {
-			cursorRow, cursorCol, paneHeight, paneWidth, cursorVisible, hasCursor = queryCursorPositionSync(cursorTarget)
+			cursorRow, cursorCol, paneHeight, paneWidth, cursorVisible, hasCursor = backend.Cursor(cursorTarget)
 		}
 
 		output = trimCapturedOutput(output, maxBytes)
@@ -865,19 +877,31 @@ func (p *Plugin) handlePollAgent(worktreeName string) tea.Cmd {
 				PaneWidth:     paneWidth,
 			}
 		}
+		if sessionLog != nil {
+			_ = sessionLog.Snapshot(output)
+		}
 
 		// Content changed - detect status (skip during interactive mode to reduce I/O, td-f29f2d)
 		status := currentStatus
 		waitingFor := ""
 		if !interactiveCapture {
-			status = detectStatus(output)
+			status = rules.detect(output)
 			if status == StatusWaiting {
 				waitingFor = extractPrompt(output)
 			}
-			// For supported agents: supplement tmux detection with session file analysis
-			// Session files are more reliable for detecting "waiting at prompt" state
+			// For supported agents: supplement tmux detection with session state,
+			// preferring the conversation adapter over reading session files directly.
+			// Sessions are more reliable for detecting "waiting at prompt" state
 			if status == StatusActive {
-				if sessionStatus, ok := detectAgentSessionStatus(agentType, wtPath); ok {
+				var sessionStatus WorktreeStatus
+				var ok bool
+				if sessionAdapter != nil {
+					sessionStatus, ok = detectAdapterSessionStatus(sessionAdapter, wtPath)
+				}
+				if !ok {
+					sessionStatus, ok = detectAgentSessionStatus(agentType, wtPath)
+				}
+				if ok {
 					if sessionStatus == StatusWaiting {
 						status = StatusWaiting
 						waitingFor = "Waiting for input"
@@ -1073,95 +1097,6 @@ func tailUTF8Safe(s string, n int) string {
 	return s[start:]
 }
 
-// detectStatus determines agent status from captured output.
-// Optimized to avoid unnecessary string allocations.
-func detectStatus(output string) WorktreeStatus {
-	// Check tail of output for status patterns (avoids splitting entire string)
-	checkText := tailUTF8Safe(output, statusCheckBytes)
-	textLower := strings.ToLower(checkText)
-
-	// Waiting patterns (agent needs user input) - highest priority
-	// Check before thinking since waiting blocks progress
-	waitingPatterns := []string{
-		"[y/n]",       // Claude Code permission prompt
-		"(y/n)",       // Aider style
-		"allow edit",  // Claude Code file edit
-		"allow bash",  // Claude Code bash command
-		"waiting for", // Generic waiting
-		"press enter", // Continue prompt
-		"continue?",
-		"approve",
-		"confirm",
-		"do you want", // Common prompt
-		"❯",           // Claude Code input prompt (waiting for user)
-		"╰─❯",         // Claude Code prompt with tree line decoration
-	}
-
-	for _, pattern := range waitingPatterns {
-		if strings.Contains(textLower, pattern) {
-			return StatusWaiting
-		}
-	}
-
-	// Thinking patterns (agent is processing) - check after waiting
-	// Only report thinking if we have an unclosed thinking tag
-	thinkingTags := []struct {
-		open  string
-		close string
-	}{
-		{"<thinking>", "</thinking>"},
-		{"<internal_monologue>", "</internal_monologue>"},
-	}
-	for _, tag := range thinkingTags {
-		openIdx := strings.LastIndex(textLower, tag.open)
-		if openIdx >= 0 {
-			closeIdx := strings.LastIndex(textLower, tag.close)
-			// Only thinking if open tag is after close tag (or no close tag)
-			if closeIdx < openIdx {
-				return StatusThinking
-			}
-		}
-	}
-	// Generic thinking indicators (no close tag to check)
-	if strings.Contains(textLower, "thinking...") || strings.Contains(textLower, "reasoning about") {
-		return StatusThinking
-	}
-
-	// Done patterns (agent completed)
-	donePatterns := []string{
-		"task completed",
-		"all done",
-		"finished",
-		"exited with code 0",
-		"goodbye",
-	}
-
-	for _, pattern := range donePatterns {
-		if strings.Contains(textLower, pattern) {
-			return StatusDone
-		}
-	}
-
-	// Error patterns
-	errorPatterns := []string{
-		"error:",
-		"failed",
-		"exited with code 1",
-		"panic:",
-		"exception:",
-		"traceback",
-	}
-
-	for _, pattern := range errorPatterns {
-		if strings.Contains(textLower, pattern) {
-			return StatusError
-		}
-	}
-
-	// Default: active if we have output
-	return StatusActive
-}
-
 // extractPrompt finds the prompt text from output.
 // Optimized to search backwards without splitting the entire string.
 func extractPrompt(output string) string {
@@ -1201,8 +1136,8 @@ func (p *Plugin) Approve(wt *Worktree) tea.Cmd {
 		}
 
 		// Send "y" followed by Enter
-		cmd := exec.Command("tmux", "send-keys", "-t", wt.Agent.TmuxSession, "y", "Enter")
-		err := cmd.Run()
+		err := backendFor(wt.Agent.TmuxSession).SendKeys(wt.Agent.TmuxSession,
+			keySpec{value: "y"}, keySpec{value: "Enter"})
 
 		return ApproveResultMsg{
 			WorkspaceName: wt.Name,
@@ -1218,8 +1153,8 @@ func (p *Plugin) Reject(wt *Worktree) tea.Cmd {
 			return RejectResultMsg{WorkspaceName: wt.Name, Err: fmt.Errorf("no agent running")}
 		}
 
-		cmd := exec.Command("tmux", "send-keys", "-t", wt.Agent.TmuxSession, "n", "Enter")
-		err := cmd.Run()
+		err := backendFor(wt.Agent.TmuxSession).SendKeys(wt.Agent.TmuxSession,
+			keySpec{value: "n"}, keySpec{value: "Enter"})
 
 		return RejectResultMsg{
 			WorkspaceName: wt.Name,
@@ -1251,16 +1186,13 @@ func (p *Plugin) SendText(wt *Worktree, text string) tea.Cmd {
 			return SendTextResultMsg{Err: fmt.Errorf("no agent running")}
 		}
 
-		// Use -l to send literal text (no key name lookup)
-		cmd := exec.Command("tmux", "send-keys", "-l", "-t", wt.Agent.TmuxSession, text)
-		if err := cmd.Run(); err != nil {
+		// Send literal text (no key name lookup), then Enter
+		err := backendFor(wt.Agent.TmuxSession).SendKeys(wt.Agent.TmuxSession,
+			keySpec{value: text, literal: true}, keySpec{value: "Enter"})
+		if err != nil {
 			return SendTextResultMsg{Err: err}
 		}
 
-		// Send Enter separately
-		cmd = exec.Command("tmux", "send-keys", "-t", wt.Agent.TmuxSession, "Enter")
-		err := cmd.Run()
-
 		return SendTextResultMsg{
 			WorkspaceName: wt.Name,
 			Text:         text,
@@ -1270,12 +1202,16 @@ func (p *Plugin) SendText(wt *Worktree, text string) tea.Cmd {
 }
 
 // AttachToSession attaches to a tmux session using tea.ExecProcess.
+// PTY sessions can't be attached; they open in interactive mode instead.
 func (p *Plugin) AttachToSession(wt *Worktree) tea.Cmd {
 	if wt.Agent == nil {
 		return nil
 	}
 
 	sessionName := wt.Agent.TmuxSession
+	if globalPTYBackend.owns(sessionName) {
+		return p.enterInteractiveMode()
+	}
 	target := wt.Agent.TmuxPane
 	if target == "" {
 		target = sessionName
@@ -1296,8 +1232,10 @@ func (p *Plugin) StopAgent(wt *Worktree) tea.Cmd {
 
 		sessionName := wt.Agent.TmuxSession
 
+		backend := backendFor(sessionName)
+
 		// Try graceful interrupt first (Ctrl+C)
-		_ = exec.Command("tmux", "send-keys", "-t", sessionName, "C-c").Run()
+		_ = backend.SendKeys(sessionName, keySpec{value: "C-c"})
 
 		// Wait briefly for graceful shutdown
 		time.Sleep(2 * time.Second)
@@ -1305,17 +1243,16 @@ func (p *Plugin) StopAgent(wt *Worktree) tea.Cmd {
 		// Check if still running
 		if sessionExists(sessionName) {
 			// Force kill
-			_ = exec.Command("tmux", "kill-session", "-t", sessionName).Run()
+			_ = backend.KillSession(sessionName)
 		}
 
 		return AgentStoppedMsg{WorkspaceName: wt.Name}
 	}
 }
 
-// sessionExists checks if a tmux session exists.
+// sessionExists checks if a session exists.
 func sessionExists(name string) bool {
-	cmd := exec.Command("tmux", "has-session", "-t", name)
-	return cmd.Run() == nil
+	return backendFor(name).HasSession(name)
 }
 
 // detectOrphanedWorktrees marks worktrees as orphaned if they have a saved
@@ -1348,25 +1285,12 @@ func (p *Plugin) detectOrphanedWorktrees() {
 	}
 }
 
-// reconnectAgents finds and reconnects to existing tmux sessions on startup.
+// reconnectAgents finds and reconnects to existing sessions on startup.
 func (p *Plugin) reconnectAgents() tea.Cmd {
 	return func() tea.Msg {
-		// Find existing sidecar-ws-* tmux sessions
-		cmd := exec.Command("tmux", "list-sessions", "-F", "#{session_name}")
-		output, err := cmd.Output()
-		if err != nil {
-			// No tmux server running, that's fine
-			return reconnectedAgentsMsg{Cmds: nil}
-		}
-
+		// Find existing sidecar-ws-* sessions
 		var pollingCmds []tea.Cmd
-		sessions := strings.Split(string(output), "\n")
-
-		for _, session := range sessions {
-			session = strings.TrimSpace(session)
-			if session == "" {
-				continue
-			}
+		for _, session := range listAllSessions() {
 
 			// Only reconnect to sessions with our prefix
 			if !strings.HasPrefix(session, tmuxSessionPrefix) {
@@ -1384,7 +1308,7 @@ func (p *Plugin) reconnectAgents() tea.Cmd {
 			}
 
 			// Create agent record
-			paneID := getPaneID(session)
+			paneID := backendFor(session).PaneID(session)
 			agent := &Agent{
 				Type:        AgentClaude, // Default, will be detected from output
 				TmuxSession: session,
@@ -1407,13 +1331,13 @@ func (p *Plugin) reconnectAgents() tea.Cmd {
 	}
 }
 
-// Cleanup cleans up tmux sessions, optionally removing them.
+// Cleanup cleans up agent sessions, optionally removing them.
 func (p *Plugin) Cleanup(removeSessions bool) error {
 	for name, agent := range p.agents {
 		if removeSessions {
 			// Only kill sessions we created
 			if p.managedSessions[agent.TmuxSession] {
-				_ = exec.Command("tmux", "kill-session", "-t", agent.TmuxSession).Run()
+				_ = backendFor(agent.TmuxSession).KillSession(agent.TmuxSession)
 				delete(p.managedSessions, agent.TmuxSession)
 				globalPaneCache.remove(agent.TmuxSession)
 				globalActiveRegistry.remove(agent.TmuxSession) // td-018f25
@@ -1426,18 +1350,7 @@ func (p *Plugin) Cleanup(removeSessions bool) error {
 
 // CleanupOrphanedSessions removes sessions that no longer have worktrees.
 func (p *Plugin) CleanupOrphanedSessions() error {
-	cmd := exec.Command("tmux", "list-sessions", "-F", "#{session_name}")
-	output, err := cmd.Output()
-	if err != nil {
-		return nil // No tmux server
-	}
-
-	for _, session := range strings.Split(string(output), "\n") {
-		session = strings.TrimSpace(session)
-		if session == "" {
-			continue
-		}
-
+	for _, session := range listAllSessions() {
 		// Only cleanup sessions we explicitly created and tracked
 		if !p.managedSessions[session] {
 			continue
@@ -1447,7 +1360,7 @@ func (p *Plugin) CleanupOrphanedSessions() error {
 		// Use sanitized name lookup since session names are created with sanitizeName()
 		sanitizedName := strings.TrimPrefix(session, tmuxSessionPrefix)
 		if p.findWorktreeBySanitizedName(sanitizedName) == nil {
-			_ = exec.Command("tmux", "kill-session", "-t", session).Run()
+			_ = backendFor(session).KillSession(session)
 			delete(p.managedSessions, session)
 			globalPaneCache.remove(session)
 			globalActiveRegistry.remove(session) // td-018f25
@@ -1456,26 +1369,14 @@ func (p *Plugin) CleanupOrphanedSessions() error {
 	return nil
 }
 
-// validateManagedSessions checks managedSessions against actual tmux sessions
+// validateManagedSessions checks managedSessions against actual sessions
 // and returns a command that will deliver the result.
 func (p *Plugin) validateManagedSessions() tea.Cmd {
 	return func() tea.Msg {
-		existing := make(map[string]bool)
-
-		// List all tmux sessions
-		cmd := exec.Command("tmux", "list-sessions", "-F", "#{session_name}")
-		output, err := cmd.Output()
-		if err != nil {
-			// No tmux server, all sessions are gone
-			return validateManagedSessionsResultMsg{ExistingSessions: existing}
-		}
-
 		// Build set of existing sessions
-		for _, session := range strings.Split(string(output), "\n") {
-			session = strings.TrimSpace(session)
-			if session != "" {
-				existing[session] = true
-			}
+		existing := make(map[string]bool)
+		for _, session := range listAllSessions() {
+			existing[session] = true
 		}
 
 		return validateManagedSessionsResultMsg{ExistingSessions: existing}
@@ -1499,6 +1400,16 @@ func (p *Plugin) findWorktree(name string) *Worktree {
 	return nil
 }
 
+// findWorktreeBySession finds the worktree whose agent runs in a session.
+func (p *Plugin) findWorktreeBySession(session string) *Worktree {
+	for _, wt := range p.worktrees {
+		if wt.Agent != nil && wt.Agent.TmuxSession == session {
+			return wt
+		}
+	}
+	return nil
+}
+
 // findWorktreeBySanitizedName finds a worktree by its sanitized name.
 // This is used when matching tmux session names back to worktrees, since
 // session names are created with sanitizeName(wt.Name) which replaces
@@ -13,14 +13,43 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/marcus/sidecar/internal/adapter"
 )
 
 const (
 	sessionStatusTailBytes  = 2 * 1024 * 1024
 	codexSessionCacheTTL    = 5 * time.Second
 	codexCwdCacheMaxEntries = 2048
+	adapterStatusCacheTTL   = 2 * time.Second
 )
 
+// agentAdapterIDs maps agent types to the conversation adapter that reads
+// their sessions.
+var agentAdapterIDs = map[AgentType]string{
+	AgentClaude:   "claude-code",
+	AgentCodex:    "codex",
+	AgentAider:    "aider",
+	AgentGemini:   "gemini-cli",
+	AgentCursor:   "cursor-cli",
+	AgentOpenCode: "opencode",
+}
+
+type adapterStatusCacheEntry struct {
+	status    WorktreeStatus
+	ok        bool
+	expiresAt time.Time
+}
+
+// adapterStatusCache throttles adapter lookups, since polls can run several
+// times a second while an agent is streaming output.
+var adapterStatusCache = struct {
+	mu      sync.Mutex
+	entries map[string]adapterStatusCacheEntry
+}{
+	entries: make(map[string]adapterStatusCacheEntry),
+}
+
 type codexSessionCacheEntry struct {
 	sessionPath string
 	expiresAt   time.Time
@@ -69,6 +98,74 @@ func detectAgentSessionStatus(agentType AgentType, worktreePath string) (Worktre
 	}
 }
 
+// agentAdapter returns the conversation adapter for an agent type, or nil
+// if none was detected for the project.
+func (p *Plugin) agentAdapter(agentType AgentType) adapter.Adapter {
+	id, ok := agentAdapterIDs[agentType]
+	if !ok || p.ctx == nil {
+		return nil
+	}
+	return p.ctx.Adapters[id]
+}
+
+// detectAdapterSessionStatus derives status from the newest session the
+// adapter reports for the worktree. Returns StatusWaiting if the agent's last
+// message is from the assistant with no tool call still running, and
+// StatusActive if the agent is still working on a reply.
+// Returns (0, false) if there is no active session.
+func detectAdapterSessionStatus(a adapter.Adapter, worktreePath string) (WorktreeStatus, bool) {
+	key := a.ID() + "\x00" + worktreePath
+	now := time.Now()
+	adapterStatusCache.mu.Lock()
+	entry, cached := adapterStatusCache.entries[key]
+	adapterStatusCache.mu.Unlock()
+	if cached && now.Before(entry.expiresAt) {
+		return entry.status, entry.ok
+	}
+
+	status, ok := adapterSessionStatus(a, worktreePath)
+
+	adapterStatusCache.mu.Lock()
+	adapterStatusCache.entries[key] = adapterStatusCacheEntry{status: status, ok: ok, expiresAt: now.Add(adapterStatusCacheTTL)}
+	adapterStatusCache.mu.Unlock()
+	return status, ok
+}
+
+func adapterSessionStatus(a adapter.Adapter, worktreePath string) (WorktreeStatus, bool) {
+	sessions, err := a.Sessions(worktreePath)
+	if err != nil || len(sessions) == 0 {
+		return 0, false
+	}
+	latest := sessions[0]
+	for _, s := range sessions[1:] {
+		if s.UpdatedAt.After(latest.UpdatedAt) {
+			latest = s
+		}
+	}
+	if !latest.IsActive {
+		return 0, false
+	}
+
+	messages, err := a.Messages(latest.ID)
+	if err != nil {
+		return 0, false
+	}
+	for i := len(messages) - 1; i >= 0; i-- {
+		switch messages[i].Role {
+		case "user":
+			return StatusActive, true
+		case "assistant":
+			for _, tu := range messages[i].ToolUses {
+				if tu.Output == "" {
+					return StatusActive, true // tool call still running
+				}
+			}
+			return StatusWaiting, true
+		}
+	}
+	return 0, false
+}
+
 // detectClaudeSessionStatus checks Claude session files.
 // Claude stores sessions in ~/.claude/projects/{path-with-dashes}/*.jsonl
 // Path format: /Users/foo/code/project becomes -Users-foo-code-project
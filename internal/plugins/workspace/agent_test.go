@@ -197,10 +197,20 @@ func TestDetectStatus(t *testing.T) {
 			expected: StatusError,
 		},
 		{
-			name:     "failed",
-			output:   "Build failed with 3 errors",
+			name:     "failed mid-sentence is not an error",
+			output:   "Build failed with 3 errors, fixing them now",
+			expected: StatusActive,
+		},
+		{
+			name:     "non-zero exit code",
+			output:   "Running tests\nProcess exited with code 2",
 			expected: StatusError,
 		},
+		{
+			name:     "zero exit code",
+			output:   "Error: flaky test, retrying\nProcess exited with code 0",
+			expected: StatusDone,
+		},
 		{
 			name:     "traceback",
 			output:   "Traceback (most recent call last):\n  File...",
@@ -0,0 +1,161 @@
+package workspace
+
+import (
+	"errors"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Agent session backends, selected with plugins.workspace.backend.
+const (
+	backendAuto = "auto" // tmux when installed, otherwise pty
+	backendTmux = "tmux"
+	backendPTY  = "pty"
+)
+
+// errNoSession is returned by backends for sessions that don't exist. Its
+// text matches isSessionDeadError.
+var errNoSession = errors.New("no such session")
+
+// AgentBackend runs agent sessions and exposes their terminal screens.
+// Sessions are addressed by name; tmux also accepts pane IDs ("%12").
+// Keys use tmux send-keys names ("Enter", "C-c", "Up") unless literal.
+type AgentBackend interface {
+	// Name returns the backend name (backendTmux or backendPTY).
+	Name() string
+	// NewSession starts a shell session in dir.
+	NewSession(name, dir string) error
+	HasSession(name string) bool
+	ListSessions() ([]string, error)
+	KillSession(name string) error
+	// PaneID returns a stable target for the session's pane, or "" if the
+	// session name is the only target.
+	PaneID(name string) string
+
+	SendKeys(name string, keys ...keySpec) error
+	// Paste sends text as a paste, bracketed if requested.
+	Paste(name, text string, bracketed bool) error
+
+	// Capture returns recent scrollback and the screen with SGR styling,
+	// optionally joining wrapped lines.
+	Capture(name string, joinWrapped bool) (string, error)
+	// Cursor returns the 0-indexed cursor position and pane size.
+	Cursor(name string) (row, col, paneHeight, paneWidth int, visible, ok bool)
+	PaneSize(name string) (width, height int, ok bool)
+	Resize(name string, width, height int)
+
+	// Updates delivers the names of sessions with new output. It is nil for
+	// backends that must be polled.
+	Updates() <-chan string
+}
+
+// newAgentBackend returns the backend for new agent sessions.
+func newAgentBackend(kind string) AgentBackend {
+	switch kind {
+	case backendTmux:
+		return tmuxBackend{}
+	case backendPTY:
+		return globalPTYBackend
+	}
+	if isTmuxInstalled() {
+		return tmuxBackend{}
+	}
+	return globalPTYBackend
+}
+
+// backendFor returns the backend that owns a session or pane. Sessions the
+// PTY backend doesn't know are assumed to be tmux sessions.
+func backendFor(name string) AgentBackend {
+	if globalPTYBackend.owns(name) {
+		return globalPTYBackend
+	}
+	return tmuxBackend{}
+}
+
+// listAllSessions returns the sessions of every backend. Without a tmux
+// server only PTY sessions are listed.
+func listAllSessions() []string {
+	sessions, _ := tmuxBackend{}.ListSessions()
+	ptySessions, _ := globalPTYBackend.ListSessions()
+	return append(sessions, ptySessions...)
+}
+
+// tmuxBackend runs sessions as detached tmux sessions.
+type tmuxBackend struct{}
+
+func (tmuxBackend) Name() string { return backendTmux }
+
+func (tmuxBackend) NewSession(name, dir string) error {
+	if err := exec.Command("tmux", "new-session", "-d", "-s", name, "-c", dir).Run(); err != nil {
+		return err
+	}
+	// Set history limit for scrollback capture
+	_ = exec.Command("tmux", "set-option", "-t", name, "history-limit",
+		strconv.Itoa(tmuxHistoryLimit)).Run()
+	return nil
+}
+
+func (tmuxBackend) HasSession(name string) bool {
+	return exec.Command("tmux", "has-session", "-t", name).Run() == nil
+}
+
+func (tmuxBackend) ListSessions() ([]string, error) {
+	output, err := exec.Command("tmux", "list-sessions", "-F", "#{session_name}").Output()
+	if err != nil {
+		return nil, err
+	}
+	var sessions []string
+	for _, s := range strings.Split(string(output), "\n") {
+		if s = strings.TrimSpace(s); s != "" {
+			sessions = append(sessions, s)
+		}
+	}
+	return sessions, nil
+}
+
+func (tmuxBackend) KillSession(name string) error {
+	return exec.Command("tmux", "kill-session", "-t", name).Run()
+}
+
+func (tmuxBackend) PaneID(name string) string { return getPaneID(name) }
+
+func (tmuxBackend) SendKeys(name string, keys ...keySpec) error {
+	for _, k := range keys {
+		var err error
+		if k.literal {
+			err = sendLiteralToTmux(name, k.value)
+		} else {
+			err = sendKeyToTmux(name, k.value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (tmuxBackend) Paste(name, text string, bracketed bool) error {
+	if bracketed {
+		return sendBracketedPasteToTmux(name, text)
+	}
+	return sendPasteToTmux(name, text)
+}
+
+func (tmuxBackend) Capture(name string, joinWrapped bool) (string, error) {
+	return capturePaneDirectWithJoin(name, joinWrapped)
+}
+
+func (tmuxBackend) Cursor(name string) (row, col, paneHeight, paneWidth int, visible, ok bool) {
+	return queryCursorPositionSync(name)
+}
+
+func (tmuxBackend) PaneSize(name string) (width, height int, ok bool) {
+	return queryPaneSize(name)
+}
+
+func (tmuxBackend) Resize(name string, width, height int) {
+	resizeTmuxPane(name, width, height)
+}
+
+func (tmuxBackend) Updates() <-chan string { return nil }
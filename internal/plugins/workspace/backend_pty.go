@@ -0,0 +1,360 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/charmbracelet/x/xpty"
+	"github.com/marcus/sidecar/internal/vterm"
+)
+
+const (
+	// Size of new PTY sessions until the preview resizes them (tmux's default).
+	ptyDefaultWidth  = 80
+	ptyDefaultHeight = 24
+
+	ptyReadBufferSize = 32 * 1024
+
+	// Pending update notifications; each session has at most one queued.
+	ptyUpdateBuffer = 64
+)
+
+// globalPTYBackend owns every PTY session in the process.
+var globalPTYBackend = newPTYBackend()
+
+// ptyBackend runs sessions as login shells on in-process pseudo-terminals.
+// Output feeds a terminal emulator, so captures don't spawn processes and
+// sessions are announced on Updates as output arrives. Sessions end with
+// sidecar.
+type ptyBackend struct {
+	mu       sync.Mutex
+	sessions map[string]*ptySession
+	updates  chan string
+}
+
+type ptySession struct {
+	name      string
+	pty       xpty.Pty
+	cmd       *exec.Cmd
+	term      *vterm.Terminal
+	exited    bool        // shell has exited; guarded by ptyBackend.mu
+	notified  atomic.Bool // an update is queued; cleared by Capture
+	closeOnce sync.Once
+}
+
+func newPTYBackend() *ptyBackend {
+	return &ptyBackend{
+		sessions: make(map[string]*ptySession),
+		updates:  make(chan string, ptyUpdateBuffer),
+	}
+}
+
+func (b *ptyBackend) Name() string { return backendPTY }
+
+func (b *ptyBackend) NewSession(name, dir string) error {
+	if b.HasSession(name) {
+		return fmt.Errorf("duplicate session: %s", name)
+	}
+	pty, err := xpty.NewPty(ptyDefaultWidth, ptyDefaultHeight)
+	if err != nil {
+		return err
+	}
+	cmd := ptyShellCommand()
+	cmd.Dir = dir
+	cmd.Env = ptyEnv()
+	if err := pty.Start(cmd); err != nil {
+		_ = pty.Close()
+		return err
+	}
+
+	s := &ptySession{name: name, pty: pty, cmd: cmd}
+	s.term = vterm.New(ptyDefaultWidth, ptyDefaultHeight, tmuxHistoryLimit, pty)
+	b.mu.Lock()
+	b.sessions[name] = s
+	b.mu.Unlock()
+
+	go b.read(s)
+	go b.wait(s)
+	return nil
+}
+
+// ptyEnv returns the environment for PTY shells: sidecar's own, without tmux
+// variables, describing the emulator as a 256-color xterm.
+func ptyEnv() []string {
+	var env []string
+	for _, kv := range os.Environ() {
+		key, _, _ := strings.Cut(kv, "=")
+		switch key {
+		case "TMUX", "TMUX_PANE", "TERM", "COLORTERM":
+			continue
+		}
+		env = append(env, kv)
+	}
+	return append(env, "TERM=xterm-256color", "COLORTERM=truecolor")
+}
+
+// read feeds PTY output to the emulator until the PTY is closed.
+func (b *ptyBackend) read(s *ptySession) {
+	buf := make([]byte, ptyReadBufferSize)
+	for {
+		n, err := s.pty.Read(buf)
+		if n > 0 {
+			_, _ = s.term.Write(buf[:n])
+			b.notify(s)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// wait marks the session exited when its shell ends. The session stays
+// registered until its exit is observed by Capture or it is killed, so
+// callers see a dead session rather than falling through to tmux.
+func (b *ptyBackend) wait(s *ptySession) {
+	_ = xpty.WaitProcess(context.Background(), s.cmd)
+	b.mu.Lock()
+	s.exited = true
+	b.mu.Unlock()
+	s.close()
+	b.notify(s)
+}
+
+func (s *ptySession) close() {
+	s.closeOnce.Do(func() {
+		_ = s.pty.Close()
+		killPTYProcess(s.cmd)
+	})
+}
+
+// notify queues an update for a session unless one is already pending.
+func (b *ptyBackend) notify(s *ptySession) {
+	if s.notified.Swap(true) {
+		return
+	}
+	select {
+	case b.updates <- s.name:
+	default:
+		s.notified.Store(false)
+	}
+}
+
+// owns reports whether the backend has a session with this name, including
+// exited sessions that haven't been reaped.
+func (b *ptyBackend) owns(name string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, ok := b.sessions[name]
+	return ok
+}
+
+// session returns a running session.
+func (b *ptyBackend) session(name string) (*ptySession, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s, ok := b.sessions[name]
+	if !ok || s.exited {
+		return nil, fmt.Errorf("%w: %s", errNoSession, name)
+	}
+	return s, nil
+}
+
+func (b *ptyBackend) HasSession(name string) bool {
+	_, err := b.session(name)
+	return err == nil
+}
+
+func (b *ptyBackend) ListSessions() ([]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var names []string
+	for name, s := range b.sessions {
+		if !s.exited {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (b *ptyBackend) KillSession(name string) error {
+	b.mu.Lock()
+	s, ok := b.sessions[name]
+	delete(b.sessions, name)
+	b.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", errNoSession, name)
+	}
+	s.close()
+	return nil
+}
+
+func (b *ptyBackend) PaneID(string) string { return "" }
+
+func (b *ptyBackend) SendKeys(name string, keys ...keySpec) error {
+	s, err := b.session(name)
+	if err != nil {
+		return err
+	}
+	appCursor := s.term.AppCursorKeys()
+	var data strings.Builder
+	for _, k := range keys {
+		if k.literal {
+			data.WriteString(k.value)
+		} else {
+			data.WriteString(ptyKeySequence(k.value, appCursor))
+		}
+	}
+	_, err = s.pty.Write([]byte(data.String()))
+	return err
+}
+
+// Paste writes text as a paste. Like tmux paste-buffer, line feeds become
+// carriage returns unless the paste is bracketed; it is bracketed when asked
+// or when the program enabled bracketed paste.
+func (b *ptyBackend) Paste(name, text string, bracketed bool) error {
+	s, err := b.session(name)
+	if err != nil {
+		return err
+	}
+	if bracketed || s.term.BracketedPaste() {
+		text = bracketedPasteStart + text + bracketedPasteEnd
+	} else {
+		text = strings.ReplaceAll(text, "\n", "\r")
+	}
+	_, err = s.pty.Write([]byte(text))
+	return err
+}
+
+// Capture renders the session's screen. Capturing an exited session reports
+// errNoSession once and forgets it.
+func (b *ptyBackend) Capture(name string, joinWrapped bool) (string, error) {
+	b.mu.Lock()
+	s, ok := b.sessions[name]
+	if ok && s.exited {
+		delete(b.sessions, name)
+		ok = false
+	}
+	b.mu.Unlock()
+	if !ok {
+		return "", fmt.Errorf("%w: %s", errNoSession, name)
+	}
+	s.notified.Store(false)
+	return s.term.Capture(captureLineCount, joinWrapped), nil
+}
+
+func (b *ptyBackend) Cursor(name string) (row, col, paneHeight, paneWidth int, visible, ok bool) {
+	s, err := b.session(name)
+	if err != nil {
+		return 0, 0, 0, 0, false, false
+	}
+	row, col, visible = s.term.Cursor()
+	paneWidth, paneHeight = s.term.Size()
+	return row, col, paneHeight, paneWidth, visible, true
+}
+
+func (b *ptyBackend) PaneSize(name string) (width, height int, ok bool) {
+	s, err := b.session(name)
+	if err != nil {
+		return 0, 0, false
+	}
+	width, height = s.term.Size()
+	return width, height, true
+}
+
+// Resize resizes the emulator before the PTY so the program's redraw after
+// SIGWINCH lands on a screen of the new size.
+func (b *ptyBackend) Resize(name string, width, height int) {
+	s, err := b.session(name)
+	if err != nil {
+		return
+	}
+	w, h := s.term.Size()
+	if width > 0 {
+		w = width
+	}
+	if height > 0 {
+		h = height
+	}
+	s.term.Resize(w, h)
+	_ = s.pty.Resize(w, h)
+}
+
+func (b *ptyBackend) Updates() <-chan string { return b.updates }
+
+// modes reports whether the session's program enabled bracketed paste and
+// mouse reporting.
+func (b *ptyBackend) modes(name string) (bracketed, mouse, ok bool) {
+	s, err := b.session(name)
+	if err != nil {
+		return false, false, false
+	}
+	return s.term.BracketedPaste(), s.term.MouseReporting(), true
+}
+
+// ptyKeys maps tmux key names to the bytes an xterm sends for them. Cursor
+// keys are handled separately because they depend on the cursor key mode.
+var ptyKeys = map[string]string{
+	"Enter":  "\r",
+	"BSpace": "\x7f",
+	"DC":     "\x1b[3~",
+	"IC":     "\x1b[2~",
+	"Tab":    "\t",
+	"BTab":   "\x1b[Z",
+	"Space":  " ",
+	"Escape": "\x1b",
+	"PPage":  "\x1b[5~",
+	"NPage":  "\x1b[6~",
+	"F1":     "\x1bOP",
+	"F2":     "\x1bOQ",
+	"F3":     "\x1bOR",
+	"F4":     "\x1bOS",
+	"F5":     "\x1b[15~",
+	"F6":     "\x1b[17~",
+	"F7":     "\x1b[18~",
+	"F8":     "\x1b[19~",
+	"F9":     "\x1b[20~",
+	"F10":    "\x1b[21~",
+	"F11":    "\x1b[23~",
+	"F12":    "\x1b[24~",
+}
+
+var ptyCursorKeys = map[string]byte{
+	"Up": 'A', "Down": 'B', "Right": 'C', "Left": 'D', "Home": 'H', "End": 'F',
+}
+
+// ptyKeySequence translates a tmux key name to terminal input. Names it
+// doesn't know are sent as text, as tmux send-keys does.
+func ptyKeySequence(key string, appCursor bool) string {
+	if seq, ok := ptyKeys[key]; ok {
+		return seq
+	}
+	if final, ok := ptyCursorKeys[key]; ok {
+		if appCursor {
+			return "\x1bO" + string(final)
+		}
+		return "\x1b[" + string(final)
+	}
+	if rest, ok := strings.CutPrefix(key, "M-"); ok && rest != "" {
+		return "\x1b" + ptyKeySequence(rest, appCursor)
+	}
+	if rest, ok := strings.CutPrefix(key, "C-"); ok && len(rest) == 1 {
+		switch c := rest[0]; {
+		case c >= 'a' && c <= 'z':
+			return string(rune(c - 'a' + 1))
+		case c >= '@' && c <= '_':
+			return string(rune(c - '@'))
+		case c == ' ':
+			return "\x00"
+		case c == '?':
+			return "\x7f"
+		}
+	}
+	return key
+}
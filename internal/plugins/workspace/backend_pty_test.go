@@ -0,0 +1,147 @@
+package workspace
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcus/sidecar/internal/plugin"
+)
+
+func TestPTYKeySequence(t *testing.T) {
+	tests := []struct {
+		key       string
+		appCursor bool
+		want      string
+	}{
+		{"Enter", false, "\r"},
+		{"BSpace", false, "\x7f"},
+		{"Up", false, "\x1b[A"},
+		{"Up", true, "\x1bOA"},
+		{"End", true, "\x1bOF"},
+		{"F5", false, "\x1b[15~"},
+		{"C-c", false, "\x03"},
+		{"C-[", false, "\x1b"},
+		{"M-b", false, "\x1bb"},
+		{"M-Left", false, "\x1b\x1b[D"},
+		{"echo hi", false, "echo hi"},
+	}
+	for _, tt := range tests {
+		if got := ptyKeySequence(tt.key, tt.appCursor); got != tt.want {
+			t.Errorf("ptyKeySequence(%q, %v) = %q, want %q", tt.key, tt.appCursor, got, tt.want)
+		}
+	}
+}
+
+// waitForCapture polls a PTY session until its capture contains want.
+func waitForCapture(t *testing.T, b *ptyBackend, name, want string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		out, err := b.Capture(name, true)
+		if err != nil {
+			t.Fatalf("capture: %v", err)
+		}
+		if strings.Contains(out, want) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("capture never contained %q:\n%s", want, out)
+		}
+		select {
+		case <-b.Updates():
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+func TestPTYBackendSession(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test drives a POSIX shell")
+	}
+	t.Setenv("SHELL", "/bin/sh")
+
+	b := newPTYBackend()
+	const name = "sidecar-ws-pty-test"
+	if err := b.NewSession(name, t.TempDir()); err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	defer func() { _ = b.KillSession(name) }()
+
+	if err := b.NewSession(name, t.TempDir()); err == nil {
+		t.Error("expected duplicate session error")
+	}
+	if sessions, _ := b.ListSessions(); len(sessions) != 1 || sessions[0] != name {
+		t.Errorf("ListSessions = %v", sessions)
+	}
+
+	err := b.SendKeys(name, keySpec{value: "echo pty-$((20+22))", literal: true}, keySpec{value: "Enter"})
+	if err != nil {
+		t.Fatalf("SendKeys: %v", err)
+	}
+	waitForCapture(t, b, name, "pty-42")
+
+	b.Resize(name, 100, 30)
+	if w, h, ok := b.PaneSize(name); !ok || w != 100 || h != 30 {
+		t.Errorf("PaneSize = %dx%d ok=%v, want 100x30", w, h, ok)
+	}
+
+	// An exited shell is reported dead once, then forgotten.
+	if err := b.SendKeys(name, keySpec{value: "exit", literal: true}, keySpec{value: "Enter"}); err != nil {
+		t.Fatalf("SendKeys: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for b.HasSession(name) {
+		if time.Now().After(deadline) {
+			t.Fatal("session still running after exit")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if !b.owns(name) {
+		t.Error("exited session should stay registered until captured")
+	}
+	if _, err := b.Capture(name, false); !errors.Is(err, errNoSession) || !isSessionDeadError(err) {
+		t.Errorf("Capture after exit = %v, want errNoSession", err)
+	}
+	if b.owns(name) {
+		t.Error("exited session should be forgotten after capture")
+	}
+}
+
+func TestShellOnPTYBackend(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test drives a POSIX shell")
+	}
+	t.Setenv("SHELL", "/bin/sh")
+
+	p := New()
+	p.ctx = &plugin.Context{WorkDir: t.TempDir()}
+	p.backend = globalPTYBackend
+
+	msg, ok := p.createNewShell("")().(ShellCreatedMsg)
+	if !ok || msg.Err != nil {
+		t.Fatalf("createNewShell = %+v", msg)
+	}
+	name := msg.SessionName
+	defer func() { _ = globalPTYBackend.KillSession(name) }()
+	if !globalPTYBackend.HasSession(name) {
+		t.Fatalf("shell %s not started on the PTY backend", name)
+	}
+	if got := p.discoverTmuxSessionNames(); len(got) != 1 || got[0] != name {
+		t.Errorf("discoverTmuxSessionNames = %v, want [%s]", got, name)
+	}
+
+	if res := p.runCommandInShell(name, "echo shell-$((40+2))")(); res != nil {
+		t.Fatalf("runCommandInShell = %+v", res)
+	}
+	waitForCapture(t, globalPTYBackend, name, "shell-42")
+
+	if _, ok := p.killShellSessionByName(name)().(ShellKilledMsg); !ok {
+		t.Fatal("expected ShellKilledMsg")
+	}
+	if globalPTYBackend.owns(name) {
+		t.Error("killed shell is still registered")
+	}
+}
@@ -0,0 +1,31 @@
+//go:build !windows
+
+package workspace
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+)
+
+// ptyShellCommand returns the user's shell started as a login shell, as tmux
+// starts it, leading a new session with the PTY as controlling terminal so
+// job control and Ctrl+C work.
+func ptyShellCommand() *exec.Cmd {
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+	cmd := exec.Command(shell)
+	cmd.Args = []string{"-" + filepath.Base(shell)}
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true}
+	return cmd
+}
+
+// killPTYProcess hangs up the shell's process group.
+func killPTYProcess(cmd *exec.Cmd) {
+	if cmd.Process != nil {
+		_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGHUP)
+	}
+}
@@ -0,0 +1,24 @@
+//go:build windows
+
+package workspace
+
+import (
+	"os"
+	"os/exec"
+)
+
+// ptyShellCommand returns the user's command interpreter.
+func ptyShellCommand() *exec.Cmd {
+	shell := os.Getenv("COMSPEC")
+	if shell == "" {
+		shell = "cmd.exe"
+	}
+	return exec.Command(shell)
+}
+
+// killPTYProcess terminates the shell.
+func killPTYProcess(cmd *exec.Cmd) {
+	if cmd.Process != nil {
+		_ = cmd.Process.Kill()
+	}
+}
@@ -0,0 +1,442 @@
+package workspace
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/adapter"
+	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/pricing"
+)
+
+// budgetCheckInterval is how often usage is measured against budgets.
+const budgetCheckInterval = 30 * time.Second
+
+// Budget limits an agent run in a worktree. Zero limits are unlimited.
+type Budget struct {
+	MaxTokens  int           // input + output tokens across the worktree's sessions
+	MaxCost    float64       // estimated USD
+	MaxRuntime time.Duration // wall-clock time since the budget started
+	Since      time.Time     // when the budget started
+}
+
+// BudgetUsage is a worktree's consumption measured against its budget.
+type BudgetUsage struct {
+	Tokens  int
+	Cost    float64
+	Runtime time.Duration
+}
+
+// Budget levels, in increasing severity.
+const (
+	budgetOK = iota
+	budgetWarn
+	budgetExceeded
+)
+
+// budgetState tracks a worktree's measured usage and the alerts already given.
+type budgetState struct {
+	usage       BudgetUsage
+	level       int
+	interrupted bool
+}
+
+// ParseBudget parses a budget spec of space- or comma-separated limits:
+// "$5" (dollars), "2h" or "90m" (runtime), and "500k", "2M" or "200000"
+// (tokens). Note "2m" is two minutes while "2M" is two million tokens.
+func ParseBudget(spec string) (Budget, error) {
+	var b Budget
+	fields := strings.FieldsFunc(spec, func(r rune) bool { return r == ' ' || r == ',' })
+	for _, f := range fields {
+		switch {
+		case strings.HasPrefix(f, "$"):
+			cost, err := strconv.ParseFloat(f[1:], 64)
+			if err != nil || cost <= 0 {
+				return Budget{}, fmt.Errorf("invalid cost %q", f)
+			}
+			b.MaxCost = cost
+		case strings.EqualFold(f, "tokens"), strings.EqualFold(f, "tok"):
+			// Optional unit word after a token count
+		default:
+			if d, err := time.ParseDuration(f); err == nil {
+				if d <= 0 {
+					return Budget{}, fmt.Errorf("invalid runtime %q", f)
+				}
+				b.MaxRuntime = d
+				continue
+			}
+			tokens, err := parseTokenCount(f)
+			if err != nil {
+				return Budget{}, fmt.Errorf("invalid limit %q (use $5, 2h or 500k)", f)
+			}
+			b.MaxTokens = tokens
+		}
+	}
+	return b, nil
+}
+
+// parseTokenCount parses "500k", "2M", "1.5M" or a plain count.
+func parseTokenCount(s string) (int, error) {
+	mult := 1.0
+	switch {
+	case strings.HasSuffix(s, "k"), strings.HasSuffix(s, "K"):
+		mult, s = 1e3, s[:len(s)-1]
+	case strings.HasSuffix(s, "M"):
+		mult, s = 1e6, s[:len(s)-1]
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid token count")
+	}
+	return int(math.Round(n * mult)), nil
+}
+
+// IsZero reports whether the budget sets no limits.
+func (b Budget) IsZero() bool {
+	return b.MaxTokens == 0 && b.MaxCost == 0 && b.MaxRuntime == 0
+}
+
+// String formats the budget as a spec ParseBudget accepts.
+func (b Budget) String() string {
+	var parts []string
+	if b.MaxCost > 0 {
+		parts = append(parts, "$"+strconv.FormatFloat(b.MaxCost, 'f', -1, 64))
+	}
+	if b.MaxRuntime > 0 {
+		parts = append(parts, formatBudgetDuration(b.MaxRuntime))
+	}
+	if b.MaxTokens > 0 {
+		parts = append(parts, formatTokenCount(b.MaxTokens))
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatTokenCount renders a count as "2M", "1.5M", "500k" or "900".
+func formatTokenCount(n int) string {
+	switch {
+	case n >= 1e6:
+		return strconv.FormatFloat(math.Round(float64(n)/1e5)/10, 'f', -1, 64) + "M"
+	case n >= 1e3:
+		return strconv.FormatFloat(math.Round(float64(n)/1e2)/10, 'f', -1, 64) + "k"
+	default:
+		return strconv.Itoa(n)
+	}
+}
+
+// formatBudgetDuration renders a duration without zero units ("2h", "1h30m").
+func formatBudgetDuration(d time.Duration) string {
+	s := d.Round(time.Second).String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// Used returns the largest share of any limit that usage has consumed.
+func (b Budget) Used(u BudgetUsage) float64 {
+	used := 0.0
+	if b.MaxTokens > 0 {
+		used = math.Max(used, float64(u.Tokens)/float64(b.MaxTokens))
+	}
+	if b.MaxCost > 0 {
+		used = math.Max(used, u.Cost/b.MaxCost)
+	}
+	if b.MaxRuntime > 0 {
+		used = math.Max(used, float64(u.Runtime)/float64(b.MaxRuntime))
+	}
+	return used
+}
+
+// level classifies usage against the budget given the warning percentage.
+func (b Budget) level(u BudgetUsage, warnPercent int) int {
+	used := b.Used(u)
+	switch {
+	case used >= 1:
+		return budgetExceeded
+	case used*100 >= float64(warnPercent):
+		return budgetWarn
+	default:
+		return budgetOK
+	}
+}
+
+// describe summarizes usage against each limit, e.g. "$4.10/$5, 1h12m/2h".
+func (b Budget) describe(u BudgetUsage) string {
+	var parts []string
+	if b.MaxCost > 0 {
+		parts = append(parts, fmt.Sprintf("$%.2f/$%s", u.Cost, strconv.FormatFloat(b.MaxCost, 'f', -1, 64)))
+	}
+	if b.MaxRuntime > 0 {
+		parts = append(parts, formatBudgetDuration(u.Runtime.Truncate(time.Minute))+"/"+formatBudgetDuration(b.MaxRuntime))
+	}
+	if b.MaxTokens > 0 {
+		parts = append(parts, formatTokenCount(u.Tokens)+"/"+formatTokenCount(b.MaxTokens)+" tok")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// budgetFile is the on-disk form of a worktree's budget.
+type budgetFile struct {
+	Spec  string    `json:"spec"`
+	Since time.Time `json:"since"`
+}
+
+// saveBudget persists a worktree's budget, removing it when b sets no limits.
+func saveBudget(worktreePath string, b *Budget) error {
+	path := filepath.Join(worktreePath, sidecarBudgetFile)
+	if b == nil || b.IsZero() {
+		_ = os.Remove(path)
+		return nil
+	}
+	data, err := json.Marshal(budgetFile{Spec: b.String(), Since: b.Since})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// loadBudget reads a worktree's budget, or nil if it has none.
+func loadBudget(worktreePath string) *Budget {
+	data, err := os.ReadFile(filepath.Join(worktreePath, sidecarBudgetFile))
+	if err != nil {
+		return nil
+	}
+	var f budgetFile
+	if json.Unmarshal(data, &f) != nil {
+		return nil
+	}
+	b, err := ParseBudget(f.Spec)
+	if err != nil || b.IsZero() {
+		return nil
+	}
+	b.Since = f.Since
+	return &b
+}
+
+// BudgetUsageMsg delivers measured usage for budgeted worktrees.
+type BudgetUsageMsg struct {
+	Epoch uint64
+	Usage map[string]BudgetUsage // by worktree name
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m BudgetUsageMsg) GetEpoch() uint64 { return m.Epoch }
+
+// budgetCheckMsg triggers a periodic budget check.
+type budgetCheckMsg struct {
+	Epoch uint64
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m budgetCheckMsg) GetEpoch() uint64 { return m.Epoch }
+
+// scheduleBudgetCheck schedules the next budget check.
+func (p *Plugin) scheduleBudgetCheck() tea.Cmd {
+	epoch := p.ctx.Epoch
+	return tea.Tick(budgetCheckInterval, func(time.Time) tea.Msg {
+		return budgetCheckMsg{Epoch: epoch}
+	})
+}
+
+// handleBudgetCheck measures usage and schedules the next check.
+func (p *Plugin) handleBudgetCheck(msg budgetCheckMsg) tea.Cmd {
+	if plugin.IsStale(p.ctx, msg) {
+		return nil
+	}
+	return tea.Batch(p.measureBudgets(), p.scheduleBudgetCheck())
+}
+
+// measureBudgets returns a command that measures the usage of budgeted
+// worktrees with a running agent, or that have not been measured yet.
+func (p *Plugin) measureBudgets() tea.Cmd {
+	type target struct {
+		name, path string
+		budget     Budget
+		sessions   func(string) (BudgetUsage, error)
+	}
+	var targets []target
+	for _, wt := range p.worktrees {
+		if wt.Budget == nil || wt.IsMain || wt.IsMissing {
+			continue
+		}
+		if _, measured := p.budgets[wt.Name]; measured && wt.Agent == nil {
+			continue
+		}
+		t := target{name: wt.Name, path: wt.Path, budget: *wt.Budget}
+		if a := p.transcriptAdapter(wt); a != nil {
+			since := wt.Budget.Since
+			t.sessions = func(path string) (BudgetUsage, error) {
+				return usageSince(a, path, since)
+			}
+		}
+		targets = append(targets, t)
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+	epoch := p.ctx.Epoch
+	return func() tea.Msg {
+		usage := make(map[string]BudgetUsage, len(targets))
+		now := time.Now()
+		for _, t := range targets {
+			var u BudgetUsage
+			if t.sessions != nil {
+				u, _ = t.sessions(t.path) // runtime is still enforced without sessions
+			}
+			if !t.budget.Since.IsZero() {
+				u.Runtime = now.Sub(t.budget.Since)
+			}
+			usage[t.name] = u
+		}
+		return BudgetUsageMsg{Epoch: epoch, Usage: usage}
+	}
+}
+
+// usageSince sums the tokens and cost of the agent messages in path sent since
+// the budget started, so a session resumed under a budget only counts its new
+// turns. Sessions whose messages carry no token usage are counted whole, and
+// only if they started under the budget.
+func usageSince(a adapter.Adapter, path string, since time.Time) (BudgetUsage, error) {
+	sessions, err := a.Sessions(path)
+	if err != nil {
+		return BudgetUsage{}, err
+	}
+	var u BudgetUsage
+	for _, s := range sessions {
+		if s.UpdatedAt.Before(since) {
+			continue
+		}
+		msgs, err := a.Messages(s.ID)
+		if err != nil {
+			return BudgetUsage{}, err
+		}
+		var perMessage bool
+		for _, m := range msgs {
+			if m.TokenUsage == (adapter.TokenUsage{}) {
+				continue
+			}
+			perMessage = true
+			if m.Timestamp.Before(since) {
+				continue
+			}
+			u.Tokens += m.InputTokens + m.OutputTokens
+			cost, _ := pricing.Cost(m.Model, m.Timestamp, pricing.Usage{
+				Input:      m.InputTokens,
+				Output:     m.OutputTokens,
+				CacheRead:  m.CacheRead,
+				CacheWrite: m.CacheWrite,
+			})
+			u.Cost += cost
+		}
+		if !perMessage && !s.CreatedAt.Before(since) {
+			u.Tokens += s.TotalTokens
+			u.Cost += s.EstCost
+		}
+	}
+	return u, nil
+}
+
+// handleBudgetUsage records usage and warns when a worktree crosses its
+// warning threshold or reaches a limit. At a limit the agent is interrupted
+// once if budgets.interrupt is set.
+func (p *Plugin) handleBudgetUsage(msg BudgetUsageMsg) tea.Cmd {
+	if plugin.IsStale(p.ctx, msg) {
+		return nil
+	}
+	warnPercent, interrupt := 80, false
+	if p.ctx.Config != nil {
+		warnPercent = p.ctx.Config.Plugins.Workspace.Budgets.WarnPercent
+		interrupt = p.ctx.Config.Plugins.Workspace.Budgets.Interrupt
+	}
+	if p.budgets == nil {
+		p.budgets = make(map[string]*budgetState)
+	}
+
+	var cmds []tea.Cmd
+	for name, u := range msg.Usage {
+		wt := p.findWorktree(name)
+		if wt == nil || wt.Budget == nil {
+			continue
+		}
+		st := p.budgets[name]
+		if st == nil {
+			st = &budgetState{}
+			p.budgets[name] = st
+		}
+		st.usage = u
+		level := wt.Budget.level(u, warnPercent)
+		if level <= st.level {
+			// A raised budget re-arms the interrupt
+			if level < budgetExceeded {
+				st.interrupted = false
+			}
+			st.level = level
+			continue
+		}
+		st.level = level
+		summary := wt.Budget.describe(u)
+		session := ""
+		if wt.Agent != nil {
+			session = wt.Agent.TmuxSession
+		}
+
+		if level == budgetWarn {
+			p.logSessionEvent(session, "budget %d%% used: %s", int(wt.Budget.Used(u)*100), summary)
+			p.toastMessage = fmt.Sprintf("%s: %d%% of budget used (%s)", name, int(wt.Budget.Used(u)*100), summary)
+			p.toastTime = time.Now()
+			continue
+		}
+
+		p.logSessionEvent(session, "budget exceeded: %s", summary)
+		p.toastMessage = fmt.Sprintf("%s: budget exceeded (%s)", name, summary)
+		p.toastTime = time.Now()
+		if interrupt && wt.Agent != nil && !st.interrupted {
+			st.interrupted = true
+			p.logSessionEvent(session, "interrupting agent: budget exceeded")
+			p.toastMessage += ", agent interrupted"
+			cmds = append(cmds, interruptAgent(wt.Agent))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// interruptAgent sends an agent the key that stops its current turn: Escape
+// for Claude Code, Ctrl+C for other agents.
+func interruptAgent(agent *Agent) tea.Cmd {
+	sessionName, agentType := agent.TmuxSession, agent.Type
+	return func() tea.Msg {
+		key := "C-c"
+		if agentType == AgentClaude {
+			key = "Escape"
+		}
+		_ = backendFor(sessionName).SendKeys(sessionName, keySpec{value: key})
+		return nil
+	}
+}
+
+// budgetLabel returns the sidebar label for a worktree's budget and its
+// level, or "" when it has no budget or hasn't been measured yet.
+func (p *Plugin) budgetLabel(wt *Worktree) (string, int) {
+	if wt.Budget == nil {
+		return "", budgetOK
+	}
+	st := p.budgets[wt.Name]
+	if st == nil {
+		return "", budgetOK
+	}
+	pct := int(wt.Budget.Used(st.usage) * 100)
+	if st.level == budgetExceeded {
+		return fmt.Sprintf("✗ budget %d%%", pct), st.level
+	}
+	return fmt.Sprintf("budget %d%%", pct), st.level
+}
@@ -0,0 +1,166 @@
+package workspace
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marcus/sidecar/internal/adapter"
+	"github.com/marcus/sidecar/internal/config"
+	"github.com/marcus/sidecar/internal/plugin"
+)
+
+func TestParseBudget(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    Budget
+		str     string
+		wantErr bool
+	}{
+		{spec: "", want: Budget{}, str: ""},
+		{spec: "$5", want: Budget{MaxCost: 5}, str: "$5"},
+		{spec: "$2.50, 90m", want: Budget{MaxCost: 2.5, MaxRuntime: 90 * time.Minute}, str: "$2.5 1h30m"},
+		{spec: "2M", want: Budget{MaxTokens: 2_000_000}, str: "2M"},
+		{spec: "2m", want: Budget{MaxRuntime: 2 * time.Minute}, str: "2m"},
+		{spec: "500k tokens 2h", want: Budget{MaxTokens: 500_000, MaxRuntime: 2 * time.Hour}, str: "2h 500k"},
+		{spec: "1.5M", want: Budget{MaxTokens: 1_500_000}, str: "1.5M"},
+		{spec: "$x", wantErr: true},
+		{spec: "$-1", wantErr: true},
+		{spec: "lots", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseBudget(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseBudget(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseBudget(%q) = %+v, want %+v", tt.spec, got, tt.want)
+		}
+		if s := got.String(); s != tt.str {
+			t.Errorf("ParseBudget(%q).String() = %q, want %q", tt.spec, s, tt.str)
+		}
+	}
+}
+
+func TestBudgetLevel(t *testing.T) {
+	b := Budget{MaxTokens: 1000, MaxCost: 10}
+	tests := []struct {
+		usage BudgetUsage
+		want  int
+	}{
+		{BudgetUsage{Tokens: 100, Cost: 1}, budgetOK},
+		{BudgetUsage{Tokens: 100, Cost: 8}, budgetWarn},
+		{BudgetUsage{Tokens: 1000, Cost: 1}, budgetExceeded},
+		// Runtime is unlimited, so it never counts
+		{BudgetUsage{Runtime: 100 * time.Hour}, budgetOK},
+	}
+	for _, tt := range tests {
+		if got := b.level(tt.usage, 80); got != tt.want {
+			t.Errorf("level(%+v) = %d, want %d", tt.usage, got, tt.want)
+		}
+	}
+}
+
+func TestBudgetFileRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	since := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := saveBudget(dir, &Budget{MaxCost: 5, MaxRuntime: 2 * time.Hour, Since: since}); err != nil {
+		t.Fatal(err)
+	}
+	got := loadBudget(dir)
+	if got == nil || got.MaxCost != 5 || got.MaxRuntime != 2*time.Hour || !got.Since.Equal(since) {
+		t.Fatalf("loadBudget = %+v", got)
+	}
+
+	if err := saveBudget(dir, &Budget{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := loadBudget(dir); got != nil {
+		t.Errorf("empty budget should remove the file, got %+v", got)
+	}
+}
+
+func TestUsageSince_CountsOnlyNewMessages(t *testing.T) {
+	since := time.Now()
+	a := &statusTestAdapter{
+		sessions: []adapter.Session{
+			{ID: "resumed", CreatedAt: since.Add(-time.Hour), UpdatedAt: since.Add(time.Minute), TotalTokens: 1_000_000, EstCost: 50},
+			{ID: "old", CreatedAt: since.Add(-time.Hour), UpdatedAt: since.Add(-time.Minute), TotalTokens: 1_000_000, EstCost: 50},
+		},
+		messages: []adapter.Message{
+			{Role: "assistant", Timestamp: since.Add(-time.Hour), TokenUsage: adapter.TokenUsage{InputTokens: 900_000, OutputTokens: 90_000}},
+			{Role: "user", Timestamp: since.Add(time.Second)},
+			{Role: "assistant", Timestamp: since.Add(time.Minute), TokenUsage: adapter.TokenUsage{InputTokens: 300, OutputTokens: 200, CacheRead: 5000}},
+		},
+	}
+	u, err := usageSince(a, "/tmp/wt", since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Tokens != 500 {
+		t.Errorf("Tokens = %d, want 500 from the message after the budget started", u.Tokens)
+	}
+
+	// Without per-message usage, only sessions started under the budget count
+	a.messages = []adapter.Message{{Role: "assistant", Timestamp: since.Add(time.Minute)}}
+	if u, _ := usageSince(a, "/tmp/wt", since); u.Tokens != 0 || u.Cost != 0 {
+		t.Errorf("resumed session without message usage counted: %+v", u)
+	}
+	a.sessions[0].CreatedAt = since.Add(time.Second)
+	if u, _ := usageSince(a, "/tmp/wt", since); u.Tokens != 1_000_000 || u.Cost != 50 {
+		t.Errorf("new session without message usage = %+v, want its totals", u)
+	}
+}
+
+func TestHandleBudgetUsage_WarnsThenInterruptsOnce(t *testing.T) {
+	p := New()
+	cfg := config.Default()
+	cfg.Plugins.Workspace.Budgets.Interrupt = true
+	p.ctx = &plugin.Context{Config: cfg, WorkDir: t.TempDir()}
+	wt := &Worktree{
+		Name:   "feature",
+		Budget: &Budget{MaxCost: 10},
+		Agent:  &Agent{Type: AgentClaude, TmuxSession: "sidecar-ws-feature"},
+	}
+	p.worktrees = []*Worktree{wt}
+
+	if p.handleBudgetUsage(BudgetUsageMsg{Usage: map[string]BudgetUsage{"feature": {Cost: 8.5}}}) != nil {
+		t.Error("warning should not interrupt")
+	}
+	if st := p.budgets["feature"]; st == nil || st.level != budgetWarn || p.toastMessage == "" {
+		t.Fatalf("expected a warning, state %+v toast %q", st, p.toastMessage)
+	}
+	if label, level := p.budgetLabel(wt); label != "budget 85%" || level != budgetWarn {
+		t.Errorf("budgetLabel = %q, %d", label, level)
+	}
+
+	if p.handleBudgetUsage(BudgetUsageMsg{Usage: map[string]BudgetUsage{"feature": {Cost: 10.2}}}) == nil {
+		t.Fatal("expected an interrupt at the limit")
+	}
+	if !p.budgets["feature"].interrupted {
+		t.Error("state should record the interrupt")
+	}
+	if p.handleBudgetUsage(BudgetUsageMsg{Usage: map[string]BudgetUsage{"feature": {Cost: 11}}}) != nil {
+		t.Error("agent should only be interrupted once")
+	}
+}
+
+func TestHandleBudgetUsage_NoInterruptByDefault(t *testing.T) {
+	p := New()
+	p.ctx = &plugin.Context{Config: config.Default(), WorkDir: t.TempDir()}
+	p.worktrees = []*Worktree{{
+		Name:   "feature",
+		Budget: &Budget{MaxTokens: 1000},
+		Agent:  &Agent{Type: AgentCodex, TmuxSession: "sidecar-ws-feature"},
+	}}
+
+	if p.handleBudgetUsage(BudgetUsageMsg{Usage: map[string]BudgetUsage{"feature": {Tokens: 2000}}}) != nil {
+		t.Error("should not interrupt unless budgets.interrupt is set")
+	}
+	if p.budgets["feature"].level != budgetExceeded {
+		t.Errorf("level = %d, want exceeded", p.budgets["feature"].level)
+	}
+}
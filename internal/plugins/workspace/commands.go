@@ -15,6 +15,11 @@ func (p *Plugin) Commands() []plugin.Command {
 			{ID: "paste", Name: "Paste", Description: "Paste clipboard (" + p.getInteractivePasteKey() + ")", Context: "workspace-interactive", Priority: 3},
 		}
 	case ViewModeCreate:
+		if p.createSetup != nil {
+			return []plugin.Command{
+				{ID: "confirm", Name: "Continue", Description: "Continue to the new workspace", Context: "workspace-create", Priority: 1},
+			}
+		}
 		return []plugin.Command{
 			{ID: "cancel", Name: "Cancel", Description: "Cancel workspace creation", Context: "workspace-create", Priority: 1},
 			{ID: "confirm", Name: "Create", Description: "Create the workspace", Context: "workspace-create", Priority: 2},
@@ -26,10 +31,14 @@ func (p *Plugin) Commands() []plugin.Command {
 		}
 	case ViewModeMerge:
 		if p.mergeState != nil && p.mergeState.Step == MergeStepError {
-			return []plugin.Command{
+			cmds := []plugin.Command{
 				{ID: "dismiss-merge-error", Name: "Dismiss", Description: "Dismiss error", Context: "workspace-merge-error", Priority: 1},
 				{ID: "yank-merge-error", Name: "Yank", Description: "Copy error to clipboard", Context: "workspace-merge-error", Priority: 2},
 			}
+			if len(p.mergeState.ConflictedFiles) > 0 {
+				cmds = append(cmds, plugin.Command{ID: "resolve-merge-conflicts", Name: "Resolve", Description: "Resolve conflicts in the git plugin", Context: "workspace-merge-error", Priority: 1})
+			}
+			return cmds
 		}
 		cmds := []plugin.Command{
 			{ID: "cancel", Name: "Cancel", Description: "Cancel merge workflow", Context: "workspace-merge", Priority: 1},
@@ -42,6 +51,9 @@ func (p *Plugin) Commands() []plugin.Command {
 				cmds = append(cmds, plugin.Command{ID: "continue", Name: "Check", Description: "Check merge status", Context: "workspace-merge", Priority: 2})
 			case MergeStepDone:
 				cmds = append(cmds, plugin.Command{ID: "continue", Name: "Done", Description: "Close modal", Context: "workspace-merge", Priority: 2})
+				if len(p.mergeState.ConflictedFiles) > 0 {
+					cmds = append(cmds, plugin.Command{ID: "resolve-merge-conflicts", Name: "Resolve", Description: "Resolve conflicts in the git plugin", Context: "workspace-merge", Priority: 2})
+				}
 			}
 		}
 		return cmds
@@ -75,12 +87,32 @@ func (p *Plugin) Commands() []plugin.Command {
 			{ID: "cancel", Name: "Cancel", Description: "Cancel PR fetch", Context: "workspace-fetch-pr", Priority: 1},
 			{ID: "fetch", Name: "Fetch", Description: "Fetch selected PR", Context: "workspace-fetch-pr", Priority: 2},
 		}
+	case ViewModeMergeQueue:
+		if p.mergeQueue != nil && p.mergeQueue.EditField != mergeQueueEditNone {
+			return []plugin.Command{
+				{ID: "cancel", Name: "Cancel", Description: "Cancel edit", Context: "workspace-merge-queue-input", Priority: 1},
+				{ID: "confirm", Name: "Save", Description: "Save option", Context: "workspace-merge-queue-input", Priority: 2},
+			}
+		}
+		return []plugin.Command{
+			{ID: "cancel", Name: "Close", Description: "Close merge queue", Context: "workspace-merge-queue", Priority: 1},
+			{ID: "start", Name: "Start", Description: "Land selected worktrees", Context: "workspace-merge-queue", Priority: 2},
+			{ID: "toggle", Name: "Select", Description: "Toggle worktree", Context: "workspace-merge-queue", Priority: 3},
+			{ID: "retry", Name: "Retry", Description: "Retry failed worktree", Context: "workspace-merge-queue", Priority: 4},
+		}
 	case ViewModeFilePicker:
 		return []plugin.Command{
 			{ID: "cancel", Name: "Cancel", Description: "Close file picker", Context: "workspace-file-picker", Priority: 1},
 			{ID: "select", Name: "Jump", Description: "Jump to selected file", Context: "workspace-file-picker", Priority: 2},
 		}
 	default:
+		if p.logView.searching {
+			return []plugin.Command{
+				{ID: "cancel", Name: "Cancel", Description: "Clear log search", Context: "workspace-log-search", Priority: 1},
+				{ID: "search", Name: "Done", Description: "Keep search and browse matches", Context: "workspace-log-search", Priority: 2},
+			}
+		}
+
 		// View toggle label changes based on current mode
 		viewToggleName := "Kanban"
 		if p.viewMode == ViewModeKanban {
@@ -117,6 +149,9 @@ func (p *Plugin) Commands() []plugin.Command {
 						)
 					}
 				}
+				if p.previewTab == PreviewTabLog {
+					cmds = append(cmds, plugin.Command{ID: "search-log", Name: "Search", Description: "Search session log", Context: "workspace-preview", Priority: 5})
+				}
 			}
 			// Also show agent commands in preview pane
 			wt := p.selectedWorktree()
@@ -166,10 +201,14 @@ func (p *Plugin) Commands() []plugin.Command {
 		cmds := []plugin.Command{
 			{ID: "new-workspace", Name: "New", Description: "Create new workspace", Context: "workspace-list", Priority: 1},
 			{ID: "fetch-pr", Name: "Fetch", Description: "Fetch remote PR as workspace", Context: "workspace-list", Priority: 2},
+			{ID: "merge-queue", Name: "Queue", Description: "Merge several worktrees in order", Context: "workspace-list", Priority: 4},
 			{ID: "toggle-view", Name: viewToggleName, Description: "Toggle list/kanban view", Context: "workspace-list", Priority: 3},
 			{ID: "toggle-sidebar", Name: "Sidebar", Description: "Toggle sidebar visibility", Context: "workspace-list", Priority: 4},
 			{ID: "refresh", Name: "Refresh", Description: "Refresh workspace list", Context: "workspace-list", Priority: 5},
 		}
+		if p.notifier.Enabled() {
+			cmds = append(cmds, plugin.Command{ID: "toggle-mute", Name: "Mute", Description: "Mute/unmute notifications", Context: "workspace-list", Priority: 17})
+		}
 
 		// Shell-specific commands when shell is selected
 		if p.shellSelected {
@@ -262,9 +301,17 @@ func (p *Plugin) FocusContext() string {
 		return "workspace-type-selector"
 	case ViewModeFetchPR:
 		return "workspace-fetch-pr"
+	case ViewModeMergeQueue:
+		if p.mergeQueue != nil && p.mergeQueue.EditField != mergeQueueEditNone {
+			return "workspace-merge-queue-input"
+		}
+		return "workspace-merge-queue"
 	case ViewModeFilePicker:
 		return "workspace-file-picker"
 	default:
+		if p.logView.searching {
+			return "workspace-log-search"
+		}
 		if p.activePane == PanePreview {
 			return "workspace-preview"
 		}
@@ -285,7 +332,23 @@ func (p *Plugin) ConsumesTextInput() bool {
 		ViewModeTypeSelector,
 		ViewModeFetchPR:
 		return true
+	case ViewModeMergeQueue:
+		return p.mergeQueue != nil && p.mergeQueue.EditField != mergeQueueEditNone
 	default:
-		return false
+		return p.logView.searching
+	}
+}
+
+// CommandVariables exposes the selected worktree's path, branch and linked
+// task to custom commands.
+func (p *Plugin) CommandVariables() map[string]string {
+	vars := make(map[string]string)
+	if wt := p.selectedWorktree(); wt != nil {
+		vars["worktree"] = wt.Path
+		vars["branch"] = wt.Branch
+		if wt.TaskID != "" {
+			vars["task"] = wt.TaskID
+		}
 	}
+	return vars
 }
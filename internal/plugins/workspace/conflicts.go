@@ -1,115 +1,413 @@
 package workspace
 
 import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/plugin"
 )
 
-// Conflict represents a file conflict between worktrees.
+// conflictCheckInterval is how often worktrees are checked for HEAD, index
+// or file changes that need conflicts predicted again.
+const conflictCheckInterval = 10 * time.Second
+
+// snapshotEnv gives snapshot commits a fixed identity and date, so the same
+// tree on the same HEAD always yields the same commit.
+var snapshotEnv = []string{
+	"GIT_AUTHOR_NAME=sidecar", "GIT_AUTHOR_EMAIL=sidecar@localhost", "GIT_AUTHOR_DATE=@0 +0000",
+	"GIT_COMMITTER_NAME=sidecar", "GIT_COMMITTER_EMAIL=sidecar@localhost", "GIT_COMMITTER_DATE=@0 +0000",
+}
+
+// Conflict represents a predicted merge conflict between two worktrees.
 type Conflict struct {
-	Worktrees []string // Names of worktrees with conflicting changes
-	Files     []string // List of conflicting files
+	Worktrees []string       // Names of worktrees with conflicting changes
+	Files     []string       // List of conflicting files
+	Details   []FileConflict // Per-file conflict kind and hunks, in Files order
+}
+
+// FileConflict describes the conflicts a trial merge found in one file.
+type FileConflict struct {
+	Path  string
+	Kind  string         // e.g. "content", "modify/delete", "add/add"
+	Hunks []ConflictHunk // empty when the conflict has no textual hunks
+}
+
+// ConflictHunk is a conflicting region, as 1-based line numbers of the
+// conflict markers in the trial merge result.
+type ConflictHunk struct {
+	Start int
+	End   int
 }
 
 // ConflictsDetectedMsg signals that conflicts have been detected.
 type ConflictsDetectedMsg struct {
+	Epoch     uint64
 	Conflicts []Conflict
 	Err       error
 }
 
-// detectConflicts scans all worktrees for files modified in multiple worktrees.
-// Returns a list of conflicts where each conflict indicates which worktrees
-// are modifying the same files.
-func (p *Plugin) detectConflicts() []Conflict {
-	if len(p.worktrees) < 2 {
-		return nil // Need at least 2 worktrees for conflicts
+// GetEpoch implements plugin.EpochMessage.
+func (m ConflictsDetectedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// conflictCheckMsg triggers a periodic, incremental conflict check.
+type conflictCheckMsg struct {
+	Epoch uint64
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m conflictCheckMsg) GetEpoch() uint64 { return m.Epoch }
+
+// conflictCache remembers worktree snapshots and trial merge results, so a
+// check only re-merges pairs where a worktree changed.
+type conflictCache struct {
+	mu        sync.Mutex
+	snapshots map[string]worktreeSnapshot // by worktree path
+	merges    map[string][]FileConflict   // by "<commit> <commit>"
+}
+
+// worktreeSnapshot is a commit holding a worktree's HEAD plus its staged,
+// unstaged and untracked changes.
+type worktreeSnapshot struct {
+	head     string
+	indexMod time.Time
+	indexLen int64
+	files    string // worktreeFingerprint of the uncommitted files
+	commit   string
+}
+
+func newConflictCache() *conflictCache {
+	return &conflictCache{
+		snapshots: make(map[string]worktreeSnapshot),
+		merges:    make(map[string][]FileConflict),
 	}
+}
 
-	// Build a map of modified files per worktree
-	filesByWorktree := make(map[string][]string)
+// loadConflicts returns a command to predict conflicts across worktrees. With
+// force, every worktree is snapshotted again; otherwise only those whose HEAD,
+// index or uncommitted files changed since the last check.
+func (p *Plugin) loadConflicts(force bool) tea.Cmd {
+	var worktrees []*Worktree
 	for _, wt := range p.worktrees {
-		files, err := getModifiedFiles(wt.Path)
+		if !wt.IsMain && !wt.IsMissing {
+			worktrees = append(worktrees, wt)
+		}
+	}
+	if p.conflictCache == nil {
+		p.conflictCache = newConflictCache()
+	}
+	cache := p.conflictCache
+	epoch := p.ctx.Epoch
+	return func() tea.Msg {
+		conflicts := cache.detect(worktrees, force)
+		return ConflictsDetectedMsg{Epoch: epoch, Conflicts: conflicts}
+	}
+}
+
+// scheduleConflictCheck schedules the next incremental conflict check.
+func (p *Plugin) scheduleConflictCheck() tea.Cmd {
+	epoch := p.ctx.Epoch
+	return tea.Tick(conflictCheckInterval, func(time.Time) tea.Msg {
+		return conflictCheckMsg{Epoch: epoch}
+	})
+}
+
+// handleConflictCheck runs an incremental check and schedules the next one.
+func (p *Plugin) handleConflictCheck(msg conflictCheckMsg) tea.Cmd {
+	if plugin.IsStale(p.ctx, msg) {
+		return nil
+	}
+	return tea.Batch(p.loadConflicts(false), p.scheduleConflictCheck())
+}
+
+// detect trial-merges the snapshot of every pair of worktrees and returns the
+// pairs that would conflict. Worktrees that can't be snapshotted are skipped.
+func (c *conflictCache) detect(worktrees []*Worktree, force bool) []Conflict {
+	if len(worktrees) < 2 {
+		return nil // Need at least 2 worktrees for conflicts
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	type snap struct {
+		name   string
+		dir    string
+		commit string
+	}
+	var snaps []snap
+	seen := make(map[string]bool)
+	for _, wt := range worktrees {
+		s, err := c.snapshot(wt.Path, force)
 		if err != nil {
-			p.ctx.Logger.Debug("failed to get modified files",
-				"worktree", wt.Name, "error", err)
 			continue
 		}
-		if len(files) > 0 {
-			filesByWorktree[wt.Name] = files
+		seen[wt.Path] = true
+		snaps = append(snaps, snap{name: wt.Name, dir: wt.Path, commit: s.commit})
+	}
+	for path := range c.snapshots {
+		if !seen[path] {
+			delete(c.snapshots, path)
 		}
 	}
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].name < snaps[j].name })
 
-	// Find overlaps between worktrees
 	var conflicts []Conflict
-	worktreeNames := make([]string, 0, len(filesByWorktree))
-	for name := range filesByWorktree {
-		worktreeNames = append(worktreeNames, name)
-	}
-
-	for i := 0; i < len(worktreeNames); i++ {
-		for j := i + 1; j < len(worktreeNames); j++ {
-			wt1 := worktreeNames[i]
-			wt2 := worktreeNames[j]
-
-			overlap := intersection(filesByWorktree[wt1], filesByWorktree[wt2])
-			if len(overlap) > 0 {
-				conflicts = append(conflicts, Conflict{
-					Worktrees: []string{wt1, wt2},
-					Files:     overlap,
-				})
+	merges := make(map[string][]FileConflict)
+	for i := 0; i < len(snaps); i++ {
+		for j := i + 1; j < len(snaps); j++ {
+			a, b := snaps[i], snaps[j]
+			key := a.commit + " " + b.commit
+			files, ok := c.merges[key]
+			if !ok {
+				var err error
+				files, err = trialMerge(a.dir, a.commit, b.commit)
+				if err != nil {
+					continue // e.g. unrelated histories; retried next check
+				}
 			}
+			merges[key] = files
+			if len(files) == 0 {
+				continue
+			}
+			conflict := Conflict{Worktrees: []string{a.name, b.name}, Details: files}
+			for _, f := range files {
+				conflict.Files = append(conflict.Files, f.Path)
+			}
+			conflicts = append(conflicts, conflict)
 		}
 	}
-
+	c.merges = merges
 	return conflicts
 }
 
-// loadConflicts returns a command to detect conflicts across worktrees.
-func (p *Plugin) loadConflicts() tea.Cmd {
-	return func() tea.Msg {
-		conflicts := p.detectConflicts()
-		return ConflictsDetectedMsg{Conflicts: conflicts}
+// snapshot returns the snapshot commit of the worktree at dir, reusing the
+// cached one unless forced or HEAD, the index or the uncommitted files
+// changed.
+func (c *conflictCache) snapshot(dir string, force bool) (worktreeSnapshot, error) {
+	out, err := gitOutput(dir, nil, "rev-parse", "HEAD", "--git-path", "index")
+	if err != nil {
+		return worktreeSnapshot{}, err
+	}
+	fields := strings.SplitN(out, "\n", 2)
+	if len(fields) != 2 {
+		return worktreeSnapshot{}, fmt.Errorf("unexpected rev-parse output %q", out)
+	}
+	s := worktreeSnapshot{head: fields[0]}
+	indexPath := fields[1]
+	if !filepath.IsAbs(indexPath) {
+		indexPath = filepath.Join(dir, indexPath)
+	}
+	if info, err := os.Stat(indexPath); err == nil {
+		s.indexMod, s.indexLen = info.ModTime(), info.Size()
+	}
+	if s.files, err = worktreeFingerprint(dir); err != nil {
+		return worktreeSnapshot{}, err
 	}
+
+	if prev, ok := c.snapshots[dir]; ok && !force && prev.head == s.head &&
+		prev.indexMod.Equal(s.indexMod) && prev.indexLen == s.indexLen && prev.files == s.files {
+		return prev, nil
+	}
+	s.commit, err = snapshotCommit(dir, s.head, indexPath)
+	if err != nil {
+		return worktreeSnapshot{}, err
+	}
+	c.snapshots[dir] = s
+	return s, nil
 }
 
-// getModifiedFiles returns a list of files modified in the worktree.
-// This includes both staged and unstaged changes.
-func getModifiedFiles(workdir string) ([]string, error) {
-	// Get list of modified files (staged + unstaged) relative to HEAD
-	cmd := exec.Command("git", "diff", "--name-only", "HEAD")
-	cmd.Dir = workdir
-	output, err := cmd.Output()
+// worktreeFingerprint summarizes a worktree's uncommitted files: git status,
+// plus the size and mtime of every changed or untracked path, since status
+// stays the same when an already modified file is edited again. Agents
+// mostly edit without staging, so HEAD and the index alone miss their work.
+func worktreeFingerprint(dir string) (string, error) {
+	// Optional locks off: status must not refresh the index, whose mtime
+	// is part of the snapshot key
+	out, err := gitOutput(dir, []string{"GIT_OPTIONAL_LOCKS=0"}, "status", "--porcelain=v2", "-z", "--untracked-files=all")
 	if err != nil {
-		// No HEAD yet or other error, try just diff
-		cmd = exec.Command("git", "diff", "--name-only")
-		cmd.Dir = workdir
-		output, _ = cmd.Output()
+		return "", err
+	}
+	h := sha256.New()
+	h.Write([]byte(out))
+	for _, path := range statusPaths(out) {
+		if info, err := os.Lstat(filepath.Join(dir, path)); err == nil {
+			fmt.Fprintf(h, "\x00%s %d %d", path, info.Size(), info.ModTime().UnixNano())
+		}
 	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
 
-	var files []string
-	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
-		line = strings.TrimSpace(line)
-		if line != "" {
-			files = append(files, line)
+// statusPaths returns the paths in `git status --porcelain=v2 -z` output.
+func statusPaths(out string) []string {
+	var paths []string
+	records := strings.Split(out, "\x00")
+	for i := 0; i < len(records); i++ {
+		rec := records[i]
+		if rec == "" {
+			continue
 		}
+		var fields []string
+		switch rec[0] {
+		case '1':
+			fields = strings.SplitN(rec, " ", 9)
+		case '2':
+			fields = strings.SplitN(rec, " ", 10)
+			i++ // the rename's original path follows
+		case 'u':
+			fields = strings.SplitN(rec, " ", 11)
+		case '?':
+			fields = strings.SplitN(rec, " ", 2)
+		default:
+			continue
+		}
+		paths = append(paths, fields[len(fields)-1])
 	}
+	return paths
+}
 
-	// Also get untracked files that are new
-	cmd = exec.Command("git", "ls-files", "--others", "--exclude-standard")
-	cmd.Dir = workdir
-	untrackedOutput, err := cmd.Output()
+// snapshotCommit records a worktree's current files, including uncommitted
+// and untracked ones, as a commit on top of head. It stages into a copy of
+// the index, so the worktree's real index is left alone. Returns head itself
+// when there are no changes.
+func snapshotCommit(dir, head, indexPath string) (string, error) {
+	tmpDir, err := os.MkdirTemp("", "sidecar-snapshot-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmpDir)
+	tmpPath := filepath.Join(tmpDir, "index")
+	if data, err := os.ReadFile(indexPath); err == nil {
+		if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+			return "", err
+		}
+	}
+
+	env := []string{"GIT_INDEX_FILE=" + tmpPath}
+	if _, err := gitOutput(dir, env, "add", "-A"); err != nil {
+		return "", err
+	}
+	tree, err := gitOutput(dir, env, "write-tree")
+	if err != nil {
+		return "", err
+	}
+	headTree, err := gitOutput(dir, nil, "rev-parse", head+"^{tree}")
+	if err != nil {
+		return "", err
+	}
+	if tree == headTree {
+		return head, nil
+	}
+	return gitOutput(dir, snapshotEnv, "commit-tree", tree, "-p", head, "-m", "sidecar conflict snapshot")
+}
+
+// trialMerge merges two commits with git merge-tree, without touching any
+// worktree, and returns the conflicting files with their hunks.
+func trialMerge(dir, a, b string) ([]FileConflict, error) {
+	cmd := exec.Command("git", "merge-tree", "--write-tree", "--name-only", a, b)
+	cmd.Dir = dir
+	output, err := cmd.Output()
 	if err == nil {
-		for _, line := range strings.Split(strings.TrimSpace(string(untrackedOutput)), "\n") {
-			line = strings.TrimSpace(line)
-			if line != "" {
-				files = append(files, line)
+		return nil, nil // Merges cleanly
+	}
+	// Exit status 1 means the merge has conflicts; anything else failed
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		return nil, fmt.Errorf("merge-tree: %w", err)
+	}
+
+	tree, paths, messages := parseMergeTree(string(output))
+	files := make([]FileConflict, 0, len(paths))
+	for _, path := range paths {
+		f := FileConflict{Path: path, Kind: conflictKind(messages, path)}
+		if blob, err := gitOutput(dir, nil, "cat-file", "-p", tree+":"+path); err == nil {
+			f.Hunks = parseConflictHunks(blob)
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+// parseMergeTree splits `git merge-tree --write-tree --name-only` output into
+// the result tree, the conflicted paths and the informational messages.
+func parseMergeTree(output string) (string, []string, []string) {
+	sections := strings.SplitN(output, "\n\n", 2)
+	lines := strings.Split(strings.TrimSpace(sections[0]), "\n")
+	tree := lines[0]
+	var paths []string
+	seen := make(map[string]bool)
+	for _, path := range lines[1:] {
+		if path != "" && !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	var messages []string
+	if len(sections) == 2 {
+		for _, line := range strings.Split(sections[1], "\n") {
+			if strings.HasPrefix(line, "CONFLICT") {
+				messages = append(messages, line)
 			}
 		}
 	}
+	return tree, paths, messages
+}
 
-	return files, nil
+// conflictKind extracts the kind from a "CONFLICT (kind): ..." message that
+// mentions path.
+func conflictKind(messages []string, path string) string {
+	for _, m := range messages {
+		if !strings.Contains(m, path) {
+			continue
+		}
+		if start, end := strings.Index(m, "("), strings.Index(m, ")"); start >= 0 && end > start {
+			return m[start+1 : end]
+		}
+	}
+	return "content"
+}
+
+// parseConflictHunks returns the ranges between conflict markers in a file.
+func parseConflictHunks(content string) []ConflictHunk {
+	var hunks []ConflictHunk
+	start := 0
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		switch {
+		case start == 0 && strings.HasPrefix(line, "<<<<<<<"):
+			start = n
+		case start > 0 && strings.HasPrefix(line, ">>>>>>>"):
+			hunks = append(hunks, ConflictHunk{Start: start, End: n})
+			start = 0
+		}
+	}
+	return hunks
+}
+
+// gitOutput runs git in dir with extra environment and returns its trimmed
+// stdout.
+func gitOutput(dir string, env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
 }
 
 // intersection returns the common elements between two slices.
@@ -185,3 +483,12 @@ func (p *Plugin) getConflictingWorktrees(worktreeName string, conflicts []Confli
 	}
 	return others
 }
+
+// formatHunkRanges renders hunks as "L12-30, L88-97".
+func formatHunkRanges(hunks []ConflictHunk) string {
+	parts := make([]string, 0, len(hunks))
+	for _, h := range hunks {
+		parts = append(parts, fmt.Sprintf("L%d-%d", h.Start, h.End))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -1,8 +1,12 @@
 package workspace
 
 import (
+	"os"
+	"os/exec"
+	"path/filepath"
 	"reflect"
 	"sort"
+	"strings"
 	"testing"
 )
 
@@ -209,3 +213,117 @@ func TestGetConflictingWorktrees(t *testing.T) {
 		})
 	}
 }
+
+func TestParseConflictHunks(t *testing.T) {
+	content := "a\n<<<<<<< ours\nb\n=======\nc\n>>>>>>> theirs\nd\n<<<<<<< ours\ne\n=======\n>>>>>>> theirs\n"
+	want := []ConflictHunk{{Start: 2, End: 6}, {Start: 8, End: 11}}
+	if got := parseConflictHunks(content); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseConflictHunks = %v, want %v", got, want)
+	}
+	if got := formatHunkRanges(want); got != "L2-6, L8-11" {
+		t.Errorf("formatHunkRanges = %q", got)
+	}
+}
+
+// conflictTestRepo creates a repo with a file of two functions and a
+// worktree per name, each on its own branch.
+func conflictTestRepo(t *testing.T, names ...string) (string, []*Worktree) {
+	t.Helper()
+	root := t.TempDir()
+	repo := filepath.Join(root, "repo")
+	run := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	if err := os.MkdirAll(repo, 0755); err != nil {
+		t.Fatal(err)
+	}
+	run(repo, "init", "-b", "main")
+	run(repo, "config", "user.email", "test@test.com")
+	run(repo, "config", "user.name", "Test")
+	var lines []string
+	for i := 1; i <= 30; i++ {
+		lines = append(lines, "line")
+	}
+	if err := os.WriteFile(filepath.Join(repo, "app.go"), []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run(repo, "add", ".")
+	run(repo, "commit", "-m", "initial")
+
+	var worktrees []*Worktree
+	for _, name := range names {
+		path := filepath.Join(root, name)
+		run(repo, "worktree", "add", "-b", name, path)
+		worktrees = append(worktrees, &Worktree{Name: name, Path: path, Branch: name})
+	}
+	return repo, worktrees
+}
+
+// editLine replaces a 1-based line of app.go in a worktree.
+func editLine(t *testing.T, wt *Worktree, n int, text string) {
+	t.Helper()
+	path := filepath.Join(wt.Path, "app.go")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(string(data), "\n")
+	lines[n-1] = text
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConflictCache_DetectsHunkConflicts(t *testing.T) {
+	_, wts := conflictTestRepo(t, "api", "docs", "ui")
+	api, docs, ui := wts[0], wts[1], wts[2]
+
+	// Same file, different regions: api and docs merge cleanly
+	editLine(t, api, 3, "api change")
+	editLine(t, docs, 25, "docs change")
+	// ui edits the same line as api
+	editLine(t, ui, 3, "ui change")
+
+	cache := newConflictCache()
+	conflicts := cache.detect(wts, true)
+	if len(conflicts) != 1 {
+		t.Fatalf("conflicts = %+v, want only api/ui", conflicts)
+	}
+	c := conflicts[0]
+	if !reflect.DeepEqual(c.Worktrees, []string{"api", "ui"}) || !reflect.DeepEqual(c.Files, []string{"app.go"}) {
+		t.Errorf("conflict = %+v", c)
+	}
+	if len(c.Details) != 1 || c.Details[0].Kind != "content" || len(c.Details[0].Hunks) != 1 || c.Details[0].Hunks[0].Start != 3 {
+		t.Errorf("details = %+v", c.Details)
+	}
+
+	// The worktree's own index is untouched by the snapshot
+	out, _ := exec.Command("git", "-C", ui.Path, "diff", "--cached", "--name-only").Output()
+	if len(strings.TrimSpace(string(out))) != 0 {
+		t.Errorf("snapshot staged files in the worktree index: %q", out)
+	}
+
+	// Unforced checks reuse snapshots of unchanged worktrees
+	snap := cache.snapshots[ui.Path]
+	snap.commit = "reused"
+	cache.snapshots[ui.Path] = snap
+	cache.detect(wts, false)
+	if got := cache.snapshots[ui.Path].commit; got != "reused" {
+		t.Errorf("unchanged worktree was snapshotted again: %s", got)
+	}
+
+	// An unstaged edit is picked up without forcing
+	editLine(t, ui, 3, "line")
+	if got := cache.detect(wts, false); len(got) != 0 {
+		t.Errorf("unstaged fix should clear the conflict, got %+v", got)
+	}
+	editLine(t, ui, 3, "ui change again")
+	if got := cache.detect(wts, false); len(got) != 1 {
+		t.Errorf("editing a modified file again should be noticed, got %+v", got)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/ansi"
 	"github.com/marcus/sidecar/internal/modal"
@@ -12,19 +14,24 @@ import (
 )
 
 const (
-	createNameFieldID           = "create-name"
-	createBaseFieldID           = "create-base"
-	createPromptFieldID         = "create-prompt"
-	createTaskFieldID           = "create-task"
-	createAgentListID           = "create-agent-list"
-	createSkipPermissionsID     = "create-skip-permissions"
-	createSubmitID              = "create-submit"
-	createCancelID              = "create-cancel"
-	createBranchItemPrefix      = "create-branch-"
-	createTaskItemPrefix        = "create-task-item-"
-	createAgentItemPrefix       = "create-agent-"
+	createNameFieldID       = "create-name"
+	createBaseFieldID       = "create-base"
+	createPromptFieldID     = "create-prompt"
+	createTaskFieldID       = "create-task"
+	createAgentListID       = "create-agent-list"
+	createSkipPermissionsID = "create-skip-permissions"
+	createBudgetFieldID     = "create-budget"
+	createSubmitID          = "create-submit"
+	createCancelID          = "create-cancel"
+	createBranchItemPrefix  = "create-branch-"
+	createTaskItemPrefix    = "create-task-item-"
+	createAgentItemPrefix   = "create-agent-"
+	createSetupContinueID   = "create-setup-continue"
 )
 
+// setupOutputLines is the number of output lines shown per setup step.
+const setupOutputLines = 6
+
 func createIndexedID(prefix string, idx int) string {
 	return fmt.Sprintf("%s%d", prefix, idx)
 }
@@ -55,6 +62,11 @@ func (p *Plugin) ensureCreateModal() {
 	}
 	p.createModalWidth = modalW
 
+	if p.createSetup != nil {
+		p.ensureCreateSetupModal(modalW)
+		return
+	}
+
 	items := make([]modal.ListItem, len(AgentTypeOrder))
 	for i, at := range AgentTypeOrder {
 		items[i] = modal.ListItem{
@@ -86,6 +98,9 @@ func (p *Plugin) ensureCreateModal() {
 		AddSection(modal.When(p.shouldShowSkipPermissions, modal.Checkbox(createSkipPermissionsID, "Auto-approve all actions", &p.createSkipPermissions))).
 		AddSection(p.createSkipPermissionsHintSection()).
 		AddSection(modal.Spacer()).
+		AddSection(p.createBudgetLabelSection()).
+		AddSection(modal.Input(createBudgetFieldID, &p.createBudgetInput, modal.WithSubmitOnEnter(false))).
+		AddSection(modal.Spacer()).
 		AddSection(p.createErrorSection()).
 		AddSection(modal.When(func() bool { return p.createError != "" }, modal.Spacer())).
 		AddSection(modal.Buttons(
@@ -95,7 +110,7 @@ func (p *Plugin) ensureCreateModal() {
 }
 
 func (p *Plugin) syncCreateModalFocus() {
-	if p.createModal == nil {
+	if p.createModal == nil || p.createSetup != nil {
 		return
 	}
 	p.normalizeCreateFocus()
@@ -143,8 +158,10 @@ func (p *Plugin) createFocusID() string {
 	case 5:
 		return createSkipPermissionsID
 	case 6:
-		return createSubmitID
+		return createBudgetFieldID
 	case 7:
+		return createSubmitID
+	case 8:
 		return createCancelID
 	default:
 		return ""
@@ -192,6 +209,12 @@ func (p *Plugin) createBaseLabelSection() modal.Section {
 	}, nil)
 }
 
+func (p *Plugin) createBudgetLabelSection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		return modal.RenderedSection{Content: "Budget (optional): " + dimText("$5 2h 2M tokens")}
+	}, nil)
+}
+
 func (p *Plugin) createBranchDropdownSection() modal.Section {
 	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
 		if !p.branchDropdownVisible(focusID) {
@@ -493,3 +516,98 @@ func (p *Plugin) createErrorSection() modal.Section {
 		return modal.RenderedSection{Content: errStyle.Render("Error: " + p.createError)}
 	}, nil)
 }
+
+// finishCreate selects a newly created worktree and starts its agent.
+func (p *Plugin) finishCreate(msg CreateDoneMsg) tea.Cmd {
+	p.viewMode = ViewModeList
+	p.worktrees = append(p.worktrees, msg.Worktree)
+
+	// Auto-focus newly created worktree (same pattern as click selection)
+	p.shellSelected = false
+	p.selectedIdx = len(p.worktrees) - 1
+	p.previewOffset = 0
+	p.autoScrollOutput = true
+	p.resetScrollBaseLineCount() // td-f7c8be: clear snapshot for new selection
+	p.saveSelectionState()
+	p.ensureVisible()
+
+	p.clearCreateModal()
+
+	// Load content for preview pane
+	cmds := []tea.Cmd{p.loadSelectedContent()}
+
+	// Start agent or attach based on selection
+	if msg.AgentType != AgentNone && msg.AgentType != "" {
+		cmds = append(cmds, p.StartAgentWithOptions(msg.Worktree, msg.AgentType, msg.SkipPerms, msg.Prompt))
+	} else {
+		// "None" selected - attach to worktree directory
+		cmds = append(cmds, p.AttachToWorktreeDir(msg.Worktree))
+	}
+	return tea.Batch(cmds...)
+}
+
+// continueCreateSetup leaves the setup results and finishes creation.
+func (p *Plugin) continueCreateSetup() tea.Cmd {
+	pending := p.createSetup
+	if pending == nil {
+		return nil
+	}
+	p.createSetup = nil
+	return p.finishCreate(*pending)
+}
+
+// ensureCreateSetupModal builds the create modal's setup results view.
+func (p *Plugin) ensureCreateSetupModal(modalW int) {
+	title := "Worktree Created"
+	if p.createSetup.Setup.Failed() {
+		title = "Worktree Created with Setup Errors"
+	}
+	p.createModal = modal.New(title,
+		modal.WithWidth(modalW),
+		modal.WithPrimaryAction(createSetupContinueID),
+		modal.WithHints(false),
+	).
+		AddSection(p.createSetupResultsSection()).
+		AddSection(modal.Spacer()).
+		AddSection(modal.Buttons(
+			modal.Btn(" Continue ", createSetupContinueID),
+		))
+}
+
+// createSetupResultsSection lists each setup step with its status and the
+// tail of its output.
+func (p *Plugin) createSetupResultsSection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		if p.createSetup == nil || p.createSetup.Setup == nil {
+			return modal.RenderedSection{}
+		}
+		okStyle := lipgloss.NewStyle().Foreground(styles.Success)
+		errStyle := lipgloss.NewStyle().Foreground(styles.Error)
+
+		var lines []string
+		for _, step := range p.createSetup.Setup.Steps {
+			status := okStyle.Render("✓")
+			if step.Err != nil {
+				status = errStyle.Render("✗")
+			}
+			header := fmt.Sprintf("%s %s", status, step.Step)
+			if step.Duration >= time.Second {
+				header += dimText(fmt.Sprintf(" (%s)", step.Duration.Round(100*time.Millisecond)))
+			}
+			lines = append(lines, ansi.Truncate(header, contentWidth, "…"))
+			if step.Err != nil {
+				lines = append(lines, errStyle.Render(ansi.Truncate("  "+step.Err.Error(), contentWidth, "…")))
+			}
+			output := strings.Split(strings.TrimSpace(step.Output), "\n")
+			if len(output) > setupOutputLines {
+				output = output[len(output)-setupOutputLines:]
+			}
+			for _, line := range output {
+				if line != "" {
+					lines = append(lines, dimText(ansi.Truncate("  "+line, contentWidth, "…")))
+				}
+			}
+		}
+		return modal.RenderedSection{Content: strings.Join(lines, "\n")}
+	}, nil)
+}
@@ -94,6 +94,31 @@ func getDiffStatFromBase(workdir, baseBranch string) (string, error) {
 }
 
 
+// getChangesFromBase returns the files changed since the merge-base with the
+// base branch and the diff, both including uncommitted changes.
+func getChangesFromBase(workdir, baseBranch string) (files []string, diff string) {
+	if baseBranch == "" {
+		baseBranch = detectDefaultBranch(workdir)
+	}
+
+	cmd := exec.Command("git", "diff", "--merge-base", baseBranch, "--name-only")
+	cmd.Dir = workdir
+	if output, err := cmd.Output(); err == nil {
+		for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+			if line != "" {
+				files = append(files, line)
+			}
+		}
+	}
+
+	cmd = exec.Command("git", "diff", "--merge-base", baseBranch)
+	cmd.Dir = workdir
+	if output, err := cmd.Output(); err == nil {
+		diff = string(output)
+	}
+	return files, diff
+}
+
 // splitLines splits a string into lines, handling various line endings.
 func splitLines(s string) []string {
 	var lines []string
@@ -175,42 +175,29 @@ func sendLiteralToTmux(sessionName, text string) error {
 	return cmd.Run()
 }
 
-// keySpec describes a key to send to tmux with ordering preserved.
+// keySpec describes a key to send to a session with ordering preserved.
 type keySpec struct {
 	value   string
 	literal bool
 }
 
-// sendInteractiveKeysCmd sends keys to tmux asynchronously (td-c2961e).
+// sendInteractiveKeysCmd sends keys to the session asynchronously (td-c2961e).
 // Keys are sent in order within a single goroutine to prevent reordering.
 // Returns InteractiveSessionDeadMsg if the session has ended.
 func sendInteractiveKeysCmd(sessionName string, keys ...keySpec) tea.Cmd {
 	return func() tea.Msg {
-		for _, k := range keys {
-			var err error
-			if k.literal {
-				err = sendLiteralToTmux(sessionName, k.value)
-			} else {
-				err = sendKeyToTmux(sessionName, k.value)
-			}
-			if err != nil && isSessionDeadError(err) {
-				return InteractiveSessionDeadMsg{}
-			}
+		if err := backendFor(sessionName).SendKeys(sessionName, keys...); err != nil && isSessionDeadError(err) {
+			return InteractiveSessionDeadMsg{}
 		}
 		return nil
 	}
 }
 
-// sendInteractivePasteInputCmd sends paste text to tmux asynchronously (td-c2961e).
+// sendInteractivePasteInputCmd sends paste text to the session asynchronously (td-c2961e).
 // Used for multi-character terminal input (not clipboard paste which is already async).
 func sendInteractivePasteInputCmd(sessionName, text string, bracketed bool) tea.Cmd {
 	return func() tea.Msg {
-		var err error
-		if bracketed {
-			err = sendBracketedPasteToTmux(sessionName, text)
-		} else {
-			err = sendPasteToTmux(sessionName, text)
-		}
+		err := backendFor(sessionName).Paste(sessionName, text, bracketed)
 		if err != nil && isSessionDeadError(err) {
 			return InteractiveSessionDeadMsg{}
 		}
@@ -298,11 +285,7 @@ func (p *Plugin) pasteClipboardToTmuxCmd() tea.Cmd {
 			return InteractivePasteResultMsg{Empty: true}
 		}
 
-		if bracketed {
-			err = sendBracketedPasteToTmux(sessionName, text)
-		} else {
-			err = sendPasteToTmux(sessionName, text)
-		}
+		err = backendFor(sessionName).Paste(sessionName, text, bracketed)
 		if err != nil {
 			return InteractivePasteResultMsg{Err: err, SessionDead: isSessionDeadError(err)}
 		}
@@ -348,6 +331,11 @@ func (p *Plugin) updateMouseReportingMode(output string) {
 	if p.interactiveState == nil || !p.interactiveState.Active {
 		return
 	}
+	// PTY sessions track modes in their emulator; captures don't include them.
+	if _, mouse, ok := globalPTYBackend.modes(p.interactiveState.TargetSession); ok {
+		p.interactiveState.MouseReportingEnabled = mouse
+		return
+	}
 	p.interactiveState.MouseReportingEnabled = detectMouseReportingMode(output)
 }
 
@@ -357,6 +345,11 @@ func (p *Plugin) updateBracketedPasteMode(output string) {
 	if p.interactiveState == nil || !p.interactiveState.Active {
 		return
 	}
+	// PTY sessions track modes in their emulator; captures don't include them.
+	if bracketed, _, ok := globalPTYBackend.modes(p.interactiveState.TargetSession); ok {
+		p.interactiveState.BracketedPasteEnabled = bracketed
+		return
+	}
 	p.interactiveState.BracketedPasteEnabled = detectBracketedPasteMode(output)
 }
 
@@ -400,12 +393,10 @@ func isNormalTyping(s string) bool {
 
 // enterInteractiveMode enters interactive mode for the current selection.
 // Returns a tea.Cmd if mode entry succeeded, nil otherwise.
-// Requires tmux_interactive_input feature flag to be enabled.
+// Requires tmux_interactive_input feature flag to be enabled for tmux
+// sessions; PTY sessions have no other way to take input.
 func (p *Plugin) enterInteractiveMode() tea.Cmd {
-	// Check feature flag
-	if !features.IsEnabled(features.TmuxInteractiveInput.Name) {
-		return nil
-	}
+	interactiveInput := features.IsEnabled(features.TmuxInteractiveInput.Name)
 
 	// Determine target based on current selection
 	var sessionName, paneID string
@@ -416,6 +407,9 @@ func (p *Plugin) enterInteractiveMode() tea.Cmd {
 			return nil
 		}
 		shell := p.shells[p.selectedShellIdx]
+		if !interactiveInput && (shell.IsOrphaned || !globalPTYBackend.owns(shell.TmuxName)) {
+			return nil
+		}
 
 		// td-f88fdd: Handle orphaned shells - recreate before entering interactive mode
 		if shell.IsOrphaned {
@@ -433,6 +427,9 @@ func (p *Plugin) enterInteractiveMode() tea.Cmd {
 		if wt == nil || wt.Agent == nil {
 			return nil
 		}
+		if !interactiveInput && !globalPTYBackend.owns(wt.Agent.TmuxSession) {
+			return nil
+		}
 		sessionName = wt.Agent.TmuxSession
 		paneID = wt.Agent.TmuxPane
 	}
@@ -445,11 +442,14 @@ func (p *Plugin) enterInteractiveMode() tea.Cmd {
 	}
 	if target != "" {
 		previewWidth, previewHeight := p.calculatePreviewDimensions()
-		tty.SetWindowSizeManual(sessionName)
-		p.resizeTmuxPane(target, previewWidth, previewHeight)
+		backend := backendFor(target)
+		if _, ok := backend.(tmuxBackend); ok {
+			tty.SetWindowSizeManual(sessionName)
+		}
+		backend.Resize(target, previewWidth, previewHeight)
 		// Verify and retry once if resize didn't take effect
-		if w, h, ok := queryPaneSize(target); ok && (w != previewWidth || h != previewHeight) {
-			p.resizeTmuxPane(target, previewWidth, previewHeight)
+		if w, h, ok := backend.PaneSize(target); ok && (w != previewWidth || h != previewHeight) {
+			backend.Resize(target, previewWidth, previewHeight)
 		}
 	}
 	// Initialize interactive state
@@ -571,16 +571,17 @@ func (p *Plugin) resizeTmuxTargetCmd(target string) tea.Cmd {
 	}
 
 	previewWidth, previewHeight := p.calculatePreviewDimensions()
+	backend := backendFor(target)
 	return func() tea.Msg {
-		if actualWidth, actualHeight, ok := queryPaneSize(target); ok {
+		if actualWidth, actualHeight, ok := backend.PaneSize(target); ok {
 			if actualWidth == previewWidth && actualHeight == previewHeight {
 				return nil
 			}
 		}
-		p.resizeTmuxPane(target, previewWidth, previewHeight)
-		if actualWidth, actualHeight, ok := queryPaneSize(target); ok {
+		backend.Resize(target, previewWidth, previewHeight)
+		if actualWidth, actualHeight, ok := backend.PaneSize(target); ok {
 			if actualWidth != previewWidth || actualHeight != previewHeight {
-				p.resizeTmuxPane(target, previewWidth, previewHeight)
+				backend.Resize(target, previewWidth, previewHeight)
 			}
 		}
 		return paneResizedMsg{}
@@ -609,7 +610,7 @@ func (p *Plugin) maybeResizeInteractivePane(paneWidth, paneHeight int) tea.Cmd {
 
 // resizeTmuxPane resizes a tmux window/pane to the specified dimensions.
 // resize-window works for detached sessions; resize-pane is a fallback.
-func (p *Plugin) resizeTmuxPane(paneID string, width, height int) {
+func resizeTmuxPane(paneID string, width, height int) {
 	if width <= 0 && height <= 0 {
 		return
 	}
@@ -683,7 +684,7 @@ func (p *Plugin) resizeForAttachCmd(target string) tea.Cmd {
 		if w <= 0 || h <= 0 {
 			return nil
 		}
-		p.resizeTmuxPane(target, w, h)
+		resizeTmuxPane(target, w, h)
 		return nil
 	}
 }
@@ -921,16 +922,11 @@ func (p *Plugin) handleInteractiveKeys(msg tea.KeyMsg) tea.Cmd {
 		// Send paste async (td-c2961e): escape + paste in order if pending
 		if pendingEscape {
 			cmds = append(cmds, func() tea.Msg {
-				if err := sendKeyToTmux(sessionName, "Escape"); err != nil && isSessionDeadError(err) {
+				backend := backendFor(sessionName)
+				if err := backend.SendKeys(sessionName, keySpec{value: "Escape"}); err != nil && isSessionDeadError(err) {
 					return InteractiveSessionDeadMsg{}
 				}
-				var err error
-				if bracketed {
-					err = sendBracketedPasteToTmux(sessionName, text)
-				} else {
-					err = sendPasteToTmux(sessionName, text)
-				}
-				if err != nil && isSessionDeadError(err) {
+				if err := backend.Paste(sessionName, text, bracketed); err != nil && isSessionDeadError(err) {
 					return InteractiveSessionDeadMsg{}
 				}
 				return nil
@@ -1084,7 +1080,7 @@ func sendSGRMouse(sessionName string, button, col, row int, release bool) error
 		suffix = "m"
 	}
 	seq := fmt.Sprintf("\x1b[<%d;%d;%d%s", button, col, row, suffix)
-	return sendLiteralToTmux(sessionName, seq)
+	return backendFor(sessionName).SendKeys(sessionName, keySpec{value: seq, literal: true})
 }
 
 func (p *Plugin) interactiveMouseCoords(x, y int) (col, row int, ok bool) {
@@ -3,7 +3,6 @@ package workspace
 import (
 	"fmt"
 	"math"
-	"os/exec"
 	"strings"
 	"time"
 
@@ -40,6 +39,8 @@ func (p *Plugin) handleKeyPress(msg tea.KeyMsg) tea.Cmd {
 		return p.handleRenameShellKeys(msg)
 	case ViewModeFetchPR:
 		return p.handleFetchPRKeys(msg)
+	case ViewModeMergeQueue:
+		return p.handleMergeQueueKeys(msg)
 	case ViewModeFilePicker:
 		return p.handleFilePickerKeys(msg)
 	case ViewModeInteractive:
@@ -351,10 +352,10 @@ func (p *Plugin) executeDelete() tea.Cmd {
 	deleteRemote := p.deleteRemoteBranchOpt && p.deleteHasRemote
 	workDir := p.ctx.WorkDir
 
-	// Kill tmux session if it exists (before deleting worktree)
+	// Kill agent session if it exists (before deleting worktree)
 	sessionName := tmuxSessionPrefix + sanitizeName(name)
 	if sessionExists(sessionName) {
-		_ = exec.Command("tmux", "kill-session", "-t", sessionName).Run()
+		_ = backendFor(sessionName).KillSession(sessionName)
 	}
 	delete(p.managedSessions, sessionName)
 	globalPaneCache.remove(sessionName)
@@ -372,7 +373,16 @@ func (p *Plugin) executeDelete() tea.Cmd {
 	return func() tea.Msg {
 		var warnings []string
 
-		// Delete the worktree first
+		// Run teardown steps while the worktree still exists
+		if !isMissing {
+			for _, r := range p.teardownWorktree(wt) {
+				if r.Err != nil {
+					warnings = append(warnings, fmt.Sprintf("Teardown %s: %v", r.Step, r.Err))
+				}
+			}
+		}
+
+		// Delete the worktree
 		err := doDeleteWorktree(workDir, path, isMissing)
 		if err != nil {
 			return DeleteDoneMsg{Name: name, Err: err}
@@ -478,6 +488,16 @@ func (p *Plugin) handleListKeys(msg tea.KeyMsg) tea.Cmd {
 	// Clear any deletion warnings on key interaction
 	p.deleteWarnings = nil
 
+	// Log tab search
+	if p.logView.searching {
+		return p.handleLogSearchKeys(msg)
+	}
+	if p.viewMode == ViewModeList && p.activePane == PanePreview && p.previewTab == PreviewTabLog && !p.shellSelected {
+		if cmd, ok := p.handleLogKeys(msg); ok {
+			return cmd
+		}
+	}
+
 	switch msg.String() {
 	case "j", "down":
 		if p.viewMode == ViewModeKanban {
@@ -742,8 +762,8 @@ func (p *Plugin) handleListKeys(msg tea.KeyMsg) tea.Cmd {
 			if pageSize < 5 {
 				pageSize = 5
 			}
-			if p.previewTab == PreviewTabOutput {
-				// For output, offset is from bottom
+			if p.previewTab == PreviewTabOutput || p.previewTab == PreviewTabTranscript || p.previewTab == PreviewTabLog {
+				// For output, transcript and log, offset is from bottom
 				if p.previewOffset > pageSize {
 					p.previewOffset -= pageSize
 				} else {
@@ -762,8 +782,8 @@ func (p *Plugin) handleListKeys(msg tea.KeyMsg) tea.Cmd {
 			if pageSize < 5 {
 				pageSize = 5
 			}
-			if p.previewTab == PreviewTabOutput {
-				// For output, offset is from bottom
+			if p.previewTab == PreviewTabOutput || p.previewTab == PreviewTabTranscript || p.previewTab == PreviewTabLog {
+				// For output, transcript and log, offset is from bottom
 				p.autoScrollOutput = false
 				p.captureScrollBaseLineCount() // td-f7c8be: prevent bounce on poll
 				p.previewOffset += pageSize
@@ -797,6 +817,9 @@ func (p *Plugin) handleListKeys(msg tea.KeyMsg) tea.Cmd {
 		if wt != nil && wt.Agent != nil {
 			return p.StopAgent(wt)
 		}
+	case "M":
+		// Mute/unmute notifications for selected worktree or shell
+		p.toggleNotifyMute()
 	case "K":
 		// Kill selected shell session
 		if p.shellSelected && p.selectedShellIdx >= 0 && p.selectedShellIdx < len(p.shells) {
@@ -860,6 +883,10 @@ func (p *Plugin) handleListKeys(msg tea.KeyMsg) tea.Cmd {
 		p.fetchPRCursor = 0
 		p.fetchPRError = ""
 		return p.fetchPRList()
+	case "Q":
+		// Land several finished worktrees in order
+		p.openMergeQueue()
+		return nil
 	case "m":
 		// In preview pane on task tab: toggle markdown render mode
 		// Otherwise: start merge workflow
@@ -899,13 +926,25 @@ func (p *Plugin) handleListKeys(msg tea.KeyMsg) tea.Cmd {
 }
 
 // handleCreateKeys handles keys in create modal.
-// createFocus: 0=name, 1=base, 2=prompt, 3=task, 4=agent, 5=skipPerms, 6=create button, 7=cancel button
+// createFocus: 0=name, 1=base, 2=prompt, 3=task, 4=agent, 5=skipPerms, 6=budget, 7=create button, 8=cancel button
 func (p *Plugin) handleCreateKeys(msg tea.KeyMsg) tea.Cmd {
 	p.ensureCreateModal()
 	if p.createModal == nil {
 		return nil
 	}
 
+	if p.createSetup != nil {
+		// Setup results: any dismissal continues with the created worktree
+		if msg.String() == "esc" {
+			return p.continueCreateSetup()
+		}
+		action, cmd := p.createModal.HandleKey(msg)
+		if action != "" {
+			return p.continueCreateSetup()
+		}
+		return cmd
+	}
+
 	focusID := p.createModal.FocusedID()
 
 	switch msg.String() {
@@ -915,14 +954,14 @@ func (p *Plugin) handleCreateKeys(msg tea.KeyMsg) tea.Cmd {
 		return nil
 	case "tab":
 		p.blurCreateInputs()
-		p.createFocus = (p.createFocus + 1) % 8
+		p.createFocus = (p.createFocus + 1) % 9
 		p.normalizeCreateFocus()
 		p.focusCreateInput()
 		p.syncCreateModalFocus()
 		return nil
 	case "shift+tab":
 		p.blurCreateInputs()
-		p.createFocus = (p.createFocus + 7) % 8
+		p.createFocus = (p.createFocus + 8) % 9
 		p.normalizeCreateFocus()
 		p.focusCreateInput()
 		p.syncCreateModalFocus()
@@ -1022,10 +1061,10 @@ func (p *Plugin) handleCreateKeys(msg tea.KeyMsg) tea.Cmd {
 			p.syncCreateModalFocus()
 			return nil
 		}
-		if p.createFocus == 6 {
+		if p.createFocus == 6 || p.createFocus == 7 {
 			return p.validateAndCreateWorktree()
 		}
-		if p.createFocus == 7 {
+		if p.createFocus == 8 {
 			p.viewMode = ViewModeList
 			p.clearCreateModal()
 			return nil
@@ -1084,6 +1123,10 @@ func (p *Plugin) validateAndCreateWorktree() tea.Cmd {
 		p.createError = "Invalid branch name: " + strings.Join(p.branchNameErrors, ", ")
 		return nil
 	}
+	if _, err := ParseBudget(p.createBudgetInput.Value()); err != nil {
+		p.createError = "Invalid budget: " + err.Error()
+		return nil
+	}
 	return p.createWorktree()
 }
 
@@ -1119,10 +1162,11 @@ func (p *Plugin) blurCreateInputs() {
 	p.createNameInput.Blur()
 	p.createBaseBranchInput.Blur()
 	p.taskSearchInput.Blur()
+	p.createBudgetInput.Blur()
 }
 
 // focusCreateInput focuses the appropriate textinput based on createFocus.
-// createFocus: 0=name, 1=base, 2=prompt (no textinput), 3=task, 4+=non-inputs
+// createFocus: 0=name, 1=base, 2=prompt (no textinput), 3=task, 6=budget; others are non-inputs
 func (p *Plugin) focusCreateInput() {
 	switch p.createFocus {
 	case 0:
@@ -1132,6 +1176,8 @@ func (p *Plugin) focusCreateInput() {
 	// case 2 is prompt field - no textinput to focus (opens picker on Enter)
 	case 3:
 		p.taskSearchInput.Focus()
+	case 6:
+		p.createBudgetInput.Focus()
 	}
 }
 
@@ -1190,11 +1236,13 @@ func (p *Plugin) handleMergeKeys(msg tea.KeyMsg) tea.Cmd {
 	// Ensure modal is built for key handling
 	p.ensureMergeModal()
 
-	// Handle error step — yank, dismiss
+	// Handle error step — yank, resolve conflicts, dismiss
 	if p.mergeState.Step == MergeStepError {
 		switch msg.String() {
 		case "y":
 			return p.yankMergeErrorToClipboard()
+		case "c":
+			return p.resolveMergeConflicts()
 		case "esc", "q", "enter":
 			p.cancelMergeWorkflow()
 			p.clearMergeModal()
@@ -1207,6 +1255,9 @@ func (p *Plugin) handleMergeKeys(msg tea.KeyMsg) tea.Cmd {
 				p.clearMergeModal()
 				return nil
 			}
+			if action == mergeResolveButtonID {
+				return p.resolveMergeConflicts()
+			}
 			return cmd
 		}
 		return nil
@@ -1337,6 +1388,13 @@ func (p *Plugin) handleMergeKeys(msg tea.KeyMsg) tea.Cmd {
 		}
 		return nil
 
+	case "c":
+		// Resolve conflicts left by a failed rebase or merge in Done step
+		if p.mergeState.Step == MergeStepDone {
+			return p.resolveMergeConflicts()
+		}
+		return nil
+
 	case "m":
 		// Merge action (only when branch diverged in Done step)
 		// Note: 'm' in list view starts merge workflow, but here we're in MergeStepDone
@@ -76,6 +76,7 @@ type MergeWorkflowState struct {
 	ErrorFromStep    MergeWorkflowStep // Which step produced the error
 	StepStatus       map[MergeWorkflowStep]string // "pending", "running", "done", "error", "skipped"
 	DeleteAfterMerge bool                         // true = delete worktree after merge (default)
+	ConflictedFiles  []string                     // Unmerged files left by a failed merge or pull
 
 	// Target branch selection
 	TargetBranch       string   // Resolved target branch for merge/PR
@@ -177,8 +178,9 @@ type CleanupDoneMsg struct {
 // DirectMergeDoneMsg signals that direct merge completed.
 type DirectMergeDoneMsg struct {
 	WorkspaceName string
-	BaseBranch   string
-	Err          error
+	BaseBranch    string
+	Err           error
+	Conflicts     []string // Unmerged files when the merge stopped on conflicts
 }
 
 // PullAfterMergeMsg signals that pull after merge completed.
@@ -490,8 +492,9 @@ func (p *Plugin) performDirectMerge(wt *Worktree, targetBranch string) tea.Cmd {
 		if output, err := mergeCmd.CombinedOutput(); err != nil {
 			return DirectMergeDoneMsg{
 				WorkspaceName: wt.Name,
-				BaseBranch:   baseBranch,
-				Err:          fmt.Errorf("merge %s: %s: %w", branch, strings.TrimSpace(string(output)), err),
+				BaseBranch:    baseBranch,
+				Err:           fmt.Errorf("merge %s: %s: %w", branch, strings.TrimSpace(string(output)), err),
+				Conflicts:     gitstatus.GetConflictedFiles(workDir),
 			}
 		}
 
@@ -652,17 +655,19 @@ func summarizeGitError(err error) (string, string, bool) {
 // RebaseResolutionMsg signals result of rebase resolution attempt.
 type RebaseResolutionMsg struct {
 	WorkspaceName string
-	Branch       string
-	Success      bool
-	Err          error
+	Branch        string
+	Success       bool
+	Err           error
+	Conflicts     []string // Unmerged files when the pull stopped on conflicts
 }
 
 // MergeResolutionMsg signals result of merge resolution attempt.
 type MergeResolutionMsg struct {
 	WorkspaceName string
-	Branch       string
-	Success      bool
-	Err          error
+	Branch        string
+	Success       bool
+	Err           error
+	Conflicts     []string // Unmerged files when the pull stopped on conflicts
 }
 
 // executeRebaseResolution performs git pull --rebase to resolve diverged branches.
@@ -676,16 +681,13 @@ func (p *Plugin) executeRebaseResolution() tea.Cmd {
 	wtName := p.mergeState.Worktree.Name
 
 	return func() tea.Msg {
-		cmd := exec.Command("git", "pull", "--rebase", "origin", branch)
-		cmd.Dir = workDir
-		output, err := cmd.CombinedOutput()
-
-		if err != nil {
+		if err := pullRebase(workDir, branch); err != nil {
 			return RebaseResolutionMsg{
 				WorkspaceName: wtName,
-				Branch:       branch,
-				Success:      false,
-				Err:          fmt.Errorf("rebase failed: %s", strings.TrimSpace(string(output))),
+				Branch:        branch,
+				Success:       false,
+				Err:           err,
+				Conflicts:     gitstatus.GetConflictedFiles(workDir),
 			}
 		}
 
@@ -697,6 +699,16 @@ func (p *Plugin) executeRebaseResolution() tea.Cmd {
 	}
 }
 
+// pullRebase rebases the branch checked out in workDir onto origin/branch.
+func pullRebase(workDir, branch string) error {
+	cmd := exec.Command("git", "pull", "--rebase", "origin", branch)
+	cmd.Dir = workDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("rebase failed: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
 // executeMergeResolution performs git pull (with merge) to resolve diverged branches.
 func (p *Plugin) executeMergeResolution() tea.Cmd {
 	if p.mergeState == nil || p.mergeState.CleanupResults == nil {
@@ -715,9 +727,10 @@ func (p *Plugin) executeMergeResolution() tea.Cmd {
 		if err != nil {
 			return MergeResolutionMsg{
 				WorkspaceName: wtName,
-				Branch:       branch,
-				Success:      false,
-				Err:          fmt.Errorf("merge failed: %s", strings.TrimSpace(string(output))),
+				Branch:        branch,
+				Success:       false,
+				Err:           fmt.Errorf("merge failed: %s", strings.TrimSpace(string(output))),
+				Conflicts:     gitstatus.GetConflictedFiles(workDir),
 			}
 		}
 
@@ -778,12 +791,17 @@ func (p *Plugin) performSelectedCleanup(wt *Worktree, state *MergeWorkflowState)
 		branch := wt.Branch
 
 		// Stop agent if running and clean up tracking (always do this)
-		_ = exec.Command("tmux", "kill-session", "-t", sessionName).Run()
+		_ = backendFor(sessionName).KillSession(sessionName)
 		delete(p.managedSessions, sessionName)
 		globalPaneCache.remove(sessionName)
 
-		// Delete local worktree if selected
+		// Delete local worktree if selected, running teardown steps first
 		if state.DeleteLocalWorktree {
+			for _, r := range p.teardownWorktree(wt) {
+				if r.Err != nil {
+					results.Errors = append(results.Errors, fmt.Sprintf("Teardown %s: %v", r.Step, r.Err))
+				}
+			}
 			if err := doDeleteWorktree(p.ctx.WorkDir, path, false); err != nil {
 				results.Errors = append(results.Errors, fmt.Sprintf("Workspace: %v", err))
 			} else {
@@ -793,15 +811,10 @@ func (p *Plugin) performSelectedCleanup(wt *Worktree, state *MergeWorkflowState)
 
 		// Delete local branch if selected
 		if state.DeleteLocalBranch {
-			cmd := exec.Command("git", "branch", "-d", branch)
-			cmd.Dir = p.ctx.WorkDir
-			if _, err := cmd.CombinedOutput(); err != nil {
+			if err := gitstatus.DeleteBranch(p.ctx.WorkDir, branch); err != nil {
 				// Try force delete if safe delete fails
-				cmd = exec.Command("git", "branch", "-D", branch)
-				cmd.Dir = p.ctx.WorkDir
-				if output, err := cmd.CombinedOutput(); err != nil {
-					results.Errors = append(results.Errors,
-						fmt.Sprintf("Branch: %s", strings.TrimSpace(string(output))))
+				if err := gitstatus.ForceDeleteBranch(p.ctx.WorkDir, branch); err != nil {
+					results.Errors = append(results.Errors, fmt.Sprintf("Branch: %v", err))
 				} else {
 					results.LocalBranchDeleted = true
 				}
@@ -1018,6 +1031,21 @@ func (p *Plugin) cancelMergeWorkflow() {
 	p.viewMode = ViewModeList
 }
 
+// resolveMergeConflicts closes the merge workflow and opens the git plugin's
+// conflict view on the files the merge left unmerged.
+func (p *Plugin) resolveMergeConflicts() tea.Cmd {
+	if p.mergeState == nil || len(p.mergeState.ConflictedFiles) == 0 {
+		return nil
+	}
+	path := p.mergeState.ConflictedFiles[0]
+	p.cancelMergeWorkflow()
+	p.clearMergeModal()
+	return tea.Batch(
+		app.FocusPlugin("git-status"),
+		func() tea.Msg { return gitstatus.OpenConflictsMsg{Path: path} },
+	)
+}
+
 // transitionToMergeError transitions the merge workflow to the error display step.
 func (p *Plugin) transitionToMergeError(fromStep MergeWorkflowStep, title string, err error) {
 	p.mergeState.Error = err
@@ -0,0 +1,501 @@
+package workspace
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/app"
+	"github.com/marcus/sidecar/internal/msg"
+)
+
+// mergeVerifyTimeout bounds the merge queue's verification command.
+const mergeVerifyTimeout = 30 * time.Minute
+
+// MergeQueueEntryStatus is the progress of one worktree in the merge queue.
+type MergeQueueEntryStatus int
+
+const (
+	QueueEntryPending   MergeQueueEntryStatus = iota
+	QueueEntryRebasing                        // Rebasing onto the target branch
+	QueueEntryVerifying                       // Running the verification command
+	QueueEntryLanding                         // Direct merge, or push and PR
+	QueueEntryCleanup                         // Removing the merged worktree
+	QueueEntryLanded                          // Merged, or PR opened
+	QueueEntryConflict                        // Rebase hit conflicts (aborted)
+	QueueEntryFailed                          // A step failed
+)
+
+// String returns a display name for the entry status.
+func (s MergeQueueEntryStatus) String() string {
+	switch s {
+	case QueueEntryPending:
+		return "pending"
+	case QueueEntryRebasing:
+		return "rebasing"
+	case QueueEntryVerifying:
+		return "verifying"
+	case QueueEntryLanding:
+		return "landing"
+	case QueueEntryCleanup:
+		return "cleaning up"
+	case QueueEntryLanded:
+		return "landed"
+	case QueueEntryConflict:
+		return "conflict"
+	case QueueEntryFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// MergeQueuePhase is the stage of the merge queue modal.
+type MergeQueuePhase int
+
+const (
+	MergeQueueSetup    MergeQueuePhase = iota // Selecting and ordering worktrees
+	MergeQueueRunning                         // Landing entries in order
+	MergeQueueFinished                        // All landed, or stopped on a failure
+)
+
+// MergeQueueEntry is a worktree in the merge queue.
+type MergeQueueEntry struct {
+	Worktree *Worktree
+	Selected bool
+	Status   MergeQueueEntryStatus
+	Detail   string // PR URL, cleanup notes, or failure output
+}
+
+// MergeQueueState holds the state for the merge queue modal. Selected entries
+// are landed in list order: each is rebased onto the target branch, verified,
+// merged directly or through a PR, and optionally cleaned up. The queue stops
+// on the first conflict or failure.
+type MergeQueueState struct {
+	Phase   MergeQueuePhase
+	Entries []*MergeQueueEntry
+	Cursor  int
+
+	TargetBranch   string
+	UseDirectMerge bool // false = push and open a PR per entry
+	Cleanup        bool // delete worktree and branch after a direct merge
+	VerifyCommand  string
+
+	// Option editing (target branch or verify command)
+	EditField int // 0 = none, 1 = target, 2 = verify
+	Input     textinput.Model
+
+	Current   int  // index of the entry being landed, -1 when idle
+	StopAfter bool // stop once the current entry finishes
+}
+
+// Merge queue option fields edited with the text input.
+const (
+	mergeQueueEditNone = iota
+	mergeQueueEditTarget
+	mergeQueueEditVerify
+)
+
+// MergeQueueStepMsg reports a finished rebase, verify or push step.
+type MergeQueueStepMsg struct {
+	WorkspaceName string
+	Status        MergeQueueEntryStatus // the step that finished
+	Output        string
+	Err           error
+	Conflict      bool
+}
+
+// openMergeQueue opens the merge queue with every non-main worktree listed.
+// Worktrees whose agent is done are preselected and listed first.
+func (p *Plugin) openMergeQueue() {
+	var done, rest []*MergeQueueEntry
+	for _, wt := range p.worktrees {
+		if wt.IsMain {
+			continue
+		}
+		entry := &MergeQueueEntry{Worktree: wt, Selected: wt.Status == StatusDone}
+		if entry.Selected {
+			done = append(done, entry)
+		} else {
+			rest = append(rest, entry)
+		}
+	}
+	q := &MergeQueueState{
+		Entries:        append(done, rest...),
+		UseDirectMerge: true,
+		Cleanup:        true,
+		Current:        -1,
+	}
+	if len(q.Entries) > 0 {
+		q.TargetBranch = resolveBaseBranch(q.Entries[0].Worktree)
+	}
+	if p.ctx.Config != nil {
+		q.VerifyCommand = p.ctx.Config.Plugins.Workspace.MergeVerifyCommand
+	}
+	p.mergeQueue = q
+	p.viewMode = ViewModeMergeQueue
+	p.clearMergeQueueModal()
+}
+
+// closeMergeQueue closes the modal and refreshes worktrees that were merged.
+func (p *Plugin) closeMergeQueue() tea.Cmd {
+	p.mergeQueue = nil
+	p.viewMode = ViewModeList
+	p.clearMergeQueueModal()
+	return p.refreshWorktrees()
+}
+
+// selected returns the entries chosen for landing, in order.
+func (q *MergeQueueState) selected() []*MergeQueueEntry {
+	var out []*MergeQueueEntry
+	for _, e := range q.Entries {
+		if e.Selected {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// current returns the entry being landed, or nil.
+func (q *MergeQueueState) current() *MergeQueueEntry {
+	if q == nil || q.Current < 0 || q.Current >= len(q.Entries) {
+		return nil
+	}
+	return q.Entries[q.Current]
+}
+
+// owns reports whether the running entry is the named worktree.
+func (q *MergeQueueState) owns(worktreeName string) bool {
+	e := q.current()
+	return e != nil && e.Worktree.Name == worktreeName
+}
+
+// failed returns the entry the queue stopped on, or nil.
+func (q *MergeQueueState) failed() *MergeQueueEntry {
+	for _, e := range q.Entries {
+		if e.Selected && (e.Status == QueueEntryConflict || e.Status == QueueEntryFailed) {
+			return e
+		}
+	}
+	return nil
+}
+
+// moveEntry moves the entry under the cursor up (-1) or down (1).
+func (q *MergeQueueState) moveEntry(delta int) {
+	to := q.Cursor + delta
+	if to < 0 || to >= len(q.Entries) {
+		return
+	}
+	q.Entries[q.Cursor], q.Entries[to] = q.Entries[to], q.Entries[q.Cursor]
+	q.Cursor = to
+}
+
+// startMergeQueue begins landing the selected entries.
+func (p *Plugin) startMergeQueue() tea.Cmd {
+	q := p.mergeQueue
+	if q == nil || len(q.selected()) == 0 || q.TargetBranch == "" {
+		return nil
+	}
+	q.Phase = MergeQueueRunning
+	q.StopAfter = false
+	for _, e := range q.selected() {
+		if e.Status != QueueEntryLanded {
+			e.Status, e.Detail = QueueEntryPending, ""
+		}
+	}
+	return p.advanceMergeQueue()
+}
+
+// advanceMergeQueue starts the next pending entry, or finishes the queue.
+func (p *Plugin) advanceMergeQueue() tea.Cmd {
+	q := p.mergeQueue
+	q.Current = -1
+	if q.StopAfter {
+		q.Phase = MergeQueueFinished
+		return nil
+	}
+	for i, e := range q.Entries {
+		if e.Selected && e.Status == QueueEntryPending {
+			q.Current = i
+			e.Status = QueueEntryRebasing
+			return mergeQueueRebase(e.Worktree, q.TargetBranch)
+		}
+	}
+	q.Phase = MergeQueueFinished
+	return nil
+}
+
+// mergeQueueRebase rebases a worktree's branch onto the updated target. A
+// conflicted rebase is aborted so the worktree is left as it was.
+func mergeQueueRebase(wt *Worktree, target string) tea.Cmd {
+	return func() tea.Msg {
+		err := pullRebase(wt.Path, target)
+		if err == nil {
+			return MergeQueueStepMsg{WorkspaceName: wt.Name, Status: QueueEntryRebasing}
+		}
+		summary, full, _ := summarizeGitError(err)
+		abort := exec.Command("git", "rebase", "--abort")
+		abort.Dir = wt.Path
+		_ = abort.Run()
+		return MergeQueueStepMsg{
+			WorkspaceName: wt.Name,
+			Status:        QueueEntryRebasing,
+			Output:        full,
+			Err:           fmt.Errorf("%s", summary),
+			Conflict:      strings.Contains(strings.ToLower(full), "conflict"),
+		}
+	}
+}
+
+// mergeQueueVerify runs the verification command in a worktree.
+func (p *Plugin) mergeQueueVerify(wt *Worktree, command string) tea.Cmd {
+	mainDir := app.GetMainWorktreePath(p.ctx.WorkDir)
+	if mainDir == "" {
+		mainDir = p.ctx.WorkDir
+	}
+	return func() tea.Msg {
+		r := setupRunner{mainDir: mainDir, wt: wt}
+		output, err := r.runCommand(command, mergeVerifyTimeout)
+		return MergeQueueStepMsg{WorkspaceName: wt.Name, Status: QueueEntryVerifying, Output: output, Err: err}
+	}
+}
+
+// mergeQueuePush pushes a rebased branch for its PR. The rebase rewrote
+// history, so the push is forced (with lease).
+func mergeQueuePush(wt *Worktree) tea.Cmd {
+	return func() tea.Msg {
+		err := doPush(wt.Path, wt.Branch, true, true)
+		return MergeQueueStepMsg{WorkspaceName: wt.Name, Status: QueueEntryLanding, Err: err}
+	}
+}
+
+// handleMergeQueueStep moves the running entry to its next step.
+func (p *Plugin) handleMergeQueueStep(m MergeQueueStepMsg) tea.Cmd {
+	q := p.mergeQueue
+	if !q.owns(m.WorkspaceName) {
+		return nil
+	}
+	e := q.current()
+	if m.Err != nil {
+		status := QueueEntryFailed
+		if m.Conflict {
+			status = QueueEntryConflict
+		}
+		return p.failMergeQueueEntry(e, status, m.Err, m.Output)
+	}
+
+	switch m.Status {
+	case QueueEntryRebasing:
+		if strings.TrimSpace(q.VerifyCommand) != "" {
+			e.Status = QueueEntryVerifying
+			return p.mergeQueueVerify(e.Worktree, q.VerifyCommand)
+		}
+		return p.landMergeQueueEntry(e)
+	case QueueEntryVerifying:
+		return p.landMergeQueueEntry(e)
+	case QueueEntryLanding:
+		// Branch pushed; open the PR
+		return p.createPR(e.Worktree, e.Worktree.Branch, "Created from worktree manager", q.TargetBranch)
+	}
+	return nil
+}
+
+// landMergeQueueEntry merges an entry directly or starts its PR.
+func (p *Plugin) landMergeQueueEntry(e *MergeQueueEntry) tea.Cmd {
+	e.Status = QueueEntryLanding
+	if p.mergeQueue.UseDirectMerge {
+		return p.performDirectMerge(e.Worktree, p.mergeQueue.TargetBranch)
+	}
+	return mergeQueuePush(e.Worktree)
+}
+
+// handleMergeQueueDirectMerge continues after performDirectMerge.
+func (p *Plugin) handleMergeQueueDirectMerge(m DirectMergeDoneMsg) tea.Cmd {
+	e := p.mergeQueue.current()
+	if m.Err != nil {
+		return p.failMergeQueueEntry(e, QueueEntryFailed, fmt.Errorf("direct merge failed"), m.Err.Error())
+	}
+	if !p.mergeQueue.Cleanup {
+		e.Status = QueueEntryLanded
+		return p.advanceMergeQueue()
+	}
+	e.Status = QueueEntryCleanup
+	return p.performSelectedCleanup(e.Worktree, &MergeWorkflowState{
+		DeleteLocalWorktree: true,
+		DeleteLocalBranch:   true,
+	})
+}
+
+// handleMergeQueuePR records the PR opened for the running entry.
+func (p *Plugin) handleMergeQueuePR(m MergeStepCompleteMsg) tea.Cmd {
+	e := p.mergeQueue.current()
+	if m.Err != nil {
+		return p.failMergeQueueEntry(e, QueueEntryFailed, fmt.Errorf("create PR failed"), m.Err.Error())
+	}
+	e.Status = QueueEntryLanded
+	e.Detail = m.Data
+	if m.Data != "" {
+		e.Worktree.PRURL = m.Data
+		_ = savePRURL(e.Worktree.Path, m.Data)
+	}
+	return p.advanceMergeQueue()
+}
+
+// handleMergeQueueCleanup finishes the running entry after cleanup. Cleanup
+// problems are noted but don't stop the queue; the merge already landed.
+func (p *Plugin) handleMergeQueueCleanup(m CleanupDoneMsg) tea.Cmd {
+	e := p.mergeQueue.current()
+	e.Status = QueueEntryLanded
+	if m.Results != nil {
+		e.Detail = strings.Join(m.Results.Errors, "; ")
+		if m.Results.LocalWorktreeDeleted {
+			p.removeWorktreeByName(m.WorkspaceName)
+			if p.selectedIdx >= len(p.worktrees) && p.selectedIdx > 0 {
+				p.selectedIdx--
+			}
+		}
+	}
+	return p.advanceMergeQueue()
+}
+
+// failMergeQueueEntry marks an entry failed and stops the queue.
+func (p *Plugin) failMergeQueueEntry(e *MergeQueueEntry, status MergeQueueEntryStatus, err error, output string) tea.Cmd {
+	e.Status = status
+	e.Detail = err.Error()
+	if output = strings.TrimSpace(output); output != "" && output != e.Detail {
+		e.Detail += "\n" + output
+	}
+	p.mergeQueue.Current = -1
+	p.mergeQueue.Phase = MergeQueueFinished
+	return nil
+}
+
+// retryMergeQueue re-runs the failed entry and continues the queue.
+func (p *Plugin) retryMergeQueue() tea.Cmd {
+	e := p.mergeQueue.failed()
+	if e == nil {
+		return nil
+	}
+	e.Status, e.Detail = QueueEntryPending, ""
+	return p.startMergeQueue()
+}
+
+// skipMergeQueueEntry drops the failed entry and continues with the rest.
+func (p *Plugin) skipMergeQueueEntry() tea.Cmd {
+	e := p.mergeQueue.failed()
+	if e == nil {
+		return nil
+	}
+	e.Selected = false
+	return p.startMergeQueue()
+}
+
+// yankMergeQueueFailure copies the failed entry's output to the clipboard.
+func (p *Plugin) yankMergeQueueFailure() tea.Cmd {
+	e := p.mergeQueue.failed()
+	if e == nil || e.Detail == "" {
+		return nil
+	}
+	if err := clipboard.WriteAll(e.Detail); err != nil {
+		return msg.ShowToast("Copy failed: "+err.Error(), 2*time.Second)
+	}
+	return msg.ShowToast("Copied error to clipboard", 2*time.Second)
+}
+
+// handleMergeQueueKeys handles keys in the merge queue modal.
+func (p *Plugin) handleMergeQueueKeys(keyMsg tea.KeyMsg) tea.Cmd {
+	q := p.mergeQueue
+	if q == nil {
+		p.viewMode = ViewModeList
+		return nil
+	}
+	key := keyMsg.String()
+
+	if q.EditField != mergeQueueEditNone {
+		switch key {
+		case "esc":
+			q.EditField = mergeQueueEditNone
+		case "enter":
+			value := strings.TrimSpace(q.Input.Value())
+			if q.EditField == mergeQueueEditTarget {
+				if value != "" {
+					q.TargetBranch = value
+				}
+			} else {
+				q.VerifyCommand = value
+			}
+			q.EditField = mergeQueueEditNone
+		default:
+			var cmd tea.Cmd
+			q.Input, cmd = q.Input.Update(keyMsg)
+			return cmd
+		}
+		return nil
+	}
+
+	switch q.Phase {
+	case MergeQueueSetup:
+		switch key {
+		case "esc", "q":
+			return p.closeMergeQueue()
+		case "j", "down":
+			if q.Cursor < len(q.Entries)-1 {
+				q.Cursor++
+			}
+		case "k", "up":
+			if q.Cursor > 0 {
+				q.Cursor--
+			}
+		case "J", "shift+down":
+			q.moveEntry(1)
+		case "K", "shift+up":
+			q.moveEntry(-1)
+		case " ", "x":
+			if q.Cursor < len(q.Entries) {
+				q.Entries[q.Cursor].Selected = !q.Entries[q.Cursor].Selected
+			}
+		case "m":
+			q.UseDirectMerge = !q.UseDirectMerge
+		case "c":
+			q.Cleanup = !q.Cleanup
+		case "b":
+			p.editMergeQueueField(mergeQueueEditTarget, q.TargetBranch, "target branch")
+		case "v":
+			p.editMergeQueueField(mergeQueueEditVerify, q.VerifyCommand, "e.g. go test ./...")
+		case "enter":
+			return p.startMergeQueue()
+		}
+	case MergeQueueRunning:
+		if key == "esc" || key == "q" {
+			q.StopAfter = true
+		}
+	case MergeQueueFinished:
+		switch key {
+		case "esc", "q", "enter":
+			return p.closeMergeQueue()
+		case "r":
+			return p.retryMergeQueue()
+		case "s":
+			return p.skipMergeQueueEntry()
+		case "y":
+			return p.yankMergeQueueFailure()
+		}
+	}
+	return nil
+}
+
+// editMergeQueueField opens the text input for an option.
+func (p *Plugin) editMergeQueueField(field int, value, placeholder string) {
+	q := p.mergeQueue
+	q.EditField = field
+	q.Input = textinput.New()
+	q.Input.Placeholder = placeholder
+	q.Input.CharLimit = 200
+	q.Input.SetValue(value)
+	q.Input.CursorEnd()
+	q.Input.Focus()
+}
@@ -0,0 +1,145 @@
+package workspace
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/marcus/sidecar/internal/config"
+	"github.com/marcus/sidecar/internal/plugin"
+)
+
+func newMergeQueueTestPlugin(t *testing.T) *Plugin {
+	t.Helper()
+	p := New()
+	p.ctx = &plugin.Context{Config: config.Default(), WorkDir: t.TempDir()}
+	p.worktrees = []*Worktree{
+		{Name: "main", IsMain: true},
+		{Name: "wip", Branch: "wip", Status: StatusActive},
+		{Name: "auth", Branch: "auth", Status: StatusDone},
+		{Name: "docs", Branch: "docs", Status: StatusDone},
+	}
+	for _, wt := range p.worktrees {
+		wt.Path = t.TempDir()
+	}
+	return p
+}
+
+func queueStatuses(q *MergeQueueState) map[string]MergeQueueEntryStatus {
+	out := make(map[string]MergeQueueEntryStatus)
+	for _, e := range q.Entries {
+		out[e.Worktree.Name] = e.Status
+	}
+	return out
+}
+
+func TestOpenMergeQueue_DoneFirstAndSelected(t *testing.T) {
+	p := newMergeQueueTestPlugin(t)
+	p.openMergeQueue()
+	q := p.mergeQueue
+	if p.viewMode != ViewModeMergeQueue || len(q.Entries) != 3 {
+		t.Fatalf("viewMode %v, %d entries", p.viewMode, len(q.Entries))
+	}
+	var names []string
+	for _, e := range q.selected() {
+		names = append(names, e.Worktree.Name)
+	}
+	if len(names) != 2 || names[0] != "auth" || names[1] != "docs" {
+		t.Errorf("selected = %v, want [auth docs]", names)
+	}
+
+	q.Cursor = 1
+	q.moveEntry(-1)
+	if q.Entries[0].Worktree.Name != "docs" || q.Cursor != 0 {
+		t.Errorf("moveEntry: first = %s cursor %d", q.Entries[0].Worktree.Name, q.Cursor)
+	}
+}
+
+func TestMergeQueue_LandsInOrder(t *testing.T) {
+	p := newMergeQueueTestPlugin(t)
+	p.openMergeQueue()
+	q := p.mergeQueue
+	q.TargetBranch = "main"
+	q.Cleanup = false
+	q.VerifyCommand = "true"
+
+	if p.startMergeQueue() == nil || !q.owns("auth") {
+		t.Fatal("expected the queue to start rebasing auth")
+	}
+	if p.handleMergeQueueStep(MergeQueueStepMsg{WorkspaceName: "auth", Status: QueueEntryRebasing}) == nil ||
+		q.current().Status != QueueEntryVerifying {
+		t.Fatal("expected verify after rebase")
+	}
+	p.handleMergeQueueStep(MergeQueueStepMsg{WorkspaceName: "auth", Status: QueueEntryVerifying})
+	if q.current().Status != QueueEntryLanding {
+		t.Fatalf("status = %v, want landing", q.current().Status)
+	}
+	p.handleMergeQueueDirectMerge(DirectMergeDoneMsg{WorkspaceName: "auth", BaseBranch: "main"})
+	if !q.owns("docs") || queueStatuses(q)["auth"] != QueueEntryLanded {
+		t.Fatalf("statuses = %v", queueStatuses(q))
+	}
+
+	// Messages for other worktrees are ignored
+	if p.handleMergeQueueStep(MergeQueueStepMsg{WorkspaceName: "auth", Status: QueueEntryRebasing}) != nil {
+		t.Error("stale step should be ignored")
+	}
+	p.handleMergeQueueStep(MergeQueueStepMsg{WorkspaceName: "docs", Status: QueueEntryRebasing})
+	p.handleMergeQueueStep(MergeQueueStepMsg{WorkspaceName: "docs", Status: QueueEntryVerifying})
+	p.handleMergeQueueDirectMerge(DirectMergeDoneMsg{WorkspaceName: "docs", BaseBranch: "main"})
+	if q.Phase != MergeQueueFinished || q.Current != -1 || queueStatuses(q)["docs"] != QueueEntryLanded {
+		t.Errorf("phase %v current %d statuses %v", q.Phase, q.Current, queueStatuses(q))
+	}
+	if queueStatuses(q)["wip"] != QueueEntryPending {
+		t.Error("unselected worktree should be untouched")
+	}
+}
+
+func TestMergeQueue_StopsOnConflictAndSkips(t *testing.T) {
+	p := newMergeQueueTestPlugin(t)
+	p.openMergeQueue()
+	q := p.mergeQueue
+	q.TargetBranch = "main"
+	p.startMergeQueue()
+
+	p.handleMergeQueueStep(MergeQueueStepMsg{
+		WorkspaceName: "auth",
+		Status:        QueueEntryRebasing,
+		Err:           errors.New("rebase failed"),
+		Output:        "CONFLICT (content): Merge conflict in auth.go",
+		Conflict:      true,
+	})
+	if q.Phase != MergeQueueFinished || queueStatuses(q)["auth"] != QueueEntryConflict {
+		t.Fatalf("phase %v statuses %v", q.Phase, queueStatuses(q))
+	}
+	if queueStatuses(q)["docs"] != QueueEntryPending {
+		t.Error("queue should stop before docs")
+	}
+	if f := q.failed(); f == nil || f.Detail != "rebase failed\nCONFLICT (content): Merge conflict in auth.go" {
+		t.Errorf("failed entry = %+v", f)
+	}
+
+	if p.skipMergeQueueEntry() == nil || !q.owns("docs") || q.Phase != MergeQueueRunning {
+		t.Errorf("skip should continue with docs, current %d phase %v", q.Current, q.Phase)
+	}
+}
+
+func TestMergeQueue_StopAfterCurrent(t *testing.T) {
+	p := newMergeQueueTestPlugin(t)
+	p.openMergeQueue()
+	q := p.mergeQueue
+	q.TargetBranch = "main"
+	q.UseDirectMerge = false
+	p.startMergeQueue()
+	q.StopAfter = true
+
+	p.handleMergeQueueStep(MergeQueueStepMsg{WorkspaceName: "auth", Status: QueueEntryRebasing})
+	if p.handleMergeQueueStep(MergeQueueStepMsg{WorkspaceName: "auth", Status: QueueEntryLanding}) == nil {
+		t.Fatal("expected PR creation after push")
+	}
+	p.handleMergeQueuePR(MergeStepCompleteMsg{WorkspaceName: "auth", Step: MergeStepCreatePR, Data: "https://example.com/pr/1"})
+	if q.Phase != MergeQueueFinished || queueStatuses(q)["docs"] != QueueEntryPending {
+		t.Errorf("queue should stop after auth: phase %v statuses %v", q.Phase, queueStatuses(q))
+	}
+	if q.Entries[0].Detail != "https://example.com/pr/1" {
+		t.Errorf("detail = %q, want PR URL", q.Entries[0].Detail)
+	}
+}
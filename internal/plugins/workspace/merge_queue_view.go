@@ -0,0 +1,190 @@
+package workspace
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/marcus/sidecar/internal/modal"
+	"github.com/marcus/sidecar/internal/styles"
+	"github.com/marcus/sidecar/internal/ui"
+)
+
+// ensureMergeQueueModal builds/rebuilds the merge queue modal when needed.
+func (p *Plugin) ensureMergeQueueModal() {
+	modalW := 76
+	maxW := p.width - 4
+	if maxW < 1 {
+		maxW = 1
+	}
+	if modalW > maxW {
+		modalW = maxW
+	}
+
+	if p.mergeQueueModal != nil && p.mergeQueueModalWidth == modalW {
+		return
+	}
+	p.mergeQueueModalWidth = modalW
+
+	p.mergeQueueModal = modal.New("Merge Queue",
+		modal.WithWidth(modalW),
+		modal.WithHints(false),
+	).
+		AddSection(p.mergeQueueContentSection())
+}
+
+// clearMergeQueueModal invalidates the cached modal so it rebuilds next frame.
+func (p *Plugin) clearMergeQueueModal() {
+	p.mergeQueueModal = nil
+	p.mergeQueueModalWidth = 0
+}
+
+// mergeQueueEntryIcon returns the status icon and color for an entry.
+func mergeQueueEntryIcon(e *MergeQueueEntry) (string, lipgloss.TerminalColor) {
+	switch e.Status {
+	case QueueEntryRebasing, QueueEntryVerifying, QueueEntryLanding, QueueEntryCleanup:
+		return "●", styles.Warning
+	case QueueEntryLanded:
+		return "✓", styles.Success
+	case QueueEntryConflict, QueueEntryFailed:
+		return "✗", styles.Error
+	default:
+		return "○", styles.TextMuted
+	}
+}
+
+// mergeQueueContentSection renders the queue entries, options and failure.
+func (p *Plugin) mergeQueueContentSection() modal.Section {
+	return modal.Custom(func(contentWidth int, focusID, hoverID string) modal.RenderedSection {
+		q := p.mergeQueue
+		if q == nil {
+			return modal.RenderedSection{}
+		}
+		var lines []string
+
+		if len(q.Entries) == 0 {
+			lines = append(lines, dimText("No worktrees to merge"))
+			lines = append(lines, "", dimText("esc close"))
+			return modal.RenderedSection{Content: strings.Join(lines, "\n")}
+		}
+
+		// Entries, numbered in landing order
+		order := 0
+		for i, e := range q.Entries {
+			prefix := "  "
+			if q.Phase == MergeQueueSetup && i == q.Cursor {
+				prefix = "> "
+			}
+			check := "[ ]"
+			num := "  "
+			if e.Selected {
+				order++
+				check = "[x]"
+				num = fmt.Sprintf("%d.", order)
+			}
+			icon, color := mergeQueueEntryIcon(e)
+			status := ""
+			if e.Selected {
+				status = e.Status.String()
+			}
+			name := e.Worktree.Name
+			maxName := contentWidth - 30
+			if maxName < 10 {
+				maxName = 10
+			}
+			if len(name) > maxName {
+				name = name[:maxName-3] + "..."
+			}
+			line := fmt.Sprintf("%s%s %-3s %s %-*s %s", prefix, check, num, icon, maxName, name, status)
+			switch {
+			case q.Phase == MergeQueueSetup && i == q.Cursor:
+				lines = append(lines, lipgloss.NewStyle().Foreground(styles.Primary).Render(line))
+			case e.Selected:
+				lines = append(lines, lipgloss.NewStyle().Foreground(color).Render(line))
+			default:
+				lines = append(lines, dimText(line))
+			}
+			if e.Status == QueueEntryLanded && e.Detail != "" {
+				lines = append(lines, dimText("          "+truncateString(e.Detail, contentWidth-10)))
+			}
+		}
+
+		// Options
+		lines = append(lines, "")
+		mode := "push and open PR"
+		if q.UseDirectMerge {
+			mode = "direct merge"
+			if q.Cleanup {
+				mode += ", then delete worktree and branch"
+			}
+		}
+		verify := q.VerifyCommand
+		if verify == "" {
+			verify = "(none)"
+		}
+		lines = append(lines, fmt.Sprintf("Target:  %s", q.TargetBranch))
+		lines = append(lines, fmt.Sprintf("Verify:  %s", verify))
+		lines = append(lines, fmt.Sprintf("Land:    %s", mode))
+
+		if q.EditField != mergeQueueEditNone {
+			label := "Target branch:"
+			if q.EditField == mergeQueueEditVerify {
+				label = "Verify command (empty to skip):"
+			}
+			inputW := contentWidth - 4
+			if inputW < 20 {
+				inputW = 20
+			}
+			q.Input.Width = inputW - 2
+			lines = append(lines, "", label, inputFocusedStyle().Width(inputW).Render(q.Input.View()))
+		}
+
+		// Failure detail
+		if failed := q.failed(); failed != nil && q.Phase == MergeQueueFinished {
+			errStyle := lipgloss.NewStyle().Foreground(styles.Error)
+			lines = append(lines, "", errStyle.Render(fmt.Sprintf("Stopped at %s (%s)", failed.Worktree.Name, failed.Status)))
+			detail := strings.Split(failed.Detail, "\n")
+			if len(detail) > 8 {
+				detail = detail[len(detail)-8:]
+			}
+			for _, d := range detail {
+				lines = append(lines, dimText("  "+truncateString(d, contentWidth-2)))
+			}
+		}
+
+		// Hints
+		lines = append(lines, "")
+		switch {
+		case q.EditField != mergeQueueEditNone:
+			lines = append(lines, dimText("enter save  esc cancel"))
+		case q.Phase == MergeQueueSetup:
+			lines = append(lines, dimText("space select  J/K reorder  b target  v verify  m merge/PR  c cleanup"))
+			lines = append(lines, dimText("enter start  esc cancel"))
+		case q.Phase == MergeQueueRunning:
+			if q.StopAfter {
+				lines = append(lines, dimText("Stopping after the current worktree..."))
+			} else {
+				lines = append(lines, dimText("esc stop after current"))
+			}
+		case q.failed() != nil:
+			lines = append(lines, dimText("r retry  s skip and continue  y copy error  esc close"))
+		default:
+			lines = append(lines, dimText("enter/esc close"))
+		}
+
+		return modal.RenderedSection{Content: strings.Join(lines, "\n")}
+	}, nil)
+}
+
+// renderMergeQueueModal renders the merge queue modal with dimmed background.
+func (p *Plugin) renderMergeQueueModal(width, height int) string {
+	background := p.renderListView(width, height)
+
+	p.ensureMergeQueueModal()
+	if p.mergeQueueModal == nil {
+		return background
+	}
+
+	modalContent := p.mergeQueueModal.Render(width, height, p.mouseHandler)
+	return ui.OverlayModal(background, modalContent, width, height)
+}
@@ -130,6 +130,30 @@ func TestCancelMergeWorkflow(t *testing.T) {
 	}
 }
 
+func TestResolveMergeConflicts(t *testing.T) {
+	p := &Plugin{
+		viewMode: ViewModeMerge,
+		mergeState: &MergeWorkflowState{
+			Worktree:   &Worktree{Name: "test"},
+			Step:       MergeStepError,
+			StepStatus: make(map[MergeWorkflowStep]string),
+		},
+	}
+
+	// Without conflicts there is nothing to resolve
+	if cmd := p.resolveMergeConflicts(); cmd != nil || p.mergeState == nil {
+		t.Fatal("resolve without conflicts should do nothing")
+	}
+
+	p.mergeState.ConflictedFiles = []string{"a.go", "b.go"}
+	if cmd := p.resolveMergeConflicts(); cmd == nil {
+		t.Fatal("resolve with conflicts should return a command")
+	}
+	if p.mergeState != nil || p.viewMode != ViewModeList {
+		t.Error("resolving conflicts should close the merge workflow")
+	}
+}
+
 func TestParsePRMergeStatus(t *testing.T) {
 	// Test parsing various JSON responses from gh pr view
 	tests := []struct {
@@ -1,5 +1,12 @@
 package workspace
 
+import (
+	"io"
+
+	"github.com/marcus/sidecar/internal/adapter"
+	"github.com/marcus/sidecar/internal/sessionlog"
+)
+
 // RefreshMsg triggers a worktree list refresh.
 type RefreshMsg struct{}
 
@@ -64,6 +71,44 @@ type DiffLoadedMsg struct {
 // GetEpoch implements plugin.EpochMessage.
 func (m DiffLoadedMsg) GetEpoch() uint64 { return m.Epoch }
 
+// TranscriptLoadedMsg delivers the agent session shown in the Transcript tab.
+type TranscriptLoadedMsg struct {
+	Epoch         uint64 // Epoch when request was issued (for stale detection)
+	WorkspaceName string
+	Adapter       adapter.Adapter
+	Session       *adapter.Session // nil when the worktree has no session
+	Messages      []adapter.Message
+	Err           error
+	WatchCh       <-chan adapter.Event // set when a watch was started
+	WatchCloser   io.Closer
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m TranscriptLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
+// TranscriptWatchMsg signals a change reported by the transcript watch.
+type TranscriptWatchMsg struct {
+	Epoch         uint64 // Epoch when request was issued (for stale detection)
+	WorkspaceName string
+	Gen           int // watch generation, to ignore closed watches
+	Event         adapter.Event
+	Closed        bool // the watch channel was closed
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m TranscriptWatchMsg) GetEpoch() uint64 { return m.Epoch }
+
+// SessionLogLoadedMsg delivers the session log shown in the Log tab.
+type SessionLogLoadedMsg struct {
+	Epoch   uint64 // Epoch when request was issued (for stale detection)
+	Session string
+	Lines   []sessionlog.Line
+	Err     error
+}
+
+// GetEpoch implements plugin.EpochMessage.
+func (m SessionLogLoadedMsg) GetEpoch() uint64 { return m.Epoch }
+
 // DiffErrorMsg signals diff loading failed.
 type DiffErrorMsg struct {
 	WorkspaceName string
@@ -96,9 +141,10 @@ type CreateWorktreeMsg struct {
 // CreateDoneMsg signals worktree creation completed.
 type CreateDoneMsg struct {
 	Worktree  *Worktree
-	AgentType AgentType // Agent selected at creation
-	SkipPerms bool      // Whether to skip permissions
-	Prompt    *Prompt   // Selected prompt template (nil if none)
+	AgentType AgentType    // Agent selected at creation
+	SkipPerms bool         // Whether to skip permissions
+	Prompt    *Prompt      // Selected prompt template (nil if none)
+	Setup     *SetupReport // Results of the post-creation setup steps
 	Err       error
 }
 
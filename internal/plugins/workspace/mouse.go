@@ -72,6 +72,10 @@ func (p *Plugin) handleMouse(msg tea.MouseMsg) tea.Cmd {
 		return p.handleFetchPRModalMouse(msg)
 	}
 
+	if p.viewMode == ViewModeMergeQueue {
+		return p.handleMergeQueueModalMouse(msg)
+	}
+
 	if p.viewMode == ViewModeMerge {
 		return p.handleMergeModalMouse(msg)
 	}
@@ -106,6 +110,12 @@ func (p *Plugin) handleCreateModalMouse(msg tea.MouseMsg) tea.Cmd {
 	}
 
 	action := p.createModal.HandleMouse(msg, p.mouseHandler)
+	if p.createSetup != nil {
+		if action == createSetupContinueID || action == "cancel" {
+			return p.continueCreateSetup()
+		}
+		return nil
+	}
 	switch action {
 	case "":
 		return nil
@@ -142,6 +152,11 @@ func (p *Plugin) handleCreateModalMouse(msg tea.MouseMsg) tea.Cmd {
 		p.createSkipPermissions = !p.createSkipPermissions
 		p.syncCreateModalFocus()
 		return nil
+	case createBudgetFieldID:
+		p.createFocus = 6
+		p.focusCreateInput()
+		p.syncCreateModalFocus()
+		return nil
 	}
 
 	if idx, ok := parseIndexedID(createBranchItemPrefix, action); ok && idx < len(p.branchFiltered) {
@@ -331,6 +346,15 @@ func (p *Plugin) handleFetchPRModalMouse(msg tea.MouseMsg) tea.Cmd {
 	return nil
 }
 
+func (p *Plugin) handleMergeQueueModalMouse(msg tea.MouseMsg) tea.Cmd {
+	p.ensureMergeQueueModal()
+	if p.mergeQueueModal == nil {
+		return nil
+	}
+	_ = p.mergeQueueModal.HandleMouse(msg, p.mouseHandler)
+	return nil
+}
+
 func (p *Plugin) handleMergeModalMouse(msg tea.MouseMsg) tea.Cmd {
 	p.ensureMergeModal()
 	if p.mergeModal == nil {
@@ -345,6 +369,8 @@ func (p *Plugin) handleMergeModalMouse(msg tea.MouseMsg) tea.Cmd {
 		p.cancelMergeWorkflow()
 		p.clearMergeModal()
 		return nil
+	case mergeResolveButtonID:
+		return p.resolveMergeConflicts()
 	case mergeMethodActionID, mergeTargetActionID, mergeCleanUpButtonID:
 		// Advance to next step
 		return p.advanceMergeStep()
@@ -407,9 +433,9 @@ func (p *Plugin) handleMouseHover(action mouse.MouseAction) tea.Cmd {
 		case regionCreateButton:
 			if idx, ok := action.Region.Data.(int); ok {
 				switch idx {
-				case 6:
-					p.createButtonHover = 1 // Create
 				case 7:
+					p.createButtonHover = 1 // Create
+				case 8:
 					p.createButtonHover = 2 // Cancel
 				}
 			}
@@ -551,7 +577,7 @@ func (p *Plugin) handleMouseClick(action mouse.MouseAction) tea.Cmd {
 		}
 	case regionPreviewTab:
 		// Click on preview tab
-		if idx, ok := action.Region.Data.(int); ok && idx >= 0 && idx <= 2 {
+		if idx, ok := action.Region.Data.(int); ok && idx >= 0 && idx < previewTabCount {
 			prevTab := p.previewTab
 			p.previewTab = PreviewTab(idx)
 			p.previewOffset = 0
@@ -567,6 +593,10 @@ func (p *Plugin) handleMouseClick(action mouse.MouseAction) tea.Cmd {
 				return p.loadSelectedDiff()
 			case PreviewTabTask:
 				return p.loadTaskDetailsIfNeeded()
+			case PreviewTabTranscript:
+				return p.loadSelectedTranscript()
+			case PreviewTabLog:
+				return p.loadSelectedSessionLog()
 			}
 		}
 	case regionKanbanCard:
@@ -658,9 +688,9 @@ func (p *Plugin) handleMouseClick(action mouse.MouseAction) tea.Cmd {
 		// Click on button
 		if idx, ok := action.Region.Data.(int); ok {
 			switch idx {
-			case 6:
-				return p.createWorktree()
 			case 7:
+				return p.validateAndCreateWorktree()
+			case 8:
 				p.viewMode = ViewModeList
 				p.clearCreateModal()
 			}
@@ -836,7 +866,7 @@ func (p *Plugin) scrollPreview(delta int) tea.Cmd {
 	// For output tab with auto-scroll, handle scroll direction correctly:
 	// - Scroll UP (delta < 0): show older content (increase offset from bottom)
 	// - Scroll DOWN (delta > 0): show newer content (decrease offset from bottom)
-	if p.previewTab == PreviewTabOutput {
+	if p.previewTab == PreviewTabOutput || p.previewTab == PreviewTabTranscript || p.previewTab == PreviewTabLog {
 		now := time.Now()
 
 		// Detect and handle scroll bursts (fast trackpad scrolling)
@@ -0,0 +1,103 @@
+package workspace
+
+import (
+	"time"
+
+	"github.com/marcus/sidecar/internal/event"
+	"github.com/marcus/sidecar/internal/notify"
+)
+
+// notifyKind maps a worktree status to the notification it triggers, or ""
+// for statuses that don't notify.
+func notifyKind(status WorktreeStatus) notify.Kind {
+	switch status {
+	case StatusWaiting:
+		return notify.KindWaiting
+	case StatusDone:
+		return notify.KindDone
+	case StatusError:
+		return notify.KindError
+	default:
+		return ""
+	}
+}
+
+// observeWorktreeStatus reports a worktree's current status to the event bus
+// and the notifier.
+func (p *Plugin) observeWorktreeStatus(wt *Worktree) {
+	status := event.AgentStatus{Workspace: wt.Name, Agent: string(wt.ChosenAgentType)}
+	if wt.Agent != nil {
+		status.Agent = string(wt.Agent.Type)
+		status.Detail = wt.Agent.WaitingFor
+	}
+	p.publishAgentStatus(notify.WorktreeKey(wt.Name), status, wt.Status)
+
+	if !p.notifier.Enabled() {
+		return
+	}
+	ev := notify.Event{
+		Key:       notify.WorktreeKey(wt.Name),
+		Workspace: wt.Name,
+		Kind:      notifyKind(wt.Status),
+	}
+	if wt.Agent != nil {
+		ev.Agent = string(wt.Agent.Type)
+		ev.Detail = wt.Agent.WaitingFor
+	}
+	p.notifier.Observe(ev)
+}
+
+// observeShellStatus reports the status of an agent running in a shell.
+func (p *Plugin) observeShellStatus(shell *ShellSession, status WorktreeStatus) {
+	p.publishAgentStatus(notify.ShellKey(shell.TmuxName),
+		event.AgentStatus{Workspace: shell.Name, Shell: true, Agent: string(shell.ChosenAgent)}, status)
+
+	if !p.notifier.Enabled() {
+		return
+	}
+	ev := notify.Event{
+		Key:       notify.ShellKey(shell.TmuxName),
+		Workspace: shell.Name,
+		Agent:     string(shell.ChosenAgent),
+		Kind:      notifyKind(status),
+	}
+	if status == StatusWaiting && shell.Agent != nil && shell.Agent.OutputBuf != nil {
+		ev.Detail = extractPrompt(shell.Agent.OutputBuf.String())
+	}
+	p.notifier.Observe(ev)
+}
+
+// selectedNotifyKey returns the notification key of the selected worktree
+// or shell.
+func (p *Plugin) selectedNotifyKey() (key, name string, ok bool) {
+	if p.shellSelected {
+		if shell := p.getSelectedShell(); shell != nil {
+			return notify.ShellKey(shell.TmuxName), shell.Name, true
+		}
+		return "", "", false
+	}
+	if wt := p.selectedWorktree(); wt != nil {
+		return notify.WorktreeKey(wt.Name), wt.Name, true
+	}
+	return "", "", false
+}
+
+// toggleNotifyMute mutes or unmutes notifications for the selected worktree
+// or shell.
+func (p *Plugin) toggleNotifyMute() {
+	if !p.notifier.Enabled() {
+		p.toastMessage = "Notifications are disabled in config"
+		p.toastTime = time.Now()
+		return
+	}
+	key, name, ok := p.selectedNotifyKey()
+	if !ok {
+		return
+	}
+	if p.notifier.ToggleMute(key) {
+		p.toastMessage = "Muted notifications for " + name
+	} else {
+		p.toastMessage = "Unmuted notifications for " + name
+	}
+	p.toastTime = time.Now()
+}
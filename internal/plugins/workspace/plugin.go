@@ -13,6 +13,7 @@ import (
 	"github.com/marcus/sidecar/internal/markdown"
 	"github.com/marcus/sidecar/internal/modal"
 	"github.com/marcus/sidecar/internal/mouse"
+	"github.com/marcus/sidecar/internal/notify"
 	"github.com/marcus/sidecar/internal/plugin"
 	"github.com/marcus/sidecar/internal/ui"
 	"github.com/marcus/sidecar/internal/plugins/gitstatus"
@@ -76,6 +77,7 @@ const (
 	mergeTargetActionID    = "merge-target-action"
 	mergeCleanUpButtonID   = "merge-cleanup-btn"
 	mergeSkipButtonID      = "merge-skip-btn"
+	mergeResolveButtonID   = "merge-resolve-btn"
 
 	// Prompt Picker modal regions
 	regionPromptItem   = "prompt-item"
@@ -113,6 +115,11 @@ type Plugin struct {
 	// Session tracking for safe cleanup
 	managedSessions map[string]bool
 
+	// Agent session backend for new sessions; existing sessions are routed
+	// with backendFor.
+	backend          AgentBackend
+	listeningUpdates bool // a listenBackendUpdates cmd is outstanding (survives Init)
+
 	// View state
 	viewMode         ViewMode
 	activePane       FocusPane
@@ -138,8 +145,19 @@ type Plugin struct {
 	kanbanRow int // Current row within the column
 
 	// Agent state
-	attachedSession     string // Name of worktree we're attached to (pauses polling)
-	tmuxCaptureMaxBytes int    // Cap for tmux capture output (bytes)
+	attachedSession     string                     // Name of worktree we're attached to (pauses polling)
+	tmuxCaptureMaxBytes int                        // Cap for tmux capture output (bytes)
+	statusRules         map[AgentType]*statusRules // Config overrides of built-in status rules
+	notifier            *notify.Notifier           // Status change notifications
+	publishedStatus     map[string]WorktreeStatus  // Last status published per notify key
+
+	// Transcript tab state
+	transcriptRenderer TranscriptRenderer
+	transcript         transcriptState
+
+	// Session output logs and the Log tab
+	sessionLogs sessionLogs
+	logView     logViewState
 
 	// Timer leak prevention (td-83dc22): generation counters to invalidate stale timers.
 	// When a timer fires, it checks if its captured generation matches the current one.
@@ -171,7 +189,11 @@ type Plugin struct {
 	commitStatusWorktree string // Name of worktree for cached status
 
 	// Conflict detection state
-	conflicts []Conflict
+	conflicts     []Conflict
+	conflictCache *conflictCache // Snapshots and trial merges reused across checks
+
+	// Budget tracking state, by worktree name
+	budgets map[string]*budgetState
 
 	// Create modal state
 	createNameInput       textinput.Model
@@ -181,9 +203,11 @@ type Plugin struct {
 	createAgentType       AgentType // Selected agent type (default: AgentClaude)
 	createAgentIdx        int       // Selected agent index in AgentTypeOrder
 	createSkipPermissions bool      // Skip permissions checkbox
-	createFocus           int       // 0=name, 1=base, 2=prompt, 3=task, 4=agent, 5=skipPerms, 6=create, 7=cancel
-	createButtonHover     int       // 0=none, 1=create, 2=cancel
-	createError           string    // Error message to display in create modal
+	createBudgetInput     textinput.Model
+	createFocus           int            // 0=name, 1=base, 2=prompt, 3=task, 4=agent, 5=skipPerms, 6=budget, 7=create, 8=cancel
+	createButtonHover     int            // 0=none, 1=create, 2=cancel
+	createError           string         // Error message to display in create modal
+	createSetup           *CreateDoneMsg // Created worktree whose setup results are shown
 	createModal           *modal.Modal
 	createModalWidth      int
 
@@ -306,6 +330,9 @@ type Plugin struct {
 	pendingResumeCmd      string // Resume command to inject after shell creation
 	pendingResumeWorktree string // Worktree name to enter interactive mode after agent starts
 
+	// Custom command to run in the next created shell
+	pendingShellCmd string
+
 	// Fetch PR modal state
 	fetchPRItems        []PRListItem // PRs from gh pr list
 	fetchPRFilter       string       // Filter text
@@ -316,6 +343,11 @@ type Plugin struct {
 	fetchPRModal        *modal.Modal // Modal instance
 	fetchPRModalWidth   int          // Cached width for rebuild detection
 
+	// Merge queue modal state
+	mergeQueue           *MergeQueueState
+	mergeQueueModal      *modal.Modal
+	mergeQueueModalWidth int
+
 	// Shell manifest for persistence and cross-instance sync (td-f88fdd)
 	shellManifest *ShellManifest
 	shellWatcher  *ShellWatcher
@@ -374,9 +406,34 @@ func (p *Plugin) SetFocused(f bool) {
 // Init initializes the plugin with context.
 func (p *Plugin) Init(ctx *plugin.Context) error {
 	p.ctx = ctx
+	p.backend = nil
+	if ctx.Config != nil {
+		p.backend = newAgentBackend(ctx.Config.Plugins.Workspace.Backend)
+	}
 	if ctx.Config != nil && ctx.Config.Plugins.Workspace.TmuxCaptureMaxBytes > 0 {
 		p.tmuxCaptureMaxBytes = ctx.Config.Plugins.Workspace.TmuxCaptureMaxBytes
 	}
+	p.statusRules = nil
+	if ctx.Config != nil && len(ctx.Config.Plugins.Workspace.StatusRules) > 0 {
+		rules, err := loadStatusRules(ctx.Config.Plugins.Workspace.StatusRules)
+		if err != nil && ctx.Logger != nil {
+			ctx.Logger.Warn("invalid workspace status rules", "error", err)
+		}
+		p.statusRules = rules
+	}
+	p.notifier.Stop() // drop pending notifications from the previous project
+	p.notifier = nil
+	if ctx.Config != nil && ctx.Config.Notifications.Enabled {
+		p.notifier = notify.New(ctx.Config.Notifications, ctx.InTmux, ctx.Logger)
+	}
+	p.publishedStatus = nil
+	p.stopTranscriptWatch()
+	p.transcript = transcriptState{watchGen: p.transcript.watchGen}
+	p.initSessionLogs()
+	p.logView = logViewState{}
+	p.conflicts = nil
+	p.conflictCache = newConflictCache()
+	p.budgets = make(map[string]*budgetState)
 
 	// Reset agent-related state for clean reinit (important for project switching)
 	// Without this, reconnectAgents() won't run again after switching projects
@@ -419,6 +476,7 @@ func (p *Plugin) Init(ctx *plugin.Context) error {
 		ctx.Keymap.RegisterPluginBinding("enter", "continue", "workspace-merge")
 		ctx.Keymap.RegisterPluginBinding("up", "select-delete", "workspace-merge")
 		ctx.Keymap.RegisterPluginBinding("down", "select-keep", "workspace-merge")
+		ctx.Keymap.RegisterPluginBinding("c", "resolve-merge-conflicts", "workspace-merge")
 
 		// Create modal context
 		ctx.Keymap.RegisterPluginBinding("esc", "cancel", "workspace-create")
@@ -478,6 +536,18 @@ func (p *Plugin) Start() tea.Cmd {
 	// Start shell manifest watcher for cross-instance sync (td-f88fdd)
 	cmds = append(cmds, p.startShellWatcher())
 
+	// Follow pushed output from backends that don't need polling
+	cmds = append(cmds, p.listenBackendUpdates(p.agentBackend()))
+
+	// Delete session logs past their retention
+	cmds = append(cmds, p.pruneSessionLogs())
+
+	// Re-predict conflicts as worktrees change
+	cmds = append(cmds, p.scheduleConflictCheck())
+
+	// Track agent usage against worktree budgets
+	cmds = append(cmds, p.scheduleBudgetCheck())
+
 	return tea.Batch(cmds...)
 }
 
@@ -512,6 +582,31 @@ func (p *Plugin) listenForShellManifestChanges() tea.Cmd {
 	}
 }
 
+// agentBackend returns the backend for new agent sessions.
+func (p *Plugin) agentBackend() AgentBackend {
+	if p.backend == nil {
+		return newAgentBackend(backendAuto)
+	}
+	return p.backend
+}
+
+// listenBackendUpdates waits for the next session with new output on a
+// pushing backend. Only one listener runs; the update handler re-arms it.
+func (p *Plugin) listenBackendUpdates(backend AgentBackend) tea.Cmd {
+	updates := backend.Updates()
+	if updates == nil || p.listeningUpdates {
+		return nil
+	}
+	p.listeningUpdates = true
+	return waitBackendUpdate(updates)
+}
+
+func waitBackendUpdate(updates <-chan string) tea.Cmd {
+	return func() tea.Msg {
+		return backendUpdateMsg{Session: <-updates, updates: updates}
+	}
+}
+
 // Stop cleans up plugin resources.
 func (p *Plugin) Stop() {
 	// Stop shell watcher (td-f88fdd)
@@ -519,6 +614,8 @@ func (p *Plugin) Stop() {
 		p.shellWatcher.Stop()
 		p.shellWatcher = nil
 	}
+	p.stopTranscriptWatch()
+	p.closeSessionLogs()
 }
 
 // saveSelectionState persists the current selection to disk.
@@ -705,8 +802,10 @@ func (p *Plugin) clearCreateModal() {
 	p.createAgentType = AgentClaude // Default to Claude
 	p.createAgentIdx = p.agentTypeIndex(p.createAgentType)
 	p.createSkipPermissions = false
+	p.createBudgetInput = textinput.Model{}
 	p.createFocus = 0
 	p.createError = ""
+	p.createSetup = nil
 	p.createModal = nil
 	p.createModalWidth = 0
 	p.taskSearchInput = textinput.Model{}
@@ -751,6 +850,11 @@ func (p *Plugin) initCreateModalBase() {
 	p.taskSearchInput.Prompt = ""
 	p.taskSearchInput.CharLimit = 100
 
+	p.createBudgetInput = textinput.New()
+	p.createBudgetInput.Placeholder = "unlimited"
+	p.createBudgetInput.Prompt = ""
+	p.createBudgetInput.CharLimit = 50
+
 	// Reset all state
 	p.createTaskID = ""
 	p.createTaskTitle = ""
@@ -759,6 +863,7 @@ func (p *Plugin) initCreateModalBase() {
 	p.createSkipPermissions = false
 	p.createFocus = 0
 	p.createError = ""
+	p.createSetup = nil
 	p.createModal = nil
 	p.createModalWidth = 0
 	p.taskSearchAll = nil
@@ -769,7 +874,7 @@ func (p *Plugin) initCreateModalBase() {
 	// Load prompts from global and project config
 	home, _ := os.UserHomeDir()
 	configDir := filepath.Join(home, ".config", "sidecar")
-	p.createPrompts = LoadPrompts(configDir, p.ctx.WorkDir)
+	p.loadCreatePrompts(configDir)
 	p.createPromptIdx = -1
 	p.promptPicker = nil
 	p.clearPromptPickerModal()
@@ -930,10 +1035,21 @@ func (p *Plugin) ensureVisible() {
 	}
 }
 
+// loadCreatePrompts loads prompts for the create modal, logging any whose
+// template failed to parse.
+func (p *Plugin) loadCreatePrompts(configDir string) {
+	p.createPrompts = LoadPrompts(configDir, p.ctx.WorkDir)
+	for _, pr := range p.createPrompts {
+		if pr.Err != nil && p.ctx.Logger != nil {
+			p.ctx.Logger.Warn("invalid prompt template", "prompt", pr.Name, "source", pr.Source, "error", pr.Err)
+		}
+	}
+}
+
 // cyclePreviewTab cycles through preview tabs.
 func (p *Plugin) cyclePreviewTab(delta int) tea.Cmd {
 	prevTab := p.previewTab
-	p.previewTab = PreviewTab((int(p.previewTab) + delta + 3) % 3)
+	p.previewTab = PreviewTab((int(p.previewTab) + delta + previewTabCount) % previewTabCount)
 	p.previewOffset = 0
 	p.autoScrollOutput = true // Reset auto-scroll when switching tabs
 	p.resetScrollBaseLineCount() // td-f7c8be: clear snapshot when switching tabs
@@ -953,6 +1069,14 @@ func (p *Plugin) cyclePreviewTab(delta int) tea.Cmd {
 		if cmd := p.loadTaskDetailsIfNeeded(); cmd != nil {
 			cmds = append(cmds, cmd)
 		}
+	case PreviewTabTranscript:
+		if cmd := p.loadSelectedTranscript(); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	case PreviewTabLog:
+		if cmd := p.loadSelectedSessionLog(); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
 	}
 	if cmd := p.pollSelectedAgentNowIfVisible(); cmd != nil {
 		cmds = append(cmds, cmd)
@@ -991,6 +1115,14 @@ func (p *Plugin) loadSelectedContent() tea.Cmd {
 		cmds = append(cmds, cmd)
 	}
 
+	if cmd := p.loadSelectedTranscript(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+
+	if cmd := p.loadSelectedSessionLog(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+
 	if cmd := p.pollSelectedAgentNowIfVisible(); cmd != nil {
 		cmds = append(cmds, cmd)
 	}
@@ -87,6 +87,9 @@ func (pp *PromptPicker) Update(msg tea.Msg) (*PromptPicker, tea.Cmd) {
 			}
 			if pp.selectedIdx < len(pp.filtered) {
 				prompt := pp.filtered[pp.selectedIdx]
+				if prompt.Err != nil {
+					return pp, nil // Invalid templates can't be used
+				}
 				return pp, func() tea.Msg { return PromptSelectedMsg{Prompt: &prompt} }
 			}
 			return pp, nil
@@ -265,14 +268,21 @@ func (pp *PromptPicker) View() string {
 			preview = string(runes[:maxPreview-3]) + "..."
 		}
 
+		if p.Err != nil {
+			ticket = "invalid"
+			preview = truncateString(p.Err.Error(), maxPreview)
+		}
+
 		line := fmt.Sprintf("%s%-24s %-7s %-10s %s", prefix, truncateString(p.Name, 24), scope, ticket, preview)
 
-		// Style priority: selected > hover > default
-		switch i {
-		case pp.selectedIdx:
+		// Style priority: selected > hover > invalid > default
+		switch {
+		case i == pp.selectedIdx:
 			sb.WriteString(lipgloss.NewStyle().Foreground(styles.Primary).Render(line))
-		case pp.hoverIdx:
+		case i == pp.hoverIdx:
 			sb.WriteString(lipgloss.NewStyle().Foreground(styles.TextSecondary).Render(line))
+		case p.Err != nil:
+			sb.WriteString(lipgloss.NewStyle().Foreground(styles.Error).Render(line))
 		default:
 			sb.WriteString(dimText(line))
 		}
@@ -284,6 +294,14 @@ func (pp *PromptPicker) View() string {
 		sb.WriteString("\n")
 	}
 
+	// Full error for a selected invalid prompt
+	if pp.selectedIdx >= 0 && pp.selectedIdx < len(pp.filtered) && pp.filtered[pp.selectedIdx].Err != nil {
+		sb.WriteString("\n")
+		errStyle := lipgloss.NewStyle().Foreground(styles.Error)
+		sb.WriteString(errStyle.Render("  " + pp.filtered[pp.selectedIdx].Err.Error()))
+		sb.WriteString("\n")
+	}
+
 	sb.WriteString("\n")
 	if pp.filterFocused {
 		sb.WriteString(dimText("  Enter: select   ↑/↓: move   Tab: list nav"))
@@ -110,6 +110,9 @@ func (p *Plugin) promptPickerSelectCmd() tea.Cmd {
 	}
 	if pp.selectedIdx < len(pp.filtered) {
 		prompt := pp.filtered[pp.selectedIdx]
+		if prompt.Err != nil {
+			return nil // Invalid templates can't be used
+		}
 		return func() tea.Msg { return PromptSelectedMsg{Prompt: &prompt} }
 	}
 	return nil
@@ -269,6 +272,10 @@ func (p *Plugin) promptPickerPromptLine(prompt Prompt, idx int, selected, hovere
 	if runes := []rune(preview); len(runes) > maxPreview {
 		preview = string(runes[:maxPreview-3]) + "..."
 	}
+	if prompt.Err != nil {
+		ticket = "invalid"
+		preview = truncateString(prompt.Err.Error(), maxPreview)
+	}
 
 	line := fmt.Sprintf("%s%-24s %-7s %-10s %s", prefix, truncateString(prompt.Name, 24), scope, ticket, preview)
 	line = ansi.Truncate(line, width, "")
@@ -279,5 +286,8 @@ func (p *Plugin) promptPickerPromptLine(prompt Prompt, idx int, selected, hovere
 	if hovered {
 		return promptPickerHoverStyle.Render(line)
 	}
+	if prompt.Err != nil {
+		return lipgloss.NewStyle().Foreground(styles.Error).Render(line)
+	}
 	return styles.Muted.Render(line)
 }
@@ -2,11 +2,13 @@ package workspace
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
+	"text/template"
 )
 
 // TicketMode defines how the task field behaves with a prompt.
@@ -23,7 +25,11 @@ type Prompt struct {
 	Name       string     `json:"name"`
 	TicketMode TicketMode `json:"ticketMode"`
 	Body       string     `json:"body"`
-	Source     string     `json:"-"` // "global" or "project" (set at load time)
+	Budget     string     `json:"budget,omitempty"` // default run budget, e.g. "$5 2h"
+	Source     string     `json:"-"`                // "global" or "project" (set at load time)
+	Err        error      `json:"-"`                // template error found at load time
+
+	tmpl *template.Template // parsed body with project snippets
 }
 
 // configWithPrompts is the config structure for loading prompts.
@@ -34,7 +40,8 @@ type configWithPrompts struct {
 // LoadPrompts loads and merges prompts from global and project config directories.
 // Project prompts override global prompts with the same name.
 // If no config exists, creates global config with default prompts.
-// Returns sorted list by name.
+// Each body is parsed with the project's shared snippets; prompts that fail
+// have Err set. Returns sorted list by name.
 func LoadPrompts(globalConfigDir, projectDir string) []Prompt {
 	// Load from global config
 	globalPrompts := loadPromptsFromDir(globalConfigDir, "global")
@@ -59,9 +66,14 @@ func LoadPrompts(globalConfigDir, projectDir string) []Prompt {
 		merged[p.Name] = p
 	}
 
-	// Convert to sorted slice
+	// Convert to sorted slice, parsing each body
+	snippets := loadPromptSnippets(filepath.Join(projectConfigDir, promptSnippetsDir))
 	result := make([]Prompt, 0, len(merged))
 	for _, p := range merged {
+		p.tmpl, p.Err = parsePromptTemplate(p.Body, snippets)
+		if _, err := ParseBudget(p.Budget); err != nil && p.Err == nil {
+			p.Err = fmt.Errorf("budget: %w", err)
+		}
 		result = append(result, p)
 	}
 	sort.Slice(result, func(i, j int) bool {
@@ -0,0 +1,223 @@
+package workspace
+
+import (
+	"fmt"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/app"
+	"github.com/marcus/sidecar/internal/event"
+	"github.com/marcus/sidecar/internal/notify"
+	"github.com/marcus/sidecar/internal/remote"
+)
+
+// remoteSession describes a worktree or shell for sessions.list.
+type remoteSession struct {
+	Name       string `json:"name"`
+	Kind       string `json:"kind"` // "worktree" or "shell"
+	Path       string `json:"path,omitempty"`
+	Branch     string `json:"branch,omitempty"`
+	Task       string `json:"task,omitempty"`
+	Agent      string `json:"agent,omitempty"`
+	Session    string `json:"session,omitempty"` // tmux session, if running
+	Status     string `json:"status"`
+	WaitingFor string `json:"waitingFor,omitempty"`
+}
+
+// RemoteMethods lists the remote control methods the workspace serves.
+func (p *Plugin) RemoteMethods() []string {
+	return []string{"worktree.focus", "agent.start", "agent.send", "sessions.list"}
+}
+
+// HandleRemote serves the workspace's remote control methods.
+func (p *Plugin) HandleRemote(req *remote.Request) tea.Cmd {
+	var params struct {
+		Name            string `json:"name"`
+		Agent           string `json:"agent"`
+		Prompt          string `json:"prompt"`
+		SkipPermissions bool   `json:"skipPermissions"`
+		Text            string `json:"text"`
+		Enter           *bool  `json:"enter"`
+	}
+	if err := req.Decode(&params); err != nil {
+		req.Reply(nil, err)
+		return nil
+	}
+	if req.Method == "sessions.list" {
+		req.Reply(p.remoteSessions(), nil)
+		return nil
+	}
+	if params.Name == "" {
+		req.Reply(nil, remote.InvalidParams("name is required"))
+		return nil
+	}
+	wt, shellIdx := p.findRemoteTarget(params.Name)
+	if wt == nil && shellIdx < 0 {
+		req.Reply(nil, remote.InvalidParams("no worktree or shell named %q", params.Name))
+		return nil
+	}
+
+	switch req.Method {
+	case "worktree.focus":
+		req.Reply(nil, nil)
+		return tea.Batch(app.FocusPlugin("workspace"), p.selectRemoteTarget(wt, shellIdx))
+
+	case "agent.start":
+		if wt == nil {
+			req.Reply(nil, remote.InvalidParams("%q is a shell; agents start in worktrees", params.Name))
+			return nil
+		}
+		if wt.Agent != nil {
+			req.Reply(nil, fmt.Errorf("an agent is already running in %s", wt.Name))
+			return nil
+		}
+		agentType := AgentType(params.Agent)
+		if agentType == AgentNone {
+			agentType = wt.ChosenAgentType
+		}
+		if agentType == AgentNone || agentType == AgentShell {
+			agentType = AgentClaude
+		}
+		if _, ok := AgentCommands[agentType]; !ok {
+			req.Reply(nil, remote.InvalidParams("unknown agent %q", params.Agent))
+			return nil
+		}
+		var prompt *Prompt
+		if params.Prompt != "" {
+			prompt = &Prompt{Name: "remote", Body: params.Prompt}
+		}
+		start := p.StartAgentWithOptions(wt, agentType, params.SkipPermissions, prompt)
+		return func() tea.Msg {
+			msg := start()
+			if started, ok := msg.(AgentStartedMsg); ok {
+				if started.Err != nil {
+					req.Reply(nil, started.Err)
+				} else {
+					req.Reply(map[string]any{
+						"session":     started.SessionName,
+						"agent":       string(started.AgentType),
+						"reconnected": started.Reconnected,
+					}, nil)
+				}
+			}
+			return msg
+		}
+
+	case "agent.send":
+		session := ""
+		if wt != nil && wt.Agent != nil {
+			session = wt.Agent.TmuxSession
+		} else if shellIdx >= 0 {
+			session = p.shells[shellIdx].TmuxName
+		}
+		if session == "" {
+			req.Reply(nil, fmt.Errorf("no agent is running in %s", params.Name))
+			return nil
+		}
+		keys := []keySpec{{value: params.Text, literal: true}}
+		if params.Enter == nil || *params.Enter {
+			keys = append(keys, keySpec{value: "Enter"})
+		}
+		return func() tea.Msg {
+			req.Reply(nil, backendFor(session).SendKeys(session, keys...))
+			return nil
+		}
+	}
+
+	req.Reply(nil, remote.MethodNotFound(req.Method))
+	return nil
+}
+
+// findRemoteTarget finds a worktree by name, branch or path, or else a shell
+// by display or session name. shellIdx is -1 when no shell matched.
+func (p *Plugin) findRemoteTarget(name string) (wt *Worktree, shellIdx int) {
+	for _, w := range p.worktrees {
+		if w.Name == name || w.Branch == name || w.Path == name {
+			return w, -1
+		}
+	}
+	for i, shell := range p.shells {
+		if shell.Name == name || shell.TmuxName == name {
+			return nil, i
+		}
+	}
+	return nil, -1
+}
+
+// selectRemoteTarget selects a worktree, or the shell at shellIdx, in the
+// sidebar and loads its content.
+func (p *Plugin) selectRemoteTarget(wt *Worktree, shellIdx int) tea.Cmd {
+	p.exitInteractiveMode()
+	if wt != nil {
+		p.shellSelected = false
+		p.selectedIdx = slices.Index(p.worktrees, wt)
+	} else {
+		p.shellSelected = true
+		p.selectedShellIdx = shellIdx
+	}
+	p.previewOffset = 0
+	p.autoScrollOutput = true
+	p.resetScrollBaseLineCount()
+	p.taskLoading = false
+	p.saveSelectionState()
+	p.ensureVisible()
+	p.activePane = PaneSidebar
+	return p.loadSelectedContent()
+}
+
+// remoteSessions lists worktrees and shells with their agent status.
+func (p *Plugin) remoteSessions() []remoteSession {
+	list := make([]remoteSession, 0, len(p.worktrees)+len(p.shells))
+	for _, wt := range p.worktrees {
+		s := remoteSession{
+			Name:   wt.Name,
+			Kind:   "worktree",
+			Path:   wt.Path,
+			Branch: wt.Branch,
+			Task:   wt.TaskID,
+			Agent:  string(wt.ChosenAgentType),
+			Status: wt.Status.String(),
+		}
+		if wt.Agent != nil {
+			s.Agent = string(wt.Agent.Type)
+			s.Session = wt.Agent.TmuxSession
+			s.WaitingFor = wt.Agent.WaitingFor
+		}
+		list = append(list, s)
+	}
+	for _, shell := range p.shells {
+		s := remoteSession{
+			Name:   shell.Name,
+			Kind:   "shell",
+			Path:   p.ctx.WorkDir,
+			Agent:  string(shell.ChosenAgent),
+			Status: StatusPaused.String(),
+		}
+		if shell.Agent != nil {
+			s.Session = shell.TmuxName
+			s.Status = StatusActive.String()
+		}
+		if status, ok := p.publishedStatus[notify.ShellKey(shell.TmuxName)]; ok {
+			s.Status = status.String()
+		}
+		list = append(list, s)
+	}
+	return list
+}
+
+// publishAgentStatus announces a worktree or shell status change on the
+// event bus. Repeated statuses are not published.
+func (p *Plugin) publishAgentStatus(key string, data event.AgentStatus, status WorktreeStatus) {
+	if last, ok := p.publishedStatus[key]; ok && last == status {
+		return
+	}
+	if p.publishedStatus == nil {
+		p.publishedStatus = make(map[string]WorktreeStatus)
+	}
+	p.publishedStatus[key] = status
+	if p.ctx == nil || p.ctx.EventBus == nil {
+		return
+	}
+	data.Status = status.String()
+	p.ctx.EventBus.Publish(event.TopicAgents, event.NewEvent(event.TypeAgentStatus, event.TopicAgents, data))
+}
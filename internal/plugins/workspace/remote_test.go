@@ -0,0 +1,84 @@
+package workspace
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/marcus/sidecar/internal/event"
+	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/remote"
+)
+
+func TestHandleRemote(t *testing.T) {
+	p := &Plugin{
+		ctx: &plugin.Context{WorkDir: "/repo"},
+		worktrees: []*Worktree{
+			{Name: "main", Path: "/repo", Branch: "main", IsMain: true},
+			{Name: "auth", Path: "/wt/auth", Branch: "feat/auth", Status: StatusWaiting,
+				Agent: &Agent{Type: AgentClaude, TmuxSession: "sidecar-wt-auth", WaitingFor: "Allow edit?"}},
+		},
+		shells: []*ShellSession{{Name: "Shell 1", TmuxName: "sidecar-sh-repo-1"}},
+	}
+	call := func(method, params string) (any, error) {
+		req := remote.NewRequest(method, json.RawMessage(params))
+		p.HandleRemote(req)
+		return req.Wait(time.Second)
+	}
+
+	result, err := call("sessions.list", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessions := result.([]remoteSession)
+	if len(sessions) != 3 || sessions[1].Status != "waiting" || sessions[1].WaitingFor != "Allow edit?" || sessions[2].Kind != "shell" {
+		t.Errorf("sessions = %+v", sessions)
+	}
+
+	if _, err := call("agent.start", `{"name": "feat/auth"}`); err == nil {
+		t.Error("starting a second agent in a worktree should fail")
+	}
+	if _, err := call("agent.start", `{"name": "main", "agent": "nope"}`); err == nil {
+		t.Error("unknown agent type should fail")
+	}
+	if _, err := call("agent.send", `{"name": "main", "text": "hi"}`); err == nil {
+		t.Error("sending to a worktree without an agent should fail")
+	}
+	if _, err := call("worktree.focus", `{"name": "missing"}`); err == nil {
+		t.Error("unknown name should fail")
+	}
+
+	if wt, idx := p.findRemoteTarget("Shell 1"); wt != nil || idx != 0 {
+		t.Errorf("shell lookup = %v, %d", wt, idx)
+	}
+}
+
+func TestPublishAgentStatus(t *testing.T) {
+	bus := event.New()
+	defer bus.Close()
+	ch := bus.Subscribe(event.TopicAgents)
+	p := &Plugin{ctx: &plugin.Context{EventBus: bus}}
+
+	wt := &Worktree{Name: "auth", Status: StatusActive, Agent: &Agent{Type: AgentCodex}}
+	p.observeWorktreeStatus(wt)
+	p.observeWorktreeStatus(wt) // unchanged: not published again
+	wt.Status = StatusDone
+	p.observeWorktreeStatus(wt)
+
+	for _, want := range []string{"active", "done"} {
+		select {
+		case e := <-ch:
+			data := e.Data.(event.AgentStatus)
+			if data.Status != want || data.Workspace != "auth" || data.Agent != "codex" {
+				t.Errorf("event = %+v, want status %s", data, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("no event for %s", want)
+		}
+	}
+	select {
+	case e := <-ch:
+		t.Errorf("unexpected event %+v", e)
+	default:
+	}
+}
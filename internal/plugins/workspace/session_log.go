@@ -0,0 +1,448 @@
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/sessionlog"
+	"github.com/marcus/sidecar/internal/styles"
+)
+
+const (
+	// sessionLogDir is where session logs live, relative to the project root.
+	sessionLogDir = ".sidecar/logs"
+
+	// sessionLogReloadInterval limits Log tab reloads driven by agent polls.
+	sessionLogReloadInterval = 2 * time.Second
+)
+
+// sessionLogs records the output of agent and shell sessions so it outlives
+// them. Logs are opened on first use and keyed by session name.
+type sessionLogs struct {
+	dir       string // empty when logging is disabled
+	opts      sessionlog.Options
+	retention time.Duration
+	open      map[string]*sessionLogEntry
+}
+
+type sessionLogEntry struct {
+	log       *sessionlog.Log
+	status    WorktreeStatus // last logged status
+	hasStatus bool
+}
+
+// initSessionLogs closes logs of the previous project and applies the
+// session log config.
+func (p *Plugin) initSessionLogs() {
+	p.closeSessionLogs()
+	p.sessionLogs = sessionLogs{open: make(map[string]*sessionLogEntry)}
+	if p.ctx.Config == nil || !p.ctx.Config.Plugins.Workspace.SessionLogs.Enabled {
+		return
+	}
+	cfg := p.ctx.Config.Plugins.Workspace.SessionLogs
+	p.sessionLogs.dir = filepath.Join(p.ctx.WorkDir, sessionLogDir)
+	p.sessionLogs.opts = sessionlog.Options{
+		MaxBytes: int64(cfg.MaxSizeMB) * 1024 * 1024,
+		MaxFiles: cfg.MaxFiles,
+	}
+	p.sessionLogs.retention = time.Duration(cfg.RetentionDays) * 24 * time.Hour
+}
+
+// sessionLog returns the open log for a session, opening it if needed. It
+// returns nil when logging is disabled or the log can't be opened.
+func (p *Plugin) sessionLog(session string) *sessionlog.Log {
+	if entry := p.sessionLogEntry(session); entry != nil {
+		return entry.log
+	}
+	return nil
+}
+
+func (p *Plugin) sessionLogEntry(session string) *sessionLogEntry {
+	logs := &p.sessionLogs
+	if logs.dir == "" || session == "" {
+		return nil
+	}
+	if entry, ok := logs.open[session]; ok {
+		return entry
+	}
+	l, err := sessionlog.Open(logs.dir, session, logs.opts)
+	if err != nil {
+		if p.ctx.Logger != nil {
+			p.ctx.Logger.Warn("open session log failed", "session", session, "error", err)
+		}
+		return nil
+	}
+	entry := &sessionLogEntry{log: l}
+	logs.open[session] = entry
+	return entry
+}
+
+// logSessionEvent writes a marker to a session's log.
+func (p *Plugin) logSessionEvent(session, format string, args ...any) {
+	if l := p.sessionLog(session); l != nil {
+		_ = l.Event(format, args...)
+	}
+}
+
+// logSessionStatus records a session's detected status when it changes.
+func (p *Plugin) logSessionStatus(session string, status WorktreeStatus, detail string) {
+	entry := p.sessionLogEntry(session)
+	if entry == nil || (entry.hasStatus && entry.status == status) {
+		return
+	}
+	from := "started"
+	if entry.hasStatus {
+		from = entry.status.String()
+	}
+	if detail != "" {
+		_ = entry.log.Event("status %s -> %s: %s", from, status, detail)
+	} else {
+		_ = entry.log.Event("status %s -> %s", from, status)
+	}
+	entry.status, entry.hasStatus = status, true
+}
+
+// closeSessionLog writes a final marker to a session's log and closes it.
+func (p *Plugin) closeSessionLog(session, event string) {
+	entry, ok := p.sessionLogs.open[session]
+	if !ok {
+		return
+	}
+	_ = entry.log.Event("%s", event)
+	_ = entry.log.Close()
+	delete(p.sessionLogs.open, session)
+}
+
+// closeSessionLogs closes every open log, flushing held output.
+func (p *Plugin) closeSessionLogs() {
+	for session, entry := range p.sessionLogs.open {
+		_ = entry.log.Close()
+		delete(p.sessionLogs.open, session)
+	}
+}
+
+// pruneSessionLogs deletes logs older than the retention period.
+func (p *Plugin) pruneSessionLogs() tea.Cmd {
+	logs := p.sessionLogs
+	if logs.dir == "" || logs.retention <= 0 {
+		return nil
+	}
+	logger := p.ctx.Logger
+	return func() tea.Msg {
+		n, err := sessionlog.Prune(logs.dir, logs.retention, time.Now())
+		if logger != nil {
+			if err != nil {
+				logger.Warn("prune session logs failed", "error", err)
+			} else if n > 0 {
+				logger.Debug("pruned session logs", "removed", n)
+			}
+		}
+		return nil
+	}
+}
+
+// logViewState holds the session log shown in the Log tab.
+type logViewState struct {
+	session  string
+	lines    []sessionlog.Line
+	err      error
+	loading  bool
+	dirty    bool // output arrived while loading; reload when done
+	loadedAt time.Time
+
+	// Search: matches are indices into lines, match the current one.
+	searching bool
+	input     textinput.Model
+	query     string
+	matches   []int
+	match     int
+	reveal    bool // scroll the current match into view on next render
+}
+
+// sessionLogVisible reports whether the Log tab is showing the named worktree.
+func (p *Plugin) sessionLogVisible(worktreeName string) bool {
+	if p.previewTab != PreviewTabLog || p.shellSelected {
+		return false
+	}
+	wt := p.selectedWorktree()
+	return wt != nil && wt.Name == worktreeName
+}
+
+// loadSelectedSessionLog loads the log of the selected worktree's agent when
+// the Log tab is active.
+func (p *Plugin) loadSelectedSessionLog() tea.Cmd {
+	if p.previewTab != PreviewTabLog || p.shellSelected {
+		return nil
+	}
+	wt := p.selectedWorktree()
+	if wt == nil || wt.IsMain {
+		return nil
+	}
+	session := TmuxSessionName(wt)
+	if p.logView.session != session {
+		p.logView = logViewState{session: session}
+	}
+	return p.loadSessionLog()
+}
+
+// loadSessionLog returns a command that reads the Log tab's session log.
+func (p *Plugin) loadSessionLog() tea.Cmd {
+	v := &p.logView
+	dir := p.sessionLogs.dir
+	if dir == "" || v.session == "" {
+		return nil
+	}
+	if v.loading {
+		v.dirty = true
+		return nil
+	}
+	v.loading = true
+	v.dirty = false
+	epoch := p.ctx.Epoch // Capture epoch for stale detection
+	session := v.session
+	return func() tea.Msg {
+		lines, err := sessionlog.Read(dir, session)
+		return SessionLogLoadedMsg{Epoch: epoch, Session: session, Lines: lines, Err: err}
+	}
+}
+
+// handleSessionLogLoaded stores loaded log lines and refreshes search matches.
+func (p *Plugin) handleSessionLogLoaded(msg SessionLogLoadedMsg) tea.Cmd {
+	v := &p.logView
+	if msg.Session != v.session {
+		return nil
+	}
+	v.loading = false
+	v.loadedAt = time.Now()
+	v.err = msg.Err
+	if msg.Err == nil {
+		v.lines = msg.Lines
+		p.updateLogMatches(false)
+	}
+	if v.dirty {
+		return p.loadSessionLog()
+	}
+	return nil
+}
+
+// refreshSessionLogOnPoll reloads the Log tab after agent output, at most
+// once per sessionLogReloadInterval.
+func (p *Plugin) refreshSessionLogOnPoll(worktreeName string) tea.Cmd {
+	if !p.sessionLogVisible(worktreeName) || time.Since(p.logView.loadedAt) < sessionLogReloadInterval {
+		return nil
+	}
+	return p.loadSessionLog()
+}
+
+// handleLogKeys handles search keys on the Log tab. It reports whether the
+// key was consumed.
+func (p *Plugin) handleLogKeys(msg tea.KeyMsg) (tea.Cmd, bool) {
+	v := &p.logView
+	switch msg.String() {
+	case "/":
+		v.searching = true
+		v.input = textinput.New()
+		v.input.Placeholder = "Search log..."
+		v.input.CharLimit = 200
+		v.input.SetValue(v.query)
+		v.input.CursorEnd()
+		v.input.Focus()
+		return nil, true
+	case "n":
+		if v.query != "" {
+			p.stepLogMatch(1)
+			return nil, true
+		}
+	case "N":
+		if v.query != "" {
+			p.stepLogMatch(-1)
+			return nil, true
+		}
+	case "esc":
+		if v.query != "" {
+			v.query = ""
+			v.matches = nil
+			return nil, true
+		}
+	}
+	return nil, false
+}
+
+// handleLogSearchKeys handles keys while the Log tab's search input is open.
+// Matches update as the query is typed.
+func (p *Plugin) handleLogSearchKeys(msg tea.KeyMsg) tea.Cmd {
+	v := &p.logView
+	switch msg.String() {
+	case "esc":
+		v.searching = false
+		v.query = ""
+		v.matches = nil
+		return nil
+	case "enter":
+		v.searching = false
+		v.input.Blur()
+		return nil
+	}
+	var cmd tea.Cmd
+	v.input, cmd = v.input.Update(msg)
+	if q := v.input.Value(); q != v.query {
+		v.query = q
+		p.updateLogMatches(true)
+	}
+	return cmd
+}
+
+// updateLogMatches recomputes search matches. With jump set, or when the
+// current match is gone, the newest match becomes current.
+func (p *Plugin) updateLogMatches(jump bool) {
+	v := &p.logView
+	current := -1
+	if !jump && v.match < len(v.matches) {
+		current = v.matches[v.match]
+	}
+	v.matches = nil
+	if v.query == "" {
+		return
+	}
+	query := strings.ToLower(v.query)
+	for i, line := range v.lines {
+		if strings.Contains(strings.ToLower(line.Text), query) {
+			v.matches = append(v.matches, i)
+		}
+	}
+	if len(v.matches) == 0 {
+		return
+	}
+	for i, idx := range v.matches {
+		if idx == current {
+			v.match = i
+			return
+		}
+	}
+	v.match = len(v.matches) - 1
+	v.reveal = true
+}
+
+// stepLogMatch moves to the next (delta 1) or previous (-1) match, wrapping.
+func (p *Plugin) stepLogMatch(delta int) {
+	v := &p.logView
+	if len(v.matches) == 0 {
+		return
+	}
+	v.match = (v.match + delta + len(v.matches)) % len(v.matches)
+	v.reveal = true
+}
+
+// renderSessionLogContent renders the Log tab, following the newest lines
+// unless the user has scrolled up or jumped to a search match.
+func (p *Plugin) renderSessionLogContent(width, height int) string {
+	wt := p.selectedWorktree()
+	if wt == nil {
+		return dimText("No worktree selected")
+	}
+	if p.sessionLogs.dir == "" {
+		return dimText("Session logs are disabled\nSet plugins.workspace.sessionLogs.enabled to record agent output")
+	}
+	v := &p.logView
+	if v.session != TmuxSessionName(wt) || v.loadedAt.IsZero() {
+		return dimText("Loading log...")
+	}
+	path := filepath.Join(sessionLogDir, v.session+sessionlog.Ext)
+	if v.err != nil {
+		if errors.Is(v.err, os.ErrNotExist) {
+			return dimText("No log yet for this worktree\nAgent output is recorded to " + path)
+		}
+		return styles.StatusDeleted.Render("Failed to read log: " + v.err.Error())
+	}
+
+	header := dimText(path)
+	switch {
+	case v.searching:
+		header = v.input.View()
+	case v.query != "" && len(v.matches) == 0:
+		header += dimText(fmt.Sprintf(" • no matches for %q", v.query))
+	case v.query != "":
+		header += dimText(fmt.Sprintf(" • %q %d/%d (n/N)", v.query, v.match+1, len(v.matches)))
+	}
+	height-- // Reserve line for header
+	if height < 1 {
+		height = 1
+	}
+
+	current := -1
+	if v.query != "" && len(v.matches) > 0 {
+		current = v.matches[v.match]
+	}
+	rows, currentRow := renderLogRows(v.lines, v.query, current)
+
+	// previewOffset is lines from the bottom, as in the Output tab
+	maxOffset := max(len(rows)-height, 0)
+	if v.reveal && currentRow >= 0 {
+		p.previewOffset = len(rows) - currentRow - height/2 - 1
+		p.autoScrollOutput = false
+		v.reveal = false
+	}
+	p.previewOffset = min(max(p.previewOffset, 0), maxOffset)
+	end := len(rows) - p.previewOffset
+	start := max(end-height, 0)
+	return header + "\n" + strings.Join(rows[start:end], "\n")
+}
+
+// renderLogRows renders log lines with dimmed times, a separator where the
+// date changes, and query matches highlighted. It returns the rows and the
+// row of the line at index current, or -1.
+func renderLogRows(lines []sessionlog.Line, query string, current int) ([]string, int) {
+	rows := make([]string, 0, len(lines))
+	currentRow := -1
+	var day string
+	for i, line := range lines {
+		stamp := strings.Repeat(" ", len("15:04:05"))
+		if !line.Time.IsZero() {
+			if d := line.Time.Format("2006-01-02"); d != day {
+				rows = append(rows, dimText("── "+d+" ──"))
+				day = d
+			}
+			stamp = line.Time.Format("15:04:05")
+		}
+		if i == current {
+			currentRow = len(rows)
+		}
+		text := highlightLogMatches(line.Text, query, i == current)
+		if line.Event {
+			text = styles.StatusInProgress.Render("● ") + text
+		}
+		rows = append(rows, dimText(stamp)+" "+text)
+	}
+	return rows, currentRow
+}
+
+// highlightLogMatches highlights case-insensitive occurrences of query.
+func highlightLogMatches(text, query string, current bool) string {
+	lower := strings.ToLower(text)
+	if query == "" || len(lower) != len(text) {
+		return text
+	}
+	style := styles.SearchMatch
+	if current {
+		style = styles.SearchMatchCurrent
+	}
+	query = strings.ToLower(query)
+	var sb strings.Builder
+	for {
+		idx := strings.Index(lower, query)
+		if idx < 0 {
+			break
+		}
+		sb.WriteString(text[:idx])
+		sb.WriteString(style.Render(text[idx : idx+len(query)]))
+		text, lower = text[idx+len(query):], lower[idx+len(query):]
+	}
+	sb.WriteString(text)
+	return sb.String()
+}
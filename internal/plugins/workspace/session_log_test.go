@@ -0,0 +1,125 @@
+package workspace
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/marcus/sidecar/internal/config"
+	"github.com/marcus/sidecar/internal/plugin"
+	"github.com/marcus/sidecar/internal/sessionlog"
+)
+
+func newSessionLogTestPlugin(t *testing.T) *Plugin {
+	t.Helper()
+	cfg := config.Default()
+	p := New()
+	p.ctx = &plugin.Context{Config: cfg, WorkDir: t.TempDir()}
+	p.initSessionLogs()
+	p.worktrees = []*Worktree{{Name: "auth", Path: "/tmp/auth"}}
+	p.activePane = PanePreview
+	p.previewTab = PreviewTabLog
+	return p
+}
+
+func logTexts(t *testing.T, p *Plugin, session string) []string {
+	t.Helper()
+	lines, err := sessionlog.Read(p.sessionLogs.dir, session)
+	if err != nil {
+		t.Fatalf("read log: %v", err)
+	}
+	var texts []string
+	for _, l := range lines {
+		texts = append(texts, l.Text)
+	}
+	return texts
+}
+
+func TestSessionLog_StatusTransitions(t *testing.T) {
+	p := newSessionLogTestPlugin(t)
+	const session = "sidecar-ws-auth"
+
+	p.logSessionStatus(session, StatusActive, "")
+	p.logSessionStatus(session, StatusActive, "") // unchanged, not logged
+	p.logSessionStatus(session, StatusWaiting, "Allow edit?")
+	p.closeSessionLog(session, "session ended")
+
+	want := []string{"status started -> active", "status active -> waiting: Allow edit?", "session ended"}
+	if got := logTexts(t, p, session); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("log = %q, want %q", got, want)
+	}
+	if len(p.sessionLogs.open) != 0 {
+		t.Error("closed log should be forgotten")
+	}
+}
+
+func TestSessionLog_Disabled(t *testing.T) {
+	p := newSessionLogTestPlugin(t)
+	p.ctx.Config.Plugins.Workspace.SessionLogs.Enabled = false
+	p.initSessionLogs()
+	if p.sessionLog("sidecar-ws-auth") != nil {
+		t.Error("expected no log when session logs are disabled")
+	}
+	if p.pruneSessionLogs() != nil {
+		t.Error("expected no prune command when session logs are disabled")
+	}
+}
+
+func TestSessionLog_TabLoadAndSearch(t *testing.T) {
+	p := newSessionLogTestPlugin(t)
+	const session = "sidecar-ws-auth"
+	p.logSessionEvent(session, "agent started (claude)")
+	for _, text := range []string{"build failed", "retrying", "build ok"} {
+		p.logSessionEvent(session, "%s", text)
+	}
+	p.closeSessionLog(session, "session ended")
+
+	cmd := p.loadSelectedSessionLog()
+	if cmd == nil {
+		t.Fatal("expected load command")
+	}
+	msg, ok := cmd().(SessionLogLoadedMsg)
+	if !ok || msg.Err != nil {
+		t.Fatalf("load = %#v", msg)
+	}
+	p.handleSessionLogLoaded(msg)
+	if len(p.logView.lines) != 5 {
+		t.Fatalf("loaded %d lines, want 5", len(p.logView.lines))
+	}
+
+	// Typing a query jumps to the newest match; n wraps to the oldest.
+	p.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	if !p.logView.searching || !p.ConsumesTextInput() {
+		t.Fatal("expected search input to open")
+	}
+	p.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("BUILD")})
+	p.handleListKeys(tea.KeyMsg{Type: tea.KeyEnter})
+	if p.logView.searching || len(p.logView.matches) != 2 || p.logView.matches[p.logView.match] != 3 {
+		t.Fatalf("matches = %v current %d", p.logView.matches, p.logView.match)
+	}
+	p.handleListKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if p.logView.matches[p.logView.match] != 1 {
+		t.Errorf("n should wrap to the oldest match, got line %d", p.logView.matches[p.logView.match])
+	}
+
+	out := ansi.Strip(p.renderSessionLogContent(80, 20))
+	if !strings.Contains(out, `"BUILD" 1/2`) || !strings.Contains(out, "build failed") {
+		t.Errorf("rendered log missing search status or lines:\n%s", out)
+	}
+
+	p.handleListKeys(tea.KeyMsg{Type: tea.KeyEsc})
+	if p.logView.query != "" || p.logView.matches != nil {
+		t.Error("esc should clear the search")
+	}
+}
+
+func TestHighlightLogMatches(t *testing.T) {
+	got := highlightLogMatches("Error: error", "ERROR", false)
+	if ansi.Strip(got) != "Error: error" {
+		t.Errorf("highlighting changed text: %q", ansi.Strip(got))
+	}
+	if got := highlightLogMatches("plain", "", false); got != "plain" {
+		t.Errorf("empty query should not highlight, got %q", got)
+	}
+}
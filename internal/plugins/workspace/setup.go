@@ -1,17 +1,28 @@
 package workspace
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 // Default setup configuration
 const (
 	setupScriptName = ".worktree-setup.sh"
+
+	// setupConfigFile is read from the global config dir and the project's
+	// .sidecar directory; project values override global ones.
+	setupConfigFile = "worktree.json"
+
+	defaultStepTimeout = 5 * time.Minute
+	maxStepOutput      = 4096 // bytes of command output kept per step
 )
 
 // Sidecar files that should be in .gitignore
@@ -22,6 +33,7 @@ var sidecarGitignoreEntries = []string{
 	".sidecar-pr",
 	".sidecar-start.sh",
 	".sidecar-base",
+	".sidecar-budget",
 	".td-root",
 }
 
@@ -31,84 +43,194 @@ var (
 )
 
 // SetupConfig holds worktree setup configuration.
+// When Setup is empty, the env file, symlink and setup script options
+// describe the steps; otherwise Setup lists them explicitly.
 type SetupConfig struct {
-	CopyEnv       bool     // Whether to copy env files (default: true)
-	EnvFiles      []string // List of env files to copy
-	SymlinkDirs   []string // Directories to symlink (default: empty, opt-in)
-	RunSetupScript bool    // Whether to run .worktree-setup.sh (default: true)
+	CopyEnv        bool     // Whether to copy env files (default: true)
+	EnvFiles       []string // List of env files to copy
+	SymlinkDirs    []string // Directories to symlink (default: empty, opt-in)
+	RunSetupScript bool     // Whether to run .worktree-setup.sh (default: true)
+
+	Setup      []SetupStep // Ordered steps run after creation
+	Teardown   []SetupStep // Ordered steps run before deletion
+	ShowOutput bool        // Show step results even when all succeed
+}
+
+// SetupStep is a single setup or teardown action. Exactly one of Copy,
+// Symlink, Template or Run is set.
+type SetupStep struct {
+	Name     string        `json:"name,omitempty"`
+	Copy     []string      `json:"copy,omitempty"`     // Globs relative to the main worktree
+	Symlink  []string      `json:"symlink,omitempty"`  // Directories relative to the main worktree
+	Template *TemplateFile `json:"template,omitempty"` // File rendered with prompt variables
+	Run      string        `json:"run,omitempty"`      // bash command run in the worktree
+	Timeout  string        `json:"timeout,omitempty"`  // Run timeout, e.g. "30s" (default: 5m)
+}
+
+// TemplateFile renders Src from the main worktree to Dst in the new worktree.
+type TemplateFile struct {
+	Src string `json:"src"`
+	Dst string `json:"dst"`
+}
+
+// SetupStepResult is the outcome of one setup or teardown step.
+type SetupStepResult struct {
+	Step     string
+	Output   string
+	Err      error
+	Duration time.Duration
+}
+
+// SetupReport collects the step results of a worktree setup.
+type SetupReport struct {
+	Steps      []SetupStepResult
+	ShowOutput bool
+}
+
+// Failed reports whether any step failed.
+func (r *SetupReport) Failed() bool {
+	if r == nil {
+		return false
+	}
+	for _, s := range r.Steps {
+		if s.Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// shouldShow reports whether the report should be shown in the create modal.
+func (r *SetupReport) shouldShow() bool {
+	return r != nil && len(r.Steps) > 0 && (r.ShowOutput || r.Failed())
 }
 
 // DefaultSetupConfig returns the default setup configuration.
 func DefaultSetupConfig() *SetupConfig {
 	return &SetupConfig{
-		CopyEnv:       true,
-		EnvFiles:      defaultEnvFiles,
-		SymlinkDirs:   nil, // Opt-in, not enabled by default
+		CopyEnv:        true,
+		EnvFiles:       append([]string(nil), defaultEnvFiles...),
+		SymlinkDirs:    nil, // Opt-in, not enabled by default
 		RunSetupScript: true,
 	}
 }
 
-// setupWorktree performs post-creation setup for a new worktree.
-// This includes copying env files, creating symlinks, and running setup scripts.
-func (p *Plugin) setupWorktree(worktreePath, branchName string) error {
-	config := DefaultSetupConfig()
+// setupConfigLayer is one worktree.json file. Only the keys present in the
+// file override earlier layers; lists replace rather than extend them.
+type setupConfigLayer struct {
+	CopyEnv        *bool        `json:"copyEnv"`
+	EnvFiles       *[]string    `json:"envFiles"`
+	SymlinkDirs    *[]string    `json:"symlinkDirs"`
+	RunSetupScript *bool        `json:"runSetupScript"`
+	Setup          *[]SetupStep `json:"setup"`
+	Teardown       *[]SetupStep `json:"teardown"`
+	ShowOutput     *bool        `json:"showOutput"`
+}
 
-	// 0. Ensure sidecar files are in main repo's .gitignore
-	if err := p.ensureSidecarGitignore(); err != nil {
-		p.ctx.Logger.Warn("failed to update .gitignore", "error", err)
-		// Don't fail creation for gitignore errors
+func (l setupConfigLayer) apply(cfg *SetupConfig) {
+	if l.CopyEnv != nil {
+		cfg.CopyEnv = *l.CopyEnv
 	}
-
-	// 1. Copy environment files
-	if config.CopyEnv {
-		if err := p.copyEnvFiles(worktreePath, config.EnvFiles); err != nil {
-			p.ctx.Logger.Warn("failed to copy env files", "path", worktreePath, "error", err)
-			// Don't fail creation for env file errors
-		}
+	if l.EnvFiles != nil {
+		cfg.EnvFiles = *l.EnvFiles
 	}
-
-	// 2. Create symlinks for large directories (if configured)
-	if len(config.SymlinkDirs) > 0 {
-		if err := p.symlinkDirs(worktreePath, config.SymlinkDirs); err != nil {
-			p.ctx.Logger.Warn("failed to create symlinks", "path", worktreePath, "error", err)
-			// Don't fail creation for symlink errors
-		}
+	if l.SymlinkDirs != nil {
+		cfg.SymlinkDirs = *l.SymlinkDirs
+	}
+	if l.RunSetupScript != nil {
+		cfg.RunSetupScript = *l.RunSetupScript
+	}
+	if l.Setup != nil {
+		cfg.Setup = *l.Setup
+	}
+	if l.Teardown != nil {
+		cfg.Teardown = *l.Teardown
 	}
+	if l.ShowOutput != nil {
+		cfg.ShowOutput = *l.ShowOutput
+	}
+}
 
-	// 3. Run setup script (if exists)
-	if config.RunSetupScript {
-		if err := p.runSetupScript(worktreePath, branchName); err != nil {
-			p.ctx.Logger.Warn("setup script failed", "path", worktreePath, "error", err)
-			// Don't fail creation for setup script errors
+// loadSetupConfig layers the global and project worktree.json files over
+// the defaults. Missing files are skipped.
+func loadSetupConfig(globalDir, workDir string) (*SetupConfig, error) {
+	cfg := DefaultSetupConfig()
+	paths := []string{
+		filepath.Join(globalDir, setupConfigFile),
+		filepath.Join(workDir, ".sidecar", setupConfigFile),
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var layer setupConfigLayer
+		if err := json.Unmarshal(data, &layer); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
 		}
+		layer.apply(cfg)
 	}
-
-	return nil
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
 }
 
-// copyEnvFiles copies environment files from the main worktree to the new worktree.
-func (p *Plugin) copyEnvFiles(worktreePath string, envFiles []string) error {
-	for _, envFile := range envFiles {
-		src := filepath.Join(p.ctx.WorkDir, envFile)
+// setupConfig loads the worktree setup configuration for the project.
+func (p *Plugin) setupConfig() (*SetupConfig, error) {
+	home, _ := os.UserHomeDir()
+	return loadSetupConfig(filepath.Join(home, ".config", "sidecar"), p.ctx.WorkDir)
+}
 
-		// Check if source file exists
-		if _, err := os.Stat(src); os.IsNotExist(err) {
-			continue // Skip if file doesn't exist
-		}
+// setupWorktree performs post-creation setup for a new worktree and returns
+// the result of each step. Failures never abort creation.
+func (p *Plugin) setupWorktree(wt *Worktree) *SetupReport {
+	// Ensure sidecar files are in main repo's .gitignore
+	if err := p.ensureSidecarGitignore(); err != nil {
+		p.ctx.Logger.Warn("failed to update .gitignore", "error", err)
+	}
 
-		dst := filepath.Join(worktreePath, envFile)
+	cfg, err := p.setupConfig()
+	if err != nil {
+		p.ctx.Logger.Warn("invalid worktree setup config", "error", err)
+		return &SetupReport{Steps: []SetupStepResult{{Step: setupConfigFile, Err: err}}}
+	}
 
-		// Copy the file
-		if err := copyFile(src, dst); err != nil {
-			p.ctx.Logger.Warn("failed to copy env file",
-				"file", envFile, "src", src, "dst", dst, "error", err)
-			continue // Continue with other files even if one fails
-		}
+	runner := setupRunner{mainDir: p.ctx.WorkDir, wt: wt, vars: p.promptVars(wt)}
+	report := &SetupReport{
+		Steps:      runner.run(cfg.setupSteps(p.ctx.WorkDir)),
+		ShowOutput: cfg.ShowOutput,
+	}
+	p.logStepFailures("setup", wt.Path, report.Steps)
+	return report
+}
 
-		p.ctx.Logger.Debug("copied env file", "file", envFile, "dst", dst)
+// teardownWorktree runs the configured teardown steps before a worktree is
+// deleted and returns the results.
+func (p *Plugin) teardownWorktree(wt *Worktree) []SetupStepResult {
+	cfg, err := p.setupConfig()
+	if err != nil {
+		return []SetupStepResult{{Step: setupConfigFile, Err: err}}
+	}
+	if len(cfg.Teardown) == 0 {
+		return nil
 	}
+	runner := setupRunner{mainDir: p.ctx.WorkDir, wt: wt, vars: p.promptVars(wt)}
+	results := runner.run(cfg.Teardown)
+	p.logStepFailures("teardown", wt.Path, results)
+	return results
+}
 
-	return nil
+func (p *Plugin) logStepFailures(phase, path string, results []SetupStepResult) {
+	for _, r := range results {
+		if r.Err != nil {
+			p.ctx.Logger.Warn("worktree "+phase+" step failed",
+				"path", path, "step", r.Step, "output", r.Output, "error", r.Err)
+		}
+	}
 }
 
 // copyFile copies a single file from src to dst, preserving permissions.
@@ -137,11 +259,185 @@ func copyFile(src, dst string) error {
 	return err
 }
 
+// label describes a step for display.
+func (s SetupStep) label() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	switch {
+	case len(s.Copy) > 0:
+		return "copy " + strings.Join(s.Copy, ", ")
+	case len(s.Symlink) > 0:
+		return "symlink " + strings.Join(s.Symlink, ", ")
+	case s.Template != nil:
+		return "template " + s.Template.Dst
+	default:
+		return "run " + s.Run
+	}
+}
+
+// timeout returns the step's run timeout.
+func (s SetupStep) timeout() time.Duration {
+	if d, err := time.ParseDuration(s.Timeout); err == nil && d > 0 {
+		return d
+	}
+	return defaultStepTimeout
+}
+
+// validate checks that the step has exactly one action and valid options.
+func (s SetupStep) validate() error {
+	actions := 0
+	for _, set := range []bool{len(s.Copy) > 0, len(s.Symlink) > 0, s.Template != nil, s.Run != ""} {
+		if set {
+			actions++
+		}
+	}
+	if actions != 1 {
+		return fmt.Errorf("step must set exactly one of copy, symlink, template or run")
+	}
+	if s.Template != nil && (s.Template.Src == "" || s.Template.Dst == "") {
+		return fmt.Errorf("template step needs src and dst")
+	}
+	if s.Timeout != "" {
+		if s.Run == "" {
+			return fmt.Errorf("timeout only applies to run steps")
+		}
+		if d, err := time.ParseDuration(s.Timeout); err != nil || d <= 0 {
+			return fmt.Errorf("invalid timeout %q", s.Timeout)
+		}
+	}
+	return nil
+}
+
+// validate checks all setup and teardown steps.
+func (c *SetupConfig) validate() error {
+	for i, s := range c.Setup {
+		if err := s.validate(); err != nil {
+			return fmt.Errorf("%s: setup step %d: %w", setupConfigFile, i+1, err)
+		}
+	}
+	for i, s := range c.Teardown {
+		if err := s.validate(); err != nil {
+			return fmt.Errorf("%s: teardown step %d: %w", setupConfigFile, i+1, err)
+		}
+	}
+	return nil
+}
+
+// setupSteps returns the ordered setup steps. Without an explicit setup
+// list, they are derived from the env file, symlink and script options.
+func (c *SetupConfig) setupSteps(mainDir string) []SetupStep {
+	if c.Setup != nil {
+		return c.Setup
+	}
+	var steps []SetupStep
+	if c.CopyEnv && len(c.EnvFiles) > 0 {
+		steps = append(steps, SetupStep{Name: "copy env files", Copy: c.EnvFiles})
+	}
+	if len(c.SymlinkDirs) > 0 {
+		steps = append(steps, SetupStep{Symlink: c.SymlinkDirs})
+	}
+	if c.RunSetupScript {
+		script := filepath.Join(mainDir, setupScriptName)
+		if _, err := os.Stat(script); err == nil {
+			steps = append(steps, SetupStep{Name: setupScriptName, Run: "bash " + shellQuote(script)})
+		}
+	}
+	return steps
+}
+
+// setupRunner runs setup steps for one worktree.
+type setupRunner struct {
+	mainDir string // main worktree, the source for copies and symlinks
+	wt      *Worktree
+	vars    PromptVars // values for template steps
+}
+
+// run executes steps in order. A failing step does not stop later ones.
+func (r setupRunner) run(steps []SetupStep) []SetupStepResult {
+	results := make([]SetupStepResult, 0, len(steps))
+	for _, step := range steps {
+		start := time.Now()
+		var output string
+		var err error
+		switch {
+		case len(step.Copy) > 0:
+			output, err = r.copyGlobs(step.Copy)
+		case len(step.Symlink) > 0:
+			output, err = r.symlinkDirs(step.Symlink)
+		case step.Template != nil:
+			output, err = r.renderTemplate(*step.Template)
+		default:
+			output, err = r.runCommand(step.Run, step.timeout())
+		}
+		results = append(results, SetupStepResult{
+			Step:     step.label(),
+			Output:   output,
+			Err:      err,
+			Duration: time.Since(start),
+		})
+	}
+	return results
+}
+
+// worktreeFile resolves a path inside the worktree, rejecting paths that
+// escape it.
+func (r setupRunner) worktreeFile(rel string) (string, error) {
+	if filepath.IsAbs(rel) || !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("%s: path must be inside the worktree", rel)
+	}
+	return filepath.Join(r.wt.Path, rel), nil
+}
+
+// copyGlobs copies files matching the globs from the main worktree to the
+// same relative paths in the new worktree. Globs without matches are skipped.
+func (r setupRunner) copyGlobs(globs []string) (string, error) {
+	var copied []string
+	var errs []error
+	for _, glob := range globs {
+		matches, err := filepath.Glob(filepath.Join(r.mainDir, glob))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", glob, err))
+			continue
+		}
+		for _, src := range matches {
+			info, err := os.Stat(src)
+			if err != nil || info.IsDir() {
+				continue
+			}
+			rel, err := filepath.Rel(r.mainDir, src)
+			if err != nil {
+				continue
+			}
+			dst, err := r.worktreeFile(rel)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if err := copyFile(src, dst); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", rel, err))
+				continue
+			}
+			copied = append(copied, rel)
+		}
+	}
+	if len(copied) == 0 && len(errs) == 0 {
+		return "no matching files", nil
+	}
+	return joinLines("copied ", copied), errors.Join(errs...)
+}
+
 // symlinkDirs creates symlinks from the main worktree to the new worktree
 // for large directories like node_modules to save disk space.
-func (p *Plugin) symlinkDirs(worktreePath string, dirs []string) error {
+func (r setupRunner) symlinkDirs(dirs []string) (string, error) {
+	var linked []string
+	var errs []error
 	for _, dir := range dirs {
-		src := filepath.Join(p.ctx.WorkDir, dir)
+		src := filepath.Join(r.mainDir, dir)
 
 		// Check if source directory exists
 		srcInfo, err := os.Stat(src)
@@ -149,8 +445,7 @@ func (p *Plugin) symlinkDirs(worktreePath string, dirs []string) error {
 			continue // Skip if directory doesn't exist in main worktree
 		}
 		if err != nil {
-			p.ctx.Logger.Warn("failed to stat source dir",
-				"dir", dir, "error", err)
+			errs = append(errs, err)
 			continue
 		}
 
@@ -159,71 +454,97 @@ func (p *Plugin) symlinkDirs(worktreePath string, dirs []string) error {
 			continue
 		}
 
-		dst := filepath.Join(worktreePath, dir)
+		dst, err := r.worktreeFile(dir)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
 
 		// Remove existing directory in worktree if present
 		// (git checkout might create empty dirs)
 		if _, err := os.Lstat(dst); err == nil {
 			if err := os.RemoveAll(dst); err != nil {
-				p.ctx.Logger.Warn("failed to remove existing dir for symlink",
-					"dir", dir, "dst", dst, "error", err)
+				errs = append(errs, fmt.Errorf("remove existing %s: %w", dir, err))
 				continue
 			}
 		}
 
 		// Create symlink
 		if err := os.Symlink(src, dst); err != nil {
-			p.ctx.Logger.Warn("failed to create symlink",
-				"dir", dir, "src", src, "dst", dst, "error", err)
+			errs = append(errs, err)
 			continue
 		}
-
-		p.ctx.Logger.Debug("created symlink", "dir", dir, "src", src, "dst", dst)
+		linked = append(linked, dir)
 	}
-
-	return nil
+	if len(linked) == 0 && len(errs) == 0 {
+		return "no matching directories", nil
+	}
+	return joinLines("linked ", linked), errors.Join(errs...)
 }
 
-// runSetupScript executes the .worktree-setup.sh script if it exists in the main repo.
-// The script runs with the new worktree as the working directory and receives
-// environment variables for the main worktree path, branch name, and worktree path.
-func (p *Plugin) runSetupScript(worktreePath, branchName string) error {
-	scriptPath := filepath.Join(p.ctx.WorkDir, setupScriptName)
-
-	// Check if setup script exists
-	if _, err := os.Stat(scriptPath); os.IsNotExist(err) {
-		return nil // No script, that's fine
+// renderTemplate renders a file from the main worktree with the prompt
+// template variables and writes it into the new worktree.
+func (r setupRunner) renderTemplate(t TemplateFile) (string, error) {
+	data, err := os.ReadFile(filepath.Join(r.mainDir, t.Src))
+	if err != nil {
+		return "", err
 	}
+	out, err := RenderPrompt(&Prompt{Body: string(data)}, r.vars)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", t.Src, err)
+	}
+	dst, err := r.worktreeFile(t.Dst)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(dst, []byte(out), 0644); err != nil {
+		return "", err
+	}
+	return "wrote " + t.Dst, nil
+}
 
-	// Run the script with the worktree as working directory
-	cmd := exec.Command("bash", scriptPath)
-	cmd.Dir = worktreePath
+// runCommand runs a bash command with the worktree as working directory.
+// The command receives environment variables for the main worktree path,
+// branch name, and worktree path.
+func (r setupRunner) runCommand(command string, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "bash", "-c", command)
+	cmd.Dir = r.wt.Path
+	cmd.WaitDelay = time.Second // Don't wait on background children holding the pipes
 
 	// Build isolated environment with overrides applied
-	isolatedEnv := ApplyEnvOverrides(os.Environ(), BuildEnvOverrides(p.ctx.WorkDir))
+	isolatedEnv := ApplyEnvOverrides(os.Environ(), BuildEnvOverrides(r.mainDir))
 
 	// Add worktree-specific variables
 	cmd.Env = append(isolatedEnv,
-		"MAIN_WORKTREE="+p.ctx.WorkDir,
-		"WORKTREE_BRANCH="+branchName,
-		"WORKTREE_PATH="+worktreePath,
+		"MAIN_WORKTREE="+r.mainDir,
+		"WORKTREE_BRANCH="+r.wt.Branch,
+		"WORKTREE_PATH="+r.wt.Path,
 	)
 
-	// Capture output for logging
 	output, err := cmd.CombinedOutput()
-	if err != nil {
-		p.ctx.Logger.Warn("setup script failed",
-			"script", scriptPath,
-			"output", string(output),
-			"error", err)
-		return err
+	out := strings.TrimSpace(string(output))
+	if len(out) > maxStepOutput {
+		out = "..." + out[len(out)-maxStepOutput:]
 	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return out, fmt.Errorf("timed out after %s", timeout)
+	}
+	return out, err
+}
 
-	p.ctx.Logger.Debug("setup script completed",
-		"script", scriptPath,
-		"output", string(output))
-
-	return nil
+// joinLines prefixes each item and joins them with newlines.
+func joinLines(prefix string, items []string) string {
+	lines := make([]string, len(items))
+	for i, item := range items {
+		lines[i] = prefix + item
+	}
+	return strings.Join(lines, "\n")
 }
 
 // ensureSidecarGitignore ensures sidecar worktree files are in .gitignore.
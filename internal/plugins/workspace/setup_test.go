@@ -1,9 +1,16 @@
 package workspace
 
 import (
+	"errors"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/marcus/sidecar/internal/plugin"
 )
 
 func TestCopyFile(t *testing.T) {
@@ -64,3 +71,221 @@ func TestDefaultSetupConfig(t *testing.T) {
 		t.Error("EnvFiles should have default values")
 	}
 }
+
+func TestLoadSetupConfig(t *testing.T) {
+	globalDir := t.TempDir()
+	workDir := t.TempDir()
+
+	// No files: defaults
+	cfg, err := loadSetupConfig(globalDir, workDir)
+	if err != nil {
+		t.Fatalf("loadSetupConfig: %v", err)
+	}
+	if !cfg.CopyEnv || cfg.Setup != nil || len(cfg.Teardown) != 0 {
+		t.Errorf("expected defaults, got %+v", cfg)
+	}
+
+	global := `{"symlinkDirs": ["node_modules"], "teardown": [{"run": "echo global"}], "showOutput": true}`
+	if err := os.WriteFile(filepath.Join(globalDir, setupConfigFile), []byte(global), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(workDir, ".sidecar"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	project := `{"setup": [{"copy": [".env*"]}, {"run": "make deps", "timeout": "30s"}], "teardown": [{"run": "echo project"}]}`
+	if err := os.WriteFile(filepath.Join(workDir, ".sidecar", setupConfigFile), []byte(project), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err = loadSetupConfig(globalDir, workDir)
+	if err != nil {
+		t.Fatalf("loadSetupConfig: %v", err)
+	}
+	if len(cfg.SymlinkDirs) != 1 || !cfg.ShowOutput {
+		t.Errorf("global values not applied: %+v", cfg)
+	}
+	if len(cfg.Setup) != 2 || cfg.Setup[1].timeout() != 30*time.Second {
+		t.Errorf("unexpected setup steps: %+v", cfg.Setup)
+	}
+	if len(cfg.Teardown) != 1 || cfg.Teardown[0].Run != "echo project" {
+		t.Errorf("project teardown should replace global: %+v", cfg.Teardown)
+	}
+	if got := cfg.setupSteps(workDir); len(got) != 2 {
+		t.Errorf("explicit setup should replace legacy steps, got %+v", got)
+	}
+}
+
+func TestLoadSetupConfigInvalid(t *testing.T) {
+	workDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(workDir, ".sidecar"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	tests := []string{
+		`{"setup": [{"copy": ["a"], "run": "b"}]}`,
+		`{"setup": [{"run": "b", "timeout": "soon"}]}`,
+		`{"teardown": [{"template": {"src": "a"}}]}`,
+		`{"setup": {}}`,
+	}
+	for _, body := range tests {
+		if err := os.WriteFile(filepath.Join(workDir, ".sidecar", setupConfigFile), []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loadSetupConfig(t.TempDir(), workDir); err == nil {
+			t.Errorf("expected error for %s", body)
+		}
+	}
+}
+
+func TestLegacySetupSteps(t *testing.T) {
+	mainDir := t.TempDir()
+	cfg := DefaultSetupConfig()
+	if steps := cfg.setupSteps(mainDir); len(steps) != 1 || len(steps[0].Copy) == 0 {
+		t.Fatalf("expected only the env file step, got %+v", steps)
+	}
+	if err := os.WriteFile(filepath.Join(mainDir, setupScriptName), []byte("true\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	steps := cfg.setupSteps(mainDir)
+	if len(steps) != 2 || steps[1].Name != setupScriptName {
+		t.Fatalf("expected setup script step, got %+v", steps)
+	}
+}
+
+func TestSetupRunner(t *testing.T) {
+	mainDir := t.TempDir()
+	wtDir := t.TempDir()
+	files := map[string]string{
+		".env":               "A=1",
+		".env.local":         "B=2",
+		"config/app.yml":     "x: 1",
+		"tmpl/settings.tmpl": "branch={{branch}} ticket={{ticket}}",
+	}
+	for name, content := range files {
+		path := filepath.Join(mainDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(mainDir, "node_modules"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	wt := &Worktree{Name: "feat", Path: wtDir, Branch: "feat"}
+	runner := setupRunner{mainDir: mainDir, wt: wt, vars: PromptVars{Branch: "feat", Ticket: "td-1"}}
+	results := runner.run([]SetupStep{
+		{Copy: []string{".env*", "config/*.yml"}},
+		{Symlink: []string{"node_modules", "missing"}},
+		{Template: &TemplateFile{Src: "tmpl/settings.tmpl", Dst: "settings.ini"}},
+		{Run: `echo "$WORKTREE_BRANCH"`},
+		{Run: "exit 3"},
+		{Run: "sleep 5", Timeout: "100ms"},
+		{Template: &TemplateFile{Src: "tmpl/settings.tmpl", Dst: "../escape"}},
+	})
+	if len(results) != 7 {
+		t.Fatalf("expected 7 results, got %d", len(results))
+	}
+	for i, r := range results[:4] {
+		if r.Err != nil {
+			t.Errorf("step %d (%s) failed: %v", i, r.Step, r.Err)
+		}
+	}
+
+	for _, name := range []string{".env", ".env.local", "config/app.yml"} {
+		if _, err := os.Stat(filepath.Join(wtDir, name)); err != nil {
+			t.Errorf("expected %s to be copied: %v", name, err)
+		}
+	}
+	if fi, err := os.Lstat(filepath.Join(wtDir, "node_modules")); err != nil || fi.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("expected node_modules symlink, err %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(wtDir, "settings.ini")); string(data) != "branch=feat ticket=td-1" {
+		t.Errorf("template output = %q", data)
+	}
+	if results[3].Output != "feat" {
+		t.Errorf("run output = %q, want %q", results[3].Output, "feat")
+	}
+	if results[4].Err == nil {
+		t.Error("expected failing command to report an error")
+	}
+	if results[5].Err == nil || !strings.Contains(results[5].Err.Error(), "timed out") {
+		t.Errorf("expected timeout error, got %v", results[5].Err)
+	}
+	if results[6].Err == nil {
+		t.Error("expected template destination outside the worktree to fail")
+	}
+
+	report := &SetupReport{Steps: results}
+	if !report.Failed() || !report.shouldShow() {
+		t.Error("expected report with failures to be shown")
+	}
+	if (&SetupReport{Steps: results[:4]}).shouldShow() {
+		t.Error("successful report should not be shown without showOutput")
+	}
+}
+
+func TestCreateDoneShowsSetupResults(t *testing.T) {
+	p := New()
+	p.ctx = &plugin.Context{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	p.width, p.height = 100, 40
+	p.viewMode = ViewModeCreate
+
+	wt := &Worktree{Name: "feat", Path: t.TempDir(), Branch: "feat"}
+	setup := &SetupReport{Steps: []SetupStepResult{
+		{Step: "copy env files", Output: "copied .env"},
+		{Step: "run npm install", Output: "npm ERR! missing script", Err: errors.New("exit status 1")},
+	}}
+	p.Update(CreateDoneMsg{Worktree: wt, AgentType: AgentNone, Setup: setup})
+
+	if p.viewMode != ViewModeCreate || p.createSetup == nil {
+		t.Fatal("expected create modal to stay open with setup results")
+	}
+	p.ensureCreateModal()
+	view := p.createModal.Render(p.width, p.height, p.mouseHandler)
+	for _, want := range []string{"run npm install", "exit status 1", "npm ERR! missing script"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("setup results missing %q", want)
+		}
+	}
+
+	p.continueCreateSetup()
+	if p.viewMode != ViewModeList || p.createSetup != nil {
+		t.Error("expected continue to close the create modal")
+	}
+	if len(p.worktrees) != 1 || p.worktrees[0] != wt {
+		t.Error("expected created worktree to be added")
+	}
+}
+
+func TestExecuteDeleteRunsTeardown(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	repo, worktrees := conflictTestRepo(t, "feat")
+	wt := worktrees[0]
+	config := `{"teardown": [{"run": "echo \"$WORKTREE_BRANCH\" > \"$MAIN_WORKTREE/torn-down\""}, {"run": "exit 2"}]}`
+	if err := os.MkdirAll(filepath.Join(repo, ".sidecar"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, ".sidecar", setupConfigFile), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := New()
+	p.ctx = &plugin.Context{WorkDir: repo, Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	p.deleteConfirmWorktree = wt
+	msg, ok := p.executeDelete()().(DeleteDoneMsg)
+	if !ok || msg.Err != nil {
+		t.Fatalf("expected successful delete, got %+v", msg)
+	}
+
+	if data, _ := os.ReadFile(filepath.Join(repo, "torn-down")); strings.TrimSpace(string(data)) != "feat" {
+		t.Errorf("expected teardown to run before delete, got %q", data)
+	}
+	if len(msg.Warnings) != 1 || !strings.Contains(msg.Warnings[0], "Teardown") {
+		t.Errorf("expected failed teardown step as a warning, got %v", msg.Warnings)
+	}
+	if _, err := os.Stat(wt.Path); !os.IsNotExist(err) {
+		t.Errorf("expected worktree to be deleted, stat err %v", err)
+	}
+}
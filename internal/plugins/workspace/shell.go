@@ -157,6 +157,9 @@ type (
 		HasCursor     bool // True if cursor position was captured
 		PaneHeight    int  // Tmux pane height for cursor offset calculation
 		PaneWidth     int  // Tmux pane width for display alignment
+		// Agent status detected from output (only set for shells running an agent)
+		Status    WorktreeStatus
+		HasStatus bool
 	}
 
 	// RenameShellDoneMsg signals shell rename operation completed
@@ -186,7 +189,7 @@ type pollShellMsg struct{}
 // Called from Init() to reconnect to sessions from previous runs.
 // td-f88fdd: Uses manifest as source of truth, merged with tmux discovery.
 func (p *Plugin) initShellSessions() {
-	// Discover running tmux sessions
+	// Discover running sessions
 	tmuxSessions := p.discoverTmuxSessionNames()
 	tmuxMap := make(map[string]bool)
 	for _, name := range tmuxSessions {
@@ -238,7 +241,7 @@ func (p *Plugin) shellFromDefinition(def ShellDefinition, isRunning bool) *Shell
 	}
 
 	if isRunning {
-		paneID := getPaneID(def.TmuxName)
+		paneID := backendFor(def.TmuxName).PaneID(def.TmuxName)
 		displayType := AgentShell
 		if shell.ChosenAgent != AgentNone {
 			displayType = shell.ChosenAgent
@@ -258,7 +261,7 @@ func (p *Plugin) shellFromDefinition(def ShellDefinition, isRunning bool) *Shell
 
 // shellFromTmux creates a ShellSession from a discovered tmux session.
 func (p *Plugin) shellFromTmux(tmuxName string) *ShellSession {
-	paneID := getPaneID(tmuxName)
+	paneID := backendFor(tmuxName).PaneID(tmuxName)
 	displayName := p.deriveDisplayName(tmuxName)
 
 	return &ShellSession{
@@ -291,27 +294,18 @@ func (p *Plugin) deriveDisplayName(tmuxName string) string {
 	return "Shell"
 }
 
-// discoverTmuxSessionNames returns names of all sidecar shell sessions for this project.
+// discoverTmuxSessionNames returns names of all sidecar shell sessions for
+// this project, on every backend.
 func (p *Plugin) discoverTmuxSessionNames() []string {
 	projectName := filepath.Base(p.ctx.WorkDir)
 	basePrefix := shellSessionPrefix + sanitizeName(projectName)
 
-	cmd := exec.Command("tmux", "list-sessions", "-F", "#{session_name}")
-	output, err := cmd.Output()
-	if err != nil {
-		return nil
-	}
-
 	var result []string
 	indexPattern := regexp.MustCompile(`^` + regexp.QuoteMeta(basePrefix) + `(?:-(\d+))?$`)
 
-	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-		if indexPattern.MatchString(line) {
-			result = append(result, line)
+	for _, name := range listAllSessions() {
+		if indexPattern.MatchString(name) {
+			result = append(result, name)
 		}
 	}
 
@@ -348,7 +342,7 @@ func (p *Plugin) applyManifestSync() {
 		return
 	}
 
-	// Get current tmux sessions
+	// Get current sessions
 	tmuxSessions := p.discoverTmuxSessionNames()
 	tmuxMap := make(map[string]bool)
 	for _, name := range tmuxSessions {
@@ -481,12 +475,38 @@ func (p *Plugin) generateShellSessionName() string {
 	return fmt.Sprintf("%s-%d", basePrefix, p.nextShellIndex())
 }
 
+// shellBackend returns the backend for new shell sessions, the same one
+// agents use.
+func (p *Plugin) shellBackend() (AgentBackend, error) {
+	backend := p.agentBackend()
+	if _, ok := backend.(tmuxBackend); ok && !isTmuxInstalled() {
+		return nil, fmt.Errorf("tmux not installed: %s", getTmuxInstallInstructions())
+	}
+	return backend, nil
+}
+
+// startShellSession starts a shell session in dir, sized to the preview when
+// width and height are known, and returns its pane ID.
+func startShellSession(backend AgentBackend, name, dir string, width, height int) (string, error) {
+	if err := backend.NewSession(name, dir); err != nil {
+		return "", err
+	}
+	if width > 0 && height > 0 {
+		if _, ok := backend.(tmuxBackend); ok {
+			tty.SetWindowSizeManual(name)
+		}
+		backend.Resize(name, width, height)
+	}
+	return backend.PaneID(name), nil
+}
+
 // createNewShell creates a new shell session. If customName is non-empty, it is
 // used as the display name instead of the auto-generated "Shell N".
 func (p *Plugin) createNewShell(customName string) tea.Cmd {
-	if !isTmuxInstalled() {
+	backend, err := p.shellBackend()
+	if err != nil {
 		return func() tea.Msg {
-			return ShellCreatedMsg{Err: fmt.Errorf("tmux not installed: %s", getTmuxInstallInstructions())}
+			return ShellCreatedMsg{Err: err}
 		}
 	}
 
@@ -500,19 +520,13 @@ func (p *Plugin) createNewShell(customName string) tea.Cmd {
 	return func() tea.Msg {
 		// Check if session already exists (shouldn't happen with unique names)
 		if sessionExists(sessionName) {
-			paneID := getPaneID(sessionName)
+			paneID := backendFor(sessionName).PaneID(sessionName)
 			return ShellCreatedMsg{SessionName: sessionName, DisplayName: displayName, PaneID: paneID}
 		}
 
 		// Create new detached session in project directory
-		args := []string{
-			"new-session",
-			"-d",              // Detached
-			"-s", sessionName, // Session name
-			"-c", workDir, // Working directory
-		}
-		cmd := exec.Command("tmux", args...)
-		if err := cmd.Run(); err != nil {
+		paneID, err := startShellSession(backend, sessionName, workDir, 0, 0)
+		if err != nil {
 			return ShellCreatedMsg{
 				SessionName: sessionName,
 				DisplayName: displayName,
@@ -520,9 +534,6 @@ func (p *Plugin) createNewShell(customName string) tea.Cmd {
 			}
 		}
 
-		// Capture pane ID for interactive mode support
-		paneID := getPaneID(sessionName)
-
 		return ShellCreatedMsg{SessionName: sessionName, DisplayName: displayName, PaneID: paneID}
 	}
 }
@@ -536,9 +547,10 @@ func (p *Plugin) createShellWithAgent() tea.Cmd {
 	agentType := p.typeSelectorAgentType
 	skipPerms := p.typeSelectorSkipPerms
 
-	if !isTmuxInstalled() {
+	backend, err := p.shellBackend()
+	if err != nil {
 		return func() tea.Msg {
-			return ShellCreatedMsg{Err: fmt.Errorf("tmux not installed: %s", getTmuxInstallInstructions())}
+			return ShellCreatedMsg{Err: err}
 		}
 	}
 
@@ -552,7 +564,7 @@ func (p *Plugin) createShellWithAgent() tea.Cmd {
 	return func() tea.Msg {
 		// Check if session already exists (shouldn't happen with unique names)
 		if sessionExists(sessionName) {
-			paneID := getPaneID(sessionName)
+			paneID := backendFor(sessionName).PaneID(sessionName)
 			return ShellCreatedMsg{
 				SessionName: sessionName,
 				DisplayName: displayName,
@@ -563,14 +575,8 @@ func (p *Plugin) createShellWithAgent() tea.Cmd {
 		}
 
 		// Create new detached session in project directory
-		args := []string{
-			"new-session",
-			"-d",              // Detached
-			"-s", sessionName, // Session name
-			"-c", workDir, // Working directory
-		}
-		cmd := exec.Command("tmux", args...)
-		if err := cmd.Run(); err != nil {
+		paneID, err := startShellSession(backend, sessionName, workDir, 0, 0)
+		if err != nil {
 			return ShellCreatedMsg{
 				SessionName: sessionName,
 				DisplayName: displayName,
@@ -578,9 +584,6 @@ func (p *Plugin) createShellWithAgent() tea.Cmd {
 			}
 		}
 
-		// Capture pane ID for interactive mode support
-		paneID := getPaneID(sessionName)
-
 		return ShellCreatedMsg{
 			SessionName: sessionName,
 			DisplayName: displayName,
@@ -591,7 +594,7 @@ func (p *Plugin) createShellWithAgent() tea.Cmd {
 	}
 }
 
-// recreateOrphanedShell recreates a tmux session for an orphaned shell.
+// recreateOrphanedShell recreates the session for an orphaned shell.
 // td-f88fdd: Called when user tries to attach/interact with an orphaned shell.
 func (p *Plugin) recreateOrphanedShell(idx int) tea.Cmd {
 	if idx < 0 || idx >= len(p.shells) {
@@ -606,15 +609,17 @@ func (p *Plugin) recreateOrphanedShell(idx int) tea.Cmd {
 	sessionName := shell.TmuxName
 	workDir := p.ctx.WorkDir
 	previewWidth, previewHeight := p.calculatePreviewDimensions()
+	backend, err := p.shellBackend()
+	if err != nil {
+		return func() tea.Msg {
+			return ShellCreatedMsg{SessionName: sessionName, DisplayName: shell.Name, Err: err}
+		}
+	}
 
 	return func() tea.Msg {
 		// Create new detached session
-		args := []string{"new-session", "-d", "-s", sessionName, "-c", workDir}
-		if previewWidth > 0 && previewHeight > 0 {
-			args = append(args, "-x", strconv.Itoa(previewWidth), "-y", strconv.Itoa(previewHeight))
-		}
-		cmd := exec.Command("tmux", args...)
-		if err := cmd.Run(); err != nil {
+		paneID, err := startShellSession(backend, sessionName, workDir, previewWidth, previewHeight)
+		if err != nil {
 			return ShellCreatedMsg{
 				SessionName: sessionName,
 				DisplayName: shell.Name,
@@ -622,11 +627,6 @@ func (p *Plugin) recreateOrphanedShell(idx int) tea.Cmd {
 			}
 		}
 
-		tty.SetWindowSizeManual(sessionName)
-
-		// Capture pane ID
-		paneID := getPaneID(sessionName)
-
 		return ShellCreatedMsg{
 			SessionName: sessionName,
 			DisplayName: shell.Name,
@@ -637,7 +637,7 @@ func (p *Plugin) recreateOrphanedShell(idx int) tea.Cmd {
 	}
 }
 
-// startAgentInShell sends an agent command to an existing shell's tmux session.
+// startAgentInShell sends an agent command to an existing shell's session.
 // td-21a2d8: Called after shell is created when an agent was selected.
 func (p *Plugin) startAgentInShell(tmuxName string, agentType AgentType, skipPerms bool) tea.Cmd {
 	return func() tea.Msg {
@@ -657,9 +657,9 @@ func (p *Plugin) startAgentInShell(tmuxName string, agentType AgentType, skipPer
 			}
 		}
 
-		// Send the command to the shell's tmux session
-		cmd := exec.Command("tmux", "send-keys", "-t", tmuxName, baseCmd, "Enter")
-		if err := cmd.Run(); err != nil {
+		// Send the command to the shell's session
+		err := backendFor(tmuxName).SendKeys(tmuxName, keySpec{value: baseCmd}, keySpec{value: "Enter"})
+		if err != nil {
 			return ShellAgentErrorMsg{
 				TmuxName: tmuxName,
 				Err:      fmt.Errorf("failed to start agent: %w", err),
@@ -675,6 +675,7 @@ func (p *Plugin) startAgentInShell(tmuxName string, agentType AgentType, skipPer
 }
 
 // attachToShellByIndex attaches to a specific shell session by index.
+// PTY sessions can't be attached; they open in interactive mode instead.
 func (p *Plugin) attachToShellByIndex(idx int) tea.Cmd {
 	if idx < 0 || idx >= len(p.shells) {
 		return nil
@@ -684,6 +685,12 @@ func (p *Plugin) attachToShellByIndex(idx int) tea.Cmd {
 	sessionName := shell.TmuxName
 	displayName := shell.Name
 
+	if globalPTYBackend.owns(sessionName) {
+		p.shellSelected = true
+		p.selectedShellIdx = idx
+		return p.enterInteractiveMode()
+	}
+
 	target := ""
 	if shell.Agent != nil && shell.Agent.TmuxPane != "" {
 		target = shell.Agent.TmuxPane
@@ -714,23 +721,22 @@ func (p *Plugin) ensureShellAndAttachByIndex(idx int) tea.Cmd {
 	// Session doesn't exist but we have a record - recreate it
 	workDir := p.ctx.WorkDir
 	previewWidth, previewHeight := p.calculatePreviewDimensions()
+	backend, err := p.shellBackend()
+	if err != nil {
+		return func() tea.Msg {
+			return ShellCreatedMsg{SessionName: sessionName, DisplayName: shell.Name, Err: err}
+		}
+	}
 	return tea.Sequence(
 		func() tea.Msg {
-			args := []string{"new-session", "-d", "-s", sessionName, "-c", workDir}
-			if previewWidth > 0 && previewHeight > 0 {
-				args = append(args, "-x", strconv.Itoa(previewWidth), "-y", strconv.Itoa(previewHeight))
-			}
-			cmd := exec.Command("tmux", args...)
-			if err := cmd.Run(); err != nil {
+			paneID, err := startShellSession(backend, sessionName, workDir, previewWidth, previewHeight)
+			if err != nil {
 				return ShellCreatedMsg{
 					SessionName: sessionName,
 					DisplayName: shell.Name,
 					Err:         fmt.Errorf("recreate shell session: %w", err),
 				}
 			}
-			tty.SetWindowSizeManual(sessionName)
-			// Capture pane ID for interactive mode support
-			paneID := getPaneID(sessionName)
 			return ShellCreatedMsg{SessionName: sessionName, DisplayName: shell.Name, PaneID: paneID}
 		},
 		func() tea.Msg {
@@ -746,7 +752,7 @@ func (p *Plugin) ensureShellAndAttachByIndex(idx int) tea.Cmd {
 	)
 }
 
-// waitForSession waits for a tmux session to become available using exponential backoff.
+// waitForSession waits for a session to become available using exponential backoff.
 // Returns true if session exists, false if max attempts exceeded.
 func waitForSession(sessionName string) bool {
 	const maxAttempts = 10
@@ -765,7 +771,7 @@ func waitForSession(sessionName string) bool {
 	return false
 }
 
-// killShellSessionByName terminates a specific shell tmux session.
+// killShellSessionByName terminates a specific shell session.
 func (p *Plugin) killShellSessionByName(sessionName string) tea.Cmd {
 	if sessionName == "" {
 		return nil
@@ -773,8 +779,7 @@ func (p *Plugin) killShellSessionByName(sessionName string) tea.Cmd {
 
 	return func() tea.Msg {
 		// Kill the session
-		cmd := exec.Command("tmux", "kill-session", "-t", sessionName)
-		_ = cmd.Run() // Ignore errors (session may already be dead)
+		_ = backendFor(sessionName).KillSession(sessionName) // Ignore errors (session may already be dead)
 
 		// Clean up pane cache
 		globalPaneCache.remove(sessionName)
@@ -801,6 +806,13 @@ func (p *Plugin) pollShellSessionByName(tmuxName string) tea.Cmd {
 	// Capture references before spawning closure to avoid data races
 	outputBuf := shell.Agent.OutputBuf
 	maxBytes := p.tmuxCaptureMaxBytes
+
+	// Shells running an agent get status detection for notifications and logs
+	sessionLog := p.sessionLog(tmuxName)
+	var rules *statusRules
+	if shell.ChosenAgent != AgentNone && (p.notifier.Enabled() || sessionLog != nil) {
+		rules = p.statusRulesFor(shell.ChosenAgent)
+	}
 	selectedShell := p.getSelectedShell()
 	interactiveCapture := p.viewMode == ViewModeInteractive &&
 		p.interactiveState != nil &&
@@ -809,12 +821,16 @@ func (p *Plugin) pollShellSessionByName(tmuxName string) tea.Cmd {
 		selectedShell != nil &&
 		selectedShell.TmuxName == tmuxName
 
+	backend := backendFor(tmuxName)
+	_, pushed := backend.(*ptyBackend)
+
 	// When feature is enabled, skip -J for the selected shell so content wraps
 	// at the pane width (matching interactive mode). Resize inline to avoid races.
+	// PTY sessions are always captured this way; they can't be attached at full size.
 	directCapture := false
 	var resizeTarget string
 	var previewWidth, previewHeight int
-	if !interactiveCapture && features.IsEnabled(features.TmuxInteractiveInput.Name) {
+	if !interactiveCapture && (pushed || features.IsEnabled(features.TmuxInteractiveInput.Name)) {
 		if selectedShell != nil && selectedShell.TmuxName == tmuxName {
 			directCapture = true
 			previewWidth, previewHeight = p.calculatePreviewDimensions()
@@ -834,23 +850,23 @@ func (p *Plugin) pollShellSessionByName(tmuxName string) tea.Cmd {
 	return func() tea.Msg {
 		// Ensure pane is at preview width before capturing (avoids race with async resize)
 		if directCapture && resizeTarget != "" {
-			if w, h, ok := queryPaneSize(resizeTarget); !ok || w != previewWidth || h != previewHeight {
-				p.resizeTmuxPane(resizeTarget, previewWidth, previewHeight)
+			if w, h, ok := backend.PaneSize(resizeTarget); !ok || w != previewWidth || h != previewHeight {
+				backend.Resize(resizeTarget, previewWidth, previewHeight)
 			}
 		}
 
 		// Use direct capture for shells (no batch), preserving wraps in interactive mode.
 		// Shell sessions have prefix "sidecar-sh-" not "sidecar-ws-" so batch capture skips them.
 		joinWrapped := !interactiveCapture && !directCapture
-		output, err := capturePaneDirectWithJoin(tmuxName, joinWrapped)
+		output, err := backend.Capture(tmuxName, joinWrapped)
 		if err != nil {
 			// Capture error - check error message to determine if session is dead
 			// Avoid synchronous sessionExists() call which would block (td-c2961e)
 			errStr := err.Error()
 			if strings.Contains(errStr, "can't find") ||
 				strings.Contains(errStr, "no server") ||
-				strings.Contains(errStr, "no such session") ||
-				strings.Contains(errStr, "session not found") {
+				strings.Contains(errStr, "session not found") ||
+				isSessionDeadError(err) {
 				return ShellSessionDeadMsg{TmuxName: tmuxName}
 			}
 			// Other errors (timeout, etc.) - return empty output and schedule retry
@@ -861,7 +877,7 @@ func (p *Plugin) pollShellSessionByName(tmuxName string) tea.Cmd {
 		var cursorRow, cursorCol, paneHeight, paneWidth int
 		var cursorVisible, hasCursor bool
 		if interactiveCapture && cursorTarget != "" {
-			cursorRow, cursorCol, paneHeight, paneWidth, cursorVisible, hasCursor = queryCursorPositionSync(cursorTarget)
+			cursorRow, cursorCol, paneHeight, paneWidth, cursorVisible, hasCursor = backend.Cursor(cursorTarget)
 		}
 
 		// Trim to max bytes
@@ -869,11 +885,22 @@ func (p *Plugin) pollShellSessionByName(tmuxName string) tea.Cmd {
 
 		// Update buffer and check if content changed
 		changed := outputBuf.Update(output)
+		if changed && sessionLog != nil {
+			_ = sessionLog.Snapshot(output)
+		}
+
+		var status WorktreeStatus
+		hasStatus := changed && rules != nil && !interactiveCapture
+		if hasStatus {
+			status = rules.detect(output)
+		}
 
 		return ShellOutputMsg{
 			TmuxName:      tmuxName,
 			Output:        output,
 			Changed:       changed,
+			Status:        status,
+			HasStatus:     hasStatus,
 			CursorRow:     cursorRow,
 			CursorCol:     cursorCol,
 			CursorVisible: cursorVisible,
@@ -937,21 +964,32 @@ func (p *Plugin) createShellWithResume(resumeCmd string) tea.Cmd {
 
 // sendResumeCommandToShell injects a command into the shell without executing it.
 func (p *Plugin) sendResumeCommandToShell(tmuxSession string, resumeCmd string) tea.Cmd {
-	if !isTmuxInstalled() {
-		return nil
-	}
-
 	return func() tea.Msg {
-		// Use tmux send-keys to type the command without pressing Enter
+		// Type the command without pressing Enter
 		// This lets the user review before executing
-		cmd := exec.Command("tmux", "send-keys", "-t", tmuxSession, resumeCmd)
-		if err := cmd.Run(); err != nil {
+		if err := backendFor(tmuxSession).SendKeys(tmuxSession, keySpec{value: resumeCmd}); err != nil {
 			return shellResumeErrorMsg{Err: err}
 		}
 		return shellResumeInjectedMsg{TmuxSession: tmuxSession}
 	}
 }
 
+// runCommandInShell types a command into the shell and presses Enter.
+func (p *Plugin) runCommandInShell(tmuxSession, command string) tea.Cmd {
+	return func() tea.Msg {
+		err := backendFor(tmuxSession).SendKeys(tmuxSession, keySpec{value: command}, keySpec{value: "Enter"})
+		if err != nil {
+			return shellCommandErrorMsg{Err: err}
+		}
+		return nil
+	}
+}
+
+// shellCommandErrorMsg signals an error running a custom command in a shell.
+type shellCommandErrorMsg struct {
+	Err error
+}
+
 // shellResumeInjectedMsg signals that resume command was injected into shell.
 type shellResumeInjectedMsg struct {
 	TmuxSession string
@@ -987,7 +1025,7 @@ func (p *Plugin) createWorktreeWithResume(msg ResumeConversationMsg) tea.Cmd {
 
 	return func() tea.Msg {
 		// Create the worktree (reuse doCreateWorktree)
-		wt, err := p.doCreateWorktree(name, baseBranch, "", "", agentType)
+		wt, _, err := p.doCreateWorktree(name, baseBranch, "", "", agentType)
 		if err != nil {
 			return worktreeResumeCreatedMsg{Err: err}
 		}
@@ -1004,74 +1042,10 @@ func (p *Plugin) createWorktreeWithResume(msg ResumeConversationMsg) tea.Cmd {
 // startAgentWithResumeCmd starts an agent in a worktree with a resume command instead of normal startup.
 func (p *Plugin) startAgentWithResumeCmd(wt *Worktree, agentType AgentType, skipPerms bool, resumeCmd string) tea.Cmd {
 	epoch := p.ctx.Epoch // Capture epoch for stale detection
+	backend := p.agentBackend()
 	return func() tea.Msg {
-		sessionName := tmuxSessionPrefix + sanitizeName(wt.Name)
-
-		// Check if session already exists
-		checkCmd := exec.Command("tmux", "has-session", "-t", sessionName)
-		if checkCmd.Run() == nil {
-			// Session exists - should not happen for new resume worktree
-			paneID := getPaneID(sessionName)
-			return AgentStartedMsg{
-				Epoch:         epoch,
-				WorkspaceName: wt.Name,
-				SessionName:   sessionName,
-				PaneID:        paneID,
-				AgentType:     agentType,
-				Reconnected:   true,
-			}
-		}
-
-		// Create new detached session with working directory
-		args := []string{
-			"new-session",
-			"-d",              // Detached
-			"-s", sessionName, // Session name
-			"-c", wt.Path, // Working directory
-		}
-
-		cmd := exec.Command("tmux", args...)
-		if err := cmd.Run(); err != nil {
-			return AgentStartedMsg{Epoch: epoch, Err: fmt.Errorf("create session: %w", err)}
-		}
-
-		// Set history limit for scrollback capture
-		_ = exec.Command("tmux", "set-option", "-t", sessionName, "history-limit",
-			strconv.Itoa(tmuxHistoryLimit)).Run()
-
-		// Detect shell type for correct syntax (fish vs posix)
-		shellType := DetectShell()
-
-		// Set TD_SESSION_ID environment variable for td session tracking
-		tdEnvCmd := GenerateExportCommand("TD_SESSION_ID", sessionName, shellType)
-		_ = exec.Command("tmux", "send-keys", "-t", sessionName, tdEnvCmd, "Enter").Run()
-
-		// Apply environment isolation
-		envOverrides := BuildEnvOverrides(p.ctx.WorkDir)
-		if envCmd := GenerateSingleEnvCommand(envOverrides, shellType); envCmd != "" {
-			_ = exec.Command("tmux", "send-keys", "-t", sessionName, envCmd, "Enter").Run()
-		}
-
-		// Small delay to ensure env is set
-		time.Sleep(100 * time.Millisecond)
-
-		// Send the resume command instead of the normal agent command
-		sendCmd := exec.Command("tmux", "send-keys", "-t", sessionName, resumeCmd, "Enter")
-		if err := sendCmd.Run(); err != nil {
-			// Try to kill the session if we failed to start the agent
-			_ = exec.Command("tmux", "kill-session", "-t", sessionName).Run()
-			return AgentStartedMsg{Epoch: epoch, Err: fmt.Errorf("start agent with resume: %w", err)}
-		}
-
-		// Capture pane ID for interactive mode support
-		paneID := getPaneID(sessionName)
-
-		return AgentStartedMsg{
-			Epoch:         epoch,
-			WorkspaceName: wt.Name,
-			SessionName:   sessionName,
-			PaneID:        paneID,
-			AgentType:     agentType,
-		}
+		msg := p.startAgentSession(backend, wt, agentType, resumeCmd)
+		msg.Epoch = epoch
+		return msg
 	}
 }
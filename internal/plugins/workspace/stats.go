@@ -14,7 +14,7 @@ func (p *Plugin) loadStats(path string) tea.Cmd {
 	epoch := p.ctx.Epoch // Capture epoch for stale detection
 	return func() tea.Msg {
 		name := filepath.Base(path)
-		stats, err := computeStats(path)
+		stats, err := ComputeStats(path)
 		if err != nil {
 			return StatsErrorMsg{WorkspaceName: name, Err: err}
 		}
@@ -22,8 +22,8 @@ func (p *Plugin) loadStats(path string) tea.Cmd {
 	}
 }
 
-// computeStats calculates git stats for a worktree.
-func computeStats(workdir string) (*GitStats, error) {
+// ComputeStats calculates git stats for a worktree.
+func ComputeStats(workdir string) (*GitStats, error) {
 	stats := &GitStats{}
 
 	// Get diff stats (uncommitted changes)
@@ -0,0 +1,422 @@
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/marcus/sidecar/internal/config"
+)
+
+const (
+	defaultStatusTailLines   = 12
+	defaultStatusPromptLines = 3
+
+	// statusDefaultKey is the config key for agents without built-in rules.
+	statusDefaultKey = "default"
+)
+
+// Shared rule fragments.
+const (
+	yesNoPattern    = `(?i)\[y/n\]|\(y/n\)`
+	exitCodePattern = `(?i)\bexited with (?:code|status) (\d+)`
+)
+
+// defaultStatusRuleSpec applies to agents without their own rules (custom
+// commands, shells). Phrases are anchored to the start of a line so that
+// prose like "the build failed earlier" doesn't change the status.
+var defaultStatusRuleSpec = config.StatusRulesConfig{
+	Waiting: []string{
+		yesNoPattern,
+		`(?i)\ballow (?:edit|bash)\b`,
+		`(?i)\bpress enter\b`,
+		`(?i)\b(?:continue|proceed)\?`,
+		`(?i)\bdo you want\b`,
+		`(?i)^(?:please )?(?:approve|confirm)\b`,
+	},
+	Prompt: []string{
+		`^(?:╰─)?❯$`,
+	},
+	Thinking: []string{
+		`(?i)\bthinking\.\.\.`,
+		`(?i)\breasoning about\b`,
+	},
+	Done: []string{
+		`(?i)^(?:task completed|all done|finished)\b`,
+		`(?i)^goodbye\b`,
+	},
+	Error: []string{
+		`(?i)^(?:error|fatal|panic|exception):`,
+		`(?i)^traceback \(most recent call last\)`,
+	},
+	ExitCode: exitCodePattern,
+}
+
+// builtinStatusRuleSpecs holds the rules for agents whose terminal UI we know.
+var builtinStatusRuleSpecs = map[AgentType]config.StatusRulesConfig{
+	AgentClaude: {
+		PromptLines: 6, // input box is followed by a border and a shortcuts hint
+		Waiting: []string{
+			yesNoPattern,
+			`(?i)\bdo you want to\b`,
+			`(?i)^│?\s*❯\s*1\.\s*yes\b`,
+		},
+		Prompt: []string{
+			`^(?:╰─)?❯$`,
+			`^│\s*>(?:\s|$)`,
+		},
+		Thinking: []string{
+			`(?i)\besc to interrupt\b`,
+			`^[✻✽✶✳✢·*]\s+\S+…`,
+		},
+		Error: []string{
+			`(?i)^⎿?\s*API Error\b`,
+		},
+		ExitCode: exitCodePattern,
+	},
+	AgentCodex: {
+		Waiting: []string{
+			yesNoPattern,
+			`(?i)\ballow command\?`,
+			`(?i)\bwould you like to (?:run|make|apply)\b`,
+			`(?i)^›\s*1\.\s*yes\b`,
+		},
+		Prompt: []string{
+			`^›$`,
+		},
+		Thinking: []string{
+			`(?i)\besc to interrupt\b`,
+		},
+		Error: []string{
+			`^■\s`,
+			`(?i)^error:`,
+		},
+		ExitCode: exitCodePattern,
+	},
+	AgentAider: {
+		PromptLines: 1,
+		Waiting: []string{
+			yesNoPattern,
+			`(?i)\(y\)es/\(n\)o`,
+		},
+		Prompt: []string{
+			`^[\w-]*>$`, // "> ", "architect> ", "diff-fenced> "
+		},
+		Thinking: []string{
+			// Aider's spinner: "Waiting for gpt-4o" is the model working, not the user
+			`(?i)\bwaiting for \S+`,
+			`(?i)\breasoning about\b`,
+		},
+		Error: []string{
+			`(?i)^litellm\.\w+:`,
+			`(?i)^traceback \(most recent call last\)`,
+			`(?i)^error:`,
+		},
+		ExitCode: exitCodePattern,
+	},
+	AgentGemini: {
+		PromptLines: 4,
+		Waiting: []string{
+			yesNoPattern,
+			`(?i)\ballow execution\b`,
+			`(?i)\bapply this change\?`,
+			`(?i)\bwaiting for user confirmation\b`,
+		},
+		Prompt: []string{
+			`(?i)^│?\s*>\s+type your message`,
+		},
+		Thinking: []string{
+			`(?i)\(esc to cancel\b`,
+		},
+		Error: []string{
+			`^✕\s`,
+			`(?i)^error:`,
+		},
+		ExitCode: exitCodePattern,
+	},
+	AgentCursor: {
+		PromptLines: 4,
+		Waiting: []string{
+			yesNoPattern,
+			`(?i)\brun (?:this )?command\?`,
+			`(?i)\bdo you want to\b`,
+		},
+		Prompt: []string{
+			`(?i)\badd a follow-up\b`,
+		},
+		Thinking: []string{
+			`(?i)\bctrl\+c to stop\b`,
+		},
+		Error: []string{
+			`(?i)^error:`,
+		},
+		ExitCode: exitCodePattern,
+	},
+	AgentOpenCode: {
+		Waiting: []string{
+			yesNoPattern,
+			`(?i)\bpermission required\b`,
+			`(?i)\ballow (?:once|always)\b`,
+		},
+		Thinking: []string{
+			`(?i)\besc\s+interrupt\b`,
+		},
+		Error: []string{
+			`(?i)^error:`,
+		},
+		ExitCode: exitCodePattern,
+	},
+}
+
+var (
+	defaultStatusRules = mustCompileStatusRules(defaultStatusRuleSpec)
+	builtinStatusRules = compileBuiltinStatusRules()
+	thinkingTags       = []struct{ open, close string }{
+		{"<thinking>", "</thinking>"},
+		{"<internal_monologue>", "</internal_monologue>"},
+	}
+)
+
+// statusRules is a compiled set of status detection rules for one agent type.
+type statusRules struct {
+	tailLines   int
+	promptLines int
+	waiting     []*regexp.Regexp
+	prompt      []*regexp.Regexp
+	thinking    []*regexp.Regexp
+	done        []*regexp.Regexp
+	errors      []*regexp.Regexp
+	exitCode    *regexp.Regexp
+}
+
+// compileStatusRules compiles a rule spec, collecting every invalid pattern.
+func compileStatusRules(spec config.StatusRulesConfig) (*statusRules, error) {
+	r := &statusRules{
+		tailLines:   spec.TailLines,
+		promptLines: spec.PromptLines,
+	}
+	if r.tailLines <= 0 {
+		r.tailLines = defaultStatusTailLines
+	}
+	if r.promptLines <= 0 {
+		r.promptLines = defaultStatusPromptLines
+	}
+
+	var errs []error
+	compile := func(kind string, patterns []string) []*regexp.Regexp {
+		res := make([]*regexp.Regexp, 0, len(patterns))
+		for _, pat := range patterns {
+			re, err := regexp.Compile(pat)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s pattern %q: %w", kind, pat, err))
+				continue
+			}
+			res = append(res, re)
+		}
+		return res
+	}
+	r.waiting = compile("waiting", spec.Waiting)
+	r.prompt = compile("prompt", spec.Prompt)
+	r.thinking = compile("thinking", spec.Thinking)
+	r.done = compile("done", spec.Done)
+	r.errors = compile("error", spec.Error)
+	if spec.ExitCode != "" {
+		if res := compile("exitCode", []string{spec.ExitCode}); len(res) == 1 {
+			r.exitCode = res[0]
+		}
+	}
+	return r, errors.Join(errs...)
+}
+
+func mustCompileStatusRules(spec config.StatusRulesConfig) *statusRules {
+	r, err := compileStatusRules(spec)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+func compileBuiltinStatusRules() map[AgentType]*statusRules {
+	rules := make(map[AgentType]*statusRules, len(builtinStatusRuleSpecs))
+	for agentType, spec := range builtinStatusRuleSpecs {
+		rules[agentType] = mustCompileStatusRules(spec)
+	}
+	return rules
+}
+
+// mergeStatusRuleSpec applies a config override on top of a rule spec. Lists
+// that are set replace the base list rather than extending it.
+func mergeStatusRuleSpec(base, override config.StatusRulesConfig) config.StatusRulesConfig {
+	if override.TailLines > 0 {
+		base.TailLines = override.TailLines
+	}
+	if override.PromptLines > 0 {
+		base.PromptLines = override.PromptLines
+	}
+	if override.Waiting != nil {
+		base.Waiting = override.Waiting
+	}
+	if override.Prompt != nil {
+		base.Prompt = override.Prompt
+	}
+	if override.Thinking != nil {
+		base.Thinking = override.Thinking
+	}
+	if override.Done != nil {
+		base.Done = override.Done
+	}
+	if override.Error != nil {
+		base.Error = override.Error
+	}
+	if override.ExitCode != "" {
+		base.ExitCode = override.ExitCode
+	}
+	return base
+}
+
+// loadStatusRules compiles the configured overrides, keyed by agent type.
+// Agents with invalid overrides keep their built-in rules.
+func loadStatusRules(overrides map[string]config.StatusRulesConfig) (map[AgentType]*statusRules, error) {
+	rules := make(map[AgentType]*statusRules, len(overrides))
+	var errs []error
+	for key, override := range overrides {
+		agentType := AgentType(key)
+		if key == statusDefaultKey {
+			agentType = AgentNone
+		}
+		base, ok := builtinStatusRuleSpecs[agentType]
+		if !ok {
+			base = defaultStatusRuleSpec
+		}
+		r, err := compileStatusRules(mergeStatusRuleSpec(base, override))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("statusRules.%s: %w", key, err))
+			continue
+		}
+		rules[agentType] = r
+	}
+	return rules, errors.Join(errs...)
+}
+
+// statusRulesFor returns the detection rules for an agent type: the config
+// override if any, then the built-in rules, then the default override or
+// built-in defaults.
+func (p *Plugin) statusRulesFor(agentType AgentType) *statusRules {
+	if r, ok := p.statusRules[agentType]; ok {
+		return r
+	}
+	if r, ok := builtinStatusRules[agentType]; ok {
+		return r
+	}
+	if r, ok := p.statusRules[AgentNone]; ok {
+		return r
+	}
+	return defaultStatusRules
+}
+
+// detectStatus determines agent status from captured output using the
+// default rules.
+func detectStatus(output string) WorktreeStatus {
+	return defaultStatusRules.detect(output)
+}
+
+// detect determines agent status from the last lines of captured output.
+// Priority: explicit questions, then work in progress, then an idle prompt,
+// then exit codes, completion and error messages.
+func (r *statusRules) detect(output string) WorktreeStatus {
+	lines := tailLines(output, r.tailLines)
+	if len(lines) == 0 {
+		return StatusActive
+	}
+
+	if matchAnyLine(r.waiting, lines) {
+		return StatusWaiting
+	}
+	if hasOpenThinkingTag(lines) || matchAnyLine(r.thinking, lines) {
+		return StatusThinking
+	}
+	if matchAnyLine(r.prompt, lines[max(len(lines)-r.promptLines, 0):]) {
+		return StatusWaiting
+	}
+	if code, ok := r.lastExitCode(lines); ok {
+		if code == 0 {
+			return StatusDone
+		}
+		return StatusError
+	}
+	if matchAnyLine(r.done, lines) {
+		return StatusDone
+	}
+	if matchAnyLine(r.errors, lines) {
+		return StatusError
+	}
+	return StatusActive
+}
+
+// lastExitCode returns the most recent exit code reported in lines.
+func (r *statusRules) lastExitCode(lines []string) (int, bool) {
+	if r.exitCode == nil {
+		return 0, false
+	}
+	for i := len(lines) - 1; i >= 0; i-- {
+		m := r.exitCode.FindStringSubmatch(lines[i])
+		if len(m) < 2 {
+			continue
+		}
+		if code, err := strconv.Atoi(m[1]); err == nil {
+			return code, true
+		}
+	}
+	return 0, false
+}
+
+// tailLines returns up to n trailing non-blank lines of output, oldest
+// first, with ANSI codes and surrounding whitespace removed.
+func tailLines(output string, n int) []string {
+	// Panes are often padded with blank lines, so allow a generous window
+	text := tailUTF8Safe(output, max(statusCheckBytes, n*256))
+	lines := make([]string, 0, n)
+	for len(lines) < n && text != "" {
+		var line string
+		if i := strings.LastIndexByte(text, '\n'); i >= 0 {
+			line, text = text[i+1:], text[:i]
+		} else {
+			line, text = text, ""
+		}
+		line = strings.TrimSpace(ansi.Strip(line))
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return lines
+}
+
+func matchAnyLine(patterns []*regexp.Regexp, lines []string) bool {
+	for _, re := range patterns {
+		for _, line := range lines {
+			if re.MatchString(line) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasOpenThinkingTag reports whether an extended-thinking tag was opened and
+// not yet closed.
+func hasOpenThinkingTag(lines []string) bool {
+	text := strings.ToLower(strings.Join(lines, "\n"))
+	for _, tag := range thinkingTags {
+		openIdx := strings.LastIndex(text, tag.open)
+		if openIdx >= 0 && strings.LastIndex(text, tag.close) < openIdx {
+			return true
+		}
+	}
+	return false
+}
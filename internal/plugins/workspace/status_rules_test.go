@@ -0,0 +1,214 @@
+package workspace
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/marcus/sidecar/internal/adapter"
+	"github.com/marcus/sidecar/internal/config"
+	"github.com/marcus/sidecar/internal/plugin"
+)
+
+func TestStatusRules_PerAgent(t *testing.T) {
+	tests := []struct {
+		name      string
+		agentType AgentType
+		output    string
+		expected  WorktreeStatus
+	}{
+		{
+			name:      "claude spinner above input box",
+			agentType: AgentClaude,
+			output:    "⏺ Reading files\n✻ Pondering… (12s · esc to interrupt)\n╭────╮\n│ >  │\n╰────╯\n  ? for shortcuts",
+			expected:  StatusThinking,
+		},
+		{
+			name:      "claude idle input box",
+			agentType: AgentClaude,
+			output:    "⏺ Done. All tests pass.\n╭────╮\n│ >  │\n╰────╯\n  ? for shortcuts",
+			expected:  StatusWaiting,
+		},
+		{
+			name:      "claude permission menu",
+			agentType: AgentClaude,
+			output:    "Do you want to make this edit to main.go?\n❯ 1. Yes\n  2. No",
+			expected:  StatusWaiting,
+		},
+		{
+			name:      "claude mentions failure in prose",
+			agentType: AgentClaude,
+			output:    "⏺ The test failed because of a typo. Error: is printed on stderr.",
+			expected:  StatusActive,
+		},
+		{
+			name:      "aider waiting for model is working",
+			agentType: AgentAider,
+			output:    "> add retries\n░█  Waiting for gpt-4o",
+			expected:  StatusThinking,
+		},
+		{
+			name:      "aider idle prompt",
+			agentType: AgentAider,
+			output:    "Applied edit to main.go\narchitect> ",
+			expected:  StatusWaiting,
+		},
+		{
+			name:      "aider confirmation",
+			agentType: AgentAider,
+			output:    "Add file to the chat? (Y)es/(N)o [Yes]:",
+			expected:  StatusWaiting,
+		},
+		{
+			name:      "codex error marker",
+			agentType: AgentCodex,
+			output:    "■ stream disconnected before completion",
+			expected:  StatusError,
+		},
+		{
+			name:      "old error scrolled out of the tail",
+			agentType: AgentCodex,
+			output:    "Error: first attempt\n1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12",
+			expected:  StatusActive,
+		},
+		{
+			name:      "ansi codes are stripped before anchoring",
+			agentType: AgentGemini,
+			output:    "\x1b[31m✕ \x1b[0mRequest failed",
+			expected:  StatusError,
+		},
+		{
+			name:      "unknown agent uses default rules",
+			agentType: AgentCustom,
+			output:    "Error: missing config",
+			expected:  StatusError,
+		},
+	}
+
+	p := &Plugin{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.statusRulesFor(tt.agentType).detect(tt.output); got != tt.expected {
+				t.Errorf("detect(%q) = %v, want %v", tt.output, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLoadStatusRules(t *testing.T) {
+	rules, err := loadStatusRules(map[string]config.StatusRulesConfig{
+		"claude":  {Error: []string{}, Done: []string{`^Session ended$`}},
+		"default": {Waiting: []string{`^Ready>`}},
+		"codex":   {Waiting: []string{`([`}},
+	})
+	if err == nil {
+		t.Error("expected error for invalid codex pattern")
+	}
+	if _, ok := rules[AgentCodex]; ok {
+		t.Error("invalid override should keep built-in rules")
+	}
+
+	p := &Plugin{statusRules: rules}
+	claude := p.statusRulesFor(AgentClaude)
+	if got := claude.detect("API Error: overloaded"); got != StatusActive {
+		t.Errorf("empty error list should disable errors, got %v", got)
+	}
+	if got := claude.detect("Session ended"); got != StatusDone {
+		t.Errorf("expected done from override, got %v", got)
+	}
+	if got := claude.detect("Do you want to proceed?"); got != StatusWaiting {
+		t.Errorf("unset lists should keep built-in rules, got %v", got)
+	}
+	if got := p.statusRulesFor(AgentShell).detect("Ready> "); got != StatusWaiting {
+		t.Errorf("default override should apply to agents without rules, got %v", got)
+	}
+	if got := p.statusRulesFor(AgentCodex).detect("Allow command? [y/n]"); got != StatusWaiting {
+		t.Errorf("expected built-in codex rules, got %v", got)
+	}
+}
+
+type statusTestAdapter struct {
+	sessions []adapter.Session
+	messages []adapter.Message
+}
+
+func (a *statusTestAdapter) ID() string                                { return "status-test" }
+func (a *statusTestAdapter) Name() string                              { return "Status Test" }
+func (a *statusTestAdapter) Icon() string                              { return "" }
+func (a *statusTestAdapter) Detect(string) (bool, error)               { return true, nil }
+func (a *statusTestAdapter) Capabilities() adapter.CapabilitySet       { return nil }
+func (a *statusTestAdapter) Usage(string) (*adapter.UsageStats, error) { return nil, nil }
+func (a *statusTestAdapter) Sessions(string) ([]adapter.Session, error) {
+	return a.sessions, nil
+}
+func (a *statusTestAdapter) Messages(string) ([]adapter.Message, error) {
+	return a.messages, nil
+}
+func (a *statusTestAdapter) Watch(string) (<-chan adapter.Event, io.Closer, error) {
+	return nil, nil, nil
+}
+
+func TestAdapterSessionStatus(t *testing.T) {
+	now := time.Now()
+	active := []adapter.Session{{ID: "s1", UpdatedAt: now, IsActive: true}}
+	tests := []struct {
+		name     string
+		sessions []adapter.Session
+		messages []adapter.Message
+		expected WorktreeStatus
+		ok       bool
+	}{
+		{
+			name:     "assistant replied",
+			sessions: active,
+			messages: []adapter.Message{{Role: "user"}, {Role: "assistant"}},
+			expected: StatusWaiting,
+			ok:       true,
+		},
+		{
+			name:     "user message pending",
+			sessions: active,
+			messages: []adapter.Message{{Role: "assistant"}, {Role: "user"}},
+			expected: StatusActive,
+			ok:       true,
+		},
+		{
+			name:     "tool call running",
+			sessions: active,
+			messages: []adapter.Message{{Role: "assistant", ToolUses: []adapter.ToolUse{{Name: "Bash"}}}},
+			expected: StatusActive,
+			ok:       true,
+		},
+		{
+			name:     "inactive session",
+			sessions: []adapter.Session{{ID: "s1", UpdatedAt: now.Add(-time.Hour)}},
+			messages: []adapter.Message{{Role: "assistant"}},
+		},
+		{
+			name: "no sessions",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &statusTestAdapter{sessions: tt.sessions, messages: tt.messages}
+			status, ok := adapterSessionStatus(a, t.TempDir())
+			if ok != tt.ok || (ok && status != tt.expected) {
+				t.Errorf("adapterSessionStatus() = (%v, %v), want (%v, %v)", status, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestAgentAdapter(t *testing.T) {
+	a := &statusTestAdapter{}
+	p := &Plugin{ctx: &plugin.Context{Adapters: map[string]adapter.Adapter{"aider": a}}}
+	if p.agentAdapter(AgentAider) != a {
+		t.Error("expected aider adapter")
+	}
+	if p.agentAdapter(AgentClaude) != nil {
+		t.Error("expected no adapter for undetected agent")
+	}
+	if p.agentAdapter(AgentCustom) != nil {
+		t.Error("expected no adapter for custom agent")
+	}
+}
@@ -1,29 +1,235 @@
 package workspace
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
 )
 
-// ticketPattern matches {{ticket}} or {{ticket || 'fallback text'}}
-var ticketPattern = regexp.MustCompile(`\{\{ticket(?:\s*\|\|\s*'([^']*)')?\}\}`)
+// Prompt bodies are Go text/templates. Variables are exposed as functions so
+// they read like placeholders ({{branch}}), work in conditionals
+// ({{if ticket}}...{{end}}) and make unknown names a parse error. Shared
+// snippets from .sidecar/prompts/ are included with {{template "name"}}.
 
-// ExpandPromptTemplate expands template variables in a prompt body.
-// - {{ticket}} expands to taskID (returns empty if taskID is empty)
-// - {{ticket || 'default'}} expands to taskID, or 'default' if taskID is empty
-func ExpandPromptTemplate(body, taskID string) string {
-	return ticketPattern.ReplaceAllStringFunc(body, func(match string) string {
-		submatch := ticketPattern.FindStringSubmatch(match)
+// promptVariables lists the variables available in prompt bodies.
+var promptVariables = []string{
+	"baseBranch",     // branch the worktree was created from
+	"branch",         // worktree branch
+	"changedFiles",   // files changed vs base, one per line
+	"diff",           // diff vs base, including uncommitted changes
+	"taskAcceptance", // td task acceptance criteria
+	"taskBody",       // td task description
+	"taskTitle",      // td task title
+	"ticket",         // td task ID
+	"worktree",       // worktree name
+	"worktreePath",   // absolute worktree path
+}
+
+// promptSnippetsDir is the project directory holding shared prompt snippets.
+const promptSnippetsDir = "prompts"
 
-		if taskID != "" {
-			return taskID
+// fallbackSyntax matches the legacy {{name || 'fallback'}} form.
+var fallbackSyntax = regexp.MustCompile(`\{\{\s*([A-Za-z]+)\s*\|\|\s*'([^']*)'\s*\}\}`)
+
+// PromptVars supplies values for prompt variables. Values that need td or
+// git are loaded on first use, so prompts only pay for what they reference.
+type PromptVars struct {
+	Ticket       string
+	Branch       string
+	BaseBranch   string
+	Worktree     string
+	WorktreePath string
+
+	LoadTask func() *TaskDetails                  // nil or nil result leaves task fields empty
+	LoadDiff func() (files []string, diff string) // changes vs BaseBranch
+}
+
+// funcs returns the template functions bound to these values.
+func (v PromptVars) funcs() template.FuncMap {
+	var task *TaskDetails
+	taskLoaded := false
+	loadTask := func() *TaskDetails {
+		if !taskLoaded {
+			taskLoaded = true
+			if v.LoadTask != nil {
+				task = v.LoadTask()
+			}
+		}
+		return task
+	}
+	var files []string
+	var diff string
+	diffLoaded := false
+	loadDiff := func() {
+		if !diffLoaded {
+			diffLoaded = true
+			if v.LoadDiff != nil {
+				files, diff = v.LoadDiff()
+			}
 		}
+	}
+	taskField := func(get func(*TaskDetails) string) func() string {
+		return func() string {
+			if t := loadTask(); t != nil {
+				return get(t)
+			}
+			return ""
+		}
+	}
+
+	return template.FuncMap{
+		"baseBranch": func() string { return v.BaseBranch },
+		"branch":     func() string { return v.Branch },
+		"changedFiles": func() string {
+			loadDiff()
+			return strings.Join(files, "\n")
+		},
+		"diff": func() string {
+			loadDiff()
+			return diff
+		},
+		"taskAcceptance": taskField(func(t *TaskDetails) string { return t.Acceptance }),
+		"taskBody":       taskField(func(t *TaskDetails) string { return t.Description }),
+		"taskTitle":      taskField(func(t *TaskDetails) string { return t.Title }),
+		"ticket":         func() string { return v.Ticket },
+		"worktree":       func() string { return v.Worktree },
+		"worktreePath":   func() string { return v.WorktreePath },
+	}
+}
 
-		// Check for fallback value
-		if len(submatch) > 1 && submatch[1] != "" {
-			return submatch[1]
+// parsePromptTemplate parses a prompt body together with the shared
+// snippets. It fails on unknown variables, syntax errors and references to
+// snippets that do not exist.
+func parsePromptTemplate(body string, snippets map[string]string) (*template.Template, error) {
+	t := template.New("prompt").Funcs(PromptVars{}.funcs())
+
+	snippetNames := make([]string, 0, len(snippets))
+	for n := range snippets {
+		snippetNames = append(snippetNames, n)
+	}
+	sort.Strings(snippetNames)
+	for _, n := range snippetNames {
+		if _, err := t.New(n).Parse(convertFallbacks(snippets[n])); err != nil {
+			return nil, fmt.Errorf("snippet %s: %w", n, err)
+		}
+	}
+	if _, err := t.Parse(convertFallbacks(body)); err != nil {
+		if strings.Contains(err.Error(), "not defined") {
+			return nil, fmt.Errorf("%w (variables: %s)", err, strings.Join(promptVariables, ", "))
 		}
+		return nil, err
+	}
+	for _, tmpl := range t.Templates() {
+		if tmpl.Tree == nil {
+			continue
+		}
+		if err := checkTemplateRefs(tmpl.Tree.Root, t); err != nil {
+			return nil, fmt.Errorf("%s: %w", tmpl.Name(), err)
+		}
+	}
+	return t, nil
+}
 
-		// No fallback, return empty
-		return ""
+// convertFallbacks rewrites {{name || 'fallback'}} to {{or name "fallback"}}.
+func convertFallbacks(body string) string {
+	return fallbackSyntax.ReplaceAllStringFunc(body, func(match string) string {
+		m := fallbackSyntax.FindStringSubmatch(match)
+		return "{{or " + m[1] + " " + strconv.Quote(m[2]) + "}}"
 	})
 }
+
+// checkTemplateRefs reports {{template}} actions naming unknown snippets.
+func checkTemplateRefs(node parse.Node, t *template.Template) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := checkTemplateRefs(child, t); err != nil {
+				return err
+			}
+		}
+	case *parse.TemplateNode:
+		if t.Lookup(n.Name) == nil {
+			return fmt.Errorf("unknown snippet %q", n.Name)
+		}
+	case *parse.IfNode:
+		return checkBranchRefs(&n.BranchNode, t)
+	case *parse.RangeNode:
+		return checkBranchRefs(&n.BranchNode, t)
+	case *parse.WithNode:
+		return checkBranchRefs(&n.BranchNode, t)
+	}
+	return nil
+}
+
+func checkBranchRefs(n *parse.BranchNode, t *template.Template) error {
+	if err := checkTemplateRefs(n.List, t); err != nil {
+		return err
+	}
+	if n.ElseList != nil {
+		return checkTemplateRefs(n.ElseList, t)
+	}
+	return nil
+}
+
+// loadPromptSnippets reads shared snippets from dir. Snippets are named after
+// their file name without extension.
+func loadPromptSnippets(dir string) map[string]string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	snippets := make(map[string]string)
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		name := strings.TrimSuffix(e.Name(), filepath.Ext(e.Name()))
+		snippets[name] = string(data)
+	}
+	return snippets
+}
+
+// RenderPrompt expands a prompt body with the given variables.
+func RenderPrompt(prompt *Prompt, vars PromptVars) (string, error) {
+	t := prompt.tmpl
+	if t == nil {
+		var err error
+		if t, err = parsePromptTemplate(prompt.Body, nil); err != nil {
+			return "", err
+		}
+	}
+	t, err := t.Clone()
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	if err := t.Funcs(vars.funcs()).Execute(&sb, nil); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// ExpandPromptTemplate expands a prompt body with only the ticket known.
+// - {{ticket}} expands to taskID (returns empty if taskID is empty)
+// - {{ticket || 'default'}} expands to taskID, or 'default' if taskID is empty
+// A body that fails to parse is returned unchanged.
+func ExpandPromptTemplate(body, taskID string) string {
+	out, err := RenderPrompt(&Prompt{Body: body}, PromptVars{Ticket: taskID})
+	if err != nil {
+		return body
+	}
+	return out
+}
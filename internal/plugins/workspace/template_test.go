@@ -0,0 +1,124 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderPrompt(t *testing.T) {
+	diffLoads := 0
+	vars := PromptVars{
+		Ticket:       "td-42",
+		Branch:       "auth-fix",
+		BaseBranch:   "main",
+		Worktree:     "auth-fix",
+		WorktreePath: "/repo-auth-fix",
+		LoadTask: func() *TaskDetails {
+			return &TaskDetails{Title: "Fix login", Description: "Users get logged out", Acceptance: "- stays logged in"}
+		},
+		LoadDiff: func() ([]string, string) {
+			diffLoads++
+			return []string{"auth/login.go", "auth/session.go"}, "diff --git a/auth/login.go"
+		},
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{
+			name:     "git variables",
+			body:     "On {{branch}} from {{baseBranch}} in {{worktreePath}}",
+			expected: "On auth-fix from main in /repo-auth-fix",
+		},
+		{
+			name:     "task variables",
+			body:     "{{ticket}}: {{taskTitle}}\n{{taskBody}}\n{{taskAcceptance}}",
+			expected: "td-42: Fix login\nUsers get logged out\n- stays logged in",
+		},
+		{
+			name:     "conditional",
+			body:     "{{if ticket}}Work on {{ticket}}{{else}}Pick a task{{end}}",
+			expected: "Work on td-42",
+		},
+		{
+			name:     "changed files are loaded once",
+			body:     "{{changedFiles}}\n{{if diff}}has diff{{end}}",
+			expected: "auth/login.go\nauth/session.go\nhas diff",
+		},
+		{
+			name:     "legacy fallback on any variable",
+			body:     "{{taskTitle || 'untitled'}}",
+			expected: "Fix login",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diffLoads = 0
+			got, err := RenderPrompt(&Prompt{Body: tt.body}, vars)
+			if err != nil {
+				t.Fatalf("RenderPrompt() error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("RenderPrompt() = %q, want %q", got, tt.expected)
+			}
+			if diffLoads > 1 {
+				t.Errorf("diff loaded %d times", diffLoads)
+			}
+		})
+	}
+
+	if _, err := RenderPrompt(&Prompt{Body: "{{branch}}"}, PromptVars{LoadDiff: vars.LoadDiff}); err != nil || diffLoads != 0 {
+		t.Errorf("diff should only load when referenced (err %v, loads %d)", err, diffLoads)
+	}
+}
+
+func TestLoadPrompts_Templates(t *testing.T) {
+	globalDir := t.TempDir()
+	projectDir := t.TempDir()
+	snippetDir := filepath.Join(projectDir, ".sidecar", "prompts")
+	if err := os.MkdirAll(snippetDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(snippetDir, "kickoff.md"), []byte("Branch {{branch}}. Run the tests first."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config := `{
+  "prompts": [
+    {"name": "kickoff", "body": "{{template \"kickoff\"}} Then start {{ticket}}."},
+    {"name": "typo", "body": "Start {{tiket}}"},
+    {"name": "missing-snippet", "body": "{{template \"review\"}}"},
+    {"name": "bad-syntax", "body": "{{if ticket}}unterminated"}
+  ]
+}`
+	if err := os.WriteFile(filepath.Join(projectDir, ".sidecar", "config.json"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	prompts := make(map[string]Prompt)
+	for _, p := range LoadPrompts(globalDir, projectDir) {
+		prompts[p.Name] = p
+	}
+
+	kickoff := prompts["kickoff"]
+	if kickoff.Err != nil {
+		t.Fatalf("kickoff prompt should be valid: %v", kickoff.Err)
+	}
+	got, err := RenderPrompt(&kickoff, PromptVars{Ticket: "td-1", Branch: "feat"})
+	if err != nil || got != "Branch feat. Run the tests first. Then start td-1." {
+		t.Errorf("RenderPrompt(kickoff) = %q, %v", got, err)
+	}
+
+	if err := prompts["typo"].Err; err == nil || !strings.Contains(err.Error(), `"tiket" not defined`) {
+		t.Errorf("expected unknown variable error, got %v", err)
+	}
+	if err := prompts["missing-snippet"].Err; err == nil || !strings.Contains(err.Error(), `unknown snippet "review"`) {
+		t.Errorf("expected unknown snippet error, got %v", err)
+	}
+	if prompts["bad-syntax"].Err == nil {
+		t.Error("expected syntax error")
+	}
+}
@@ -0,0 +1,345 @@
+package workspace
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/marcus/sidecar/internal/adapter"
+	"github.com/marcus/sidecar/internal/styles"
+)
+
+// transcriptReloadInterval limits reloads driven by agent polls for adapters
+// that cannot watch their sessions.
+const transcriptReloadInterval = 2 * time.Second
+
+// TranscriptRenderer renders agent session messages for the Transcript tab.
+// The conversations plugin provides the implementation; it is injected by the
+// caller because that package already imports this one.
+type TranscriptRenderer interface {
+	Render(messages []adapter.Message, agentName string, width int) []string
+}
+
+// SetTranscriptRenderer sets the renderer used by the Transcript tab.
+func (p *Plugin) SetTranscriptRenderer(r TranscriptRenderer) {
+	p.transcriptRenderer = r
+}
+
+// transcriptState holds the agent session shown in the Transcript tab.
+type transcriptState struct {
+	worktree string // worktree the session belongs to
+	adapter  adapter.Adapter
+	session  *adapter.Session // nil when the worktree has no session yet
+	messages []adapter.Message
+	err      error
+	loading  bool
+	dirty    bool // a change arrived while loading; reload when done
+	loadedAt time.Time
+
+	watchGen    int // incremented per watch so stale listeners are ignored
+	watchCh     <-chan adapter.Event
+	watchCloser io.Closer
+
+	// Rendered lines, reused until messages or width change.
+	lines      []string
+	linesWidth int
+}
+
+// transcriptAdapter returns the conversation adapter for a worktree's agent.
+func (p *Plugin) transcriptAdapter(wt *Worktree) adapter.Adapter {
+	agentType := wt.ChosenAgentType
+	if wt.Agent != nil {
+		agentType = wt.Agent.Type
+	}
+	return p.agentAdapter(agentType)
+}
+
+// transcriptVisible reports whether the Transcript tab is showing the named worktree.
+func (p *Plugin) transcriptVisible(worktreeName string) bool {
+	if p.previewTab != PreviewTabTranscript || p.shellSelected {
+		return false
+	}
+	wt := p.selectedWorktree()
+	return wt != nil && wt.Name == worktreeName
+}
+
+// loadSelectedTranscript loads the agent session for the selected worktree
+// when the Transcript tab is active, and starts watching it for changes.
+func (p *Plugin) loadSelectedTranscript() tea.Cmd {
+	if p.previewTab != PreviewTabTranscript || p.shellSelected {
+		return nil
+	}
+	wt := p.selectedWorktree()
+	if wt == nil || wt.IsMain {
+		return nil
+	}
+	if p.transcript.worktree != wt.Name {
+		p.stopTranscriptWatch()
+		p.transcript = transcriptState{worktree: wt.Name, watchGen: p.transcript.watchGen}
+	}
+	a := p.transcriptAdapter(wt)
+	if a == nil {
+		p.transcript.adapter = nil
+		return nil
+	}
+	if p.transcript.adapter != a {
+		p.stopTranscriptWatch()
+		p.transcript.adapter = a
+	}
+	return p.loadTranscript(wt, p.transcript.watchCh == nil)
+}
+
+// loadTranscript returns a command that loads the newest session of a
+// worktree's agent, optionally starting a watch on the worktree.
+func (p *Plugin) loadTranscript(wt *Worktree, watch bool) tea.Cmd {
+	a := p.transcript.adapter
+	if a == nil {
+		return nil
+	}
+	if p.transcript.loading {
+		p.transcript.dirty = true
+		return nil
+	}
+	p.transcript.loading = true
+	p.transcript.dirty = false
+	epoch := p.ctx.Epoch // Capture epoch for stale detection
+	name, path := wt.Name, wt.Path
+	return func() tea.Msg {
+		msg := TranscriptLoadedMsg{Epoch: epoch, WorkspaceName: name, Adapter: a}
+		if watch {
+			ch, closer, err := a.Watch(path)
+			if err == nil && ch != nil && closer != nil {
+				msg.WatchCh, msg.WatchCloser = ch, closer
+			} else if closer != nil {
+				_ = closer.Close()
+			}
+		}
+		msg.Session, msg.Messages, msg.Err = latestAdapterSession(a, path)
+		return msg
+	}
+}
+
+// latestAdapterSession returns the most recently updated top-level session
+// for a worktree and its messages. A nil session means none was found.
+func latestAdapterSession(a adapter.Adapter, worktreePath string) (*adapter.Session, []adapter.Message, error) {
+	sessions, err := a.Sessions(worktreePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	var latest *adapter.Session
+	for i := range sessions {
+		s := &sessions[i]
+		if s.IsSubAgent {
+			continue
+		}
+		if latest == nil || s.UpdatedAt.After(latest.UpdatedAt) {
+			latest = s
+		}
+	}
+	if latest == nil {
+		return nil, nil, nil
+	}
+	messages, err := a.Messages(latest.ID)
+	if err != nil {
+		return latest, nil, err
+	}
+	return latest, messages, nil
+}
+
+// handleTranscriptLoaded stores a loaded session and starts listening for
+// watch events.
+func (p *Plugin) handleTranscriptLoaded(msg TranscriptLoadedMsg) tea.Cmd {
+	t := &p.transcript
+	if msg.WorkspaceName != t.worktree || msg.Adapter != t.adapter {
+		if msg.WatchCloser != nil {
+			_ = msg.WatchCloser.Close()
+		}
+		return nil
+	}
+	t.loading = false
+	t.loadedAt = time.Now()
+	t.err = msg.Err
+	if msg.Err == nil {
+		t.session = msg.Session
+		t.messages = msg.Messages
+		t.lines = nil
+	}
+
+	var cmds []tea.Cmd
+	if msg.WatchCloser != nil {
+		if t.watchCh != nil {
+			_ = msg.WatchCloser.Close()
+		} else {
+			t.watchGen++
+			t.watchCh, t.watchCloser = msg.WatchCh, msg.WatchCloser
+			cmds = append(cmds, p.listenTranscript())
+		}
+	}
+	if t.dirty {
+		if wt := p.findWorktree(t.worktree); wt != nil {
+			cmds = append(cmds, p.loadTranscript(wt, false))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// listenTranscript waits for the next event from the transcript watch.
+func (p *Plugin) listenTranscript() tea.Cmd {
+	ch := p.transcript.watchCh
+	if ch == nil {
+		return nil
+	}
+	epoch := p.ctx.Epoch
+	name, gen := p.transcript.worktree, p.transcript.watchGen
+	return func() tea.Msg {
+		evt, ok := <-ch
+		return TranscriptWatchMsg{Epoch: epoch, WorkspaceName: name, Gen: gen, Event: evt, Closed: !ok}
+	}
+}
+
+// handleTranscriptWatch reloads the transcript when its session changes.
+// The watch is dropped once the tab or selection moves away.
+func (p *Plugin) handleTranscriptWatch(msg TranscriptWatchMsg) tea.Cmd {
+	t := &p.transcript
+	if msg.WorkspaceName != t.worktree || msg.Gen != t.watchGen || t.watchCh == nil {
+		return nil
+	}
+	if msg.Closed {
+		t.watchCh, t.watchCloser = nil, nil
+		return nil
+	}
+	if !p.transcriptVisible(msg.WorkspaceName) {
+		p.stopTranscriptWatch()
+		return nil
+	}
+	cmds := []tea.Cmd{p.listenTranscript()}
+	// Events for other sessions only matter when a new session starts.
+	if t.session == nil || msg.Event.SessionID == "" || msg.Event.SessionID == t.session.ID ||
+		msg.Event.Type == adapter.EventSessionCreated {
+		if wt := p.findWorktree(t.worktree); wt != nil {
+			cmds = append(cmds, p.loadTranscript(wt, false))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// refreshTranscriptOnPoll reloads the transcript after agent output for
+// adapters without a watch, at most once per transcriptReloadInterval.
+func (p *Plugin) refreshTranscriptOnPoll(worktreeName string) tea.Cmd {
+	t := &p.transcript
+	if t.watchCh != nil || t.adapter == nil || t.worktree != worktreeName || !p.transcriptVisible(worktreeName) {
+		return nil
+	}
+	if time.Since(t.loadedAt) < transcriptReloadInterval {
+		return nil
+	}
+	wt := p.findWorktree(worktreeName)
+	if wt == nil {
+		return nil
+	}
+	return p.loadTranscript(wt, false)
+}
+
+// stopTranscriptWatch closes the transcript watch, if any.
+func (p *Plugin) stopTranscriptWatch() {
+	t := &p.transcript
+	if t.watchCloser != nil {
+		_ = t.watchCloser.Close()
+	}
+	t.watchCh, t.watchCloser = nil, nil
+	t.watchGen++
+}
+
+// renderTranscriptContent renders the Transcript tab, following the newest
+// messages unless the user has scrolled up.
+func (p *Plugin) renderTranscriptContent(width, height int) string {
+	wt := p.selectedWorktree()
+	if wt == nil {
+		return dimText("No worktree selected")
+	}
+	t := &p.transcript
+	if t.worktree != wt.Name {
+		return dimText("Loading transcript...")
+	}
+	if t.adapter == nil {
+		agentType := wt.ChosenAgentType
+		if wt.Agent != nil {
+			agentType = wt.Agent.Type
+		}
+		name := AgentDisplayNames[agentType]
+		if name == "" {
+			name = string(agentType)
+		}
+		return dimText(fmt.Sprintf("No conversation history available for %s", name))
+	}
+	if t.err != nil && t.session == nil {
+		return styles.StatusDeleted.Render("Failed to load session: " + t.err.Error())
+	}
+	if t.session == nil {
+		if t.loading {
+			return dimText("Loading transcript...")
+		}
+		return dimText("No agent session found for this worktree")
+	}
+
+	header := t.session.Name
+	if header == "" {
+		header = t.session.ID
+	}
+	if t.watchCh != nil {
+		header += " • live"
+	}
+	height-- // Reserve line for header
+
+	if len(t.messages) == 0 {
+		return dimText(header) + "\n" + dimText("No messages yet")
+	}
+	if t.lines == nil || t.linesWidth != width {
+		t.lines = p.renderTranscriptLines(t.messages, t.adapter.Name(), width)
+		t.linesWidth = width
+	}
+
+	// previewOffset is lines from the bottom, as in the Output tab
+	lines := t.lines
+	if height < 1 {
+		height = 1
+	}
+	maxOffset := len(lines) - height
+	if maxOffset < 0 {
+		maxOffset = 0
+	}
+	if p.previewOffset > maxOffset {
+		p.previewOffset = maxOffset
+	}
+	end := len(lines) - p.previewOffset
+	start := end - height
+	if start < 0 {
+		start = 0
+	}
+	return dimText(header) + "\n" + strings.Join(lines[start:end], "\n")
+}
+
+// renderTranscriptLines renders messages with the injected renderer, falling
+// back to plain role-prefixed text.
+func (p *Plugin) renderTranscriptLines(messages []adapter.Message, agentName string, width int) []string {
+	if p.transcriptRenderer != nil {
+		return p.transcriptRenderer.Render(messages, agentName, width)
+	}
+	var lines []string
+	for _, msg := range messages {
+		if msg.Content == "" {
+			continue
+		}
+		role := agentName
+		if msg.Role == "user" {
+			role = "you"
+		}
+		lines = append(lines, fmt.Sprintf("[%s] %s", msg.Timestamp.Local().Format("15:04"), role))
+		for _, line := range strings.Split(msg.Content, "\n") {
+			lines = append(lines, "    "+line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,112 @@
+package workspace
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/marcus/sidecar/internal/adapter"
+	"github.com/marcus/sidecar/internal/plugin"
+)
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error { return f() }
+
+// transcriptTestAdapter is a statusTestAdapter whose Watch reports events.
+type transcriptTestAdapter struct {
+	statusTestAdapter
+	events chan adapter.Event
+	closed bool
+}
+
+func (a *transcriptTestAdapter) Watch(string) (<-chan adapter.Event, io.Closer, error) {
+	return a.events, closerFunc(func() error { a.closed = true; return nil }), nil
+}
+
+func newTranscriptTestPlugin(a adapter.Adapter) *Plugin {
+	p := New()
+	p.ctx = &plugin.Context{Adapters: map[string]adapter.Adapter{"claude-code": a}}
+	p.worktrees = []*Worktree{{Name: "auth", Path: "/tmp/auth", ChosenAgentType: AgentClaude}}
+	p.previewTab = PreviewTabTranscript
+	return p
+}
+
+func TestLatestAdapterSession(t *testing.T) {
+	now := time.Now()
+	a := &statusTestAdapter{
+		sessions: []adapter.Session{
+			{ID: "old", UpdatedAt: now.Add(-time.Hour)},
+			{ID: "sub", UpdatedAt: now, IsSubAgent: true},
+			{ID: "new", UpdatedAt: now.Add(-time.Minute)},
+		},
+		messages: []adapter.Message{{Role: "user", Content: "hi"}},
+	}
+	session, messages, err := latestAdapterSession(a, "/tmp/auth")
+	if err != nil || session == nil || session.ID != "new" || len(messages) != 1 {
+		t.Fatalf("latestAdapterSession() = (%v, %v, %v), want session new", session, messages, err)
+	}
+
+	session, _, err = latestAdapterSession(&statusTestAdapter{}, "/tmp/auth")
+	if session != nil || err != nil {
+		t.Errorf("expected no session, got (%v, %v)", session, err)
+	}
+}
+
+func TestTranscript_LoadWatchAndRender(t *testing.T) {
+	a := &transcriptTestAdapter{
+		statusTestAdapter: statusTestAdapter{
+			sessions: []adapter.Session{{ID: "s1", Name: "fix login", UpdatedAt: time.Now()}},
+			messages: []adapter.Message{{Role: "user", Content: "fix the login bug"}},
+		},
+		events: make(chan adapter.Event, 1),
+	}
+	p := newTranscriptTestPlugin(a)
+
+	cmd := p.loadSelectedTranscript()
+	if cmd == nil {
+		t.Fatal("expected load command")
+	}
+	msg, ok := cmd().(TranscriptLoadedMsg)
+	if !ok || msg.WatchCh == nil {
+		t.Fatalf("expected loaded message with watch, got %#v", msg)
+	}
+	listen := p.handleTranscriptLoaded(msg)
+	if listen == nil || p.transcript.watchCh == nil {
+		t.Fatal("expected watch to be started")
+	}
+
+	out := ansi.Strip(p.renderTranscriptContent(80, 20))
+	if !strings.Contains(out, "fix login • live") || !strings.Contains(out, "fix the login bug") {
+		t.Errorf("unexpected transcript:\n%s", out)
+	}
+
+	a.events <- adapter.Event{Type: adapter.EventMessageAdded, SessionID: "s1"}
+	watchMsg := listen().(TranscriptWatchMsg)
+	if p.handleTranscriptWatch(watchMsg) == nil || !p.transcript.loading {
+		t.Error("expected session change to reload the transcript")
+	}
+
+	// Leaving the tab drops the watch on the next event.
+	p.previewTab = PreviewTabOutput
+	a.events <- adapter.Event{Type: adapter.EventMessageAdded, SessionID: "s1"}
+	watchMsg = p.listenTranscript()().(TranscriptWatchMsg)
+	p.handleTranscriptWatch(watchMsg)
+	if p.transcript.watchCh != nil || !a.closed {
+		t.Error("expected watch to be closed after leaving the tab")
+	}
+}
+
+func TestTranscript_NoAdapter(t *testing.T) {
+	p := newTranscriptTestPlugin(nil)
+	p.ctx.Adapters = nil
+	if cmd := p.loadSelectedTranscript(); cmd != nil {
+		t.Error("expected no load without an adapter")
+	}
+	out := p.renderTranscriptContent(80, 20)
+	if !strings.Contains(out, "No conversation history available for Claude") {
+		t.Errorf("unexpected content: %q", out)
+	}
+}
@@ -23,21 +23,22 @@ var partialMouseEscapeRegex = regexp.MustCompile(`\[<\d+;\d+;\d+[Mm]?`)
 type ViewMode int
 
 const (
-	ViewModeList           ViewMode = iota // List view (default)
-	ViewModeKanban                         // Kanban board view
-	ViewModeCreate                         // New worktree modal
-	ViewModeTaskLink                       // Task link modal (for existing worktrees)
-	ViewModeMerge                          // Merge workflow modal
-	ViewModeAgentChoice                    // Agent action choice modal (attach/restart)
-	ViewModeConfirmDelete                  // Delete confirmation modal
-	ViewModeConfirmDeleteShell             // Shell delete confirmation modal
-	ViewModeCommitForMerge                 // Commit modal before merge workflow
-	ViewModePromptPicker                   // Prompt template picker modal
-	ViewModeTypeSelector                   // Type selector modal (shell vs worktree)
-	ViewModeRenameShell                    // Rename shell modal
-	ViewModeFilePicker                     // Diff file picker modal
-	ViewModeInteractive                    // Interactive mode (tmux input passthrough)
-	ViewModeFetchPR                        // Fetch remote PR modal
+	ViewModeList               ViewMode = iota // List view (default)
+	ViewModeKanban                             // Kanban board view
+	ViewModeCreate                             // New worktree modal
+	ViewModeTaskLink                           // Task link modal (for existing worktrees)
+	ViewModeMerge                              // Merge workflow modal
+	ViewModeAgentChoice                        // Agent action choice modal (attach/restart)
+	ViewModeConfirmDelete                      // Delete confirmation modal
+	ViewModeConfirmDeleteShell                 // Shell delete confirmation modal
+	ViewModeCommitForMerge                     // Commit modal before merge workflow
+	ViewModePromptPicker                       // Prompt template picker modal
+	ViewModeTypeSelector                       // Type selector modal (shell vs worktree)
+	ViewModeRenameShell                        // Rename shell modal
+	ViewModeFilePicker                         // Diff file picker modal
+	ViewModeInteractive                        // Interactive mode (tmux input passthrough)
+	ViewModeFetchPR                            // Fetch remote PR modal
+	ViewModeMergeQueue                         // Merge queue modal
 )
 
 // FocusPane represents which pane is active in the split view.
@@ -52,11 +53,16 @@ const (
 type PreviewTab int
 
 const (
-	PreviewTabOutput PreviewTab = iota // Agent output
-	PreviewTabDiff                     // Git diff
-	PreviewTabTask                     // TD task info
+	PreviewTabOutput     PreviewTab = iota // Agent output
+	PreviewTabDiff                         // Git diff
+	PreviewTabTask                         // TD task info
+	PreviewTabTranscript                   // Agent session transcript
+	PreviewTabLog                          // Persisted session output log
 )
 
+// previewTabCount is the number of preview tabs.
+const previewTabCount = 5
+
 // DiffViewMode specifies the diff rendering mode.
 type DiffViewMode int
 
@@ -219,6 +225,7 @@ type Worktree struct {
 	Agent           *Agent         // nil if no agent running
 	Status          WorktreeStatus // Derived from agent state
 	Stats           *GitStats      // +/- line counts
+	Budget          *Budget        // Run budget (nil if unlimited)
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 	IsOrphaned      bool // True if agent file exists but tmux session is gone
@@ -9,6 +9,7 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	app "github.com/marcus/sidecar/internal/app"
+	"github.com/marcus/sidecar/internal/notify"
 	"github.com/marcus/sidecar/internal/plugin"
 	"github.com/marcus/sidecar/internal/plugins/gitstatus"
 )
@@ -104,17 +105,13 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 					continue // Skip metadata for worktrees with missing directories
 				}
 				cmds = append(cmds, p.loadStats(wt.Path))
-				// Load linked task ID from .sidecar-task file
-				wt.TaskID = loadTaskLink(wt.Path)
-				// Load chosen agent type from .sidecar-agent file
-				wt.ChosenAgentType = loadAgentType(wt.Path)
-				// Load PR URL from .sidecar-pr file
-				wt.PRURL = loadPRURL(wt.Path)
-				// Load base branch from .sidecar-base file
-				wt.BaseBranch = loadBaseBranch(wt.Path)
-			}
-			// Detect conflicts across worktrees
-			cmds = append(cmds, p.loadConflicts())
+				// Load task link, agent type, PR URL and base branch from dotfiles
+				LoadWorktreeMetadata(wt)
+			}
+			// Predict conflicts across worktrees
+			cmds = append(cmds, p.loadConflicts(true))
+			// Measure usage for budgeted worktrees
+			cmds = append(cmds, p.measureBudgets())
 
 			// Load diff for the selected worktree so diff tab shows content immediately
 			cmds = append(cmds, p.loadSelectedDiff())
@@ -127,10 +124,22 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		}
 
 	case ConflictsDetectedMsg:
+		if plugin.IsStale(p.ctx, msg) {
+			return p, nil
+		}
 		if msg.Err == nil {
 			p.conflicts = msg.Conflicts
 		}
 
+	case conflictCheckMsg:
+		cmds = append(cmds, p.handleConflictCheck(msg))
+
+	case budgetCheckMsg:
+		cmds = append(cmds, p.handleBudgetCheck(msg))
+
+	case BudgetUsageMsg:
+		cmds = append(cmds, p.handleBudgetUsage(msg))
+
 	case StatsLoadedMsg:
 		// Discard stale messages from previous project
 		if plugin.IsStale(p.ctx, msg) {
@@ -160,6 +169,34 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 			}
 		}
 
+	case TranscriptLoadedMsg:
+		// Discard stale messages from previous project
+		if plugin.IsStale(p.ctx, msg) {
+			if msg.WatchCloser != nil {
+				_ = msg.WatchCloser.Close()
+			}
+			return p, nil
+		}
+		if cmd := p.handleTranscriptLoaded(msg); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+
+	case TranscriptWatchMsg:
+		if plugin.IsStale(p.ctx, msg) {
+			return p, nil
+		}
+		if cmd := p.handleTranscriptWatch(msg); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+
+	case SessionLogLoadedMsg:
+		if plugin.IsStale(p.ctx, msg) {
+			return p, nil
+		}
+		if cmd := p.handleSessionLogLoaded(msg); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+
 	case CommitStatusLoadedMsg:
 		// Discard stale messages from previous project
 		if plugin.IsStale(p.ctx, msg) {
@@ -174,31 +211,12 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		if msg.Err != nil {
 			p.createError = msg.Err.Error()
 			// Stay in ViewModeCreate - don't close modal or clear state
+		} else if msg.Setup.shouldShow() && p.viewMode == ViewModeCreate {
+			// Show setup results in the create modal until the user continues
+			p.createSetup = &msg
+			p.createModal = nil
 		} else {
-			p.viewMode = ViewModeList
-			p.worktrees = append(p.worktrees, msg.Worktree)
-
-			// Auto-focus newly created worktree (same pattern as click selection)
-			p.shellSelected = false
-			p.selectedIdx = len(p.worktrees) - 1
-			p.previewOffset = 0
-			p.autoScrollOutput = true
-			p.resetScrollBaseLineCount() // td-f7c8be: clear snapshot for new selection
-			p.saveSelectionState()
-			p.ensureVisible()
-
-			p.clearCreateModal()
-
-			// Load content for preview pane
-			cmds = append(cmds, p.loadSelectedContent())
-
-			// Start agent or attach based on selection
-			if msg.AgentType != AgentNone && msg.AgentType != "" {
-				cmds = append(cmds, p.StartAgentWithOptions(msg.Worktree, msg.AgentType, msg.SkipPerms, msg.Prompt))
-			} else {
-				// "None" selected - attach to worktree directory
-				cmds = append(cmds, p.AttachToWorktreeDir(msg.Worktree))
-			}
+			cmds = append(cmds, p.finishCreate(msg))
 		}
 
 	case PromptSelectedMsg:
@@ -214,6 +232,10 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 					break
 				}
 			}
+			// Prefill the prompt's default budget unless one was typed
+			if msg.Prompt.Budget != "" && p.createBudgetInput.Value() == "" {
+				p.createBudgetInput.SetValue(msg.Prompt.Budget)
+			}
 			// If ticketMode is none, skip task field and jump to agent
 			if msg.Prompt.TicketMode == TicketNone {
 				p.createFocus = 4 // agent field
@@ -241,7 +263,7 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		}
 		configDir := filepath.Join(home, ".config", "sidecar")
 		if WriteDefaultPromptsToConfig(configDir) {
-			p.createPrompts = LoadPrompts(configDir, p.ctx.WorkDir)
+			p.loadCreatePrompts(configDir)
 			p.promptPicker = NewPromptPicker(p.createPrompts, p.width, p.height)
 			p.clearPromptPickerModal()
 		} else {
@@ -313,6 +335,7 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 			break
 		}
 		p.removeWorktreeByName(msg.Name)
+		p.notifier.Forget(notify.WorktreeKey(msg.Name))
 		if p.selectedIdx >= len(p.worktrees) && p.selectedIdx > 0 {
 			p.selectedIdx--
 		}
@@ -362,6 +385,8 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 			}
 			p.agents[msg.WorkspaceName] = agent
 			p.managedSessions[msg.SessionName] = true
+			p.logSessionEvent(msg.SessionName, "agent started (%s)", msg.AgentType)
+			p.logSessionStatus(msg.SessionName, StatusActive, "")
 
 			// Resize pane to match preview width immediately
 			if cmd := p.resizeSelectedPaneCmd(); cmd != nil {
@@ -369,6 +394,9 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 			}
 			// Start polling for output
 			cmds = append(cmds, p.scheduleAgentPoll(msg.WorkspaceName, pollIntervalInitial))
+			if cmd := p.listenBackendUpdates(backendFor(msg.SessionName)); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
 
 			// If this is a resume operation, enter interactive mode (td-aa4136)
 			if p.pendingResumeWorktree == msg.WorkspaceName {
@@ -377,6 +405,29 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 			}
 		}
 
+	case backendUpdateMsg:
+		cmds = append(cmds, waitBackendUpdate(msg.updates))
+		if shell := p.findShellByName(msg.Session); shell != nil {
+			// Only the selected shell is on screen
+			if shell != p.getSelectedShell() || (p.viewMode != ViewModeList && p.viewMode != ViewModeInteractive) {
+				return p, tea.Batch(cmds...)
+			}
+			p.shellPollGeneration[msg.Session]++
+			cmds = append(cmds, p.pollShellSessionByName(msg.Session))
+			return p, tea.Batch(cmds...)
+		}
+		wt := p.findWorktreeBySession(msg.Session)
+		// Output that isn't on screen waits for the regular poll, which also
+		// keeps runaway sessions throttled.
+		if wt == nil || p.attachedSession == wt.Name || wt.Agent.PollsThrottled ||
+			!p.outputVisibleForUnfocused(wt.Name) {
+			return p, tea.Batch(cmds...)
+		}
+		// Replace the pending poll with an immediate capture
+		p.pollGeneration[wt.Name]++
+		cmds = append(cmds, p.handlePollAgent(wt.Name))
+		return p, tea.Batch(cmds...)
+
 	case pollAgentMsg:
 		// Timer leak prevention (td-83dc22): ignore stale poll messages.
 		// If the worktree was removed or reset since this timer was scheduled,
@@ -400,6 +451,14 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 			wt.Status = msg.Status
 			// Track poll time for runaway detection (td-018f25)
 			wt.Agent.RecordPollTime()
+			p.observeWorktreeStatus(wt)
+			p.logSessionStatus(wt.Agent.TmuxSession, msg.Status, msg.WaitingFor)
+		}
+		if cmd := p.refreshTranscriptOnPoll(msg.WorkspaceName); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+		if cmd := p.refreshSessionLogOnPoll(msg.WorkspaceName); cmd != nil {
+			cmds = append(cmds, cmd)
 		}
 		// Update bracketed paste mode and cursor position if in interactive mode (td-79ab6163)
 		if p.viewMode == ViewModeInteractive && !p.shellSelected {
@@ -515,6 +574,7 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		if msg.Err != nil {
 			// Creation failed, show error toast
 			p.pendingResumeCmd = "" // Clear pending resume
+			p.pendingShellCmd = ""
 			return p, func() tea.Msg {
 				return app.ToastMsg{Message: msg.Err.Error(), Duration: 5 * time.Second, IsError: true}
 			}
@@ -593,6 +653,7 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		p.activePane = PaneSidebar
 		p.autoScrollOutput = true
 		p.resetScrollBaseLineCount() // td-f7c8be: clear snapshot for new shell
+		p.logSessionEvent(msg.SessionName, "shell started (%s)", displayAgentType)
 
 		// Resize pane to match preview width immediately
 		if cmd := p.resizeSelectedPaneCmd(); cmd != nil {
@@ -600,6 +661,9 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		}
 		// Start polling for output using stable TmuxName
 		cmds = append(cmds, p.scheduleShellPollByName(msg.SessionName, 500*time.Millisecond))
+		if cmd := p.listenBackendUpdates(backendFor(msg.SessionName)); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
 
 		// If there's a pending resume command, inject it and enter interactive mode (td-aa4136)
 		if p.pendingResumeCmd != "" {
@@ -608,6 +672,11 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 			cmds = append(cmds, p.sendResumeCommandToShell(msg.SessionName, resumeCmd))
 			// Enter interactive mode after command is injected
 			cmds = append(cmds, func() tea.Msg { return shellResumeInjectedMsg{TmuxSession: msg.SessionName} })
+		} else if p.pendingShellCmd != "" {
+			// Custom command from the palette: run it right away
+			shellCmd := p.pendingShellCmd
+			p.pendingShellCmd = ""
+			cmds = append(cmds, p.runCommandInShell(msg.SessionName, shellCmd))
 		} else if msg.AgentType != AgentNone && msg.AgentType != "" {
 			// td-2ba8a3: Start agent if one was selected (not AgentNone)
 			cmds = append(cmds, p.startAgentInShell(msg.SessionName, msg.AgentType, msg.SkipPerms))
@@ -684,6 +753,11 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 			return app.ToastMsg{Message: "Failed to inject resume command", Duration: 3 * time.Second, IsError: true}
 		}
 
+	case shellCommandErrorMsg:
+		return p, func() tea.Msg {
+			return app.ToastMsg{Message: "Failed to run command in shell: " + msg.Err.Error(), Duration: 3 * time.Second, IsError: true}
+		}
+
 	case worktreeResumeCreatedMsg:
 		// Worktree created for resume - start agent with resume command (td-aa4136)
 		if msg.Err != nil {
@@ -723,6 +797,7 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 				}
 				p.shells = append(p.shells[:i], p.shells[i+1:]...)
 				delete(p.managedSessions, msg.SessionName)
+				p.closeSessionLog(msg.SessionName, "shell killed")
 				// Clean up pane cache and active registry (td-018f25)
 				globalPaneCache.remove(msg.SessionName)
 				globalActiveRegistry.remove(msg.SessionName)
@@ -775,6 +850,8 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 				}
 				p.shells = append(p.shells[:i], p.shells[i+1:]...)
 				delete(p.managedSessions, msg.TmuxName)
+				p.notifier.Forget(notify.ShellKey(msg.TmuxName))
+				p.closeSessionLog(msg.TmuxName, "session ended")
 				// Clean up pane cache and active registry (td-018f25)
 				globalPaneCache.remove(msg.TmuxName)
 				globalActiveRegistry.remove(msg.TmuxName)
@@ -838,6 +915,10 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		if shell != nil && msg.Changed && shell.Agent != nil {
 			shell.Agent.LastOutput = time.Now()
 		}
+		if shell != nil && msg.HasStatus {
+			p.observeShellStatus(shell, msg.Status)
+			p.logSessionStatus(msg.TmuxName, msg.Status, "")
+		}
 		// Update bracketed paste mode and cursor position if in interactive mode (td-79ab6163)
 		if p.viewMode == ViewModeInteractive && p.shellSelected {
 			if selectedShell := p.getSelectedShell(); selectedShell != nil && selectedShell.TmuxName == msg.TmuxName {
@@ -936,12 +1017,18 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 			sessionName := tmuxSessionPrefix + sanitizeName(wt.Name)
 			wt.Agent = nil
 			wt.Status = StatusPaused
+			p.observeWorktreeStatus(wt)
+			p.closeSessionLog(sessionName, "session ended")
 			// Clean up cache, active registry, and session tracking (td-53e8a023, td-018f25)
 			globalPaneCache.remove(sessionName)
 			globalActiveRegistry.remove(sessionName)
 			delete(p.managedSessions, sessionName)
 		}
 		delete(p.agents, msg.WorkspaceName)
+		// Show the flushed end of the log
+		if p.sessionLogVisible(msg.WorkspaceName) {
+			return p, p.loadSessionLog()
+		}
 		return p, nil
 
 	case restartAgentMsg:
@@ -1086,7 +1173,9 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		}
 
 	case MergeStepCompleteMsg:
-		if p.mergeState != nil && p.mergeState.Worktree.Name == msg.WorkspaceName {
+		if p.mergeQueue.owns(msg.WorkspaceName) {
+			cmds = append(cmds, p.handleMergeQueuePR(msg))
+		} else if p.mergeState != nil && p.mergeState.Worktree.Name == msg.WorkspaceName {
 			if msg.Err != nil {
 				title := fmt.Sprintf("%s Failed", msg.Step.String())
 				p.transitionToMergeError(msg.Step, title, msg.Err)
@@ -1121,6 +1210,9 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 			}
 		}
 
+	case MergeQueueStepMsg:
+		cmds = append(cmds, p.handleMergeQueueStep(msg))
+
 	case CheckPRMergedMsg:
 		if p.mergeState != nil && p.mergeState.Worktree.Name == msg.WorkspaceName {
 			if msg.Err != nil {
@@ -1142,8 +1234,11 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		}
 
 	case DirectMergeDoneMsg:
-		if p.mergeState != nil && p.mergeState.Worktree.Name == msg.WorkspaceName {
+		if p.mergeQueue.owns(msg.WorkspaceName) {
+			cmds = append(cmds, p.handleMergeQueueDirectMerge(msg))
+		} else if p.mergeState != nil && p.mergeState.Worktree.Name == msg.WorkspaceName {
 			if msg.Err != nil {
+				p.mergeState.ConflictedFiles = msg.Conflicts
 				p.transitionToMergeError(MergeStepDirectMerge, "Direct Merge Failed", msg.Err)
 			} else {
 				// Direct merge succeeded, advance to confirmation
@@ -1152,7 +1247,9 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		}
 
 	case CleanupDoneMsg:
-		if p.mergeState != nil && p.mergeState.Worktree.Name == msg.WorkspaceName {
+		if p.mergeQueue.owns(msg.WorkspaceName) {
+			cmds = append(cmds, p.handleMergeQueueCleanup(msg))
+		} else if p.mergeState != nil && p.mergeState.Worktree.Name == msg.WorkspaceName {
 			if p.mergeState.CleanupResults == nil {
 				p.mergeState.CleanupResults = msg.Results
 			} else {
@@ -1223,9 +1320,11 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 				p.mergeState.CleanupResults.BranchDiverged = false
 				p.mergeState.CleanupResults.PullErrorSummary = ""
 				p.mergeState.CleanupResults.PullErrorFull = ""
+				p.mergeState.ConflictedFiles = nil
 			} else {
 				// Rebase failed - update error state
 				p.mergeState.CleanupResults.PullError = msg.Err
+				p.mergeState.ConflictedFiles = msg.Conflicts
 				summary, full, diverged := summarizeGitError(msg.Err)
 				p.mergeState.CleanupResults.PullErrorSummary = summary
 				p.mergeState.CleanupResults.PullErrorFull = full
@@ -1242,9 +1341,11 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 				p.mergeState.CleanupResults.BranchDiverged = false
 				p.mergeState.CleanupResults.PullErrorSummary = ""
 				p.mergeState.CleanupResults.PullErrorFull = ""
+				p.mergeState.ConflictedFiles = nil
 			} else {
 				// Merge failed - update error state
 				p.mergeState.CleanupResults.PullError = msg.Err
+				p.mergeState.ConflictedFiles = msg.Conflicts
 				summary, full, diverged := summarizeGitError(msg.Err)
 				p.mergeState.CleanupResults.PullErrorSummary = summary
 				p.mergeState.CleanupResults.PullErrorFull = full
@@ -1256,6 +1357,9 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		// After reconnecting to existing sessions, detect orphaned worktrees
 		// (worktrees with .sidecar-agent file but no tmux session)
 		p.detectOrphanedWorktrees()
+		for _, agent := range p.agents {
+			p.logSessionEvent(agent.TmuxSession, "reconnected to running session")
+		}
 		// Start periodic session validation to prevent memory leaks (td-41695b)
 		pollingCmds := append(msg.Cmds, p.scheduleSessionValidation(60*time.Second))
 		return p, tea.Batch(pollingCmds...)
@@ -1281,6 +1385,11 @@ func (p *Plugin) Update(msg tea.Msg) (plugin.Plugin, tea.Cmd) {
 		// Handle resume from conversations plugin (td-aa4136)
 		return p.handleResumeConversation(msg)
 
+	case app.RunShellCommandMsg:
+		// Custom command with shell output, sent by the app
+		p.pendingShellCmd = msg.Command
+		return p, p.createNewShell(msg.Name)
+
 	case cursorPositionMsg:
 		// Update cached cursor position for interactive mode rendering (td-648af4)
 		if p.interactiveState != nil && p.interactiveState.Active {
@@ -22,6 +22,12 @@ func (p *Plugin) renderDiffContent(width, height int) string {
 	if p.commitStatusWorktree == wt.Name {
 		header = p.renderCommitStatusHeader(width)
 	}
+	if conflictHeader := p.renderConflictHeader(wt.Name, width); conflictHeader != "" {
+		if header != "" {
+			header += "\n"
+		}
+		header += conflictHeader
+	}
 
 	headerHeight := 0
 	if header != "" {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/ansi"
+	"github.com/marcus/sidecar/internal/notify"
 	"github.com/marcus/sidecar/internal/styles"
 	"github.com/marcus/sidecar/internal/ui"
 )
@@ -79,6 +80,8 @@ func (p *Plugin) View(width, height int) string {
 		return p.renderRenameShellModal(width, height)
 	case ViewModeFetchPR:
 		return p.renderFetchPRModal(width, height)
+	case ViewModeMergeQueue:
+		return p.renderMergeQueueModal(width, height)
 	case ViewModeFilePicker:
 		background := p.renderListView(width, height)
 		return p.renderFilePickerModal(background)
@@ -114,7 +117,7 @@ func (p *Plugin) renderListView(width, height int) string {
 		// Shell has no tabs - it shows primer/output directly
 		if !p.shellSelected {
 			// X starts at panelOverhead/2 (1 for border + 1 for panel padding)
-			tabWidths := []int{10, 8, 8} // " Output " + padding, " Diff " + padding, " Task " + padding
+			tabWidths := []int{10, 8, 8, 14, 7} // " Output ", " Diff ", " Task ", " Transcript ", " Log " + padding
 			tabX := panelOverhead / 2
 			for i, tabWidth := range tabWidths {
 				p.mouseHandler.HitMap.AddRect(regionPreviewTab, tabX, 1, tabWidth, 1, i)
@@ -171,9 +174,9 @@ func (p *Plugin) renderListView(width, height int) string {
 		// Tabs are rendered at Y=1 (first line inside panel border)
 		// X starts at sidebarW + dividerWidth + panelOverhead/2 (border + padding on left side)
 		previewPaneX := sidebarW + dividerWidth + panelOverhead/2
-		// Tab widths: text is " Output " (8), " Diff " (6), " Task " (6)
-		// Plus BarChip Padding(0,1) adds 2 chars = 10, 8, 8 visual width
-		tabWidths := []int{10, 8, 8}
+		// Tab widths: text is " Output " (8), " Diff " (6), " Task " (6), " Transcript " (12), " Log " (5)
+		// Plus BarChip Padding(0,1) adds 2 chars = 10, 8, 8, 14, 7 visual width
+		tabWidths := []int{10, 8, 8, 14, 7}
 		tabX := previewPaneX
 		for i, tabWidth := range tabWidths {
 			p.mouseHandler.HitMap.AddRect(regionPreviewTab, tabX, 1, tabWidth, 1, i)
@@ -509,12 +512,19 @@ func (p *Plugin) renderWorktreeItem(wt *Worktree, selected bool, width int) stri
 			parts = append(parts, fmt.Sprintf("⚠ %d conflicts", len(conflictFiles)))
 		}
 	}
+	budgetStr, budgetLevel := p.budgetLabel(wt)
+	if budgetStr != "" {
+		parts = append(parts, budgetStr)
+	}
 	if wt.IsOrphaned {
 		parts = append(parts, "⚠ session ended")
 	}
 	if wt.IsMissing {
 		parts = append(parts, "✗ folder missing")
 	}
+	if p.notifier.Muted(notify.WorktreeKey(wt.Name)) {
+		parts = append(parts, "muted")
+	}
 
 	// When selected, use plain text to ensure consistent background
 	if isSelected {
@@ -615,6 +625,15 @@ func (p *Plugin) renderWorktreeItem(wt *Worktree, selected bool, width int) stri
 			styledParts = append(styledParts, styles.StatusModified.Render(fmt.Sprintf("⚠ %d conflicts", len(conflictFiles))))
 		}
 	}
+	switch {
+	case budgetStr == "":
+	case budgetLevel == budgetExceeded:
+		styledParts = append(styledParts, styles.StatusDeleted.Render(budgetStr))
+	case budgetLevel == budgetWarn:
+		styledParts = append(styledParts, styles.StatusModified.Render(budgetStr))
+	default:
+		styledParts = append(styledParts, dimText(budgetStr))
+	}
 	if wt.IsOrphaned {
 		styledParts = append(styledParts, styles.StatusModified.Render("⚠ session ended"))
 	}
@@ -717,9 +717,22 @@ func (p *Plugin) ensureMergeModal() {
 		m.AddSection(modal.Spacer())
 		m.AddSection(modal.Text(p.mergeState.ErrorDetail))
 		m.AddSection(modal.Spacer())
-		m.AddSection(modal.Buttons(modal.Btn(" Dismiss ", "dismiss")))
-		m.AddSection(modal.Spacer())
-		m.AddSection(modal.Text(dimText("y: copy error   Esc: dismiss")))
+		if n := len(p.mergeState.ConflictedFiles); n > 0 {
+			m.AddSection(modal.Spacer())
+			m.AddSection(modal.Text(lipgloss.NewStyle().Foreground(styles.Warning).Render(
+				fmt.Sprintf("The merge stopped with conflicts in %d file(s).", n))))
+			m.AddSection(modal.Spacer())
+			m.AddSection(modal.Buttons(
+				modal.Btn(" Resolve Conflicts ", mergeResolveButtonID, modal.BtnPrimary()),
+				modal.Btn(" Dismiss ", "dismiss"),
+			))
+			m.AddSection(modal.Spacer())
+			m.AddSection(modal.Text(dimText("c: resolve conflicts   y: copy error   Esc: dismiss")))
+		} else {
+			m.AddSection(modal.Buttons(modal.Btn(" Dismiss ", "dismiss")))
+			m.AddSection(modal.Spacer())
+			m.AddSection(modal.Text(dimText("y: copy error   Esc: dismiss")))
+		}
 	}
 
 	p.mergeModal = m
@@ -994,6 +1007,15 @@ func (p *Plugin) mergeDoneSection() modal.Section {
 						sb.WriteString(dimText("        Creates a merge commit combining both histories"))
 						sb.WriteString("\n")
 					}
+
+					if n := len(p.mergeState.ConflictedFiles); n > 0 {
+						sb.WriteString("\n")
+						sb.WriteString(lipgloss.NewStyle().Foreground(styles.Warning).Render(
+							fmt.Sprintf("  Stopped with conflicts in %d file(s).", n)))
+						sb.WriteString("\n")
+						sb.WriteString(dimText("    [c] Resolve conflicts in the git plugin"))
+						sb.WriteString("\n")
+					}
 				}
 			}
 
@@ -1011,7 +1033,9 @@ func (p *Plugin) mergeDoneSection() modal.Section {
 		}
 
 		sb.WriteString("\n\n")
-		if p.mergeState.CleanupResults != nil && p.mergeState.CleanupResults.BranchDiverged {
+		if len(p.mergeState.ConflictedFiles) > 0 {
+			sb.WriteString(dimText("c: resolve conflicts  d: details  Enter: close"))
+		} else if p.mergeState.CleanupResults != nil && p.mergeState.CleanupResults.BranchDiverged {
 			sb.WriteString(dimText("r: rebase  m: merge  d: details  Enter: close"))
 		} else if p.mergeState.CleanupResults != nil && p.mergeState.CleanupResults.PullError != nil {
 			sb.WriteString(dimText("d: details  Enter: close"))
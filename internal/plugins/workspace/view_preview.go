@@ -64,6 +64,10 @@ func (p *Plugin) renderPreviewContent(width, height int) string {
 		content = p.renderDiffContent(width, contentHeight)
 	case PreviewTabTask:
 		content = p.renderTaskContent(width, contentHeight)
+	case PreviewTabTranscript:
+		content = p.renderTranscriptContent(width, contentHeight)
+	case PreviewTabLog:
+		content = p.renderSessionLogContent(width, contentHeight)
 	}
 
 	lines = append(lines, content)
@@ -95,8 +99,9 @@ func (p *Plugin) renderWelcomeGuide(width, height int) string {
 	sectionStyle := lipgloss.NewStyle().Bold(true).Foreground(styles.Primary)
 	warningStyle := lipgloss.NewStyle().Bold(true).Foreground(styles.Warning)
 
-	// Check if tmux is installed
-	if !isTmuxInstalled() {
+	// Check if tmux is installed; agents can do without it on the PTY backend
+	_, usesTmux := p.agentBackend().(tmuxBackend)
+	if !isTmuxInstalled() && usesTmux {
 		lines = append(lines, warningStyle.Render("⚠ tmux Required"))
 		lines = append(lines, "")
 		lines = append(lines, dimText("Workspaces and shell sessions require tmux to be installed."))
@@ -108,6 +113,14 @@ func (p *Plugin) renderWelcomeGuide(width, height int) string {
 		return strings.Join(lines, "\n")
 	}
 
+	if !usesTmux {
+		lines = append(lines, dimText("Agents run in built-in terminals (backend: pty)."))
+		if !isTmuxInstalled() {
+			lines = append(lines, dimText("Shell sessions still require tmux."))
+		}
+		lines = append(lines, "")
+	}
+
 	// Git Worktree Explanation
 	lines = append(lines, sectionStyle.Render("Git Worktrees: A Better Workflow"))
 	lines = append(lines, dimText("  • Parallel Development: Work on multiple branches simultaneously"))
@@ -189,7 +202,7 @@ func (p *Plugin) truncateAllLines(content string, maxWidth int) string {
 
 // renderTabs renders the preview pane tab header.
 func (p *Plugin) renderTabs(width int) string {
-	tabs := []string{"Output", "Diff", "Task"}
+	tabs := []string{"Output", "Diff", "Task", "Transcript", "Log"}
 	var rendered []string
 
 	for i, tab := range tabs {
@@ -610,8 +623,9 @@ func (p *Plugin) renderShellPrimer(width, height int) string {
 	sectionStyle := lipgloss.NewStyle().Bold(true).Foreground(styles.Primary)
 	warningStyle := lipgloss.NewStyle().Bold(true).Foreground(styles.Warning)
 
-	// Check if tmux is installed
-	if !isTmuxInstalled() {
+	// Check if tmux is installed; agents can do without it on the PTY backend
+	_, usesTmux := p.agentBackend().(tmuxBackend)
+	if !isTmuxInstalled() && usesTmux {
 		lines = append(lines, warningStyle.Render("⚠ tmux Required"))
 		lines = append(lines, "")
 		lines = append(lines, dimText("The project shell requires tmux to be installed."))
@@ -725,6 +739,59 @@ func (p *Plugin) renderCommitStatusHeader(width int) string {
 	return headerStyle.Render(sb.String())
 }
 
+// renderConflictHeader renders the conflicts predicted between a worktree and
+// the others, with the conflicting line ranges of each file.
+func (p *Plugin) renderConflictHeader(worktreeName string, width int) string {
+	var lines []string
+	for _, c := range p.conflicts {
+		if len(c.Worktrees) != 2 {
+			continue
+		}
+		var other string
+		switch worktreeName {
+		case c.Worktrees[0]:
+			other = c.Worktrees[1]
+		case c.Worktrees[1]:
+			other = c.Worktrees[0]
+		default:
+			continue
+		}
+		for _, f := range c.Details {
+			where := formatHunkRanges(f.Hunks)
+			if where == "" {
+				where = f.Kind
+			}
+			lines = append(lines, fmt.Sprintf("%s %s %s", lipgloss.NewStyle().Foreground(styles.Warning).Render(other), f.Path, dimText(where)))
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+
+	headerStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.Warning).
+		Padding(0, 1).
+		Width(width - 2)
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(styles.Warning)
+
+	maxLines := 5
+	shown := lines
+	if len(shown) > maxLines {
+		shown = shown[:maxLines]
+	}
+	for i, line := range shown {
+		if lipgloss.Width(line) > width-6 {
+			shown[i] = p.truncateCache.Truncate(line, width-6, "…")
+		}
+	}
+	body := titleStyle.Render("⚠ Predicted conflicts") + "\n" + strings.Join(shown, "\n")
+	if len(lines) > maxLines {
+		body += "\n" + dimText(fmt.Sprintf("  ... and %d more", len(lines)-maxLines))
+	}
+	return headerStyle.Render(body)
+}
+
 // renderMainWorktreeView renders a helpful view when the main worktree is selected.
 func (p *Plugin) renderMainWorktreeView(width, height int) string {
 	var lines []string
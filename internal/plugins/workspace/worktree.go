@@ -12,6 +12,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/marcus/sidecar/internal/app"
+	"github.com/marcus/sidecar/internal/plugins/gitstatus"
 	"github.com/marcus/sidecar/internal/tdroot"
 )
 
@@ -102,8 +103,17 @@ func findMainWorktreeFromDeleted(deletedPath string) string {
 
 // listWorktrees parses git worktree list --porcelain output.
 func (p *Plugin) listWorktrees() ([]*Worktree, error) {
+	return ListWorktrees(p.ctx.WorkDir, true)
+}
+
+// ListWorktrees returns the worktrees of the repository containing workDir,
+// main worktree first. Missing worktrees whose branch is also gone are
+// excluded, and pruned from git if prune is set; read-only callers pass
+// false. Sidecar metadata (task, agent, PR, base) is not loaded; use
+// LoadWorktreeMetadata for that.
+func ListWorktrees(workDir string, prune bool) ([]*Worktree, error) {
 	cmd := exec.Command("git", "worktree", "list", "--porcelain")
-	cmd.Dir = p.ctx.WorkDir
+	cmd.Dir = workDir
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("git worktree list: %w", err)
@@ -111,9 +121,9 @@ func (p *Plugin) listWorktrees() ([]*Worktree, error) {
 
 	// Get the actual main worktree path (the original repo), not the current workdir
 	// This ensures IsMain is set correctly regardless of which worktree we're in
-	mainRepoPath := app.GetMainWorktreePath(p.ctx.WorkDir)
+	mainRepoPath := app.GetMainWorktreePath(workDir)
 	if mainRepoPath == "" {
-		mainRepoPath = p.ctx.WorkDir // Fallback if detection fails
+		mainRepoPath = workDir // Fallback if detection fails
 	}
 
 	worktrees, err := parseWorktreeList(string(output), mainRepoPath)
@@ -136,7 +146,7 @@ func (p *Plugin) listWorktrees() ([]*Worktree, error) {
 
 		if wt.IsMissing {
 			// If branch is also gone, auto-prune and exclude from list
-			if !branchExists(p.ctx.WorkDir, wt.Branch) {
+			if !branchExists(workDir, wt.Branch) {
 				needsPrune = true
 				continue // exclude from returned list
 			}
@@ -146,8 +156,8 @@ func (p *Plugin) listWorktrees() ([]*Worktree, error) {
 		filtered = append(filtered, wt)
 	}
 
-	if needsPrune {
-		_ = doWorktreePrune(p.ctx.WorkDir)
+	if needsPrune && prune {
+		_ = doWorktreePrune(workDir)
 	}
 
 	return filtered, nil
@@ -234,6 +244,7 @@ func (p *Plugin) createWorktree() tea.Cmd {
 	agentType := p.createAgentType
 	skipPerms := p.createSkipPermissions
 	prompt := p.getSelectedPrompt()
+	budget, _ := ParseBudget(p.createBudgetInput.Value()) // validated by validateAndCreateWorktree
 
 	// Debug log to trace taskID flow
 	if p.ctx != nil && p.ctx.Logger != nil {
@@ -247,13 +258,22 @@ func (p *Plugin) createWorktree() tea.Cmd {
 	}
 
 	return func() tea.Msg {
-		wt, err := p.doCreateWorktree(name, baseBranch, taskID, taskTitle, agentType)
-		return CreateDoneMsg{Worktree: wt, AgentType: agentType, SkipPerms: skipPerms, Prompt: prompt, Err: err}
+		wt, setup, err := p.doCreateWorktree(name, baseBranch, taskID, taskTitle, agentType)
+		if err == nil && !budget.IsZero() {
+			budget.Since = time.Now()
+			if serr := saveBudget(wt.Path, &budget); serr != nil {
+				p.ctx.Logger.Warn("failed to save budget", "path", wt.Path, "error", serr)
+			} else {
+				wt.Budget = &budget
+			}
+		}
+		return CreateDoneMsg{Worktree: wt, AgentType: agentType, SkipPerms: skipPerms, Prompt: prompt, Setup: setup, Err: err}
 	}
 }
 
-// doCreateWorktree performs the actual worktree creation.
-func (p *Plugin) doCreateWorktree(name, baseBranch, taskID, taskTitle string, agentType AgentType) (*Worktree, error) {
+// doCreateWorktree performs the actual worktree creation. The setup report
+// holds the results of the post-creation setup steps.
+func (p *Plugin) doCreateWorktree(name, baseBranch, taskID, taskTitle string, agentType AgentType) (*Worktree, *SetupReport, error) {
 	// Default base branch to current branch if not specified
 	if baseBranch == "" {
 		baseBranch = "HEAD"
@@ -280,7 +300,7 @@ func (p *Plugin) doCreateWorktree(name, baseBranch, taskID, taskTitle string, ag
 	cmd := exec.Command("git", args...)
 	cmd.Dir = p.ctx.WorkDir
 	if output, err := cmd.CombinedOutput(); err != nil {
-		return nil, fmt.Errorf("git worktree add: %s: %w", strings.TrimSpace(string(output)), err)
+		return nil, nil, fmt.Errorf("git worktree add: %s: %w", strings.TrimSpace(string(output)), err)
 	}
 
 	// Create .td-root file pointing to main repo for td database sharing
@@ -335,13 +355,11 @@ func (p *Plugin) doCreateWorktree(name, baseBranch, taskID, taskTitle string, ag
 		p.ctx.Logger.Warn("failed to save base branch", "path", wtPath, "error", err)
 	}
 
-	// Run post-creation setup (env files, symlinks, setup script)
-	if err := p.setupWorktree(wtPath, name); err != nil {
-		p.ctx.Logger.Warn("workspace setup had errors", "path", wtPath, "error", err)
-		// Don't fail creation for setup errors
-	}
+	// Run post-creation setup steps (env files, symlinks, templates, commands).
+	// Don't fail creation for setup errors; the results are shown instead.
+	setup := p.setupWorktree(wt)
 
-	return wt, nil
+	return wt, setup, nil
 }
 
 // doDeleteWorktree removes a worktree. When isMissing is true, uses prune
@@ -453,18 +471,15 @@ func deleteBranch(workdir, branch string) error {
 	if isMainBranch(workdir, branch) {
 		return fmt.Errorf("refusing to delete main branch %q", branch)
 	}
-	// Try safe delete first
-	cmd := exec.Command("git", "branch", "-d", branch)
-	cmd.Dir = workdir
-	if err := cmd.Run(); err == nil {
+	// Try safe delete first; both record the branch tip in the git
+	// plugin's operation journal so the deletion can be undone
+	if err := gitstatus.DeleteBranch(workdir, branch); err == nil {
 		return nil
 	}
 
 	// Try force delete
-	cmd = exec.Command("git", "branch", "-D", branch)
-	cmd.Dir = workdir
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("delete branch: %s: %w", strings.TrimSpace(string(output)), err)
+	if err := gitstatus.ForceDeleteBranch(workdir, branch); err != nil {
+		return fmt.Errorf("delete branch: %w", err)
 	}
 	return nil
 }
@@ -552,6 +567,7 @@ const sidecarTaskFile = ".sidecar-task"
 const sidecarAgentFile = ".sidecar-agent"
 const sidecarPRFile = ".sidecar-pr"
 const sidecarBaseFile = ".sidecar-base"
+const sidecarBudgetFile = ".sidecar-budget"
 
 // saveBaseBranch persists the base branch to the worktree.
 func saveBaseBranch(worktreePath string, branch string) error {
@@ -574,6 +590,16 @@ func loadBaseBranch(worktreePath string) string {
 	return strings.TrimSpace(string(content))
 }
 
+// LoadWorktreeMetadata populates the sidecar-managed fields of wt (linked
+// task, chosen agent, PR URL, base branch and budget) from its dotfiles.
+func LoadWorktreeMetadata(wt *Worktree) {
+	wt.TaskID = loadTaskLink(wt.Path)
+	wt.ChosenAgentType = loadAgentType(wt.Path)
+	wt.PRURL = loadPRURL(wt.Path)
+	wt.BaseBranch = loadBaseBranch(wt.Path)
+	wt.Budget = loadBudget(wt.Path)
+}
+
 // loadTaskLink reads the linked task ID from the .sidecar-task file.
 func loadTaskLink(worktreePath string) string {
 	taskPath := filepath.Join(worktreePath, sidecarTaskFile)
@@ -906,42 +932,45 @@ func SanitizeBranchName(name string) string {
 // loadTaskDetails fetches full task details from td.
 func (p *Plugin) loadTaskDetails(taskID string) tea.Cmd {
 	return func() tea.Msg {
-		cmd := exec.Command("td", "show", taskID, "--json")
-		cmd.Dir = p.ctx.WorkDir
-		output, err := cmd.Output()
-		if err != nil {
-			return TaskDetailsLoadedMsg{TaskID: taskID, Err: fmt.Errorf("td show: %w", err)}
-		}
-
-		var details struct {
-			ID          string `json:"id"`
-			Title       string `json:"title"`
-			Status      string `json:"status"`
-			Priority    string `json:"priority"`
-			Type        string `json:"type"`
-			Description string `json:"description"`
-			Acceptance  string `json:"acceptance"`
-			CreatedAt   string `json:"created_at"`
-			UpdatedAt   string `json:"updated_at"`
-		}
-
-		if err := json.Unmarshal(output, &details); err != nil {
-			return TaskDetailsLoadedMsg{TaskID: taskID, Err: fmt.Errorf("parse task json: %w", err)}
-		}
+		details, err := fetchTaskDetails(p.ctx.WorkDir, taskID)
+		return TaskDetailsLoadedMsg{TaskID: taskID, Details: details, Err: err}
+	}
+}
 
-		return TaskDetailsLoadedMsg{
-			TaskID: taskID,
-			Details: &TaskDetails{
-				ID:          details.ID,
-				Title:       details.Title,
-				Status:      details.Status,
-				Priority:    details.Priority,
-				Type:        details.Type,
-				Description: details.Description,
-				Acceptance:  details.Acceptance,
-				CreatedAt:   details.CreatedAt,
-				UpdatedAt:   details.UpdatedAt,
-			},
-		}
+// fetchTaskDetails runs td show for a task.
+func fetchTaskDetails(workDir, taskID string) (*TaskDetails, error) {
+	cmd := exec.Command("td", "show", taskID, "--json")
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("td show: %w", err)
 	}
+
+	var details struct {
+		ID          string `json:"id"`
+		Title       string `json:"title"`
+		Status      string `json:"status"`
+		Priority    string `json:"priority"`
+		Type        string `json:"type"`
+		Description string `json:"description"`
+		Acceptance  string `json:"acceptance"`
+		CreatedAt   string `json:"created_at"`
+		UpdatedAt   string `json:"updated_at"`
+	}
+
+	if err := json.Unmarshal(output, &details); err != nil {
+		return nil, fmt.Errorf("parse task json: %w", err)
+	}
+
+	return &TaskDetails{
+		ID:          details.ID,
+		Title:       details.Title,
+		Status:      details.Status,
+		Priority:    details.Priority,
+		Type:        details.Type,
+		Description: details.Description,
+		Acceptance:  details.Acceptance,
+		CreatedAt:   details.CreatedAt,
+		UpdatedAt:   details.UpdatedAt,
+	}, nil
 }
@@ -0,0 +1,79 @@
+package pricing
+
+import "time"
+
+// DefaultVersion identifies the built-in pricing table. Bump it whenever
+// rates are added or changed.
+const DefaultVersion = "2026-02-01"
+
+// date parses a YYYY-MM-DD literal for the built-in table.
+func date(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// rates builds an always-effective price entry.
+func rates(input, output, cacheRead, cacheWrite float64) Price {
+	return Price{Rates: Rates{Input: input, Output: output, CacheRead: cacheRead, CacheWrite: cacheWrite}}
+}
+
+// ratesSince builds a price entry effective from the given date.
+func ratesSince(since string, input, output, cacheRead, cacheWrite float64) Price {
+	p := rates(input, output, cacheRead, cacheWrite)
+	p.Since = date(since)
+	return p
+}
+
+// Default returns a fresh copy of the built-in pricing table. Rates are list
+// prices in USD per million tokens. Providers that don't bill cache writes
+// separately use the input rate for CacheWrite.
+func Default() *Table {
+	t := NewTable(DefaultVersion)
+
+	// Anthropic
+	t.Set("opus", rates(15, 75, 1.50, 18.75))
+	t.Set("opus-4-5", rates(5, 25, 0.50, 6.25))
+	t.Set("sonnet", rates(3, 15, 0.30, 3.75))
+	t.Set("haiku", rates(0.25, 1.25, 0.03, 0.30))
+	t.Set("3-5-haiku", rates(0.80, 4, 0.08, 1))
+	t.Set("haiku-4-5", rates(1, 5, 0.10, 1.25))
+
+	// OpenAI
+	t.Set("gpt-4", rates(30, 60, 30, 30))
+	t.Set("gpt-4-turbo", rates(10, 30, 10, 10))
+	t.Set("gpt-4o", rates(2.50, 10, 1.25, 2.50))
+	t.Set("gpt-4o-mini", rates(0.15, 0.60, 0.075, 0.15))
+	t.Set("gpt-4.1", rates(2, 8, 0.50, 2))
+	t.Set("gpt-4.1-mini", rates(0.40, 1.60, 0.10, 0.40))
+	t.Set("gpt-4.1-nano", rates(0.10, 0.40, 0.025, 0.10))
+	t.Set("gpt-5", rates(1.25, 10, 0.125, 1.25))
+	t.Set("gpt-5-mini", rates(0.25, 2, 0.025, 0.25))
+	t.Set("gpt-5-nano", rates(0.05, 0.40, 0.005, 0.05))
+	t.Set("codex-mini", rates(1.50, 6, 0.375, 1.50))
+	t.Set("o1", rates(15, 60, 7.50, 15))
+	t.Set("o1-mini", rates(1.10, 4.40, 0.55, 1.10))
+	t.Set("o3",
+		rates(10, 40, 2.50, 10),
+		ratesSince("2025-06-10", 2, 8, 0.50, 2),
+	)
+	t.Set("o3-mini", rates(1.10, 4.40, 0.55, 1.10))
+	t.Set("o4-mini", rates(1.10, 4.40, 0.275, 1.10))
+
+	// Google
+	t.Set("gemini-1.5-flash", rates(0.075, 0.30, 0.01875, 0.075))
+	t.Set("gemini-1.5-pro", rates(1.25, 5, 0.3125, 1.25))
+	t.Set("gemini-2.0-flash", rates(0.10, 0.40, 0.025, 0.10))
+	t.Set("gemini-2.5-flash", rates(0.30, 2.50, 0.075, 0.30))
+	t.Set("gemini-2.5-pro", rates(1.25, 10, 0.31, 1.25))
+	t.Set("gemini-3-flash", rates(0.50, 3, 0.05, 0.50))
+	t.Set("gemini-3-pro", rates(2, 12, 0.20, 2))
+
+	// DeepSeek
+	t.Set("deepseek", rates(0.27, 1.10, 0.07, 0.27))
+	t.Set("deepseek-reasoner", rates(0.55, 2.19, 0.14, 0.55))
+
+	return t
+}
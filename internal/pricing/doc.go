@@ -0,0 +1,5 @@
+// Package pricing holds the model pricing table used to estimate session
+// costs. The built-in table is versioned and dated so historical sessions are
+// priced at the rates in effect when they ran; users can override or extend
+// it via the "pricing" section of config.json.
+package pricing
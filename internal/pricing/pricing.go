@@ -0,0 +1,231 @@
+package pricing
+
+import (
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/marcus/sidecar/internal/config"
+)
+
+// Rates are prices in USD per million tokens.
+type Rates struct {
+	Input      float64 // Uncached input tokens
+	Output     float64 // Output tokens (including reasoning)
+	CacheRead  float64 // Input tokens served from the prompt cache
+	CacheWrite float64 // Input tokens written to the prompt cache
+}
+
+// Price is a set of rates that took effect on Since. A zero Since means the
+// rates apply to all dates before the next entry.
+type Price struct {
+	Since time.Time
+	Rates
+}
+
+// Usage is a token count breakdown to be priced. Input excludes cached tokens.
+type Usage struct {
+	Input      int
+	Output     int
+	CacheRead  int
+	CacheWrite int
+}
+
+// Total returns the sum of all token counts.
+func (u Usage) Total() int {
+	return u.Input + u.Output + u.CacheRead + u.CacheWrite
+}
+
+// Table maps model name patterns to dated price histories.
+type Table struct {
+	Version string
+	models  map[string][]Price // pattern -> history sorted by Since ascending
+}
+
+// NewTable creates an empty pricing table.
+func NewTable(version string) *Table {
+	return &Table{Version: version, models: make(map[string][]Price)}
+}
+
+// Set replaces the price history for pattern. Patterns are matched
+// case-insensitively against model names (see Lookup).
+func (t *Table) Set(pattern string, history ...Price) {
+	h := make([]Price, len(history))
+	copy(h, history)
+	sort.SliceStable(h, func(i, j int) bool { return h[i].Since.Before(h[j].Since) })
+	t.models[strings.ToLower(pattern)] = h
+}
+
+// Patterns returns the table's model patterns in sorted order.
+func (t *Table) Patterns() []string {
+	patterns := make([]string, 0, len(t.models))
+	for p := range t.models {
+		patterns = append(patterns, p)
+	}
+	sort.Strings(patterns)
+	return patterns
+}
+
+// Clone returns a copy of the table that can be modified independently.
+func (t *Table) Clone() *Table {
+	c := NewTable(t.Version)
+	for p, h := range t.models {
+		c.models[p] = append([]Price(nil), h...)
+	}
+	return c
+}
+
+// Lookup returns the rates for model in effect at the given time. A zero time
+// selects the latest rates. The longest pattern that occurs in the model name
+// at a word boundary wins, so "gpt-4o-mini" beats "gpt-4o" and "opus-4-5"
+// beats "opus". Returns false when no pattern matches.
+func (t *Table) Lookup(model string, at time.Time) (Rates, bool) {
+	history := t.match(model)
+	if len(history) == 0 {
+		return Rates{}, false
+	}
+	if at.IsZero() {
+		return history[len(history)-1].Rates, true
+	}
+	rates := history[0].Rates
+	for _, p := range history[1:] {
+		if p.Since.After(at) {
+			break
+		}
+		rates = p.Rates
+	}
+	return rates, true
+}
+
+// Cost estimates the dollar cost of usage for model at the given time.
+// Returns false (and zero cost) when the model has no known price.
+func (t *Table) Cost(model string, at time.Time, u Usage) (float64, bool) {
+	r, ok := t.Lookup(model, at)
+	if !ok {
+		return 0, false
+	}
+	return (float64(u.Input)*r.Input +
+		float64(u.Output)*r.Output +
+		float64(u.CacheRead)*r.CacheRead +
+		float64(u.CacheWrite)*r.CacheWrite) / 1_000_000, true
+}
+
+// match returns the history for the longest pattern matching model.
+func (t *Table) match(model string) []Price {
+	model = strings.ToLower(strings.TrimSpace(model))
+	if model == "" {
+		return nil
+	}
+	var best string
+	for pattern := range t.models {
+		if len(pattern) <= len(best) || !containsAtBoundary(model, pattern) {
+			continue
+		}
+		best = pattern
+	}
+	if best == "" {
+		return nil
+	}
+	return t.models[best]
+}
+
+// containsAtBoundary reports whether pattern occurs in s starting at the
+// beginning or right after a non-alphanumeric separator ("-", "/", ".", ":").
+func containsAtBoundary(s, pattern string) bool {
+	for i := 0; i+len(pattern) <= len(s); {
+		idx := strings.Index(s[i:], pattern)
+		if idx < 0 {
+			return false
+		}
+		pos := i + idx
+		if pos == 0 || !isAlnum(s[pos-1]) {
+			return true
+		}
+		i = pos + 1
+	}
+	return false
+}
+
+func isAlnum(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= '0' && c <= '9'
+}
+
+var (
+	mu      sync.RWMutex
+	current = Default()
+)
+
+// Init installs the built-in table merged with the user's config overrides.
+// Should be called once at startup after config is loaded.
+func Init(cfg *config.Config) {
+	t := Default()
+	if cfg != nil {
+		applyOverrides(t, cfg.Pricing)
+	}
+	mu.Lock()
+	current = t
+	mu.Unlock()
+}
+
+// applyOverrides replaces built-in histories with user-configured ones.
+func applyOverrides(t *Table, pc config.PricingConfig) {
+	if len(pc.Models) == 0 {
+		return
+	}
+	t.Version += "+user"
+	for pattern, entries := range pc.Models {
+		history := make([]Price, 0, len(entries))
+		for _, e := range entries {
+			var since time.Time
+			if e.Since != "" {
+				parsed, err := time.Parse("2006-01-02", e.Since)
+				if err != nil {
+					slog.Warn("pricing: invalid since date", "model", pattern, "since", e.Since, "err", err)
+					continue
+				}
+				since = parsed
+			}
+			history = append(history, Price{Since: since, Rates: withFallbacks(Rates{
+				Input:      e.Input,
+				Output:     e.Output,
+				CacheRead:  e.CacheRead,
+				CacheWrite: e.CacheWrite,
+			})})
+		}
+		if len(history) > 0 {
+			t.Set(pattern, history...)
+		}
+	}
+}
+
+// withFallbacks prices cache reads and writes at the input rate when the
+// user leaves them unset.
+func withFallbacks(r Rates) Rates {
+	if r.CacheRead == 0 {
+		r.CacheRead = r.Input
+	}
+	if r.CacheWrite == 0 {
+		r.CacheWrite = r.Input
+	}
+	return r
+}
+
+// Current returns the active pricing table.
+func Current() *Table {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// Cost prices usage with the active table. See Table.Cost.
+func Cost(model string, at time.Time, u Usage) (float64, bool) {
+	return Current().Cost(model, at, u)
+}
+
+// Known reports whether the active table has a price for model.
+func Known(model string) bool {
+	_, ok := Current().Lookup(model, time.Time{})
+	return ok
+}
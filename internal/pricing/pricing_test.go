@@ -0,0 +1,120 @@
+package pricing
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/marcus/sidecar/internal/config"
+)
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestLookup_LongestMatchWins(t *testing.T) {
+	tbl := Default()
+	tests := []struct {
+		model     string
+		wantInput float64
+	}{
+		{"claude-opus-4-1-20250805", 15},
+		{"claude-opus-4-5-20251101", 5},
+		{"claude-sonnet-4-5-20250929", 3},
+		{"claude-3-5-haiku-latest", 0.80},
+		{"claude-haiku-4-5", 1},
+		{"gpt-4o-mini", 0.15},
+		{"gpt-4o-2024-08-06", 2.50},
+		{"gpt-5-codex", 1.25},
+		{"openai/o4-mini", 1.10},
+		{"GEMINI-2.5-PRO", 1.25},
+	}
+	for _, tt := range tests {
+		r, ok := tbl.Lookup(tt.model, time.Time{})
+		if !ok {
+			t.Errorf("Lookup(%q) not found", tt.model)
+			continue
+		}
+		if !approx(r.Input, tt.wantInput) {
+			t.Errorf("Lookup(%q).Input = %v, want %v", tt.model, r.Input, tt.wantInput)
+		}
+	}
+}
+
+func TestLookup_Unknown(t *testing.T) {
+	tbl := Default()
+	for _, model := range []string{"", "llama-3-70b", "mystery", "proto3"} {
+		if _, ok := tbl.Lookup(model, time.Time{}); ok {
+			t.Errorf("Lookup(%q) should not match", model)
+		}
+	}
+}
+
+func TestLookup_DatedPrices(t *testing.T) {
+	tbl := Default()
+	before, _ := tbl.Lookup("o3", time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC))
+	after, _ := tbl.Lookup("o3", time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC))
+	latest, _ := tbl.Lookup("o3", time.Time{})
+	if before.Input != 10 || after.Input != 2 || latest.Input != 2 {
+		t.Errorf("o3 input rates = %v/%v/%v, want 10/2/2", before.Input, after.Input, latest.Input)
+	}
+}
+
+func TestCost(t *testing.T) {
+	tbl := Default()
+	cost, ok := tbl.Cost("claude-sonnet-4-5", time.Time{}, Usage{
+		Input:      1_000_000,
+		Output:     1_000_000,
+		CacheRead:  1_000_000,
+		CacheWrite: 1_000_000,
+	})
+	if !ok {
+		t.Fatal("sonnet should be priced")
+	}
+	// 3 + 15 + 0.30 + 3.75
+	if !approx(cost, 22.05) {
+		t.Errorf("cost = %v, want 22.05", cost)
+	}
+
+	if cost, ok := tbl.Cost("unknown-model", time.Time{}, Usage{Input: 1000}); ok || cost != 0 {
+		t.Errorf("unknown model cost = %v, %v; want 0, false", cost, ok)
+	}
+}
+
+func TestInit_ConfigOverrides(t *testing.T) {
+	t.Cleanup(func() { Init(nil) })
+
+	cfg := config.Default()
+	cfg.Pricing.Models = map[string][]config.ModelPrice{
+		"sonnet":    {{Input: 1, Output: 2}},
+		"in-house":  {{Input: 4, Output: 8, CacheRead: 0.4}},
+		"bad-dates": {{Since: "not-a-date", Input: 1, Output: 1}},
+		"gpt-5": {
+			{Input: 1.25, Output: 10},
+			{Since: "2026-01-01", Input: 1, Output: 8},
+		},
+	}
+	Init(cfg)
+
+	r, ok := Current().Lookup("claude-sonnet-4-5", time.Time{})
+	if !ok || r.Input != 1 || r.Output != 2 || r.CacheRead != 1 {
+		t.Errorf("sonnet override = %+v, %v", r, ok)
+	}
+	if !Known("acme-in-house-v2") {
+		t.Error("custom model should be known")
+	}
+	if Known("bad-dates") {
+		t.Error("entry with only invalid dates should be skipped")
+	}
+	old, _ := Current().Lookup("gpt-5", time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC))
+	if old.Input != 1.25 {
+		t.Errorf("gpt-5 before override date = %v, want 1.25", old.Input)
+	}
+	if Current().Version != DefaultVersion+"+user" {
+		t.Errorf("version = %q", Current().Version)
+	}
+	// Built-in entries not overridden are still present
+	if !Known("claude-opus-4-5") {
+		t.Error("built-in entries should survive overrides")
+	}
+}
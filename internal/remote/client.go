@@ -0,0 +1,125 @@
+package remote
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/marcus/sidecar/internal/event"
+)
+
+// Client is a connection to a sidecar's remote control socket.
+type Client struct {
+	conn    net.Conn
+	scanner *bufio.Scanner
+	enc     *json.Encoder
+	nextID  int
+	pending []event.Event // events read while waiting for a response
+}
+
+// Dial connects to the socket at path.
+func Dial(path string) (*Client, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	return &Client{conn: conn, scanner: scanner, enc: json.NewEncoder(conn)}, nil
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Call invokes method with params and decodes the result into result, which
+// may be nil. Errors returned by sidecar are *Error.
+func (c *Client) Call(method string, params, result any) error {
+	c.nextID++
+	id := json.RawMessage(strconv.Itoa(c.nextID))
+	req := wireRequest{JSONRPC: "2.0", ID: id, Method: method}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		req.Params = data
+	}
+	if err := c.enc.Encode(req); err != nil {
+		return err
+	}
+
+	for {
+		msg, err := c.read()
+		if err != nil {
+			return err
+		}
+		if msg.Method == "event" {
+			var e event.Event
+			if err := json.Unmarshal(msg.Params, &e); err == nil {
+				c.pending = append(c.pending, e)
+			}
+			continue
+		}
+		if string(msg.ID) != string(id) {
+			continue
+		}
+		if msg.Error != nil {
+			return msg.Error
+		}
+		if result == nil || len(msg.Result) == 0 {
+			return nil
+		}
+		return json.Unmarshal(msg.Result, result)
+	}
+}
+
+// NextEvent blocks until the next event notification arrives. Subscribe
+// first with Call("events.subscribe", ...).
+func (c *Client) NextEvent() (event.Event, error) {
+	if len(c.pending) > 0 {
+		e := c.pending[0]
+		c.pending = c.pending[1:]
+		return e, nil
+	}
+	for {
+		msg, err := c.read()
+		if err != nil {
+			return event.Event{}, err
+		}
+		if msg.Method != "event" {
+			continue
+		}
+		var e event.Event
+		if err := json.Unmarshal(msg.Params, &e); err != nil {
+			return event.Event{}, err
+		}
+		return e, nil
+	}
+}
+
+// clientMessage is a response or notification as read by a client.
+type clientMessage struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *Error          `json:"error"`
+}
+
+func (c *Client) read() (clientMessage, error) {
+	if !c.scanner.Scan() {
+		if err := c.scanner.Err(); err != nil {
+			return clientMessage{}, err
+		}
+		return clientMessage{}, fmt.Errorf("connection closed")
+	}
+	var msg clientMessage
+	if err := json.Unmarshal(c.scanner.Bytes(), &msg); err != nil {
+		return clientMessage{}, fmt.Errorf("invalid response: %w", err)
+	}
+	return msg, nil
+}
@@ -0,0 +1,6 @@
+// Package remote serves sidecar's remote control API: newline-delimited
+// JSON-RPC 2.0 on a Unix domain socket. Editor integrations and agent hooks
+// use it to switch plugins, open files, drive workspace agents and stream
+// events from the event bus. Requests are handed to the running program as
+// messages; whichever part of the UI owns the method replies.
+package remote
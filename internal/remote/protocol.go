@@ -0,0 +1,114 @@
+package remote
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// JSON-RPC 2.0 error codes.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeFailed         = -32000 // method ran but failed
+)
+
+// Error is a JSON-RPC error.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// MethodNotFound returns the error for a method nothing serves.
+func MethodNotFound(method string) *Error {
+	return &Error{Code: CodeMethodNotFound, Message: fmt.Sprintf("unknown method %q", method)}
+}
+
+// InvalidParams returns an invalid params error.
+func InvalidParams(format string, args ...any) *Error {
+	return &Error{Code: CodeInvalidParams, Message: fmt.Sprintf(format, args...)}
+}
+
+// Request is a remote call delivered to the program as a tea.Msg. The
+// handler that owns Method must call Reply exactly once, either while
+// handling the message or later from a command.
+type Request struct {
+	Method string
+	Params json.RawMessage
+
+	once  sync.Once
+	reply chan response
+}
+
+type response struct {
+	result any
+	err    error
+}
+
+// NewRequest creates a request for method with raw JSON params.
+func NewRequest(method string, params json.RawMessage) *Request {
+	return &Request{Method: method, Params: params, reply: make(chan response, 1)}
+}
+
+// Decode unmarshals the request params into v. Missing params leave v
+// unchanged.
+func (r *Request) Decode(v any) error {
+	if len(r.Params) == 0 || string(r.Params) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(r.Params, v); err != nil {
+		return InvalidParams("invalid params: %v", err)
+	}
+	return nil
+}
+
+// Reply answers the request. Only the first reply counts.
+func (r *Request) Reply(result any, err error) {
+	r.once.Do(func() {
+		r.reply <- response{result: result, err: err}
+	})
+}
+
+// Wait blocks until the request is answered or the timeout passes.
+func (r *Request) Wait(timeout time.Duration) (any, error) {
+	select {
+	case resp := <-r.reply:
+		return resp.result, resp.err
+	case <-time.After(timeout):
+		return nil, &Error{Code: CodeFailed, Message: fmt.Sprintf("%s: no reply within %s", r.Method, timeout)}
+	}
+}
+
+// wireRequest is a JSON-RPC request or notification as read from a client.
+type wireRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// wireResponse is a JSON-RPC response. Notifications sent to clients reuse
+// it with Method and Params set and no ID.
+type wireResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  any             `json:"params,omitempty"`
+	Result  any             `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// toWireError converts a handler error into a JSON-RPC error.
+func toWireError(err error) *Error {
+	if e, ok := err.(*Error); ok {
+		return e
+	}
+	return &Error{Code: CodeFailed, Message: err.Error()}
+}
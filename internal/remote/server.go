@@ -0,0 +1,282 @@
+package remote
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/marcus/sidecar/internal/event"
+)
+
+// EnvSocket is the environment variable that carries the socket path to
+// child processes such as agents and their hooks.
+const EnvSocket = "SIDECAR_SOCKET"
+
+// DefaultTimeout is how long the server waits for the program to answer.
+const DefaultTimeout = 10 * time.Second
+
+// maxLineSize bounds a single request line.
+const maxLineSize = 1 << 20
+
+// SocketDir returns the directory that holds sidecar's sockets.
+func SocketDir(configDir string) string {
+	return filepath.Join(configDir, "sockets")
+}
+
+// DefaultSocketPath returns the socket path for the sidecar with the given
+// process ID.
+func DefaultSocketPath(configDir string, pid int) string {
+	return filepath.Join(SocketDir(configDir), "sidecar-"+strconv.Itoa(pid)+".sock")
+}
+
+// FindSockets returns the sockets in configDir that accept connections.
+func FindSockets(configDir string) []string {
+	paths, _ := filepath.Glob(filepath.Join(SocketDir(configDir), "sidecar-*.sock"))
+	var live []string
+	for _, path := range paths {
+		if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			_ = conn.Close()
+			live = append(live, path)
+		}
+	}
+	return live
+}
+
+// Server accepts remote control connections on a Unix socket.
+type Server struct {
+	path    string
+	ln      net.Listener
+	bus     *event.Dispatcher
+	logger  *slog.Logger
+	timeout time.Duration
+
+	mu     sync.Mutex
+	conns  map[net.Conn]struct{}
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// Listen creates the socket at path. A stale socket left by a crashed
+// sidecar is replaced; one that still accepts connections is an error. bus
+// may be nil, in which case events.subscribe fails.
+func Listen(path string, bus *event.Dispatcher, logger *slog.Logger) (*Server, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("create socket dir: %w", err)
+	}
+	if _, err := os.Stat(path); err == nil {
+		if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("socket %s is in use", path)
+		}
+		_ = os.Remove(path)
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		_ = ln.Close()
+		return nil, err
+	}
+	return &Server{
+		path:    path,
+		ln:      ln,
+		bus:     bus,
+		logger:  logger,
+		timeout: DefaultTimeout,
+		conns:   make(map[net.Conn]struct{}),
+	}, nil
+}
+
+// Path returns the socket path.
+func (s *Server) Path() string {
+	return s.path
+}
+
+// Serve accepts connections until Close and hands each request to send,
+// typically tea.Program.Send. It returns nil after Close.
+func (s *Server) Serve(send func(*Request)) error {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			_ = conn.Close()
+			return nil
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+
+		go func() {
+			defer s.wg.Done()
+			s.serveConn(conn, send)
+		}()
+	}
+}
+
+// Close stops the server, disconnects clients and removes the socket.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	err := s.ln.Close()
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	_ = os.Remove(s.path)
+	return err
+}
+
+// connWriter serializes writes to a connection shared by responses and
+// event notifications.
+type connWriter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func (w *connWriter) write(v wireResponse) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	v.JSONRPC = "2.0"
+	return w.enc.Encode(v)
+}
+
+// serveConn reads requests from one connection until it closes.
+func (s *Server) serveConn(conn net.Conn, send func(*Request)) {
+	w := &connWriter{enc: json.NewEncoder(conn)}
+	var subs []subscription
+	defer func() {
+		for _, sub := range subs {
+			s.bus.Unsubscribe(sub.topic, sub.ch)
+		}
+		_ = conn.Close()
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var req wireRequest
+		if err := json.Unmarshal(line, &req); err != nil {
+			_ = w.write(wireResponse{ID: json.RawMessage("null"), Error: &Error{Code: CodeParseError, Message: "parse error: " + err.Error()}})
+			continue
+		}
+		if req.Method == "" {
+			_ = w.write(wireResponse{ID: idOrNull(req.ID), Error: &Error{Code: CodeInvalidRequest, Message: "missing method"}})
+			continue
+		}
+
+		var result any
+		var err error
+		switch req.Method {
+		case "ping":
+			result = "pong"
+		case "events.subscribe":
+			var added []subscription
+			added, result, err = s.subscribe(req.Params, w)
+			subs = append(subs, added...)
+		default:
+			r := NewRequest(req.Method, req.Params)
+			send(r)
+			result, err = r.Wait(s.timeout)
+		}
+
+		if len(req.ID) == 0 {
+			continue // notification: no response
+		}
+		resp := wireResponse{ID: req.ID}
+		if err != nil {
+			resp.Error = toWireError(err)
+		} else {
+			if result == nil {
+				result = struct{}{}
+			}
+			resp.Result = result
+		}
+		if err := w.write(resp); err != nil {
+			return
+		}
+	}
+	if err := scanner.Err(); err != nil && !errors.Is(err, net.ErrClosed) {
+		s.logger.Debug("remote: connection closed", "err", err)
+	}
+}
+
+// DefaultTopics are the topics events.subscribe streams when none are given.
+var DefaultTopics = []string{event.TopicUI, event.TopicAgents}
+
+type subscription struct {
+	topic string
+	ch    <-chan event.Event
+}
+
+// subscribe streams events on the requested topics to w as "event"
+// notifications until the connection closes.
+func (s *Server) subscribe(params json.RawMessage, w *connWriter) ([]subscription, any, error) {
+	if s.bus == nil {
+		return nil, nil, &Error{Code: CodeFailed, Message: "events are not available"}
+	}
+	var p struct {
+		Topics []string `json:"topics"`
+	}
+	if err := NewRequest("", params).Decode(&p); err != nil {
+		return nil, nil, err
+	}
+	if len(p.Topics) == 0 {
+		p.Topics = DefaultTopics
+	}
+
+	subs := make([]subscription, 0, len(p.Topics))
+	for _, topic := range p.Topics {
+		ch := s.bus.Subscribe(topic)
+		subs = append(subs, subscription{topic: topic, ch: ch})
+		go func() {
+			for e := range ch {
+				if err := w.write(wireResponse{Method: "event", Params: e}); err != nil {
+					s.logger.Debug("remote: event write failed", "err", err)
+				}
+			}
+		}()
+	}
+	return subs, map[string]any{"topics": p.Topics}, nil
+}
+
+func idOrNull(id json.RawMessage) json.RawMessage {
+	if len(id) == 0 {
+		return json.RawMessage("null")
+	}
+	return id
+}
@@ -0,0 +1,145 @@
+package remote
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/marcus/sidecar/internal/event"
+)
+
+// startServer serves on a temp socket, answering "echo" with its params and
+// everything else with MethodNotFound.
+func startServer(t *testing.T, bus *event.Dispatcher) *Server {
+	t.Helper()
+	srv, err := Listen(DefaultSocketPath(t.TempDir(), 1), bus, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		_ = srv.Serve(func(r *Request) {
+			if r.Method != "echo" {
+				r.Reply(nil, MethodNotFound(r.Method))
+				return
+			}
+			var p map[string]any
+			if err := r.Decode(&p); err != nil {
+				r.Reply(nil, err)
+				return
+			}
+			r.Reply(p, nil)
+		})
+	}()
+	t.Cleanup(func() { _ = srv.Close() })
+	return srv
+}
+
+func TestServer_Call(t *testing.T) {
+	srv := startServer(t, nil)
+	if info, err := os.Stat(srv.Path()); err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("socket should be private, got %v %v", info, err)
+	}
+	if got := FindSockets(filepath.Dir(filepath.Dir(srv.Path()))); len(got) != 1 || got[0] != srv.Path() {
+		t.Errorf("FindSockets = %v", got)
+	}
+
+	c, err := Dial(srv.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = c.Close() }()
+
+	var pong string
+	if err := c.Call("ping", nil, &pong); err != nil || pong != "pong" {
+		t.Errorf("ping = %q, %v", pong, err)
+	}
+	var got map[string]any
+	if err := c.Call("echo", map[string]any{"path": "main.go"}, &got); err != nil || got["path"] != "main.go" {
+		t.Errorf("echo = %v, %v", got, err)
+	}
+
+	err = c.Call("nope", nil, nil)
+	var rpcErr *Error
+	if !errors.As(err, &rpcErr) || rpcErr.Code != CodeMethodNotFound {
+		t.Errorf("unknown method should fail with MethodNotFound, got %v", err)
+	}
+	if err := c.Call("echo", []int{1}, nil); !errors.As(err, &rpcErr) || rpcErr.Code != CodeInvalidParams {
+		t.Errorf("bad params should fail with InvalidParams, got %v", err)
+	}
+}
+
+func TestServer_Timeout(t *testing.T) {
+	srv, err := Listen(DefaultSocketPath(t.TempDir(), 1), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.timeout = 50 * time.Millisecond
+	go func() { _ = srv.Serve(func(*Request) {}) }()
+	defer func() { _ = srv.Close() }()
+
+	c, err := Dial(srv.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = c.Close() }()
+	if err := c.Call("slow", nil, nil); err == nil {
+		t.Error("unanswered request should time out")
+	}
+}
+
+func TestServer_Events(t *testing.T) {
+	bus := event.New()
+	defer bus.Close()
+	srv := startServer(t, bus)
+
+	c, err := Dial(srv.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = c.Close() }()
+	if err := c.Call("events.subscribe", map[string]any{"topics": []string{event.TopicAgents}}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	bus.Publish(event.TopicUI, event.NewEvent(event.TypeFocusChanged, event.TopicUI, "git-status"))
+	bus.Publish(event.TopicAgents, event.NewEvent(event.TypeAgentStatus, event.TopicAgents,
+		event.AgentStatus{Workspace: "auth", Status: "done"}))
+
+	e, err := c.NextEvent()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := e.Data.(map[string]any)
+	if e.Type != event.TypeAgentStatus || data["workspace"] != "auth" || data["status"] != "done" {
+		t.Errorf("event = %+v", e)
+	}
+}
+
+func TestListen_StaleSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "s.sock")
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	srv, err := Listen(path, nil, nil)
+	if err != nil {
+		t.Fatalf("stale socket should be replaced: %v", err)
+	}
+	go func() { _ = srv.Serve(func(*Request) {}) }()
+
+	if _, err := Listen(path, nil, nil); err == nil {
+		t.Error("live socket should not be replaced")
+	}
+
+	if err := srv.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Close should remove the socket")
+	}
+	if _, err := net.Dial("unix", path); err == nil {
+		t.Error("closed server should refuse connections")
+	}
+}
@@ -0,0 +1,5 @@
+// Package searchindex maintains a persistent SQLite FTS5 index of
+// conversation message content across all adapters. Sessions are indexed
+// incrementally as they change, so cross-conversation search can query the
+// index instead of re-parsing every session file on each keystroke.
+package searchindex
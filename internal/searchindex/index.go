@@ -0,0 +1,293 @@
+package searchindex
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	_ "modernc.org/sqlite" // pure-Go driver with FTS5 built in
+
+	"github.com/marcus/sidecar/internal/adapter"
+)
+
+// schemaVersion is stored in PRAGMA user_version. Bump it when the schema
+// changes; older indexes are dropped and rebuilt on open.
+const schemaVersion = 2
+
+// FileName is the index database file name within the sidecar config dir.
+const FileName = "search.db"
+
+// Index is a persistent full-text index of session messages.
+// It is safe for concurrent use.
+type Index struct {
+	db *sql.DB
+	mu sync.Mutex // serializes writers
+}
+
+// Open opens (or creates) the index database at path.
+func Open(path string) (*Index, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("create index dir: %w", err)
+	}
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)")
+	if err != nil {
+		return nil, fmt.Errorf("open index: %w", err)
+	}
+	idx := &Index{db: db}
+	if err := idx.initSchema(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("init index schema: %w", err)
+	}
+	return idx, nil
+}
+
+// Close closes the underlying database.
+func (idx *Index) Close() error {
+	if idx == nil || idx.db == nil {
+		return nil
+	}
+	return idx.db.Close()
+}
+
+func (idx *Index) initSchema() error {
+	var version int
+	if err := idx.db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return err
+	}
+	if version != schemaVersion {
+		for _, stmt := range []string{
+			`DROP TABLE IF EXISTS blocks_fts`,
+			`DROP TABLE IF EXISTS blocks`,
+			`DROP TABLE IF EXISTS sessions`,
+		} {
+			if _, err := idx.db.Exec(stmt); err != nil {
+				return err
+			}
+		}
+	}
+
+	schema := []string{
+		`CREATE TABLE IF NOT EXISTS sessions (
+			session_key   TEXT PRIMARY KEY,
+			updated_at    INTEGER NOT NULL,
+			message_count INTEGER NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS blocks (
+			id          INTEGER PRIMARY KEY,
+			session_key TEXT NOT NULL,
+			message_idx INTEGER NOT NULL,
+			message_id  TEXT NOT NULL,
+			role        TEXT NOT NULL,
+			model       TEXT NOT NULL,
+			ts          INTEGER NOT NULL,
+			block_type  TEXT NOT NULL,
+			content     TEXT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS blocks_session ON blocks(session_key, message_idx)`,
+		// The trigram tokenizer matches substrings, like the scan it replaces
+		`CREATE VIRTUAL TABLE IF NOT EXISTS blocks_fts USING fts5(
+			content, content='blocks', content_rowid='id', tokenize='trigram'
+		)`,
+		`CREATE TRIGGER IF NOT EXISTS blocks_ai AFTER INSERT ON blocks BEGIN
+			INSERT INTO blocks_fts(rowid, content) VALUES (new.id, new.content);
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS blocks_ad AFTER DELETE ON blocks BEGIN
+			INSERT INTO blocks_fts(blocks_fts, rowid, content) VALUES ('delete', old.id, old.content);
+		END`,
+		fmt.Sprintf(`PRAGMA user_version = %d`, schemaVersion),
+	}
+	for _, stmt := range schema {
+		if _, err := idx.db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SessionKey returns the index key for a session. Session IDs are only
+// unique per adapter, so the key includes the adapter ID.
+func SessionKey(adapterID, sessionID string) string {
+	return adapterID + "/" + sessionID
+}
+
+// Stale returns the sessions whose indexed state does not match their
+// current UpdatedAt, including sessions that have never been indexed.
+func (idx *Index) Stale(sessions []adapter.Session) ([]adapter.Session, error) {
+	indexed := make(map[string]int64)
+	rows, err := idx.db.Query(`SELECT session_key, updated_at FROM sessions`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var key string
+		var updated int64
+		if err := rows.Scan(&key, &updated); err != nil {
+			return nil, err
+		}
+		indexed[key] = updated
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var stale []adapter.Session
+	for _, s := range sessions {
+		updated, ok := indexed[SessionKey(s.AdapterID, s.ID)]
+		if !ok || updated != toUnixNano(s.UpdatedAt) {
+			stale = append(stale, s)
+		}
+	}
+	return stale, nil
+}
+
+// IndexSession stores the messages of a session. Indexing is incremental:
+// when the session only grew, messages before the last indexed one are kept
+// and the rest are replaced, since the last message may still have been
+// streaming when it was indexed.
+func (idx *Index) IndexSession(s adapter.Session, messages []adapter.Message) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	key := SessionKey(s.AdapterID, s.ID)
+	tx, err := idx.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var indexedCount int
+	err = tx.QueryRow(`SELECT message_count FROM sessions WHERE session_key = ?`, key).Scan(&indexedCount)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	start := 0
+	if indexedCount > 0 && indexedCount <= len(messages) {
+		start = indexedCount - 1
+	}
+	if _, err := tx.Exec(`DELETE FROM blocks WHERE session_key = ? AND message_idx >= ?`, key, start); err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(`INSERT INTO blocks
+		(session_key, message_idx, message_id, role, model, ts, block_type, content)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = stmt.Close() }()
+
+	for i := start; i < len(messages); i++ {
+		msg := &messages[i]
+		for _, b := range messageBlocks(msg) {
+			if _, err := stmt.Exec(key, i, msg.ID, msg.Role, msg.Model,
+				toUnixNano(msg.Timestamp), b.blockType, b.content); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := tx.Exec(`INSERT INTO sessions (session_key, updated_at, message_count)
+		VALUES (?, ?, ?)
+		ON CONFLICT(session_key) DO UPDATE SET
+			updated_at = excluded.updated_at, message_count = excluded.message_count`,
+		key, toUnixNano(s.UpdatedAt), len(messages)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RemoveSession deletes a session and its messages from the index.
+func (idx *Index) RemoveSession(adapterID, sessionID string) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	key := SessionKey(adapterID, sessionID)
+	tx, err := idx.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+	if _, err := tx.Exec(`DELETE FROM blocks WHERE session_key = ?`, key); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM sessions WHERE session_key = ?`, key); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// block is one searchable unit of a message: all content of one block type.
+type block struct {
+	blockType string
+	content   string
+}
+
+// messageBlocks flattens a message into per-type text, covering the same
+// fields as adapter.SearchMessage: content, text/thinking/tool blocks, tool
+// uses and thinking blocks. Duplicate text is skipped.
+func messageBlocks(msg *adapter.Message) []block {
+	parts := map[string][]string{}
+	seen := map[string]bool{}
+	add := func(blockType, text string) {
+		if text == "" || seen[blockType+"\x00"+text] {
+			return
+		}
+		seen[blockType+"\x00"+text] = true
+		parts[blockType] = append(parts[blockType], text)
+	}
+
+	add("text", msg.Content)
+	for _, cb := range msg.ContentBlocks {
+		switch cb.Type {
+		case "text":
+			if !strings.Contains(msg.Content, cb.Text) {
+				add("text", cb.Text)
+			}
+		case "thinking":
+			add("thinking", cb.Text)
+		case "tool_use":
+			add("tool_use", cb.ToolName)
+			add("tool_use", cb.ToolInput)
+		case "tool_result":
+			add("tool_result", cb.ToolOutput)
+		}
+	}
+	for _, tu := range msg.ToolUses {
+		add("tool_use", tu.Name)
+		add("tool_use", tu.Input)
+		add("tool_result", tu.Output)
+	}
+	for _, tb := range msg.ThinkingBlocks {
+		add("thinking", tb.Content)
+	}
+
+	var blocks []block
+	for _, t := range []string{"text", "thinking", "tool_use", "tool_result"} {
+		if len(parts[t]) > 0 {
+			blocks = append(blocks, block{blockType: t, content: strings.Join(parts[t], "\n")})
+		}
+	}
+	return blocks
+}
+
+// toUnixNano converts a time for storage, mapping the zero time to 0.
+func toUnixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// unixNano converts stored nanoseconds back to a time, treating zero as unset.
+func unixNano(ns int64) time.Time {
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ns)
+}
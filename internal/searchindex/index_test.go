@@ -0,0 +1,284 @@
+package searchindex
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/marcus/sidecar/internal/adapter"
+)
+
+func openTestIndex(t *testing.T) *Index {
+	t.Helper()
+	idx, err := Open(filepath.Join(t.TempDir(), FileName))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { _ = idx.Close() })
+	return idx
+}
+
+func testSession(adapterID, id string, updated time.Time) adapter.Session {
+	return adapter.Session{ID: id, AdapterID: adapterID, UpdatedAt: updated}
+}
+
+func textMsg(id, role, content string) adapter.Message {
+	return adapter.Message{ID: id, Role: role, Content: content}
+}
+
+func TestSearch_RanksAcrossAdapters(t *testing.T) {
+	idx := openTestIndex(t)
+	now := time.Now()
+
+	claude := testSession("claude-code", "c1", now)
+	codex := testSession("codex", "x1", now)
+	other := testSession("codex", "x2", now)
+
+	mustIndex(t, idx, claude, []adapter.Message{
+		textMsg("m1", "user", "fix the database migration"),
+		textMsg("m2", "assistant", "the database migration is fixed; database schema updated"),
+	})
+	mustIndex(t, idx, codex, []adapter.Message{
+		textMsg("m1", "user", "add a migration for the users table"),
+	})
+	mustIndex(t, idx, other, []adapter.Message{
+		textMsg("m1", "user", "unrelated work"),
+	})
+
+	hits, _, err := idx.Search("database", adapter.DefaultSearchOptions(), nil)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(hits) != 1 || hits[0].AdapterID != "claude-code" || hits[0].SessionID != "c1" {
+		t.Fatalf("expected single claude hit, got %+v", hits)
+	}
+	if got := adapter.TotalMatches(hits[0].Messages); got != 3 {
+		t.Errorf("expected 3 line matches, got %d", got)
+	}
+	if hits[0].Snippet == "" {
+		t.Error("expected snippet")
+	}
+
+	hits, _, err = idx.Search("migration", adapter.DefaultSearchOptions(), nil)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(hits) != 2 {
+		t.Fatalf("expected hits in both adapters, got %d", len(hits))
+	}
+}
+
+func TestSearch_PrefixAndLiteralVerification(t *testing.T) {
+	idx := openTestIndex(t)
+	s := testSession("claude-code", "s1", time.Now())
+	mustIndex(t, idx, s, []adapter.Message{
+		textMsg("m1", "user", "Searching for foo-bar"),
+		textMsg("m2", "user", "bar then foo"),
+	})
+
+	// Prefix of a word matches while typing
+	hits, _, _ := idx.Search("searc", adapter.DefaultSearchOptions(), nil)
+	if len(hits) != 1 {
+		t.Fatalf("expected prefix hit, got %d", len(hits))
+	}
+
+	// So does text in the middle of a word, like the scan
+	hits, _, _ = idx.Search("earchin", adapter.DefaultSearchOptions(), nil)
+	if len(hits) != 1 {
+		t.Fatalf("expected mid-word hit, got %d", len(hits))
+	}
+
+	// Quotes in the query are literal
+	hits, _, err := idx.Search(`"foo`, adapter.DefaultSearchOptions(), nil)
+	if err != nil || len(hits) != 0 {
+		t.Fatalf("quoted query: %+v, %v", hits, err)
+	}
+
+	// Both messages contain the words, but only one has the literal phrase
+	hits, _, _ = idx.Search("foo-bar", adapter.DefaultSearchOptions(), nil)
+	if len(hits) != 1 || len(hits[0].Messages) != 1 || hits[0].Messages[0].MessageIdx != 0 {
+		t.Fatalf("expected only literal match in message 0, got %+v", hits)
+	}
+
+	// Case-sensitive queries are verified against the content
+	opts := adapter.DefaultSearchOptions()
+	opts.CaseSensitive = true
+	hits, _, _ = idx.Search("searching", opts, nil)
+	if len(hits) != 0 {
+		t.Fatalf("expected no case-sensitive hit, got %+v", hits)
+	}
+}
+
+func TestSearch_Scope(t *testing.T) {
+	idx := openTestIndex(t)
+	a := testSession("claude-code", "a", time.Now())
+	b := testSession("claude-code", "b", time.Now())
+	mustIndex(t, idx, a, []adapter.Message{textMsg("m1", "user", "shared term")})
+	mustIndex(t, idx, b, []adapter.Message{textMsg("m1", "user", "shared term")})
+
+	hits, _, err := idx.Search("shared", adapter.DefaultSearchOptions(), []adapter.Session{b})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(hits) != 1 || hits[0].SessionID != "b" {
+		t.Fatalf("expected only scoped session, got %+v", hits)
+	}
+}
+
+func TestSearch_ReportsRowCap(t *testing.T) {
+	orig := maxRows
+	maxRows = 3
+	t.Cleanup(func() { maxRows = orig })
+
+	idx := openTestIndex(t)
+	a := testSession("claude-code", "a", time.Now())
+	b := testSession("claude-code", "b", time.Now())
+	mustIndex(t, idx, a, []adapter.Message{
+		textMsg("m1", "user", "needle one"),
+		textMsg("m2", "user", "needle two"),
+	})
+	mustIndex(t, idx, b, []adapter.Message{textMsg("m1", "user", "needle three")})
+
+	// Exactly at the cap: nothing dropped
+	hits, truncated, err := idx.Search("needle", adapter.DefaultSearchOptions(), nil)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if truncated || len(hits) != 2 {
+		t.Fatalf("expected 2 sessions without truncation, got %d (truncated=%v)", len(hits), truncated)
+	}
+
+	mustIndex(t, idx, b, []adapter.Message{
+		textMsg("m1", "user", "needle three"),
+		textMsg("m2", "user", "needle four"),
+	})
+	hits, truncated, err = idx.Search("needle", adapter.DefaultSearchOptions(), nil)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if !truncated {
+		t.Fatal("expected truncated once matches exceed the row cap")
+	}
+	total := 0
+	for _, h := range hits {
+		total += adapter.TotalMatches(h.Messages)
+	}
+	if total != maxRows {
+		t.Errorf("expected %d matches kept, got %d", maxRows, total)
+	}
+}
+
+func TestStaleAndIncrementalIndex(t *testing.T) {
+	idx := openTestIndex(t)
+	t0 := time.Now().Add(-time.Hour)
+	s := testSession("codex", "s1", t0)
+
+	stale, err := idx.Stale([]adapter.Session{s})
+	if err != nil || len(stale) != 1 {
+		t.Fatalf("expected unindexed session to be stale, got %v (%v)", stale, err)
+	}
+
+	msgs := []adapter.Message{
+		textMsg("m1", "user", "first question"),
+		textMsg("m2", "assistant", "partial ans"),
+	}
+	mustIndex(t, idx, s, msgs)
+	if stale, _ := idx.Stale([]adapter.Session{s}); len(stale) != 0 {
+		t.Fatalf("expected indexed session to be current, got %v", stale)
+	}
+
+	// Session grows and the last message finished streaming
+	s.UpdatedAt = t0.Add(time.Minute)
+	if stale, _ := idx.Stale([]adapter.Session{s}); len(stale) != 1 {
+		t.Fatal("expected updated session to be stale")
+	}
+	msgs[1].Content = "partial answer completed"
+	msgs = append(msgs, textMsg("m3", "user", "follow up"))
+	mustIndex(t, idx, s, msgs)
+
+	if hits, _, _ := idx.Search("completed", adapter.DefaultSearchOptions(), nil); len(hits) != 1 {
+		t.Error("expected re-indexed last message to be searchable")
+	}
+	if hits, _, _ := idx.Search("follow", adapter.DefaultSearchOptions(), nil); len(hits) != 1 {
+		t.Error("expected new message to be searchable")
+	}
+	hits, _, _ := idx.Search("first", adapter.DefaultSearchOptions(), nil)
+	if len(hits) != 1 || len(hits[0].Messages) != 1 {
+		t.Errorf("expected earlier message indexed exactly once, got %+v", hits)
+	}
+
+	if err := idx.RemoveSession("codex", "s1"); err != nil {
+		t.Fatalf("RemoveSession: %v", err)
+	}
+	if hits, _, _ := idx.Search("first", adapter.DefaultSearchOptions(), nil); len(hits) != 0 {
+		t.Error("expected removed session to be gone")
+	}
+}
+
+func TestIndexSession_ToolBlocks(t *testing.T) {
+	idx := openTestIndex(t)
+	s := testSession("claude-code", "s1", time.Now())
+	mustIndex(t, idx, s, []adapter.Message{{
+		ID:   "m1",
+		Role: "assistant",
+		ContentBlocks: []adapter.ContentBlock{
+			{Type: "tool_use", ToolName: "Bash", ToolInput: `{"command":"go test ./..."}`},
+			{Type: "tool_result", ToolOutput: "ok  github.com/example/pkg"},
+		},
+	}})
+
+	hits, _, _ := idx.Search("example", adapter.DefaultSearchOptions(), nil)
+	if len(hits) != 1 {
+		t.Fatalf("expected tool result hit, got %d", len(hits))
+	}
+	if bt := hits[0].Messages[0].Matches[0].BlockType; bt != "tool_result" {
+		t.Errorf("expected tool_result block, got %q", bt)
+	}
+}
+
+func TestSupports(t *testing.T) {
+	tests := []struct {
+		query string
+		regex bool
+		want  bool
+	}{
+		{"hello", false, true},
+		{"foo.go", false, true},
+		{"---", false, true},
+		{"ab", false, false},
+		{"", false, false},
+		{"hello", true, false},
+	}
+	for _, tt := range tests {
+		if got := Supports(tt.query, adapter.SearchOptions{UseRegex: tt.regex}); got != tt.want {
+			t.Errorf("Supports(%q, regex=%v) = %v, want %v", tt.query, tt.regex, got, tt.want)
+		}
+	}
+}
+
+func TestOpen_Reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	idx, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	s := testSession("codex", "s1", time.Now())
+	mustIndex(t, idx, s, []adapter.Message{textMsg("m1", "user", "persisted text")})
+	_ = idx.Close()
+
+	idx, err = Open(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer func() { _ = idx.Close() }()
+	if hits, _, _ := idx.Search("persisted", adapter.DefaultSearchOptions(), nil); len(hits) != 1 {
+		t.Error("expected index to persist across opens")
+	}
+}
+
+func mustIndex(t *testing.T, idx *Index, s adapter.Session, msgs []adapter.Message) {
+	t.Helper()
+	if err := idx.IndexSession(s, msgs); err != nil {
+		t.Fatalf("IndexSession: %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package searchindex
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/marcus/sidecar/internal/adapter"
+)
+
+// maxRows caps the number of index rows a single query scans. Search reports
+// when a query matched more rows than this.
+var maxRows = 2000
+
+// SessionHits holds the matches for one session, ranked against the others.
+type SessionHits struct {
+	AdapterID string
+	SessionID string
+
+	// Rank is the best bm25 score of any matching block; lower is better.
+	Rank float64
+
+	// Snippet is a short excerpt around the best-ranked match.
+	Snippet string
+
+	// Messages holds the matched messages in message order, in the same shape
+	// adapter.MessageSearcher returns.
+	Messages []adapter.MessageMatch
+}
+
+// minQueryRunes is the shortest query the trigram index can look up.
+const minQueryRunes = 3
+
+// Supports reports whether the index can answer a query. Regex queries and
+// queries shorter than a trigram fall back to scanning sessions.
+func Supports(query string, opts adapter.SearchOptions) bool {
+	return !opts.UseRegex && utf8.RuneCountInString(query) >= minQueryRunes
+}
+
+// Search returns indexed sessions matching query, best-ranked first. Only
+// sessions in scope are considered; a nil scope searches the whole index.
+// Hits are verified against the literal query (honoring opts.CaseSensitive)
+// and capped at opts.MaxResults matches per session. The bool is true when the
+// query matched more than maxRows blocks and the lower-ranked ones were
+// dropped.
+func (idx *Index) Search(query string, opts adapter.SearchOptions, scope []adapter.Session) ([]SessionHits, bool, error) {
+	if !Supports(query, opts) {
+		return nil, false, nil
+	}
+	re, err := adapter.CompileSearchPattern(query, opts)
+	if err != nil {
+		return nil, false, err
+	}
+	maxResults := opts.MaxResults
+	if maxResults <= 0 {
+		maxResults = adapter.DefaultMaxResults
+	}
+
+	sqlQuery := `SELECT b.session_key, b.message_idx, b.message_id, b.role, b.model, b.ts,
+			b.block_type, b.content, snippet(blocks_fts, 0, '', '', '…', 16), bm25(blocks_fts)
+		FROM blocks_fts JOIN blocks b ON b.id = blocks_fts.rowid
+		WHERE blocks_fts MATCH ?`
+	args := []any{ftsQuery(query)}
+	if scope != nil {
+		keys := make([]string, len(scope))
+		for i, s := range scope {
+			keys[i] = SessionKey(s.AdapterID, s.ID)
+		}
+		keysJSON, err := json.Marshal(keys)
+		if err != nil {
+			return nil, false, err
+		}
+		sqlQuery += ` AND b.session_key IN (SELECT value FROM json_each(?))`
+		args = append(args, string(keysJSON))
+	}
+	// Fetch one row past the cap to tell whether anything was dropped.
+	sqlQuery += ` ORDER BY bm25(blocks_fts) LIMIT ?`
+	args = append(args, maxRows+1)
+
+	rows, err := idx.db.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	bySession := make(map[string]*SessionHits)
+	counts := make(map[string]int)
+	var order []string
+	scanned, truncated := 0, false
+	for rows.Next() {
+		if scanned == maxRows {
+			truncated = true
+			break
+		}
+		scanned++
+		var (
+			key, msgID, role, model, blockType, content, snippet string
+			msgIdx                                               int
+			ts                                                   int64
+			rank                                                 float64
+		)
+		if err := rows.Scan(&key, &msgIdx, &msgID, &role, &model, &ts, &blockType, &content, &snippet, &rank); err != nil {
+			return nil, false, err
+		}
+		if counts[key] >= maxResults {
+			continue
+		}
+		matches := adapter.SearchContent(content, blockType, re)
+		if len(matches) == 0 {
+			continue // case differs from a case-sensitive query
+		}
+		if remaining := maxResults - counts[key]; len(matches) > remaining {
+			matches = matches[:remaining]
+		}
+		counts[key] += len(matches)
+
+		sh, ok := bySession[key]
+		if !ok {
+			adapterID, sessionID, _ := strings.Cut(key, "/")
+			sh = &SessionHits{AdapterID: adapterID, SessionID: sessionID, Rank: rank, Snippet: snippet}
+			bySession[key] = sh
+			order = append(order, key)
+		}
+		sh.addMatches(adapter.MessageMatch{
+			MessageID:  msgID,
+			MessageIdx: msgIdx,
+			Role:       role,
+			Timestamp:  unixNano(ts),
+			Model:      model,
+			Matches:    matches,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+
+	results := make([]SessionHits, 0, len(order))
+	for _, key := range order {
+		sh := bySession[key]
+		sort.SliceStable(sh.Messages, func(i, j int) bool {
+			return sh.Messages[i].MessageIdx < sh.Messages[j].MessageIdx
+		})
+		results = append(results, *sh)
+	}
+	return results, truncated, nil
+}
+
+// addMatches merges mm into the session's hits, combining blocks of the same
+// message into a single MessageMatch.
+func (sh *SessionHits) addMatches(mm adapter.MessageMatch) {
+	for i := range sh.Messages {
+		if sh.Messages[i].MessageIdx == mm.MessageIdx {
+			sh.Messages[i].Matches = append(sh.Messages[i].Matches, mm.Matches...)
+			return
+		}
+	}
+	sh.Messages = append(sh.Messages, mm)
+}
+
+// ftsQuery builds an FTS5 MATCH expression for the literal query: a single
+// phrase, which the trigram tokenizer matches as a case-insensitive
+// substring. Quoting keeps FTS5 operators in user input inert.
+func ftsQuery(query string) string {
+	return `"` + strings.ReplaceAll(query, `"`, `""`) + `"`
+}
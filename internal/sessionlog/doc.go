@@ -0,0 +1,6 @@
+// Package sessionlog keeps a durable, timestamped log of a terminal session's
+// output. Callers feed it successive captures of the session's screen; it
+// appends only the lines that are new since the previous capture, records
+// events such as status changes between them, rotates files by size and
+// prunes logs past a retention age.
+package sessionlog
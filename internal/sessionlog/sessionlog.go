@@ -0,0 +1,371 @@
+package sessionlog
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	// Ext is the extension of session log files.
+	Ext = ".log"
+
+	// TimeFormat prefixes every log line.
+	TimeFormat = "2006-01-02 15:04:05"
+
+	// Separators between the timestamp and the text of output and event lines.
+	outputSep = " | "
+	eventSep  = " # "
+
+	// holdLines trailing lines of a capture are not logged until they scroll
+	// up, since agents redraw their input box and spinners in place there.
+	holdLines = 8
+
+	// anchorLines last logged lines locate where a new capture resumes.
+	anchorLines = 3
+
+	// tailBytes of an existing log are read to resume it after a restart.
+	tailBytes = 64 * 1024
+)
+
+// Options controls rotation.
+type Options struct {
+	// MaxBytes rotates the log once it grows past this size; 0 never rotates.
+	MaxBytes int64
+	// MaxFiles is how many rotated files are kept (name.1.log is the newest).
+	MaxFiles int
+}
+
+// Log appends a session's output to <dir>/<name>.log. It is safe for
+// concurrent use.
+type Log struct {
+	mu   sync.Mutex
+	dir  string
+	name string
+	opts Options
+	f    *os.File
+	size int64
+	now  func() time.Time
+
+	last    []string // last logged output lines, for locating new output
+	pending []string // held tail of the latest capture
+}
+
+// Path returns the path of a session's current log file.
+func Path(dir, name string) string {
+	return filepath.Join(dir, name+Ext)
+}
+
+func rotatedPath(dir, name string, n int) string {
+	return filepath.Join(dir, fmt.Sprintf("%s.%d%s", name, n, Ext))
+}
+
+// Open opens a session's log for appending, creating dir if needed. An
+// existing log is resumed: captures repeating its last lines don't log them
+// again.
+func Open(dir, name string, opts Options) (*Log, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	l := &Log{dir: dir, name: name, opts: opts, now: time.Now}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	l.last = tailOutput(l.f, l.size)
+	return l, nil
+}
+
+func (l *Log) open() error {
+	f, err := os.OpenFile(Path(l.dir, l.name), os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	l.f, l.size = f, info.Size()
+	return nil
+}
+
+// tailOutput returns the last output lines of a log file.
+func tailOutput(f *os.File, size int64) []string {
+	offset := max(size-tailBytes, 0)
+	data := make([]byte, size-offset)
+	if _, err := f.ReadAt(data, offset); err != nil && !errors.Is(err, io.EOF) {
+		return nil
+	}
+	var last []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if text, ok := outputText(line); ok {
+			last = append(last, text)
+		}
+	}
+	return last[max(len(last)-anchorLines, 0):]
+}
+
+// Snapshot logs the lines of a capture that are new since the previous one.
+// The capture's last few lines are held back until they scroll up or the log
+// is closed.
+func (l *Log) Snapshot(capture string) error {
+	lines := captureLines(capture)
+	stable := max(len(lines)-holdLines, 0)
+	// Keep blank lines pending so the last logged line is a useful anchor
+	for stable > 0 && lines[stable-1] == "" {
+		stable--
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return os.ErrClosed
+	}
+	l.pending = append(l.pending[:0], lines[stable:]...)
+	start, found := l.resume(lines[:stable])
+	if !found && stable > 0 {
+		if err := l.write(eventSep, "output redrawn or skipped"); err != nil {
+			return err
+		}
+	}
+	return l.writeOutput(lines[start:stable])
+}
+
+// resume returns where lines continue the logged output: the index after the
+// last occurrence of the logged anchor.
+func (l *Log) resume(lines []string) (int, bool) {
+	if len(l.last) == 0 {
+		return 0, true
+	}
+	for i := len(lines) - 1; i >= 0; i-- {
+		n := min(len(l.last), i+1)
+		match := true
+		for k := 0; k < n; k++ {
+			if lines[i-k] != l.last[len(l.last)-1-k] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
+
+func (l *Log) writeOutput(lines []string) error {
+	for _, line := range lines {
+		if err := l.write(outputSep, line); err != nil {
+			return err
+		}
+	}
+	if len(lines) > 0 {
+		l.last = append(l.last, lines...)
+		l.last = append([]string(nil), l.last[max(len(l.last)-anchorLines, 0):]...)
+	}
+	return nil
+}
+
+// Event logs a marker such as a status change.
+func (l *Log) Event(format string, args ...any) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return os.ErrClosed
+	}
+	return l.write(eventSep, fmt.Sprintf(format, args...))
+}
+
+func (l *Log) write(sep, text string) error {
+	line := l.now().Format(TimeFormat) + sep + text + "\n"
+	if l.opts.MaxBytes > 0 && l.size > 0 && l.size+int64(len(line)) > l.opts.MaxBytes {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := l.f.WriteString(line)
+	l.size += int64(n)
+	return err
+}
+
+// rotate shifts name.log to name.1.log, name.1.log to name.2.log and so on,
+// dropping the oldest.
+func (l *Log) rotate() error {
+	if err := l.f.Close(); err != nil {
+		return err
+	}
+	l.f = nil
+	_ = os.Remove(rotatedPath(l.dir, l.name, l.opts.MaxFiles))
+	for n := l.opts.MaxFiles - 1; n >= 1; n-- {
+		_ = os.Rename(rotatedPath(l.dir, l.name, n), rotatedPath(l.dir, l.name, n+1))
+	}
+	current := Path(l.dir, l.name)
+	if l.opts.MaxFiles > 0 {
+		_ = os.Rename(current, rotatedPath(l.dir, l.name, 1))
+	} else {
+		_ = os.Remove(current)
+	}
+	return l.open()
+}
+
+// Close logs the held lines of the last capture and closes the file.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return nil
+	}
+	start, _ := l.resume(l.pending)
+	err := l.writeOutput(l.pending[start:])
+	l.pending = nil
+	if cerr := l.f.Close(); err == nil {
+		err = cerr
+	}
+	l.f = nil
+	return err
+}
+
+// captureLines splits a capture into lines without styling or trailing
+// whitespace, dropping trailing blank lines.
+func captureLines(capture string) []string {
+	lines := strings.Split(ansi.Strip(capture), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// Line is one parsed log line.
+type Line struct {
+	Time  time.Time
+	Text  string
+	Event bool // a marker rather than session output
+}
+
+// ParseLine parses a log line. Lines in an unknown format are returned as
+// output text with a zero time.
+func ParseLine(s string) Line {
+	if len(s) >= len(TimeFormat)+len(outputSep) {
+		ts, rest := s[:len(TimeFormat)], s[len(TimeFormat):]
+		if t, err := time.ParseInLocation(TimeFormat, ts, time.Local); err == nil {
+			switch {
+			case strings.HasPrefix(rest, outputSep):
+				return Line{Time: t, Text: rest[len(outputSep):]}
+			case strings.HasPrefix(rest, eventSep):
+				return Line{Time: t, Text: rest[len(eventSep):], Event: true}
+			}
+		}
+	}
+	return Line{Text: s}
+}
+
+func outputText(s string) (string, bool) {
+	if s == "" {
+		return "", false
+	}
+	line := ParseLine(s)
+	return line.Text, !line.Event && !line.Time.IsZero()
+}
+
+// Read returns a session's log lines, oldest first, across rotated files.
+func Read(dir, name string) ([]Line, error) {
+	entries, _ := os.ReadDir(dir)
+	var rotated []string
+	for _, e := range entries {
+		if rotatedIndex(e.Name(), name) > 0 {
+			rotated = append(rotated, e.Name())
+		}
+	}
+	sort.Slice(rotated, func(i, j int) bool {
+		return rotatedIndex(rotated[i], name) > rotatedIndex(rotated[j], name)
+	})
+	for i, base := range rotated {
+		rotated[i] = filepath.Join(dir, base)
+	}
+
+	var lines []Line
+	found := false
+	for _, p := range append(rotated, Path(dir, name)) {
+		f, err := os.Open(p)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		found = true
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			lines = append(lines, ParseLine(scanner.Text()))
+		}
+		err = scanner.Err()
+		_ = f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !found {
+		return nil, os.ErrNotExist
+	}
+	return lines, nil
+}
+
+// rotatedIndex returns n for "<name>.<n>.log", or 0.
+func rotatedIndex(base, name string) int {
+	rest, ok := strings.CutPrefix(base, name+".")
+	if !ok {
+		return 0
+	}
+	rest, ok = strings.CutSuffix(rest, Ext)
+	if !ok {
+		return 0
+	}
+	n := 0
+	for _, c := range rest {
+		if c < '0' || c > '9' {
+			return 0
+		}
+		n = n*10 + int(c-'0')
+	}
+	return n
+}
+
+// Prune deletes log files in dir last written before now-maxAge and returns
+// how many it removed.
+func Prune(dir string, maxAge time.Duration, now time.Time) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	cutoff := now.Add(-maxAge)
+	removed := 0
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != Ext {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || !info.ModTime().Before(cutoff) {
+			continue
+		}
+		if os.Remove(filepath.Join(dir, e.Name())) == nil {
+			removed++
+		}
+	}
+	return removed, nil
+}
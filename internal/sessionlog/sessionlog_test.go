@@ -0,0 +1,135 @@
+package sessionlog
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// screen builds a capture from output lines followed by holdLines of
+// redrawn prompt area.
+func screen(lines ...string) string {
+	for i := 0; i < holdLines; i++ {
+		lines = append(lines, "> prompt")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func texts(t *testing.T, dir, name string) []string {
+	t.Helper()
+	lines, err := Read(dir, name)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	var out []string
+	for _, l := range lines {
+		if l.Event {
+			out = append(out, "# "+l.Text)
+		} else {
+			out = append(out, l.Text)
+		}
+	}
+	return out
+}
+
+func TestSnapshotLogsNewLines(t *testing.T) {
+	dir := t.TempDir()
+	l, err := Open(dir, "s", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps := []string{
+		screen("\x1b[1mone\x1b[0m", "two"),
+		screen("one", "two"), // unchanged
+		screen("two", "three", "four"),
+		screen("redrawn"), // anchor lost
+	}
+	for _, s := range steps {
+		if err := l.Snapshot(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.Event("status %s -> %s", "active", "done"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"one", "two", "three", "four", "# output redrawn or skipped", "redrawn", "# status active -> done"}
+	for i := 0; i < holdLines; i++ {
+		want = append(want, "> prompt")
+	}
+	if got := texts(t, dir, "s"); !slices.Equal(got, want) {
+		t.Errorf("log = %q\nwant %q", got, want)
+	}
+}
+
+func TestOpenResumesExistingLog(t *testing.T) {
+	dir := t.TempDir()
+	l, _ := Open(dir, "s", Options{})
+	_ = l.Snapshot(screen("one", "two"))
+	_ = l.Event("session ended")
+	l.f.Close() // simulate a crash: held lines are lost, logged ones stay
+
+	l, err := Open(dir, "s", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = l.Snapshot(screen("one", "two", "three"))
+	_ = l.Close()
+	got := texts(t, dir, "s")
+	if got[0] != "one" || got[1] != "two" || got[2] != "# session ended" || got[3] != "three" {
+		t.Errorf("resumed log = %q", got)
+	}
+}
+
+func TestRotationAndRead(t *testing.T) {
+	dir := t.TempDir()
+	l, _ := Open(dir, "s", Options{MaxBytes: 100, MaxFiles: 2})
+	for i := 0; i < 20; i++ {
+		_ = l.Event("event %02d", i)
+	}
+	_ = l.Close()
+
+	for _, name := range []string{"s.log", "s.1.log", "s.2.log"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected %s: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "s.3.log")); err == nil {
+		t.Error("expected at most 2 rotated files")
+	}
+	got := texts(t, dir, "s")
+	if len(got) == 0 || got[len(got)-1] != "# event 19" || !slices.IsSorted(got) {
+		t.Errorf("rotated logs read out of order: %q", got)
+	}
+	if _, err := Read(dir, "missing"); !os.IsNotExist(err) {
+		t.Errorf("Read of missing log = %v, want not exist", err)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	for name, age := range map[string]time.Duration{"old.log": 48 * time.Hour, "new.log": time.Hour, "keep.txt": 48 * time.Hour} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		_ = os.Chtimes(path, now.Add(-age), now.Add(-age))
+	}
+	n, err := Prune(dir, 24*time.Hour, now)
+	if err != nil || n != 1 {
+		t.Fatalf("Prune = %d, %v; want 1 removed", n, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "old.log")); err == nil {
+		t.Error("old.log should be pruned")
+	}
+	if n, _ := Prune(filepath.Join(dir, "missing"), time.Hour, now); n != 0 {
+		t.Error("pruning a missing dir should do nothing")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
 )
 
@@ -28,6 +29,10 @@ type State struct {
 
 	// Worktree state: maps main repo path -> last active worktree path
 	LastWorktreePath map[string]string `json:"lastWorktreePath,omitempty"`
+
+	// Project custom commands the user confirmed: maps project path ->
+	// command fingerprints
+	TrustedCommands map[string][]string `json:"trustedCommands,omitempty"`
 }
 
 // FileBrowserTabState holds persistent tab state for the file browser.
@@ -409,6 +414,35 @@ func ClearLastWorktreePath(mainRepoPath string) error {
 	return Save()
 }
 
+// IsCommandTrusted reports whether the user confirmed the project custom
+// command with the given fingerprint.
+func IsCommandTrusted(projectPath, fingerprint string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	if current == nil || current.TrustedCommands == nil {
+		return false
+	}
+	return slices.Contains(current.TrustedCommands[projectPath], fingerprint)
+}
+
+// TrustCommand records that the user confirmed a project custom command.
+func TrustCommand(projectPath, fingerprint string) error {
+	mu.Lock()
+	if current == nil {
+		current = &State{}
+	}
+	if current.TrustedCommands == nil {
+		current.TrustedCommands = make(map[string][]string)
+	}
+	if slices.Contains(current.TrustedCommands[projectPath], fingerprint) {
+		mu.Unlock()
+		return nil
+	}
+	current.TrustedCommands[projectPath] = append(current.TrustedCommands[projectPath], fingerprint)
+	mu.Unlock()
+	return Save()
+}
+
 // GetNotesState returns the saved notes state for a given working directory.
 func GetNotesState(workdir string) NotesState {
 	mu.RLock()
@@ -0,0 +1,6 @@
+// Package vterm is a small in-memory terminal emulator. It parses program
+// output into a cell screen with scrollback, tracks the cursor and the modes
+// interactive programs toggle (alternate screen, bracketed paste, mouse
+// reporting, application cursor keys), and renders the screen back to
+// ANSI-styled text in the same shape as tmux capture-pane.
+package vterm
@@ -0,0 +1,615 @@
+package vterm
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/cellbuf"
+	"github.com/mattn/go-runewidth"
+)
+
+const tabWidth = 8
+
+// Terminal is a terminal screen fed with program output through Write.
+// It is safe for concurrent use.
+type Terminal struct {
+	mu     sync.Mutex
+	parser *ansi.Parser
+	reply  io.Writer // receives answers to status queries; may be nil
+
+	width, height int
+	main, alt     screen
+	scr           *screen // active screen
+	scrollback    []row
+	maxScrollback int
+
+	pen         cellbuf.Style
+	link        cellbuf.Link
+	x, y        int
+	pendingWrap bool // cursor is past the last column; wrap on next print
+	top, bottom int  // scroll region rows, bottom exclusive
+	saved       savedCursor
+
+	cursorHidden   bool
+	noAutowrap     bool
+	appCursorKeys  bool
+	bracketedPaste bool
+	mouseModes     map[int]bool
+}
+
+// screen is a grid of cells plus, per row, whether it wrapped onto the next.
+type screen struct {
+	buf     *cellbuf.Buffer
+	wrapped []bool
+}
+
+// row is a rendered scrollback line.
+type row struct {
+	text    string
+	wrapped bool
+}
+
+type savedCursor struct {
+	x, y        int
+	pen         cellbuf.Style
+	pendingWrap bool
+}
+
+func newScreen(width, height int) screen {
+	return screen{buf: cellbuf.NewBuffer(width, height), wrapped: make([]bool, height)}
+}
+
+// New creates a terminal of the given size that keeps up to scrollback lines
+// of history. Replies to device status queries, which programs use to find
+// the cursor, are written to reply.
+func New(width, height, scrollback int, reply io.Writer) *Terminal {
+	width, height = max(width, 1), max(height, 1)
+	t := &Terminal{
+		reply:         reply,
+		width:         width,
+		height:        height,
+		main:          newScreen(width, height),
+		alt:           newScreen(width, height),
+		maxScrollback: scrollback,
+		bottom:        height,
+		mouseModes:    make(map[int]bool),
+	}
+	t.scr = &t.main
+	t.parser = ansi.NewParser()
+	t.parser.SetHandler(ansi.Handler{
+		Print:     t.print,
+		Execute:   t.execute,
+		HandleCsi: t.csi,
+		HandleEsc: t.esc,
+		HandleOsc: t.osc,
+	})
+	return t
+}
+
+// Write feeds program output to the terminal.
+func (t *Terminal) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, b := range p {
+		t.parser.Advance(b)
+	}
+	return len(p), nil
+}
+
+// Size returns the screen size.
+func (t *Terminal) Size() (width, height int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.width, t.height
+}
+
+// Cursor returns the 0-indexed cursor position on the screen and whether the
+// program shows the cursor.
+func (t *Terminal) Cursor() (row, col int, visible bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.y, t.x, !t.cursorHidden
+}
+
+// AppCursorKeys reports whether the program asked for application cursor
+// keys (arrows sent as ESC O A rather than ESC [ A).
+func (t *Terminal) AppCursorKeys() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.appCursorKeys
+}
+
+// BracketedPaste reports whether the program enabled bracketed paste.
+func (t *Terminal) BracketedPaste() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.bracketedPaste
+}
+
+// MouseReporting reports whether the program enabled mouse reporting.
+func (t *Terminal) MouseReporting() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.mouseModes) > 0
+}
+
+// Resize changes the screen size. Lines are not reflowed: narrowing truncates
+// them. When the screen gets shorter, rows above the cursor move into
+// scrollback so the cursor stays on screen, as in tmux.
+func (t *Terminal) Resize(width, height int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	width, height = max(width, 1), max(height, 1)
+	if width == t.width && height == t.height {
+		return
+	}
+	if height < t.height && t.y >= height {
+		n := t.y - height + 1
+		if t.scr == &t.main {
+			for y := range n {
+				t.pushScrollback(y)
+			}
+		}
+		t.scr.buf.DeleteLine(0, n, nil)
+		t.shiftWrapped(0, t.height, -n)
+		t.y -= n
+	}
+	for _, s := range []*screen{&t.main, &t.alt} {
+		s.buf.Resize(width, height)
+		wrapped := make([]bool, height)
+		copy(wrapped, s.wrapped)
+		s.wrapped = wrapped
+	}
+	t.width, t.height = width, height
+	t.top, t.bottom = 0, height
+	t.x, t.y = min(t.x, width-1), min(t.y, height-1)
+	t.pendingWrap = false
+}
+
+// Capture renders up to history scrollback lines followed by every screen
+// row, one line per row with SGR styling, like tmux capture-pane -p -e.
+// With joinWrapped, rows that wrapped are joined into one line (-J).
+func (t *Terminal) Capture(history int, joinWrapped bool) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var rows []row
+	if t.scr == &t.main && history > 0 {
+		start := max(0, len(t.scrollback)-history)
+		rows = append(rows, t.scrollback[start:]...)
+	}
+	for y := range t.height {
+		rows = append(rows, t.renderRow(y))
+	}
+
+	var sb strings.Builder
+	for i, r := range rows {
+		sb.WriteString(r.text)
+		if joinWrapped && r.wrapped && i < len(rows)-1 {
+			continue
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// renderRow renders a screen row. Wrapped rows keep their trailing blanks so
+// words split at the wrap point stay apart when joined.
+func (t *Terminal) renderRow(y int) row {
+	w, text := cellbuf.RenderLine(t.scr.buf, y)
+	wrapped := t.scr.wrapped[y]
+	if wrapped && w < t.width {
+		text += strings.Repeat(" ", t.width-w)
+	}
+	return row{text: text, wrapped: wrapped}
+}
+
+func (t *Terminal) pushScrollback(y int) {
+	if t.maxScrollback <= 0 {
+		return
+	}
+	t.scrollback = append(t.scrollback, t.renderRow(y))
+	if len(t.scrollback) > t.maxScrollback {
+		t.scrollback = t.scrollback[len(t.scrollback)-t.maxScrollback:]
+	}
+}
+
+// blank returns the cell used to erase: the current background, or nil for
+// a default blank.
+func (t *Terminal) blank() *cellbuf.Cell {
+	if t.pen.Bg == nil {
+		return nil
+	}
+	return &cellbuf.Cell{Rune: ' ', Width: 1, Style: cellbuf.Style{Bg: t.pen.Bg}}
+}
+
+func (t *Terminal) print(r rune) {
+	w := runewidth.RuneWidth(r)
+	if w == 0 {
+		t.combine(r)
+		return
+	}
+	if w > t.width {
+		return
+	}
+	if t.pendingWrap || t.x+w > t.width {
+		if t.noAutowrap {
+			t.x = t.width - w
+		} else {
+			t.scr.wrapped[t.y] = true
+			t.x = 0
+			t.index()
+		}
+		t.pendingWrap = false
+	}
+	t.scr.buf.SetCell(t.x, t.y, &cellbuf.Cell{Rune: r, Width: w, Style: t.pen, Link: t.link})
+	if t.x+w >= t.width {
+		t.x = t.width - 1
+		t.pendingWrap = true
+	} else {
+		t.x += w
+	}
+}
+
+// combine appends a zero-width rune to the previously printed cell.
+func (t *Terminal) combine(r rune) {
+	x := t.x - 1
+	if t.pendingWrap {
+		x = t.x
+	}
+	prev := t.scr.buf.Cell(x, t.y)
+	if prev == nil || prev.Width == 0 {
+		return
+	}
+	c := prev.Clone()
+	c.Comb = append(c.Comb, r)
+	t.scr.buf.SetCell(x, t.y, c)
+}
+
+func (t *Terminal) execute(b byte) {
+	switch b {
+	case ansi.BS:
+		if t.x > 0 && !t.pendingWrap {
+			t.x--
+		}
+		t.pendingWrap = false
+	case ansi.HT:
+		t.x = min(t.width-1, (t.x/tabWidth+1)*tabWidth)
+		t.pendingWrap = false
+	case ansi.LF, ansi.VT, ansi.FF:
+		t.index()
+		t.pendingWrap = false
+	case ansi.CR:
+		t.x = 0
+		t.pendingWrap = false
+	}
+}
+
+// index moves the cursor down, scrolling at the bottom of the scroll region.
+func (t *Terminal) index() {
+	if t.y == t.bottom-1 {
+		t.scrollUp(1)
+	} else if t.y < t.height-1 {
+		t.y++
+	}
+}
+
+// reverseIndex moves the cursor up, scrolling at the top of the scroll region.
+func (t *Terminal) reverseIndex() {
+	if t.y == t.top {
+		t.scrollDown(1)
+	} else if t.y > 0 {
+		t.y--
+	}
+}
+
+// scrollUp scrolls the scroll region up by n rows. Rows leaving a full-screen
+// region of the main screen go to scrollback.
+func (t *Terminal) scrollUp(n int) {
+	n = min(n, t.bottom-t.top)
+	if t.scr == &t.main && t.top == 0 && t.bottom == t.height {
+		for y := range n {
+			t.pushScrollback(y)
+		}
+	}
+	t.scr.buf.DeleteLineRect(t.top, n, t.blank(), cellbuf.Rect(0, t.top, t.width, t.bottom-t.top))
+	t.shiftWrapped(t.top, t.bottom, -n)
+}
+
+// scrollDown scrolls the scroll region down by n rows.
+func (t *Terminal) scrollDown(n int) {
+	n = min(n, t.bottom-t.top)
+	t.scr.buf.InsertLineRect(t.top, n, t.blank(), cellbuf.Rect(0, t.top, t.width, t.bottom-t.top))
+	t.shiftWrapped(t.top, t.bottom, n)
+}
+
+// shiftWrapped moves the wrapped flags of rows [from, to) by n rows, clearing
+// the flags of rows that were vacated.
+func (t *Terminal) shiftWrapped(from, to, n int) {
+	w := t.scr.wrapped[from:to]
+	switch {
+	case n < 0:
+		copy(w, w[-n:])
+		clear(w[len(w)+n:])
+	case n > 0:
+		copy(w[n:], w)
+		clear(w[:n])
+	}
+}
+
+func (t *Terminal) moveTo(x, y int) {
+	t.x = min(max(x, 0), t.width-1)
+	t.y = min(max(y, 0), t.height-1)
+	t.pendingWrap = false
+}
+
+func param(params ansi.Params, i, def int) int {
+	v, _, _ := params.Param(i, def)
+	return v
+}
+
+// count returns a repeat or position parameter, where 0 means 1.
+func count(params ansi.Params, i int) int {
+	return max(param(params, i, 1), 1)
+}
+
+func (t *Terminal) csi(cmd ansi.Cmd, params ansi.Params) {
+	if cmd.Intermediate() != 0 {
+		return
+	}
+	switch cmd.Prefix() {
+	case '?':
+		switch cmd.Final() {
+		case 'h', 'l':
+			for i := range params {
+				t.setMode(param(params, i, 0), cmd.Final() == 'h')
+			}
+		}
+		return
+	case '>':
+		if cmd.Final() == 'c' {
+			t.respond("\x1b[>0;0;0c")
+		}
+		return
+	case 0:
+	default:
+		return
+	}
+
+	switch cmd.Final() {
+	case 'A':
+		limit := 0
+		if t.y >= t.top {
+			limit = t.top
+		}
+		t.moveTo(t.x, max(t.y-count(params, 0), limit))
+	case 'B', 'e':
+		limit := t.height - 1
+		if t.y < t.bottom {
+			limit = t.bottom - 1
+		}
+		t.moveTo(t.x, min(t.y+count(params, 0), limit))
+	case 'C', 'a':
+		t.moveTo(t.x+count(params, 0), t.y)
+	case 'D':
+		t.moveTo(t.x-count(params, 0), t.y)
+	case 'E':
+		t.moveTo(0, t.y+count(params, 0))
+	case 'F':
+		t.moveTo(0, t.y-count(params, 0))
+	case 'G', '`':
+		t.moveTo(count(params, 0)-1, t.y)
+	case 'H', 'f':
+		t.moveTo(count(params, 1)-1, count(params, 0)-1)
+	case 'd':
+		t.moveTo(t.x, count(params, 0)-1)
+	case 'J':
+		t.eraseDisplay(param(params, 0, 0))
+	case 'K':
+		t.eraseLine(param(params, 0, 0))
+	case 'L', 'M':
+		if t.y < t.top || t.y >= t.bottom {
+			return
+		}
+		n := count(params, 0)
+		rect := cellbuf.Rect(0, t.y, t.width, t.bottom-t.y)
+		if cmd.Final() == 'L' {
+			t.scr.buf.InsertLineRect(t.y, n, t.blank(), rect)
+			t.shiftWrapped(t.y, t.bottom, min(n, t.bottom-t.y))
+		} else {
+			t.scr.buf.DeleteLineRect(t.y, n, t.blank(), rect)
+			t.shiftWrapped(t.y, t.bottom, -min(n, t.bottom-t.y))
+		}
+		t.x = 0
+		t.pendingWrap = false
+	case '@':
+		t.scr.buf.InsertCell(t.x, t.y, count(params, 0), t.blank())
+		t.pendingWrap = false
+	case 'P':
+		t.scr.buf.DeleteCell(t.x, t.y, count(params, 0), t.blank())
+		t.pendingWrap = false
+	case 'X':
+		n := min(count(params, 0), t.width-t.x)
+		t.scr.buf.FillRect(t.blank(), cellbuf.Rect(t.x, t.y, n, 1))
+		t.pendingWrap = false
+	case 'S':
+		t.scrollUp(count(params, 0))
+	case 'T':
+		t.scrollDown(count(params, 0))
+	case 'r':
+		top, bottom := param(params, 0, 1), param(params, 1, t.height)
+		top, bottom = max(top, 1), min(bottom, t.height)
+		if bottom == 0 {
+			bottom = t.height
+		}
+		if top < bottom {
+			t.top, t.bottom = top-1, bottom
+			t.moveTo(0, 0)
+		}
+	case 'm':
+		cellbuf.ReadStyle(params, &t.pen)
+	case 's':
+		t.saveCursor()
+	case 'u':
+		t.restoreCursor()
+	case 'n':
+		switch param(params, 0, 0) {
+		case 5:
+			t.respond("\x1b[0n")
+		case 6:
+			t.respond(fmt.Sprintf("\x1b[%d;%dR", t.y+1, t.x+1))
+		}
+	case 'c':
+		t.respond("\x1b[?1;2c")
+	}
+}
+
+func (t *Terminal) eraseDisplay(mode int) {
+	blank := t.blank()
+	switch mode {
+	case 0:
+		t.eraseLine(0)
+		if t.y+1 < t.height {
+			t.scr.buf.FillRect(blank, cellbuf.Rect(0, t.y+1, t.width, t.height-t.y-1))
+			clear(t.scr.wrapped[t.y+1:])
+		}
+	case 1:
+		t.scr.buf.FillRect(blank, cellbuf.Rect(0, 0, t.width, t.y))
+		clear(t.scr.wrapped[:t.y])
+		t.eraseLine(1)
+	case 2:
+		t.scr.buf.FillRect(blank, cellbuf.Rect(0, 0, t.width, t.height))
+		clear(t.scr.wrapped)
+	case 3:
+		if t.scr == &t.main {
+			t.scrollback = nil
+		}
+	}
+}
+
+func (t *Terminal) eraseLine(mode int) {
+	var rect cellbuf.Rectangle
+	switch mode {
+	case 0:
+		rect = cellbuf.Rect(t.x, t.y, t.width-t.x, 1)
+		t.scr.wrapped[t.y] = false
+	case 1:
+		rect = cellbuf.Rect(0, t.y, t.x+1, 1)
+	case 2:
+		rect = cellbuf.Rect(0, t.y, t.width, 1)
+		t.scr.wrapped[t.y] = false
+	default:
+		return
+	}
+	t.scr.buf.FillRect(t.blank(), rect)
+	t.pendingWrap = false
+}
+
+// setMode sets or resets a DEC private mode.
+func (t *Terminal) setMode(mode int, set bool) {
+	switch mode {
+	case 1:
+		t.appCursorKeys = set
+	case 7:
+		t.noAutowrap = !set
+	case 25:
+		t.cursorHidden = !set
+	case 47, 1047:
+		t.useAltScreen(set)
+	case 1049:
+		if set {
+			t.saveCursor()
+			t.useAltScreen(true)
+		} else {
+			t.useAltScreen(false)
+			t.restoreCursor()
+		}
+	case 1000, 1002, 1003:
+		if set {
+			t.mouseModes[mode] = true
+		} else {
+			delete(t.mouseModes, mode)
+		}
+	case 2004:
+		t.bracketedPaste = set
+	}
+}
+
+// useAltScreen switches between the main and a freshly cleared alternate
+// screen.
+func (t *Terminal) useAltScreen(on bool) {
+	if on == (t.scr == &t.alt) {
+		return
+	}
+	if on {
+		t.alt.buf.Clear()
+		clear(t.alt.wrapped)
+		t.scr = &t.alt
+	} else {
+		t.scr = &t.main
+	}
+	t.pendingWrap = false
+}
+
+func (t *Terminal) saveCursor() {
+	t.saved = savedCursor{x: t.x, y: t.y, pen: t.pen, pendingWrap: t.pendingWrap}
+}
+
+func (t *Terminal) restoreCursor() {
+	t.moveTo(t.saved.x, t.saved.y)
+	t.pen = t.saved.pen
+	t.pendingWrap = t.saved.pendingWrap
+}
+
+func (t *Terminal) esc(cmd ansi.Cmd) {
+	if cmd.Intermediate() != 0 {
+		return // character set designations
+	}
+	switch cmd.Final() {
+	case '7':
+		t.saveCursor()
+	case '8':
+		t.restoreCursor()
+	case 'D':
+		t.index()
+		t.pendingWrap = false
+	case 'E':
+		t.x = 0
+		t.index()
+		t.pendingWrap = false
+	case 'M':
+		t.reverseIndex()
+		t.pendingWrap = false
+	case 'c':
+		t.reset()
+	}
+}
+
+func (t *Terminal) osc(cmd int, data []byte) {
+	if cmd == 8 {
+		cellbuf.ReadLink(data, &t.link)
+	}
+}
+
+// reset restores the initial state, keeping size and scrollback.
+func (t *Terminal) reset() {
+	t.useAltScreen(false)
+	t.main.buf.Clear()
+	clear(t.main.wrapped)
+	t.pen.Reset()
+	t.link.Reset()
+	t.moveTo(0, 0)
+	t.top, t.bottom = 0, t.height
+	t.saved = savedCursor{}
+	t.cursorHidden, t.noAutowrap, t.appCursorKeys, t.bracketedPaste = false, false, false, false
+	clear(t.mouseModes)
+}
+
+func (t *Terminal) respond(s string) {
+	if t.reply != nil {
+		_, _ = io.WriteString(t.reply, s)
+	}
+}
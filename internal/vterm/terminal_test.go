@@ -0,0 +1,131 @@
+package vterm
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func write(t *testing.T, term *Terminal, s string) {
+	t.Helper()
+	if _, err := term.Write([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// lines returns the captured rows without styling.
+func lines(term *Terminal, history int, join bool) []string {
+	out := strings.TrimSuffix(term.Capture(history, join), "\n")
+	rows := strings.Split(ansi.Strip(out), "\n")
+	for i := range rows {
+		rows[i] = strings.TrimRight(rows[i], " ")
+	}
+	return rows
+}
+
+func TestWrapAndJoin(t *testing.T) {
+	term := New(5, 3, 100, nil)
+	write(t, term, "hello world\r\n")
+
+	if got, want := lines(term, 0, false), []string{" worl", "d", ""}; !slices.Equal(got, want) {
+		t.Errorf("split capture = %q, want %q", got, want)
+	}
+	if got, want := lines(term, 10, true), []string{"hello world", ""}; !slices.Equal(got, want) {
+		t.Errorf("joined capture = %q, want %q", got, want)
+	}
+	if row, col, visible := term.Cursor(); row != 2 || col != 0 || !visible {
+		t.Errorf("cursor = %d,%d visible=%v, want 2,0 visible", row, col, visible)
+	}
+}
+
+func TestScrollback(t *testing.T) {
+	term := New(10, 2, 3, nil)
+	for _, s := range []string{"one", "two", "three", "four", "five"} {
+		write(t, term, s+"\r\n")
+	}
+	if got, want := lines(term, 100, false), []string{"two", "three", "four", "five", ""}; !slices.Equal(got, want) {
+		t.Errorf("capture = %q, want %q", got, want)
+	}
+	if got, want := lines(term, 1, false), []string{"four", "five", ""}; !slices.Equal(got, want) {
+		t.Errorf("capture with 1 history line = %q, want %q", got, want)
+	}
+}
+
+func TestCursorMovementAndErase(t *testing.T) {
+	term := New(10, 3, 0, nil)
+	write(t, term, "aaaaaaaaaa\r\nbbbbbbbbbb\r\ncccccccccc")
+	write(t, term, "\x1b[2;4H\x1b[K")  // erase rest of row 2 from column 4
+	write(t, term, "\x1b[1;1H\x1b[2P") // delete two cells on row 1
+	write(t, term, "\x1b[3;1H\x1b[2@X")
+	if got, want := lines(term, 0, false), []string{"aaaaaaaa", "bbb", "X cccccccc"}; !slices.Equal(got, want) {
+		t.Errorf("capture = %q, want %q", got, want)
+	}
+	if row, col, _ := term.Cursor(); row != 2 || col != 1 {
+		t.Errorf("cursor = %d,%d, want 2,1", row, col)
+	}
+}
+
+func TestScrollRegion(t *testing.T) {
+	term := New(10, 4, 10, nil)
+	write(t, term, "head\r\n1\r\n2\r\nfoot")
+	write(t, term, "\x1b[2;3r\x1b[3;1H\n3") // scroll rows 2-3 only
+	if got, want := lines(term, 10, false), []string{"head", "2", "3", "foot"}; !slices.Equal(got, want) {
+		t.Errorf("capture = %q, want %q", got, want)
+	}
+}
+
+func TestAltScreen(t *testing.T) {
+	term := New(10, 2, 10, nil)
+	write(t, term, "shell$ \x1b[?1049h\x1b[Hfullscreen")
+	if got, want := lines(term, 10, false), []string{"fullscreen", ""}; !slices.Equal(got, want) {
+		t.Errorf("alt capture = %q, want %q", got, want)
+	}
+	write(t, term, "\x1b[?1049l")
+	if got, want := lines(term, 10, false), []string{"shell$", ""}; !slices.Equal(got, want) {
+		t.Errorf("main capture = %q, want %q", got, want)
+	}
+	if row, col, _ := term.Cursor(); row != 0 || col != 7 {
+		t.Errorf("cursor = %d,%d, want restored 0,7", row, col)
+	}
+}
+
+func TestModesAndReplies(t *testing.T) {
+	var reply bytes.Buffer
+	term := New(10, 3, 0, &reply)
+	write(t, term, "\x1b[?2004h\x1b[?1002h\x1b[?1h\x1b[?25l\x1b[2;3H\x1b[6n")
+	if !term.BracketedPaste() || !term.MouseReporting() || !term.AppCursorKeys() {
+		t.Error("expected bracketed paste, mouse reporting and application cursor keys")
+	}
+	if _, _, visible := term.Cursor(); visible {
+		t.Error("expected hidden cursor")
+	}
+	if got := reply.String(); got != "\x1b[2;3R" {
+		t.Errorf("cursor report = %q", got)
+	}
+	write(t, term, "\x1b[?2004l\x1b[?1002l\x1b[?25h")
+	if term.BracketedPaste() || term.MouseReporting() {
+		t.Error("expected modes to be reset")
+	}
+}
+
+func TestStyleAndResize(t *testing.T) {
+	term := New(10, 3, 10, nil)
+	write(t, term, "\x1b[31mred\x1b[0m\r\nplain\r\nlast")
+	if out := term.Capture(0, false); !strings.Contains(out, "\x1b[31mred") {
+		t.Errorf("expected SGR in capture, got %q", out)
+	}
+
+	term.Resize(4, 2)
+	if got, want := lines(term, 10, false), []string{"red", "plai", "last"}; !slices.Equal(got, want) {
+		t.Errorf("capture after resize = %q, want %q", got, want)
+	}
+	if row, col, _ := term.Cursor(); row != 1 || col != 3 {
+		t.Errorf("cursor after resize = %d,%d, want 1,3", row, col)
+	}
+	if w, h := term.Size(); w != 4 || h != 2 {
+		t.Errorf("size = %dx%d", w, h)
+	}
+}